@@ -30,9 +30,19 @@ import (
 // @tag.name positions
 // @tag.description Operações relacionadas a posições geográficas
 
+// @tag.name sectors
+// @tag.description Operações relacionadas a setores geográficos
+
+// @tag.name admin
+// @tag.description Operações administrativas e de diagnóstico
+
 // @tag.name health
 // @tag.description Operações de health check
 
+// @securityDefinitions.apikey AdminToken
+// @in header
+// @name X-Admin-Token
+
 func main() {
 	// Criar aplicação
 	application, err := app.New()
@@ -24,9 +24,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/positions": {
-            "post": {
-                "description": "Salva uma nova posição geográfica para um usuário específico",
+        "/admin/cache/user/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Retorna as entradas de cache conhecidas do usuário (posição atual e histórico) com seus TTLs, indicando hits e misses",
                 "consumes": [
                     "application/json"
                 ],
@@ -34,36 +39,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "positions"
+                    "admin"
                 ],
-                "summary": "Salvar posição do usuário",
+                "summary": "Inspecionar cache do usuário",
                 "parameters": [
                     {
-                        "description": "Dados da posição",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.SavePositionRequest"
-                        }
+                        "type": "string",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Posição salva com sucesso",
+                    "200": {
+                        "description": "Entradas de cache do usuário",
                         "schema": {
-                            "$ref": "#/definitions/usecase.SaveUserPositionResponse"
+                            "$ref": "#/definitions/usecase.InspectUserCacheResponse"
                         }
                     },
                     "400": {
-                        "description": "Dados de posição inválidos",
+                        "description": "ID do usuário inválido",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Usuário não encontrado",
+                    "401": {
+                        "description": "Token de admin inválido ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Endpoints de admin desabilitados",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -79,71 +89,84 @@ const docTemplate = `{
                 }
             }
         },
-        "/positions/nearby": {
+        "/admin/sectors/recompute": {
             "get": {
-                "description": "Busca usuários próximos a uma coordenada específica dentro de um raio determinado",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
                 ],
+                "description": "Retorna o estado do último recálculo iniciado (idle, running, completed ou failed), com o resumo ao terminar",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "positions"
+                    "admin"
                 ],
-                "summary": "Buscar usuários próximos",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ID do usuário que está buscando",
-                        "name": "user_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "number",
-                        "description": "Latitude da posição de referência (-90 a 90)",
-                        "name": "latitude",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "number",
-                        "description": "Longitude da posição de referência (-180 a 180)",
-                        "name": "longitude",
-                        "in": "query",
-                        "required": true
+                "summary": "Estado do recálculo de setores",
+                "responses": {
+                    "200": {
+                        "description": "Estado do recálculo",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.RecomputeSectorsStatus"
+                        }
                     },
-                    {
-                        "type": "number",
-                        "description": "Raio de busca em metros (1 a 50000)",
-                        "name": "radius_meters",
-                        "in": "query",
-                        "required": true
+                    "401": {
+                        "description": "Token de admin inválido ou ausente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     },
+                    "403": {
+                        "description": "Endpoints de admin desabilitados",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "Número máximo de resultados (padrão: 50)",
-                        "name": "max_results",
-                        "in": "query"
+                        "AdminToken": []
                     }
                 ],
+                "description": "Inicia em background o recálculo de sector_x/sector_y de todas as posições (histórico e posição atual) com o esquema de setorização atual, em chunks. Use após mudar a origem ou o tamanho dos setores e acompanhe em GET /admin/sectors/recompute",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Recalcular setores armazenados",
                 "responses": {
-                    "200": {
-                        "description": "Lista de usuários próximos",
+                    "202": {
+                        "description": "Recálculo iniciado",
                         "schema": {
-                            "$ref": "#/definitions/usecase.FindNearbyUsersResponse"
+                            "$ref": "#/definitions/usecase.RecomputeSectorsStatus"
                         }
                     },
-                    "400": {
-                        "description": "Parâmetros de busca inválidos",
+                    "401": {
+                        "description": "Token de admin inválido ou ausente",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Erro interno do servidor",
+                    "403": {
+                        "description": "Endpoints de admin desabilitados",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Já existe um recálculo em execução",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -152,9 +175,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/positions/sector": {
+        "/feed/recent": {
             "get": {
-                "description": "Busca todos os usuários que estão no mesmo setor geográfico de uma coordenada específica",
+                "description": "Retorna as posições atuais atualizadas mais recentemente entre todos os usuários, da mais nova para a mais antiga",
                 "consumes": [
                     "application/json"
                 ],
@@ -162,41 +185,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "positions"
+                    "feed"
                 ],
-                "summary": "Buscar usuários no mesmo setor",
+                "summary": "Feed de atividade recente",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID do usuário que está buscando",
-                        "name": "user_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "number",
-                        "description": "Latitude da posição de referência (-90 a 90)",
-                        "name": "latitude",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "number",
-                        "description": "Longitude da posição de referência (-180 a 180)",
-                        "name": "longitude",
-                        "in": "query",
-                        "required": true
+                        "type": "integer",
+                        "description": "Número máximo de itens (padrão: 20, máximo: 100)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Lista de usuários no setor",
+                        "description": "Posições mais recentes",
                         "schema": {
-                            "$ref": "#/definitions/usecase.GetUsersInSectorResponse"
+                            "$ref": "#/definitions/usecase.GetRecentActivityResponse"
                         }
                     },
                     "400": {
-                        "description": "Parâmetros de busca inválidos",
+                        "description": "Parâmetros inválidos",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -212,9 +220,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/users": {
+        "/health/deep": {
+            "get": {
+                "description": "Verifica a conexão com PostgreSQL e Redis e retorna as estatísticas dos pools de conexão",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check profundo",
+                "responses": {
+                    "200": {
+                        "description": "Todas as dependências disponíveis",
+                        "schema": {
+                            "$ref": "#/definitions/handler.DeepHealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Alguma dependência indisponível",
+                        "schema": {
+                            "$ref": "#/definitions/handler.DeepHealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/positions": {
             "post": {
-                "description": "Cria um novo usuário no sistema para participar de um evento",
+                "description": "Salva uma nova posição geográfica para um usuário específico. O timestamp opcional (RFC3339) registra o horário real da leitura em envios offline; sem ele vale o horário do servidor. Timestamps no futuro ou mais antigos que POSITIONS_MAX_AGE_HOURS são recusados com 400",
                 "consumes": [
                     "application/json"
                 ],
@@ -222,36 +256,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "positions"
                 ],
-                "summary": "Criar um novo usuário",
+                "summary": "Salvar posição do usuário",
                 "parameters": [
                     {
-                        "description": "Dados do usuário",
+                        "description": "Dados da posição",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/usecase.CreateUserRequest"
+                            "$ref": "#/definitions/handler.SavePositionRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Usuário criado com sucesso",
+                        "description": "Posição salva com sucesso",
                         "schema": {
-                            "$ref": "#/definitions/usecase.CreateUserResponse"
+                            "$ref": "#/definitions/usecase.SaveUserPositionResponse"
                         }
                     },
                     "400": {
-                        "description": "Erro de validação",
+                        "description": "Dados de posição inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ValidationErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Usuário não encontrado",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "409": {
-                        "description": "Usuário já existe",
+                    "413": {
+                        "description": "Corpo da requisição acima de HTTP_MAX_BODY_BYTES",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "422": {
+                        "description": "Posição recusada pela validação do evento ou por velocidade implausível",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -267,9 +314,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/position": {
-            "get": {
-                "description": "Retorna a posição geográfica atual de um usuário específico",
+        "/positions/batch": {
+            "post": {
+                "description": "Salva até 500 posições em uma única transação. Cada item é validado individualmente: coordenadas inválidas não rejeitam o lote inteiro. Apenas a posição mais recente de cada usuário gera evento de mudança de posição",
                 "consumes": [
                     "application/json"
                 ],
@@ -277,27 +324,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "positions"
                 ],
-                "summary": "Obter posição atual do usuário",
+                "summary": "Salvar posições em lote",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID do usuário",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Posições do lote",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SavePositionsBatchRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Posição atual do usuário",
+                        "description": "Resultado por item do lote",
                         "schema": {
-                            "$ref": "#/definitions/usecase.GetCurrentPositionResponse"
+                            "$ref": "#/definitions/usecase.SaveUserPositionsBatchResponse"
                         }
                     },
                     "400": {
-                        "description": "ID do usuário inválido",
+                        "description": "Payload do lote inválido",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -310,6 +359,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "413": {
+                        "description": "Corpo da requisição acima de HTTP_MAX_BODY_BYTES",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Erro interno do servidor",
                         "schema": {
@@ -320,53 +376,73 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/positions/history": {
+        "/positions/bbox": {
             "get": {
-                "description": "Retorna o histórico de posições geográficas de um usuário com limite configurável",
+                "description": "Retorna as posições atuais dentro do retângulo (viewport do mapa), atualizadas mais recentemente primeiro. Exige min \u003c max nos dois eixos, não aceita retângulos que cruzam o antimeridiano e rejeita áreas acima de 10.000 km²",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/geo+json"
                 ],
                 "tags": [
-                    "users"
+                    "positions"
                 ],
-                "summary": "Obter histórico de posições do usuário",
+                "summary": "Buscar usuários em um retângulo",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID do usuário",
-                        "name": "id",
-                        "in": "path",
+                        "type": "number",
+                        "description": "Latitude mínima (-90 a 90)",
+                        "name": "min_lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude mínima (-180 a 180)",
+                        "name": "min_lng",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude máxima (maior que min_lat)",
+                        "name": "max_lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude máxima (maior que min_lng)",
+                        "name": "max_lng",
+                        "in": "query",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Número máximo de posições a retornar (padrão: 10, máximo: 100)",
+                        "description": "Número máximo de posições (1 a 500, padrão: 100)",
                         "name": "limit",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Formato da resposta: geojson retorna uma FeatureCollection (também via Accept: application/geo+json)",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Histórico de posições do usuário",
+                        "description": "Posições no retângulo",
                         "schema": {
-                            "$ref": "#/definitions/usecase.GetPositionHistoryResponse"
+                            "$ref": "#/definitions/usecase.GetPositionsInBoundingBoxResponse"
                         }
                     },
                     "400": {
-                        "description": "ID do usuário inválido",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "404": {
-                        "description": "Usuário não encontrado",
+                        "description": "Retângulo inválido ou grande demais",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/handler.ValidationErrorResponse"
                         }
                     },
                     "500": {
@@ -378,111 +454,2205 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handler.SavePositionRequest": {
-            "type": "object",
-            "required": [
+        },
+        "/positions/centroid": {
+            "post": {
+                "description": "Calcula o centro geográfico (média esférica) das posições atuais de até 100 usuários e a maior distância de um membro até ele. Com um único usuário o centro é a própria posição; IDs sem posição atual ficam fora do cálculo e aparecem em missing_user_ids",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "positions"
+                ],
+                "summary": "Ponto de encontro do grupo",
+                "parameters": [
+                    {
+                        "description": "IDs dos usuários do grupo",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetGroupCentroidRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Centro do grupo e distância de cada membro",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.GetGroupCentroidResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Lista vazia, acima de 100 IDs ou ID inválido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Nenhum usuário do grupo tem posição atual",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "413": {
+                        "description": "Corpo da requisição acima de HTTP_MAX_BODY_BYTES",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/positions/current/batch": {
+            "post": {
+                "description": "Retorna a posição atual de até 100 usuários em uma única consulta, na ordem dos IDs pedidos. IDs sem posição atual aparecem em missing_user_ids",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "positions"
+                ],
+                "summary": "Buscar posições atuais em lote",
+                "parameters": [
+                    {
+                        "description": "IDs dos usuários",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetCurrentPositionsBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Posições encontradas e IDs sem posição",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.GetCurrentPositionsBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Payload do lote inválido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "413": {
+                        "description": "Corpo da requisição acima de HTTP_MAX_BODY_BYTES",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/positions/nearby": {
+            "get": {
+                "description": "Busca usuários próximos a uma coordenada específica dentro de um raio determinado",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/geo+json"
+                ],
+                "tags": [
+                    "positions"
+                ],
+                "summary": "Buscar usuários próximos",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do usuário que está buscando",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude da posição de referência (-90 a 90)",
+                        "name": "latitude",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude da posição de referência (-180 a 180)",
+                        "name": "longitude",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Raio de busca em metros (1 a 50000)",
+                        "name": "radius_meters",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Número máximo de resultados (padrão: 20, via NEARBY_DEFAULT_MAX_RESULTS; valores acima do teto NEARBY_MAX_RESULTS, padrão 100, são reduzidos a ele; negativos retornam 400)",
+                        "name": "max_results",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Começa com raio pequeno e dobra até radius_meters ou até achar min_users",
+                        "name": "adaptive",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Usuários desejados na busca adaptativa (padrão: 5)",
+                        "name": "min_users",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ignora usuários vistos há mais segundos que isso (1 a 86400; padrão: sem filtro)",
+                        "name": "max_age_seconds",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Formato da resposta: geojson retorna uma FeatureCollection (também via Accept: application/geo+json)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Lista de usuários próximos",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.FindNearbyUsersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros de busca inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ValidationErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Usuário não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/positions/nearby/batch": {
+            "post": {
+                "description": "Executa a busca de proximidade para até 50 centros em paralelo e retorna os resultados na mesma ordem do pedido. Centros inválidos são reportados individualmente sem rejeitar o lote inteiro",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "positions"
+                ],
+                "summary": "Buscar usuários próximos em lote",
+                "parameters": [
+                    {
+                        "description": "Centros da busca",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.FindNearbyBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resultado por centro",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.FindNearbyUsersBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Payload do lote inválido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "413": {
+                        "description": "Corpo da requisição acima de HTTP_MAX_BODY_BYTES",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/positions/sector": {
+            "get": {
+                "description": "Busca todos os usuários que estão no mesmo setor geográfico de uma coordenada específica",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/geo+json"
+                ],
+                "tags": [
+                    "positions"
+                ],
+                "summary": "Buscar usuários no mesmo setor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do usuário que está buscando",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude da posição de referência (-90 a 90)",
+                        "name": "latitude",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude da posição de referência (-180 a 180)",
+                        "name": "longitude",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ignora usuários vistos há mais segundos que isso (1 a 86400; padrão: sem filtro)",
+                        "name": "max_age_seconds",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Formato da resposta: geojson retorna uma FeatureCollection (também via Accept: application/geo+json)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Lista de usuários no setor",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.GetUsersInSectorResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros de busca inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ValidationErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Usuário não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/positions/{id}": {
+            "get": {
+                "description": "Retorna coordenada, setor, horário de registro e dono de uma posição (ex.: position_id de um evento position.changed)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "positions"
+                ],
+                "summary": "Buscar posição por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da posição",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Posição encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.GetPositionByIDResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "ID inválido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Posição não encontrada",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sectors/around": {
+            "get": {
+                "description": "Retorna a contagem de usuários do setor central e de N anéis de setores vizinhos, organizada em grid (norte -\u003e sul, oeste -\u003e leste)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sectors"
+                ],
+                "summary": "Contagem de usuários nos setores ao redor",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Latitude do ponto central (-90 a 90)",
+                        "name": "latitude",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude do ponto central (-180 a 180)",
+                        "name": "longitude",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Número de anéis ao redor do setor central (padrão: 1, máximo: 10)",
+                        "name": "rings",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Grid de setores com contagem de usuários",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.GetSectorsAroundResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros de busca inválidos",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sectors/occupied": {
+            "get": {
+                "description": "Retorna todos os setores com pelo menos um usuário na posição atual, com o centro e a contagem de usuários, do mais ocupado para o menos ocupado. Com o retângulo (os quatro limites), retorna apenas os setores com algum usuário dentro dele, com a contagem do setor inteiro",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sectors"
+                ],
+                "summary": "Setores ocupados (heatmap)",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Latitude mínima do retângulo (-90 a 90)",
+                        "name": "min_latitude",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude máxima do retângulo (-90 a 90)",
+                        "name": "max_latitude",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude mínima do retângulo (-180 a 180)",
+                        "name": "min_longitude",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude máxima do retângulo (-180 a 180; não pode ser menor que min_longitude)",
+                        "name": "max_longitude",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Setores ocupados",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.GetOccupiedSectorsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Retângulo inválido ou incompleto",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sectors/{id}/analysis": {
+            "get": {
+                "description": "Retorna a contagem de usuários e a densidade do setor, e a contagem de usuários de cada um dos 8 setores vizinhos (bloco 3x3 consultado de uma vez)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sectors"
+                ],
+                "summary": "Análise do setor e vizinhos",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do setor no formato sector_x_y (ex: sector_-518_-2616)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Análise do setor e vizinhos",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.GetSectorAnalysisResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "ID do setor inválido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sectors/{id}/density": {
+            "get": {
+                "description": "Calcula a densidade atual do setor (usuários com posição atual por km²) e o tamanho de setor recomendado para essa densidade",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sectors"
+                ],
+                "summary": "Densidade do setor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do setor no formato sector_x_y (ex: sector_-518_-2616)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Densidade e tamanho recomendado",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.GetSectorDensityResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "ID do setor inválido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sectors/{id}/stats": {
+            "get": {
+                "description": "Retorna a quantidade de usuários (posição atual), a quantidade de posições registradas e a última atividade de um setor, para heatmaps",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sectors"
+                ],
+                "summary": "Estatísticas do setor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do setor no formato sector_x_y (ex: sector_-518_-2616)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Estatísticas do setor",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.GetSectorStatisticsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "ID do setor inválido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "Retorna uma página de usuários, com total e indicação de mais páginas. Filtros por trecho do nome e domínio do email são combinados, e o total considera os filtros",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Listar usuários",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Número máximo de usuários a retornar (padrão: 20, máximo: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Número de usuários a pular, para paginação (padrão: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trecho do nome, sem diferenciar maiúsculas",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Domínio do email (ex: example.com)",
+                        "name": "email_domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ordenação: created_at_desc (padrão), created_at_asc ou name",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Página de usuários",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.ListUsersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ordenação ou domínio inválido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Cria um novo usuário no sistema para participar de um evento",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Criar um novo usuário",
+                "parameters": [
+                    {
+                        "description": "Dados do usuário",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/usecase.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Usuário criado com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.CreateUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Erro de validação",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ValidationErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Usuário já existe",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "413": {
+                        "description": "Corpo da requisição acima de HTTP_MAX_BODY_BYTES",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/by-email": {
+            "get": {
+                "description": "Retorna o usuário cadastrado com o email informado, para clientes que não conhecem o ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Buscar usuário por email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email do usuário",
+                        "name": "email",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Usuário encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.GetUserByEmailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Email inválido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Usuário não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "delete": {
+                "description": "Remove o usuário junto com sua posição atual e histórico, e publica o evento user.deleted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Remover usuário",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Usuário removido com sucesso",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.DeleteUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "ID do usuário inválido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Usuário não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/movement-stats": {
+            "get": {
+                "description": "Retorna distância percorrida, velocidade média, trocas de setor e setor mais visitado no intervalo",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Obter estatísticas de deslocamento do usuário",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Início do intervalo em RFC3339 (padrão: to - 24h)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fim do intervalo em RFC3339 (padrão: agora)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Estatísticas de deslocamento",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.GetUserMovementStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Intervalo inválido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Usuário não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/position": {
+            "get": {
+                "description": "Retorna a posição geográfica atual de um usuário específico",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Obter posição atual do usuário",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Posição atual do usuário",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.GetCurrentPositionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "ID do usuário inválido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Usuário não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/positions/export": {
+            "get": {
+                "description": "Faz o download de todo o histórico do usuário (sem o limite de 100 da paginação), da posição mais antiga para a mais recente. O histórico é lido do banco em chunks e escrito em streaming. O CSV tem as colunas position_id, latitude, longitude, sector_id, recorded_at",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Exportar histórico de posições do usuário",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Formato do arquivo: json (padrão) ou csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Histórico completo (anexo)",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/usecase.PositionExportItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "ID do usuário ou formato inválido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Usuário não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/positions/history": {
+            "get": {
+                "description": "Retorna uma página do histórico de posições geográficas de um usuário, com total e indicação de mais páginas",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Obter histórico de posições do usuário",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Número máximo de posições a retornar (padrão: 10, máximo: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Número de posições a pular, para paginação (padrão: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Histórico de posições do usuário",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.GetPositionHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "ID do usuário inválido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Usuário não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/positions/stream": {
+            "get": {
+                "description": "Retorna as posições gravadas após a posição since, da mais antiga para a mais recente. Use next_cursor como since da próxima chamada; com wait_seconds a resposta aguarda posições novas antes de retornar vazia (sem since, começa do início do histórico)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Acompanhar o histórico de posições do usuário",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "position_id da última posição já recebida",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Número máximo de posições a retornar (padrão: 50, máximo: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Segundos para aguardar posições novas quando não há nenhuma (padrão: 0, máximo: 30; limitado ao timeout da requisição)",
+                        "name": "wait_seconds",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Posições após o cursor e o próximo cursor",
+                        "schema": {
+                            "$ref": "#/definitions/usecase.StreamPositionHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "ID do usuário ou cursor inválido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Usuário ou posição do cursor não encontrados",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/ws/positions": {
+            "get": {
+                "description": "Abre um WebSocket que recebe eventos position.changed. Com sector, recebe apenas as mudanças que entram ou saem do setor",
+                "tags": [
+                    "realtime"
+                ],
+                "summary": "Atualizações de posição em tempo real",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do setor no formato sector_x_y (ex: sector_-518_-2616)",
+                        "name": "sector",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Conexão WebSocket estabelecida"
+                    },
+                    "400": {
+                        "description": "Setor inválido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.BatchPositionPayload": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.DeepHealthResponse": {
+            "type": "object",
+            "properties": {
+                "dependencies": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/handler.DependencyHealth"
+                    }
+                },
+                "service": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "\"healthy\" ou \"unhealthy\"",
+                    "type": "string"
+                }
+            }
+        },
+        "handler.DependencyHealth": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "pool": {},
+                "status": {
+                    "description": "\"up\" ou \"down\"",
+                    "type": "string"
+                }
+            }
+        },
+        "handler.FindNearbyBatchRequest": {
+            "type": "object",
+            "required": [
+                "centers"
+            ],
+            "properties": {
+                "centers": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/handler.NearbyBatchCenterPayload"
+                    }
+                },
+                "max_results": {
+                    "description": "Por centro; padrão 20, reduzido ao teto NEARBY_MAX_RESULTS (padrão 100)",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "handler.GetCurrentPositionsBatchRequest": {
+            "type": "object",
+            "required": [
+                "user_ids"
+            ],
+            "properties": {
+                "user_ids": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.GetGroupCentroidRequest": {
+            "type": "object",
+            "properties": {
+                "user_ids": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.NearbyBatchCenterPayload": {
+            "type": "object",
+            "properties": {
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "radius_meters": {
+                    "type": "number"
+                }
+            }
+        },
+        "handler.SavePositionRequest": {
+            "type": "object",
+            "required": [
                 "latitude",
                 "longitude",
                 "user_id"
             ],
             "properties": {
                 "latitude": {
-                    "type": "number",
-                    "maximum": 90,
-                    "minimum": -90
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180
+                },
+                "timestamp": {
+                    "description": "Timestamp é o momento da leitura no cliente (RFC3339), para envios offline; ausente = horário do servidor",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.SavePositionsBatchRequest": {
+            "type": "object",
+            "required": [
+                "positions"
+            ],
+            "properties": {
+                "positions": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/handler.BatchPositionPayload"
+                    }
+                }
+            }
+        },
+        "handler.ValidationError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "REQUIRED"
+                },
+                "field": {
+                    "description": "Nome do campo no JSON ou na query",
+                    "type": "string",
+                    "example": "latitude"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "latitude is required"
+                }
+            }
+        },
+        "handler.ValidationErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Invalid request payload"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ValidationError"
+                    }
+                }
+            }
+        },
+        "usecase.BatchPositionResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "flag_reason": {
+                    "type": "string"
+                },
+                "flagged": {
+                    "type": "boolean"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "position_id": {
+                    "type": "string"
+                },
+                "sector_id": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "usecase.BoundingBoxPositionResponse": {
+            "type": "object",
+            "properties": {
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "position_id": {
+                    "type": "string"
+                },
+                "recorded_at": {
+                    "type": "string"
+                },
+                "sector_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "usecase.CacheEntryResponse": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "hit ou miss",
+                    "type": "string"
+                },
+                "ttl_seconds": {
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "position ou history",
+                    "type": "string"
+                },
+                "value": {
+                    "type": "object"
+                }
+            }
+        },
+        "usecase.CreateUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "event_id",
+                "id",
+                "name"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "usecase.CreateUserResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "usecase.CurrentPositionItem": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "description": "Ex: \"5m30s\"",
+                    "type": "string"
+                },
+                "age_seconds": {
+                    "type": "integer"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "position_id": {
+                    "type": "string"
+                },
+                "recorded_at": {
+                    "type": "string"
+                },
+                "sector_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "usecase.DeleteUserResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "usecase.FindNearbyUsersBatchResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/usecase.NearbyBatchResult"
+                    }
+                }
+            }
+        },
+        "usecase.FindNearbyUsersResponse": {
+            "type": "object",
+            "properties": {
+                "max_results": {
+                    "description": "Limite efetivo aplicado (padrão ou teto do servidor)",
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "nearby_users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/usecase.NearbyUserResponse"
+                    }
+                },
+                "radius_used_meters": {
+                    "type": "number"
+                },
+                "search_center": {
+                    "$ref": "#/definitions/usecase.NearbyUserResponse"
+                },
+                "total_found": {
+                    "type": "integer"
+                }
+            }
+        },
+        "usecase.GetCurrentPositionResponse": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "position_id": {
+                    "type": "string"
+                },
+                "sector_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "user_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "usecase.GetCurrentPositionsBatchResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "missing_user_ids": {
+                    "description": "IDs sem posição atual",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "positions": {
+                    "description": "Na ordem dos IDs pedidos",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/usecase.CurrentPositionItem"
+                    }
+                }
+            }
+        },
+        "usecase.GetGroupCentroidResponse": {
+            "type": "object",
+            "properties": {
+                "farthest_user_id": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "description": "Centro geográfico das posições atuais",
+                    "type": "number"
+                },
+                "longitude": {
+                    "description": "Centro geográfico das posições atuais",
+                    "type": "number"
+                },
+                "max_distance_meters": {
+                    "description": "Maior distância de um membro até o centro",
+                    "type": "number"
+                },
+                "members": {
+                    "description": "Na ordem dos IDs pedidos",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/usecase.GroupMemberDistance"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "missing_user_ids": {
+                    "description": "IDs sem posição atual (fora do cálculo)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "usecase.GetOccupiedSectorsResponse": {
+            "type": "object",
+            "properties": {
+                "bounding_box": {
+                    "description": "Retângulo aplicado, se houver",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/usecase.SectorBounds"
+                        }
+                    ]
+                },
+                "message": {
+                    "type": "string"
+                },
+                "sectors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/usecase.OccupiedSectorResponse"
+                    }
+                },
+                "total_sectors": {
+                    "type": "integer"
+                },
+                "total_users": {
+                    "type": "integer"
+                }
+            }
+        },
+        "usecase.GetPositionByIDResponse": {
+            "type": "object",
+            "properties": {
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "position_id": {
+                    "type": "string"
+                },
+                "recorded_at": {
+                    "type": "string"
+                },
+                "sector_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "user_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "usecase.GetPositionHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/usecase.PositionHistoryItem"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Total de posições no histórico (todas as páginas)",
+                    "type": "integer"
+                },
+                "total_distance_meters": {
+                    "description": "TotalDistanceM é o caminho percorrido entre as posições desta página, em ordem cronológica",
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "user_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "usecase.GetPositionsInBoundingBoxResponse": {
+            "type": "object",
+            "properties": {
+                "area_km2": {
+                    "type": "number"
+                },
+                "bounding_box": {
+                    "$ref": "#/definitions/usecase.SectorBounds"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "positions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/usecase.BoundingBoxPositionResponse"
+                    }
+                },
+                "total_found": {
+                    "type": "integer"
+                }
+            }
+        },
+        "usecase.GetRecentActivityResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/usecase.RecentActivityResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "total_found": {
+                    "type": "integer"
+                }
+            }
+        },
+        "usecase.GetSectorAnalysisResponse": {
+            "type": "object",
+            "properties": {
+                "density_per_km2": {
+                    "type": "number"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "neighbors": {
+                    "description": "Os 8 setores ao redor, com a contagem de cada um",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/usecase.SectorCountResponse"
+                    }
+                },
+                "neighbors_users": {
+                    "type": "integer"
+                },
+                "sector_id": {
+                    "type": "string"
+                },
+                "user_count": {
+                    "type": "integer"
+                },
+                "x": {
+                    "type": "integer"
+                },
+                "y": {
+                    "type": "integer"
+                }
+            }
+        },
+        "usecase.GetSectorDensityResponse": {
+            "type": "object",
+            "properties": {
+                "area_km2": {
+                    "type": "number"
+                },
+                "current_sector_size_meters": {
+                    "type": "number"
+                },
+                "density_per_km2": {
+                    "description": "Usuários com posição atual no setor por km²",
+                    "type": "number"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "recommended_sector_size_meters": {
+                    "description": "Heurística de CalculateOptimalSectorSize",
+                    "type": "number"
+                },
+                "sector_id": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "integer"
+                },
+                "y": {
+                    "type": "integer"
+                }
+            }
+        },
+        "usecase.GetSectorStatisticsResponse": {
+            "type": "object",
+            "properties": {
+                "last_activity": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "position_count": {
+                    "type": "integer"
+                },
+                "sector_id": {
+                    "type": "string"
+                },
+                "user_count": {
+                    "type": "integer"
+                },
+                "x": {
+                    "type": "integer"
+                },
+                "y": {
+                    "type": "integer"
+                }
+            }
+        },
+        "usecase.GetSectorsAroundResponse": {
+            "type": "object",
+            "properties": {
+                "center_sector_id": {
+                    "type": "string"
+                },
+                "grid": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/usecase.SectorCountResponse"
+                        }
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "rings": {
+                    "type": "integer"
+                },
+                "total_sectors": {
+                    "type": "integer"
+                },
+                "total_users": {
+                    "type": "integer"
+                }
+            }
+        },
+        "usecase.GetUserByEmailResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "usecase.GetUserMovementStatsResponse": {
+            "type": "object",
+            "properties": {
+                "average_speed_mps": {
+                    "type": "number"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "most_visited_count": {
+                    "type": "integer"
+                },
+                "most_visited_sector": {
+                    "type": "string"
+                },
+                "position_count": {
+                    "type": "integer"
+                },
+                "sector_changes": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total_distance_meters": {
+                    "type": "number"
+                },
+                "truncated": {
+                    "description": "true se o intervalo tinha mais que MaxMovementStatsPositions",
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "usecase.GetUsersInSectorResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "requested_by": {
+                    "$ref": "#/definitions/usecase.SectorUserResponse"
+                },
+                "sector_bounds": {
+                    "$ref": "#/definitions/usecase.SectorBounds"
+                },
+                "sector_id": {
+                    "type": "string"
+                },
+                "total_found": {
+                    "type": "integer"
+                },
+                "users_in_sector": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/usecase.SectorUserResponse"
+                    }
+                }
+            }
+        },
+        "usecase.GroupMemberDistance": {
+            "type": "object",
+            "properties": {
+                "distance_meters": {
+                    "type": "number"
+                },
+                "latitude": {
+                    "type": "number"
                 },
                 "longitude": {
-                    "type": "number",
-                    "maximum": 180,
-                    "minimum": -180
+                    "type": "number"
                 },
                 "user_id": {
                     "type": "string"
                 }
             }
         },
-        "usecase.CreateUserRequest": {
+        "usecase.InspectUserCacheResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "event_id",
-                "id",
-                "name"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/usecase.CacheEntryResponse"
+                    }
                 },
-                "event_id": {
-                    "type": "string"
+                "hits": {
+                    "type": "integer"
                 },
-                "id": {
+                "message": {
                     "type": "string"
                 },
-                "name": {
+                "misses": {
+                    "type": "integer"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "usecase.CreateUserResponse": {
+        "usecase.ListUsersResponse": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
+                "has_more": {
+                    "type": "boolean"
                 },
-                "event_id": {
-                    "type": "string"
+                "limit": {
+                    "type": "integer"
                 },
                 "message": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string"
+                "offset": {
+                    "type": "integer"
                 },
-                "user_id": {
+                "sort": {
+                    "description": "Ordenação aplicada",
                     "type": "string"
+                },
+                "total": {
+                    "description": "Total de usuários (todas as páginas)",
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/usecase.UserSummary"
+                    }
                 }
             }
         },
-        "usecase.FindNearbyUsersResponse": {
+        "usecase.NearbyBatchResult": {
             "type": "object",
             "properties": {
-                "message": {
+                "error": {
                     "type": "string"
                 },
+                "index": {
+                    "type": "integer"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
                 "nearby_users": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/usecase.NearbyUserResponse"
                     }
                 },
-                "search_center": {
-                    "$ref": "#/definitions/usecase.NearbyUserResponse"
+                "radius_meters": {
+                    "type": "number"
                 },
                 "total_found": {
                     "type": "integer"
                 }
             }
         },
-        "usecase.GetCurrentPositionResponse": {
+        "usecase.NearbyUserResponse": {
             "type": "object",
             "properties": {
                 "age": {
+                    "description": "Ex: \"5m30s\"",
                     "type": "string"
                 },
+                "age_seconds": {
+                    "description": "Para filtros como \"vistos nos últimos 60s\"",
+                    "type": "integer"
+                },
+                "distance_meters": {
+                    "type": "number"
+                },
                 "latitude": {
                     "type": "number"
                 },
                 "longitude": {
                     "type": "number"
                 },
-                "message": {
+                "position_id": {
                     "type": "string"
                 },
-                "position_id": {
+                "recorded_at": {
                     "type": "string"
                 },
                 "sector_id": {
@@ -496,65 +2666,79 @@ const docTemplate = `{
                 }
             }
         },
-        "usecase.GetPositionHistoryResponse": {
+        "usecase.OccupiedSectorResponse": {
             "type": "object",
             "properties": {
-                "history": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/usecase.PositionHistoryItem"
-                    }
+                "center_latitude": {
+                    "type": "number"
                 },
-                "message": {
+                "center_longitude": {
+                    "type": "number"
+                },
+                "sector_id": {
                     "type": "string"
                 },
-                "total": {
+                "user_count": {
                     "type": "integer"
                 },
-                "user_id": {
-                    "type": "string"
+                "x": {
+                    "type": "integer"
                 },
-                "user_name": {
-                    "type": "string"
+                "y": {
+                    "type": "integer"
                 }
             }
         },
-        "usecase.GetUsersInSectorResponse": {
+        "usecase.PositionExportItem": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string"
-                },
-                "requested_by": {
-                    "$ref": "#/definitions/usecase.SectorUserResponse"
+                "latitude": {
+                    "type": "number"
                 },
-                "sector_bounds": {
-                    "$ref": "#/definitions/usecase.SectorBounds"
+                "longitude": {
+                    "type": "number"
                 },
-                "sector_id": {
+                "position_id": {
                     "type": "string"
                 },
-                "total_found": {
-                    "type": "integer"
+                "recorded_at": {
+                    "type": "string"
                 },
-                "users_in_sector": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/usecase.SectorUserResponse"
-                    }
+                "sector_id": {
+                    "type": "string"
                 }
             }
         },
-        "usecase.NearbyUserResponse": {
+        "usecase.PositionHistoryItem": {
             "type": "object",
             "properties": {
                 "age": {
-                    "description": "Ex: \"5m30s\"",
                     "type": "string"
                 },
-                "distance_meters": {
+                "bearing": {
+                    "description": "Bearing é o rumo em graus (0 = norte) vindo da posição anterior no tempo\nAusente na posição mais antiga da página e quando o usuário não se moveu",
+                    "type": "number"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
                     "type": "number"
                 },
+                "position_id": {
+                    "type": "string"
+                },
+                "recorded_at": {
+                    "type": "string"
+                },
+                "sector_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "usecase.RecentActivityResponse": {
+            "type": "object",
+            "properties": {
                 "latitude": {
                     "type": "number"
                 },
@@ -567,6 +2751,9 @@ const docTemplate = `{
                 "sector_id": {
                     "type": "string"
                 },
+                "updated_at": {
+                    "type": "string"
+                },
                 "user_id": {
                     "type": "string"
                 },
@@ -575,25 +2762,39 @@ const docTemplate = `{
                 }
             }
         },
-        "usecase.PositionHistoryItem": {
+        "usecase.RecomputeSectorsResponse": {
             "type": "object",
             "properties": {
-                "age": {
+                "chunks": {
+                    "type": "integer"
+                },
+                "message": {
                     "type": "string"
                 },
-                "latitude": {
-                    "type": "number"
+                "scanned": {
+                    "type": "integer"
                 },
-                "longitude": {
-                    "type": "number"
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "usecase.RecomputeSectorsStatus": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
                 },
-                "position_id": {
+                "finished_at": {
                     "type": "string"
                 },
-                "recorded_at": {
+                "result": {
+                    "$ref": "#/definitions/usecase.RecomputeSectorsResponse"
+                },
+                "started_at": {
                     "type": "string"
                 },
-                "sector_id": {
+                "status": {
                     "type": "string"
                 }
             }
@@ -601,6 +2802,12 @@ const docTemplate = `{
         "usecase.SaveUserPositionResponse": {
             "type": "object",
             "properties": {
+                "flag_reason": {
+                    "type": "string"
+                },
+                "flagged": {
+                    "type": "boolean"
+                },
                 "message": {
                     "type": "string"
                 },
@@ -612,6 +2819,26 @@ const docTemplate = `{
                 }
             }
         },
+        "usecase.SaveUserPositionsBatchResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/usecase.BatchPositionResult"
+                    }
+                },
+                "saved": {
+                    "type": "integer"
+                }
+            }
+        },
         "usecase.SectorBounds": {
             "type": "object",
             "properties": {
@@ -629,6 +2856,23 @@ const docTemplate = `{
                 }
             }
         },
+        "usecase.SectorCountResponse": {
+            "type": "object",
+            "properties": {
+                "sector_id": {
+                    "type": "string"
+                },
+                "user_count": {
+                    "type": "integer"
+                },
+                "x": {
+                    "type": "integer"
+                },
+                "y": {
+                    "type": "integer"
+                }
+            }
+        },
         "usecase.SectorUserResponse": {
             "type": "object",
             "properties": {
@@ -636,6 +2880,14 @@ const docTemplate = `{
                     "description": "Ex: \"5m30s\"",
                     "type": "string"
                 },
+                "age_seconds": {
+                    "description": "Para filtros como \"vistos nos últimos 60s\"",
+                    "type": "integer"
+                },
+                "distance_meters": {
+                    "description": "Distância até o solicitante",
+                    "type": "number"
+                },
                 "latitude": {
                     "type": "number"
                 },
@@ -645,6 +2897,9 @@ const docTemplate = `{
                 "position_id": {
                     "type": "string"
                 },
+                "recorded_at": {
+                    "type": "string"
+                },
                 "user_id": {
                     "type": "string"
                 },
@@ -652,6 +2907,58 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "usecase.StreamPositionHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "has_more": {
+                    "description": "Há mais posições já gravadas após next_cursor",
+                    "type": "boolean"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "next_cursor": {
+                    "description": "since da próxima chamada (o próprio since quando não há nada novo)",
+                    "type": "string"
+                },
+                "positions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/usecase.PositionExportItem"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "usecase.UserSummary": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "AdminToken": {
+            "type": "apiKey",
+            "name": "X-Admin-Token",
+            "in": "header"
         }
     },
     "tags": [
@@ -663,6 +2970,14 @@ const docTemplate = `{
             "description": "Operações relacionadas a posições geográficas",
             "name": "positions"
         },
+        {
+            "description": "Operações relacionadas a setores geográficos",
+            "name": "sectors"
+        },
+        {
+            "description": "Operações administrativas e de diagnóstico",
+            "name": "admin"
+        },
         {
             "description": "Operações de health check",
             "name": "health"
@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -10,32 +11,64 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/internal/infrastructure/cache"
+	"github.com/vitao/geolocation-tracker/internal/infrastructure/database"
 	"github.com/vitao/geolocation-tracker/internal/infrastructure/events"
+	"github.com/vitao/geolocation-tracker/internal/infrastructure/realtime"
+	"github.com/vitao/geolocation-tracker/internal/interfaces/http/handler"
+	"github.com/vitao/geolocation-tracker/internal/interfaces/http/middleware"
 	"github.com/vitao/geolocation-tracker/internal/interfaces/http/routes"
 	"github.com/vitao/geolocation-tracker/internal/wire"
 	"github.com/vitao/geolocation-tracker/pkg/config"
 	"github.com/vitao/geolocation-tracker/pkg/logger"
+	"github.com/vitao/geolocation-tracker/pkg/worker"
 )
 
+// workerShutdownTimeout é quanto o shutdown aguarda os workers em background (consumers, limpeza)
+const workerShutdownTimeout = 10 * time.Second
+
+// migrationTimeout limita as migrações do boot (índices em tabelas grandes podem demorar)
+const migrationTimeout = 2 * time.Minute
+
 type Application struct {
 	config       *config.Config
 	logger       logger.Logger
 	server       *http.Server
 	container    *wire.Container
+	redis        *cache.Redis
 	eventService *events.EventService
+	hub          *realtime.Hub // Clientes WebSocket de tempo real
+
+	// workers registra as rotinas em background e as encerra no shutdown
+	workers *worker.Manager
 }
 
 // New cria uma nova instância da aplicação
 func New() (*Application, error) {
-	// Configurar logger estruturado
-	log := logger.NewLogger()
-
 	// Carregar configurações
 	cfg, err := config.Load()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
+	// Configurar logger estruturado (nível e formato padrão dependem do ambiente)
+	log, err := logger.New(logger.Options{Level: cfg.Log.Level, Format: cfg.Log.Format})
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure logger: %w", err)
+	}
+
+	// Origem da grade de setores (antes de qualquer conversão coordenada -> setor)
+	if err := valueobject.SetSectorOrigin(cfg.Sectors.OriginLatitude, cfg.Sectors.OriginLongitude); err != nil {
+		return nil, fmt.Errorf("failed to configure sectors: %w", err)
+	}
+
+	// Idade máxima de novas posições (POSITIONS_MAX_AGE_HOURS)
+	if err := entity.SetMaxPositionAge(time.Duration(cfg.Positions.MaxAgeHours) * time.Hour); err != nil {
+		return nil, fmt.Errorf("failed to configure positions: %w", err)
+	}
+
 	// Configurar Gin mode baseado no environment
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -47,25 +80,51 @@ func New() (*Application, error) {
 		return nil, fmt.Errorf("failed to initialize container: %w", err)
 	}
 
+	// Garantir o schema (extensão PostGIS, tabelas e índices) antes de atender requisições
+	if err := runMigrations(container.DB); err != nil {
+		return nil, err
+	}
+
 	// Inicializar Redis (extraído do container)
 	redis, err := wire.InitializeRedis()
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize Redis: %w", err)
 	}
 
-	// Inicializar event service
-	eventService := events.NewEventService(redis, log)
+	// Inicializar event service (o consumer de tempo real publica no hub WebSocket)
+	hub := realtime.NewHub(log)
+	workers := worker.NewManager(context.Background(), log)
+	eventService := events.NewEventService(redis, hub, container.Metrics, workers, cfg, log)
 
 	app := &Application{
 		config:       cfg,
 		logger:       log,
 		container:    container,
+		redis:        redis,
 		eventService: eventService,
+		hub:          hub,
+		workers:      workers,
 	}
 
 	return app, nil
 }
 
+// runMigrations aplica as migrações embutidas que ainda não constam em schema_migrations
+func runMigrations(db *database.DB) error {
+	migrations, err := database.Migrations()
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), migrationTimeout)
+	defer cancel()
+
+	if err := db.RunMigrations(ctx, migrations); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+	return nil
+}
+
 // Start inicia a aplicação
 func (a *Application) Start() error {
 	a.logger.Info("Starting Geolocation Tracker Application...")
@@ -75,10 +134,16 @@ func (a *Application) Start() error {
 		return fmt.Errorf("failed to start event service: %w", err)
 	}
 
-	// 2. Configurar rotas
+	// 2. Iniciar limpeza periódica do histórico
+	a.startRetentionSweeper()
+
+	// 3. Iniciar o relay da outbox de eventos
+	a.workers.Go("outbox-relay", a.container.OutboxRelay.Run)
+
+	// 4. Configurar rotas
 	router := a.setupRoutes()
 
-	// 3. Configurar servidor HTTP
+	// 5. Configurar servidor HTTP
 	a.server = &http.Server{
 		Addr:         ":" + a.config.Port,
 		Handler:      router,
@@ -110,21 +175,104 @@ func (a *Application) Start() error {
 	return a.gracefulShutdown()
 }
 
+// startRetentionSweeper remove periodicamente as posições fora da janela de retenção
+// A primeira limpeza acontece após o primeiro intervalo, não no boot
+func (a *Application) startRetentionSweeper() {
+	purge := a.container.PurgeOldPositions
+	interval := time.Duration(a.config.Positions.RetentionSweepIntervalMinutes) * time.Minute
+	if !purge.Enabled() || interval <= 0 {
+		a.logger.Info("Position retention sweeper disabled")
+		return
+	}
+
+	a.logger.Info("Starting position retention sweeper",
+		"retention_hours", a.config.Positions.RetentionHours,
+		"interval", interval.String(),
+	)
+
+	a.workers.Go("retention-sweeper", func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// Erro já registrado pelo use case; tenta de novo no próximo intervalo
+				_, _ = purge.Execute(ctx)
+			}
+		}
+	})
+}
+
 // setupRoutes configura todas as rotas da aplicação
 func (a *Application) setupRoutes() *gin.Engine {
+	// Mesma política de origens para o CORS da API e para o handshake do WebSocket
+	corsPolicy := middleware.NewCORSPolicy(a.config.HTTP.CORSAllowedOrigins)
+
 	router := routes.SetupRoutes(
 		a.container.CreateUser,
+		a.container.ListUsers,
+		a.container.GetUserByEmail,
+		a.container.DeleteUser,
 		a.container.SaveUserPosition,
+		a.container.SavePositionsBatch,
 		a.container.FindNearbyUsers,
+		a.container.FindNearbyBatch,
 		a.container.GetUsersInSector,
 		a.container.GetCurrentPosition,
+		a.container.GetCurrentBatch,
+		a.container.GetInBoundingBox,
+		a.container.GetGroupCentroid,
 		a.container.GetPositionHistory,
+		a.container.ExportHistory,
+		a.container.StreamHistory,
+		a.container.GetMovementStats,
+		a.container.GetSectorsAround,
+		a.container.GetSectorStats,
+		a.container.GetSectorDensity,
+		a.container.GetSectorAnalysis,
+		a.container.GetOccupiedSectors,
+		a.container.InspectUserCache,
+		a.container.RecomputeSectors,
+		a.container.GetRecentActivity,
+		a.container.GetPositionByID,
+		a.workers,
+		a.config.Admin.Token,
+		a.redis,
+		a.config.RateLimit,
+		time.Duration(a.config.HTTP.RequestTimeoutSeconds)*time.Second,
+		a.config.HTTP.MaxBodyBytes,
+		a.config.HTTP.StrictJSON,
+		corsPolicy,
+		a.container.Metrics,
 		a.logger,
 	)
 
+	// Métricas no formato Prometheus (fora do /api/v1, sem rate limit)
+	router.GET("/metrics", gin.WrapH(a.container.Metrics.Handler()))
+
 	// Adicionar endpoint para estatísticas de eventos
 	router.GET("/api/v1/events/stats", a.handleEventStats)
 
+	// Replay de eventos só existe com EVENTS_REPLAY_ENABLED (reexecuta os handlers dos consumers)
+	if a.config.Events.ReplayEnabled {
+		router.POST("/api/v1/admin/events/replay", middleware.AdminAuth(a.config.Admin.Token, a.logger), a.handleEventReplay)
+	}
+
+	// Health check profundo fora do grupo /api/v1: sem rate limit e com timeout próprio por dependência
+	healthHandler := handler.NewHealthHandler(a.container.DB, a.redis, handler.DefaultHealthCheckTimeout, a.logger)
+	router.GET("/api/v1/health/deep", healthHandler.DeepHealth)
+
+	// Probes do orquestrador: /healthz só indica o processo vivo, /readyz verifica as dependências
+	router.GET("/healthz", healthHandler.Liveness)
+	router.GET("/readyz", healthHandler.Readiness)
+
+	// WebSocket fora do grupo /api/v1: conexões longas não passam pelo timeout de requisição
+	wsHandler := handler.NewWebSocketHandler(a.hub, corsPolicy.AllowsOrigin, a.logger)
+	router.GET("/api/v1/ws/positions", wsHandler.SubscribePositions)
+
 	return router
 }
 
@@ -148,6 +296,40 @@ func (a *Application) handleEventStats(c *gin.Context) {
 	})
 }
 
+// handleEventReplay reentrega aos handlers os eventos de um stream dentro de uma janela
+func (a *Application) handleEventReplay(c *gin.Context) {
+	var req events.ReplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result, err := a.eventService.Replay(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, events.ErrInvalidReplayRange) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid replay request",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		a.logger.Error("Failed to replay events", "stream", req.Stream, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to replay events",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   result,
+	})
+}
+
 // gracefulShutdown realiza o encerramento gracioso da aplicação
 func (a *Application) gracefulShutdown() error {
 	a.logger.Info("Starting graceful shutdown...")
@@ -162,10 +344,15 @@ func (a *Application) gracefulShutdown() error {
 	}
 	a.logger.Info("HTTP server stopped")
 
-	// 2. Parar event service
-	a.eventService.Stop()
+	// 2. Parar workers em background (consumers de eventos, relay da outbox e limpeza de retenção)
+	// O relay publica os eventos pendentes antes de sair, depois que o HTTP parou de gravar novos
+	// Workers que não encerram a tempo já foram registrados no log; o shutdown continua
+	_ = a.workers.Shutdown(workerShutdownTimeout)
+
+	// 3. Desconectar clientes WebSocket (Shutdown não fecha conexões sequestradas)
+	a.hub.Close()
 
-	// 3. Sync dos logs pendentes
+	// 4. Sync dos logs pendentes
 	if err := a.logger.Sync(); err != nil {
 		return fmt.Errorf("failed to sync logger: %w", err)
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
 )
 
@@ -26,16 +27,37 @@ type PositionID struct {
 
 // Constantes de validação
 const (
-	MaxPositionAgeHours = 24 // Posições não podem ser muito antigas
+	DefaultMaxPositionAgeHours = 24 // Idade máxima padrão de uma nova posição
 )
 
+// maxPositionAge é a idade máxima aceita em NewPosition
+// Alterada apenas na inicialização da aplicação (uploads em lote/offline podem precisar de mais)
+var maxPositionAge = DefaultMaxPositionAgeHours * time.Hour
+
+// SetMaxPositionAge define a idade máxima de novas posições (deve ser chamada na inicialização)
+func SetMaxPositionAge(maxAge time.Duration) error {
+	if maxAge <= 0 {
+		return fmt.Errorf("max position age must be positive, got %v", maxAge)
+	}
+
+	maxPositionAge = maxAge
+	return nil
+}
+
+// CurrentMaxPositionAge retorna a idade máxima atual de novas posições
+func CurrentMaxPositionAge() time.Duration {
+	return maxPositionAge
+}
+
 // Erros específicos do domínio Position
 var (
 	ErrEmptyPositionID   = errors.New("position ID cannot be empty")
+	ErrInvalidPositionID = errors.New("position ID must be a UUID")
 	ErrPositionTooOld    = errors.New("position is too old")
 	ErrInvalidCoordinate = errors.New("invalid coordinate")
 	ErrInvalidUserID     = errors.New("invalid user ID")
 	ErrFuturePosition    = errors.New("position cannot be in the future")
+	ErrPositionNotFound  = errors.New("position not found")
 )
 
 // NewPositionID cria um novo PositionID
@@ -47,6 +69,28 @@ func NewPositionID(id string) (*PositionID, error) {
 	return &PositionID{value: id}, nil
 }
 
+// NewRandomPositionID gera o ID de uma nova posição (UUID v4)
+func NewRandomPositionID() *PositionID {
+	return &PositionID{value: uuid.New().String()}
+}
+
+// ParsePositionID cria um PositionID exigindo o formato UUID canônico
+// (xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx), normalizado em minúsculas
+// NewPositionID continua aceitando qualquer valor para reconstruir dados já persistidos
+func ParsePositionID(id string) (*PositionID, error) {
+	if id == "" {
+		return nil, ErrEmptyPositionID
+	}
+
+	// uuid.Parse também aceita "urn:uuid:" e chaves; apenas o formato canônico é aceito
+	parsed, err := uuid.Parse(id)
+	if err != nil || len(id) != 36 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPositionID, id)
+	}
+
+	return &PositionID{value: parsed.String()}, nil
+}
+
 // Value retorna o valor do PositionID
 func (pid *PositionID) Value() string {
 	return pid.value
@@ -109,9 +153,38 @@ func NewPosition(id string, userID UserID, lat, lng float64, recordedAt time.Tim
 	}, nil
 }
 
+// ReconstructPosition reconstrói uma posição já persistida (uso do repository)
+// O setor armazenado é a fonte da verdade: não é recalculado a partir de lat/lng,
+// e a regra de idade máxima não se aplica (vale apenas para novas posições)
+func ReconstructPosition(id string, userID UserID, lat, lng float64, sectorX, sectorY int, recordedAt, createdAt time.Time) (*Position, error) {
+	positionID, err := NewPositionID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	coordinate, err := valueobject.NewCoordinate(lat, lng)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCoordinate, err.Error())
+	}
+
+	sector, err := valueobject.NewSector(sectorX, sectorY)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stored sector: %w", err)
+	}
+
+	return &Position{
+		id:         *positionID,
+		userID:     userID,
+		coordinate: coordinate,
+		sector:     sector,
+		recordedAt: valueobject.NewTimestamp(recordedAt),
+		createdAt:  valueobject.NewTimestamp(createdAt),
+	}, nil
+}
+
 // validatePositionAge valida se a posição não é muito antiga
 func validatePositionAge(recordedAt *valueobject.Timestamp) error {
-	maxAge := time.Duration(MaxPositionAgeHours) * time.Hour
+	maxAge := maxPositionAge
 
 	if recordedAt.Age() > maxAge {
 		return fmt.Errorf("%w: position is %v old, max allowed is %v",
@@ -174,6 +247,24 @@ func (p *Position) DistanceTo(other *Position) float64 {
 	return p.coordinate.DistanceTo(other.coordinate)
 }
 
+// minSpeedInterval é o menor intervalo usado no cálculo de velocidade
+// Evita velocidades infinitas quando duas posições chegam com o mesmo timestamp
+const minSpeedInterval = time.Second
+
+// SpeedKmhFrom calcula a velocidade média em km/h desde a posição anterior (0 sem anterior)
+// Intervalos menores que 1s, inclusive negativos, contam como 1s
+func (p *Position) SpeedKmhFrom(previous *Position) float64 {
+	if previous == nil {
+		return 0
+	}
+
+	elapsed := p.recordedAt.Time().Sub(previous.recordedAt.Time())
+	if elapsed < minSpeedInterval {
+		elapsed = minSpeedInterval
+	}
+	return p.DistanceTo(previous) / elapsed.Seconds() * 3.6
+}
+
 // IsWithinRadius verifica se posição está dentro de raio de outra posição
 func (p *Position) IsWithinRadius(other *Position, radiusMeters float64) bool {
 	if other == nil {
@@ -190,6 +281,18 @@ func (p *Position) IsInSameSector(other *Position) bool {
 	return p.sector.Equals(other.sector)
 }
 
+// WithinSectorRadius verifica se posição está a até N setores de outra posição
+// Usa a distância euclidiana entre os setores (grid), mais barata que Haversine
+func (p *Position) WithinSectorRadius(other *Position, sectors int) bool {
+	if other == nil || sectors < 0 {
+		return false
+	}
+
+	// Point.DistanceTo retorna metros (setores * SectorSizeMeters)
+	distance := p.sector.Point().DistanceTo(other.sector.Point())
+	return distance <= float64(sectors*valueobject.SectorSizeMeters)
+}
+
 // GetNeighboringSectors retorna setores vizinhos desta posição
 func (p *Position) GetNeighboringSectors() ([]*valueobject.Sector, error) {
 	return p.sector.GetNeighboringSectors()
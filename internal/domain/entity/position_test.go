@@ -0,0 +1,240 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+)
+
+// newTestPosition cria uma posição válida para os testes
+func newTestPosition(t *testing.T, id string, lat, lng float64) *entity.Position {
+	t.Helper()
+
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+
+	position, err := entity.NewPosition(id, *userID, lat, lng, time.Now())
+	require.NoError(t, err)
+
+	return position
+}
+
+// TestPosition_WithinSectorRadius testa proximidade baseada no grid de setores
+func TestPosition_WithinSectorRadius(t *testing.T) {
+	// Na linha do equador, ~0.0009 grau de latitude equivale a 1 setor (100m)
+	origin := newTestPosition(t, "pos-origin", 0, 0)
+
+	testCases := []struct {
+		name    string
+		lat     float64
+		lng     float64
+		sectors int
+		want    bool
+	}{
+		{
+			name:    "mesmo setor com raio zero",
+			lat:     0.0001,
+			lng:     0.0001,
+			sectors: 0,
+			want:    true,
+		},
+		{
+			name:    "setor adjacente com raio 1",
+			lat:     0.0009,
+			lng:     0,
+			sectors: 1,
+			want:    true,
+		},
+		{
+			name:    "setor adjacente com raio zero",
+			lat:     0.0009,
+			lng:     0,
+			sectors: 0,
+			want:    false,
+		},
+		{
+			name:    "setor diagonal exige raio 2",
+			lat:     0.0009,
+			lng:     0.0009,
+			sectors: 1,
+			want:    false,
+		},
+		{
+			name:    "setor distante",
+			lat:     0.01,
+			lng:     0,
+			sectors: 5,
+			want:    false,
+		},
+		{
+			name:    "setor distante com raio suficiente",
+			lat:     0.01,
+			lng:     0,
+			sectors: 11,
+			want:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			other := newTestPosition(t, "pos-other", tc.lat, tc.lng)
+
+			assert.Equal(t, tc.want, origin.WithinSectorRadius(other, tc.sectors))
+			assert.Equal(t, tc.want, other.WithinSectorRadius(origin, tc.sectors))
+		})
+	}
+}
+
+// TestPosition_WithinSectorRadius_InvalidInput testa entradas inválidas
+func TestPosition_WithinSectorRadius_InvalidInput(t *testing.T) {
+	origin := newTestPosition(t, "pos-origin", 0, 0)
+
+	assert.False(t, origin.WithinSectorRadius(nil, 1))
+	assert.False(t, origin.WithinSectorRadius(origin, -1))
+}
+
+// TestReconstructPosition_KeepsStoredSector testa que o setor persistido não é recalculado
+func TestReconstructPosition_KeepsStoredSector(t *testing.T) {
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+
+	recordedAt := time.Now().Add(-48 * time.Hour) // Mais antiga que DefaultMaxPositionAgeHours
+
+	// Setor (7, 9) não corresponde a lat/lng (0, 0): o valor armazenado prevalece
+	position, err := entity.ReconstructPosition("pos-1", *userID, 0, 0, 7, 9, recordedAt, recordedAt)
+	require.NoError(t, err)
+
+	assert.Equal(t, 7, position.SectorX())
+	assert.Equal(t, 9, position.SectorY())
+	assert.True(t, position.RecordedAt().Time().Equal(recordedAt))
+	assert.True(t, position.CreatedAt().Time().Equal(recordedAt))
+}
+
+// TestReconstructPosition_InvalidData testa validações aplicadas na reconstrução
+func TestReconstructPosition_InvalidData(t *testing.T) {
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+	now := time.Now()
+
+	_, err = entity.ReconstructPosition("", *userID, 0, 0, 0, 0, now, now)
+	assert.ErrorIs(t, err, entity.ErrEmptyPositionID)
+
+	_, err = entity.ReconstructPosition("pos-1", *userID, 91, 0, 0, 0, now, now)
+	assert.ErrorIs(t, err, entity.ErrInvalidCoordinate)
+
+	_, err = entity.ReconstructPosition("pos-1", *userID, 0, 0, valueobject.MaxSectorCoord+1, 0, now, now)
+	assert.Error(t, err)
+}
+
+// TestPosition_SpeedKmhFrom testa a velocidade média desde a posição anterior
+func TestPosition_SpeedKmhFrom(t *testing.T) {
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+
+	now := time.Now()
+	previous, err := entity.NewPosition("pos-previous", *userID, 0, 0, now.Add(-time.Minute))
+	require.NoError(t, err)
+
+	// ~1km ao norte em 1 minuto: ~60 km/h
+	current, err := entity.NewPosition("pos-current", *userID, 0.008993, 0, now)
+	require.NoError(t, err)
+	assert.InDelta(t, 60, current.SpeedKmhFrom(previous), 0.5)
+
+	// Sem posição anterior não há velocidade
+	assert.Zero(t, current.SpeedKmhFrom(nil))
+
+	// Mesmo timestamp: o intervalo mínimo de 1s evita velocidade infinita
+	teleport, err := entity.NewPosition("pos-teleport", *userID, 0.008993, 0, now.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.InDelta(t, 3600, teleport.SpeedKmhFrom(previous), 5)
+}
+
+// TestNewRandomPositionID testa que os IDs gerados são UUIDs únicos aceitos pela variante estrita
+func TestNewRandomPositionID(t *testing.T) {
+	first := entity.NewRandomPositionID()
+	second := entity.NewRandomPositionID()
+
+	assert.False(t, first.Equals(second))
+
+	parsed, err := entity.ParsePositionID(first.Value())
+	require.NoError(t, err)
+	assert.True(t, parsed.Equals(first))
+}
+
+// TestParsePositionID testa a validação estrita de UUID e a versão leniente para reconstrução
+func TestParsePositionID(t *testing.T) {
+	parsed, err := entity.ParsePositionID("3F2504E0-4F89-11D3-9A0C-0305E82C3301")
+	require.NoError(t, err)
+	assert.Equal(t, "3f2504e0-4f89-11d3-9a0c-0305e82c3301", parsed.Value())
+
+	invalid := []string{
+		"pos-1",
+		"3f2504e0-4f89-11d3-9a0c-0305e82c330",
+		"urn:uuid:3f2504e0-4f89-11d3-9a0c-0305e82c3301",
+		"{3f2504e0-4f89-11d3-9a0c-0305e82c3301}",
+	}
+	for _, id := range invalid {
+		_, err := entity.ParsePositionID(id)
+		assert.ErrorIs(t, err, entity.ErrInvalidPositionID, id)
+	}
+
+	_, err = entity.ParsePositionID("")
+	assert.ErrorIs(t, err, entity.ErrEmptyPositionID)
+
+	lenient, err := entity.NewPositionID("pos-1")
+	require.NoError(t, err)
+	assert.Equal(t, "pos-1", lenient.Value())
+}
+
+// useMaxPositionAge define a idade máxima durante o teste e restaura a anterior ao final
+func useMaxPositionAge(t *testing.T, maxAge time.Duration) {
+	t.Helper()
+	previous := entity.CurrentMaxPositionAge()
+	require.NoError(t, entity.SetMaxPositionAge(maxAge))
+	t.Cleanup(func() {
+		require.NoError(t, entity.SetMaxPositionAge(previous))
+	})
+}
+
+// TestNewPosition_MaxAge testa aceitação logo abaixo e rejeição logo acima da idade máxima
+func TestNewPosition_MaxAge(t *testing.T) {
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		maxAge  time.Duration
+		age     time.Duration
+		wantErr bool
+	}{
+		{name: "default just under", maxAge: entity.DefaultMaxPositionAgeHours * time.Hour, age: 24*time.Hour - time.Minute},
+		{name: "default just over", maxAge: entity.DefaultMaxPositionAgeHours * time.Hour, age: 24*time.Hour + time.Minute, wantErr: true},
+		{name: "configured just under", maxAge: 72 * time.Hour, age: 72*time.Hour - time.Minute},
+		{name: "configured just over", maxAge: 72 * time.Hour, age: 72*time.Hour + time.Minute, wantErr: true},
+		{name: "shorter than default", maxAge: time.Hour, age: 2 * time.Hour, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useMaxPositionAge(t, tt.maxAge)
+
+			_, err := entity.NewPosition("pos-1", *userID, -23.550520, -46.633309, time.Now().Add(-tt.age))
+			if tt.wantErr {
+				assert.ErrorIs(t, err, entity.ErrPositionTooOld)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// TestSetMaxPositionAge_RejectsNonPositive testa que a idade máxima precisa ser positiva
+func TestSetMaxPositionAge_RejectsNonPositive(t *testing.T) {
+	assert.Error(t, entity.SetMaxPositionAge(0))
+	assert.Error(t, entity.SetMaxPositionAge(-time.Hour))
+	assert.Equal(t, entity.DefaultMaxPositionAgeHours*time.Hour, entity.CurrentMaxPositionAge())
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
 )
@@ -47,6 +48,7 @@ var (
 	ErrNameTooShort   = errors.New("name too short")
 	ErrNameTooLong    = errors.New("name too long")
 	ErrUserIDNotFound = errors.New("user ID not found")
+	ErrEmailNotFound  = errors.New("user email not found")
 )
 
 // NewUserID cria um novo UserID
@@ -136,6 +138,20 @@ func NewUser(id, name, email string) (*User, error) {
 	}, nil
 }
 
+// ReconstructUser reconstrói um usuário já persistido (uso do repository)
+// Aplica as mesmas validações de NewUser, mas preserva os timestamps originais
+func ReconstructUser(id, name, email string, createdAt, updatedAt time.Time) (*User, error) {
+	user, err := NewUser(id, name, email)
+	if err != nil {
+		return nil, err
+	}
+
+	user.createdAt = valueobject.NewTimestamp(createdAt)
+	user.updatedAt = valueobject.NewTimestamp(updatedAt)
+
+	return user, nil
+}
+
 // validateName valida o nome do usuário
 func validateName(name string) error {
 	name = strings.TrimSpace(name)
@@ -0,0 +1,33 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+)
+
+// TestReconstructUser_PreservesTimestamps testa que a reconstrução mantém os timestamps originais
+func TestReconstructUser_PreservesTimestamps(t *testing.T) {
+	createdAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	updatedAt := time.Date(2024, 2, 20, 8, 0, 0, 0, time.UTC)
+
+	user, err := entity.ReconstructUser("user123", "João Silva", "joao@example.com", createdAt, updatedAt)
+	require.NoError(t, err)
+
+	assert.True(t, user.CreatedAt().Time().Equal(createdAt))
+	assert.True(t, user.UpdatedAt().Time().Equal(updatedAt))
+}
+
+// TestReconstructUser_InvalidData testa que a reconstrução aplica as validações do domínio
+func TestReconstructUser_InvalidData(t *testing.T) {
+	now := time.Now()
+
+	_, err := entity.ReconstructUser("user123", "João Silva", "invalid-email", now, now)
+	assert.ErrorIs(t, err, entity.ErrInvalidEmail)
+
+	_, err = entity.ReconstructUser("", "João Silva", "joao@example.com", now, now)
+	assert.ErrorIs(t, err, entity.ErrEmptyUserID)
+}
@@ -17,8 +17,14 @@ const (
 	// UserLeftSector quando usuário sai de um setor
 	EventTypeUserLeftSector EventType = "sector.user_left"
 
+	// UserMovedInSector quando usuário se move sem sair do setor (opcional, ver config)
+	EventTypeUserMovedInSector EventType = "sector.user_moved"
+
 	// UserNearby quando usuários ficam próximos
 	EventTypeUserNearby EventType = "proximity.user_nearby"
+
+	// UserDeleted quando um usuário é removido (junto com suas posições)
+	EventTypeUserDeleted EventType = "user.deleted"
 )
 
 // Event representa a estrutura base de um evento
@@ -50,6 +56,8 @@ type PositionChangedData struct {
 	PreviousSector string  `json:"previous_sector"` // Setor anterior (pode ser vazio)
 	NewSector      string  `json:"new_sector"`      // Novo setor
 	DistanceMoved  float64 `json:"distance_moved"`  // Distância movida em metros
+	SpeedKmh       float64 `json:"speed_kmh"`       // Velocidade média desde a posição anterior (0 sem anterior)
+	Suspicious     bool    `json:"suspicious"`      // Velocidade acima de POSITIONS_MAX_SPEED_KMH
 }
 
 // SectorChangedData dados específicos de mudança de setor
@@ -62,6 +70,16 @@ type SectorChangedData struct {
 	UsersInSector int     `json:"users_in_sector"` // Quantos usuários no setor agora
 }
 
+// SectorMovementData dados específicos de movimento dentro do mesmo setor
+type SectorMovementData struct {
+	SectorX       int     `json:"sector_x"`       // Coordenada X do setor
+	SectorY       int     `json:"sector_y"`       // Coordenada Y do setor
+	SectorID      string  `json:"sector_id"`      // ID do setor
+	Latitude      float64 `json:"latitude"`       // Nova latitude
+	Longitude     float64 `json:"longitude"`      // Nova longitude
+	DistanceMoved float64 `json:"distance_moved"` // Distância movida em metros
+}
+
 // ProximityData dados específicos de proximidade entre usuários
 type ProximityData struct {
 	NearUserID   string  `json:"near_user_id"`   // ID do usuário próximo
@@ -87,6 +105,8 @@ func NewPositionChangedEvent(userID, eventID string, data PositionChangedData) *
 			"previous_sector": data.PreviousSector,
 			"new_sector":      data.NewSector,
 			"distance_moved":  data.DistanceMoved,
+			"speed_kmh":       data.SpeedKmh,
+			"suspicious":      data.Suspicious,
 		},
 		Metadata: EventMetadata{
 			Source:  "position-api",
@@ -116,3 +136,63 @@ func NewSectorChangedEvent(userID, eventID string, eventType EventType, data Sec
 		},
 	}
 }
+
+// NewSectorMovementEvent cria um novo evento de movimento dentro do setor
+func NewSectorMovementEvent(userID, eventID string, data SectorMovementData) *Event {
+	return &Event{
+		Type:      EventTypeUserMovedInSector,
+		UserID:    userID,
+		EventID:   eventID,
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"sector_x":       data.SectorX,
+			"sector_y":       data.SectorY,
+			"sector_id":      data.SectorID,
+			"latitude":       data.Latitude,
+			"longitude":      data.Longitude,
+			"distance_moved": data.DistanceMoved,
+		},
+		Metadata: EventMetadata{
+			Source:  "position-api",
+			Version: "1.0",
+		},
+	}
+}
+
+// NewProximityEvent cria um novo evento de proximidade entre usuários
+func NewProximityEvent(userID, eventID string, data ProximityData) *Event {
+	return &Event{
+		Type:      EventTypeUserNearby,
+		UserID:    userID,
+		EventID:   eventID,
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"near_user_id":   data.NearUserID,
+			"near_user_name": data.NearUserName,
+			"distance":       data.Distance,
+			"max_distance":   data.MaxDistance,
+			"is_entering":    data.IsEntering,
+		},
+		Metadata: EventMetadata{
+			Source:  "position-api",
+			Version: "1.0",
+		},
+	}
+}
+
+// NewUserDeletedEvent cria um novo evento de remoção de usuário
+func NewUserDeletedEvent(userID, eventID string) *Event {
+	return &Event{
+		Type:      EventTypeUserDeleted,
+		UserID:    userID,
+		EventID:   eventID,
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"user_id": userID,
+		},
+		Metadata: EventMetadata{
+			Source:  "user-api",
+			Version: "1.0",
+		},
+	}
+}
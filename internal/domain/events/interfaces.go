@@ -2,6 +2,7 @@ package events
 
 import (
 	"context"
+	"fmt"
 )
 
 // Publisher interface para publicar eventos
@@ -15,10 +16,35 @@ type Publisher interface {
 	// PublishSectorChanged publica evento de mudança de setor
 	PublishSectorChanged(ctx context.Context, event *Event) error
 
+	// PublishProximity publica evento de proximidade entre usuários
+	PublishProximity(ctx context.Context, event *Event) error
+
+	// PublishUserDeleted publica evento de remoção de usuário
+	PublishUserDeleted(ctx context.Context, event *Event) error
+
 	// Close fecha a conexão do publisher
 	Close() error
 }
 
+// PublishError indica que o evento não foi publicado depois de todas as tentativas do publisher
+// Quem publica decide se a perda do evento falha a operação (use errors.As para obter as tentativas)
+type PublishError struct {
+	Stream    string
+	EventType EventType
+	Attempts  int
+	Err       error
+}
+
+// Error descreve a falha com o stream e o total de tentativas
+func (e *PublishError) Error() string {
+	return fmt.Sprintf("failed to publish %s to stream %s after %d attempts: %v", e.EventType, e.Stream, e.Attempts, e.Err)
+}
+
+// Unwrap retorna o erro da última tentativa
+func (e *PublishError) Unwrap() error {
+	return e.Err
+}
+
 // Consumer interface para consumir eventos
 type Consumer interface {
 	// Subscribe se inscreve em um stream para consumir eventos
@@ -45,8 +71,19 @@ const (
 	StreamPositionEvents  = "geolocation:position-events"
 	StreamSectorEvents    = "geolocation:sector-events"
 	StreamProximityEvents = "geolocation:proximity-events"
+	StreamUserEvents      = "geolocation:user-events"
 )
 
+// DefaultStreamEventTypes retorna os tipos de evento aceitos por padrão em cada stream
+func DefaultStreamEventTypes() map[string][]EventType {
+	return map[string][]EventType{
+		StreamPositionEvents:  {EventTypePositionChanged},
+		StreamSectorEvents:    {EventTypeUserEnteredSector, EventTypeUserLeftSector, EventTypeUserMovedInSector},
+		StreamProximityEvents: {EventTypeUserNearby},
+		StreamUserEvents:      {EventTypeUserDeleted},
+	}
+}
+
 // ConsumerGroups nomes dos grupos de consumidores
 const (
 	ConsumerGroupNotifications = "notifications"
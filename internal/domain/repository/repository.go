@@ -2,11 +2,17 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/events"
 	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
 )
 
+// ErrQueryTimeout indica uma consulta interrompida pelo prazo configurado (DB_QUERY_TIMEOUT_MS)
+var ErrQueryTimeout = errors.New("database query timed out")
+
 // UserRepository define operações de persistência para usuários
 // Interface = contrato, não implementação
 // Seguindo Repository Pattern + Dependency Inversion Principle
@@ -14,20 +20,23 @@ type UserRepository interface {
 	// Save persiste um usuário (create ou update)
 	Save(ctx context.Context, user *entity.User) error
 
-	// FindByID busca usuário por ID
+	// FindByID busca usuário por ID; retorna entity.ErrUserIDNotFound se não existir
 	FindByID(ctx context.Context, id entity.UserID) (*entity.User, error)
 
-	// FindByEmail busca usuário por email
+	// FindByEmail busca usuário por email; retorna entity.ErrEmailNotFound se não existir
 	FindByEmail(ctx context.Context, email entity.Email) (*entity.User, error)
 
 	// Exists verifica se usuário existe
 	Exists(ctx context.Context, id entity.UserID) (bool, error)
 
-	// Delete remove usuário
+	// Delete remove usuário e suas posições; retorna entity.ErrUserIDNotFound se não existir
 	Delete(ctx context.Context, id entity.UserID) error
 
-	// FindAll retorna todos os usuários (com paginação)
-	FindAll(ctx context.Context, limit, offset int) ([]*entity.User, error)
+	// FindAll retorna uma página de usuários com filtro e ordenação
+	FindAll(ctx context.Context, query UserQuery) ([]*entity.User, error)
+
+	// CountUsers retorna o total de usuários que atendem ao filtro (para paginação de FindAll)
+	CountUsers(ctx context.Context, filter UserFilter) (int, error)
 }
 
 // PositionRepository define operações de persistência para posições
@@ -35,17 +44,42 @@ type PositionRepository interface {
 	// Save persiste uma posição
 	Save(ctx context.Context, position *entity.Position) error
 
-	// FindByID busca posição por ID
+	// SaveWithEvents persiste uma posição e grava os eventos na outbox na mesma transação
+	// Se a posição atual já for mais recente, a posição fica só no histórico e os eventos não são gravados
+	SaveWithEvents(ctx context.Context, position *entity.Position, outbox []*OutboxEntry) error
+
+	// SaveHistory persiste uma posição apenas no histórico (sem alterar a posição atual)
+	SaveHistory(ctx context.Context, position *entity.Position) error
+
+	// SaveBatch persiste várias posições no histórico, atualiza a posição atual com current
+	// e grava os eventos na outbox (transação única)
+	SaveBatch(ctx context.Context, history []*entity.Position, current []*entity.Position, outbox []*OutboxEntry) error
+
+	// FindByID busca posição por ID; retorna entity.ErrPositionNotFound se não existir
 	FindByID(ctx context.Context, id entity.PositionID) (*entity.Position, error)
 
-	// FindCurrentByUserID busca posição atual de um usuário
+	// FindCurrentByUserID busca posição atual de um usuário; retorna entity.ErrPositionNotFound se não houver
 	FindCurrentByUserID(ctx context.Context, userID entity.UserID) (*entity.Position, error)
 
-	// FindHistoryByUserID busca histórico de posições de um usuário
-	FindHistoryByUserID(ctx context.Context, userID entity.UserID, limit int) ([]*entity.Position, error)
+	// FindCurrentByUserIDs busca as posições atuais de vários usuários em uma única query
+	// Usuários sem posição atual são omitidos (sem erro); a ordem do resultado não é garantida
+	FindCurrentByUserIDs(ctx context.Context, userIDs []entity.UserID) ([]*entity.Position, error)
+
+	// FindHistoryByUserID busca uma página do histórico de posições de um usuário (mais recentes primeiro)
+	FindHistoryByUserID(ctx context.Context, userID entity.UserID, limit, offset int) ([]*entity.Position, error)
+
+	// FindHistoryByUserIDInRange busca o histórico de um usuário entre from e to (mais antigas primeiro), até limit posições
+	FindHistoryByUserIDInRange(ctx context.Context, userID entity.UserID, from, to *valueobject.Timestamp, limit int) ([]*entity.Position, error)
 
-	// FindNearby busca posições próximas a uma coordenada
-	FindNearby(ctx context.Context, coord *valueobject.Coordinate, radiusMeters float64, limit int) ([]*entity.Position, error)
+	// FindHistoryByUserIDAfter busca o histórico de um usuário em ordem cronológica, a partir de after (paginação por chave)
+	// Com after == nil começa pela posição mais antiga
+	FindHistoryByUserIDAfter(ctx context.Context, userID entity.UserID, after *HistoryCursor, limit int) ([]*entity.Position, error)
+
+	// CountHistoryByUserID conta o total de posições no histórico de um usuário
+	CountHistoryByUserID(ctx context.Context, userID entity.UserID) (int, error)
+
+	// FindNearby busca posições próximas a uma coordenada, da mais próxima para a mais distante
+	FindNearby(ctx context.Context, coord *valueobject.Coordinate, radiusMeters float64, limit int) ([]*NearbyPosition, error)
 
 	// FindInSector busca posições em um setor específico
 	FindInSector(ctx context.Context, sector *valueobject.Sector) ([]*entity.Position, error)
@@ -58,6 +92,53 @@ type PositionRepository interface {
 
 	// DeleteOldPositions remove posições antigas (cleanup)
 	DeleteOldPositions(ctx context.Context, olderThan *valueobject.Timestamp) (int, error)
+
+	// FindPositionsAfterID lista posições do histórico em ordem de ID, a partir de afterID (paginação por chave)
+	// afterID vazio começa do início
+	FindPositionsAfterID(ctx context.Context, afterID string, limit int) ([]*entity.Position, error)
+
+	// UpdateSectors regrava sector_x/sector_y das posições (histórico e posição atual) em uma transação
+	UpdateSectors(ctx context.Context, positions []*entity.Position) error
+
+	// GetSectorStatistics retorna estatísticas de um setor
+	GetSectorStatistics(ctx context.Context, sector *valueobject.Sector) (*SectorStats, error)
+
+	// FindRecentActivity busca as posições atuais mais recentemente atualizadas (mais nova primeiro)
+	// Com since != nil, posições atualizadas antes de since são ignoradas
+	FindRecentActivity(ctx context.Context, since *valueobject.Timestamp, limit int) ([]*RecentActivity, error)
+
+	// FindOccupiedSectors conta os usuários com posição atual em cada setor ocupado (mais ocupado primeiro)
+	// Com bbox != nil, retorna apenas setores com algum usuário dentro do retângulo, com a contagem do setor inteiro
+	FindOccupiedSectors(ctx context.Context, bbox *BoundingBox) ([]*SectorOccupancy, error)
+
+	// FindInBoundingBox busca as posições atuais dentro do retângulo (atualizadas mais recentemente primeiro)
+	FindInBoundingBox(ctx context.Context, bbox *BoundingBox, limit int) ([]*entity.Position, error)
+}
+
+// OutboxRepository define operações sobre a outbox de eventos (eventos gravados junto com a posição)
+type OutboxRepository interface {
+	// FindPending retorna os eventos ainda não publicados nem descartados, em ordem de gravação
+	FindPending(ctx context.Context, limit int) ([]*OutboxEntry, error)
+
+	// MarkPublished registra que o evento chegou ao stream
+	MarkPublished(ctx context.Context, id int64) error
+
+	// MarkFailed registra uma tentativa de publicação sem sucesso (o evento segue pendente)
+	MarkFailed(ctx context.Context, id int64, reason string) error
+
+	// MarkDead registra a última falha e descarta o evento, que deixa de ser lido como pendente
+	MarkDead(ctx context.Context, id int64, reason string) error
+
+	// DeletePublishedBefore remove eventos publicados antes do timestamp e retorna quantos foram removidos
+	DeletePublishedBefore(ctx context.Context, before *valueobject.Timestamp) (int, error)
+}
+
+// OutboxEntry representa um evento na outbox, a ser publicado no stream indicado
+type OutboxEntry struct {
+	ID       int64 // Atribuído pelo banco; zero antes de gravar
+	Stream   string
+	Event    *events.Event
+	Attempts int // Tentativas de publicação sem sucesso
 }
 
 // PositionQuery representa critérios de busca para posições
@@ -72,6 +153,55 @@ type PositionQuery struct {
 	Offset       int                     `json:"offset,omitempty"`
 }
 
+// UserFilter restringe a listagem de usuários; campos vazios não filtram
+type UserFilter struct {
+	NameContains string // Trecho do nome, sem diferenciar maiúsculas
+	EmailDomain  string // Domínio do email (ex: "example.com"), sem o "@"
+}
+
+// UserSort define a ordenação da listagem de usuários
+type UserSort string
+
+// Ordenações aceitas em UserQuery.Sort
+const (
+	UserSortCreatedAtDesc UserSort = "created_at_desc" // Mais recentes primeiro (padrão)
+	UserSortCreatedAtAsc  UserSort = "created_at_asc"
+	UserSortName          UserSort = "name"
+)
+
+// IsValid verifica se a ordenação é uma das aceitas
+func (s UserSort) IsValid() bool {
+	switch s {
+	case UserSortCreatedAtDesc, UserSortCreatedAtAsc, UserSortName:
+		return true
+	default:
+		return false
+	}
+}
+
+// UserQuery combina filtro, ordenação e paginação da listagem de usuários
+type UserQuery struct {
+	Filter UserFilter
+	Sort   UserSort // Vazio usa UserSortCreatedAtDesc
+	Limit  int
+	Offset int
+}
+
+// HistoryCursor marca a última posição lida na paginação por chave do histórico
+type HistoryCursor struct {
+	RecordedAt time.Time
+	PositionID string
+}
+
+// NewHistoryCursor cria o cursor que continua a leitura logo após a posição
+func NewHistoryCursor(position *entity.Position) *HistoryCursor {
+	positionID := position.ID()
+	return &HistoryCursor{
+		RecordedAt: position.RecordedAt().Time(),
+		PositionID: positionID.Value(),
+	}
+}
+
 // TimeRange representa um intervalo de tempo
 type TimeRange struct {
 	From *valueobject.Timestamp `json:"from,omitempty"`
@@ -88,9 +218,6 @@ type AdvancedPositionRepository interface {
 
 	// FindUsersInRadius busca usuários únicos dentro de um raio
 	FindUsersInRadius(ctx context.Context, coord *valueobject.Coordinate, radiusMeters float64) ([]entity.UserID, error)
-
-	// GetSectorStatistics retorna estatísticas de um setor
-	GetSectorStatistics(ctx context.Context, sector *valueobject.Sector) (*SectorStats, error)
 }
 
 // SectorStats representa estatísticas de um setor
@@ -100,3 +227,39 @@ type SectorStats struct {
 	PositionCount int                    `json:"position_count"`
 	LastActivity  *valueobject.Timestamp `json:"last_activity,omitempty"`
 }
+
+// SectorOccupancy representa um setor com usuários na posição atual
+type SectorOccupancy struct {
+	Sector    *valueobject.Sector `json:"sector"`
+	UserCount int                 `json:"user_count"`
+}
+
+// BoundingBox representa um retângulo em lat/lng (ex.: viewport de um mapa)
+// MinLongitude <= MaxLongitude: retângulos que cruzam o antimeridiano não são suportados
+type BoundingBox struct {
+	MinLatitude  float64 `json:"min_latitude"`
+	MaxLatitude  float64 `json:"max_latitude"`
+	MinLongitude float64 `json:"min_longitude"`
+	MaxLongitude float64 `json:"max_longitude"`
+}
+
+// NearbyPosition representa uma posição encontrada na busca de proximidade
+type NearbyPosition struct {
+	Position  *entity.Position `json:"position"`
+	DistanceM *float64         `json:"distance_meters,omitempty"` // Calculada pelo banco (ST_Distance); nil se indisponível
+}
+
+// Distance retorna a distância calculada pelo banco, ou Haversine até center quando ausente
+func (n *NearbyPosition) Distance(center *valueobject.Coordinate) float64 {
+	if n.DistanceM != nil {
+		return *n.DistanceM
+	}
+	return center.DistanceTo(n.Position.Coordinate())
+}
+
+// RecentActivity representa a posição atual de um usuário no feed de atividade
+type RecentActivity struct {
+	UserName  string                 `json:"user_name"`
+	Position  *entity.Position       `json:"position"`
+	UpdatedAt *valueobject.Timestamp `json:"updated_at"`
+}
@@ -25,12 +25,21 @@ type ProximityResult struct {
 
 // SectorAnalysis representa análise de um setor
 type SectorAnalysis struct {
-	Sector          *valueobject.Sector   `json:"sector"`
-	UserCount       int                   `json:"user_count"`
-	Density         float64               `json:"density_per_km2"`
-	NeighborSectors []*valueobject.Sector `json:"neighbor_sectors"`
+	Sector    *valueobject.Sector `json:"sector"`
+	UserCount int                 `json:"user_count"`
+	Density   float64             `json:"density_per_km2"`
+	Neighbors []*SectorUserCount  `json:"neighbors"` // Os 8 setores ao redor (menos os fora dos limites)
 }
 
+// SectorUserCount representa a contagem de usuários (posição atual) de um setor
+type SectorUserCount struct {
+	Sector    *valueobject.Sector `json:"sector"`
+	UserCount int                 `json:"user_count"`
+}
+
+// SectorAreaKm2 é a área de um setor (SectorSizeMeters x SectorSizeMeters) em km²
+const SectorAreaKm2 = (valueobject.SectorSizeMeters / 1000.0) * (valueobject.SectorSizeMeters / 1000.0)
+
 // Erros específicos do domain service
 var (
 	ErrNoPositionsFound = errors.New("no positions found")
@@ -63,13 +72,11 @@ func (s *GeoLocationService) FindNearbyUsers(ctx context.Context, coord *valueob
 
 	// Calcular distâncias e criar resultados
 	results := make([]*ProximityResult, 0, len(positions))
-	for _, pos := range positions {
-		distance := coord.DistanceTo(pos.Coordinate())
-
+	for _, nearby := range positions {
 		result := &ProximityResult{
-			User:     pos.UserID(),
-			Position: pos,
-			Distance: distance,
+			User:     nearby.Position.UserID(),
+			Position: nearby.Position,
+			Distance: nearby.Distance(coord),
 		}
 		results = append(results, result)
 	}
@@ -93,37 +100,67 @@ func (s *GeoLocationService) FindUsersInSector(ctx context.Context, sector *valu
 }
 
 // AnalyzeSector analisa um setor e seus vizinhos
+// O bloco 3x3 é consultado de uma vez com FindInSectors e contado por setor em memória
 func (s *GeoLocationService) AnalyzeSector(ctx context.Context, sector *valueobject.Sector) (*SectorAnalysis, error) {
 	if sector == nil {
 		return nil, ErrInvalidSector
 	}
 
-	// Buscar posições no setor
-	positions, err := s.positionRepo.FindInSector(ctx, sector)
+	// Obter o bloco 3x3 (o próprio setor e os vizinhos)
+	block, err := sector.GetNeighboringSectors()
 	if err != nil {
-		return nil, fmt.Errorf("failed to analyze sector %s: %w", sector.ID(), err)
+		return nil, fmt.Errorf("failed to get neighboring sectors: %w", err)
 	}
 
-	// Obter setores vizinhos
-	neighbors, err := sector.GetNeighboringSectors()
+	// Buscar posições atuais em todo o bloco
+	positions, err := s.positionRepo.FindInSectors(ctx, block)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get neighboring sectors: %w", err)
+		return nil, fmt.Errorf("failed to analyze sector %s: %w", sector.ID(), err)
+	}
+
+	counts := make(map[string]int, len(block))
+	for _, position := range positions {
+		counts[position.Sector().ID()]++
+	}
+
+	neighbors := make([]*SectorUserCount, 0, len(block)-1)
+	for _, neighbor := range block {
+		if neighbor.Equals(sector) {
+			continue
+		}
+		neighbors = append(neighbors, &SectorUserCount{
+			Sector:    neighbor,
+			UserCount: counts[neighbor.ID()],
+		})
 	}
 
-	// Calcular densidade (usuários por km²)
-	// Cada setor = 100m x 100m = 0.01 km²
-	sectorAreaKm2 := 0.01
-	density := float64(len(positions)) / sectorAreaKm2
+	userCount := counts[sector.ID()]
 
 	return &SectorAnalysis{
-		Sector:          sector,
-		UserCount:       len(positions),
-		Density:         density,
-		NeighborSectors: neighbors,
+		Sector:    sector,
+		UserCount: userCount,
+		Density:   float64(userCount) / SectorAreaKm2,
+		Neighbors: neighbors,
 	}, nil
 }
 
+// ComputeSectorDensity calcula a densidade atual do setor (usuários por km²)
+// Conta os usuários cuja posição atual está no setor, sobre a área de SectorAreaKm2
+func (s *GeoLocationService) ComputeSectorDensity(ctx context.Context, sector *valueobject.Sector) (float64, error) {
+	if sector == nil {
+		return 0, ErrInvalidSector
+	}
+
+	positions, err := s.positionRepo.FindInSector(ctx, sector)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute density of sector %s: %w", sector.ID(), err)
+	}
+
+	return float64(len(positions)) / SectorAreaKm2, nil
+}
+
 // FindUsersInRadius encontra usuários em múltiplos setores dentro de um raio
+// Cada usuário aparece uma única vez, com a sua posição mais próxima do centro
 func (s *GeoLocationService) FindUsersInRadius(ctx context.Context, center *valueobject.Coordinate, radiusMeters float64) ([]*ProximityResult, error) {
 	if radiusMeters <= 0 {
 		return nil, fmt.Errorf("%w: radius must be positive", ErrInvalidRadius)
@@ -154,20 +191,33 @@ func (s *GeoLocationService) FindUsersInRadius(ctx context.Context, center *valu
 		return nil, fmt.Errorf("failed to find positions in sectors: %w", err)
 	}
 
-	// Filtrar por distância real e criar resultados
+	// Filtrar por distância real, mantendo a posição mais próxima de cada usuário
 	results := make([]*ProximityResult, 0)
+	byUser := make(map[string]*ProximityResult)
 	for _, pos := range positions {
 		distance := center.DistanceTo(pos.Coordinate())
 
 		// Só incluir se estiver realmente dentro do raio
-		if distance <= radiusMeters {
-			result := &ProximityResult{
-				User:     pos.UserID(),
-				Position: pos,
-				Distance: distance,
+		if distance > radiusMeters {
+			continue
+		}
+
+		userID := pos.UserID()
+		if closest, ok := byUser[userID.Value()]; ok {
+			if distance < closest.Distance {
+				closest.Position = pos
+				closest.Distance = distance
 			}
-			results = append(results, result)
+			continue
+		}
+
+		result := &ProximityResult{
+			User:     userID,
+			Position: pos,
+			Distance: distance,
 		}
+		byUser[userID.Value()] = result
+		results = append(results, result)
 	}
 
 	return s.sortByDistance(results), nil
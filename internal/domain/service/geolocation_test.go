@@ -0,0 +1,67 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/service"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+)
+
+// newPosition cria uma posição de userID na coordenada informada
+func newPosition(t *testing.T, id, userID string, lat, lng float64) *entity.Position {
+	uid, err := entity.NewUserID(userID)
+	require.NoError(t, err)
+
+	position, err := entity.NewPosition(id, *uid, lat, lng, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	return position
+}
+
+// TestGeoLocationService_FindUsersInRadius_DedupsByUser testa um único resultado por usuário, com a posição mais próxima
+func TestGeoLocationService_FindUsersInRadius_DedupsByUser(t *testing.T) {
+	center, err := valueobject.NewCoordinate(-23.550520, -46.633309)
+	require.NoError(t, err)
+
+	// user123 tem três pontos no raio; user456 um ponto; user789 está fora do raio
+	positions := []*entity.Position{
+		newPosition(t, "pos-1", "user123", -23.551000, -46.633309),
+		newPosition(t, "pos-2", "user123", -23.550600, -46.633309),
+		newPosition(t, "pos-3", "user456", -23.550800, -46.633309),
+		newPosition(t, "pos-4", "user123", -23.551500, -46.633309),
+		newPosition(t, "pos-5", "user789", -23.560000, -46.633309),
+	}
+
+	positionRepo := new(mocks.MockPositionRepository)
+	positionRepo.On("FindInSectors", mock.Anything, mock.Anything).Return(positions, nil)
+
+	results, err := service.NewGeoLocationService(positionRepo).FindUsersInRadius(context.Background(), center, 200)
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "user123", results[0].User.Value())
+	assert.Same(t, positions[1], results[0].Position)
+	assert.InDelta(t, center.DistanceTo(positions[1].Coordinate()), results[0].Distance, 1e-9)
+
+	assert.Equal(t, "user456", results[1].User.Value())
+	assert.Same(t, positions[2], results[1].Position)
+	positionRepo.AssertExpectations(t)
+}
+
+// TestGeoLocationService_FindUsersInRadius_InvalidRadius testa o raio não positivo
+func TestGeoLocationService_FindUsersInRadius_InvalidRadius(t *testing.T) {
+	center, err := valueobject.NewCoordinate(-23.550520, -46.633309)
+	require.NoError(t, err)
+
+	results, err := service.NewGeoLocationService(new(mocks.MockPositionRepository)).FindUsersInRadius(context.Background(), center, 0)
+
+	assert.Nil(t, results)
+	assert.ErrorIs(t, err, service.ErrInvalidRadius)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+)
+
+// ErrPositionRejected indica que a posição foi recusada por um validador
+var ErrPositionRejected = errors.New("position rejected by validator")
+
+// PositionValidation representa o resultado da validação de uma posição
+type PositionValidation struct {
+	Rejected bool   // Posição não deve ser salva
+	Flagged  bool   // Posição é salva, mas marcada para revisão
+	Reason   string // Motivo da recusa ou marcação
+}
+
+// PositionValidator é um hook opcional para validar posições com regras do deployment
+// (ex: posição precisa estar dentro do polígono do evento, ou em terra firme)
+type PositionValidator interface {
+	Validate(ctx context.Context, position *entity.Position) (*PositionValidation, error)
+}
+
+// NoopPositionValidator aceita todas as posições (padrão)
+type NoopPositionValidator struct{}
+
+// NewNoopPositionValidator cria o validador padrão
+func NewNoopPositionValidator() PositionValidator {
+	return NoopPositionValidator{}
+}
+
+// Validate aceita a posição sem verificações
+func (NoopPositionValidator) Validate(ctx context.Context, position *entity.Position) (*PositionValidation, error) {
+	return &PositionValidation{}, nil
+}
+
+// CoordinatePredicate decide se uma coordenada é aceitável
+type CoordinatePredicate func(coord *valueobject.Coordinate) bool
+
+// ValidationAction define o que fazer quando o predicado falha
+type ValidationAction int
+
+const (
+	// ValidationReject recusa a posição
+	ValidationReject ValidationAction = iota
+
+	// ValidationFlag salva a posição, mas a marca para revisão
+	ValidationFlag
+)
+
+// PredicatePositionValidator valida posições com um predicado injetado
+type PredicatePositionValidator struct {
+	predicate CoordinatePredicate
+	action    ValidationAction
+	reason    string
+}
+
+// NewPredicatePositionValidator cria um validador baseado em predicado
+func NewPredicatePositionValidator(predicate CoordinatePredicate, action ValidationAction, reason string) *PredicatePositionValidator {
+	return &PredicatePositionValidator{
+		predicate: predicate,
+		action:    action,
+		reason:    reason,
+	}
+}
+
+// Validate aplica o predicado à coordenada da posição
+func (v *PredicatePositionValidator) Validate(ctx context.Context, position *entity.Position) (*PositionValidation, error) {
+	if v.predicate(position.Coordinate()) {
+		return &PositionValidation{}, nil
+	}
+
+	if v.action == ValidationFlag {
+		return &PositionValidation{Flagged: true, Reason: v.reason}, nil
+	}
+	return &PositionValidation{Rejected: true, Reason: v.reason}, nil
+}
@@ -1,6 +1,8 @@
 package valueobject
 
 import (
+	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
@@ -20,12 +22,21 @@ const (
 	MinLongitude  = -180.0
 	MaxLongitude  = 180.0
 	EarthRadiusKm = 6371.0 // Raio da Terra em quilômetros
+
+	// Precisão de geohash (número de caracteres)
+	MinGeohashPrecision = 1
+	MaxGeohashPrecision = 12
 )
 
+// geohashBase32 é o alfabeto base32 padrão do geohash (sem a, i, l, o)
+const geohashBase32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
 // Erros específicos do domínio
 var (
-	ErrInvalidLatitude  = errors.New("latitude must be between -90 and 90 degrees")
-	ErrInvalidLongitude = errors.New("longitude must be between -180 and 180 degrees")
+	ErrInvalidLatitude   = errors.New("latitude must be between -90 and 90 degrees")
+	ErrInvalidLongitude  = errors.New("longitude must be between -180 and 180 degrees")
+	ErrNoCoordinates     = errors.New("at least one coordinate is required")
+	ErrUndefinedCentroid = errors.New("centroid is undefined for antipodal coordinates")
 )
 
 // NewCoordinate cria uma nova coordenada com validação
@@ -99,6 +110,25 @@ func (c *Coordinate) DistanceTo(other *Coordinate) float64 {
 	return EarthRadiusKm * centralAngle * 1000
 }
 
+// BearingTo calcula o rumo inicial (azimute) até outra coordenada
+// Retorna graus em [0, 360): 0 = norte, 90 = leste, 180 = sul, 270 = oeste
+func (c *Coordinate) BearingTo(other *Coordinate) float64 {
+	if other == nil {
+		return 0
+	}
+
+	lat1Rad := degToRad(c.latitude)
+	lat2Rad := degToRad(other.latitude)
+	deltaLng := degToRad(other.longitude - c.longitude)
+
+	y := math.Sin(deltaLng) * math.Cos(lat2Rad)
+	x := math.Cos(lat1Rad)*math.Sin(lat2Rad) -
+		math.Sin(lat1Rad)*math.Cos(lat2Rad)*math.Cos(deltaLng)
+
+	bearing := math.Atan2(y, x) * 180 / math.Pi
+	return math.Mod(bearing+360, 360)
+}
+
 // IsWithinRadius verifica se coordenada está dentro de um raio (em metros)
 func (c *Coordinate) IsWithinRadius(other *Coordinate, radiusMeters float64) bool {
 	if other == nil || radiusMeters < 0 {
@@ -108,11 +138,203 @@ func (c *Coordinate) IsWithinRadius(other *Coordinate, radiusMeters float64) boo
 	return c.DistanceTo(other) <= radiusMeters
 }
 
+// Midpoint retorna o ponto médio do arco de círculo máximo até outra coordenada
+// Para pontos antípodas o meio do arco é indefinido e a própria coordenada é retornada
+func (c *Coordinate) Midpoint(other *Coordinate) *Coordinate {
+	if other == nil {
+		return c
+	}
+
+	centroid, err := CalculateCentroid([]*Coordinate{c, other})
+	if err != nil {
+		return c
+	}
+	return centroid
+}
+
+// CalculateCentroid calcula o centro geográfico (média esférica) das coordenadas
+// Cada ponto vira um vetor unitário 3D; a média desses vetores é projetada de volta na esfera,
+// o que evita os erros da média simples de graus perto do antimeridiano e dos polos
+func CalculateCentroid(coordinates []*Coordinate) (*Coordinate, error) {
+	var x, y, z float64
+	count := 0
+	for _, coordinate := range coordinates {
+		if coordinate == nil {
+			continue
+		}
+		latRad := degToRad(coordinate.latitude)
+		lngRad := degToRad(coordinate.longitude)
+		x += math.Cos(latRad) * math.Cos(lngRad)
+		y += math.Cos(latRad) * math.Sin(lngRad)
+		z += math.Sin(latRad)
+		count++
+	}
+
+	switch count {
+	case 0:
+		return nil, ErrNoCoordinates
+	case 1:
+		// Um único ponto é o próprio centro (sem erro de arredondamento da conversão)
+		for _, coordinate := range coordinates {
+			if coordinate != nil {
+				return &Coordinate{latitude: coordinate.latitude, longitude: coordinate.longitude}, nil
+			}
+		}
+	}
+
+	x /= float64(count)
+	y /= float64(count)
+	z /= float64(count)
+
+	// Vetores que se anulam (ex.: dois pontos antípodas) não têm centro na superfície
+	hyp := math.Sqrt(x*x + y*y)
+	if hyp < 1e-12 && math.Abs(z) < 1e-12 {
+		return nil, ErrUndefinedCentroid
+	}
+
+	lat := math.Atan2(z, hyp) * 180 / math.Pi
+	lng := math.Atan2(y, x) * 180 / math.Pi
+	return NewCoordinate(lat, lng)
+}
+
+// Snap aproxima a coordenada para o centro de uma grade de gridMeters metros
+// Coordenadas na mesma célula retornam o mesmo valor (útil para chaves de cache);
+// o erro máximo é de meia célula em cada eixo. gridMeters <= 0 retorna a própria coordenada
+func (c *Coordinate) Snap(gridMeters float64) *Coordinate {
+	if gridMeters <= 0 {
+		return c
+	}
+
+	metersPerDegree := EarthRadiusKm * 1000 * math.Pi / 180
+
+	latStep := gridMeters / metersPerDegree
+	lat := math.Max(MinLatitude, math.Min(MaxLatitude, math.Round(c.latitude/latStep)*latStep))
+
+	// A célula de longitude usa a latitude já ajustada, para que toda a faixa tenha o mesmo passo
+	lng := c.longitude
+	if cosLat := math.Cos(degToRad(lat)); cosLat > 1e-9 {
+		lngStep := gridMeters / (metersPerDegree * cosLat)
+		lng = math.Max(MinLongitude, math.Min(MaxLongitude, math.Round(c.longitude/lngStep)*lngStep))
+	}
+
+	return &Coordinate{latitude: lat, longitude: lng}
+}
+
+// coordinateJSON é o formato JSON da coordenada; ponteiros distinguem campo ausente de zero
+type coordinateJSON struct {
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+// MarshalJSON serializa como {"latitude": .., "longitude": ..}
+func (c *Coordinate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(coordinateJSON{Latitude: &c.latitude, Longitude: &c.longitude})
+}
+
+// UnmarshalJSON lê {"latitude": .., "longitude": ..} com a mesma validação de NewCoordinate
+// Os dois campos são obrigatórios; null mantém o valor
+func (c *Coordinate) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var raw coordinateJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("invalid coordinate JSON: %w", err)
+	}
+	if raw.Latitude == nil || raw.Longitude == nil {
+		return errors.New("invalid coordinate JSON: latitude and longitude are required")
+	}
+
+	coordinate, err := NewCoordinate(*raw.Latitude, *raw.Longitude)
+	if err != nil {
+		return err
+	}
+	*c = *coordinate
+	return nil
+}
+
+// ToGeoJSON retorna a posição GeoJSON da coordenada: [longitude, latitude] (RFC 7946)
+func (c *Coordinate) ToGeoJSON() [2]float64 {
+	return [2]float64{c.longitude, c.latitude}
+}
+
 // ToWKT converte para formato Well-Known Text (usado no PostGIS)
 func (c *Coordinate) ToWKT() string {
 	return fmt.Sprintf("POINT(%f %f)", c.longitude, c.latitude)
 }
 
+// Layout de um POINT em WKB: ordem dos bytes (1) + tipo de geometria (4) + X (8) + Y (8)
+const (
+	wkbPointSize     = 21
+	wkbLittleEndian  = 1
+	wkbGeometryPoint = 1
+)
+
+// ToWKB converte para formato Well-Known Binary (POINT, little-endian)
+// Mais barato para o PostGIS que WKT (sem parsing de texto) e sem perda de precisão
+func (c *Coordinate) ToWKB() []byte {
+	buf := make([]byte, wkbPointSize)
+	buf[0] = wkbLittleEndian
+	binary.LittleEndian.PutUint32(buf[1:5], wkbGeometryPoint)
+	binary.LittleEndian.PutUint64(buf[5:13], math.Float64bits(c.longitude))
+	binary.LittleEndian.PutUint64(buf[13:21], math.Float64bits(c.latitude))
+	return buf
+}
+
+// Geohash codifica a coordenada no formato geohash padrão
+// Coordenadas próximas compartilham prefixo, útil para chaves de cache e agrupamento
+// A precisão é limitada entre MinGeohashPrecision e MaxGeohashPrecision
+func (c *Coordinate) Geohash(precision int) string {
+	if precision < MinGeohashPrecision {
+		precision = MinGeohashPrecision
+	}
+	if precision > MaxGeohashPrecision {
+		precision = MaxGeohashPrecision
+	}
+
+	latRange := [2]float64{MinLatitude, MaxLatitude}
+	lngRange := [2]float64{MinLongitude, MaxLongitude}
+
+	hash := make([]byte, 0, precision)
+	evenBit := true // Bits pares codificam longitude, ímpares latitude
+	bit := 0
+	index := 0
+
+	for len(hash) < precision {
+		if evenBit {
+			mid := (lngRange[0] + lngRange[1]) / 2
+			if c.longitude >= mid {
+				index = index*2 + 1
+				lngRange[0] = mid
+			} else {
+				index = index * 2
+				lngRange[1] = mid
+			}
+		} else {
+			mid := (latRange[0] + latRange[1]) / 2
+			if c.latitude >= mid {
+				index = index*2 + 1
+				latRange[0] = mid
+			} else {
+				index = index * 2
+				latRange[1] = mid
+			}
+		}
+		evenBit = !evenBit
+
+		// A cada 5 bits, emitir um caractere base32
+		bit++
+		if bit == 5 {
+			hash = append(hash, geohashBase32[index])
+			bit = 0
+			index = 0
+		}
+	}
+
+	return string(hash)
+}
+
 // degToRad converte graus para radianos
 func degToRad(deg float64) float64 {
 	return deg * (math.Pi / 180)
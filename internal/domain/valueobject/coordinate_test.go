@@ -0,0 +1,310 @@
+package valueobject_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+)
+
+// TestCoordinate_Geohash testa valores conhecidos de geohash
+func TestCoordinate_Geohash(t *testing.T) {
+	testCases := []struct {
+		name      string
+		lat       float64
+		lng       float64
+		precision int
+		want      string
+	}{
+		{name: "Jutland (referência clássica)", lat: 57.64911, lng: 10.40744, precision: 11, want: "u4pruydqqvj"},
+		{name: "Espanha", lat: 42.605, lng: -5.603, precision: 5, want: "ezs42"},
+		{name: "São Paulo", lat: -23.550520, lng: -46.633309, precision: 6, want: "6gyf4b"},
+		{name: "origem", lat: 0, lng: 0, precision: 4, want: "s000"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			coord, err := valueobject.NewCoordinate(tc.lat, tc.lng)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.want, coord.Geohash(tc.precision))
+		})
+	}
+}
+
+// TestCoordinate_Geohash_NearbySharePrefix testa que coordenadas próximas compartilham prefixo
+func TestCoordinate_Geohash_NearbySharePrefix(t *testing.T) {
+	a, err := valueobject.NewCoordinate(-23.550520, -46.633309)
+	require.NoError(t, err)
+	b, err := valueobject.NewCoordinate(-23.550600, -46.633400) // ~13m de distância
+	require.NoError(t, err)
+	far, err := valueobject.NewCoordinate(40.712776, -74.005974) // Nova York
+	require.NoError(t, err)
+
+	hashA := a.Geohash(9)
+	hashB := b.Geohash(9)
+
+	assert.Equal(t, hashA[:6], hashB[:6])
+	assert.False(t, strings.HasPrefix(far.Geohash(9), hashA[:2]))
+}
+
+// TestCoordinate_Geohash_PrecisionBounds testa limites de precisão
+func TestCoordinate_Geohash_PrecisionBounds(t *testing.T) {
+	coord, err := valueobject.NewCoordinate(57.64911, 10.40744)
+	require.NoError(t, err)
+
+	assert.Len(t, coord.Geohash(0), valueobject.MinGeohashPrecision)
+	assert.Len(t, coord.Geohash(50), valueobject.MaxGeohashPrecision)
+}
+
+// decodeWKBPoint lê X/Y de um POINT WKB little-endian
+func decodeWKBPoint(t *testing.T, wkb []byte) (x, y float64) {
+	t.Helper()
+	require.Len(t, wkb, 21)
+	require.Equal(t, byte(1), wkb[0], "byte order deve ser little-endian")
+	require.Equal(t, uint32(1), binary.LittleEndian.Uint32(wkb[1:5]), "tipo deve ser POINT")
+	x = math.Float64frombits(binary.LittleEndian.Uint64(wkb[5:13]))
+	y = math.Float64frombits(binary.LittleEndian.Uint64(wkb[13:21]))
+	return x, y
+}
+
+// TestCoordinate_BearingTo testa rumos conhecidos
+func TestCoordinate_BearingTo(t *testing.T) {
+	testCases := []struct {
+		name  string
+		toLat float64
+		toLng float64
+		want  float64
+	}{
+		{name: "norte", toLat: 1, toLng: 0, want: 0},
+		{name: "leste", toLat: 0, toLng: 1, want: 90},
+		{name: "sul", toLat: -1, toLng: 0, want: 180},
+		{name: "oeste", toLat: 0, toLng: -1, want: 270},
+	}
+
+	origin, err := valueobject.NewCoordinate(0, 0)
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			to, err := valueobject.NewCoordinate(tc.toLat, tc.toLng)
+			require.NoError(t, err)
+
+			assert.InDelta(t, tc.want, origin.BearingTo(to), 1e-9)
+		})
+	}
+}
+
+// TestCoordinate_BearingTo_Range testa que o rumo fica em [0, 360) fora do equador
+func TestCoordinate_BearingTo_Range(t *testing.T) {
+	from, err := valueobject.NewCoordinate(-23.550520, -46.633309)
+	require.NoError(t, err)
+	to, err := valueobject.NewCoordinate(-23.550000, -46.634000) // Noroeste
+	require.NoError(t, err)
+
+	bearing := from.BearingTo(to)
+	assert.Greater(t, bearing, 270.0)
+	assert.Less(t, bearing, 360.0)
+}
+
+// TestCoordinate_Snap testa que pontos próximos caem na mesma célula e o erro fica em meia célula
+func TestCoordinate_Snap(t *testing.T) {
+	a, err := valueobject.NewCoordinate(-23.550520, -46.633309)
+	require.NoError(t, err)
+	b, err := valueobject.NewCoordinate(-23.550521, -46.633311) // Jitter de GPS (< 1m)
+	require.NoError(t, err)
+
+	snappedA := a.Snap(10)
+	snappedB := b.Snap(10)
+
+	assert.True(t, snappedA.Equals(snappedB))
+	assert.LessOrEqual(t, a.DistanceTo(snappedA), 10*math.Sqrt2/2)
+	assert.Same(t, a, a.Snap(0))
+}
+
+// TestCoordinate_ToWKB_MatchesWKT testa que WKB e WKT descrevem a mesma geometria
+func TestCoordinate_ToWKB_MatchesWKT(t *testing.T) {
+	testCases := []struct {
+		name string
+		lat  float64
+		lng  float64
+	}{
+		{name: "São Paulo", lat: -23.550520, lng: -46.633309},
+		{name: "Nova York", lat: 40.712776, lng: -74.005974},
+		{name: "origem", lat: 0, lng: 0},
+		{name: "limites", lat: 90, lng: -180},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			coord, err := valueobject.NewCoordinate(tc.lat, tc.lng)
+			require.NoError(t, err)
+
+			var wktX, wktY float64
+			_, err = fmt.Sscanf(coord.ToWKT(), "POINT(%f %f)", &wktX, &wktY)
+			require.NoError(t, err)
+
+			wkbX, wkbY := decodeWKBPoint(t, coord.ToWKB())
+
+			// Mesma ordem de eixos (X = longitude, Y = latitude)
+			assert.Equal(t, wktX, wkbX)
+			assert.Equal(t, wktY, wkbY)
+		})
+	}
+}
+
+// TestCoordinate_ToWKB_FullPrecision testa que WKB preserva casas decimais que o WKT arredonda
+func TestCoordinate_ToWKB_FullPrecision(t *testing.T) {
+	coord, err := valueobject.NewCoordinate(-23.5505201234, -46.6333091234)
+	require.NoError(t, err)
+
+	x, y := decodeWKBPoint(t, coord.ToWKB())
+	assert.Equal(t, coord.Longitude(), x)
+	assert.Equal(t, coord.Latitude(), y)
+}
+
+// Sinks evitam que o compilador elimine as chamadas nos benchmarks
+var (
+	wktSink string
+	wkbSink []byte
+)
+
+// BenchmarkCoordinate_ToWKT mede a serialização em texto usada antes nos inserts
+// O ganho principal do WKB está no PostGIS (sem parsing de texto), não medido aqui
+func BenchmarkCoordinate_ToWKT(b *testing.B) {
+	coord, _ := valueobject.NewCoordinate(-23.550520, -46.633309)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		wktSink = coord.ToWKT()
+	}
+}
+
+// BenchmarkCoordinate_ToWKB mede a serialização binária usada nos inserts
+func BenchmarkCoordinate_ToWKB(b *testing.B) {
+	coord, _ := valueobject.NewCoordinate(-23.550520, -46.633309)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		wkbSink = coord.ToWKB()
+	}
+}
+
+// TestCoordinate_JSONRoundTrip testa a serialização com latitude/longitude e a volta
+func TestCoordinate_JSONRoundTrip(t *testing.T) {
+	coord, err := valueobject.NewCoordinate(-23.550520, -46.633309)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(struct {
+		Center *valueobject.Coordinate `json:"center"`
+	}{Center: coord})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"center":{"latitude":-23.55052,"longitude":-46.633309}}`, string(data))
+
+	var decoded struct {
+		Center *valueobject.Coordinate `json:"center"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.NotNil(t, decoded.Center)
+	assert.True(t, decoded.Center.Equals(coord))
+}
+
+// TestCoordinate_UnmarshalJSON_Invalid testa campos ausentes e coordenadas fora dos limites
+func TestCoordinate_UnmarshalJSON_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"missing longitude": `{"latitude":10}`,
+		"latitude too high": `{"latitude":91,"longitude":0}`,
+		"not an object":     `[-46.6,-23.5]`,
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			var coord valueobject.Coordinate
+			assert.Error(t, json.Unmarshal([]byte(input), &coord))
+		})
+	}
+
+	// Zero é um valor válido quando informado explicitamente
+	var origin valueobject.Coordinate
+	require.NoError(t, json.Unmarshal([]byte(`{"latitude":0,"longitude":0}`), &origin))
+}
+
+// TestCoordinate_ToGeoJSON testa a ordem [longitude, latitude] do GeoJSON
+func TestCoordinate_ToGeoJSON(t *testing.T) {
+	coord, err := valueobject.NewCoordinate(-23.550520, -46.633309)
+	require.NoError(t, err)
+
+	assert.Equal(t, [2]float64{-46.633309, -23.550520}, coord.ToGeoJSON())
+}
+
+// TestCalculateCentroid testa a média esférica, inclusive através do antimeridiano
+func TestCalculateCentroid(t *testing.T) {
+	mustCoordinate := func(lat, lng float64) *valueobject.Coordinate {
+		coord, err := valueobject.NewCoordinate(lat, lng)
+		require.NoError(t, err)
+		return coord
+	}
+
+	t.Run("equador", func(t *testing.T) {
+		centroid, err := valueobject.CalculateCentroid([]*valueobject.Coordinate{mustCoordinate(0, 0), mustCoordinate(0, 90)})
+		require.NoError(t, err)
+		assert.InDelta(t, 0, centroid.Latitude(), 1e-9)
+		assert.InDelta(t, 45, centroid.Longitude(), 1e-9)
+	})
+
+	t.Run("antimeridiano", func(t *testing.T) {
+		// A média simples de graus daria longitude 0, do outro lado do planeta
+		centroid, err := valueobject.CalculateCentroid([]*valueobject.Coordinate{mustCoordinate(10, 179), mustCoordinate(10, -179)})
+		require.NoError(t, err)
+		assert.InDelta(t, 180, math.Abs(centroid.Longitude()), 1e-9)
+		assert.InDelta(t, 10, centroid.Latitude(), 0.01)
+	})
+
+	t.Run("equidistante dos membros", func(t *testing.T) {
+		coords := []*valueobject.Coordinate{
+			mustCoordinate(-23.55, -46.63),
+			mustCoordinate(-23.56, -46.65),
+			mustCoordinate(-23.54, -46.64),
+		}
+		centroid, err := valueobject.CalculateCentroid(coords)
+		require.NoError(t, err)
+		assert.InDelta(t, -23.55, centroid.Latitude(), 1e-4)
+		assert.InDelta(t, -46.64, centroid.Longitude(), 1e-4)
+	})
+
+	t.Run("um ponto", func(t *testing.T) {
+		coord := mustCoordinate(-23.550520, -46.633309)
+		centroid, err := valueobject.CalculateCentroid([]*valueobject.Coordinate{coord})
+		require.NoError(t, err)
+		assert.True(t, coord.Equals(centroid))
+		assert.Equal(t, coord.Latitude(), centroid.Latitude())
+	})
+
+	t.Run("vazio", func(t *testing.T) {
+		_, err := valueobject.CalculateCentroid(nil)
+		assert.ErrorIs(t, err, valueobject.ErrNoCoordinates)
+	})
+
+	t.Run("antípodas", func(t *testing.T) {
+		_, err := valueobject.CalculateCentroid([]*valueobject.Coordinate{mustCoordinate(0, 0), mustCoordinate(0, 180)})
+		assert.ErrorIs(t, err, valueobject.ErrUndefinedCentroid)
+	})
+}
+
+// TestCoordinate_Midpoint testa o ponto médio e a equidistância das extremidades
+func TestCoordinate_Midpoint(t *testing.T) {
+	a, err := valueobject.NewCoordinate(-23.550520, -46.633309)
+	require.NoError(t, err)
+	b, err := valueobject.NewCoordinate(-22.906847, -43.172896)
+	require.NoError(t, err)
+
+	mid := a.Midpoint(b)
+
+	assert.InDelta(t, a.DistanceTo(mid), b.DistanceTo(mid), 1e-6)
+	assert.InDelta(t, a.DistanceTo(b)/2, a.DistanceTo(mid), 1e-6)
+	assert.Same(t, a, a.Midpoint(nil))
+}
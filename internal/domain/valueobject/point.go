@@ -22,8 +22,9 @@ const (
 
 // Erros específicos
 var (
-	ErrInvalidSectorX = errors.New("sector X coordinate out of bounds")
-	ErrInvalidSectorY = errors.New("sector Y coordinate out of bounds")
+	ErrInvalidSectorX  = errors.New("sector X coordinate out of bounds")
+	ErrInvalidSectorY  = errors.New("sector Y coordinate out of bounds")
+	ErrInvalidSectorID = errors.New("invalid sector ID")
 )
 
 // NewPoint cria um novo ponto com validação
@@ -78,11 +79,21 @@ func (p *Point) DistanceTo(other *Point) float64 {
 
 // GetNeighboringSectors retorna pontos dos setores vizinhos (8 direções + próprio)
 func (p *Point) GetNeighboringSectors() []*Point {
-	neighbors := make([]*Point, 0, 9)
+	return p.GetSectorsInRings(1)
+}
+
+// GetSectorsInRings retorna o próprio setor e N anéis de setores ao redor
+// Resultado é um quadrado de (2N+1)x(2N+1) setores (menos os que estão fora dos limites)
+func (p *Point) GetSectorsInRings(rings int) []*Point {
+	if rings < 0 {
+		rings = 0
+	}
 
-	// Incluir o próprio setor e os 8 vizinhos
-	for dx := -1; dx <= 1; dx++ {
-		for dy := -1; dy <= 1; dy++ {
+	side := 2*rings + 1
+	sectors := make([]*Point, 0, side*side)
+
+	for dx := -rings; dx <= rings; dx++ {
+		for dy := -rings; dy <= rings; dy++ {
 			newX := p.x + dx
 			newY := p.y + dy
 
@@ -92,13 +103,13 @@ func (p *Point) GetNeighboringSectors() []*Point {
 
 				neighbor, _ := NewPoint(newX, newY) // Não deveria dar erro aqui
 				if neighbor != nil {
-					neighbors = append(neighbors, neighbor)
+					sectors = append(sectors, neighbor)
 				}
 			}
 		}
 	}
 
-	return neighbors
+	return sectors
 }
 
 // GetSectorsInRadius retorna todos os setores dentro de um raio específico
@@ -1,6 +1,7 @@
 package valueobject
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 )
@@ -21,6 +22,32 @@ const (
 	MetersPerDegreeLngAtEquator = 111320.0
 )
 
+// SectorOrigin é a coordenada de referência que corresponde ao setor (0, 0)
+type SectorOrigin struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// sectorOrigin padrão: linha do equador e meridiano de Greenwich
+// Alterada apenas na inicialização da aplicação, antes de qualquer conversão
+var sectorOrigin SectorOrigin
+
+// SetSectorOrigin define a origem da grade de setores (deve ser chamada na inicialização)
+// Setores já gravados com outra origem precisam ser recalculados
+func SetSectorOrigin(latitude, longitude float64) error {
+	if _, err := NewCoordinate(latitude, longitude); err != nil {
+		return fmt.Errorf("invalid sector origin: %w", err)
+	}
+
+	sectorOrigin = SectorOrigin{Latitude: latitude, Longitude: longitude}
+	return nil
+}
+
+// CurrentSectorOrigin retorna a origem atual da grade de setores
+func CurrentSectorOrigin() SectorOrigin {
+	return sectorOrigin
+}
+
 // NewSector cria um novo setor
 func NewSector(x, y int) (*Sector, error) {
 	point, err := NewPoint(x, y)
@@ -31,6 +58,12 @@ func NewSector(x, y int) (*Sector, error) {
 	return &Sector{point: point}, nil
 }
 
+// metersPerDegreeLng retorna quantos metros um grau de longitude mede na latitude informada
+// Conversão direta, inversa e bounds usam o mesmo fator, senão o centro não volta ao setor de origem
+func metersPerDegreeLng(latitude float64) float64 {
+	return MetersPerDegreeLngAtEquator * math.Cos(degToRad(latitude))
+}
+
 // NewSectorFromCoordinate converte coordenada geográfica para setor
 // Esta é uma função crucial que mapeia o mundo real para nosso sistema de setores
 func NewSectorFromCoordinate(coord *Coordinate) (*Sector, error) {
@@ -38,18 +71,16 @@ func NewSectorFromCoordinate(coord *Coordinate) (*Sector, error) {
 		return nil, fmt.Errorf("coordinate cannot be nil")
 	}
 
-	// Para simplificar, vamos usar uma origem fixa (pode ser configurável)
-	// Origem: (0,0) será equivalente a lat=0, lng=0 (linha do equador, meridiano de Greenwich)
+	// O setor (0,0) fica na origem configurada (padrão: lat=0, lng=0)
 
 	// Converter latitude para coordenada Y do setor
-	// Positivo = Norte, Negativo = Sul
-	latMeters := coord.Latitude() * MetersPerDegreeLat
+	// Positivo = Norte da origem, Negativo = Sul
+	latMeters := (coord.Latitude() - sectorOrigin.Latitude) * MetersPerDegreeLat
 	sectorY := int(math.Round(latMeters / SectorSizeMeters))
 
 	// Converter longitude para coordenada X do setor
 	// Ajustar por latitude para compensar convergência dos meridianos
-	lngMetersPerDegree := MetersPerDegreeLngAtEquator * math.Cos(degToRad(coord.Latitude()))
-	lngMeters := coord.Longitude() * lngMetersPerDegree
+	lngMeters := longitudeDelta(coord.Longitude(), sectorOrigin.Longitude) * metersPerDegreeLng(coord.Latitude())
 	sectorX := int(math.Round(lngMeters / SectorSizeMeters))
 
 	return NewSector(sectorX, sectorY)
@@ -71,14 +102,15 @@ func (s *Sector) Y() int {
 }
 
 // ToCoordinate converte setor de volta para coordenada geográfica (centro do setor)
+// Inverso de NewSectorFromCoordinate: a longitude usa o mesmo ajuste por latitude
 func (s *Sector) ToCoordinate() (*Coordinate, error) {
-	// Converter X do setor para longitude
-	lngMeters := float64(s.point.X()) * SectorSizeMeters
-	longitude := lngMeters / MetersPerDegreeLngAtEquator
-
 	// Converter Y do setor para latitude
 	latMeters := float64(s.point.Y()) * SectorSizeMeters
-	latitude := latMeters / MetersPerDegreeLat
+	latitude := sectorOrigin.Latitude + latMeters/MetersPerDegreeLat
+
+	// Converter X do setor para longitude
+	lngMeters := float64(s.point.X()) * SectorSizeMeters
+	longitude := longitudeDelta(sectorOrigin.Longitude+lngMeters/metersPerDegreeLng(latitude), 0)
 
 	return NewCoordinate(latitude, longitude)
 }
@@ -92,7 +124,7 @@ func (s *Sector) GetBounds() (topLeft, topRight, bottomLeft, bottomRight *Coordi
 
 	// Calcular offset de meio setor
 	halfSectorLat := (SectorSizeMeters / 2) / MetersPerDegreeLat
-	halfSectorLng := (SectorSizeMeters / 2) / (MetersPerDegreeLngAtEquator * math.Cos(degToRad(center.Latitude())))
+	halfSectorLng := (SectorSizeMeters / 2) / metersPerDegreeLng(center.Latitude())
 
 	topLeft, _ = NewCoordinate(center.Latitude()+halfSectorLat, center.Longitude()-halfSectorLng)
 	topRight, _ = NewCoordinate(center.Latitude()+halfSectorLat, center.Longitude()+halfSectorLng)
@@ -102,6 +134,40 @@ func (s *Sector) GetBounds() (topLeft, topRight, bottomLeft, bottomRight *Coordi
 	return topLeft, topRight, bottomLeft, bottomRight, nil
 }
 
+// sectorJSON é o formato JSON do setor
+type sectorJSON struct {
+	ID     string            `json:"id"`
+	X      int               `json:"x"`
+	Y      int               `json:"y"`
+	Bounds *sectorBoundsJSON `json:"bounds,omitempty"`
+}
+
+// sectorBoundsJSON são os limites geográficos do setor (cantos de GetBounds)
+type sectorBoundsJSON struct {
+	MinLatitude  float64 `json:"min_latitude"`
+	MaxLatitude  float64 `json:"max_latitude"`
+	MinLongitude float64 `json:"min_longitude"`
+	MaxLongitude float64 `json:"max_longitude"`
+}
+
+// MarshalJSON serializa como {"id": "sector_x_y", "x": .., "y": .., "bounds": {...}}
+// Setores cujos limites caem fora das coordenadas válidas são serializados sem bounds
+func (s *Sector) MarshalJSON() ([]byte, error) {
+	out := sectorJSON{ID: s.ID(), X: s.X(), Y: s.Y()}
+
+	topLeft, _, _, bottomRight, err := s.GetBounds()
+	if err == nil && topLeft != nil && bottomRight != nil {
+		out.Bounds = &sectorBoundsJSON{
+			MinLatitude:  bottomRight.Latitude(),
+			MaxLatitude:  topLeft.Latitude(),
+			MinLongitude: topLeft.Longitude(),
+			MaxLongitude: bottomRight.Longitude(),
+		}
+	}
+
+	return json.Marshal(out)
+}
+
 // String implementa fmt.Stringer
 func (s *Sector) String() string {
 	return fmt.Sprintf("Sector(%d, %d)", s.point.X(), s.point.Y())
@@ -128,7 +194,50 @@ func (s *Sector) GetNeighboringSectors() ([]*Sector, error) {
 	return sectors, nil
 }
 
+// GetSectorsInRings retorna o setor e N anéis de setores ao redor
+func (s *Sector) GetSectorsInRings(rings int) []*Sector {
+	points := s.point.GetSectorsInRings(rings)
+	sectors := make([]*Sector, 0, len(points))
+
+	for _, point := range points {
+		sectors = append(sectors, &Sector{point: point})
+	}
+
+	return sectors
+}
+
 // ID retorna identificador único do setor
 func (s *Sector) ID() string {
 	return s.point.ToSectorID()
 }
+
+// ParseSectorID converte um ID no formato "sector_x_y" (ver Point.ToSectorID) em setor
+// Aceita apenas a forma canônica gerada por ID(): sem sinal "+", zeros à esquerda ou espaços
+// Todos os erros envolvem ErrInvalidSectorID (e ErrInvalidSectorX/Y para coordenadas fora do limite)
+func ParseSectorID(id string) (*Sector, error) {
+	var x, y int
+	if _, err := fmt.Sscanf(id, "sector_%d_%d", &x, &y); err != nil {
+		return nil, fmt.Errorf("%w %q: expected format sector_x_y", ErrInvalidSectorID, id)
+	}
+
+	sector, err := NewSector(x, y)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrInvalidSectorID, id, err)
+	}
+
+	// Rejeitar sufixos extras e formas não canônicas (ex: "sector_1_2_3", "sector_01_2")
+	if sector.ID() != id {
+		return nil, fmt.Errorf("%w %q: expected format sector_x_y", ErrInvalidSectorID, id)
+	}
+
+	return sector, nil
+}
+
+// longitudeDelta retorna lng - origin normalizado para [-180, 180], tratando o antimeridiano
+func longitudeDelta(lng, origin float64) float64 {
+	delta := math.Mod(lng-origin+180, 360)
+	if delta < 0 {
+		delta += 360
+	}
+	return delta - 180
+}
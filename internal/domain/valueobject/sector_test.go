@@ -0,0 +1,192 @@
+package valueobject_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+)
+
+// TestParseSectorID testa a conversão de IDs no formato sector_x_y
+func TestParseSectorID(t *testing.T) {
+	tests := []struct {
+		id   string
+		x, y int
+	}{
+		{id: "sector_0_0", x: 0, y: 0},
+		{id: "sector_12_34", x: 12, y: 34},
+		{id: "sector_-518_2616", x: -518, y: 2616},
+		{id: "sector_7_-3", x: 7, y: -3},
+		{id: "sector_-100000_-100000", x: -valueobject.MaxSectorCoord, y: -valueobject.MaxSectorCoord},
+	}
+
+	for _, tt := range tests {
+		sector, err := valueobject.ParseSectorID(tt.id)
+		require.NoError(t, err, tt.id)
+		assert.Equal(t, tt.x, sector.X(), tt.id)
+		assert.Equal(t, tt.y, sector.Y(), tt.id)
+		assert.Equal(t, tt.id, sector.ID(), "round trip")
+	}
+}
+
+// TestParseSectorID_Malformed testa formatos inválidos e coordenadas fora do limite
+func TestParseSectorID_Malformed(t *testing.T) {
+	malformed := []string{
+		"", "abc", "sector", "sector_1", "sector_1_", "sector__1", "sector_1_2_3", "sector_a_b", "1_2",
+		"SECTOR_1_2", "sector_+1_2", "sector_01_2", "sector_ 1_2", " sector_1_2", "sector_1_2 ", "sector_1.5_2",
+	}
+	for _, id := range malformed {
+		_, err := valueobject.ParseSectorID(id)
+		assert.ErrorIs(t, err, valueobject.ErrInvalidSectorID, id)
+	}
+
+	_, err := valueobject.ParseSectorID("sector_100001_0")
+	assert.ErrorIs(t, err, valueobject.ErrInvalidSectorID)
+	assert.ErrorIs(t, err, valueobject.ErrInvalidSectorX)
+
+	_, err = valueobject.ParseSectorID("sector_0_-100001")
+	assert.ErrorIs(t, err, valueobject.ErrInvalidSectorY)
+}
+
+// useSectorOrigin define a origem da grade durante o teste e restaura a anterior ao final
+func useSectorOrigin(t *testing.T, latitude, longitude float64) {
+	t.Helper()
+	previous := valueobject.CurrentSectorOrigin()
+	require.NoError(t, valueobject.SetSectorOrigin(latitude, longitude))
+	t.Cleanup(func() {
+		require.NoError(t, valueobject.SetSectorOrigin(previous.Latitude, previous.Longitude))
+	})
+}
+
+// assertRoundTripWithinSector testa que coordenada -> setor -> coordenada volta a menos de um setor de distância
+func assertRoundTripWithinSector(t *testing.T, lat, lng float64) *valueobject.Sector {
+	t.Helper()
+
+	coord, err := valueobject.NewCoordinate(lat, lng)
+	require.NoError(t, err)
+
+	sector, err := valueobject.NewSectorFromCoordinate(coord)
+	require.NoError(t, err)
+
+	center, err := sector.ToCoordinate()
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, coord.DistanceTo(center), float64(valueobject.SectorSizeMeters),
+		"lat=%f lng=%f sector=%s", lat, lng, sector.ID())
+	return sector
+}
+
+// TestSector_RoundTripDefaultOrigin testa o round trip com a origem padrão (equador/Greenwich)
+func TestSector_RoundTripDefaultOrigin(t *testing.T) {
+	assert.Equal(t, valueobject.SectorOrigin{}, valueobject.CurrentSectorOrigin())
+
+	for _, c := range [][2]float64{{0, 0}, {-23.550520, -46.633309}, {40.712776, -74.005974}, {-22.906847, -43.172896}} {
+		assertRoundTripWithinSector(t, c[0], c[1])
+	}
+}
+
+// TestSector_RoundTripCustomOrigin testa que uma origem local gera setores pequenos e o round trip continua preciso
+func TestSector_RoundTripCustomOrigin(t *testing.T) {
+	useSectorOrigin(t, -23.550520, -46.633309)
+
+	origin := assertRoundTripWithinSector(t, -23.550520, -46.633309)
+	assert.Equal(t, 0, origin.X())
+	assert.Equal(t, 0, origin.Y())
+
+	// ~1,1km ao norte e ~1km a leste da origem: setores na casa da dezena
+	nearby := assertRoundTripWithinSector(t, -23.540520, -46.623500)
+	assert.InDelta(t, 11, nearby.Y(), 1)
+	assert.InDelta(t, 10, nearby.X(), 1)
+
+	for _, c := range [][2]float64{{-23.6, -46.7}, {-23.5, -46.5}, {-22.906847, -43.172896}} {
+		assertRoundTripWithinSector(t, c[0], c[1])
+	}
+}
+
+// TestSector_RoundTripAcrossAntimeridian testa uma origem perto do antimeridiano
+func TestSector_RoundTripAcrossAntimeridian(t *testing.T) {
+	useSectorOrigin(t, -17.7, 179.99)
+
+	sector := assertRoundTripWithinSector(t, -17.7, -179.99)
+	assert.InDelta(t, 21, sector.X(), 1) // ~2,1km a leste, não o outro lado do mundo
+}
+
+// TestSetSectorOrigin_Invalid testa que uma origem fora dos limites é rejeitada
+func TestSetSectorOrigin_Invalid(t *testing.T) {
+	assert.Error(t, valueobject.SetSectorOrigin(91, 0))
+	assert.Equal(t, valueobject.SectorOrigin{}, valueobject.CurrentSectorOrigin())
+}
+
+// TestSector_ToCoordinateLandsInOriginalSector testa que o centro recuperado cai no mesmo setor em várias latitudes
+// A longitude só volta ao setor de origem se o inverso aplicar o mesmo fator cos(latitude) da conversão direta
+func TestSector_ToCoordinateLandsInOriginalSector(t *testing.T) {
+	for _, lat := range []float64{0, 23, -23, 60, -60} {
+		for _, lng := range []float64{-80.25, -46.633309, 0.0007, 12.34, 85.5} {
+			coord, err := valueobject.NewCoordinate(lat, lng)
+			require.NoError(t, err)
+
+			sector, err := valueobject.NewSectorFromCoordinate(coord)
+			require.NoError(t, err)
+
+			center, err := sector.ToCoordinate()
+			require.NoError(t, err)
+
+			recovered, err := valueobject.NewSectorFromCoordinate(center)
+			require.NoError(t, err)
+			assert.True(t, sector.Equals(recovered), "lat=%v lng=%v: %s != %s", lat, lng, sector.ID(), recovered.ID())
+
+			// Centro a no máximo meia diagonal (~71m) da coordenada original
+			assert.LessOrEqual(t, coord.DistanceTo(center), valueobject.SectorSizeMeters*0.71, "lat=%v lng=%v", lat, lng)
+		}
+	}
+}
+
+// TestSector_MarshalJSON testa ID, coordenadas do grid e limites no JSON
+func TestSector_MarshalJSON(t *testing.T) {
+	sector, err := valueobject.NewSector(3, -2)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(struct {
+		Sector *valueobject.Sector `json:"sector"`
+	}{Sector: sector})
+	require.NoError(t, err)
+
+	var decoded struct {
+		Sector struct {
+			ID     string             `json:"id"`
+			X      int                `json:"x"`
+			Y      int                `json:"y"`
+			Bounds map[string]float64 `json:"bounds"`
+		} `json:"sector"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	assert.Equal(t, "sector_3_-2", decoded.Sector.ID)
+	assert.Equal(t, 3, decoded.Sector.X)
+	assert.Equal(t, -2, decoded.Sector.Y)
+
+	center, err := sector.ToCoordinate()
+	require.NoError(t, err)
+	bounds := decoded.Sector.Bounds
+	require.Len(t, bounds, 4)
+	assert.Less(t, bounds["min_latitude"], center.Latitude())
+	assert.Greater(t, bounds["max_latitude"], center.Latitude())
+	assert.Less(t, bounds["min_longitude"], center.Longitude())
+	assert.Greater(t, bounds["max_longitude"], center.Longitude())
+	assert.InDelta(t, float64(valueobject.SectorSizeMeters)/valueobject.MetersPerDegreeLat, bounds["max_latitude"]-bounds["min_latitude"], 1e-9)
+}
+
+// TestSector_MarshalJSON_OmitsInvalidBounds testa que setores fora das coordenadas válidas não falham
+func TestSector_MarshalJSON_OmitsInvalidBounds(t *testing.T) {
+	// Com a origem em 80°N, o setor y=100000 fica além do polo
+	useSectorOrigin(t, 80, 0)
+	sector, err := valueobject.NewSector(0, valueobject.MaxSectorCoord)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(sector)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"sector_0_100000","x":0,"y":100000}`, string(data))
+}
@@ -1,6 +1,7 @@
 package valueobject
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -68,7 +69,31 @@ func (ts *Timestamp) Unix() int64 {
 
 // String implementa fmt.Stringer
 func (ts *Timestamp) String() string {
-	return ts.time.Format(TimestampFormat)
+	return ts.time.UTC().Format(TimestampFormat)
+}
+
+// MarshalJSON serializa como string RFC3339 em UTC (ex: "2024-01-15T10:30:00Z")
+func (ts *Timestamp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ts.String())
+}
+
+// UnmarshalJSON lê uma string RFC3339 (qualquer offset, convertida para UTC); null mantém o valor
+func (ts *Timestamp) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("%w: timestamp must be an RFC3339 string", ErrInvalidTime)
+	}
+
+	parsed, err := NewTimestampFromString(value)
+	if err != nil {
+		return err
+	}
+	*ts = *parsed
+	return nil
 }
 
 // Equals compara dois timestamps
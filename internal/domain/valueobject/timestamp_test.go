@@ -0,0 +1,55 @@
+package valueobject_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+)
+
+// TestTimestamp_MarshalJSON testa a serialização RFC3339 em UTC
+func TestTimestamp_MarshalJSON(t *testing.T) {
+	saoPaulo := time.FixedZone("BRT", -3*60*60)
+	ts := valueobject.NewTimestamp(time.Date(2024, 1, 15, 7, 30, 0, 0, saoPaulo))
+
+	data, err := json.Marshal(ts)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `"2024-01-15T10:30:00Z"`, string(data))
+}
+
+// TestTimestamp_JSONRoundTrip testa ida e volta dentro de uma struct, inclusive com ponteiro nil
+func TestTimestamp_JSONRoundTrip(t *testing.T) {
+	type payload struct {
+		LastActivity *valueobject.Timestamp `json:"last_activity"`
+		Missing      *valueobject.Timestamp `json:"missing"`
+	}
+
+	original := payload{LastActivity: valueobject.NewTimestamp(time.Date(2024, 2, 20, 8, 0, 0, 0, time.UTC))}
+
+	data, err := json.Marshal(original)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"last_activity":"2024-02-20T08:00:00Z","missing":null}`, string(data))
+
+	var decoded payload
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.NotNil(t, decoded.LastActivity)
+	assert.True(t, decoded.LastActivity.Equals(original.LastActivity))
+	assert.Nil(t, decoded.Missing)
+}
+
+// TestTimestamp_UnmarshalJSON testa offsets convertidos para UTC e valores inválidos
+func TestTimestamp_UnmarshalJSON(t *testing.T) {
+	var ts valueobject.Timestamp
+	require.NoError(t, json.Unmarshal([]byte(`"2024-01-15T07:30:00-03:00"`), &ts))
+	assert.Equal(t, "2024-01-15T10:30:00Z", ts.String())
+	assert.Equal(t, time.UTC, ts.Time().Location())
+
+	for _, invalid := range []string{`"15/01/2024"`, `1705314600`, `{}`} {
+		err := json.Unmarshal([]byte(invalid), &ts)
+		assert.ErrorIs(t, err, valueobject.ErrInvalidTime, invalid)
+	}
+}
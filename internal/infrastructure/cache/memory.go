@@ -0,0 +1,259 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// Verificar se Memory implementa as interfaces
+var (
+	_ usecase.CacheInterface = (*Memory)(nil)
+	_ usecase.CacheInspector = (*Memory)(nil)
+)
+
+// DefaultMemoryMaxEntries é o limite de chaves usado quando a config não define um valor válido
+const DefaultMemoryMaxEntries = 10000
+
+// memoryEntry é um item armazenado no cache em memória
+type memoryEntry struct {
+	key       string
+	value     []byte    // JSON serializado, como no Redis
+	expiresAt time.Time // Zero indica chave sem expiração
+}
+
+// expired indica se a entrada já passou do TTL
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// Memory é um cache LRU com TTL em memória (testes e desenvolvimento local)
+// Os valores são serializados em JSON para manter a mesma semântica do Redis
+type Memory struct {
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	lru        *list.List // Frente = mais recente
+	maxEntries int
+	logger     logger.Logger
+
+	// now permite controlar o relógio nos testes
+	now func() time.Time
+}
+
+// NewMemory cria um cache em memória com o limite de chaves da config
+func NewMemory(cfg *config.Config, logger logger.Logger) *Memory {
+	maxEntries := cfg.Cache.MemoryMaxEntries
+	if maxEntries <= 0 {
+		maxEntries = DefaultMemoryMaxEntries
+	}
+
+	logger.Info("In-memory cache initialized",
+		"max_entries", maxEntries,
+	)
+
+	return &Memory{
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		maxEntries: maxEntries,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Set armazena um valor no cache; expiration <= 0 mantém a chave sem expiração
+func (m *Memory) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := &memoryEntry{key: key, value: data}
+	if expiration > 0 {
+		entry.expiresAt = m.now().Add(expiration)
+	}
+
+	if elem, ok := m.entries[key]; ok {
+		elem.Value = entry
+		m.lru.MoveToFront(elem)
+	} else {
+		m.entries[key] = m.lru.PushFront(entry)
+		m.evictOverflow()
+	}
+
+	m.logger.Debug("Cache set successfully",
+		"key", key,
+		"expiration", expiration.String(),
+	)
+
+	return nil
+}
+
+// Get recupera um valor do cache
+func (m *Memory) Get(ctx context.Context, key string, dest interface{}) error {
+	entry, ok := m.lookup(key)
+	if !ok {
+		return fmt.Errorf("cache miss: key not found")
+	}
+
+	if err := json.Unmarshal(entry.value, dest); err != nil {
+		return fmt.Errorf("failed to unmarshal value: %w", err)
+	}
+
+	m.logger.Debug("Cache hit",
+		"key", key,
+	)
+
+	return nil
+}
+
+// Delete remove um valor do cache
+func (m *Memory) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.entries[key]; ok {
+		m.remove(elem)
+	}
+
+	m.logger.Debug("Cache deleted",
+		"key", key,
+	)
+
+	return nil
+}
+
+// Exists verifica se uma chave existe no cache
+func (m *Memory) Exists(ctx context.Context, key string) (bool, error) {
+	_, ok := m.lookup(key)
+	return ok, nil
+}
+
+// Inspect retorna o valor bruto e o TTL de uma chave (uso em diagnóstico)
+func (m *Memory) Inspect(ctx context.Context, key string) (*usecase.CacheEntry, error) {
+	result := &usecase.CacheEntry{Key: key}
+
+	entry, ok := m.lookup(key)
+	if !ok {
+		return result, nil
+	}
+
+	result.Found = true
+	result.Value = string(entry.value)
+	result.TTL = -1 // Sem expiração
+	if !entry.expiresAt.IsZero() {
+		result.TTL = entry.expiresAt.Sub(m.now())
+	}
+
+	return result, nil
+}
+
+// Len retorna o número de chaves armazenadas (inclusive as expiradas ainda não removidas)
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lru.Len()
+}
+
+// CacheUserPosition armazena a posição atual de um usuário no cache
+func (m *Memory) CacheUserPosition(ctx context.Context, userID string, position interface{}) error {
+	key := fmt.Sprintf("user:position:%s", userID)
+	return m.Set(ctx, key, position, 5*time.Minute)
+}
+
+// GetCachedUserPosition recupera a posição atual de um usuário do cache
+func (m *Memory) GetCachedUserPosition(ctx context.Context, userID string, dest interface{}) error {
+	key := fmt.Sprintf("user:position:%s", userID)
+	return m.Get(ctx, key, dest)
+}
+
+// CacheNearbyUsers armazena resultado de busca por proximidade
+func (m *Memory) CacheNearbyUsers(ctx context.Context, lat, lng, radius float64, users interface{}) error {
+	key := fmt.Sprintf("nearby:%.6f:%.6f:%.0f", lat, lng, radius)
+	return m.Set(ctx, key, users, 2*time.Minute)
+}
+
+// GetCachedNearbyUsers recupera resultado de busca por proximidade do cache
+func (m *Memory) GetCachedNearbyUsers(ctx context.Context, lat, lng, radius float64, dest interface{}) error {
+	key := fmt.Sprintf("nearby:%.6f:%.6f:%.0f", lat, lng, radius)
+	return m.Get(ctx, key, dest)
+}
+
+// CacheUserHistory armazena histórico de posições de um usuário no cache
+func (m *Memory) CacheUserHistory(ctx context.Context, userID string, limit int, history interface{}) error {
+	key := fmt.Sprintf("history:%s:%d", userID, limit)
+	return m.Set(ctx, key, history, 1*time.Minute)
+}
+
+// GetCachedUserHistory recupera histórico de posições de um usuário do cache
+func (m *Memory) GetCachedUserHistory(ctx context.Context, userID string, limit int, dest interface{}) error {
+	key := fmt.Sprintf("history:%s:%d", userID, limit)
+	return m.Get(ctx, key, dest)
+}
+
+// InvalidateUserCaches invalida a posição e todos os históricos cacheados de um usuário
+func (m *Memory) InvalidateUserCaches(ctx context.Context, userID string) error {
+	positionKey := fmt.Sprintf("user:position:%s", userID)
+	historyPrefix := fmt.Sprintf("history:%s:", userID)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	removed := 0
+	for key, elem := range m.entries {
+		if key == positionKey || strings.HasPrefix(key, historyPrefix) {
+			m.remove(elem)
+			removed++
+		}
+	}
+
+	m.logger.Debug("User caches invalidated successfully",
+		"user_id", userID,
+		"keys", removed,
+	)
+
+	return nil
+}
+
+// lookup retorna uma entrada válida e a marca como usada; entradas expiradas são removidas
+func (m *Memory) lookup(key string) (*memoryEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*memoryEntry)
+	if entry.expired(m.now()) {
+		m.remove(elem)
+		return nil, false
+	}
+
+	m.lru.MoveToFront(elem)
+	return entry, true
+}
+
+// evictOverflow remove as entradas menos usadas além do limite (exige m.mu)
+func (m *Memory) evictOverflow() {
+	for m.lru.Len() > m.maxEntries {
+		m.remove(m.lru.Back())
+	}
+}
+
+// remove retira a entrada do índice e da lista (exige m.mu)
+func (m *Memory) remove(elem *list.Element) {
+	m.lru.Remove(elem)
+	delete(m.entries, elem.Value.(*memoryEntry).key)
+}
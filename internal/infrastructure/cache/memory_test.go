@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+)
+
+// nopLogger descarta todos os logs durante os testes
+type nopLogger struct{}
+
+func (nopLogger) Info(msg string, fields ...interface{})  {}
+func (nopLogger) Error(msg string, fields ...interface{}) {}
+func (nopLogger) Warn(msg string, fields ...interface{})  {}
+func (nopLogger) Fatal(msg string, fields ...interface{}) {}
+func (nopLogger) Debug(msg string, fields ...interface{}) {}
+func (nopLogger) Sync() error                             { return nil }
+
+// newTestMemory cria um cache em memória com relógio controlável
+func newTestMemory(maxEntries int) (*Memory, *time.Time) {
+	cfg := &config.Config{Cache: config.CacheConfig{MemoryMaxEntries: maxEntries}}
+	m := NewMemory(cfg, nopLogger{})
+
+	now := time.Now()
+	m.now = func() time.Time { return now }
+	return m, &now
+}
+
+// TestMemory_SetGet testa o round trip JSON de um valor
+func TestMemory_SetGet(t *testing.T) {
+	m, _ := newTestMemory(10)
+	ctx := context.Background()
+
+	type position struct {
+		Lat float64 `json:"lat"`
+		Lng float64 `json:"lng"`
+	}
+
+	require.NoError(t, m.Set(ctx, "key", position{Lat: -23.5, Lng: -46.6}, time.Minute))
+
+	var got position
+	require.NoError(t, m.Get(ctx, "key", &got))
+	assert.Equal(t, position{Lat: -23.5, Lng: -46.6}, got)
+
+	assert.Error(t, m.Get(ctx, "missing", &got))
+}
+
+// TestMemory_TTLExpires testa que a chave some após o TTL e que TTL <= 0 não expira
+func TestMemory_TTLExpires(t *testing.T) {
+	m, now := newTestMemory(10)
+	ctx := context.Background()
+
+	require.NoError(t, m.Set(ctx, "short", "v", time.Minute))
+	require.NoError(t, m.Set(ctx, "forever", "v", 0))
+
+	*now = now.Add(30 * time.Second)
+	entry, err := m.Inspect(ctx, "short")
+	require.NoError(t, err)
+	assert.True(t, entry.Found)
+	assert.Equal(t, 30*time.Second, entry.TTL)
+
+	*now = now.Add(time.Minute)
+	var got string
+	assert.Error(t, m.Get(ctx, "short", &got))
+
+	entry, err = m.Inspect(ctx, "forever")
+	require.NoError(t, err)
+	assert.True(t, entry.Found)
+	assert.Equal(t, time.Duration(-1), entry.TTL)
+	assert.Equal(t, `"v"`, entry.Value)
+}
+
+// TestMemory_EvictsLeastRecentlyUsed testa o descarte da chave menos usada acima do limite
+func TestMemory_EvictsLeastRecentlyUsed(t *testing.T) {
+	m, _ := newTestMemory(2)
+	ctx := context.Background()
+
+	require.NoError(t, m.Set(ctx, "a", 1, 0))
+	require.NoError(t, m.Set(ctx, "b", 2, 0))
+
+	// Ler "a" a torna a mais recente; "b" passa a ser a próxima a sair
+	var got int
+	require.NoError(t, m.Get(ctx, "a", &got))
+
+	require.NoError(t, m.Set(ctx, "c", 3, 0))
+
+	assert.Equal(t, 2, m.Len())
+	assert.NoError(t, m.Get(ctx, "a", &got))
+	assert.Error(t, m.Get(ctx, "b", &got))
+	assert.NoError(t, m.Get(ctx, "c", &got))
+}
+
+// TestMemory_InvalidateUserCaches testa que posição e todos os históricos do usuário são removidos
+func TestMemory_InvalidateUserCaches(t *testing.T) {
+	m, _ := newTestMemory(10)
+	ctx := context.Background()
+
+	require.NoError(t, m.CacheUserPosition(ctx, "user-1", "pos"))
+	require.NoError(t, m.CacheUserHistory(ctx, "user-1", 10, "h10"))
+	require.NoError(t, m.CacheUserHistory(ctx, "user-1", 37, "h37"))
+	require.NoError(t, m.CacheUserHistory(ctx, "user-10", 10, "other"))
+
+	require.NoError(t, m.InvalidateUserCaches(ctx, "user-1"))
+
+	var got string
+	assert.Error(t, m.GetCachedUserPosition(ctx, "user-1", &got))
+	assert.Error(t, m.GetCachedUserHistory(ctx, "user-1", 10, &got))
+	assert.Error(t, m.GetCachedUserHistory(ctx, "user-1", 37, &got))
+	require.NoError(t, m.GetCachedUserHistory(ctx, "user-10", 10, &got))
+	assert.Equal(t, "other", got)
+}
+
+// TestMemory_ConcurrentAccess testa o uso concorrente (rodar com -race)
+func TestMemory_ConcurrentAccess(t *testing.T) {
+	m, _ := newTestMemory(50)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				key := fmt.Sprintf("k:%d", (worker*200+j)%100)
+				_ = m.Set(ctx, key, j, time.Minute)
+				var got int
+				_ = m.Get(ctx, key, &got)
+				_ = m.Delete(ctx, key)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, m.Len(), 50)
+}
@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// slidingWindowScript implementa a janela deslizante em um sorted set (score = timestamp em ms)
+// Executado como script para que limpeza, contagem e registro sejam atômicos
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+if redis.call('ZCARD', key) < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	redis.call('PEXPIRE', key, window)
+	return {1, 0}
+end
+
+local retry = window
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] then
+	retry = tonumber(oldest[2]) + window - now
+end
+return {0, retry}
+`)
+
+// AllowRequest registra uma requisição na janela deslizante da chave
+// Retorna false e o tempo até liberar uma vaga quando o limite já foi atingido
+func (r *Redis) AllowRequest(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	now := time.Now().UnixMilli()
+	member := fmt.Sprintf("%d-%s", now, uuid.New().String())
+
+	result, err := slidingWindowScript.Run(ctx, r.client,
+		[]string{"ratelimit:" + key},
+		now, window.Milliseconds(), limit, member,
+	).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to evaluate rate limit: %w", err)
+	}
+	if len(result) != 2 {
+		return false, 0, fmt.Errorf("unexpected rate limit result: %v", result)
+	}
+
+	return result[0] == 1, time.Duration(result[1]) * time.Millisecond, nil
+}
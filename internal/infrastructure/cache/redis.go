@@ -3,7 +3,9 @@ package cache
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -12,8 +14,26 @@ import (
 	"github.com/vitao/geolocation-tracker/pkg/logger"
 )
 
-// Verificar se Redis implementa a interface
-var _ usecase.CacheInterface = (*Redis)(nil)
+// Verificar se Redis implementa as interfaces
+var (
+	_ usecase.CacheInterface = (*Redis)(nil)
+	_ usecase.CacheInspector = (*Redis)(nil)
+)
+
+// Padrões do pool e dos timeouts quando a configuração não os informa
+const (
+	defaultRedisPoolSize     = 10
+	defaultRedisMinIdleConns = 2
+	defaultRedisDialTimeout  = 5 * time.Second
+	defaultRedisReadTimeout  = 3 * time.Second
+	defaultRedisWriteTimeout = 3 * time.Second
+)
+
+// ErrRedisAuthRequired indica um Redis que exige senha sem REDIS_PASSWORD configurado
+var ErrRedisAuthRequired = errors.New("redis requires authentication but REDIS_PASSWORD is not set")
+
+// ErrRedisAuthFailed indica que o Redis recusou a senha configurada
+var ErrRedisAuthFailed = errors.New("redis rejected the configured REDIS_PASSWORD")
 
 // Redis representa o cliente Redis para cache
 type Redis struct {
@@ -24,29 +44,24 @@ type Redis struct {
 // NewRedis cria uma nova instância do cliente Redis
 func NewRedis(cfg *config.Config, logger logger.Logger) (*Redis, error) {
 	// Criar cliente Redis
-	client := redis.NewClient(&redis.Options{
-		Addr:         fmt.Sprintf("%s:%s", cfg.Redis.Host, cfg.Redis.Port),
-		Password:     "", // Sem senha por enquanto
-		DB:           0,  // DB padrão
-		PoolSize:     10,
-		MinIdleConns: 2,
-		MaxRetries:   3,
-		DialTimeout:  5 * time.Second,
-		ReadTimeout:  3 * time.Second,
-		WriteTimeout: 3 * time.Second,
-	})
+	options := redisOptions(cfg.Redis)
+	client := redis.NewClient(options)
 
-	// Testar conexão
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// Testar conexão (também valida senha e índice do banco)
+	ctx, cancel := context.WithTimeout(context.Background(), options.DialTimeout)
 	defer cancel()
 
 	if err := client.Ping(ctx).Err(); err != nil {
-		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+		_ = client.Close()
+		return nil, redisConnectError(err, cfg.Redis)
 	}
 
 	logger.Info("Redis connection established",
 		"host", cfg.Redis.Host,
 		"port", cfg.Redis.Port,
+		"db", options.DB,
+		"auth", options.Password != "",
+		"pool_size", options.PoolSize,
 	)
 
 	return &Redis{
@@ -55,6 +70,60 @@ func NewRedis(cfg *config.Config, logger logger.Logger) (*Redis, error) {
 	}, nil
 }
 
+// redisOptions monta as opções do cliente a partir da configuração, com os padrões para valores vazios
+func redisOptions(cfg config.RedisConfig) *redis.Options {
+	options := &redis.Options{
+		Addr:         fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		PoolSize:     cfg.PoolSize,
+		MinIdleConns: cfg.MinIdleConns,
+		MaxRetries:   3,
+		DialTimeout:  time.Duration(cfg.DialTimeoutSeconds) * time.Second,
+		ReadTimeout:  time.Duration(cfg.ReadTimeoutSeconds) * time.Second,
+		WriteTimeout: time.Duration(cfg.WriteTimeoutSeconds) * time.Second,
+	}
+
+	if options.PoolSize <= 0 {
+		options.PoolSize = defaultRedisPoolSize
+	}
+	if options.MinIdleConns <= 0 {
+		options.MinIdleConns = defaultRedisMinIdleConns
+	}
+	if options.DialTimeout <= 0 {
+		options.DialTimeout = defaultRedisDialTimeout
+	}
+	if options.ReadTimeout <= 0 {
+		options.ReadTimeout = defaultRedisReadTimeout
+	}
+	if options.WriteTimeout <= 0 {
+		options.WriteTimeout = defaultRedisWriteTimeout
+	}
+
+	return options
+}
+
+// redisConnectError traduz as falhas de autenticação do Ping inicial em erros acionáveis
+func redisConnectError(err error, cfg config.RedisConfig) error {
+	message := err.Error()
+	switch {
+	case strings.HasPrefix(message, "NOAUTH") && cfg.Password == "":
+		return fmt.Errorf("failed to connect to Redis at %s:%s: %w", cfg.Host, cfg.Port, ErrRedisAuthRequired)
+	case strings.HasPrefix(message, "WRONGPASS"),
+		strings.Contains(message, "invalid password"),
+		strings.HasPrefix(message, "NOAUTH"):
+		return fmt.Errorf("failed to connect to Redis at %s:%s: %w: %s", cfg.Host, cfg.Port, ErrRedisAuthFailed, message)
+	case strings.Contains(message, "no password is set"),
+		strings.Contains(message, "without any password configured"):
+		// Senha configurada para um Redis sem autenticação
+		return fmt.Errorf("failed to connect to Redis at %s:%s: REDIS_PASSWORD is set but the server has no password: %w", cfg.Host, cfg.Port, err)
+	case strings.Contains(message, "DB index is out of range"):
+		return fmt.Errorf("failed to connect to Redis at %s:%s: REDIS_DB=%d is out of range: %w", cfg.Host, cfg.Port, cfg.DB, err)
+	default:
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+}
+
 // Close fecha a conexão com Redis
 func (r *Redis) Close() error {
 	return r.client.Close()
@@ -144,6 +213,42 @@ func (r *Redis) Exists(ctx context.Context, key string) (bool, error) {
 	return result > 0, nil
 }
 
+// Inspect retorna o valor bruto e o TTL de uma chave (uso em diagnóstico)
+func (r *Redis) Inspect(ctx context.Context, key string) (*usecase.CacheEntry, error) {
+	pipe := r.client.Pipeline()
+	getCmd := pipe.Get(ctx, key)
+	ttlCmd := pipe.TTL(ctx, key)
+
+	// redis.Nil no GET indica apenas chave ausente
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to inspect cache: %w", err)
+	}
+
+	entry := &usecase.CacheEntry{Key: key}
+
+	value, err := getCmd.Result()
+	if err == redis.Nil {
+		return entry, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect cache: %w", err)
+	}
+
+	ttl, err := ttlCmd.Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cache TTL: %w", err)
+	}
+
+	entry.Found = true
+	entry.Value = value
+	entry.TTL = ttl
+	if ttl < 0 {
+		entry.TTL = -1 // Sem expiração
+	}
+
+	return entry, nil
+}
+
 // CacheUserPosition armazena a posição atual de um usuário no cache
 func (r *Redis) CacheUserPosition(ctx context.Context, userID string, position interface{}) error {
 	key := fmt.Sprintf("user:position:%s", userID)
@@ -186,39 +291,53 @@ func (r *Redis) GetCachedUserHistory(ctx context.Context, userID string, limit i
 	return r.Get(ctx, key, dest)
 }
 
-// InvalidateUserCaches invalida todos os caches relacionados a um usuário
+// invalidateScanCount é o COUNT sugerido a cada iteração do SCAN na invalidação
+const invalidateScanCount = 100
+
+// InvalidateUserCaches invalida a posição atual e todos os históricos cacheados de um usuário
+// Os históricos são encontrados com SCAN (não bloqueia o Redis como KEYS) e removidos com DEL
 func (r *Redis) InvalidateUserCaches(ctx context.Context, userID string) error {
-	// Padrão de chaves relacionadas ao usuário
-	patterns := []string{
-		fmt.Sprintf("user:position:%s", userID),
-		fmt.Sprintf("history:%s:*", userID),
-	}
-
-	var lastError error
-	for _, pattern := range patterns {
-		if err := r.Delete(ctx, pattern); err != nil {
-			r.logger.Error("Failed to invalidate user cache pattern",
-				"user_id", userID,
-				"pattern", pattern,
-				"error", err.Error(),
-			)
-			lastError = err
-		}
+	keys := []string{fmt.Sprintf("user:position:%s", userID)}
+
+	pattern := fmt.Sprintf("history:%s:*", userID)
+	iter := r.client.Scan(ctx, 0, pattern, invalidateScanCount).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		r.logger.Error("Failed to scan user cache keys",
+			"user_id", userID,
+			"pattern", pattern,
+			"error", err.Error(),
+		)
+		return fmt.Errorf("failed to scan user cache keys: %w", err)
 	}
 
-	if lastError == nil {
-		r.logger.Debug("User caches invalidated successfully",
+	if err := r.client.Del(ctx, keys...).Err(); err != nil {
+		r.logger.Error("Failed to invalidate user caches",
 			"user_id", userID,
-			"patterns", len(patterns),
+			"keys", len(keys),
+			"error", err.Error(),
 		)
+		return fmt.Errorf("failed to invalidate user caches: %w", err)
 	}
 
-	return lastError
+	r.logger.Debug("User caches invalidated successfully",
+		"user_id", userID,
+		"keys", len(keys),
+	)
+
+	return nil
+}
+
+// PoolStats retorna estatísticas do pool de conexões do Redis
+func (r *Redis) PoolStats() *redis.PoolStats {
+	return r.client.PoolStats()
 }
 
 // LogStats registra estatísticas do Redis
 func (r *Redis) LogStats() {
-	stats := r.client.PoolStats()
+	stats := r.PoolStats()
 	r.logger.Info("Redis connection stats",
 		"hits", stats.Hits,
 		"misses", stats.Misses,
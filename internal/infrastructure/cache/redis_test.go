@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+)
+
+// TestRedisOptions_DefaultsForEmptyConfig testa que a configuração vazia mantém os padrões anteriores
+func TestRedisOptions_DefaultsForEmptyConfig(t *testing.T) {
+	options := redisOptions(config.RedisConfig{Host: "localhost", Port: "6379"})
+
+	assert.Equal(t, "localhost:6379", options.Addr)
+	assert.Empty(t, options.Password)
+	assert.Equal(t, 0, options.DB)
+	assert.Equal(t, 10, options.PoolSize)
+	assert.Equal(t, 2, options.MinIdleConns)
+	assert.Equal(t, 5*time.Second, options.DialTimeout)
+	assert.Equal(t, 3*time.Second, options.ReadTimeout)
+	assert.Equal(t, 3*time.Second, options.WriteTimeout)
+}
+
+// TestRedisOptions_UsesConfig testa senha, banco, pool e timeouts configurados
+func TestRedisOptions_UsesConfig(t *testing.T) {
+	options := redisOptions(config.RedisConfig{
+		Host:                "redis.internal",
+		Port:                "6380",
+		Password:            "secret",
+		DB:                  3,
+		PoolSize:            50,
+		MinIdleConns:        5,
+		DialTimeoutSeconds:  2,
+		ReadTimeoutSeconds:  1,
+		WriteTimeoutSeconds: 4,
+	})
+
+	assert.Equal(t, "redis.internal:6380", options.Addr)
+	assert.Equal(t, "secret", options.Password)
+	assert.Equal(t, 3, options.DB)
+	assert.Equal(t, 50, options.PoolSize)
+	assert.Equal(t, 5, options.MinIdleConns)
+	assert.Equal(t, 2*time.Second, options.DialTimeout)
+	assert.Equal(t, time.Second, options.ReadTimeout)
+	assert.Equal(t, 4*time.Second, options.WriteTimeout)
+}
+
+// TestRedisConnectError testa a tradução das falhas de autenticação do Ping inicial
+func TestRedisConnectError(t *testing.T) {
+	withoutPassword := config.RedisConfig{Host: "redis", Port: "6379"}
+	withPassword := config.RedisConfig{Host: "redis", Port: "6379", Password: "secret"}
+
+	err := redisConnectError(errors.New("NOAUTH Authentication required."), withoutPassword)
+	assert.ErrorIs(t, err, ErrRedisAuthRequired)
+
+	err = redisConnectError(errors.New("WRONGPASS invalid username-password pair or user is disabled."), withPassword)
+	assert.ErrorIs(t, err, ErrRedisAuthFailed)
+
+	err = redisConnectError(errors.New("ERR DB index is out of range"), withoutPassword)
+	assert.Contains(t, err.Error(), "REDIS_DB=0")
+
+	connErr := errors.New("dial tcp: connection refused")
+	err = redisConnectError(connErr, withoutPassword)
+	assert.ErrorIs(t, err, connErr)
+	assert.NotErrorIs(t, err, ErrRedisAuthRequired)
+}
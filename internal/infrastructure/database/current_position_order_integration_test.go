@@ -0,0 +1,63 @@
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/events"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+)
+
+// TestSaveWithEvents_OlderPositionKeepsCurrent testa que uma posição mais antiga gravada depois
+// (ex.: requisição concorrente que leu a mesma posição atual) não substitui a atual
+func TestSaveWithEvents_OlderPositionKeepsCurrent(t *testing.T) {
+	db := newIntegrationDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+	ctx := context.Background()
+
+	userID := uuid.New().String()
+	_, err := db.Connection().ExecContext(ctx,
+		`INSERT INTO users (id, name, email) VALUES ($1, 'Order', $2)`, userID, userID+"@example.com")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = db.Connection().ExecContext(context.Background(), `DELETE FROM event_outbox WHERE payload->>'user_id' = $1`, userID)
+		_, _ = db.Connection().ExecContext(context.Background(), `DELETE FROM users WHERE id = $1`, userID)
+	})
+
+	user, err := entity.NewUserID(userID)
+	require.NoError(t, err)
+	now := time.Now().Truncate(time.Millisecond)
+	newer, err := entity.NewPosition(uuid.New().String(), *user, -23.550520, -46.633309, now)
+	require.NoError(t, err)
+	older, err := entity.NewPosition(uuid.New().String(), *user, -22.906847, -43.172897, now.Add(-time.Minute))
+	require.NoError(t, err)
+
+	outboxFor := func(position *entity.Position) []*repository.OutboxEntry {
+		positionID := position.ID()
+		event := events.NewPositionChangedEvent(userID, "req-order", events.PositionChangedData{PositionID: positionID.Value()})
+		return []*repository.OutboxEntry{{Stream: events.StreamPositionEvents, Event: event}}
+	}
+
+	require.NoError(t, repo.SaveWithEvents(ctx, newer, outboxFor(newer)))
+	olderOutbox := outboxFor(older)
+	require.NoError(t, repo.SaveWithEvents(ctx, older, olderOutbox))
+
+	current, err := repo.FindCurrentByUserID(ctx, *user)
+	require.NoError(t, err)
+	currentID, newerID := current.ID(), newer.ID()
+	assert.Equal(t, newerID.Value(), currentID.Value())
+	assert.Zero(t, olderOutbox[0].ID, "the older position must not write a position.changed event")
+
+	// A posição mais antiga continua no histórico
+	olderID := older.ID()
+	_, err = repo.FindByID(ctx, olderID)
+	assert.NoError(t, err)
+}
@@ -3,31 +3,31 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
 	"github.com/vitao/geolocation-tracker/pkg/config"
 	"github.com/vitao/geolocation-tracker/pkg/logger"
+	"github.com/vitao/geolocation-tracker/pkg/metrics"
 )
 
 // DB representa a conexão com o banco de dados
 type DB struct {
-	conn   *sql.DB
-	logger logger.Logger
+	conn    *sql.DB
+	metrics metrics.Collector
+	logger  logger.Logger
+
+	// queryTimeout é o prazo de cada consulta pesada (0 usa apenas o prazo do ctx)
+	queryTimeout time.Duration
 }
 
 // New cria uma nova conexão com PostgreSQL
-func New(cfg *config.Config, logger logger.Logger) (*DB, error) {
+func New(cfg *config.Config, collector metrics.Collector, logger logger.Logger) (*DB, error) {
 	// Construir string de conexão
-	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable TimeZone=UTC",
-		cfg.Database.Host,
-		cfg.Database.Port,
-		cfg.Database.User,
-		cfg.Database.Password,
-		cfg.Database.DBName,
-	)
+	dsn := buildDSN(cfg.Database)
 
 	// Conectar ao banco
 	conn, err := sql.Open("postgres", dsn)
@@ -35,10 +35,11 @@ func New(cfg *config.Config, logger logger.Logger) (*DB, error) {
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
 
-	// Configurar pool de conexões
-	conn.SetMaxOpenConns(25)                 // Máximo de conexões ativas
-	conn.SetMaxIdleConns(5)                  // Conexões idle no pool
-	conn.SetConnMaxLifetime(5 * time.Minute) // Tempo de vida da conexão
+	// Configurar pool de conexões (limites validados em config.Load)
+	connMaxLifetime := time.Duration(cfg.Database.ConnMaxLifetimeMinutes) * time.Minute
+	conn.SetMaxOpenConns(cfg.Database.MaxOpenConns) // Máximo de conexões ativas
+	conn.SetMaxIdleConns(cfg.Database.MaxIdleConns) // Conexões idle no pool
+	conn.SetConnMaxLifetime(connMaxLifetime)        // Tempo de vida da conexão
 
 	// Testar conexão
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -53,14 +54,44 @@ func New(cfg *config.Config, logger logger.Logger) (*DB, error) {
 		"host", cfg.Database.Host,
 		"port", cfg.Database.Port,
 		"database", cfg.Database.DBName,
+		"sslmode", cfg.Database.SSLMode,
+		"max_open_conns", cfg.Database.MaxOpenConns,
+		"max_idle_conns", cfg.Database.MaxIdleConns,
+		"conn_max_lifetime", connMaxLifetime.String(),
+		"query_timeout_ms", cfg.Database.QueryTimeoutMs,
 	)
 
 	return &DB{
-		conn:   conn,
-		logger: logger,
+		conn:         conn,
+		metrics:      collector,
+		logger:       logger,
+		queryTimeout: time.Duration(cfg.Database.QueryTimeoutMs) * time.Millisecond,
 	}, nil
 }
 
+// buildDSN monta a string de conexão do lib/pq, incluindo sslmode e o certificado da CA
+// A combinação já foi validada em config.Load
+func buildDSN(cfg config.DatabaseConfig) string {
+	sslMode := cfg.SSLMode
+	if sslMode == "" {
+		sslMode = config.SSLModeDisable
+	}
+
+	dsn := fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s TimeZone=UTC",
+		cfg.Host,
+		cfg.Port,
+		cfg.User,
+		cfg.Password,
+		cfg.DBName,
+		sslMode,
+	)
+	if cfg.SSLRootCert != "" {
+		dsn += fmt.Sprintf(" sslrootcert=%s", cfg.SSLRootCert)
+	}
+	return dsn
+}
+
 // Connection retorna a conexão SQL
 func (db *DB) Connection() *sql.DB {
 	return db.conn
@@ -79,6 +110,29 @@ func (db *DB) Health(ctx context.Context) error {
 	return db.conn.PingContext(ctx)
 }
 
+// observeQuery registra a duração de uma operação; uso: defer db.observeQuery("op", time.Now())
+func (db *DB) observeQuery(operation string, start time.Time) {
+	db.metrics.ObserveDBQuery(operation, time.Since(start))
+}
+
+// withQueryTimeout deriva do ctx da requisição o prazo de uma consulta; vale o menor dos dois
+// As linhas devem ser lidas antes do cancel, que libera a conexão
+func (db *DB) withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if db.queryTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, db.queryTimeout)
+}
+
+// queryError traduz o estouro do prazo de uma consulta em repository.ErrQueryTimeout
+// O driver pode devolver o cancelamento em vez de context.DeadlineExceeded, por isso o ctx é consultado
+func queryError(ctx context.Context, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", repository.ErrQueryTimeout, err)
+	}
+	return err
+}
+
 // BeginTx inicia uma transação
 func (db *DB) BeginTx(ctx context.Context) (*sql.Tx, error) {
 	return db.conn.BeginTx(ctx, nil)
@@ -140,8 +194,13 @@ func (db *DB) RunMigrations(ctx context.Context, migrations []Migration) error {
 		}
 		appliedMigrations[version] = true
 	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	rows.Close()
 
 	// Aplicar migrações pendentes
+	applied := make([]int, 0, len(migrations))
 	for _, migration := range migrations {
 		if appliedMigrations[migration.Version] {
 			db.logger.Debug("Migration already applied",
@@ -186,7 +245,13 @@ func (db *DB) RunMigrations(ctx context.Context, migrations []Migration) error {
 		db.logger.Info("Migration applied successfully",
 			"version", migration.Version,
 		)
+		applied = append(applied, migration.Version)
 	}
 
+	db.logger.Info("Database migrations up to date",
+		"applied_versions", applied,
+		"total", len(migrations),
+	)
+
 	return nil
 }
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+	"github.com/vitao/geolocation-tracker/pkg/metrics"
+)
+
+// nopLogger descarta todos os logs durante os testes
+type nopLogger struct{}
+
+func (nopLogger) Info(msg string, fields ...interface{})  {}
+func (nopLogger) Error(msg string, fields ...interface{}) {}
+func (nopLogger) Warn(msg string, fields ...interface{})  {}
+func (nopLogger) Fatal(msg string, fields ...interface{}) {}
+func (nopLogger) Debug(msg string, fields ...interface{}) {}
+func (nopLogger) Sync() error                             { return nil }
+
+// newTestDB cria um DB apoiado por sqlmock para testes de repository
+func newTestDB(t *testing.T) (*DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	conn, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	return &DB{conn: conn, metrics: metrics.NewNoopCollector(), logger: nopLogger{}}, mock
+}
+
+// TestBuildDSN testa o sslmode e o certificado da CA na string de conexão
+func TestBuildDSN(t *testing.T) {
+	base := config.DatabaseConfig{Host: "db", Port: "5432", User: "app", Password: "secret", DBName: "geo"}
+
+	dsn := buildDSN(base)
+	assert.Contains(t, dsn, "sslmode=disable")
+	assert.NotContains(t, dsn, "sslrootcert")
+
+	base.SSLMode = config.SSLModeRequire
+	assert.Contains(t, buildDSN(base), "sslmode=require")
+
+	base.SSLMode = config.SSLModeVerifyFull
+	base.SSLRootCert = "/etc/ssl/db-ca.pem"
+	dsn = buildDSN(base)
+	assert.Contains(t, dsn, "sslmode=verify-full")
+	assert.Contains(t, dsn, "sslrootcert=/etc/ssl/db-ca.pem")
+}
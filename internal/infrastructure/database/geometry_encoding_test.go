@@ -0,0 +1,83 @@
+package database
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+)
+
+// postgisDSNEnv aponta para um PostGIS real; sem ela os testes abaixo são ignorados
+const postgisDSNEnv = "POSTGIS_TEST_DSN"
+
+// openPostGIS abre uma conexão com o PostGIS de teste ou pula o teste
+func openPostGIS(tb testing.TB) *sql.DB {
+	tb.Helper()
+
+	dsn := os.Getenv(postgisDSNEnv)
+	if dsn == "" {
+		tb.Skipf("%s not set, skipping PostGIS test", postgisDSNEnv)
+	}
+
+	conn, err := sql.Open("postgres", dsn)
+	require.NoError(tb, err)
+	tb.Cleanup(func() { conn.Close() })
+
+	require.NoError(tb, conn.Ping())
+	return conn
+}
+
+// TestGeometryEncoding_WKTAndWKBAreEqual testa que o PostGIS gera a mesma geometria para WKT e WKB
+func TestGeometryEncoding_WKTAndWKBAreEqual(t *testing.T) {
+	conn := openPostGIS(t)
+
+	coord, err := valueobject.NewCoordinate(-23.550520, -46.633309)
+	require.NoError(t, err)
+
+	var equal bool
+	err = conn.QueryRow(
+		`SELECT ST_Equals(ST_GeomFromText($1, 4326), ST_GeomFromWKB($2, 4326))`,
+		coord.ToWKT(), coord.ToWKB(),
+	).Scan(&equal)
+	require.NoError(t, err)
+	assert.True(t, equal)
+}
+
+// benchmarkGeometryInsert mede inserts em uma tabela temporária com a expressão de geometria informada
+func benchmarkGeometryInsert(b *testing.B, geomExpr string, arg func(*valueobject.Coordinate) interface{}) {
+	conn := openPostGIS(b)
+
+	_, err := conn.Exec(`CREATE TEMP TABLE IF NOT EXISTS geometry_bench (location geometry(Point, 4326))`)
+	require.NoError(b, err)
+
+	stmt, err := conn.Prepare(`INSERT INTO geometry_bench (location) VALUES (` + geomExpr + `)`)
+	require.NoError(b, err)
+	defer stmt.Close()
+
+	coord, err := valueobject.NewCoordinate(-23.550520, -46.633309)
+	require.NoError(b, err)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := stmt.Exec(arg(coord)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkInsert_WKT mede inserts com ST_GeomFromText (formato anterior)
+func BenchmarkInsert_WKT(b *testing.B) {
+	benchmarkGeometryInsert(b, "ST_GeomFromText($1, 4326)", func(c *valueobject.Coordinate) interface{} {
+		return c.ToWKT()
+	})
+}
+
+// BenchmarkInsert_WKB mede inserts com ST_GeomFromWKB (formato atual)
+func BenchmarkInsert_WKB(b *testing.B) {
+	benchmarkGeometryInsert(b, "ST_GeomFromWKB($1, 4326)", func(c *valueobject.Coordinate) interface{} {
+		return c.ToWKB()
+	})
+}
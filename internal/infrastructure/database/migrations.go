@@ -0,0 +1,87 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migrationFiles contém o schema versionado: migrations/NNN_descricao.sql
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migrations retorna as migrações embutidas no binário, ordenadas por versão
+// Cada arquivo deve ser idempotente: bancos criados antes do registro já têm o schema
+func Migrations() ([]Migration, error) {
+	sub, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedded migrations: %w", err)
+	}
+	return loadMigrations(sub)
+}
+
+// loadMigrations lê os arquivos .sql da raiz de fsys no formato NNN_descricao.sql
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	migrations := make([]Migration, 0, len(entries))
+	seen := make(map[int]string, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || path.Ext(name) != ".sql" {
+			continue
+		}
+
+		version, description, err := parseMigrationName(name)
+		if err != nil {
+			return nil, err
+		}
+		if previous, ok := seen[version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, previous, name)
+		}
+		seen[version] = name
+
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", name, err)
+		}
+		if strings.TrimSpace(string(content)) == "" {
+			return nil, fmt.Errorf("migration %s is empty", name)
+		}
+
+		migrations = append(migrations, Migration{
+			Version:     version,
+			Description: description,
+			SQL:         string(content),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// parseMigrationName extrai versão e descrição de "003_create_positions.sql"
+func parseMigrationName(name string) (int, string, error) {
+	base := strings.TrimSuffix(name, ".sql")
+	prefix, rest, ok := strings.Cut(base, "_")
+	if !ok || rest == "" {
+		return 0, "", fmt.Errorf("invalid migration file name %q: expected NNN_description.sql", name)
+	}
+
+	version, err := strconv.Atoi(prefix)
+	if err != nil || version <= 0 {
+		return 0, "", fmt.Errorf("invalid migration file name %q: version must be a positive number", name)
+	}
+
+	return version, strings.ReplaceAll(rest, "_", " "), nil
+}
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS positions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    location GEOMETRY(POINT, 4326) NOT NULL,
+    sector_x INTEGER NOT NULL,
+    sector_y INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_positions_user_id ON positions (user_id);
+CREATE INDEX IF NOT EXISTS idx_positions_location ON positions USING GIST (location);
+CREATE INDEX IF NOT EXISTS idx_positions_sector ON positions (sector_x, sector_y);
+CREATE INDEX IF NOT EXISTS idx_positions_created_at ON positions (created_at DESC);
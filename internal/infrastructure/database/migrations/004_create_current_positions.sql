@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS current_positions (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    position_id UUID NOT NULL REFERENCES positions(id) ON DELETE CASCADE,
+    location GEOMETRY(POINT, 4326) NOT NULL,
+    sector_x INTEGER NOT NULL,
+    sector_y INTEGER NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_current_positions_location ON current_positions USING GIST (location);
+CREATE INDEX IF NOT EXISTS idx_current_positions_sector ON current_positions (sector_x, sector_y);
+CREATE INDEX IF NOT EXISTS idx_current_positions_updated_at ON current_positions (updated_at DESC);
+
+DROP TRIGGER IF EXISTS update_current_positions_updated_at ON current_positions;
+CREATE TRIGGER update_current_positions_updated_at BEFORE UPDATE ON current_positions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
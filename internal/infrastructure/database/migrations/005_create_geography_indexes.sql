@@ -0,0 +1,4 @@
+-- As buscas por raio usam ST_DWithin(location::geography, ...), que não aproveita o índice
+-- GIST sobre a geometria; o índice de expressão permite o index scan nessas consultas
+CREATE INDEX IF NOT EXISTS idx_positions_location_geography ON positions USING GIST ((location::geography));
+CREATE INDEX IF NOT EXISTS idx_current_positions_location_geography ON current_positions USING GIST ((location::geography));
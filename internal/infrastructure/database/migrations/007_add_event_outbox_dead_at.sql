@@ -0,0 +1,6 @@
+-- Eventos que nunca vão ser publicados (tipo recusado pelo stream ou tentativas esgotadas) saem da fila
+-- com dead_at preenchido; ficam na tabela com o last_error para inspeção
+ALTER TABLE event_outbox ADD COLUMN IF NOT EXISTS dead_at TIMESTAMP WITH TIME ZONE;
+
+DROP INDEX IF EXISTS idx_event_outbox_pending;
+CREATE INDEX IF NOT EXISTS idx_event_outbox_pending ON event_outbox (id) WHERE published_at IS NULL AND dead_at IS NULL;
@@ -0,0 +1,7 @@
+-- updated_at de current_positions marca a última posição recebida (feed de atividade e retenção)
+-- Só atualiza quando a posição atual muda: regravar setores (recálculo) não é atividade do usuário
+DROP TRIGGER IF EXISTS update_current_positions_updated_at ON current_positions;
+CREATE TRIGGER update_current_positions_updated_at BEFORE UPDATE ON current_positions
+    FOR EACH ROW
+    WHEN (OLD.position_id IS DISTINCT FROM NEW.position_id)
+    EXECUTE FUNCTION update_updated_at_column();
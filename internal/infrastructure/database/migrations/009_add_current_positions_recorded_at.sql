@@ -0,0 +1,10 @@
+-- recorded_at da posição atual, para o upsert recusar posições mais antigas dentro do próprio
+-- INSERT ... ON CONFLICT (a linha fica travada, então requisições concorrentes não se sobrescrevem)
+ALTER TABLE current_positions ADD COLUMN IF NOT EXISTS recorded_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE current_positions c
+SET recorded_at = p.created_at
+FROM positions p
+WHERE p.id = c.position_id AND c.recorded_at IS NULL;
+
+ALTER TABLE current_positions ALTER COLUMN recorded_at SET NOT NULL;
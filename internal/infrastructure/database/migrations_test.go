@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMigrations_Embedded testa que o schema embutido é carregado em ordem de versão
+func TestMigrations_Embedded(t *testing.T) {
+	migrations, err := Migrations()
+	require.NoError(t, err)
+	require.Len(t, migrations, 9)
+
+	for i, migration := range migrations {
+		assert.Equal(t, i+1, migration.Version)
+		assert.NotEmpty(t, migration.Description)
+	}
+	assert.Equal(t, "enable postgis", migrations[0].Description)
+	assert.Contains(t, migrations[0].SQL, "CREATE EXTENSION IF NOT EXISTS postgis")
+	assert.Contains(t, migrations[2].SQL, "idx_positions_sector")
+	assert.Contains(t, migrations[3].SQL, "idx_current_positions_sector")
+	assert.Contains(t, migrations[4].SQL, "USING GIST ((location::geography))")
+	assert.Contains(t, migrations[5].SQL, "CREATE TABLE IF NOT EXISTS event_outbox")
+	assert.Contains(t, migrations[6].SQL, "ADD COLUMN IF NOT EXISTS dead_at")
+	assert.Contains(t, migrations[7].SQL, "WHEN (OLD.position_id IS DISTINCT FROM NEW.position_id)")
+	assert.Contains(t, migrations[8].SQL, "ADD COLUMN IF NOT EXISTS recorded_at")
+}
+
+// TestLoadMigrations_InvalidFiles testa nomes inválidos, versões duplicadas e arquivos vazios
+func TestLoadMigrations_InvalidFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+		err   string
+	}{
+		{
+			name:  "sem descrição",
+			files: fstest.MapFS{"001.sql": {Data: []byte("SELECT 1;")}},
+			err:   "expected NNN_description.sql",
+		},
+		{
+			name:  "versão não numérica",
+			files: fstest.MapFS{"abc_users.sql": {Data: []byte("SELECT 1;")}},
+			err:   "version must be a positive number",
+		},
+		{
+			name: "versão duplicada",
+			files: fstest.MapFS{
+				"001_users.sql":  {Data: []byte("SELECT 1;")},
+				"0001_other.sql": {Data: []byte("SELECT 1;")},
+			},
+			err: "duplicate migration version 1",
+		},
+		{
+			name:  "arquivo vazio",
+			files: fstest.MapFS{"001_users.sql": {Data: []byte("  \n")}},
+			err:   "is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadMigrations(tt.files)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+// TestRunMigrations_AppliesOnlyPending testa que versões já registradas são puladas
+func TestRunMigrations_AppliesOnlyPending(t *testing.T) {
+	db, mock := newTestDB(t)
+	migrations := []Migration{
+		{Version: 1, Description: "enable postgis", SQL: "CREATE EXTENSION IF NOT EXISTS postgis;"},
+		{Version: 2, Description: "create users", SQL: "CREATE TABLE IF NOT EXISTS users (id UUID);"},
+	}
+
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(migrations[1].SQL)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").
+		WithArgs(2, "create users").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, db.RunMigrations(context.Background(), migrations))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestRunMigrations_RollsBackFailedMigration testa que uma migração com erro não é registrada
+func TestRunMigrations_RollsBackFailedMigration(t *testing.T) {
+	db, mock := newTestDB(t)
+	migrations := []Migration{
+		{Version: 1, Description: "broken", SQL: "CREATE TABLE broken"},
+	}
+
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version"}))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE broken").
+		WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+
+	err := db.RunMigrations(context.Background(), migrations)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to execute migration 1")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
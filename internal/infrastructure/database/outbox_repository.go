@@ -0,0 +1,162 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitao/geolocation-tracker/internal/domain/events"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// maxOutboxErrorLength limita o último erro gravado por evento
+const maxOutboxErrorLength = 1000
+
+// outboxRepository implementa repository.OutboxRepository usando PostgreSQL
+type outboxRepository struct {
+	db     *DB
+	logger logger.Logger
+}
+
+// NewOutboxRepository cria uma nova instância do repository da outbox de eventos
+func NewOutboxRepository(db *DB, logger logger.Logger) repository.OutboxRepository {
+	return &outboxRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// insertOutboxEntry grava o evento na outbox dentro da transação de quem persiste o agregado
+// O ID do evento é definido aqui para que todas as tentativas de publicação usem o mesmo ID
+func insertOutboxEntry(ctx context.Context, tx *sql.Tx, entry *repository.OutboxEntry) error {
+	if entry.Event.ID == "" {
+		entry.Event.ID = uuid.New().String()
+	}
+
+	payload, err := json.Marshal(entry.Event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox event: %w", err)
+	}
+
+	query := `
+		INSERT INTO event_outbox (stream, event_type, payload)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+
+	if err := tx.QueryRowContext(ctx, query, entry.Stream, string(entry.Event.Type), payload).Scan(&entry.ID); err != nil {
+		return fmt.Errorf("failed to insert outbox event: %w", err)
+	}
+	return nil
+}
+
+// FindPending retorna os eventos ainda não publicados nem descartados, em ordem de gravação
+func (r *outboxRepository) FindPending(ctx context.Context, limit int) ([]*repository.OutboxEntry, error) {
+	defer r.db.observeQuery("outbox.find_pending", time.Now())
+
+	query := `
+		SELECT id, stream, payload, attempts
+		FROM event_outbox
+		WHERE published_at IS NULL AND dead_at IS NULL
+		ORDER BY id
+		LIMIT $1
+	`
+
+	rows, err := r.db.Connection().QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*repository.OutboxEntry
+	for rows.Next() {
+		var entry repository.OutboxEntry
+		var payload []byte
+
+		if err := rows.Scan(&entry.ID, &entry.Stream, &payload, &entry.Attempts); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+
+		var event events.Event
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal outbox event %d: %w", entry.ID, err)
+		}
+		entry.Event = &event
+
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outbox events: %w", err)
+	}
+
+	return entries, nil
+}
+
+// MarkPublished registra que o evento chegou ao stream
+func (r *outboxRepository) MarkPublished(ctx context.Context, id int64) error {
+	query := `UPDATE event_outbox SET published_at = NOW() WHERE id = $1`
+
+	if _, err := r.db.Connection().ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to mark outbox event as published: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed registra uma tentativa de publicação sem sucesso; o evento segue pendente
+func (r *outboxRepository) MarkFailed(ctx context.Context, id int64, reason string) error {
+	query := `UPDATE event_outbox SET attempts = attempts + 1, last_error = $2 WHERE id = $1`
+
+	if _, err := r.db.Connection().ExecContext(ctx, query, id, truncateOutboxError(reason)); err != nil {
+		return fmt.Errorf("failed to mark outbox event as failed: %w", err)
+	}
+	return nil
+}
+
+// MarkDead registra a última falha e descarta o evento; ele fica na tabela, fora dos pendentes
+func (r *outboxRepository) MarkDead(ctx context.Context, id int64, reason string) error {
+	query := `UPDATE event_outbox SET attempts = attempts + 1, last_error = $2, dead_at = NOW() WHERE id = $1`
+
+	if _, err := r.db.Connection().ExecContext(ctx, query, id, truncateOutboxError(reason)); err != nil {
+		return fmt.Errorf("failed to mark outbox event as dead: %w", err)
+	}
+	return nil
+}
+
+// truncateOutboxError limita o erro gravado a maxOutboxErrorLength
+func truncateOutboxError(reason string) string {
+	if len(reason) > maxOutboxErrorLength {
+		return reason[:maxOutboxErrorLength]
+	}
+	return reason
+}
+
+// DeletePublishedBefore remove eventos publicados antes do timestamp
+// Eventos pendentes nunca são removidos, por mais antigos que sejam
+func (r *outboxRepository) DeletePublishedBefore(ctx context.Context, before *valueobject.Timestamp) (int, error) {
+	query := `DELETE FROM event_outbox WHERE published_at < $1`
+
+	result, err := r.db.Connection().ExecContext(ctx, query, before.Time())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete published outbox events: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if deleted > 0 {
+		r.logger.Info("Published outbox events deleted",
+			"count", deleted,
+			"published_before", before.String(),
+		)
+	}
+
+	return int(deleted), nil
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitao/geolocation-tracker/internal/domain/events"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+)
+
+// TestOutboxRepository_FindPending testa a leitura dos eventos pendentes em ordem de gravação
+func TestOutboxRepository_FindPending(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewOutboxRepository(db, nopLogger{})
+
+	event := events.NewPositionChangedEvent("user123", "req-1", events.PositionChangedData{PositionID: "pos-1", NewSector: "sector_1_2"})
+	event.ID = "evt-1"
+	event.Metadata.RequestID = "req-1"
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	rows := sqlmock.NewRows([]string{"id", "stream", "payload", "attempts"}).
+		AddRow(int64(3), events.StreamPositionEvents, payload, 2)
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE published_at IS NULL AND dead_at IS NULL")).
+		WithArgs(50).
+		WillReturnRows(rows)
+
+	entries, err := repo.FindPending(context.Background(), 50)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	assert.Equal(t, int64(3), entries[0].ID)
+	assert.Equal(t, events.StreamPositionEvents, entries[0].Stream)
+	assert.Equal(t, 2, entries[0].Attempts)
+	assert.Equal(t, "evt-1", entries[0].Event.ID)
+	assert.Equal(t, events.EventTypePositionChanged, entries[0].Event.Type)
+	assert.Equal(t, "req-1", entries[0].Event.Metadata.RequestID)
+	assert.Equal(t, "sector_1_2", entries[0].Event.Data["new_sector"])
+	assert.True(t, entries[0].Event.Timestamp.Equal(event.Timestamp))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestOutboxRepository_FindPendingInvalidPayload testa que um payload corrompido é reportado
+func TestOutboxRepository_FindPendingInvalidPayload(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewOutboxRepository(db, nopLogger{})
+
+	rows := sqlmock.NewRows([]string{"id", "stream", "payload", "attempts"}).
+		AddRow(int64(3), events.StreamPositionEvents, []byte("{"), 0)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM event_outbox")).WillReturnRows(rows)
+
+	entries, err := repo.FindPending(context.Background(), 10)
+	assert.Nil(t, entries)
+	assert.ErrorContains(t, err, "failed to unmarshal outbox event 3")
+}
+
+// TestOutboxRepository_MarkPublished testa a marcação do evento publicado
+func TestOutboxRepository_MarkPublished(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewOutboxRepository(db, nopLogger{})
+
+	mock.ExpectExec(regexp.QuoteMeta("SET published_at = NOW()")).
+		WithArgs(int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.MarkPublished(context.Background(), 3))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestOutboxRepository_MarkFailedTruncatesReason testa o registro da falha com o erro truncado
+func TestOutboxRepository_MarkFailedTruncatesReason(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewOutboxRepository(db, nopLogger{})
+
+	reason := strings.Repeat("x", maxOutboxErrorLength+10)
+	mock.ExpectExec(regexp.QuoteMeta("SET attempts = attempts + 1")).
+		WithArgs(int64(3), reason[:maxOutboxErrorLength]).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.MarkFailed(context.Background(), 3, reason))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestOutboxRepository_MarkDead testa o descarte do evento com o último erro
+func TestOutboxRepository_MarkDead(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewOutboxRepository(db, nopLogger{})
+
+	mock.ExpectExec(regexp.QuoteMeta("SET attempts = attempts + 1, last_error = $2, dead_at = NOW()")).
+		WithArgs(int64(3), "event type not allowed").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.MarkDead(context.Background(), 3, "event type not allowed"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestOutboxRepository_DeletePublishedBefore testa a limpeza dos eventos já publicados
+func TestOutboxRepository_DeletePublishedBefore(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewOutboxRepository(db, nopLogger{})
+
+	cutoff := valueobject.NewTimestamp(time.Now().Add(-24 * time.Hour))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM event_outbox WHERE published_at < $1")).
+		WithArgs(cutoff.Time()).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	deleted, err := repo.DeletePublishedBefore(context.Background(), cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, 4, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestOutboxRepository_DeletePublishedBeforeError testa a falha na limpeza
+func TestOutboxRepository_DeletePublishedBeforeError(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewOutboxRepository(db, nopLogger{})
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM event_outbox")).
+		WillReturnError(errors.New("connection reset"))
+
+	deleted, err := repo.DeletePublishedBefore(context.Background(), valueobject.NewTimestamp(time.Now()))
+	assert.Error(t, err)
+	assert.Equal(t, 0, deleted)
+}
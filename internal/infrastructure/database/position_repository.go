@@ -5,30 +5,64 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/vitao/geolocation-tracker/internal/domain/entity"
 	"github.com/vitao/geolocation-tracker/internal/domain/repository"
 	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/pkg/config"
 	"github.com/vitao/geolocation-tracker/pkg/logger"
 )
 
+// Limites de parâmetros para queries com múltiplos setores
+const (
+	// maxQueryParams é o limite de parâmetros por statement do PostgreSQL
+	maxQueryParams = 65535
+
+	// paramsPerSector é o número de parâmetros usados por setor (sector_x, sector_y)
+	paramsPerSector = 2
+
+	// maxSectorsPerQuery é o maior chunk possível sem estourar o limite
+	maxSectorsPerQuery = maxQueryParams / paramsPerSector
+)
+
 // positionRepository implementa repository.PositionRepository usando PostgreSQL + PostGIS
 type positionRepository struct {
-	db     *DB
-	logger logger.Logger
+	db              *DB
+	logger          logger.Logger
+	sectorChunkSize int
+
+	// updateCurrentOnOutOfOrder desliga a checagem de recorded_at no upsert da posição atual
+	updateCurrentOnOutOfOrder bool
 }
 
 // NewPositionRepository cria uma nova instância do repository de posições
-func NewPositionRepository(db *DB, logger logger.Logger) repository.PositionRepository {
+func NewPositionRepository(db *DB, cfg *config.Config, logger logger.Logger) repository.PositionRepository {
+	chunkSize := cfg.Database.SectorQueryChunkSize
+	if chunkSize <= 0 || chunkSize > maxSectorsPerQuery {
+		chunkSize = maxSectorsPerQuery
+	}
+
 	return &positionRepository{
-		db:     db,
-		logger: logger,
+		db:                        db,
+		logger:                    logger,
+		sectorChunkSize:           chunkSize,
+		updateCurrentOnOutOfOrder: cfg.Positions.UpdateCurrentOnOutOfOrder,
 	}
 }
 
 // Save persiste uma posição
 func (r *positionRepository) Save(ctx context.Context, position *entity.Position) error {
+	return r.SaveWithEvents(ctx, position, nil)
+}
+
+// SaveWithEvents persiste uma posição e grava os eventos na outbox na mesma transação
+// Se o commit falhar, nem a posição nem os eventos ficam gravados
+func (r *positionRepository) SaveWithEvents(ctx context.Context, position *entity.Position, outbox []*repository.OutboxEntry) error {
+	defer r.db.observeQuery("position.save", time.Now())
+
 	tx, err := r.db.BeginTx(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -40,15 +74,132 @@ func (r *positionRepository) Save(ctx context.Context, position *entity.Position
 	userID := position.UserID()
 
 	// 1. Inserir na tabela positions (histórico)
+	if err := r.insertHistory(ctx, tx, position); err != nil {
+		return err
+	}
+
+	// 2. Atualizar/inserir posição atual
+	updated, err := r.updateCurrentPosition(ctx, tx, position)
+	if err != nil {
+		return fmt.Errorf("failed to update current position: %w", err)
+	}
+
+	// 3. Gravar eventos na outbox (publicados depois pelo relay)
+	// Se uma posição mais recente já é a atual (requisição concorrente), a posição fica só
+	// no histórico e, como no fluxo fora de ordem, nenhum evento é gravado
+	if !updated {
+		outbox = nil
+	}
+	for _, entry := range outbox {
+		if err := insertOutboxEntry(ctx, tx, entry); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.logger.Debug("Position saved successfully",
+		"position_id", posID.Value(),
+		"user_id", userID.Value(),
+		"outbox_events", len(outbox),
+	)
+
+	return nil
+}
+
+// SaveHistory persiste uma posição apenas no histórico
+// Usado para posições fora de ordem, que não devem substituir a posição atual
+func (r *positionRepository) SaveHistory(ctx context.Context, position *entity.Position) error {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.insertHistory(ctx, tx, position); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	posID := position.ID()
+	userID := position.UserID()
+	r.logger.Debug("Position saved to history only",
+		"position_id", posID.Value(),
+		"user_id", userID.Value(),
+	)
+
+	return nil
+}
+
+// SaveBatch persiste várias posições no histórico, atualiza a posição atual
+// com as posições de current e grava os eventos na outbox, tudo em uma única transação
+func (r *positionRepository) SaveBatch(ctx context.Context, history []*entity.Position, current []*entity.Position, outbox []*repository.OutboxEntry) error {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, position := range history {
+		if err := r.insertHistory(ctx, tx, position); err != nil {
+			return err
+		}
+	}
+
+	// Usuários cuja posição atual já é mais recente não geram eventos (ver SaveWithEvents)
+	stale := make(map[string]bool)
+	for _, position := range current {
+		updated, err := r.updateCurrentPosition(ctx, tx, position)
+		if err != nil {
+			return fmt.Errorf("failed to update current position: %w", err)
+		}
+		if !updated {
+			userID := position.UserID()
+			stale[userID.Value()] = true
+		}
+	}
+
+	for _, entry := range outbox {
+		if stale[entry.Event.UserID] {
+			continue
+		}
+		if err := insertOutboxEntry(ctx, tx, entry); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.logger.Debug("Position batch saved successfully",
+		"history_count", len(history),
+		"current_count", len(current),
+		"outbox_events", len(outbox),
+	)
+
+	return nil
+}
+
+// insertHistory insere a posição na tabela positions (histórico)
+func (r *positionRepository) insertHistory(ctx context.Context, tx *sql.Tx, position *entity.Position) error {
+	posID := position.ID()
+	userID := position.UserID()
+
 	insertPosition := `
 		INSERT INTO positions (id, user_id, location, sector_x, sector_y, created_at)
-		VALUES ($1, $2, ST_GeomFromText($3, 4326), $4, $5, $6)
+		VALUES ($1, $2, ST_GeomFromWKB($3, 4326), $4, $5, $6)
 	`
 
-	_, err = tx.ExecContext(ctx, insertPosition,
+	_, err := tx.ExecContext(ctx, insertPosition,
 		posID.Value(),
 		userID.Value(),
-		position.Coordinate().ToWKT(),
+		position.Coordinate().ToWKB(),
 		position.SectorX(),
 		position.SectorY(),
 		position.RecordedAt().Time(),
@@ -63,49 +214,56 @@ func (r *positionRepository) Save(ctx context.Context, position *entity.Position
 		return fmt.Errorf("failed to insert position: %w", err)
 	}
 
-	// 2. Atualizar/inserir posição atual
-	if err := r.updateCurrentPosition(ctx, tx, position); err != nil {
-		return fmt.Errorf("failed to update current position: %w", err)
-	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	r.logger.Debug("Position saved successfully",
-		"position_id", posID.Value(),
-		"user_id", userID.Value(),
-	)
-
 	return nil
 }
 
 // updateCurrentPosition atualiza a tabela current_positions
-func (r *positionRepository) updateCurrentPosition(ctx context.Context, tx *sql.Tx, position *entity.Position) error {
+// A ordem é garantida no próprio upsert: uma posição com recorded_at anterior ao da atual não a
+// substitui (a menos que updateCurrentOnOutOfOrder). Retorna false quando a atual foi mantida
+func (r *positionRepository) updateCurrentPosition(ctx context.Context, tx *sql.Tx, position *entity.Position) (bool, error) {
 	posID := position.ID()
 	userID := position.UserID()
 
 	upsertCurrent := `
-		INSERT INTO current_positions (user_id, position_id, location, sector_x, sector_y, updated_at)
-		VALUES ($1, $2, ST_GeomFromText($3, 4326), $4, $5, $6)
+		INSERT INTO current_positions (user_id, position_id, location, sector_x, sector_y, recorded_at, updated_at)
+		VALUES ($1, $2, ST_GeomFromWKB($3, 4326), $4, $5, $6, $6)
 		ON CONFLICT (user_id) DO UPDATE SET
 			position_id = EXCLUDED.position_id,
 			location = EXCLUDED.location,
 			sector_x = EXCLUDED.sector_x,
 			sector_y = EXCLUDED.sector_y,
+			recorded_at = EXCLUDED.recorded_at,
 			updated_at = EXCLUDED.updated_at
 	`
+	if !r.updateCurrentOnOutOfOrder {
+		upsertCurrent += `		WHERE current_positions.recorded_at <= EXCLUDED.recorded_at
+	`
+	}
 
-	_, err := tx.ExecContext(ctx, upsertCurrent,
+	result, err := tx.ExecContext(ctx, upsertCurrent,
 		userID.Value(),
 		posID.Value(),
-		position.Coordinate().ToWKT(),
+		position.Coordinate().ToWKB(),
 		position.SectorX(),
 		position.SectorY(),
 		position.RecordedAt().Time(),
 	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		r.logger.Debug("Current position is more recent; position kept in history only",
+			"position_id", posID.Value(),
+			"user_id", userID.Value(),
+		)
+	}
 
-	return err
+	return affected > 0, nil
 }
 
 // FindByID busca posição por ID
@@ -127,16 +285,18 @@ func (r *positionRepository) FindByID(ctx context.Context, id entity.PositionID)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("position not found: %s", id.Value())
+			return nil, fmt.Errorf("%w: %s", entity.ErrPositionNotFound, id.Value())
 		}
 		return nil, fmt.Errorf("failed to find position %s: %w", id.Value(), err)
 	}
 
-	return r.scanToPosition(posID, userID, lat, lng, createdAt)
+	return r.scanToPosition(posID, userID, lat, lng, sectorX, sectorY, createdAt)
 }
 
 // FindCurrentByUserID busca posição atual de um usuário
 func (r *positionRepository) FindCurrentByUserID(ctx context.Context, userID entity.UserID) (*entity.Position, error) {
+	defer r.db.observeQuery("position.find_current", time.Now())
+
 	query := `
 		SELECT p.id, p.user_id, ST_X(p.location), ST_Y(p.location), p.sector_x, p.sector_y, p.created_at
 		FROM positions p
@@ -155,27 +315,93 @@ func (r *positionRepository) FindCurrentByUserID(ctx context.Context, userID ent
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("current position not found for user: %s", userID.Value())
+			return nil, fmt.Errorf("%w: no current position for user %s", entity.ErrPositionNotFound, userID.Value())
 		}
 		return nil, fmt.Errorf("failed to find current position for user %s: %w", userID.Value(), err)
 	}
 
-	return r.scanToPosition(posID, posUserID, lat, lng, createdAt)
+	return r.scanToPosition(posID, posUserID, lat, lng, sectorX, sectorY, createdAt)
+}
+
+// FindCurrentByUserIDs busca as posições atuais de vários usuários em uma única query
+// Usuários sem posição atual simplesmente não aparecem no resultado
+func (r *positionRepository) FindCurrentByUserIDs(ctx context.Context, userIDs []entity.UserID) ([]*entity.Position, error) {
+	if len(userIDs) == 0 {
+		return []*entity.Position{}, nil
+	}
+
+	defer r.db.observeQuery("position.find_current_many", time.Now())
+
+	query := `
+		SELECT p.id, p.user_id, ST_X(p.location), ST_Y(p.location), p.sector_x, p.sector_y, p.created_at
+		FROM positions p
+		INNER JOIN current_positions cp ON p.id = cp.position_id
+		WHERE cp.user_id IN (%s)
+	`
+
+	args := make([]interface{}, 0, len(userIDs))
+	placeholders := make([]string, 0, len(userIDs))
+	for i, userID := range userIDs {
+		placeholders = append(placeholders, fmt.Sprintf("$%d", i+1))
+		args = append(args, userID.Value())
+	}
+
+	query = fmt.Sprintf(query, strings.Join(placeholders, ", "))
+
+	rows, err := r.db.Connection().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find current positions for %d users: %w", len(userIDs), err)
+	}
+	defer rows.Close()
+
+	positions := make([]*entity.Position, 0, len(userIDs))
+
+	for rows.Next() {
+		var posID, posUserID string
+		var lat, lng float64
+		var sectorX, sectorY int
+		var createdAt time.Time
+
+		if err := rows.Scan(&posID, &posUserID, &lng, &lat, &sectorX, &sectorY, &createdAt); err != nil {
+			r.logger.Error("Failed to scan current position row", "error", err)
+			continue
+		}
+
+		position, err := r.scanToPosition(posID, posUserID, lat, lng, sectorX, sectorY, createdAt)
+		if err != nil {
+			r.logger.Error("Failed to reconstruct current position", "position_id", posID, "error", err)
+			continue
+		}
+
+		positions = append(positions, position)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate current positions: %w", err)
+	}
+
+	return positions, nil
 }
 
-// FindHistoryByUserID busca histórico de posições de um usuário
-func (r *positionRepository) FindHistoryByUserID(ctx context.Context, userID entity.UserID, limit int) ([]*entity.Position, error) {
+// FindHistoryByUserID busca uma página do histórico de posições de um usuário
+// O desempate por id mantém a ordem estável entre páginas
+func (r *positionRepository) FindHistoryByUserID(ctx context.Context, userID entity.UserID, limit, offset int) ([]*entity.Position, error) {
+	defer r.db.observeQuery("position.find_history", time.Now())
+
+	ctx, cancel := r.db.withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		SELECT id, user_id, ST_X(location), ST_Y(location), sector_x, sector_y, created_at
 		FROM positions
 		WHERE user_id = $1
-		ORDER BY created_at DESC
-		LIMIT $2
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.Connection().QueryContext(ctx, query, userID.Value(), limit)
+	rows, err := r.db.Connection().QueryContext(ctx, query, userID.Value(), limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find position history for user %s: %w", userID.Value(), err)
+		return nil, fmt.Errorf("failed to find position history for user %s: %w", userID.Value(), queryError(ctx, err))
 	}
 	defer rows.Close()
 
@@ -192,7 +418,7 @@ func (r *positionRepository) FindHistoryByUserID(ctx context.Context, userID ent
 			continue
 		}
 
-		position, err := r.scanToPosition(posID, posUserID, lat, lng, createdAt)
+		position, err := r.scanToPosition(posID, posUserID, lat, lng, sectorX, sectorY, createdAt)
 		if err != nil {
 			r.logger.Error("Failed to reconstruct position", "position_id", posID, "error", err)
 			continue
@@ -201,12 +427,191 @@ func (r *positionRepository) FindHistoryByUserID(ctx context.Context, userID ent
 		positions = append(positions, position)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate position history: %w", queryError(ctx, err))
+	}
+
 	return positions, nil
 }
 
-// FindNearby busca posições próximas usando PostGIS
-func (r *positionRepository) FindNearby(ctx context.Context, coord *valueobject.Coordinate, radiusMeters float64, limit int) ([]*entity.Position, error) {
+// FindHistoryByUserIDInRange busca o histórico de um usuário em um intervalo, em ordem cronológica
+func (r *positionRepository) FindHistoryByUserIDInRange(ctx context.Context, userID entity.UserID, from, to *valueobject.Timestamp, limit int) ([]*entity.Position, error) {
+	ctx, cancel := r.db.withQueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT id, user_id, ST_X(location), ST_Y(location), sector_x, sector_y, created_at
+		FROM positions
+		WHERE user_id = $1 AND created_at >= $2 AND created_at <= $3
+		ORDER BY created_at ASC, id ASC
+		LIMIT $4
+	`
+
+	rows, err := r.db.Connection().QueryContext(ctx, query, userID.Value(), from.Time(), to.Time(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find position history in range for user %s: %w", userID.Value(), queryError(ctx, err))
+	}
+	defer rows.Close()
+
+	positions := make([]*entity.Position, 0)
+
+	for rows.Next() {
+		var posID, posUserID string
+		var lat, lng float64
+		var sectorX, sectorY int
+		var createdAt time.Time
+
+		if err := rows.Scan(&posID, &posUserID, &lng, &lat, &sectorX, &sectorY, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan position: %w", err)
+		}
+
+		position, err := r.scanToPosition(posID, posUserID, lat, lng, sectorX, sectorY, createdAt)
+		if err != nil {
+			r.logger.Error("Failed to reconstruct position", "position_id", posID, "error", err)
+			continue
+		}
+
+		positions = append(positions, position)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", queryError(ctx, err))
+	}
+
+	return positions, nil
+}
+
+// FindHistoryByUserIDAfter busca uma página do histórico de um usuário em ordem cronológica
+// Paginação por chave (created_at, id): cada página é uma query curta, sem OFFSET crescente
+func (r *positionRepository) FindHistoryByUserIDAfter(ctx context.Context, userID entity.UserID, after *repository.HistoryCursor, limit int) ([]*entity.Position, error) {
+	defer r.db.observeQuery("position.find_history_after", time.Now())
+
+	ctx, cancel := r.db.withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
+		SELECT id, user_id, ST_X(location), ST_Y(location), sector_x, sector_y, created_at
+		FROM positions
+		WHERE user_id = $1
+		ORDER BY created_at ASC, id ASC
+		LIMIT $2
+	`
+	args := []interface{}{userID.Value(), limit}
+
+	if after != nil {
+		query = `
+		SELECT id, user_id, ST_X(location), ST_Y(location), sector_x, sector_y, created_at
+		FROM positions
+		WHERE user_id = $1 AND (created_at, id) > ($3, $4)
+		ORDER BY created_at ASC, id ASC
+		LIMIT $2
+	`
+		args = append(args, after.RecordedAt, after.PositionID)
+	}
+
+	rows, err := r.db.Connection().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find position history page for user %s: %w", userID.Value(), queryError(ctx, err))
+	}
+	defer rows.Close()
+
+	positions := make([]*entity.Position, 0, limit)
+
+	for rows.Next() {
+		var posID, posUserID string
+		var lat, lng float64
+		var sectorX, sectorY int
+		var createdAt time.Time
+
+		// Não pulamos linhas: o chamador monta o próximo cursor a partir da última posição
+		if err := rows.Scan(&posID, &posUserID, &lng, &lat, &sectorX, &sectorY, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan position row: %w", err)
+		}
+
+		position, err := r.scanToPosition(posID, posUserID, lat, lng, sectorX, sectorY, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconstruct position %s: %w", posID, err)
+		}
+
+		positions = append(positions, position)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating position rows: %w", queryError(ctx, err))
+	}
+
+	return positions, nil
+}
+
+// FindRecentActivity busca as posições atuais mais recentemente atualizadas, com o nome do usuário
+func (r *positionRepository) FindRecentActivity(ctx context.Context, since *valueobject.Timestamp, limit int) ([]*repository.RecentActivity, error) {
+	var sinceTime interface{}
+	if since != nil {
+		sinceTime = since.Time()
+	}
+
+	query := `
+		SELECT p.id, p.user_id, u.name, ST_X(p.location), ST_Y(p.location), p.sector_x, p.sector_y, p.created_at, cp.updated_at
+		FROM current_positions cp
+		INNER JOIN positions p ON p.id = cp.position_id
+		INNER JOIN users u ON u.id = cp.user_id
+		WHERE $1::timestamptz IS NULL OR cp.updated_at >= $1
+		ORDER BY cp.updated_at DESC, cp.user_id ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Connection().QueryContext(ctx, query, sinceTime, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find recent activity: %w", err)
+	}
+	defer rows.Close()
+
+	activity := make([]*repository.RecentActivity, 0)
+
+	for rows.Next() {
+		var posID, userID, userName string
+		var lat, lng float64
+		var sectorX, sectorY int
+		var createdAt, updatedAt time.Time
+
+		if err := rows.Scan(&posID, &userID, &userName, &lng, &lat, &sectorX, &sectorY, &createdAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan recent activity: %w", err)
+		}
+
+		position, err := r.scanToPosition(posID, userID, lat, lng, sectorX, sectorY, createdAt)
+		if err != nil {
+			r.logger.Error("Failed to reconstruct recent position", "position_id", posID, "error", err)
+			continue
+		}
+
+		activity = append(activity, &repository.RecentActivity{
+			UserName:  userName,
+			Position:  position,
+			UpdatedAt: valueobject.NewTimestamp(updatedAt),
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return activity, nil
+}
+
+// CountHistoryByUserID conta o total de posições no histórico de um usuário
+func (r *positionRepository) CountHistoryByUserID(ctx context.Context, userID entity.UserID) (int, error) {
+	query := `SELECT COUNT(*) FROM positions WHERE user_id = $1`
+
+	var total int
+	if err := r.db.Connection().QueryRowContext(ctx, query, userID.Value()).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count position history for user %s: %w", userID.Value(), err)
+	}
+
+	return total, nil
+}
+
+// findNearbyQuery filtra por ST_DWithin sobre geography, coberto por idx_positions_location_geography
+const findNearbyQuery = `
 		SELECT p.id, p.user_id, ST_X(p.location), ST_Y(p.location), p.sector_x, p.sector_y, p.created_at,
 			   ST_Distance(p.location::geography, ST_GeomFromText($1, 4326)::geography) as distance
 		FROM positions p
@@ -216,40 +621,107 @@ func (r *positionRepository) FindNearby(ctx context.Context, coord *valueobject.
 		LIMIT $3
 	`
 
-	rows, err := r.db.Connection().QueryContext(ctx, query, coord.ToWKT(), radiusMeters, limit)
+// FindNearby busca posições próximas usando PostGIS
+func (r *positionRepository) FindNearby(ctx context.Context, coord *valueobject.Coordinate, radiusMeters float64, limit int) ([]*repository.NearbyPosition, error) {
+	defer r.db.observeQuery("position.find_nearby", time.Now())
+
+	ctx, cancel := r.db.withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.Connection().QueryContext(ctx, findNearbyQuery, coord.ToWKT(), radiusMeters, limit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find nearby positions: %w", err)
+		return nil, fmt.Errorf("failed to find nearby positions: %w", queryError(ctx, err))
 	}
 	defer rows.Close()
 
-	positions := make([]*entity.Position, 0)
+	positions := make([]*repository.NearbyPosition, 0)
 
 	for rows.Next() {
 		var posID, userID string
 		var lat, lng float64
 		var sectorX, sectorY int
 		var createdAt time.Time
-		var distance float64
+		var distance sql.NullFloat64
 
 		if err := rows.Scan(&posID, &userID, &lng, &lat, &sectorX, &sectorY, &createdAt, &distance); err != nil {
 			r.logger.Error("Failed to scan nearby position row", "error", err)
 			continue
 		}
 
-		position, err := r.scanToPosition(posID, userID, lat, lng, createdAt)
+		position, err := r.scanToPosition(posID, userID, lat, lng, sectorX, sectorY, createdAt)
 		if err != nil {
 			r.logger.Error("Failed to reconstruct nearby position", "position_id", posID, "error", err)
 			continue
 		}
 
-		positions = append(positions, position)
+		nearby := &repository.NearbyPosition{Position: position}
+		if distance.Valid {
+			nearby.DistanceM = &distance.Float64
+		}
+		positions = append(positions, nearby)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate nearby positions: %w", queryError(ctx, err))
 	}
 
 	return positions, nil
 }
 
+// findUsersInRadiusQuery agrupa o histórico por usuário: cada um aparece uma vez, pela posição mais próxima
+const findUsersInRadiusQuery = `
+		SELECT p.user_id
+		FROM positions p
+		WHERE ST_DWithin(p.location::geography, ST_GeomFromText($1, 4326)::geography, $2)
+		GROUP BY p.user_id
+		ORDER BY MIN(ST_Distance(p.location::geography, ST_GeomFromText($1, 4326)::geography)), p.user_id
+	`
+
+// FindUsersInRadius busca os usuários distintos com alguma posição (histórico incluído) dentro do raio
+// Implementa repository.AdvancedPositionRepository; ordenado pela posição mais próxima de cada usuário
+func (r *positionRepository) FindUsersInRadius(ctx context.Context, coord *valueobject.Coordinate, radiusMeters float64) ([]entity.UserID, error) {
+	defer r.db.observeQuery("position.find_users_in_radius", time.Now())
+
+	ctx, cancel := r.db.withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.Connection().QueryContext(ctx, findUsersInRadiusQuery, coord.ToWKT(), radiusMeters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users in radius: %w", queryError(ctx, err))
+	}
+	defer rows.Close()
+
+	userIDs := make([]entity.UserID, 0)
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			r.logger.Error("Failed to scan user in radius row", "error", err)
+			continue
+		}
+
+		userID, err := entity.NewUserID(id)
+		if err != nil {
+			r.logger.Error("Invalid user ID in radius search", "user_id", id, "error", err)
+			continue
+		}
+		userIDs = append(userIDs, *userID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate users in radius: %w", queryError(ctx, err))
+	}
+
+	return userIDs, nil
+}
+
 // FindInSector busca posições em um setor específico
 func (r *positionRepository) FindInSector(ctx context.Context, sector *valueobject.Sector) ([]*entity.Position, error) {
+	defer r.db.observeQuery("position.find_in_sector", time.Now())
+
+	ctx, cancel := r.db.withQueryTimeout(ctx)
+	defer cancel()
+
 	query := `
 		SELECT p.id, p.user_id, ST_X(p.location), ST_Y(p.location), p.sector_x, p.sector_y, p.created_at
 		FROM positions p
@@ -259,7 +731,7 @@ func (r *positionRepository) FindInSector(ctx context.Context, sector *valueobje
 
 	rows, err := r.db.Connection().QueryContext(ctx, query, sector.X(), sector.Y())
 	if err != nil {
-		return nil, fmt.Errorf("failed to find positions in sector %s: %w", sector.ID(), err)
+		return nil, fmt.Errorf("failed to find positions in sector %s: %w", sector.ID(), queryError(ctx, err))
 	}
 	defer rows.Close()
 
@@ -276,7 +748,7 @@ func (r *positionRepository) FindInSector(ctx context.Context, sector *valueobje
 			continue
 		}
 
-		position, err := r.scanToPosition(posID, userID, lat, lng, createdAt)
+		position, err := r.scanToPosition(posID, userID, lat, lng, sectorX, sectorY, createdAt)
 		if err != nil {
 			r.logger.Error("Failed to reconstruct sector position", "position_id", posID, "error", err)
 			continue
@@ -285,24 +757,62 @@ func (r *positionRepository) FindInSector(ctx context.Context, sector *valueobje
 		positions = append(positions, position)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sector positions: %w", queryError(ctx, err))
+	}
+
 	return positions, nil
 }
 
 // FindInSectors busca posições em múltiplos setores
+// Listas grandes são divididas em chunks para respeitar o limite de parâmetros do PostgreSQL
 func (r *positionRepository) FindInSectors(ctx context.Context, sectors []*valueobject.Sector) ([]*entity.Position, error) {
 	if len(sectors) == 0 {
 		return []*entity.Position{}, nil
 	}
 
+	positions := make([]*entity.Position, 0)
+	seen := make(map[string]bool)
+
+	for start := 0; start < len(sectors); start += r.sectorChunkSize {
+		end := start + r.sectorChunkSize
+		if end > len(sectors) {
+			end = len(sectors)
+		}
+
+		chunk, err := r.findInSectorsChunk(ctx, sectors[start:end])
+		if err != nil {
+			return nil, err
+		}
+
+		// Mesclar resultados sem duplicar posições
+		for _, position := range chunk {
+			posID := position.ID()
+			if seen[posID.Value()] {
+				continue
+			}
+			seen[posID.Value()] = true
+			positions = append(positions, position)
+		}
+	}
+
+	return positions, nil
+}
+
+// findInSectorsChunk executa a query de FindInSectors para um único chunk de setores
+func (r *positionRepository) findInSectorsChunk(ctx context.Context, sectors []*valueobject.Sector) ([]*entity.Position, error) {
+	ctx, cancel := r.db.withQueryTimeout(ctx)
+	defer cancel()
+
 	// Construir query dinâmica com placeholders
 	query := `
 		SELECT p.id, p.user_id, ST_X(p.location), ST_Y(p.location), p.sector_x, p.sector_y, p.created_at
 		FROM positions p
 		INNER JOIN current_positions cp ON p.id = cp.position_id
-		WHERE (p.sector_x, p.sector_y) IN (
+		WHERE (p.sector_x, p.sector_y) IN (%s)
 	`
 
-	args := make([]interface{}, 0, len(sectors)*2)
+	args := make([]interface{}, 0, len(sectors)*paramsPerSector)
 	placeholders := make([]string, 0, len(sectors))
 
 	for i, sector := range sectors {
@@ -310,14 +820,11 @@ func (r *positionRepository) FindInSectors(ctx context.Context, sectors []*value
 		args = append(args, sector.X(), sector.Y())
 	}
 
-	query += fmt.Sprintf("%s)", fmt.Sprintf("%s", placeholders[0]))
-	for _, ph := range placeholders[1:] {
-		query += ", " + ph
-	}
+	query = fmt.Sprintf(query, strings.Join(placeholders, ", "))
 
 	rows, err := r.db.Connection().QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find positions in sectors: %w", err)
+		return nil, fmt.Errorf("failed to find positions in sectors: %w", queryError(ctx, err))
 	}
 	defer rows.Close()
 
@@ -334,7 +841,7 @@ func (r *positionRepository) FindInSectors(ctx context.Context, sectors []*value
 			continue
 		}
 
-		position, err := r.scanToPosition(posID, userID, lat, lng, createdAt)
+		position, err := r.scanToPosition(posID, userID, lat, lng, sectorX, sectorY, createdAt)
 		if err != nil {
 			r.logger.Error("Failed to reconstruct sectors position", "position_id", posID, "error", err)
 			continue
@@ -343,6 +850,10 @@ func (r *positionRepository) FindInSectors(ctx context.Context, sectors []*value
 		positions = append(positions, position)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sectors positions: %w", queryError(ctx, err))
+	}
+
 	return positions, nil
 }
 
@@ -354,7 +865,7 @@ func (r *positionRepository) UpdateCurrentPosition(ctx context.Context, position
 	}
 	defer tx.Rollback()
 
-	if err := r.updateCurrentPosition(ctx, tx, position); err != nil {
+	if _, err := r.updateCurrentPosition(ctx, tx, position); err != nil {
 		return err
 	}
 
@@ -362,39 +873,293 @@ func (r *positionRepository) UpdateCurrentPosition(ctx context.Context, position
 }
 
 // DeleteOldPositions remove posições antigas
+// Em uma única transação remove o histórico expirado e os ponteiros de current_positions
+// que ficaram órfãos ou desatualizados, evitando posições atuais apontando para o nada
 func (r *positionRepository) DeleteOldPositions(ctx context.Context, olderThan *valueobject.Timestamp) (int, error) {
-	query := `DELETE FROM positions WHERE created_at < $1`
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1. Remover histórico expirado
+	deleteHistory := `DELETE FROM positions WHERE created_at < $1`
 
-	result, err := r.db.Connection().ExecContext(ctx, query, olderThan.Time())
+	result, err := tx.ExecContext(ctx, deleteHistory, olderThan.Time())
 	if err != nil {
 		return 0, fmt.Errorf("failed to delete old positions: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	historyDeleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	// 2. Remover posições atuais órfãs ou mais antigas que o corte
+	deleteCurrent := `
+		DELETE FROM current_positions cp
+		WHERE cp.updated_at < $1
+		   OR NOT EXISTS (SELECT 1 FROM positions p WHERE p.id = cp.position_id)
+	`
+
+	result, err = tx.ExecContext(ctx, deleteCurrent, olderThan.Time())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete stale current positions: %w", err)
+	}
+
+	currentDeleted, err := result.RowsAffected()
 	if err != nil {
 		return 0, fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	r.logger.Info("Old positions deleted",
-		"count", rowsAffected,
+		"count", historyDeleted,
+		"current_positions_deleted", currentDeleted,
 		"older_than", olderThan.String(),
 	)
 
-	return int(rowsAffected), nil
+	return int(historyDeleted), nil
+}
+
+// FindPositionsAfterID lista posições do histórico em ordem de ID a partir de afterID
+// Paginação por chave: estável mesmo com as linhas sendo regravadas entre os chunks
+func (r *positionRepository) FindPositionsAfterID(ctx context.Context, afterID string, limit int) ([]*entity.Position, error) {
+	// id é UUID: a primeira página parte do UUID nulo, menor que qualquer ID ("" não é um UUID válido)
+	if afterID == "" {
+		afterID = uuid.Nil.String()
+	}
+
+	query := `
+		SELECT id, user_id, ST_X(location), ST_Y(location), sector_x, sector_y, created_at
+		FROM positions
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+	`
+
+	rows, err := r.db.Connection().QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list positions after %q: %w", afterID, err)
+	}
+	defer rows.Close()
+
+	positions := make([]*entity.Position, 0, limit)
+
+	for rows.Next() {
+		var posID, posUserID string
+		var lat, lng float64
+		var sectorX, sectorY int
+		var createdAt time.Time
+
+		// Diferente das buscas de leitura, não pulamos linhas: o chamador depende do último ID
+		if err := rows.Scan(&posID, &posUserID, &lng, &lat, &sectorX, &sectorY, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan position row: %w", err)
+		}
+
+		position, err := r.scanToPosition(posID, posUserID, lat, lng, sectorX, sectorY, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconstruct position %s: %w", posID, err)
+		}
+
+		positions = append(positions, position)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating position rows: %w", err)
+	}
+
+	return positions, nil
+}
+
+// UpdateSectors regrava sector_x/sector_y das posições no histórico e na posição atual
+// updated_at da posição atual é preservado: o trigger só o renova quando position_id muda
+func (r *positionRepository) UpdateSectors(ctx context.Context, positions []*entity.Position) error {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	updateHistory := `UPDATE positions SET sector_x = $2, sector_y = $3 WHERE id = $1`
+	updateCurrent := `UPDATE current_positions SET sector_x = $2, sector_y = $3 WHERE position_id = $1`
+
+	for _, position := range positions {
+		posID := position.ID()
+
+		if _, err := tx.ExecContext(ctx, updateHistory, posID.Value(), position.SectorX(), position.SectorY()); err != nil {
+			return fmt.Errorf("failed to update sector of position %s: %w", posID.Value(), err)
+		}
+
+		if _, err := tx.ExecContext(ctx, updateCurrent, posID.Value(), position.SectorX(), position.SectorY()); err != nil {
+			return fmt.Errorf("failed to update sector of current position %s: %w", posID.Value(), err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.logger.Debug("Position sectors updated", "count", len(positions))
+
+	return nil
+}
+
+// GetSectorStatistics retorna estatísticas de um setor
+// Usuários e última atividade vêm de current_positions; a contagem de posições inclui o histórico
+func (r *positionRepository) GetSectorStatistics(ctx context.Context, sector *valueobject.Sector) (*repository.SectorStats, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(DISTINCT user_id) FROM current_positions WHERE sector_x = $1 AND sector_y = $2),
+			(SELECT COUNT(*) FROM positions WHERE sector_x = $1 AND sector_y = $2),
+			(SELECT MAX(updated_at) FROM current_positions WHERE sector_x = $1 AND sector_y = $2)
+	`
+
+	var userCount, positionCount int
+	var lastActivity sql.NullTime
+
+	err := r.db.Connection().QueryRowContext(ctx, query, sector.X(), sector.Y()).Scan(
+		&userCount, &positionCount, &lastActivity,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statistics for sector %s: %w", sector.ID(), err)
+	}
+
+	stats := &repository.SectorStats{
+		Sector:        sector,
+		UserCount:     userCount,
+		PositionCount: positionCount,
+	}
+	if lastActivity.Valid {
+		stats.LastActivity = valueobject.NewTimestamp(lastActivity.Time)
+	}
+
+	return stats, nil
+}
+
+// FindOccupiedSectors conta os usuários com posição atual por setor
+// O filtro do retângulo usa o índice GIST de current_positions.location (operador &&)
+func (r *positionRepository) FindOccupiedSectors(ctx context.Context, bbox *repository.BoundingBox) ([]*repository.SectorOccupancy, error) {
+	defer r.db.observeQuery("position.find_occupied_sectors", time.Now())
+
+	filter := ""
+	var args []interface{}
+	if bbox != nil {
+		// Setores com algum usuário no retângulo; a contagem considera o setor inteiro
+		filter = `
+		WHERE (sector_x, sector_y) IN (
+			SELECT sector_x, sector_y
+			FROM current_positions
+			WHERE location && ST_MakeEnvelope($1, $2, $3, $4, 4326)
+		)`
+		args = []interface{}{bbox.MinLongitude, bbox.MinLatitude, bbox.MaxLongitude, bbox.MaxLatitude}
+	}
+
+	query := fmt.Sprintf(`
+		SELECT sector_x, sector_y, COUNT(DISTINCT user_id) AS user_count
+		FROM current_positions%s
+		GROUP BY sector_x, sector_y
+		ORDER BY user_count DESC, sector_x, sector_y
+	`, filter)
+
+	rows, err := r.db.Connection().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find occupied sectors: %w", err)
+	}
+	defer rows.Close()
+
+	occupied := make([]*repository.SectorOccupancy, 0)
+
+	for rows.Next() {
+		var sectorX, sectorY, userCount int
+		if err := rows.Scan(&sectorX, &sectorY, &userCount); err != nil {
+			return nil, fmt.Errorf("failed to scan occupied sector: %w", err)
+		}
+
+		sector, err := valueobject.NewSector(sectorX, sectorY)
+		if err != nil {
+			r.logger.Error("Invalid stored sector", "sector_x", sectorX, "sector_y", sectorY, "error", err)
+			continue
+		}
+
+		occupied = append(occupied, &repository.SectorOccupancy{
+			Sector:    sector,
+			UserCount: userCount,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return occupied, nil
+}
+
+// findInBoundingBoxQuery filtra current_positions pelo operador &&, coberto por idx_current_positions_location
+const findInBoundingBoxQuery = `
+		SELECT p.id, p.user_id, ST_X(p.location), ST_Y(p.location), p.sector_x, p.sector_y, p.created_at
+		FROM current_positions cp
+		INNER JOIN positions p ON p.id = cp.position_id
+		WHERE cp.location && ST_MakeEnvelope($1, $2, $3, $4, 4326)
+		ORDER BY cp.updated_at DESC, cp.user_id ASC
+		LIMIT $5
+	`
+
+// FindInBoundingBox busca as posições atuais dentro de um retângulo
+func (r *positionRepository) FindInBoundingBox(ctx context.Context, bbox *repository.BoundingBox, limit int) ([]*entity.Position, error) {
+	defer r.db.observeQuery("position.find_in_bounding_box", time.Now())
+
+	rows, err := r.db.Connection().QueryContext(ctx, findInBoundingBoxQuery,
+		bbox.MinLongitude, bbox.MinLatitude, bbox.MaxLongitude, bbox.MaxLatitude, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find positions in bounding box: %w", err)
+	}
+	defer rows.Close()
+
+	positions := make([]*entity.Position, 0)
+
+	for rows.Next() {
+		var posID, userID string
+		var lat, lng float64
+		var sectorX, sectorY int
+		var createdAt time.Time
+
+		if err := rows.Scan(&posID, &userID, &lng, &lat, &sectorX, &sectorY, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan bounding box position: %w", err)
+		}
+
+		position, err := r.scanToPosition(posID, userID, lat, lng, sectorX, sectorY, createdAt)
+		if err != nil {
+			r.logger.Error("Failed to reconstruct bounding box position", "position_id", posID, "error", err)
+			continue
+		}
+
+		positions = append(positions, position)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return positions, nil
 }
 
 // scanToPosition converte dados do banco para entidade Position
-func (r *positionRepository) scanToPosition(posID, userID string, lat, lng float64, recordedAt time.Time) (*entity.Position, error) {
+// As colunas sector_x/sector_y são usadas como estão (o banco é a fonte da verdade)
+func (r *positionRepository) scanToPosition(posID, userID string, lat, lng float64, sectorX, sectorY int, recordedAt time.Time) (*entity.Position, error) {
 	// Reconstruir UserID
 	uid, err := entity.NewUserID(userID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid user ID: %w", err)
 	}
 
-	// Criar posição
-	position, err := entity.NewPosition(posID, *uid, lat, lng, recordedAt)
+	// Reconstruir posição (created_at da tabela guarda o momento do registro)
+	position, err := entity.ReconstructPosition(posID, *uid, lat, lng, sectorX, sectorY, recordedAt, recordedAt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create position: %w", err)
+		return nil, fmt.Errorf("failed to reconstruct position: %w", err)
 	}
 
 	return position, nil
@@ -0,0 +1,842 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/events"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+)
+
+// positionColumns são as colunas retornadas pelas queries de leitura de posição
+var positionColumns = []string{"id", "user_id", "st_x", "st_y", "sector_x", "sector_y", "created_at"}
+
+// TestPositionRepository_FindByIDUsesStoredSector testa que o setor vem das colunas do banco
+func TestPositionRepository_FindByIDUsesStoredSector(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	recordedAt := time.Now().Add(-72 * time.Hour).UTC()
+	rows := sqlmock.NewRows(positionColumns).
+		AddRow("pos-1", "user123", -46.633309, -23.550520, 42, 24, recordedAt)
+	mock.ExpectQuery("FROM positions").
+		WithArgs("pos-1").
+		WillReturnRows(rows)
+
+	positionID, err := entity.NewPositionID("pos-1")
+	require.NoError(t, err)
+
+	position, err := repo.FindByID(context.Background(), *positionID)
+	require.NoError(t, err)
+
+	assert.Equal(t, 42, position.SectorX())
+	assert.Equal(t, 24, position.SectorY())
+	assert.InDelta(t, -23.550520, position.Latitude(), 1e-9)
+	assert.InDelta(t, -46.633309, position.Longitude(), 1e-9)
+	assert.True(t, position.RecordedAt().Time().Equal(recordedAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_FindByIDNotFound testa o erro sentinela para posição inexistente
+func TestPositionRepository_FindByIDNotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	mock.ExpectQuery("FROM positions").
+		WithArgs("pos-missing").
+		WillReturnRows(sqlmock.NewRows(positionColumns))
+
+	positionID, err := entity.NewPositionID("pos-missing")
+	require.NoError(t, err)
+
+	_, err = repo.FindByID(context.Background(), *positionID)
+	assert.ErrorIs(t, err, entity.ErrPositionNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_FindNearbyReturnsDatabaseDistance testa que o ST_Distance do banco acompanha cada posição
+func TestPositionRepository_FindNearbyReturnsDatabaseDistance(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	recordedAt := time.Now().Add(-time.Minute).UTC()
+	rows := sqlmock.NewRows(append(positionColumns, "distance")).
+		AddRow("pos-1", "user123", -46.633309, -23.550520, 42, 24, recordedAt, 12.5).
+		AddRow("pos-2", "user456", -46.633400, -23.550600, 42, 24, recordedAt, nil)
+	mock.ExpectQuery("ST_DWithin").
+		WithArgs(sqlmock.AnyArg(), 500.0, 10).
+		WillReturnRows(rows)
+
+	center, err := valueobject.NewCoordinate(-23.550520, -46.633309)
+	require.NoError(t, err)
+
+	nearby, err := repo.FindNearby(context.Background(), center, 500, 10)
+	require.NoError(t, err)
+	require.Len(t, nearby, 2)
+
+	require.NotNil(t, nearby[0].DistanceM)
+	assert.Equal(t, 12.5, *nearby[0].DistanceM)
+	assert.Equal(t, 12.5, nearby[0].Distance(center))
+
+	assert.Nil(t, nearby[1].DistanceM)
+	assert.InDelta(t, center.DistanceTo(nearby[1].Position.Coordinate()), nearby[1].Distance(center), 1e-9)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_FindUsersInRadius testa que o histórico é agrupado em usuários distintos
+func TestPositionRepository_FindUsersInRadius(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{}).(*positionRepository)
+
+	mock.ExpectQuery(`GROUP BY p.user_id`).
+		WithArgs(sqlmock.AnyArg(), 250.0).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow("user123").AddRow("user456"))
+
+	center, err := valueobject.NewCoordinate(-23.550520, -46.633309)
+	require.NoError(t, err)
+
+	userIDs, err := repo.FindUsersInRadius(context.Background(), center, 250)
+
+	require.NoError(t, err)
+	require.Len(t, userIDs, 2)
+	assert.Equal(t, "user123", userIDs[0].Value())
+	assert.Equal(t, "user456", userIDs[1].Value())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_FindInSectorsQuery testa a construção do IN dinâmico e a ordem dos argumentos
+func TestPositionRepository_FindInSectorsQuery(t *testing.T) {
+	testCases := []struct {
+		name         string
+		coords       [][2]int
+		wantInClause string
+	}{
+		{
+			name:         "um setor",
+			coords:       [][2]int{{1, 2}},
+			wantInClause: "IN (($1, $2))",
+		},
+		{
+			name:         "dois setores",
+			coords:       [][2]int{{1, 2}, {3, 4}},
+			wantInClause: "IN (($1, $2), ($3, $4))",
+		},
+		{
+			name:         "três setores",
+			coords:       [][2]int{{1, 2}, {3, 4}, {-5, 6}},
+			wantInClause: "IN (($1, $2), ($3, $4), ($5, $6))",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := newTestDB(t)
+			repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+			sectors := make([]*valueobject.Sector, 0, len(tc.coords))
+			args := make([]driver.Value, 0, len(tc.coords)*2)
+			for _, c := range tc.coords {
+				sector, err := valueobject.NewSector(c[0], c[1])
+				require.NoError(t, err)
+				sectors = append(sectors, sector)
+				args = append(args, c[0], c[1])
+			}
+
+			mock.ExpectQuery(regexp.QuoteMeta(tc.wantInClause)).
+				WithArgs(args...).
+				WillReturnRows(sqlmock.NewRows(positionColumns))
+
+			positions, err := repo.FindInSectors(context.Background(), sectors)
+			require.NoError(t, err)
+
+			assert.Empty(t, positions)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestPositionRepository_FindInSectorsChunking testa a divisão em múltiplas queries e o merge sem duplicados
+func TestPositionRepository_FindInSectorsChunking(t *testing.T) {
+	db, mock := newTestDB(t)
+	cfg := &config.Config{Database: config.DatabaseConfig{SectorQueryChunkSize: 2}}
+	repo := NewPositionRepository(db, cfg, nopLogger{})
+
+	// 5 setores com chunk de 2 => 3 queries
+	sectors := make([]*valueobject.Sector, 0, 5)
+	for x := 0; x < 5; x++ {
+		sector, err := valueobject.NewSector(x, 0)
+		require.NoError(t, err)
+		sectors = append(sectors, sector)
+	}
+
+	now := time.Now().UTC()
+	mock.ExpectQuery(regexp.QuoteMeta("IN (($1, $2), ($3, $4))")).
+		WithArgs(0, 0, 1, 0).
+		WillReturnRows(sqlmock.NewRows(positionColumns).
+			AddRow("pos-a", "user-a", 0.0, 0.0, 0, 0, now).
+			AddRow("pos-b", "user-b", 0.0009, 0.0, 1, 0, now))
+	mock.ExpectQuery(regexp.QuoteMeta("IN (($1, $2), ($3, $4))")).
+		WithArgs(2, 0, 3, 0).
+		WillReturnRows(sqlmock.NewRows(positionColumns).
+			AddRow("pos-b", "user-b", 0.0009, 0.0, 1, 0, now). // Duplicado entre chunks
+			AddRow("pos-c", "user-c", 0.0018, 0.0, 2, 0, now))
+	mock.ExpectQuery(regexp.QuoteMeta("IN (($1, $2))")).
+		WithArgs(4, 0).
+		WillReturnRows(sqlmock.NewRows(positionColumns).
+			AddRow("pos-d", "user-d", 0.0036, 0.0, 4, 0, now))
+
+	positions, err := repo.FindInSectors(context.Background(), sectors)
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(positions))
+	for _, position := range positions {
+		posID := position.ID()
+		ids = append(ids, posID.Value())
+	}
+
+	assert.Equal(t, []string{"pos-a", "pos-b", "pos-c", "pos-d"}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestNewPositionRepository_ChunkSizeBounds testa os limites do chunk configurado
+func TestNewPositionRepository_ChunkSizeBounds(t *testing.T) {
+	db, _ := newTestDB(t)
+
+	testCases := []struct {
+		name       string
+		configured int
+		want       int
+	}{
+		{name: "não configurado usa o máximo", configured: 0, want: maxSectorsPerQuery},
+		{name: "acima do limite do PostgreSQL", configured: 40000, want: maxSectorsPerQuery},
+		{name: "valor válido", configured: 100, want: 100},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config.Config{Database: config.DatabaseConfig{SectorQueryChunkSize: tc.configured}}
+			repo := NewPositionRepository(db, cfg, nopLogger{}).(*positionRepository)
+
+			assert.Equal(t, tc.want, repo.sectorChunkSize)
+		})
+	}
+}
+
+// TestPositionRepository_DeleteOldPositionsCleansCurrent testa a limpeza transacional de current_positions
+func TestPositionRepository_DeleteOldPositionsCleansCurrent(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	cutoff := valueobject.NewTimestamp(time.Now().Add(-24 * time.Hour))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM positions WHERE created_at < $1")).
+		WithArgs(cutoff.Time()).
+		WillReturnResult(sqlmock.NewResult(0, 7))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM current_positions cp")).
+		WithArgs(cutoff.Time()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	deleted, err := repo.DeleteOldPositions(context.Background(), cutoff)
+	require.NoError(t, err)
+
+	assert.Equal(t, 7, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_DeleteOldPositionsRollback testa que uma falha na limpeza desfaz tudo
+func TestPositionRepository_DeleteOldPositionsRollback(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	cutoff := valueobject.NewTimestamp(time.Now().Add(-24 * time.Hour))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM positions WHERE created_at < $1")).
+		WithArgs(cutoff.Time()).
+		WillReturnResult(sqlmock.NewResult(0, 7))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM current_positions cp")).
+		WithArgs(cutoff.Time()).
+		WillReturnError(errors.New("lock timeout"))
+	mock.ExpectRollback()
+
+	deleted, err := repo.DeleteOldPositions(context.Background(), cutoff)
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_SaveHistorySkipsCurrent testa que apenas o histórico é gravado
+func TestPositionRepository_SaveHistorySkipsCurrent(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+	position, err := entity.NewPosition("pos-1", *userID, -23.550520, -46.633309, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("ST_GeomFromWKB($3, 4326)")).
+		WithArgs("pos-1", "user123", position.Coordinate().ToWKB(), position.SectorX(), position.SectorY(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.SaveHistory(context.Background(), position))
+	// Nenhuma escrita em current_positions é esperada
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_SaveWithEvents testa que a posição e a outbox são gravadas na mesma transação
+func TestPositionRepository_SaveWithEvents(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+	position, err := entity.NewPosition("pos-1", *userID, -23.550520, -46.633309, time.Now())
+	require.NoError(t, err)
+
+	event := events.NewPositionChangedEvent("user123", "req-1", events.PositionChangedData{PositionID: "pos-1"})
+	outbox := []*repository.OutboxEntry{{Stream: events.StreamPositionEvents, Event: event}}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO positions")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO current_positions")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO event_outbox")).
+		WithArgs(events.StreamPositionEvents, "position.changed", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(7)))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.SaveWithEvents(context.Background(), position, outbox))
+	assert.Equal(t, int64(7), outbox[0].ID)
+	assert.NotEmpty(t, event.ID, "event ID is fixed before the first publish attempt")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_SaveWithEventsRollback testa que uma falha na outbox desfaz a posição
+func TestPositionRepository_SaveWithEventsRollback(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+	position, err := entity.NewPosition("pos-1", *userID, -23.550520, -46.633309, time.Now())
+	require.NoError(t, err)
+
+	event := events.NewPositionChangedEvent("user123", "req-1", events.PositionChangedData{PositionID: "pos-1"})
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO positions")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO current_positions")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO event_outbox")).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	err = repo.SaveWithEvents(context.Background(), position, []*repository.OutboxEntry{
+		{Stream: events.StreamPositionEvents, Event: event},
+	})
+	assert.ErrorContains(t, err, "failed to insert outbox event")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_SaveWithEventsKeepsNewerCurrent testa que o upsert não substitui uma posição
+// atual mais recente e que, nesse caso, nenhum evento vai para a outbox
+func TestPositionRepository_SaveWithEventsKeepsNewerCurrent(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+	position, err := entity.NewPosition("pos-1", *userID, -23.550520, -46.633309, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	event := events.NewPositionChangedEvent("user123", "req-1", events.PositionChangedData{PositionID: "pos-1"})
+	outbox := []*repository.OutboxEntry{{Stream: events.StreamPositionEvents, Event: event}}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO positions")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// Outra requisição já gravou uma posição mais recente: o WHERE do ON CONFLICT não atualiza nada
+	mock.ExpectExec(regexp.QuoteMeta("WHERE current_positions.recorded_at <= EXCLUDED.recorded_at")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.SaveWithEvents(context.Background(), position, outbox))
+	assert.Zero(t, outbox[0].ID, "no outbox event for a position that did not become current")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_UpdateCurrentOnOutOfOrder testa que o upsert sem a checagem de ordem é usado
+// quando POSITIONS_UPDATE_CURRENT_ON_OUT_OF_ORDER está habilitado
+func TestPositionRepository_UpdateCurrentOnOutOfOrder(t *testing.T) {
+	db, mock := newTestDB(t)
+	cfg := &config.Config{Positions: config.PositionsConfig{UpdateCurrentOnOutOfOrder: true}}
+	repo := NewPositionRepository(db, cfg, nopLogger{})
+
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+	position, err := entity.NewPosition("pos-1", *userID, -23.550520, -46.633309, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`updated_at = EXCLUDED\.updated_at$`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.UpdateCurrentPosition(context.Background(), position))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_SaveBatch testa histórico completo, posição atual e outbox em uma única transação
+func TestPositionRepository_SaveBatch(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+	older, err := entity.NewPosition("pos-1", *userID, -23.550520, -46.633309, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	latest, err := entity.NewPosition("pos-2", *userID, -23.551000, -46.634000, time.Now())
+	require.NoError(t, err)
+
+	event := events.NewPositionChangedEvent("user123", "req-1", events.PositionChangedData{PositionID: "pos-2"})
+	outbox := []*repository.OutboxEntry{{Stream: events.StreamPositionEvents, Event: event}}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO positions")).
+		WithArgs("pos-1", "user123", sqlmock.AnyArg(), older.SectorX(), older.SectorY(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO positions")).
+		WithArgs("pos-2", "user123", sqlmock.AnyArg(), latest.SectorX(), latest.SectorY(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO current_positions")).
+		WithArgs("user123", "pos-2", sqlmock.AnyArg(), latest.SectorX(), latest.SectorY(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO event_outbox")).
+		WithArgs(events.StreamPositionEvents, "position.changed", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(9)))
+	mock.ExpectCommit()
+
+	err = repo.SaveBatch(context.Background(), []*entity.Position{older, latest}, []*entity.Position{latest}, outbox)
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), outbox[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_SaveBatchRollback testa que uma falha desfaz todo o lote
+func TestPositionRepository_SaveBatchRollback(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+	position, err := entity.NewPosition("pos-1", *userID, -23.550520, -46.633309, time.Now())
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO positions")).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	err = repo.SaveBatch(context.Background(), []*entity.Position{position}, []*entity.Position{position}, nil)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_FindPositionsAfterID testa a paginação por chave usada no recálculo de setores
+func TestPositionRepository_FindPositionsAfterID(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	recordedAt := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE id > $1")).
+		WithArgs("pos-1", 2).
+		WillReturnRows(sqlmock.NewRows(positionColumns).
+			AddRow("pos-2", "user123", -46.633309, -23.550520, 1, 1, recordedAt).
+			AddRow("pos-3", "user123", -46.633309, -23.550520, 1, 1, recordedAt))
+
+	positions, err := repo.FindPositionsAfterID(context.Background(), "pos-1", 2)
+	require.NoError(t, err)
+	require.Len(t, positions, 2)
+	// Setores armazenados são preservados, mesmo divergentes do esquema atual
+	assert.Equal(t, 1, positions[0].SectorX())
+	lastID := positions[1].ID()
+	assert.Equal(t, "pos-3", lastID.Value())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_FindPositionsAfterIDFirstPage testa que a primeira página usa o UUID nulo, e não "", na comparação com id
+func TestPositionRepository_FindPositionsAfterIDFirstPage(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE id > $1")).
+		WithArgs("00000000-0000-0000-0000-000000000000", 2).
+		WillReturnRows(sqlmock.NewRows(positionColumns).
+			AddRow("pos-1", "user123", -46.633309, -23.550520, 1, 1, time.Now()))
+
+	positions, err := repo.FindPositionsAfterID(context.Background(), "", 2)
+	require.NoError(t, err)
+	require.Len(t, positions, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_UpdateSectors testa a regravação no histórico e na posição atual
+func TestPositionRepository_UpdateSectors(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+	position, err := entity.NewPosition("pos-1", *userID, -23.550520, -46.633309, time.Now())
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE positions SET sector_x")).
+		WithArgs("pos-1", position.SectorX(), position.SectorY()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE current_positions SET sector_x")).
+		WithArgs("pos-1", position.SectorX(), position.SectorY()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.UpdateSectors(context.Background(), []*entity.Position{position}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_FindHistoryByUserIDOffset testa que limit e offset vão para a query
+func TestPositionRepository_FindHistoryByUserIDOffset(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("LIMIT $2 OFFSET $3")).
+		WithArgs("user123", 10, 20).
+		WillReturnRows(sqlmock.NewRows(positionColumns).
+			AddRow("pos-21", "user123", -46.633309, -23.550520, 1, 1, time.Now()))
+
+	positions, err := repo.FindHistoryByUserID(context.Background(), *userID, 10, 20)
+	require.NoError(t, err)
+	assert.Len(t, positions, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_FindCurrentByUserIDs testa a query única com IN e a omissão de quem não tem posição
+func TestPositionRepository_FindCurrentByUserIDs(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	userIDs := make([]entity.UserID, 0, 3)
+	for _, id := range []string{"user-1", "user-2", "user-3"} {
+		userID, err := entity.NewUserID(id)
+		require.NoError(t, err)
+		userIDs = append(userIDs, *userID)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE cp.user_id IN ($1, $2, $3)")).
+		WithArgs("user-1", "user-2", "user-3").
+		WillReturnRows(sqlmock.NewRows(positionColumns).
+			AddRow("pos-1", "user-1", -46.633309, -23.550520, 1, 1, time.Now()).
+			AddRow("pos-3", "user-3", -46.633400, -23.550600, 1, 1, time.Now()))
+
+	positions, err := repo.FindCurrentByUserIDs(context.Background(), userIDs)
+	require.NoError(t, err)
+	assert.Len(t, positions, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_FindCurrentByUserIDsEmpty testa que uma lista vazia não vai ao banco
+func TestPositionRepository_FindCurrentByUserIDsEmpty(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	positions, err := repo.FindCurrentByUserIDs(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, positions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_FindHistoryByUserIDInRange testa o intervalo e a ordem cronológica
+func TestPositionRepository_FindHistoryByUserIDInRange(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+
+	from := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	mock.ExpectQuery(regexp.QuoteMeta("ORDER BY created_at ASC, id ASC")).
+		WithArgs("user123", from, to, 500).
+		WillReturnRows(sqlmock.NewRows(positionColumns).
+			AddRow("pos-1", "user123", -46.633309, -23.550520, 1, 1, from.Add(time.Minute)).
+			AddRow("pos-2", "user123", -46.633309, -23.550520, 1, 1, from.Add(2*time.Minute)))
+
+	positions, err := repo.FindHistoryByUserIDInRange(context.Background(), *userID,
+		valueobject.NewTimestamp(from), valueobject.NewTimestamp(to), 500)
+	require.NoError(t, err)
+	assert.Len(t, positions, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_FindHistoryByUserIDAfter testa a primeira página e a continuação pelo cursor
+func TestPositionRepository_FindHistoryByUserIDAfter(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+
+	first := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE user_id = $1\n")).
+		WithArgs("user123", 2).
+		WillReturnRows(sqlmock.NewRows(positionColumns).
+			AddRow("pos-1", "user123", -46.633309, -23.550520, 1, 1, first).
+			AddRow("pos-2", "user123", -46.633309, -23.550520, 1, 1, first.Add(time.Minute)))
+	mock.ExpectQuery(regexp.QuoteMeta("(created_at, id) > ($3, $4)")).
+		WithArgs("user123", 2, first.Add(time.Minute), "pos-2").
+		WillReturnRows(sqlmock.NewRows(positionColumns).
+			AddRow("pos-3", "user123", -46.633309, -23.550520, 1, 1, first.Add(2*time.Minute)))
+
+	page, err := repo.FindHistoryByUserIDAfter(context.Background(), *userID, nil, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+
+	page, err = repo.FindHistoryByUserIDAfter(context.Background(), *userID, repository.NewHistoryCursor(page[1]), 2)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	lastID := page[0].ID()
+	assert.Equal(t, "pos-3", lastID.Value())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_FindRecentActivity testa a ordem por updated_at e o limite do feed
+func TestPositionRepository_FindRecentActivity(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	newest := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	columns := []string{"id", "user_id", "name", "lng", "lat", "sector_x", "sector_y", "created_at", "updated_at"}
+
+	mock.ExpectQuery(regexp.QuoteMeta("ORDER BY cp.updated_at DESC, cp.user_id ASC")).
+		WithArgs(nil, 2).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("pos-1", "user-a", "Ana", -46.633309, -23.550520, 1, 1, newest, newest).
+			AddRow("pos-2", "user-b", "Bruno", -46.633309, -23.550520, 1, 1, newest, newest.Add(-time.Minute)))
+
+	activity, err := repo.FindRecentActivity(context.Background(), nil, 2)
+	require.NoError(t, err)
+	require.Len(t, activity, 2)
+	assert.Equal(t, "Ana", activity[0].UserName)
+	assert.True(t, activity[0].UpdatedAt.Time().After(activity[1].UpdatedAt.Time()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_FindRecentActivitySince testa o corte de posições paradas
+func TestPositionRepository_FindRecentActivitySince(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	since := time.Date(2024, 1, 15, 9, 50, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("cp.updated_at >= $1")).
+		WithArgs(since, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "lng", "lat", "sector_x", "sector_y", "created_at", "updated_at"}))
+
+	activity, err := repo.FindRecentActivity(context.Background(), valueobject.NewTimestamp(since), 20)
+	require.NoError(t, err)
+	assert.Empty(t, activity)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_FindOccupiedSectors testa a agregação por setor sem retângulo
+func TestPositionRepository_FindOccupiedSectors(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	mock.ExpectQuery(`SELECT sector_x, sector_y, COUNT\(DISTINCT user_id\) AS user_count\s+FROM current_positions\s+GROUP BY`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"sector_x", "sector_y", "user_count"}).
+			AddRow(-518, 2616, 3).
+			AddRow(7, -3, 1))
+
+	occupied, err := repo.FindOccupiedSectors(context.Background(), nil)
+	require.NoError(t, err)
+	require.Len(t, occupied, 2)
+	assert.Equal(t, "sector_-518_2616", occupied[0].Sector.ID())
+	assert.Equal(t, 3, occupied[0].UserCount)
+	assert.Equal(t, "sector_7_-3", occupied[1].Sector.ID())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_FindOccupiedSectorsInBoundingBox testa o envelope na ordem (lng, lat)
+func TestPositionRepository_FindOccupiedSectorsInBoundingBox(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	bbox := &repository.BoundingBox{MinLatitude: -23.6, MaxLatitude: -23.5, MinLongitude: -46.7, MaxLongitude: -46.6}
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE location && ST_MakeEnvelope($1, $2, $3, $4, 4326)")).
+		WithArgs(-46.7, -23.6, -46.6, -23.5).
+		WillReturnRows(sqlmock.NewRows([]string{"sector_x", "sector_y", "user_count"}))
+
+	occupied, err := repo.FindOccupiedSectors(context.Background(), bbox)
+	require.NoError(t, err)
+	assert.Empty(t, occupied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_FindInBoundingBox testa o envelope na ordem (lng, lat) e o limite
+func TestPositionRepository_FindInBoundingBox(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	recordedAt := time.Now().Add(-time.Minute).UTC()
+	bbox := &repository.BoundingBox{MinLatitude: -23.6, MaxLatitude: -23.5, MinLongitude: -46.7, MaxLongitude: -46.6}
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE cp.location && ST_MakeEnvelope($1, $2, $3, $4, 4326)")).
+		WithArgs(-46.7, -23.6, -46.6, -23.5, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "lng", "lat", "sector_x", "sector_y", "created_at"}).
+			AddRow("pos-1", "user-1", -46.633309, -23.550520, -518, 2616, recordedAt))
+
+	positions, err := repo.FindInBoundingBox(context.Background(), bbox, 50)
+	require.NoError(t, err)
+	require.Len(t, positions, 1)
+	positionID, userID := positions[0].ID(), positions[0].UserID()
+	assert.Equal(t, "pos-1", positionID.Value())
+	assert.Equal(t, "user-1", userID.Value())
+	assert.InDelta(t, -23.550520, positions[0].Coordinate().Latitude(), 1e-9)
+	assert.InDelta(t, -46.633309, positions[0].Coordinate().Longitude(), 1e-9)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_CountHistoryByUserID testa o total do histórico
+func TestPositionRepository_CountHistoryByUserID(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM positions WHERE user_id = $1")).
+		WithArgs("user123").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+	total, err := repo.CountHistoryByUserID(context.Background(), *userID)
+	require.NoError(t, err)
+	assert.Equal(t, 42, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_GetSectorStatistics testa contagens e última atividade do setor
+func TestPositionRepository_GetSectorStatistics(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	sector, err := valueobject.NewSector(10, -20)
+	require.NoError(t, err)
+
+	lastActivity := time.Now().UTC().Truncate(time.Second)
+	mock.ExpectQuery(regexp.QuoteMeta("COUNT(DISTINCT user_id) FROM current_positions")).
+		WithArgs(10, -20).
+		WillReturnRows(sqlmock.NewRows([]string{"users", "positions", "last_activity"}).
+			AddRow(3, 42, lastActivity))
+
+	stats, err := repo.GetSectorStatistics(context.Background(), sector)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.UserCount)
+	assert.Equal(t, 42, stats.PositionCount)
+	require.NotNil(t, stats.LastActivity)
+	assert.True(t, lastActivity.Equal(stats.LastActivity.Time()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_GetSectorStatisticsEmpty testa setor sem usuários (MAX retorna NULL)
+func TestPositionRepository_GetSectorStatisticsEmpty(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	sector, err := valueobject.NewSector(0, 0)
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("COUNT(DISTINCT user_id) FROM current_positions")).
+		WithArgs(0, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"users", "positions", "last_activity"}).
+			AddRow(0, 0, nil))
+
+	stats, err := repo.GetSectorStatistics(context.Background(), sector)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.UserCount)
+	assert.Nil(t, stats.LastActivity)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPositionRepository_QueryTimeout testa que uma query travada é interrompida pelo prazo e vira ErrQueryTimeout
+func TestPositionRepository_QueryTimeout(t *testing.T) {
+	db, mock := newTestDB(t)
+	db.queryTimeout = 20 * time.Millisecond
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	// O banco falso só responderia depois de 5s
+	mock.ExpectQuery("ST_DWithin").
+		WillDelayFor(5 * time.Second).
+		WillReturnRows(sqlmock.NewRows(append(positionColumns, "distance")))
+	mock.ExpectQuery("FROM positions").
+		WillDelayFor(5 * time.Second).
+		WillReturnRows(sqlmock.NewRows(positionColumns))
+
+	center, err := valueobject.NewCoordinate(-23.550520, -46.633309)
+	require.NoError(t, err)
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+
+	start := time.Now()
+	nearby, err := repo.FindNearby(context.Background(), center, 500, 10)
+	assert.Nil(t, nearby)
+	assert.ErrorIs(t, err, repository.ErrQueryTimeout)
+
+	history, err := repo.FindHistoryByUserID(context.Background(), *userID, 10, 0)
+	assert.Nil(t, history)
+	assert.ErrorIs(t, err, repository.ErrQueryTimeout)
+
+	assert.Less(t, time.Since(start), 2*time.Second, "queries must not wait for the blocked database")
+}
+
+// TestPositionRepository_CanceledRequestIsNotTimeout testa que o cancelamento pelo cliente não é tratado como timeout
+func TestPositionRepository_CanceledRequestIsNotTimeout(t *testing.T) {
+	db, mock := newTestDB(t)
+	db.queryTimeout = time.Minute
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+
+	mock.ExpectQuery("ST_DWithin").
+		WillDelayFor(5 * time.Second).
+		WillReturnRows(sqlmock.NewRows(append(positionColumns, "distance")))
+
+	center, err := valueobject.NewCoordinate(-23.550520, -46.633309)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	_, err = repo.FindNearby(ctx, center, 500, 10)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, repository.ErrQueryTimeout)
+}
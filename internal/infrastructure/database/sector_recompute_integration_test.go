@@ -0,0 +1,100 @@
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+)
+
+// insertStalePosition grava um usuário com uma posição (histórico e atual) em um setor desatualizado
+// e remove tudo ao final do teste
+func insertStalePosition(t *testing.T, db *DB) (userID, positionID string) {
+	t.Helper()
+	ctx := context.Background()
+
+	userID = uuid.New().String()
+	positionID = uuid.New().String()
+
+	_, err := db.Connection().ExecContext(ctx,
+		`INSERT INTO users (id, name, email) VALUES ($1, 'Recompute', $2)`, userID, userID+"@example.com")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = db.Connection().ExecContext(context.Background(), `DELETE FROM users WHERE id = $1`, userID)
+	})
+
+	_, err = db.Connection().ExecContext(ctx, `
+		INSERT INTO positions (id, user_id, location, sector_x, sector_y)
+		VALUES ($1, $2, ST_SetSRID(ST_MakePoint(-46.633309, -23.550520), 4326), 1, 1)`,
+		positionID, userID)
+	require.NoError(t, err)
+
+	_, err = db.Connection().ExecContext(ctx, `
+		INSERT INTO current_positions (user_id, position_id, location, sector_x, sector_y, recorded_at, updated_at)
+		VALUES ($1, $2, ST_SetSRID(ST_MakePoint(-46.633309, -23.550520), 4326), 1, 1,
+			NOW() - INTERVAL '3 days', NOW() - INTERVAL '3 days')`,
+		userID, positionID)
+	require.NoError(t, err)
+
+	return userID, positionID
+}
+
+// TestFindPositionsAfterID_FirstPage testa que a primeira página do recálculo roda contra a coluna UUID
+func TestFindPositionsAfterID_FirstPage(t *testing.T) {
+	db := newIntegrationDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+	_, positionID := insertStalePosition(t, db)
+
+	found := false
+	afterID := ""
+	for !found {
+		positions, err := repo.FindPositionsAfterID(context.Background(), afterID, 1000)
+		require.NoError(t, err)
+		if len(positions) == 0 {
+			break
+		}
+		for _, position := range positions {
+			id := position.ID()
+			found = found || id.Value() == positionID
+		}
+		lastID := positions[len(positions)-1].ID()
+		afterID = lastID.Value()
+	}
+
+	assert.True(t, found, "position %s should be listed starting from the first page", positionID)
+}
+
+// TestUpdateSectors_PreservesCurrentUpdatedAt testa que o recálculo não faz o usuário parecer ativo
+// (updated_at alimenta o feed de atividade e a retenção de posições paradas)
+func TestUpdateSectors_PreservesCurrentUpdatedAt(t *testing.T) {
+	db := newIntegrationDB(t)
+	repo := NewPositionRepository(db, &config.Config{}, nopLogger{})
+	ctx := context.Background()
+	userID, positionID := insertStalePosition(t, db)
+
+	var before time.Time
+	require.NoError(t, db.Connection().QueryRowContext(ctx,
+		`SELECT updated_at FROM current_positions WHERE user_id = $1`, userID).Scan(&before))
+
+	user, err := entity.NewUserID(userID)
+	require.NoError(t, err)
+	now := time.Now()
+	resectored, err := entity.ReconstructPosition(positionID, *user, -23.550520, -46.633309, 11, 11, now, now)
+	require.NoError(t, err)
+	require.NoError(t, repo.UpdateSectors(ctx, []*entity.Position{resectored}))
+
+	var after time.Time
+	var sectorX int
+	require.NoError(t, db.Connection().QueryRowContext(ctx,
+		`SELECT updated_at, sector_x FROM current_positions WHERE user_id = $1`, userID).Scan(&after, &sectorX))
+
+	assert.Equal(t, resectored.SectorX(), sectorX)
+	assert.True(t, before.Equal(after), "updated_at changed from %s to %s", before, after)
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/vitao/geolocation-tracker/internal/domain/entity"
 	"github.com/vitao/geolocation-tracker/internal/domain/repository"
@@ -27,6 +29,8 @@ func NewUserRepository(db *DB, logger logger.Logger) repository.UserRepository {
 
 // Save persiste um usuário (INSERT ou UPDATE)
 func (r *userRepository) Save(ctx context.Context, user *entity.User) error {
+	defer r.db.observeQuery("user.save", time.Now())
+
 	// Query para UPSERT (INSERT ON CONFLICT UPDATE)
 	query := `
 		INSERT INTO users (id, name, email, created_at, updated_at)
@@ -67,6 +71,8 @@ func (r *userRepository) Save(ctx context.Context, user *entity.User) error {
 
 // FindByID busca usuário por ID
 func (r *userRepository) FindByID(ctx context.Context, id entity.UserID) (*entity.User, error) {
+	defer r.db.observeQuery("user.find_by_id", time.Now())
+
 	query := `
 		SELECT id, name, email, created_at, updated_at
 		FROM users
@@ -82,7 +88,7 @@ func (r *userRepository) FindByID(ctx context.Context, id entity.UserID) (*entit
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("user not found: %s", id.Value())
+			return nil, fmt.Errorf("%w: %s", entity.ErrUserIDNotFound, id.Value())
 		}
 		r.logger.Error("Failed to find user by ID",
 			"user_id", id.Value(),
@@ -117,7 +123,7 @@ func (r *userRepository) FindByEmail(ctx context.Context, email entity.Email) (*
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("user not found with email: %s", email.Value())
+			return nil, fmt.Errorf("%w: %s", entity.ErrEmailNotFound, email.Value())
 		}
 		r.logger.Error("Failed to find user by email",
 			"email", email.Value(),
@@ -152,11 +158,30 @@ func (r *userRepository) Exists(ctx context.Context, id entity.UserID) (bool, er
 	return exists, nil
 }
 
-// Delete remove usuário
+// Delete remove usuário junto com suas posições (atual e histórico) em uma única transação
 func (r *userRepository) Delete(ctx context.Context, id entity.UserID) error {
-	query := `DELETE FROM users WHERE id = $1`
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Explícito em vez de depender do ON DELETE CASCADE do schema; current_positions referencia positions
+	cascade := []string{
+		`DELETE FROM current_positions WHERE user_id = $1`,
+		`DELETE FROM positions WHERE user_id = $1`,
+	}
+	for _, query := range cascade {
+		if _, err := tx.ExecContext(ctx, query, id.Value()); err != nil {
+			r.logger.Error("Failed to delete user positions",
+				"user_id", id.Value(),
+				"error", err,
+			)
+			return fmt.Errorf("failed to delete positions of user %s: %w", id.Value(), err)
+		}
+	}
 
-	result, err := r.db.Connection().ExecContext(ctx, query, id.Value())
+	result, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, id.Value())
 	if err != nil {
 		r.logger.Error("Failed to delete user",
 			"user_id", id.Value(),
@@ -172,7 +197,11 @@ func (r *userRepository) Delete(ctx context.Context, id entity.UserID) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("user not found: %s", id.Value())
+		return fmt.Errorf("%w: %s", entity.ErrUserIDNotFound, id.Value())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	r.logger.Info("User deleted successfully",
@@ -182,20 +211,64 @@ func (r *userRepository) Delete(ctx context.Context, id entity.UserID) error {
 	return nil
 }
 
-// FindAll retorna todos os usuários com paginação
-func (r *userRepository) FindAll(ctx context.Context, limit, offset int) ([]*entity.User, error) {
-	query := `
+// userSortClauses mapeia as ordenações aceitas para ORDER BY fixos
+// Apenas esses trechos entram no SQL; valores do cliente vão sempre como parâmetros
+var userSortClauses = map[repository.UserSort]string{
+	repository.UserSortCreatedAtDesc: "created_at DESC, id ASC",
+	repository.UserSortCreatedAtAsc:  "created_at ASC, id ASC",
+	repository.UserSortName:          "LOWER(name) ASC, id ASC",
+}
+
+// likeEscaper escapa os curingas do LIKE para buscar o texto literalmente
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// userFilterClause monta o WHERE do filtro de usuários e seus parâmetros ($1, $2, ...)
+func userFilterClause(filter repository.UserFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.NameContains != "" {
+		args = append(args, "%"+likeEscaper.Replace(filter.NameContains)+"%")
+		conditions = append(conditions, fmt.Sprintf("name ILIKE $%d", len(args)))
+	}
+	if filter.EmailDomain != "" {
+		args = append(args, "%@"+likeEscaper.Replace(filter.EmailDomain))
+		conditions = append(conditions, fmt.Sprintf("email ILIKE $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// FindAll retorna uma página de usuários com filtro e ordenação
+func (r *userRepository) FindAll(ctx context.Context, query repository.UserQuery) ([]*entity.User, error) {
+	sort := query.Sort
+	if sort == "" {
+		sort = repository.UserSortCreatedAtDesc
+	}
+	orderBy, ok := userSortClauses[sort]
+	if !ok {
+		return nil, fmt.Errorf("unsupported user sort %q", query.Sort)
+	}
+
+	where, args := userFilterClause(query.Filter)
+	args = append(args, query.Limit, query.Offset)
+	sqlQuery := fmt.Sprintf(`
 		SELECT id, name, email, created_at, updated_at
 		FROM users
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, where, orderBy, len(args)-1, len(args))
 
-	rows, err := r.db.Connection().QueryContext(ctx, query, limit, offset)
+	rows, err := r.db.Connection().QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		r.logger.Error("Failed to find all users",
-			"limit", limit,
-			"offset", offset,
+			"limit", query.Limit,
+			"offset", query.Offset,
+			"sort", sort,
 			"error", err,
 		)
 		return nil, fmt.Errorf("failed to find users: %w", err)
@@ -233,25 +306,55 @@ func (r *userRepository) FindAll(ctx context.Context, limit, offset int) ([]*ent
 
 	r.logger.Debug("Found users",
 		"count", len(users),
-		"limit", limit,
-		"offset", offset,
+		"limit", query.Limit,
+		"offset", query.Offset,
 	)
 
 	return users, nil
 }
 
+// CountUsers retorna o total de usuários que atendem ao filtro
+func (r *userRepository) CountUsers(ctx context.Context, filter repository.UserFilter) (int, error) {
+	where, args := userFilterClause(filter)
+	query := strings.TrimSpace("SELECT COUNT(*) FROM users " + where)
+
+	var total int
+	if err := r.db.Connection().QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	return total, nil
+}
+
 // scanToUser converte dados do banco para entidade User
-func (r *userRepository) scanToUser(userID, name, email string, _, _ sql.NullTime) (*entity.User, error) {
-	// Esta é uma função de reconstrução - precisamos usar um factory interno
-	// Por enquanto, vamos usar o factory público (idealmente teríamos um método interno)
-	user, err := entity.NewUser(userID, name, email)
+func (r *userRepository) scanToUser(userID, name, email string, createdAt, updatedAt sql.NullTime) (*entity.User, error) {
+	// Linhas legadas podem ter timestamps NULL: created_at cai para updated_at (ou agora)
+	// e updated_at cai para created_at, evitando o zero time na entidade
+	if !createdAt.Valid || !updatedAt.Valid {
+		r.logger.Warn("User row with NULL timestamps",
+			"user_id", userID,
+			"created_at_null", !createdAt.Valid,
+			"updated_at_null", !updatedAt.Valid,
+		)
+	}
+
+	created := time.Now()
+	if createdAt.Valid {
+		created = createdAt.Time
+	} else if updatedAt.Valid {
+		created = updatedAt.Time
+	}
+
+	updated := created
+	if updatedAt.Valid {
+		updated = updatedAt.Time
+	}
+
+	// Reconstruir a entidade preservando os timestamps persistidos
+	user, err := entity.ReconstructUser(userID, name, email, created, updated)
 	if err != nil {
 		return nil, err
 	}
 
-	// NOTA: Em uma implementação mais sofisticada, teríamos métodos para
-	// reconstruir a entidade com timestamps originais do banco
-	// Por agora, os timestamps serão recriados
-
 	return user, nil
 }
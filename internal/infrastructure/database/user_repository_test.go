@@ -0,0 +1,223 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+)
+
+// captureArg guarda o valor de um argumento da query para reutilizar no teste
+type captureArg struct {
+	value driver.Value
+}
+
+// Match implementa sqlmock.Argument
+func (c *captureArg) Match(v driver.Value) bool {
+	c.value = v
+	return true
+}
+
+// TestUserRepository_SaveThenFindKeepsTimestamps testa que os timestamps persistidos são preservados
+func TestUserRepository_SaveThenFindKeepsTimestamps(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewUserRepository(db, nopLogger{})
+	ctx := context.Background()
+
+	createdAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	updatedAt := time.Date(2024, 2, 20, 8, 0, 0, 0, time.UTC)
+
+	user, err := entity.ReconstructUser("user123", "João Silva", "joao@example.com", createdAt, updatedAt)
+	require.NoError(t, err)
+
+	// Save: capturar os timestamps enviados ao banco
+	savedCreatedAt := &captureArg{}
+	savedUpdatedAt := &captureArg{}
+	mock.ExpectExec("INSERT INTO users").
+		WithArgs("user123", "João Silva", "joao@example.com", savedCreatedAt, savedUpdatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.Save(ctx, user))
+
+	// FindByID: devolver exatamente o que foi salvo
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at"}).
+		AddRow("user123", "João Silva", "joao@example.com", savedCreatedAt.value, savedUpdatedAt.value)
+	mock.ExpectQuery("SELECT id, name, email, created_at, updated_at").
+		WithArgs("user123").
+		WillReturnRows(rows)
+
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+
+	loaded, err := repo.FindByID(ctx, *userID)
+	require.NoError(t, err)
+
+	assert.True(t, loaded.CreatedAt().Time().Equal(createdAt))
+	assert.True(t, loaded.UpdatedAt().Time().Equal(updatedAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUserRepository_DeleteCascadesPositions testa que posições atual e histórico são removidas na mesma transação
+func TestUserRepository_DeleteCascadesPositions(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewUserRepository(db, nopLogger{})
+
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM current_positions WHERE user_id = $1")).
+		WithArgs("user123").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM positions WHERE user_id = $1")).
+		WithArgs("user123").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM users WHERE id = $1")).
+		WithArgs("user123").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.Delete(context.Background(), *userID))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUserRepository_DeleteNotFound testa que um usuário inexistente não comita nada
+func TestUserRepository_DeleteNotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewUserRepository(db, nopLogger{})
+
+	userID, err := entity.NewUserID("missing")
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM current_positions")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM positions")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM users")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = repo.Delete(context.Background(), *userID)
+	assert.ErrorIs(t, err, entity.ErrUserIDNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUserRepository_FindByEmailNotFound testa o erro sentinela para email sem usuário
+func TestUserRepository_FindByEmailNotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewUserRepository(db, nopLogger{})
+
+	email, err := entity.NewEmail("missing@example.com")
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT id, name, email, created_at, updated_at").
+		WithArgs("missing@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at"}))
+
+	_, err = repo.FindByEmail(context.Background(), *email)
+	assert.ErrorIs(t, err, entity.ErrEmailNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUserRepository_FindByIDWithNullTimestamps testa que linhas legadas com timestamps NULL são reconstruídas
+func TestUserRepository_FindByIDWithNullTimestamps(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewUserRepository(db, nopLogger{})
+
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+
+	t.Run("both NULL", func(t *testing.T) {
+		mock.ExpectQuery("SELECT id, name, email, created_at, updated_at").
+			WithArgs("user123").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at"}).
+				AddRow("user123", "João Silva", "joao@example.com", nil, nil))
+
+		before := time.Now()
+		loaded, err := repo.FindByID(context.Background(), *userID)
+		require.NoError(t, err)
+
+		assert.False(t, loaded.CreatedAt().Time().Before(before))
+		assert.True(t, loaded.UpdatedAt().Time().Equal(loaded.CreatedAt().Time()))
+	})
+
+	t.Run("only updated_at NULL", func(t *testing.T) {
+		createdAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+		mock.ExpectQuery("SELECT id, name, email, created_at, updated_at").
+			WithArgs("user123").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at"}).
+				AddRow("user123", "João Silva", "joao@example.com", createdAt, nil))
+
+		loaded, err := repo.FindByID(context.Background(), *userID)
+		require.NoError(t, err)
+
+		assert.True(t, loaded.CreatedAt().Time().Equal(createdAt))
+		assert.True(t, loaded.UpdatedAt().Time().Equal(createdAt))
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUserRepository_FindAllWithFilters testa os filtros parametrizados e a ordenação da lista branca
+func TestUserRepository_FindAllWithFilters(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewUserRepository(db, nopLogger{})
+
+	createdAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at"}).
+		AddRow("user123", "João Silva", "joao@example.com", createdAt, createdAt)
+
+	// Curingas digitados pelo cliente são escapados e buscados literalmente
+	mock.ExpectQuery(`WHERE name ILIKE \$1 AND email ILIKE \$2\s+ORDER BY LOWER\(name\) ASC, id ASC\s+LIMIT \$3 OFFSET \$4`).
+		WithArgs(`%50\%\_off%`, "%@example.com", 10, 20).
+		WillReturnRows(rows)
+
+	users, err := repo.FindAll(context.Background(), repository.UserQuery{
+		Filter: repository.UserFilter{NameContains: "50%_off", EmailDomain: "example.com"},
+		Sort:   repository.UserSortName,
+		Limit:  10,
+		Offset: 20,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUserRepository_FindAllDefaultsAndRejectsUnknownSort testa a ordenação padrão e a recusa fora da lista
+func TestUserRepository_FindAllDefaultsAndRejectsUnknownSort(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewUserRepository(db, nopLogger{})
+
+	mock.ExpectQuery(`FROM users\s+ORDER BY created_at DESC, id ASC\s+LIMIT \$1 OFFSET \$2`).
+		WithArgs(20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at"}))
+
+	_, err := repo.FindAll(context.Background(), repository.UserQuery{Limit: 20})
+	require.NoError(t, err)
+
+	_, err = repo.FindAll(context.Background(), repository.UserQuery{Sort: "name; DROP TABLE users", Limit: 20})
+	assert.ErrorContains(t, err, "unsupported user sort")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUserRepository_CountUsersWithFilter testa que a contagem usa o mesmo filtro da listagem
+func TestUserRepository_CountUsersWithFilter(t *testing.T) {
+	db, mock := newTestDB(t)
+	repo := NewUserRepository(db, nopLogger{})
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users WHERE email ILIKE $1")).
+		WithArgs("%@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+
+	total, err := repo.CountUsers(context.Background(), repository.UserFilter{EmailDomain: "example.com"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 7, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,210 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ConsumerGroupStats é o estado de um consumer group em um stream
+type ConsumerGroupStats struct {
+	Stream          string `json:"stream"`
+	Consumers       int64  `json:"consumers"`
+	Pending         int64  `json:"pending"`                     // Entregues e ainda sem ACK (XPENDING)
+	OldestPendingID string `json:"oldest_pending_id,omitempty"` // Entrada pendente mais antiga
+	LastDeliveredID string `json:"last_delivered_id"`
+
+	// Lag são as entradas do stream ainda não entregues ao grupo
+	// LagExact é false quando o Redis não informa o lag (< 7.0 ou entradas removidas no meio do
+	// stream); nesse caso o valor é uma estimativa pelo tamanho do stream (limite superior)
+	Lag      int64 `json:"lag"`
+	LagExact bool  `json:"lag_exact"`
+
+	// PendingByConsumer conta as entradas pendentes de cada consumer do grupo
+	PendingByConsumer map[string]int64 `json:"pending_by_consumer,omitempty"`
+}
+
+// xinfoGroup é uma linha do XINFO GROUPS; lag é nil quando o Redis não o calcula
+type xinfoGroup struct {
+	name            string
+	consumers       int64
+	pending         int64
+	lastDeliveredID string
+	lag             *int64
+}
+
+// ConsumerGroupStats retorna o estado de cada consumer group dos streams gerenciados
+// Resultado: stream -> grupo -> estatísticas; streams inexistentes não têm grupos
+func (p *RedisStreamPublisher) ConsumerGroupStats(ctx context.Context) (map[string]map[string]*ConsumerGroupStats, error) {
+	stats := make(map[string]map[string]*ConsumerGroupStats)
+
+	for _, stream := range managedStreams() {
+		groups, err := p.streamGroupStats(ctx, stream)
+		if err != nil {
+			return nil, err
+		}
+		stats[stream] = groups
+	}
+
+	return stats, nil
+}
+
+// streamGroupStats consulta XINFO GROUPS e XPENDING de cada grupo do stream
+func (p *RedisStreamPublisher) streamGroupStats(ctx context.Context, stream string) (map[string]*ConsumerGroupStats, error) {
+	stats := make(map[string]*ConsumerGroupStats)
+
+	// O XInfoGroups do go-redis v8 não reconhece os campos do Redis 7 (entries-read, lag)
+	reply, err := p.client.Do(ctx, "XINFO", "GROUPS", stream).Result()
+	if err != nil {
+		if isNoSuchKey(err) {
+			return stats, nil
+		}
+		return nil, fmt.Errorf("failed to get consumer groups of stream %s: %w", stream, err)
+	}
+
+	groups, err := parseXInfoGroups(reply)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse consumer groups of stream %s: %w", stream, err)
+	}
+	if len(groups) == 0 {
+		return stats, nil
+	}
+
+	length, err := p.client.XLen(ctx, stream).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get length of stream %s: %w", stream, err)
+	}
+
+	lastEntryID, err := p.lastEntryID(ctx, stream)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		groupStats := &ConsumerGroupStats{
+			Stream:          stream,
+			Consumers:       group.consumers,
+			Pending:         group.pending,
+			LastDeliveredID: group.lastDeliveredID,
+		}
+
+		pending, err := p.client.XPending(ctx, stream, group.name).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pending entries of group %s on %s: %w", group.name, stream, err)
+		}
+		groupStats.Pending = pending.Count
+		groupStats.OldestPendingID = pending.Lower
+		groupStats.PendingByConsumer = pending.Consumers
+
+		groupStats.Lag, groupStats.LagExact = groupLag(group, length, lastEntryID)
+		stats[group.name] = groupStats
+	}
+
+	return stats, nil
+}
+
+// lastEntryID retorna o ID da última entrada do stream ("" se estiver vazio)
+func (p *RedisStreamPublisher) lastEntryID(ctx context.Context, stream string) (string, error) {
+	messages, err := p.client.XRevRangeN(ctx, stream, "+", "-", 1).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to get last entry of stream %s: %w", stream, err)
+	}
+	if len(messages) == 0 {
+		return "", nil
+	}
+	return messages[0].ID, nil
+}
+
+// groupLag usa o lag do Redis quando disponível; sem ele, o grupo está em dia se já recebeu a
+// última entrada e, caso contrário, o tamanho do stream é o limite superior do atraso
+func groupLag(group xinfoGroup, length int64, lastEntryID string) (int64, bool) {
+	if group.lag != nil {
+		return *group.lag, true
+	}
+	if lastEntryID == "" || compareStreamIDs(group.lastDeliveredID, lastEntryID) >= 0 {
+		return 0, true
+	}
+	return length, false
+}
+
+// parseXInfoGroups converte a resposta do XINFO GROUPS (lista de pares campo/valor por grupo)
+// Campos desconhecidos são ignorados para tolerar versões novas do Redis
+func parseXInfoGroups(reply interface{}) ([]xinfoGroup, error) {
+	rows, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected XINFO GROUPS reply %T", reply)
+	}
+
+	groups := make([]xinfoGroup, 0, len(rows))
+	for _, row := range rows {
+		fields, ok := row.([]interface{})
+		if !ok || len(fields)%2 != 0 {
+			return nil, fmt.Errorf("unexpected XINFO GROUPS entry %v", row)
+		}
+
+		var group xinfoGroup
+		for i := 0; i < len(fields); i += 2 {
+			key, _ := fields[i].(string)
+			value := fields[i+1]
+
+			switch key {
+			case "name":
+				group.name, _ = value.(string)
+			case "consumers":
+				group.consumers, _ = value.(int64)
+			case "pending":
+				group.pending, _ = value.(int64)
+			case "last-delivered-id":
+				group.lastDeliveredID, _ = value.(string)
+			case "lag":
+				if lag, ok := value.(int64); ok {
+					group.lag = &lag
+				}
+			}
+		}
+
+		if group.name == "" {
+			return nil, fmt.Errorf("XINFO GROUPS entry without name: %v", row)
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+// compareStreamIDs compara IDs de stream ("<ms>-<seq>"): -1 se a < b, 0 se iguais, 1 se a > b
+func compareStreamIDs(a, b string) int {
+	aMs, aSeq := splitStreamID(a)
+	bMs, bSeq := splitStreamID(b)
+
+	switch {
+	case aMs != bMs:
+		if aMs < bMs {
+			return -1
+		}
+		return 1
+	case aSeq != bSeq:
+		if aSeq < bSeq {
+			return -1
+		}
+		return 1
+	default:
+		return 0
+	}
+}
+
+// splitStreamID separa o timestamp e a sequência do ID; partes inválidas valem 0
+func splitStreamID(id string) (uint64, uint64) {
+	msPart, seqPart, _ := strings.Cut(id, "-")
+	ms, _ := strconv.ParseUint(msPart, 10, 64)
+	seq, _ := strconv.ParseUint(seqPart, 10, 64)
+	return ms, seq
+}
+
+// isNoSuchKey indica que o stream não existe (XINFO em chave ausente)
+func isNoSuchKey(err error) bool {
+	return err != redis.Nil && strings.Contains(err.Error(), "no such key")
+}
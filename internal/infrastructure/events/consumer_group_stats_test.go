@@ -0,0 +1,184 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	domainEvents "github.com/vitao/geolocation-tracker/internal/domain/events"
+)
+
+// groupStatsClient responde XINFO GROUPS, XLEN, XPENDING e XREVRANGE a partir de dados fixos
+// Streams ausentes de groups respondem como chave inexistente
+type groupStatsClient struct {
+	streamClient
+	groups    map[string][]interface{} // stream -> resposta do XINFO GROUPS
+	lengths   map[string]int64
+	lastIDs   map[string]string
+	pending   map[string]*redis.XPending // "stream/grupo" -> resumo do XPENDING
+	xinfoErrs map[string]error
+}
+
+func (c *groupStatsClient) Do(ctx context.Context, args ...interface{}) *redis.Cmd {
+	stream := args[2].(string)
+	if err := c.xinfoErrs[stream]; err != nil {
+		return redis.NewCmdResult(nil, err)
+	}
+	reply, ok := c.groups[stream]
+	if !ok {
+		return redis.NewCmdResult(nil, errors.New("ERR no such key"))
+	}
+	return redis.NewCmdResult(reply, nil)
+}
+
+func (c *groupStatsClient) XLen(ctx context.Context, stream string) *redis.IntCmd {
+	return redis.NewIntResult(c.lengths[stream], nil)
+}
+
+func (c *groupStatsClient) XRevRangeN(ctx context.Context, stream, start, stop string, count int64) *redis.XMessageSliceCmd {
+	if id := c.lastIDs[stream]; id != "" {
+		return redis.NewXMessageSliceCmdResult([]redis.XMessage{{ID: id}}, nil)
+	}
+	return redis.NewXMessageSliceCmdResult(nil, nil)
+}
+
+func (c *groupStatsClient) XPending(ctx context.Context, stream, group string) *redis.XPendingCmd {
+	cmd := redis.NewXPendingCmd(ctx, "xpending", stream, group)
+	if pending, ok := c.pending[stream+"/"+group]; ok {
+		cmd.SetVal(pending)
+	} else {
+		cmd.SetVal(&redis.XPending{})
+	}
+	return cmd
+}
+
+// newGroupStatsPublisher cria um publisher sobre o client de dados fixos
+func newGroupStatsPublisher(client *groupStatsClient) *RedisStreamPublisher {
+	publisher := NewRedisStreamPublisher(nil, nopLogger{})
+	publisher.client = client
+	return publisher
+}
+
+// TestConsumerGroupStats_Redis7Lag testa o lag informado pelo Redis 7 e o resumo do XPENDING
+func TestConsumerGroupStats_Redis7Lag(t *testing.T) {
+	stream := domainEvents.StreamPositionEvents
+	client := &groupStatsClient{
+		groups: map[string][]interface{}{
+			stream: {
+				[]interface{}{
+					"name", "analytics", "consumers", int64(1), "pending", int64(2),
+					"last-delivered-id", "1700000000000-4", "entries-read", int64(5), "lag", int64(7),
+				},
+			},
+		},
+		lengths: map[string]int64{stream: 12},
+		lastIDs: map[string]string{stream: "1700000000000-11"},
+		pending: map[string]*redis.XPending{
+			stream + "/analytics": {Count: 2, Lower: "1700000000000-3", Higher: "1700000000000-4", Consumers: map[string]int64{"analytics-worker-1": 2}},
+		},
+	}
+
+	stats, err := newGroupStatsPublisher(client).ConsumerGroupStats(context.Background())
+
+	require.NoError(t, err)
+	analytics := stats[stream]["analytics"]
+	require.NotNil(t, analytics)
+	assert.Equal(t, int64(1), analytics.Consumers)
+	assert.Equal(t, int64(2), analytics.Pending)
+	assert.Equal(t, "1700000000000-3", analytics.OldestPendingID)
+	assert.Equal(t, "1700000000000-4", analytics.LastDeliveredID)
+	assert.Equal(t, int64(7), analytics.Lag)
+	assert.True(t, analytics.LagExact)
+	assert.Equal(t, map[string]int64{"analytics-worker-1": 2}, analytics.PendingByConsumer)
+
+	// Streams que ainda não existem aparecem sem grupos
+	assert.Empty(t, stats[domainEvents.StreamUserEvents])
+}
+
+// TestConsumerGroupStats_LagEstimateWithoutRedisLag testa a estimativa quando o Redis não informa o lag
+func TestConsumerGroupStats_LagEstimateWithoutRedisLag(t *testing.T) {
+	stream := domainEvents.StreamSectorEvents
+	client := &groupStatsClient{
+		groups: map[string][]interface{}{
+			stream: {
+				// Redis < 7: apenas os quatro campos clássicos
+				[]interface{}{"name", "notifications", "consumers", int64(1), "pending", int64(0), "last-delivered-id", "1700000000000-9"},
+				[]interface{}{"name", "analytics", "consumers", int64(0), "pending", int64(0), "last-delivered-id", "1700000000000-2"},
+				// Redis 7 sem lag calculável (entradas removidas no meio do stream)
+				[]interface{}{"name", "realtime", "consumers", int64(1), "pending", int64(0), "last-delivered-id", "0-0", "entries-read", nil, "lag", nil},
+			},
+		},
+		lengths: map[string]int64{stream: 5},
+		lastIDs: map[string]string{stream: "1700000000000-9"},
+	}
+
+	stats, err := newGroupStatsPublisher(client).ConsumerGroupStats(context.Background())
+
+	require.NoError(t, err)
+	groups := stats[stream]
+
+	// Já recebeu a última entrada: em dia
+	assert.Equal(t, int64(0), groups["notifications"].Lag)
+	assert.True(t, groups["notifications"].LagExact)
+
+	// Atrás da última entrada: o tamanho do stream é o limite superior
+	assert.Equal(t, int64(5), groups["analytics"].Lag)
+	assert.False(t, groups["analytics"].LagExact)
+	assert.Equal(t, int64(5), groups["realtime"].Lag)
+	assert.False(t, groups["realtime"].LagExact)
+}
+
+// TestConsumerGroupStats_XInfoError testa que falhas do Redis são propagadas
+func TestConsumerGroupStats_XInfoError(t *testing.T) {
+	client := &groupStatsClient{
+		xinfoErrs: map[string]error{domainEvents.StreamPositionEvents: errors.New("connection refused")},
+	}
+
+	stats, err := newGroupStatsPublisher(client).ConsumerGroupStats(context.Background())
+
+	assert.Nil(t, stats)
+	assert.ErrorContains(t, err, "connection refused")
+}
+
+// TestEventServiceGetStats_AggregatesGroups testa os totais por grupo somando os streams
+func TestEventServiceGetStats_AggregatesGroups(t *testing.T) {
+	group := func(name string, pending int64, lastDelivered string) []interface{} {
+		return []interface{}{"name", name, "consumers", int64(1), "pending", pending, "last-delivered-id", lastDelivered}
+	}
+	client := &groupStatsClient{
+		groups: map[string][]interface{}{
+			domainEvents.StreamPositionEvents: {group("notifications", 1, "1-0")},
+			domainEvents.StreamSectorEvents:   {group("notifications", 0, "2-0")},
+		},
+		lengths: map[string]int64{domainEvents.StreamPositionEvents: 4, domainEvents.StreamSectorEvents: 2},
+		lastIDs: map[string]string{domainEvents.StreamPositionEvents: "4-0", domainEvents.StreamSectorEvents: "2-0"},
+		pending: map[string]*redis.XPending{
+			domainEvents.StreamPositionEvents + "/notifications": {Count: 1, Lower: "1-0", Higher: "1-0"},
+		},
+	}
+	service := &EventService{publisher: newGroupStatsPublisher(client)}
+
+	stats, err := service.GetStats(context.Background())
+
+	require.NoError(t, err)
+	notifications := stats["consumer_groups"].(map[string]interface{})["notifications"].(map[string]interface{})
+	assert.Equal(t, int64(1), notifications["pending"])
+	assert.Equal(t, int64(4), notifications["lag"])
+	assert.Equal(t, false, notifications["lag_exact"])
+	assert.Len(t, notifications["streams"], 2)
+
+	streams := stats["streams"].(map[string]interface{})
+	assert.Equal(t, 1, streams[domainEvents.StreamPositionEvents].(map[string]interface{})["groups"])
+	assert.Equal(t, 0, streams[domainEvents.StreamUserEvents].(map[string]interface{})["groups"])
+}
+
+// TestCompareStreamIDs testa a ordem dos IDs de stream por timestamp e sequência
+func TestCompareStreamIDs(t *testing.T) {
+	assert.Equal(t, 0, compareStreamIDs("1700000000000-1", "1700000000000-1"))
+	assert.Equal(t, -1, compareStreamIDs("1700000000000-2", "1700000000000-10"))
+	assert.Equal(t, 1, compareStreamIDs("1700000000001-0", "1700000000000-99"))
+	assert.Equal(t, -1, compareStreamIDs("0-0", "1-0"))
+}
@@ -0,0 +1,165 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	domainEvents "github.com/vitao/geolocation-tracker/internal/domain/events"
+)
+
+// Versões do formato das entradas nos streams
+const (
+	// legacySchemaVersion são os campos separados (event_id, type, event_ctx, data, metadata...)
+	legacySchemaVersion = 1
+
+	// EventSchemaVersion é o envelope JSON com o evento inteiro no campo payload
+	EventSchemaVersion = 2
+)
+
+// payloadField é o campo da entrada do stream que guarda o envelope
+const payloadField = "payload"
+
+// ErrUnsupportedSchemaVersion indica uma entrada gravada com um formato mais novo que o do consumer
+var ErrUnsupportedSchemaVersion = errors.New("unsupported event schema version")
+
+// EventCodec converte eventos nos campos de uma entrada do stream e de volta
+type EventCodec interface {
+	// Encode retorna os campos gravados pelo XADD
+	Encode(event *domainEvents.Event) (map[string]interface{}, error)
+
+	// Decode reconstrói o evento de uma entrada lida do stream (StreamID = ID da entrada)
+	Decode(message redis.XMessage) (*domainEvents.Event, error)
+}
+
+// eventEnvelope é o conteúdo do campo payload
+type eventEnvelope struct {
+	SchemaVersion int                 `json:"schema_version"`
+	Event         *domainEvents.Event `json:"event"`
+}
+
+// JSONEnvelopeCodec grava o evento inteiro (inclusive metadata) em um único envelope JSON
+// e lê tanto o envelope quanto o formato antigo de campos separados
+type JSONEnvelopeCodec struct{}
+
+// NewJSONEnvelopeCodec cria o codec padrão dos streams
+func NewJSONEnvelopeCodec() *JSONEnvelopeCodec {
+	return &JSONEnvelopeCodec{}
+}
+
+// Encode serializa o evento no envelope da versão atual
+func (JSONEnvelopeCodec) Encode(event *domainEvents.Event) (map[string]interface{}, error) {
+	payload, err := json.Marshal(eventEnvelope{SchemaVersion: EventSchemaVersion, Event: event})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event envelope: %w", err)
+	}
+	return map[string]interface{}{payloadField: string(payload)}, nil
+}
+
+// Decode escolhe o decoder pela presença do payload: entradas sem ele são do formato antigo
+func (JSONEnvelopeCodec) Decode(message redis.XMessage) (*domainEvents.Event, error) {
+	var (
+		event *domainEvents.Event
+		err   error
+	)
+	if payload, ok := message.Values[payloadField].(string); ok {
+		event, err = decodeEnvelope(payload)
+	} else {
+		event, err = decodeLegacyFields(message.Values)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	event.StreamID = message.ID
+	return event, nil
+}
+
+// decodeEnvelope lê o envelope JSON, recusando versões mais novas que a suportada
+func decodeEnvelope(payload string) (*domainEvents.Event, error) {
+	var envelope eventEnvelope
+	if err := json.Unmarshal([]byte(payload), &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse event envelope: %w", err)
+	}
+
+	switch {
+	case envelope.SchemaVersion > EventSchemaVersion:
+		return nil, fmt.Errorf("%w: %d (max %d)", ErrUnsupportedSchemaVersion, envelope.SchemaVersion, EventSchemaVersion)
+	case envelope.SchemaVersion <= legacySchemaVersion:
+		return nil, fmt.Errorf("%w: %d in envelope", ErrUnsupportedSchemaVersion, envelope.SchemaVersion)
+	case envelope.Event == nil:
+		return nil, fmt.Errorf("event envelope without event")
+	case envelope.Event.ID == "" || envelope.Event.Type == "":
+		return nil, fmt.Errorf("event envelope missing id or type")
+	}
+
+	return envelope.Event, nil
+}
+
+// decodeLegacyFields lê o formato da versão 1, com um campo por atributo do evento
+// Mantido para as entradas gravadas antes do envelope que ainda estejam nos streams
+func decodeLegacyFields(values map[string]interface{}) (*domainEvents.Event, error) {
+	eventID, ok := values["event_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid event_id")
+	}
+
+	eventType, ok := values["type"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid type")
+	}
+
+	userID, ok := values["user_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid user_id")
+	}
+
+	eventCtx, ok := values["event_ctx"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid event_ctx")
+	}
+
+	timestampStr, ok := values["timestamp"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid timestamp")
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, timestampStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse timestamp: %w", err)
+	}
+
+	// Parse data JSON
+	dataStr, ok := values["data"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid data")
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
+		return nil, fmt.Errorf("failed to parse data JSON: %w", err)
+	}
+
+	// Parse metadata JSON
+	metadataStr, ok := values["metadata"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid metadata")
+	}
+
+	var metadata domainEvents.EventMetadata
+	if err := json.Unmarshal([]byte(metadataStr), &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata JSON: %w", err)
+	}
+
+	return &domainEvents.Event{
+		ID:        eventID,
+		Type:      domainEvents.EventType(eventType),
+		UserID:    userID,
+		EventID:   eventCtx,
+		Timestamp: timestamp,
+		Data:      data,
+		Metadata:  metadata,
+	}, nil
+}
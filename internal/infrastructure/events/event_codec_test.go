@@ -0,0 +1,85 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	domainEvents "github.com/vitao/geolocation-tracker/internal/domain/events"
+)
+
+// TestJSONEnvelopeCodec_RoundTripKeepsMetadata testa que o evento volta igual do stream, inclusive a metadata
+func TestJSONEnvelopeCodec_RoundTripKeepsMetadata(t *testing.T) {
+	codec := NewJSONEnvelopeCodec()
+	event := &domainEvents.Event{
+		ID:        "evt-1",
+		Type:      domainEvents.EventTypePositionChanged,
+		UserID:    "user123",
+		EventID:   "ctx-1",
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+		Data:      map[string]interface{}{"latitude": -23.5, "sector_id": "sector-1"},
+		Metadata:  domainEvents.EventMetadata{Source: "api", Version: "1.0", RequestID: "req-42"},
+	}
+
+	fields, err := codec.Encode(event)
+	require.NoError(t, err)
+	assert.Len(t, fields, 1)
+	assert.Contains(t, fields[payloadField], `"schema_version":2`)
+
+	decoded, err := codec.Decode(redis.XMessage{ID: "1700000000000-0", Values: fields})
+
+	require.NoError(t, err)
+	assert.Equal(t, "1700000000000-0", decoded.StreamID)
+	assert.Equal(t, event.Metadata, decoded.Metadata)
+	assert.Equal(t, event.ID, decoded.ID)
+	assert.Equal(t, event.Type, decoded.Type)
+	assert.Equal(t, event.EventID, decoded.EventID)
+	assert.True(t, event.Timestamp.Equal(decoded.Timestamp))
+	assert.Equal(t, event.Data, decoded.Data)
+}
+
+// TestJSONEnvelopeCodec_DecodesLegacyFields testa a leitura de entradas gravadas antes do envelope
+func TestJSONEnvelopeCodec_DecodesLegacyFields(t *testing.T) {
+	message := redis.XMessage{
+		ID: "1600000000000-3",
+		Values: map[string]interface{}{
+			"event_id":  "evt-old",
+			"type":      string(domainEvents.EventTypeUserEnteredSector),
+			"user_id":   "user123",
+			"event_ctx": "ctx-old",
+			"timestamp": "2023-05-06T07:08:09.123Z",
+			"data":      `{"sector_id":"sector-2"}`,
+			"metadata":  `{"source":"worker","version":"1.0","request_id":"req-7"}`,
+		},
+	}
+
+	event, err := NewJSONEnvelopeCodec().Decode(message)
+
+	require.NoError(t, err)
+	assert.Equal(t, "evt-old", event.ID)
+	assert.Equal(t, domainEvents.EventTypeUserEnteredSector, event.Type)
+	assert.Equal(t, "ctx-old", event.EventID)
+	assert.Equal(t, "1600000000000-3", event.StreamID)
+	assert.Equal(t, "sector-2", event.Data["sector_id"])
+	assert.Equal(t, domainEvents.EventMetadata{Source: "worker", Version: "1.0", RequestID: "req-7"}, event.Metadata)
+}
+
+// TestJSONEnvelopeCodec_RejectsInvalidEntries testa versões desconhecidas e envelopes incompletos
+func TestJSONEnvelopeCodec_RejectsInvalidEntries(t *testing.T) {
+	codec := NewJSONEnvelopeCodec()
+	decode := func(payload string) error {
+		_, err := codec.Decode(redis.XMessage{ID: "1-0", Values: map[string]interface{}{payloadField: payload}})
+		return err
+	}
+
+	assert.ErrorIs(t, decode(`{"schema_version":3,"event":{"id":"evt","type":"position.changed"}}`), ErrUnsupportedSchemaVersion)
+	assert.ErrorIs(t, decode(`{"event":{"id":"evt","type":"position.changed"}}`), ErrUnsupportedSchemaVersion)
+	assert.ErrorContains(t, decode(`{"schema_version":2}`), "without event")
+	assert.ErrorContains(t, decode(`{"schema_version":2,"event":{"type":"position.changed"}}`), "missing id or type")
+	assert.ErrorContains(t, decode(`not json`), "failed to parse event envelope")
+
+	_, err := codec.Decode(redis.XMessage{ID: "1-0", Values: map[string]interface{}{"type": "position.changed"}})
+	assert.ErrorContains(t, err, "event_id")
+}
@@ -2,36 +2,54 @@ package events
 
 import (
 	"context"
-	"sync"
+	"fmt"
+	"time"
 
 	"github.com/vitao/geolocation-tracker/internal/domain/events"
 	"github.com/vitao/geolocation-tracker/internal/infrastructure/cache"
+	"github.com/vitao/geolocation-tracker/pkg/config"
 	"github.com/vitao/geolocation-tracker/pkg/logger"
+	"github.com/vitao/geolocation-tracker/pkg/metrics"
+	"github.com/vitao/geolocation-tracker/pkg/worker"
 )
 
 // EventService gerencia publishers e consumers de eventos
 type EventService struct {
 	publisher *RedisStreamPublisher
 	consumer  *RedisStreamConsumer
+	throttle  *RedisNotificationThrottle
+	realtime  Broadcaster
+	config    *config.Config
 	logger    logger.Logger
-	ctx       context.Context
-	cancel    context.CancelFunc
-	wg        sync.WaitGroup
+
+	// workers executa os consumers; o encerramento é coordenado pela aplicação (workers.Shutdown)
+	workers *worker.Manager
 }
 
 // NewEventService cria um novo service de eventos
-func NewEventService(redis *cache.Redis, logger logger.Logger) *EventService {
-	ctx, cancel := context.WithCancel(context.Background())
-
+// realtime recebe as atualizações de posição para os clientes WebSocket
+func NewEventService(redis *cache.Redis, realtime Broadcaster, collector metrics.Collector, workers *worker.Manager, cfg *config.Config, logger logger.Logger) *EventService {
 	publisher := NewRedisStreamPublisher(redis.Client(), logger)
+	publisher.ConfigureStreamEventTypes(cfg.Events.StreamEventTypes)
+	publisher.SetMaxLen(int64(cfg.Events.StreamMaxLen))
+	publisher.SetRetry(cfg.Events.PublishMaxAttempts, time.Duration(cfg.Events.PublishRetryBackoffMs)*time.Millisecond)
+	publisher.SetMetrics(collector)
 	consumer := NewRedisStreamConsumer(redis.Client(), logger)
+	consumer.SetMetrics(collector)
+	consumer.SetDuplicateHandlerPolicy(ParseDuplicateHandlerPolicy(cfg.Events.DuplicateHandlerPolicy))
+	for group, mode := range cfg.Events.AckModes {
+		consumer.SetAckMode(group, ParseAckMode(mode))
+	}
+	consumer.SetDeduplication(NewRedisProcessedEventStore(redis.Client()), time.Duration(cfg.Events.DedupTTLSeconds)*time.Second)
 
 	return &EventService{
 		publisher: publisher,
 		consumer:  consumer,
+		throttle:  NewRedisNotificationThrottle(redis.Client()),
+		realtime:  realtime,
+		config:    cfg,
 		logger:    logger,
-		ctx:       ctx,
-		cancel:    cancel,
+		workers:   workers,
 	}
 }
 
@@ -40,7 +58,7 @@ func (s *EventService) Start() error {
 	s.logger.Info("Starting Event Service...")
 
 	// 1. Inicializar streams no Redis
-	if err := s.publisher.InitializeStreams(s.ctx); err != nil {
+	if err := s.publisher.InitializeStreams(s.workers.Context()); err != nil {
 		return err
 	}
 
@@ -54,36 +72,28 @@ func (s *EventService) Start() error {
 	return nil
 }
 
-// Stop para o service de eventos
-func (s *EventService) Stop() {
-	s.logger.Info("Stopping Event Service...")
-
-	s.cancel()  // Cancela o contexto
-	s.wg.Wait() // Aguarda todas as goroutines terminarem
-
-	s.logger.Info("Event Service stopped")
-}
-
 // Publisher retorna o publisher para uso em use cases
 func (s *EventService) Publisher() events.Publisher {
 	return s.publisher
 }
 
-// registerEventHandlers registra todos os handlers de eventos
+// registerEventHandlers registra os handlers de cada consumer group
+// Cada grupo executa apenas os seus handlers, então a falha de um não bloqueia o ACK dos outros
 func (s *EventService) registerEventHandlers() {
 	// Handlers para notificações
-	notificationHandler := NewNotificationHandler(s.logger)
-	s.consumer.RegisterHandler(events.EventTypePositionChanged, notificationHandler)
-	s.consumer.RegisterHandler(events.EventTypeUserEnteredSector, notificationHandler)
-	s.consumer.RegisterHandler(events.EventTypeUserLeftSector, notificationHandler)
+	sectorEntryThrottle := time.Duration(s.config.Events.SectorEntryThrottleSeconds) * time.Second
+	notificationHandler := NewNotificationHandler(s.throttle, sectorEntryThrottle, s.logger)
+	s.consumer.RegisterHandler(events.ConsumerGroupNotifications, events.EventTypePositionChanged, notificationHandler)
+	s.consumer.RegisterHandler(events.ConsumerGroupNotifications, events.EventTypeUserEnteredSector, notificationHandler)
+	s.consumer.RegisterHandler(events.ConsumerGroupNotifications, events.EventTypeUserLeftSector, notificationHandler)
 
 	// Handlers para analytics
 	analyticsHandler := NewAnalyticsHandler(s.logger)
-	s.consumer.RegisterHandler(events.EventTypePositionChanged, analyticsHandler)
+	s.consumer.RegisterHandler(events.ConsumerGroupAnalytics, events.EventTypePositionChanged, analyticsHandler)
 
 	// Handlers para tempo real
-	realtimeHandler := NewRealtimeHandler(s.logger)
-	s.consumer.RegisterHandler(events.EventTypePositionChanged, realtimeHandler)
+	realtimeHandler := NewRealtimeHandler(s.realtime, s.logger)
+	s.consumer.RegisterHandler(events.ConsumerGroupRealtime, events.EventTypePositionChanged, realtimeHandler)
 
 	s.logger.Info("Event handlers registered",
 		"notification_types", 3,
@@ -101,6 +111,13 @@ func (s *EventService) startConsumers() {
 		"notification-worker-1",
 	)
 
+	// Consumer para notificações de entrada/saída de setor
+	s.startConsumer(
+		events.StreamSectorEvents,
+		events.ConsumerGroupNotifications,
+		"notification-sector-worker-1",
+	)
+
 	// Consumer para analytics
 	s.startConsumer(
 		events.StreamPositionEvents,
@@ -116,13 +133,9 @@ func (s *EventService) startConsumers() {
 	)
 }
 
-// startConsumer inicia um consumer específico
+// startConsumer inicia um consumer específico como worker da aplicação
 func (s *EventService) startConsumer(streamName, consumerGroup, consumerName string) {
-	s.wg.Add(1)
-
-	go func() {
-		defer s.wg.Done()
-
+	s.workers.Go("consumer:"+consumerName, func(ctx context.Context) {
 		s.logger.Info("Starting consumer",
 			"stream", streamName,
 			"group", consumerGroup,
@@ -130,7 +143,7 @@ func (s *EventService) startConsumer(streamName, consumerGroup, consumerName str
 		)
 
 		// Subscribe ao stream
-		eventChan, err := s.consumer.Subscribe(s.ctx, streamName, consumerGroup, consumerName)
+		eventChan, err := s.consumer.Subscribe(ctx, streamName, consumerGroup, consumerName)
 		if err != nil {
 			s.logger.Error("Failed to subscribe consumer",
 				"stream", streamName,
@@ -142,54 +155,128 @@ func (s *EventService) startConsumer(streamName, consumerGroup, consumerName str
 		}
 
 		// Processar eventos
-		s.consumer.ProcessEvents(s.ctx, eventChan, streamName, consumerGroup)
+		s.consumer.ProcessEvents(ctx, eventChan, streamName, consumerGroup)
 
 		s.logger.Info("Consumer stopped",
 			"stream", streamName,
 			"group", consumerGroup,
 			"consumer", consumerName,
 		)
-	}()
+	})
 }
 
-// GetStats retorna estatísticas dos streams
+// GetStats retorna estatísticas dos streams e dos consumer groups
+// Para cada grupo: pendentes sem ACK (XPENDING), último ID entregue e atraso em relação ao stream
 func (s *EventService) GetStats(ctx context.Context) (map[string]interface{}, error) {
-	stats := make(map[string]interface{})
-
-	// Estatísticas do stream de posições
-	positionLen, err := s.publisher.client.XLen(ctx, events.StreamPositionEvents).Result()
+	// Tamanho atual e limite (MAXLEN) de cada stream
+	streamStats, err := s.publisher.StreamStats(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	// Lista básica dos consumer groups conhecidos
-	consumerGroups := []string{
-		events.ConsumerGroupNotifications,
-		events.ConsumerGroupAnalytics,
-		events.ConsumerGroupRealtime,
+	// Estado dos grupos em cada stream (XINFO GROUPS + XPENDING)
+	groupStats, err := s.publisher.ConsumerGroupStats(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	stats["streams"] = map[string]interface{}{
-		events.StreamPositionEvents: map[string]interface{}{
-			"length": positionLen,
-			"groups": len(consumerGroups),
-		},
+	consumerGroups := make(map[string]interface{})
+	for stream, groups := range groupStats {
+		if streamStat, ok := streamStats[stream].(map[string]interface{}); ok {
+			streamStat["groups"] = len(groups)
+		}
+
+		for groupName, groupStat := range groups {
+			summary, ok := consumerGroups[groupName].(map[string]interface{})
+			if !ok {
+				summary = map[string]interface{}{
+					"name":      groupName,
+					"pending":   int64(0),
+					"lag":       int64(0),
+					"lag_exact": true,
+					"streams":   make(map[string]*ConsumerGroupStats),
+				}
+				consumerGroups[groupName] = summary
+			}
+
+			// Totais do grupo somando todos os streams
+			summary["pending"] = summary["pending"].(int64) + groupStat.Pending
+			summary["lag"] = summary["lag"].(int64) + groupStat.Lag
+			summary["lag_exact"] = summary["lag_exact"].(bool) && groupStat.LagExact
+			summary["streams"].(map[string]*ConsumerGroupStats)[stream] = groupStat
+		}
 	}
 
-	stats["consumer_groups"] = make(map[string]interface{})
-	for _, groupName := range consumerGroups {
-		// Para cada grupo, tentamos obter informações básicas
-		stats["consumer_groups"].(map[string]interface{})[groupName] = map[string]interface{}{
-			"name":   groupName,
-			"active": true, // Simplificado - assumimos que estão ativos
+	return map[string]interface{}{
+		"streams":         streamStats,
+		"consumer_groups": consumerGroups,
+		"generated_at":    time.Now().UTC(),
+	}, nil
+}
+
+// ReplayRequest define a janela e o destino de um replay de eventos
+type ReplayRequest struct {
+	Stream string   `json:"stream"`           // Stream gerenciado (ex.: geolocation:position-events)
+	Start  string   `json:"start,omitempty"`  // ID do stream ou timestamp RFC3339 (vazio: início)
+	End    string   `json:"end,omitempty"`    // ID do stream ou timestamp RFC3339 (vazio: fim)
+	Groups []string `json:"groups,omitempty"` // Consumer groups cujos handlers recebem os eventos (vazio: todos)
+	Limit  int      `json:"limit,omitempty"`  // Máximo de entradas lidas (padrão e teto: MaxReplayEvents)
+}
+
+// Replay reentrega aos handlers os eventos de um stream dentro da janela, sem publicá-los de novo
+// Erros de validação vêm embrulhados em ErrInvalidReplayRange
+func (s *EventService) Replay(ctx context.Context, req ReplayRequest) (*ReplayResult, error) {
+	if !isManagedStream(req.Stream) {
+		return nil, fmt.Errorf("%w: unknown stream %q", ErrInvalidReplayRange, req.Stream)
+	}
+	for _, group := range req.Groups {
+		if !s.consumer.HasGroup(group) {
+			return nil, fmt.Errorf("%w: unknown consumer group %q", ErrInvalidReplayRange, group)
 		}
 	}
+	if req.Limit < 0 || req.Limit > MaxReplayEvents {
+		return nil, fmt.Errorf("%w: limit must be between 0 (maximum) and %d", ErrInvalidReplayRange, MaxReplayEvents)
+	}
+
+	start, err := ParseReplayBoundary(req.Start, false)
+	if err != nil {
+		return nil, err
+	}
+	end, err := ParseReplayBoundary(req.End, true)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Replaying events",
+		"stream", req.Stream,
+		"start", start,
+		"end", end,
+		"groups", req.Groups,
+	)
 
-	// Adicionar timestamp da consulta
-	stats["generated_at"] = ctx.Value("timestamp")
-	if stats["generated_at"] == nil {
-		stats["generated_at"] = "now"
+	result, err := s.consumer.Replay(ctx, req.Stream, start, end, req.Groups, req.Limit)
+	if err != nil {
+		return nil, err
 	}
 
-	return stats, nil
+	s.logger.Info("Events replayed",
+		"stream", result.Stream,
+		"read", result.Read,
+		"replayed", result.Replayed,
+		"failed", result.Failed,
+		"skipped", result.Skipped,
+		"truncated", result.Truncated,
+	)
+
+	return result, nil
+}
+
+// isManagedStream indica se o stream é um dos criados pelo publisher
+func isManagedStream(streamName string) bool {
+	for _, stream := range managedStreams() {
+		if stream == streamName {
+			return true
+		}
+	}
+	return false
 }
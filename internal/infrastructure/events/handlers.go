@@ -2,7 +2,9 @@ package events
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/vitao/geolocation-tracker/internal/domain/events"
 	"github.com/vitao/geolocation-tracker/pkg/logger"
@@ -10,13 +12,18 @@ import (
 
 // NotificationHandler processa eventos para enviar notificações
 type NotificationHandler struct {
-	logger logger.Logger
+	throttle            NotificationThrottle
+	sectorEntryThrottle time.Duration
+	logger              logger.Logger
 }
 
 // NewNotificationHandler cria um novo handler de notificações
-func NewNotificationHandler(logger logger.Logger) *NotificationHandler {
+// sectorEntryThrottle é a janela de deduplicação de entradas em setor (0 desabilita)
+func NewNotificationHandler(throttle NotificationThrottle, sectorEntryThrottle time.Duration, logger logger.Logger) *NotificationHandler {
 	return &NotificationHandler{
-		logger: logger,
+		throttle:            throttle,
+		sectorEntryThrottle: sectorEntryThrottle,
+		logger:              logger,
 	}
 }
 
@@ -76,6 +83,16 @@ func (h *NotificationHandler) handleUserEnteredSector(ctx context.Context, event
 	sectorID, _ := event.Data["sector_id"].(string)
 	usersInSector, _ := event.Data["users_in_sector"].(float64) // JSON numbers are float64
 
+	// Usuário andando na borda do setor entra repetidamente: notificar no máximo uma vez por janela
+	if !h.allowSectorEntry(ctx, event.UserID, sectorID) {
+		h.logger.Debug("Sector entry notification throttled",
+			"user_id", event.UserID,
+			"sector_id", sectorID,
+			"window", h.sectorEntryThrottle,
+		)
+		return nil
+	}
+
 	h.logger.Info("User Entered Sector Notification",
 		"user_id", event.UserID,
 		"sector_id", sectorID,
@@ -94,6 +111,25 @@ func (h *NotificationHandler) handleUserEnteredSector(ctx context.Context, event
 	return nil
 }
 
+// allowSectorEntry verifica o throttle de entrada por usuário e setor
+// Falhas no Redis não bloqueiam a notificação
+func (h *NotificationHandler) allowSectorEntry(ctx context.Context, userID, sectorID string) bool {
+	if h.throttle == nil || h.sectorEntryThrottle <= 0 {
+		return true
+	}
+
+	allowed, err := h.throttle.Allow(ctx, fmt.Sprintf(sectorEntryThrottleKey, userID, sectorID), h.sectorEntryThrottle)
+	if err != nil {
+		h.logger.Warn("Sector entry throttle unavailable, notifying anyway",
+			"user_id", userID,
+			"sector_id", sectorID,
+			"error", err,
+		)
+		return true
+	}
+	return allowed
+}
+
 // handleUserLeftSector processa eventos de saída de setor
 func (h *NotificationHandler) handleUserLeftSector(ctx context.Context, event *events.Event) error {
 	sectorID, _ := event.Data["sector_id"].(string)
@@ -137,12 +173,16 @@ func (h *AnalyticsHandler) CanHandle(eventType events.EventType) bool {
 // trackPositionChange registra métricas de mudança de posição
 func (h *AnalyticsHandler) trackPositionChange(ctx context.Context, event *events.Event) error {
 	distanceMoved, _ := event.Data["distance_moved"].(float64)
+	speedKmh, _ := event.Data["speed_kmh"].(float64)
+	suspicious, _ := event.Data["suspicious"].(bool)
 	newSector, _ := event.Data["new_sector"].(string)
 	previousSector, _ := event.Data["previous_sector"].(string)
 
 	h.logger.Info("Analytics: Position Change",
 		"user_id", event.UserID,
 		"distance_moved", distanceMoved,
+		"speed_kmh", speedKmh,
+		"suspicious", suspicious,
 		"sector_changed", newSector != previousSector,
 		"new_sector", newSector,
 		"timestamp", event.Timestamp.Format("15:04:05"),
@@ -151,15 +191,32 @@ func (h *AnalyticsHandler) trackPositionChange(ctx context.Context, event *event
 	return nil
 }
 
+// Broadcaster distribui mensagens aos clientes conectados (ex: hub WebSocket)
+// Broadcast não deve bloquear o consumer
+type Broadcaster interface {
+	Broadcast(message []byte, sectorIDs ...string)
+}
+
+// RealtimeMessage é o payload enviado aos clientes em tempo real
+type RealtimeMessage struct {
+	Type      events.EventType       `json:"type"`
+	EventID   string                 `json:"event_id"`
+	UserID    string                 `json:"user_id"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data"`
+}
+
 // RealtimeHandler processa eventos para atualizações em tempo real
 type RealtimeHandler struct {
-	logger logger.Logger
+	broadcaster Broadcaster
+	logger      logger.Logger
 }
 
 // NewRealtimeHandler cria um novo handler de tempo real
-func NewRealtimeHandler(logger logger.Logger) *RealtimeHandler {
+func NewRealtimeHandler(broadcaster Broadcaster, logger logger.Logger) *RealtimeHandler {
 	return &RealtimeHandler{
-		logger: logger,
+		broadcaster: broadcaster,
+		logger:      logger,
 	}
 }
 
@@ -183,8 +240,27 @@ func (h *RealtimeHandler) broadcastPositionUpdate(ctx context.Context, event *ev
 	newLat, _ := event.Data["new_lat"].(float64)
 	newLng, _ := event.Data["new_lng"].(float64)
 	newSector, _ := event.Data["new_sector"].(string)
+	previousSector, _ := event.Data["previous_sector"].(string)
+
+	message, err := json.Marshal(RealtimeMessage{
+		Type:      event.Type,
+		EventID:   event.ID,
+		UserID:    event.UserID,
+		Timestamp: event.Timestamp,
+		Data:      event.Data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode realtime message: %w", err)
+	}
+
+	// Inscritos no setor de origem também recebem a saída do usuário
+	sectors := []string{newSector}
+	if previousSector != "" && previousSector != newSector {
+		sectors = append(sectors, previousSector)
+	}
+	h.broadcaster.Broadcast(message, sectors...)
 
-	h.logger.Info("Realtime: Broadcasting Position Update",
+	h.logger.Debug("Realtime: Broadcasting Position Update",
 		"user_id", event.UserID,
 		"position", fmt.Sprintf("%.6f,%.6f", newLat, newLng),
 		"sector", newSector,
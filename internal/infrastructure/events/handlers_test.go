@@ -0,0 +1,125 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	domainEvents "github.com/vitao/geolocation-tracker/internal/domain/events"
+)
+
+// memoryThrottle simula o throttle Redis em memória
+type memoryThrottle struct {
+	now     time.Time
+	expires map[string]time.Time
+}
+
+func newMemoryThrottle() *memoryThrottle {
+	return &memoryThrottle{now: time.Now(), expires: make(map[string]time.Time)}
+}
+
+func (t *memoryThrottle) Allow(ctx context.Context, key string, window time.Duration) (bool, error) {
+	if expiresAt, ok := t.expires[key]; ok && t.now.Before(expiresAt) {
+		return false, nil
+	}
+	t.expires[key] = t.now.Add(window)
+	return true, nil
+}
+
+// recordingLogger guarda as mensagens de Info para verificar notificações enviadas
+type recordingLogger struct {
+	nopLogger
+	infos []string
+}
+
+func (l *recordingLogger) Info(msg string, fields ...interface{}) {
+	l.infos = append(l.infos, msg)
+}
+
+func (l *recordingLogger) count(msg string) int {
+	total := 0
+	for _, m := range l.infos {
+		if m == msg {
+			total++
+		}
+	}
+	return total
+}
+
+func newEnteredSectorEvent(userID, sectorID string) *domainEvents.Event {
+	return domainEvents.NewSectorChangedEvent(userID, "test", domainEvents.EventTypeUserEnteredSector, domainEvents.SectorChangedData{
+		SectorID: sectorID,
+	})
+}
+
+// TestNotificationHandler_SectorEntryThrottled testa que a primeira entrada notifica e a reentrada rápida é suprimida
+func TestNotificationHandler_SectorEntryThrottled(t *testing.T) {
+	throttle := newMemoryThrottle()
+	log := &recordingLogger{}
+	handler := NewNotificationHandler(throttle, time.Minute, log)
+
+	require.NoError(t, handler.Handle(context.Background(), newEnteredSectorEvent("user123", "sector_1_1")))
+	assert.Equal(t, 1, log.count("User Entered Sector Notification"))
+
+	// Reentrada no mesmo setor dentro da janela
+	throttle.now = throttle.now.Add(10 * time.Second)
+	require.NoError(t, handler.Handle(context.Background(), newEnteredSectorEvent("user123", "sector_1_1")))
+	assert.Equal(t, 1, log.count("User Entered Sector Notification"))
+
+	// Outro setor não é afetado
+	require.NoError(t, handler.Handle(context.Background(), newEnteredSectorEvent("user123", "sector_1_2")))
+	assert.Equal(t, 2, log.count("User Entered Sector Notification"))
+
+	// Após a janela, a entrada volta a notificar
+	throttle.now = throttle.now.Add(time.Minute)
+	require.NoError(t, handler.Handle(context.Background(), newEnteredSectorEvent("user123", "sector_1_1")))
+	assert.Equal(t, 3, log.count("User Entered Sector Notification"))
+}
+
+// TestNotificationHandler_SectorEntryThrottleDisabled testa que janela zero notifica sempre
+func TestNotificationHandler_SectorEntryThrottleDisabled(t *testing.T) {
+	log := &recordingLogger{}
+	handler := NewNotificationHandler(newMemoryThrottle(), 0, log)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, handler.Handle(context.Background(), newEnteredSectorEvent("user123", "sector_1_1")))
+	}
+	assert.Equal(t, 3, log.count("User Entered Sector Notification"))
+}
+
+// recordingBroadcaster guarda as mensagens enviadas ao hub
+type recordingBroadcaster struct {
+	messages [][]byte
+	sectors  [][]string
+}
+
+func (b *recordingBroadcaster) Broadcast(message []byte, sectorIDs ...string) {
+	b.messages = append(b.messages, message)
+	b.sectors = append(b.sectors, sectorIDs)
+}
+
+// TestRealtimeHandler_BroadcastsPositionChanged testa o payload e os setores de origem e destino
+func TestRealtimeHandler_BroadcastsPositionChanged(t *testing.T) {
+	broadcaster := &recordingBroadcaster{}
+	handler := NewRealtimeHandler(broadcaster, nopLogger{})
+
+	event := domainEvents.NewPositionChangedEvent("user123", "test", domainEvents.PositionChangedData{
+		NewLat:         -23.55,
+		NewLng:         -46.63,
+		PreviousSector: "sector_1_1",
+		NewSector:      "sector_1_2",
+	})
+	require.NoError(t, handler.Handle(context.Background(), event))
+
+	require.Len(t, broadcaster.messages, 1)
+	assert.Equal(t, []string{"sector_1_2", "sector_1_1"}, broadcaster.sectors[0])
+
+	var message RealtimeMessage
+	require.NoError(t, json.Unmarshal(broadcaster.messages[0], &message))
+	assert.Equal(t, domainEvents.EventTypePositionChanged, message.Type)
+	assert.Equal(t, "user123", message.UserID)
+	assert.Equal(t, -23.55, message.Data["new_lat"])
+}
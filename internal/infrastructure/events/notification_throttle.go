@@ -0,0 +1,40 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// sectorEntryThrottleKey identifica a última notificação de entrada de um usuário em um setor
+const sectorEntryThrottleKey = "notification:sector_entered:%s:%s"
+
+// NotificationThrottle limita a frequência de notificações repetidas
+type NotificationThrottle interface {
+	// Allow retorna true se a notificação identificada por key pode ser enviada
+	// Chamadas seguintes com a mesma key dentro da janela retornam false
+	Allow(ctx context.Context, key string, window time.Duration) (bool, error)
+}
+
+// RedisNotificationThrottle implementa NotificationThrottle com chaves Redis com TTL
+type RedisNotificationThrottle struct {
+	client *redis.Client
+}
+
+// NewRedisNotificationThrottle cria um novo throttle baseado em Redis
+func NewRedisNotificationThrottle(client *redis.Client) *RedisNotificationThrottle {
+	return &RedisNotificationThrottle{
+		client: client,
+	}
+}
+
+// Allow usa SET NX com TTL: apenas a primeira chamada da janela cria a chave
+func (t *RedisNotificationThrottle) Allow(ctx context.Context, key string, window time.Duration) (bool, error) {
+	allowed, err := t.client.SetNX(ctx, key, time.Now().Unix(), window).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check notification throttle: %w", err)
+	}
+	return allowed, nil
+}
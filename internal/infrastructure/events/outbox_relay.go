@@ -0,0 +1,201 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domainEvents "github.com/vitao/geolocation-tracker/internal/domain/events"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// Padrões do relay de outbox (usados quando a configuração não é positiva)
+const (
+	DefaultOutboxPollInterval = 500 * time.Millisecond
+	DefaultOutboxBatchSize    = 100
+	DefaultOutboxMaxAttempts  = 20
+
+	// outboxPurgeInterval é o intervalo entre as limpezas de eventos já publicados
+	outboxPurgeInterval = time.Hour
+
+	// outboxDrainTimeout limita a última leitura da outbox no shutdown (abaixo do prazo dos workers)
+	outboxDrainTimeout = 5 * time.Second
+)
+
+// OutboxRelay publica no Redis os eventos gravados na outbox junto com a posição
+// A entrega é at-least-once: se a marcação falhar depois do XADD, o evento é publicado de novo
+// (com o mesmo ID) e os consumers devem tolerar duplicatas
+type OutboxRelay struct {
+	outbox    repository.OutboxRepository
+	publisher domainEvents.Publisher
+	logger    logger.Logger
+
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	retention   time.Duration // 0 mantém os eventos publicados
+}
+
+// NewOutboxRelay cria o relay com o intervalo, o lote, as tentativas e a retenção de EVENTS_OUTBOX_*
+func NewOutboxRelay(
+	outbox repository.OutboxRepository,
+	publisher domainEvents.Publisher,
+	cfg *config.Config,
+	logger logger.Logger,
+) *OutboxRelay {
+	interval := time.Duration(cfg.Events.OutboxPollIntervalMs) * time.Millisecond
+	if interval <= 0 {
+		interval = DefaultOutboxPollInterval
+	}
+
+	batchSize := cfg.Events.OutboxBatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultOutboxBatchSize
+	}
+
+	maxAttempts := cfg.Events.OutboxMaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultOutboxMaxAttempts
+	}
+
+	return &OutboxRelay{
+		outbox:      outbox,
+		publisher:   publisher,
+		logger:      logger,
+		interval:    interval,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+		retention:   time.Duration(cfg.Events.OutboxRetentionHours) * time.Hour,
+	}
+}
+
+// Run lê a outbox a cada intervalo até o ctx terminar
+// No encerramento faz uma última leitura com prazo próprio, para não deixar eventos para o próximo boot
+func (r *OutboxRelay) Run(ctx context.Context) {
+	r.logger.Info("Starting outbox relay",
+		"interval", r.interval.String(),
+		"batch_size", r.batchSize,
+		"max_attempts", r.maxAttempts,
+		"retention", r.retention.String(),
+	)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	purgeTicker := time.NewTicker(outboxPurgeInterval)
+	defer purgeTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.drain()
+			return
+		case <-ticker.C:
+			r.relayAll(ctx)
+		case <-purgeTicker.C:
+			r.purgePublished(ctx)
+		}
+	}
+}
+
+// relayAll publica lotes seguidos enquanto a outbox devolver lotes cheios
+func (r *OutboxRelay) relayAll(ctx context.Context) {
+	for ctx.Err() == nil {
+		published, err := r.RelayPending(ctx)
+		if err != nil || published < r.batchSize {
+			return
+		}
+	}
+}
+
+// drain faz a última leitura da outbox no shutdown
+func (r *OutboxRelay) drain() {
+	ctx, cancel := context.WithTimeout(context.Background(), outboxDrainTimeout)
+	defer cancel()
+
+	r.relayAll(ctx)
+	r.logger.Info("Outbox relay stopped")
+}
+
+// RelayPending publica um lote de eventos pendentes, em ordem de gravação, e retorna quantos foram publicados
+// Uma falha do Redis interrompe o lote para preservar a ordem; o evento continua pendente para a próxima leitura
+// até esgotar maxAttempts. Eventos descartados (dead) não voltam mais em FindPending
+func (r *OutboxRelay) RelayPending(ctx context.Context) (int, error) {
+	entries, err := r.outbox.FindPending(ctx, r.batchSize)
+	if err != nil {
+		r.logger.Error("Failed to read event outbox", "error", err)
+		return 0, err
+	}
+
+	published := 0
+	for _, entry := range entries {
+		if err := r.publisher.Publish(ctx, entry.Stream, entry.Event); err != nil {
+			attempts := entry.Attempts + 1
+			r.logger.Error("Failed to relay outbox event",
+				"outbox_id", entry.ID,
+				"stream", entry.Stream,
+				"event_type", entry.Event.Type,
+				"event_id", entry.Event.ID,
+				"attempts", attempts,
+				"error", err,
+			)
+
+			// Tipo não permitido no stream não se resolve com novas tentativas; as demais falhas têm um limite
+			if errors.Is(err, ErrEventTypeNotAllowed) || attempts >= r.maxAttempts {
+				r.discard(ctx, entry, attempts, err)
+				continue
+			}
+
+			if markErr := r.outbox.MarkFailed(ctx, entry.ID, err.Error()); markErr != nil {
+				r.logger.Error("Failed to record outbox event failure", "outbox_id", entry.ID, "error", markErr)
+			}
+			return published, err
+		}
+
+		if err := r.outbox.MarkPublished(ctx, entry.ID); err != nil {
+			// O evento já está no stream e será publicado de novo na próxima leitura
+			r.logger.Error("Failed to mark outbox event as published",
+				"outbox_id", entry.ID,
+				"event_id", entry.Event.ID,
+				"error", err,
+			)
+			return published, err
+		}
+		published++
+	}
+
+	if published > 0 {
+		r.logger.Debug("Outbox events relayed", "count", published)
+	}
+	return published, nil
+}
+
+// discard marca o evento como dead para que ele saia da fila sem bloquear os seguintes
+func (r *OutboxRelay) discard(ctx context.Context, entry *repository.OutboxEntry, attempts int, cause error) {
+	r.logger.Warn("Discarding outbox event",
+		"outbox_id", entry.ID,
+		"stream", entry.Stream,
+		"event_type", entry.Event.Type,
+		"event_id", entry.Event.ID,
+		"attempts", attempts,
+		"error", cause,
+	)
+	if err := r.outbox.MarkDead(ctx, entry.ID, cause.Error()); err != nil {
+		r.logger.Error("Failed to discard outbox event", "outbox_id", entry.ID, "error", err)
+	}
+}
+
+// purgePublished remove os eventos publicados há mais tempo que a retenção
+func (r *OutboxRelay) purgePublished(ctx context.Context) {
+	if r.retention <= 0 {
+		return
+	}
+
+	cutoff := valueobject.NewTimestamp(time.Now().Add(-r.retention))
+	if _, err := r.outbox.DeletePublishedBefore(ctx, cutoff); err != nil {
+		r.logger.Error("Failed to purge event outbox", "error", err)
+	}
+}
@@ -0,0 +1,217 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	domainEvents "github.com/vitao/geolocation-tracker/internal/domain/events"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+)
+
+// memoryOutbox guarda a outbox em memória e registra publicações, falhas e descartes
+type memoryOutbox struct {
+	entries   []*repository.OutboxEntry
+	published []int64
+	failed    map[int64]string
+	dead      map[int64]string
+}
+
+func newMemoryOutbox(entries ...*repository.OutboxEntry) *memoryOutbox {
+	return &memoryOutbox{entries: entries, failed: make(map[int64]string), dead: make(map[int64]string)}
+}
+
+func (o *memoryOutbox) FindPending(ctx context.Context, limit int) ([]*repository.OutboxEntry, error) {
+	var pending []*repository.OutboxEntry
+	for _, entry := range o.entries {
+		_, dead := o.dead[entry.ID]
+		if !o.isPublished(entry.ID) && !dead && len(pending) < limit {
+			pending = append(pending, entry)
+		}
+	}
+	return pending, nil
+}
+
+func (o *memoryOutbox) MarkPublished(ctx context.Context, id int64) error {
+	o.published = append(o.published, id)
+	return nil
+}
+
+func (o *memoryOutbox) MarkFailed(ctx context.Context, id int64, reason string) error {
+	o.failed[id] = reason
+	o.entry(id).Attempts++
+	return nil
+}
+
+func (o *memoryOutbox) MarkDead(ctx context.Context, id int64, reason string) error {
+	o.dead[id] = reason
+	o.entry(id).Attempts++
+	return nil
+}
+
+func (o *memoryOutbox) DeletePublishedBefore(ctx context.Context, before *valueobject.Timestamp) (int, error) {
+	return 0, nil
+}
+
+func (o *memoryOutbox) entry(id int64) *repository.OutboxEntry {
+	for _, entry := range o.entries {
+		if entry.ID == id {
+			return entry
+		}
+	}
+	return nil
+}
+
+func (o *memoryOutbox) isPublished(id int64) bool {
+	for _, published := range o.published {
+		if published == id {
+			return true
+		}
+	}
+	return false
+}
+
+// newOutboxEntry cria um evento de posição pendente na outbox
+func newOutboxEntry(id int64, eventType domainEvents.EventType) *repository.OutboxEntry {
+	return &repository.OutboxEntry{
+		ID:     id,
+		Stream: domainEvents.StreamPositionEvents,
+		Event:  &domainEvents.Event{ID: "evt", Type: eventType, UserID: "user123"},
+	}
+}
+
+// newTestRelay cria um relay sobre a outbox em memória e um publisher que falha as primeiras failures chamadas
+func newTestRelay(outbox *memoryOutbox, failures int) (*OutboxRelay, *flakyStreamClient) {
+	publisher, client := newFlakyPublisher(failures, 1)
+	cfg := &config.Config{Events: config.EventsConfig{OutboxBatchSize: 2}}
+	return NewOutboxRelay(outbox, publisher, cfg, nopLogger{}), client
+}
+
+// TestOutboxRelay_PublishesInOrder testa que os eventos pendentes são publicados e marcados em ordem
+func TestOutboxRelay_PublishesInOrder(t *testing.T) {
+	outbox := newMemoryOutbox(
+		newOutboxEntry(1, domainEvents.EventTypePositionChanged),
+		newOutboxEntry(2, domainEvents.EventTypePositionChanged),
+	)
+	relay, client := newTestRelay(outbox, 0)
+
+	published, err := relay.RelayPending(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, []int64{1, 2}, outbox.published)
+	assert.Equal(t, 2, client.calls)
+	assert.Empty(t, outbox.failed)
+}
+
+// TestOutboxRelay_StopsOnRedisFailure testa que uma falha do Redis mantém o evento e os seguintes pendentes
+func TestOutboxRelay_StopsOnRedisFailure(t *testing.T) {
+	outbox := newMemoryOutbox(
+		newOutboxEntry(1, domainEvents.EventTypePositionChanged),
+		newOutboxEntry(2, domainEvents.EventTypePositionChanged),
+	)
+	relay, client := newTestRelay(outbox, 1)
+
+	published, err := relay.RelayPending(context.Background())
+
+	var publishErr *domainEvents.PublishError
+	require.ErrorAs(t, err, &publishErr)
+	assert.Equal(t, 0, published)
+	assert.Empty(t, outbox.published)
+	assert.Contains(t, outbox.failed[1], "connection reset")
+	assert.Equal(t, 1, client.calls, "later events wait for the failed one")
+
+	// Redis de volta: a próxima leitura publica tudo na ordem original
+	published, err = relay.RelayPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, []int64{1, 2}, outbox.published)
+}
+
+// TestOutboxRelay_SkipsNotAllowedEvents testa que um tipo recusado pelo stream não bloqueia a outbox
+func TestOutboxRelay_SkipsNotAllowedEvents(t *testing.T) {
+	outbox := newMemoryOutbox(
+		newOutboxEntry(1, domainEvents.EventTypeUserNearby),
+		newOutboxEntry(2, domainEvents.EventTypePositionChanged),
+	)
+	relay, _ := newTestRelay(outbox, 0)
+
+	published, err := relay.RelayPending(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, []int64{2}, outbox.published)
+	assert.Contains(t, outbox.dead[1], "not allowed")
+	assert.Empty(t, outbox.failed)
+}
+
+// TestOutboxRelay_DeadEventsDoNotStallQueue testa que um lote cheio de tipos recusados não impede a publicação dos seguintes
+func TestOutboxRelay_DeadEventsDoNotStallQueue(t *testing.T) {
+	outbox := newMemoryOutbox(
+		newOutboxEntry(1, domainEvents.EventTypeUserNearby),
+		newOutboxEntry(2, domainEvents.EventTypeUserNearby),
+		newOutboxEntry(3, domainEvents.EventTypePositionChanged),
+	)
+	relay, _ := newTestRelay(outbox, 0)
+
+	// Lote de 2: a primeira leitura só encontra eventos recusados e os descarta
+	published, err := relay.RelayPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, published)
+	assert.Len(t, outbox.dead, 2)
+
+	// A leitura seguinte já não os devolve e chega ao evento válido
+	published, err = relay.RelayPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, []int64{3}, outbox.published)
+}
+
+// TestOutboxRelay_DiscardsAfterMaxAttempts testa que falhas repetidas do Redis têm limite de tentativas
+func TestOutboxRelay_DiscardsAfterMaxAttempts(t *testing.T) {
+	outbox := newMemoryOutbox(
+		newOutboxEntry(1, domainEvents.EventTypePositionChanged),
+		newOutboxEntry(2, domainEvents.EventTypePositionChanged),
+	)
+	publisher, _ := newFlakyPublisher(3, 1)
+	cfg := &config.Config{Events: config.EventsConfig{OutboxBatchSize: 2, OutboxMaxAttempts: 2}}
+	relay := NewOutboxRelay(outbox, publisher, cfg, nopLogger{})
+
+	// 1ª leitura: falha e segue pendente
+	_, err := relay.RelayPending(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, outbox.failed[1], "connection reset")
+	assert.Empty(t, outbox.dead)
+
+	// 2ª leitura: tentativas esgotadas, o evento 1 é descartado; o 2 falha e fica pendente
+	_, err = relay.RelayPending(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, outbox.dead[1], "connection reset")
+	assert.Equal(t, 1, outbox.entry(2).Attempts)
+
+	// Redis de volta: só o evento 2 é publicado
+	published, err := relay.RelayPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, []int64{2}, outbox.published)
+}
+
+// TestOutboxRelay_DrainsOnShutdown testa que o relay publica os pendentes quando o ctx termina
+func TestOutboxRelay_DrainsOnShutdown(t *testing.T) {
+	outbox := newMemoryOutbox(
+		newOutboxEntry(1, domainEvents.EventTypePositionChanged),
+		newOutboxEntry(2, domainEvents.EventTypePositionChanged),
+		newOutboxEntry(3, domainEvents.EventTypePositionChanged),
+	)
+	relay, _ := newTestRelay(outbox, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	relay.Run(ctx)
+
+	// Lote de 2: o dreno continua enquanto os lotes vêm cheios
+	assert.Equal(t, []int64{1, 2, 3}, outbox.published)
+}
@@ -0,0 +1,51 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// processedEventKey identifica um evento já processado por um consumer group
+const processedEventKey = "events:processed:%s:%s"
+
+// ProcessedEventStore registra os eventos já processados por cada consumer group
+// Permite descartar reentregas (crash antes do ACK, publicação duplicada pela outbox)
+type ProcessedEventStore interface {
+	// IsProcessed retorna true se o grupo já processou o evento dentro do TTL
+	IsProcessed(ctx context.Context, consumerGroup, eventID string) (bool, error)
+
+	// MarkProcessed registra o evento como processado pelo grupo durante ttl
+	MarkProcessed(ctx context.Context, consumerGroup, eventID string, ttl time.Duration) error
+}
+
+// RedisProcessedEventStore implementa ProcessedEventStore com chaves Redis com TTL
+type RedisProcessedEventStore struct {
+	client *redis.Client
+}
+
+// NewRedisProcessedEventStore cria um novo registro de eventos processados baseado em Redis
+func NewRedisProcessedEventStore(client *redis.Client) *RedisProcessedEventStore {
+	return &RedisProcessedEventStore{
+		client: client,
+	}
+}
+
+// IsProcessed verifica se a chave do evento existe
+func (s *RedisProcessedEventStore) IsProcessed(ctx context.Context, consumerGroup, eventID string) (bool, error) {
+	count, err := s.client.Exists(ctx, fmt.Sprintf(processedEventKey, consumerGroup, eventID)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check processed event: %w", err)
+	}
+	return count > 0, nil
+}
+
+// MarkProcessed grava a chave do evento com TTL
+func (s *RedisProcessedEventStore) MarkProcessed(ctx context.Context, consumerGroup, eventID string, ttl time.Duration) error {
+	if err := s.client.Set(ctx, fmt.Sprintf(processedEventKey, consumerGroup, eventID), time.Now().Unix(), ttl).Err(); err != nil {
+		return fmt.Errorf("failed to mark event as processed: %w", err)
+	}
+	return nil
+}
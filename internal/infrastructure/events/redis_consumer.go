@@ -2,29 +2,134 @@ package events
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 	domainEvents "github.com/vitao/geolocation-tracker/internal/domain/events"
 	"github.com/vitao/geolocation-tracker/pkg/logger"
+	"github.com/vitao/geolocation-tracker/pkg/metrics"
 )
 
+// DuplicateHandlerPolicy define o comportamento ao registrar o mesmo handler duas vezes
+type DuplicateHandlerPolicy string
+
+const (
+	// DuplicateHandlerIgnore mantém o handler já registrado e descarta o novo
+	DuplicateHandlerIgnore DuplicateHandlerPolicy = "ignore"
+
+	// DuplicateHandlerReplace substitui o handler já registrado pelo novo
+	DuplicateHandlerReplace DuplicateHandlerPolicy = "replace"
+
+	// DuplicateHandlerAllow mantém ambos (cada evento é processado duas vezes)
+	DuplicateHandlerAllow DuplicateHandlerPolicy = "allow"
+)
+
+// ParseDuplicateHandlerPolicy converte string de configuração em política
+// Valores desconhecidos caem no padrão seguro (ignore)
+func ParseDuplicateHandlerPolicy(value string) DuplicateHandlerPolicy {
+	switch DuplicateHandlerPolicy(value) {
+	case DuplicateHandlerReplace:
+		return DuplicateHandlerReplace
+	case DuplicateHandlerAllow:
+		return DuplicateHandlerAllow
+	default:
+		return DuplicateHandlerIgnore
+	}
+}
+
+// AckMode define quando um consumer group confirma (ACK) os eventos
+type AckMode string
+
+const (
+	// AckAtLeastOnce confirma apenas após todos os handlers terem sucesso (falhas são reprocessadas)
+	AckAtLeastOnce AckMode = "at-least-once"
+
+	// AckAtMostOnce confirma antes de processar: menor latência, mas falhas não são reprocessadas
+	// Indicado para handlers de broadcast que toleram perder um evento
+	AckAtMostOnce AckMode = "at-most-once"
+)
+
+// ParseAckMode converte string de configuração em modo de ACK
+// Valores desconhecidos caem no padrão seguro (at-least-once)
+func ParseAckMode(value string) AckMode {
+	if AckMode(value) == AckAtMostOnce {
+		return AckAtMostOnce
+	}
+	return AckAtLeastOnce
+}
+
+// groupHandlers agrupa os handlers de um consumer group por tipo de evento
+type groupHandlers map[domainEvents.EventType][]domainEvents.EventHandler
+
 // RedisStreamConsumer implementa Consumer usando Redis Streams
+// Cada consumer group tem seu próprio conjunto de handlers e faz ACK de forma independente
 type RedisStreamConsumer struct {
-	client   *redis.Client
-	logger   logger.Logger
-	handlers map[domainEvents.EventType][]domainEvents.EventHandler
+	client          *redis.Client
+	logger          logger.Logger
+	handlers        map[string]groupHandlers
+	ackModes        map[string]AckMode
+	duplicatePolicy DuplicateHandlerPolicy
+	metrics         metrics.Collector
+	codec           EventCodec
+
+	// processed descarta eventos já processados pelo grupo (nil desabilita); dedupTTL é a janela
+	processed ProcessedEventStore
+	dedupTTL  time.Duration
 }
 
 // NewRedisStreamConsumer cria uma nova instância do consumer
 func NewRedisStreamConsumer(client *redis.Client, logger logger.Logger) *RedisStreamConsumer {
 	return &RedisStreamConsumer{
-		client:   client,
-		logger:   logger,
-		handlers: make(map[domainEvents.EventType][]domainEvents.EventHandler),
+		client:          client,
+		logger:          logger,
+		handlers:        make(map[string]groupHandlers),
+		ackModes:        make(map[string]AckMode),
+		duplicatePolicy: DuplicateHandlerIgnore,
+		metrics:         metrics.NewNoopCollector(),
+		codec:           NewJSONEnvelopeCodec(),
+	}
+}
+
+// SetCodec troca o decoder das entradas do stream (padrão: JSONEnvelopeCodec)
+func (c *RedisStreamConsumer) SetCodec(codec EventCodec) {
+	c.codec = codec
+}
+
+// SetMetrics configura o collector de métricas de consumo (padrão: no-op)
+func (c *RedisStreamConsumer) SetMetrics(collector metrics.Collector) {
+	c.metrics = collector
+}
+
+// SetDuplicateHandlerPolicy configura o comportamento para registros duplicados
+func (c *RedisStreamConsumer) SetDuplicateHandlerPolicy(policy DuplicateHandlerPolicy) {
+	c.duplicatePolicy = policy
+}
+
+// SetDeduplication ativa o descarte de eventos já processados pelo grupo durante ttl
+// Com ttl <= 0 ou store nil a deduplicação fica desabilitada
+func (c *RedisStreamConsumer) SetDeduplication(store ProcessedEventStore, ttl time.Duration) {
+	if store == nil || ttl <= 0 {
+		c.processed = nil
+		c.dedupTTL = 0
+		return
+	}
+	c.processed = store
+	c.dedupTTL = ttl
+}
+
+// SetAckMode configura o modo de ACK de um consumer group (padrão: at-least-once)
+func (c *RedisStreamConsumer) SetAckMode(consumerGroup string, mode AckMode) {
+	c.ackModes[consumerGroup] = mode
+}
+
+// ackMode retorna o modo de ACK do consumer group
+func (c *RedisStreamConsumer) ackMode(consumerGroup string) AckMode {
+	if mode, ok := c.ackModes[consumerGroup]; ok {
+		return mode
 	}
+	return AckAtLeastOnce
 }
 
 // Subscribe se inscreve em um stream para consumir eventos
@@ -33,14 +138,7 @@ func (c *RedisStreamConsumer) Subscribe(ctx context.Context, streamName, consume
 	eventChan := make(chan *domainEvents.Event, 100)
 
 	// Criar consumer group se não existir
-	err := c.client.XGroupCreate(ctx, streamName, consumerGroup, "$").Err()
-	if err != nil && err.Error() != "BUSYGROUP Consumer Group name already exists" {
-		c.logger.Error("Failed to create consumer group",
-			"stream", streamName,
-			"group", consumerGroup,
-			"error", err,
-		)
-	}
+	c.ensureGroup(ctx, streamName, consumerGroup, "$")
 
 	// Goroutine para consumir eventos continuamente
 	go func() {
@@ -70,6 +168,16 @@ func (c *RedisStreamConsumer) Subscribe(ctx context.Context, streamName, consume
 						// Nenhuma mensagem nova, continuar
 						continue
 					}
+					if isNoGroupError(err) {
+						// Stream removido/recriado (ex.: corte total): recriar o grupo e ler do início
+						c.logger.Warn("Consumer group missing, recreating",
+							"stream", streamName,
+							"group", consumerGroup,
+							"error", err,
+						)
+						c.ensureGroup(ctx, streamName, consumerGroup, "0")
+						continue
+					}
 					c.logger.Error("Failed to read from stream",
 						"stream", streamName,
 						"consumer", consumerName,
@@ -82,7 +190,7 @@ func (c *RedisStreamConsumer) Subscribe(ctx context.Context, streamName, consume
 				// Processar mensagens recebidas
 				for _, stream := range result {
 					for _, message := range stream.Messages {
-						event, err := c.parseMessage(message)
+						event, err := c.codec.Decode(message)
 						if err != nil {
 							c.logger.Error("Failed to parse event message",
 								"stream", streamName,
@@ -118,74 +226,21 @@ func (c *RedisStreamConsumer) Subscribe(ctx context.Context, streamName, consume
 	return eventChan, nil
 }
 
-// parseMessage converte uma mensagem Redis Stream em Event
-func (c *RedisStreamConsumer) parseMessage(message redis.XMessage) (*domainEvents.Event, error) {
-	// Extrair campos da mensagem
-	eventID, ok := message.Values["event_id"].(string)
-	if !ok {
-		return nil, fmt.Errorf("missing or invalid event_id")
-	}
-
-	eventType, ok := message.Values["type"].(string)
-	if !ok {
-		return nil, fmt.Errorf("missing or invalid type")
-	}
-
-	userID, ok := message.Values["user_id"].(string)
-	if !ok {
-		return nil, fmt.Errorf("missing or invalid user_id")
-	}
-
-	eventCtx, ok := message.Values["event_ctx"].(string)
-	if !ok {
-		return nil, fmt.Errorf("missing or invalid event_ctx")
-	}
-
-	timestampStr, ok := message.Values["timestamp"].(string)
-	if !ok {
-		return nil, fmt.Errorf("missing or invalid timestamp")
-	}
-
-	timestamp, err := time.Parse(time.RFC3339Nano, timestampStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse timestamp: %w", err)
-	}
-
-	// Parse data JSON
-	dataStr, ok := message.Values["data"].(string)
-	if !ok {
-		return nil, fmt.Errorf("missing or invalid data")
-	}
-
-	var data map[string]interface{}
-	if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
-		return nil, fmt.Errorf("failed to parse data JSON: %w", err)
-	}
-
-	// Parse metadata JSON
-	metadataStr, ok := message.Values["metadata"].(string)
-	if !ok {
-		return nil, fmt.Errorf("missing or invalid metadata")
-	}
-
-	var metadata domainEvents.EventMetadata
-	if err := json.Unmarshal([]byte(metadataStr), &metadata); err != nil {
-		return nil, fmt.Errorf("failed to parse metadata JSON: %w", err)
-	}
-
-	// Criar evento
-	event := &domainEvents.Event{
-		ID:        eventID,
-		Type:      domainEvents.EventType(eventType),
-		StreamID:  message.ID,
-		UserID:    userID,
-		EventID:   eventCtx,
-		Timestamp: timestamp,
-		Data:      data,
-		Metadata:  metadata,
+// ensureGroup cria o consumer group (e o stream, se necessário) a partir do ID informado
+func (c *RedisStreamConsumer) ensureGroup(ctx context.Context, streamName, consumerGroup, start string) {
+	err := c.client.XGroupCreateMkStream(ctx, streamName, consumerGroup, start).Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		c.logger.Error("Failed to create consumer group",
+			"stream", streamName,
+			"group", consumerGroup,
+			"error", err,
+		)
 	}
+}
 
-	return event, nil
+// isNoGroupError identifica leituras em um grupo (ou stream) que não existe mais
+func isNoGroupError(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "NOGROUP")
 }
 
 // Ack confirma o processamento de um evento
@@ -216,19 +271,48 @@ func (c *RedisStreamConsumer) Close() error {
 	return nil
 }
 
-// RegisterHandler registra um handler para um tipo de evento
-func (c *RedisStreamConsumer) RegisterHandler(eventType domainEvents.EventType, handler domainEvents.EventHandler) {
-	if c.handlers[eventType] == nil {
-		c.handlers[eventType] = make([]domainEvents.EventHandler, 0)
+// RegisterHandler registra um handler para um tipo de evento em um consumer group
+// Handlers do mesmo tipo (Go) no mesmo grupo são considerados duplicados e tratados conforme a política
+func (c *RedisStreamConsumer) RegisterHandler(consumerGroup string, eventType domainEvents.EventType, handler domainEvents.EventHandler) {
+	if c.handlers[consumerGroup] == nil {
+		c.handlers[consumerGroup] = make(groupHandlers)
 	}
-	c.handlers[eventType] = append(c.handlers[eventType], handler)
+	handlers := c.handlers[consumerGroup]
+
+	if index := findHandlerIndex(handlers[eventType], handler); index >= 0 && c.duplicatePolicy != DuplicateHandlerAllow {
+		c.logger.Warn("Duplicate event handler registration",
+			"group", consumerGroup,
+			"event_type", eventType,
+			"handler", fmt.Sprintf("%T", handler),
+			"policy", c.duplicatePolicy,
+		)
+
+		if c.duplicatePolicy == DuplicateHandlerReplace {
+			handlers[eventType][index] = handler
+		}
+		return
+	}
+
+	handlers[eventType] = append(handlers[eventType], handler)
 
 	c.logger.Info("Event handler registered",
+		"group", consumerGroup,
 		"event_type", eventType,
-		"handler_count", len(c.handlers[eventType]),
+		"handler_count", len(handlers[eventType]),
 	)
 }
 
+// findHandlerIndex retorna a posição de um handler do mesmo tipo já registrado (-1 se não houver)
+func findHandlerIndex(registered []domainEvents.EventHandler, handler domainEvents.EventHandler) int {
+	handlerType := fmt.Sprintf("%T", handler)
+	for i, h := range registered {
+		if fmt.Sprintf("%T", h) == handlerType {
+			return i
+		}
+	}
+	return -1
+}
+
 // ProcessEvents processa eventos usando handlers registrados
 func (c *RedisStreamConsumer) ProcessEvents(ctx context.Context, eventChan <-chan *domainEvents.Event, streamName, consumerGroup string) {
 	for {
@@ -249,25 +333,85 @@ func (c *RedisStreamConsumer) ProcessEvents(ctx context.Context, eventChan <-cha
 	}
 }
 
-// processEvent processa um evento individual
-func (c *RedisStreamConsumer) processEvent(ctx context.Context, event *domainEvents.Event, streamName, consumerGroup string) {
-	handlers, exists := c.handlers[event.Type]
-	if !exists || len(handlers) == 0 {
-		c.logger.Error("No handlers registered for event type",
+// processEvent processa um evento individual com os handlers do consumer group
+// Retorna true se todos os handlers do grupo processaram o evento com sucesso
+func (c *RedisStreamConsumer) processEvent(ctx context.Context, event *domainEvents.Event, streamName, consumerGroup string) bool {
+	handlers := c.handlers[consumerGroup][event.Type]
+	if len(handlers) == 0 {
+		c.logger.Debug("No handlers registered for event type in group",
+			"group", consumerGroup,
 			"event_type", event.Type,
 			"event_id", event.ID,
 		)
 		// Ainda assim fazemos ACK para não reprocessar
 		_ = c.Ack(ctx, streamName, consumerGroup, event.StreamID)
-		return
+		return true
+	}
+
+	// Reentrega de um evento que o grupo já processou: apenas confirmar
+	if c.alreadyProcessed(ctx, event, consumerGroup) {
+		c.logger.Info("Skipping already processed event",
+			"group", consumerGroup,
+			"event_type", event.Type,
+			"event_id", event.ID,
+			"stream_id", event.StreamID,
+		)
+		_ = c.Ack(ctx, streamName, consumerGroup, event.StreamID)
+		return true
+	}
+
+	// At-most-once: confirmar antes de processar, falhas não serão reprocessadas
+	atMostOnce := c.ackMode(consumerGroup) == AckAtMostOnce
+	if atMostOnce {
+		_ = c.Ack(ctx, streamName, consumerGroup, event.StreamID)
 	}
 
 	// Executar todos os handlers para este tipo de evento
+	success := c.runHandlers(ctx, handlers, event, consumerGroup)
+
+	c.metrics.ObserveEventConsumed(consumerGroup, string(event.Type), success)
+
+	// Registrar antes do ACK: um crash entre os dois não faz os handlers rodarem de novo
+	if success {
+		c.markProcessed(ctx, event, consumerGroup)
+	}
+
+	// Fazer ACK apenas se todos os handlers do grupo executaram com sucesso
+	if atMostOnce {
+		if !success {
+			c.logger.Warn("Event processing failed in at-most-once group, event will not be retried",
+				"group", consumerGroup,
+				"event_id", event.ID,
+				"stream_id", event.StreamID,
+			)
+		}
+	} else if success {
+		if err := c.Ack(ctx, streamName, consumerGroup, event.StreamID); err != nil {
+			c.logger.Error("Failed to acknowledge successfully processed event",
+				"event_id", event.ID,
+				"stream_id", event.StreamID,
+			)
+		}
+	} else {
+		c.logger.Error("Event processing failed, will be retried",
+			"group", consumerGroup,
+			"event_id", event.ID,
+			"stream_id", event.StreamID,
+		)
+	}
+
+	return success
+}
+
+// runHandlers executa os handlers do grupo para o evento
+// Retorna true se todos tiveram sucesso
+func (c *RedisStreamConsumer) runHandlers(ctx context.Context, handlers []domainEvents.EventHandler, event *domainEvents.Event, consumerGroup string) bool {
 	success := true
 	for _, handler := range handlers {
 		if handler.CanHandle(event.Type) {
 			if err := handler.Handle(ctx, event); err != nil {
 				c.logger.Error("Handler failed to process event",
+					"group", consumerGroup,
 					"event_type", event.Type,
 					"event_id", event.ID,
 					"handler", fmt.Sprintf("%T", handler),
@@ -283,19 +427,39 @@ func (c *RedisStreamConsumer) processEvent(ctx context.Context, event *domainEve
 			}
 		}
 	}
+	return success
+}
 
-	// Fazer ACK apenas se todos os handlers executaram com sucesso
-	if success {
-		if err := c.Ack(ctx, streamName, consumerGroup, event.StreamID); err != nil {
-			c.logger.Error("Failed to acknowledge successfully processed event",
-				"event_id", event.ID,
-				"stream_id", event.StreamID,
-			)
-		}
-	} else {
-		c.logger.Error("Event processing failed, will be retried",
+// alreadyProcessed consulta o registro de eventos processados do grupo
+// Erro na consulta processa o evento normalmente (mantém o at-least-once)
+func (c *RedisStreamConsumer) alreadyProcessed(ctx context.Context, event *domainEvents.Event, consumerGroup string) bool {
+	if c.processed == nil || event.ID == "" {
+		return false
+	}
+
+	processed, err := c.processed.IsProcessed(ctx, consumerGroup, event.ID)
+	if err != nil {
+		c.logger.Warn("Failed to check processed event, processing anyway",
+			"group", consumerGroup,
 			"event_id", event.ID,
-			"stream_id", event.StreamID,
+			"error", err,
+		)
+		return false
+	}
+	return processed
+}
+
+// markProcessed registra o evento como processado pelo grupo
+func (c *RedisStreamConsumer) markProcessed(ctx context.Context, event *domainEvents.Event, consumerGroup string) {
+	if c.processed == nil || event.ID == "" {
+		return
+	}
+
+	if err := c.processed.MarkProcessed(ctx, consumerGroup, event.ID, c.dedupTTL); err != nil {
+		c.logger.Warn("Failed to mark event as processed",
+			"group", consumerGroup,
+			"event_id", event.ID,
+			"error", err,
 		)
 	}
 }
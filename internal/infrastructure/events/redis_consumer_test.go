@@ -0,0 +1,596 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	domainEvents "github.com/vitao/geolocation-tracker/internal/domain/events"
+)
+
+// nopLogger descarta todos os logs durante os testes
+type nopLogger struct{}
+
+func (nopLogger) Info(msg string, fields ...interface{})  {}
+func (nopLogger) Error(msg string, fields ...interface{}) {}
+func (nopLogger) Warn(msg string, fields ...interface{})  {}
+func (nopLogger) Fatal(msg string, fields ...interface{}) {}
+func (nopLogger) Debug(msg string, fields ...interface{}) {}
+func (nopLogger) Sync() error                             { return nil }
+
+// countingHandler conta quantas vezes foi invocado
+type countingHandler struct {
+	calls int
+}
+
+func (h *countingHandler) Handle(ctx context.Context, event *domainEvents.Event) error {
+	h.calls++
+	return nil
+}
+
+func (h *countingHandler) CanHandle(eventType domainEvents.EventType) bool {
+	return true
+}
+
+// failingHandler sempre falha ao processar
+type failingHandler struct {
+	calls int
+}
+
+func (h *failingHandler) Handle(ctx context.Context, event *domainEvents.Event) error {
+	h.calls++
+	return errors.New("handler failed")
+}
+
+func (h *failingHandler) CanHandle(eventType domainEvents.EventType) bool {
+	return true
+}
+
+// newTestConsumer cria um consumer apontando para um Redis inexistente
+// O ACK falha rapidamente (conexão recusada), o que não afeta a execução dos handlers
+func newTestConsumer() *RedisStreamConsumer {
+	client := redis.NewClient(&redis.Options{
+		Addr:       "127.0.0.1:1",
+		MaxRetries: -1,
+	})
+	return NewRedisStreamConsumer(client, nopLogger{})
+}
+
+// TestRegisterHandler_DuplicateIgnored testa que o mesmo handler registrado duas vezes processa o evento uma vez
+func TestRegisterHandler_DuplicateIgnored(t *testing.T) {
+	consumer := newTestConsumer()
+	handler := &countingHandler{}
+
+	consumer.RegisterHandler(domainEvents.ConsumerGroupAnalytics, domainEvents.EventTypePositionChanged, handler)
+	consumer.RegisterHandler(domainEvents.ConsumerGroupAnalytics, domainEvents.EventTypePositionChanged, handler)
+
+	event := &domainEvents.Event{ID: "evt-1", Type: domainEvents.EventTypePositionChanged, StreamID: "1-0"}
+	consumer.processEvent(context.Background(), event, domainEvents.StreamPositionEvents, domainEvents.ConsumerGroupAnalytics)
+
+	assert.Equal(t, 1, handler.calls)
+	assert.Len(t, consumer.handlers[domainEvents.ConsumerGroupAnalytics][domainEvents.EventTypePositionChanged], 1)
+}
+
+// TestRegisterHandler_DuplicateReplaced testa que a política replace mantém apenas o último handler
+func TestRegisterHandler_DuplicateReplaced(t *testing.T) {
+	consumer := newTestConsumer()
+	consumer.SetDuplicateHandlerPolicy(DuplicateHandlerReplace)
+
+	first := &countingHandler{}
+	second := &countingHandler{}
+	consumer.RegisterHandler(domainEvents.ConsumerGroupAnalytics, domainEvents.EventTypePositionChanged, first)
+	consumer.RegisterHandler(domainEvents.ConsumerGroupAnalytics, domainEvents.EventTypePositionChanged, second)
+
+	event := &domainEvents.Event{ID: "evt-1", Type: domainEvents.EventTypePositionChanged, StreamID: "1-0"}
+	consumer.processEvent(context.Background(), event, domainEvents.StreamPositionEvents, domainEvents.ConsumerGroupAnalytics)
+
+	assert.Equal(t, 0, first.calls)
+	assert.Equal(t, 1, second.calls)
+}
+
+// TestRegisterHandler_DuplicateAllowed testa que a política allow mantém o comportamento antigo
+func TestRegisterHandler_DuplicateAllowed(t *testing.T) {
+	consumer := newTestConsumer()
+	consumer.SetDuplicateHandlerPolicy(DuplicateHandlerAllow)
+	handler := &countingHandler{}
+
+	consumer.RegisterHandler(domainEvents.ConsumerGroupAnalytics, domainEvents.EventTypePositionChanged, handler)
+	consumer.RegisterHandler(domainEvents.ConsumerGroupAnalytics, domainEvents.EventTypePositionChanged, handler)
+
+	event := &domainEvents.Event{ID: "evt-1", Type: domainEvents.EventTypePositionChanged, StreamID: "1-0"}
+	consumer.processEvent(context.Background(), event, domainEvents.StreamPositionEvents, domainEvents.ConsumerGroupAnalytics)
+
+	assert.Equal(t, 2, handler.calls)
+}
+
+// TestProcessEvent_IsolatedPerConsumerGroup testa que cada grupo executa apenas seus handlers
+func TestProcessEvent_IsolatedPerConsumerGroup(t *testing.T) {
+	consumer := newTestConsumer()
+
+	notifications := &countingHandler{}
+	analytics := &failingHandler{}
+	consumer.RegisterHandler(domainEvents.ConsumerGroupNotifications, domainEvents.EventTypePositionChanged, notifications)
+	consumer.RegisterHandler(domainEvents.ConsumerGroupAnalytics, domainEvents.EventTypePositionChanged, analytics)
+
+	event := &domainEvents.Event{ID: "evt-1", Type: domainEvents.EventTypePositionChanged, StreamID: "1-0"}
+
+	// Falha no grupo de analytics não afeta o grupo de notificações
+	assert.False(t, consumer.processEvent(context.Background(), event, domainEvents.StreamPositionEvents, domainEvents.ConsumerGroupAnalytics))
+	assert.Equal(t, 1, analytics.calls)
+	assert.Equal(t, 0, notifications.calls)
+
+	assert.True(t, consumer.processEvent(context.Background(), event, domainEvents.StreamPositionEvents, domainEvents.ConsumerGroupNotifications))
+	assert.Equal(t, 1, notifications.calls)
+	assert.Equal(t, 1, analytics.calls)
+}
+
+// TestProcessEvent_NoHandlersInGroup testa grupo sem handlers para o tipo de evento
+func TestProcessEvent_NoHandlersInGroup(t *testing.T) {
+	consumer := newTestConsumer()
+	handler := &countingHandler{}
+	consumer.RegisterHandler(domainEvents.ConsumerGroupNotifications, domainEvents.EventTypePositionChanged, handler)
+
+	event := &domainEvents.Event{ID: "evt-1", Type: domainEvents.EventTypePositionChanged, StreamID: "1-0"}
+
+	assert.True(t, consumer.processEvent(context.Background(), event, domainEvents.StreamPositionEvents, domainEvents.ConsumerGroupRealtime))
+	assert.Equal(t, 0, handler.calls)
+}
+
+// ackRecorder é um hook do Redis que registra os XACK sem acessar a rede
+type ackRecorder struct {
+	acks int
+}
+
+func (r *ackRecorder) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	if cmd.Name() == "xack" {
+		r.acks++
+	}
+	return ctx, errors.New("redis disabled in tests")
+}
+
+func (r *ackRecorder) AfterProcess(ctx context.Context, cmd redis.Cmder) error { return nil }
+
+func (r *ackRecorder) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return ctx, errors.New("redis disabled in tests")
+}
+
+func (r *ackRecorder) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error { return nil }
+
+// newRecordingConsumer cria um consumer que registra os ACKs enviados
+func newRecordingConsumer() (*RedisStreamConsumer, *ackRecorder) {
+	consumer := newTestConsumer()
+	recorder := &ackRecorder{}
+	consumer.client.AddHook(recorder)
+	return consumer, recorder
+}
+
+// ackCheckingHandler falha e registra quantos ACKs já existiam quando foi executado
+type ackCheckingHandler struct {
+	recorder     *ackRecorder
+	acksOnHandle int
+}
+
+func (h *ackCheckingHandler) Handle(ctx context.Context, event *domainEvents.Event) error {
+	h.acksOnHandle = h.recorder.acks
+	return errors.New("handler failed")
+}
+
+func (h *ackCheckingHandler) CanHandle(eventType domainEvents.EventType) bool {
+	return true
+}
+
+// TestProcessEvent_AtMostOnceAcksBeforeHandler testa ACK imediato mesmo quando o handler falha
+func TestProcessEvent_AtMostOnceAcksBeforeHandler(t *testing.T) {
+	consumer, recorder := newRecordingConsumer()
+	consumer.SetAckMode(domainEvents.ConsumerGroupRealtime, AckAtMostOnce)
+
+	handler := &ackCheckingHandler{recorder: recorder}
+	consumer.RegisterHandler(domainEvents.ConsumerGroupRealtime, domainEvents.EventTypePositionChanged, handler)
+
+	event := &domainEvents.Event{ID: "evt-1", Type: domainEvents.EventTypePositionChanged, StreamID: "1-0"}
+
+	assert.False(t, consumer.processEvent(context.Background(), event, domainEvents.StreamPositionEvents, domainEvents.ConsumerGroupRealtime))
+	assert.Equal(t, 1, handler.acksOnHandle, "ACK deve ocorrer antes do handler")
+	assert.Equal(t, 1, recorder.acks, "falha não gera novo ACK")
+}
+
+// TestProcessEvent_AtLeastOnceDoesNotAckOnFailure testa que o modo padrão não confirma falhas
+func TestProcessEvent_AtLeastOnceDoesNotAckOnFailure(t *testing.T) {
+	consumer, recorder := newRecordingConsumer()
+
+	handler := &ackCheckingHandler{recorder: recorder}
+	consumer.RegisterHandler(domainEvents.ConsumerGroupAnalytics, domainEvents.EventTypePositionChanged, handler)
+
+	event := &domainEvents.Event{ID: "evt-1", Type: domainEvents.EventTypePositionChanged, StreamID: "1-0"}
+
+	assert.False(t, consumer.processEvent(context.Background(), event, domainEvents.StreamPositionEvents, domainEvents.ConsumerGroupAnalytics))
+	assert.Equal(t, 0, handler.acksOnHandle)
+	assert.Equal(t, 0, recorder.acks)
+}
+
+// TestProcessEvent_AtLeastOnceAcksOnSuccess testa ACK após sucesso no modo padrão
+func TestProcessEvent_AtLeastOnceAcksOnSuccess(t *testing.T) {
+	consumer, recorder := newRecordingConsumer()
+	consumer.RegisterHandler(domainEvents.ConsumerGroupAnalytics, domainEvents.EventTypePositionChanged, &countingHandler{})
+
+	event := &domainEvents.Event{ID: "evt-1", Type: domainEvents.EventTypePositionChanged, StreamID: "1-0"}
+
+	assert.True(t, consumer.processEvent(context.Background(), event, domainEvents.StreamPositionEvents, domainEvents.ConsumerGroupAnalytics))
+	assert.Equal(t, 1, recorder.acks)
+}
+
+// memoryProcessedEvents guarda os eventos processados em memória (sem expiração)
+type memoryProcessedEvents struct {
+	processed map[string]time.Duration
+	checkErr  error
+}
+
+func newMemoryProcessedEvents() *memoryProcessedEvents {
+	return &memoryProcessedEvents{processed: make(map[string]time.Duration)}
+}
+
+func (m *memoryProcessedEvents) IsProcessed(ctx context.Context, consumerGroup, eventID string) (bool, error) {
+	if m.checkErr != nil {
+		return false, m.checkErr
+	}
+	_, ok := m.processed[consumerGroup+":"+eventID]
+	return ok, nil
+}
+
+func (m *memoryProcessedEvents) MarkProcessed(ctx context.Context, consumerGroup, eventID string, ttl time.Duration) error {
+	m.processed[consumerGroup+":"+eventID] = ttl
+	return nil
+}
+
+// TestProcessEvent_RedeliveredEventSkipped testa que a reentrega de um evento processado só é confirmada
+func TestProcessEvent_RedeliveredEventSkipped(t *testing.T) {
+	consumer, recorder := newRecordingConsumer()
+	store := newMemoryProcessedEvents()
+	consumer.SetDeduplication(store, time.Hour)
+	handler := &countingHandler{}
+	consumer.RegisterHandler(domainEvents.ConsumerGroupNotifications, domainEvents.EventTypePositionChanged, handler)
+
+	event := &domainEvents.Event{ID: "evt-1", Type: domainEvents.EventTypePositionChanged, StreamID: "1-0"}
+	assert.True(t, consumer.processEvent(context.Background(), event, domainEvents.StreamPositionEvents, domainEvents.ConsumerGroupNotifications))
+	assert.Equal(t, time.Hour, store.processed[domainEvents.ConsumerGroupNotifications+":evt-1"])
+
+	// Mesmo evento entregue de novo (ex: crash antes do ACK) com outro ID de stream
+	redelivered := &domainEvents.Event{ID: "evt-1", Type: domainEvents.EventTypePositionChanged, StreamID: "2-0"}
+	assert.True(t, consumer.processEvent(context.Background(), redelivered, domainEvents.StreamPositionEvents, domainEvents.ConsumerGroupNotifications))
+
+	assert.Equal(t, 1, handler.calls)
+	assert.Equal(t, 2, recorder.acks)
+}
+
+// TestProcessEvent_DeduplicationPerGroup testa que o registro de um grupo não afeta os outros
+func TestProcessEvent_DeduplicationPerGroup(t *testing.T) {
+	consumer := newTestConsumer()
+	consumer.SetDeduplication(newMemoryProcessedEvents(), time.Hour)
+	notifications := &countingHandler{}
+	analytics := &countingHandler{}
+	consumer.RegisterHandler(domainEvents.ConsumerGroupNotifications, domainEvents.EventTypePositionChanged, notifications)
+	consumer.RegisterHandler(domainEvents.ConsumerGroupAnalytics, domainEvents.EventTypePositionChanged, analytics)
+
+	event := &domainEvents.Event{ID: "evt-1", Type: domainEvents.EventTypePositionChanged, StreamID: "1-0"}
+	consumer.processEvent(context.Background(), event, domainEvents.StreamPositionEvents, domainEvents.ConsumerGroupNotifications)
+	consumer.processEvent(context.Background(), event, domainEvents.StreamPositionEvents, domainEvents.ConsumerGroupAnalytics)
+
+	assert.Equal(t, 1, notifications.calls)
+	assert.Equal(t, 1, analytics.calls)
+}
+
+// TestProcessEvent_FailedEventNotMarked testa que falhas continuam sendo reprocessadas
+func TestProcessEvent_FailedEventNotMarked(t *testing.T) {
+	consumer := newTestConsumer()
+	store := newMemoryProcessedEvents()
+	consumer.SetDeduplication(store, time.Hour)
+	handler := &failingHandler{}
+	consumer.RegisterHandler(domainEvents.ConsumerGroupAnalytics, domainEvents.EventTypePositionChanged, handler)
+
+	event := &domainEvents.Event{ID: "evt-1", Type: domainEvents.EventTypePositionChanged, StreamID: "1-0"}
+	consumer.processEvent(context.Background(), event, domainEvents.StreamPositionEvents, domainEvents.ConsumerGroupAnalytics)
+	consumer.processEvent(context.Background(), event, domainEvents.StreamPositionEvents, domainEvents.ConsumerGroupAnalytics)
+
+	assert.Equal(t, 2, handler.calls)
+	assert.Empty(t, store.processed)
+}
+
+// TestProcessEvent_DeduplicationCheckErrorProcesses testa que falha no registro não descarta o evento
+func TestProcessEvent_DeduplicationCheckErrorProcesses(t *testing.T) {
+	consumer := newTestConsumer()
+	store := newMemoryProcessedEvents()
+	store.checkErr = errors.New("redis unavailable")
+	consumer.SetDeduplication(store, time.Hour)
+	handler := &countingHandler{}
+	consumer.RegisterHandler(domainEvents.ConsumerGroupAnalytics, domainEvents.EventTypePositionChanged, handler)
+
+	event := &domainEvents.Event{ID: "evt-1", Type: domainEvents.EventTypePositionChanged, StreamID: "1-0"}
+	assert.True(t, consumer.processEvent(context.Background(), event, domainEvents.StreamPositionEvents, domainEvents.ConsumerGroupAnalytics))
+
+	assert.Equal(t, 1, handler.calls)
+}
+
+// TestSetDeduplication_DisabledWithoutTTL testa que TTL zero desabilita a deduplicação
+func TestSetDeduplication_DisabledWithoutTTL(t *testing.T) {
+	consumer := newTestConsumer()
+	consumer.SetDeduplication(newMemoryProcessedEvents(), 0)
+	handler := &countingHandler{}
+	consumer.RegisterHandler(domainEvents.ConsumerGroupAnalytics, domainEvents.EventTypePositionChanged, handler)
+
+	event := &domainEvents.Event{ID: "evt-1", Type: domainEvents.EventTypePositionChanged, StreamID: "1-0"}
+	consumer.processEvent(context.Background(), event, domainEvents.StreamPositionEvents, domainEvents.ConsumerGroupAnalytics)
+	consumer.processEvent(context.Background(), event, domainEvents.StreamPositionEvents, domainEvents.ConsumerGroupAnalytics)
+
+	assert.Equal(t, 2, handler.calls)
+}
+
+// TestParseAckMode testa a conversão da configuração
+func TestParseAckMode(t *testing.T) {
+	assert.Equal(t, AckAtMostOnce, ParseAckMode("at-most-once"))
+	assert.Equal(t, AckAtLeastOnce, ParseAckMode("at-least-once"))
+	assert.Equal(t, AckAtLeastOnce, ParseAckMode("unknown"))
+}
+
+// TestParseDuplicateHandlerPolicy testa a conversão da configuração
+func TestParseDuplicateHandlerPolicy(t *testing.T) {
+	assert.Equal(t, DuplicateHandlerReplace, ParseDuplicateHandlerPolicy("replace"))
+	assert.Equal(t, DuplicateHandlerAllow, ParseDuplicateHandlerPolicy("allow"))
+	assert.Equal(t, DuplicateHandlerIgnore, ParseDuplicateHandlerPolicy("ignore"))
+	assert.Equal(t, DuplicateHandlerIgnore, ParseDuplicateHandlerPolicy("unknown"))
+}
+
+// errFakeStreams interrompe os comandos antes da rede; a resposta é preenchida no AfterProcess
+var errFakeStreams = errors.New("served by fake streams")
+
+// fakeStreams é um hook do Redis que simula streams em memória (XADD com MAXLEN, XLEN,
+// XGROUP CREATE, XREADGROUP, XACK e XRANGE), suficiente para exercitar o corte de streams e o replay
+type fakeStreams struct {
+	mu      sync.Mutex
+	seq     int64
+	entries map[string][]redis.XMessage
+	groups  map[string]map[string]int64 // stream -> grupo -> último seq entregue
+}
+
+func newFakeStreams() *fakeStreams {
+	return &fakeStreams{
+		entries: make(map[string][]redis.XMessage),
+		groups:  make(map[string]map[string]int64),
+	}
+}
+
+// deleteStream remove o stream e seus grupos (como um DEL ou corte total)
+func (f *fakeStreams) deleteStream(stream string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.entries, stream)
+	delete(f.groups, stream)
+}
+
+func (f *fakeStreams) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return ctx, errFakeStreams
+}
+
+func (f *fakeStreams) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	args := make([]string, len(cmd.Args()))
+	for i, arg := range cmd.Args() {
+		args[i] = fmt.Sprint(arg)
+	}
+
+	// XREADGROUP controla o próprio lock para simular o BLOCK sem segurar o fake
+	if cmd.Name() == "xreadgroup" {
+		f.xreadgroup(cmd.(*redis.XStreamSliceCmd), args)
+		return nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch cmd.Name() {
+	case "xadd":
+		f.xadd(cmd.(*redis.StringCmd), args)
+	case "xlen":
+		cmd.(*redis.IntCmd).SetVal(int64(len(f.entries[args[1]])))
+		cmd.SetErr(nil)
+	case "xgroup":
+		f.xgroupCreate(cmd.(*redis.StatusCmd), args)
+	case "xack":
+		cmd.(*redis.IntCmd).SetVal(1)
+		cmd.SetErr(nil)
+	case "xrange":
+		f.xrange(cmd.(*redis.XMessageSliceCmd), args)
+	}
+	return nil
+}
+
+// xrange devolve as entradas entre os limites (inclusivos), com COUNT opcional
+// Os IDs do fake são "<seq>-0"; limites só com ms cobrem o seq inteiro
+func (f *fakeStreams) xrange(cmd *redis.XMessageSliceCmd, args []string) {
+	stream, start, end := args[1], args[2], args[3]
+	count := -1
+	if len(args) == 6 {
+		count, _ = strconv.Atoi(args[5])
+	}
+
+	from, to := int64(0), int64(math.MaxInt64)
+	if start != "-" {
+		ms, seq, _ := strings.Cut(start, "-")
+		from, _ = strconv.ParseInt(ms, 10, 64)
+		// "N-1" em diante (cursor da paginação) começa depois da única entrada do ms N
+		if n, _ := strconv.ParseInt(seq, 10, 64); n > 0 {
+			from++
+		}
+	}
+	if end != "+" {
+		ms, _, _ := strings.Cut(end, "-")
+		to, _ = strconv.ParseInt(ms, 10, 64)
+	}
+
+	messages := make([]redis.XMessage, 0)
+	for _, entry := range f.entries[stream] {
+		seq, _ := strconv.ParseInt(strings.TrimSuffix(entry.ID, "-0"), 10, 64)
+		if seq >= from && seq <= to && (count < 0 || len(messages) < count) {
+			messages = append(messages, entry)
+		}
+	}
+
+	cmd.SetVal(messages)
+	cmd.SetErr(nil)
+}
+
+func (f *fakeStreams) xadd(cmd *redis.StringCmd, args []string) {
+	stream, rest := args[1], args[2:]
+	maxLen := 0
+	if rest[0] == "maxlen" {
+		maxLen, _ = strconv.Atoi(rest[2])
+		rest = rest[3:]
+	}
+
+	f.seq++
+	values := make(map[string]interface{})
+	for i := 1; i+1 < len(rest); i += 2 {
+		values[rest[i]] = rest[i+1]
+	}
+	id := fmt.Sprintf("%d-0", f.seq)
+	f.entries[stream] = append(f.entries[stream], redis.XMessage{ID: id, Values: values})
+
+	// O Redis corta de forma aproximada; o fake corta exatamente no limite
+	if maxLen > 0 && len(f.entries[stream]) > maxLen {
+		f.entries[stream] = f.entries[stream][len(f.entries[stream])-maxLen:]
+	}
+
+	cmd.SetVal(id)
+	cmd.SetErr(nil)
+}
+
+func (f *fakeStreams) xgroupCreate(cmd *redis.StatusCmd, args []string) {
+	stream, group, start := args[2], args[3], args[4]
+	if f.groups[stream] == nil {
+		f.groups[stream] = make(map[string]int64)
+	}
+	if _, exists := f.groups[stream][group]; exists {
+		cmd.SetErr(errors.New("BUSYGROUP Consumer Group name already exists"))
+		return
+	}
+
+	lastDelivered := int64(0)
+	if start == "$" {
+		lastDelivered = f.seq
+	}
+	f.groups[stream][group] = lastDelivered
+	cmd.SetVal("OK")
+	cmd.SetErr(nil)
+}
+
+func (f *fakeStreams) xreadgroup(cmd *redis.XStreamSliceCmd, args []string) {
+	group, stream := args[2], args[len(args)-2]
+
+	f.mu.Lock()
+	lastDelivered, ok := f.groups[stream][group]
+	if !ok {
+		f.mu.Unlock()
+		cmd.SetErr(fmt.Errorf("NOGROUP No such key '%s' or consumer group '%s'", stream, group))
+		return
+	}
+
+	messages := make([]redis.XMessage, 0)
+	for _, entry := range f.entries[stream] {
+		seq, _ := strconv.ParseInt(strings.TrimSuffix(entry.ID, "-0"), 10, 64)
+		if seq > lastDelivered {
+			messages = append(messages, entry)
+			f.groups[stream][group] = seq
+		}
+	}
+	f.mu.Unlock()
+
+	if len(messages) == 0 {
+		// Simula um BLOCK curto sem mensagens novas
+		time.Sleep(5 * time.Millisecond)
+		cmd.SetErr(redis.Nil)
+		return
+	}
+
+	cmd.SetVal([]redis.XStream{{Stream: stream, Messages: messages}})
+	cmd.SetErr(nil)
+}
+
+func (f *fakeStreams) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return ctx, errors.New("pipelines not supported by fake streams")
+}
+
+func (f *fakeStreams) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error { return nil }
+
+// receiveEvents lê n eventos do canal (falha o teste após o timeout)
+func receiveEvents(t *testing.T, eventChan <-chan *domainEvents.Event, n int) []*domainEvents.Event {
+	t.Helper()
+	received := make([]*domainEvents.Event, 0, n)
+	for len(received) < n {
+		select {
+		case event, ok := <-eventChan:
+			require.True(t, ok, "canal fechado inesperadamente")
+			received = append(received, event)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("recebidos %d de %d eventos", len(received), n)
+		}
+	}
+	return received
+}
+
+// TestSubscribe_StreamTrimmedMidRead testa corte (MAXLEN) e remoção do stream durante a leitura
+func TestSubscribe_StreamTrimmedMidRead(t *testing.T) {
+	fake := newFakeStreams()
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	client.AddHook(fake)
+
+	publisher := NewRedisStreamPublisher(client, nopLogger{})
+	publisher.SetMaxLen(3)
+	consumer := NewRedisStreamConsumer(client, nopLogger{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventChan, err := consumer.Subscribe(ctx, domainEvents.StreamPositionEvents, domainEvents.ConsumerGroupAnalytics, "consumer-1")
+	require.NoError(t, err)
+
+	publish := func(userID string) {
+		event := &domainEvents.Event{Type: domainEvents.EventTypePositionChanged, UserID: userID, Timestamp: time.Now()}
+		require.NoError(t, publisher.Publish(ctx, domainEvents.StreamPositionEvents, event))
+	}
+
+	// Publicar mais que o MAXLEN: entradas antigas podem ser cortadas antes de lidas
+	for i := 0; i < 8; i++ {
+		publish(fmt.Sprintf("user-%d", i))
+	}
+
+	stats, err := publisher.StreamStats(ctx)
+	require.NoError(t, err)
+	positionStats := stats[domainEvents.StreamPositionEvents].(map[string]interface{})
+	assert.Equal(t, int64(3), positionStats["length"])
+	assert.Equal(t, int64(3), positionStats["max_length"])
+	assert.Equal(t, true, positionStats["capped"])
+
+	// As entradas que sobreviveram ao corte chegam em ordem, sem erro no consumer
+	var last *domainEvents.Event
+	for last == nil || last.UserID != "user-7" {
+		last = receiveEvents(t, eventChan, 1)[0]
+	}
+
+	// Stream removido por completo: o consumer recria o grupo e continua lendo
+	fake.deleteStream(domainEvents.StreamPositionEvents)
+	publish("user-after-reset")
+
+	received := receiveEvents(t, eventChan, 1)
+	assert.Equal(t, "user-after-reset", received[0].UserID)
+
+	stats, err = publisher.StreamStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats[domainEvents.StreamPositionEvents].(map[string]interface{})["length"])
+}
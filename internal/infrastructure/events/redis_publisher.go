@@ -2,80 +2,201 @@ package events
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
 	domainEvents "github.com/vitao/geolocation-tracker/internal/domain/events"
 	"github.com/vitao/geolocation-tracker/pkg/logger"
+	"github.com/vitao/geolocation-tracker/pkg/metrics"
 )
 
+// ErrEventTypeNotAllowed indica um evento publicado em um stream que não aceita o seu tipo
+var ErrEventTypeNotAllowed = errors.New("event type not allowed on stream")
+
+// ParseEventTypes converte a lista de configuração ("position.changed|proximity.user_nearby") em tipos
+func ParseEventTypes(value string) []domainEvents.EventType {
+	types := make([]domainEvents.EventType, 0)
+	for _, eventType := range strings.Split(value, "|") {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			types = append(types, domainEvents.EventType(eventType))
+		}
+	}
+	return types
+}
+
+// Padrões do retry de publicação (ajustáveis via SetRetry)
+const (
+	DefaultPublishMaxAttempts  = 3
+	DefaultPublishRetryBackoff = 100 * time.Millisecond
+
+	// maxPublishRetryBackoff limita a espera entre tentativas, que dobra a cada falha
+	maxPublishRetryBackoff = 2 * time.Second
+)
+
+// streamClient são os comandos do Redis usados pelo publisher (*redis.Client em produção)
+type streamClient interface {
+	XAdd(ctx context.Context, a *redis.XAddArgs) *redis.StringCmd
+	XDel(ctx context.Context, stream string, ids ...string) *redis.IntCmd
+	XGroupCreate(ctx context.Context, stream, group, start string) *redis.StatusCmd
+	XLen(ctx context.Context, stream string) *redis.IntCmd
+	XPending(ctx context.Context, stream, group string) *redis.XPendingCmd
+	XRevRangeN(ctx context.Context, stream, start, stop string, count int64) *redis.XMessageSliceCmd
+	Do(ctx context.Context, args ...interface{}) *redis.Cmd
+}
+
 // RedisStreamPublisher implementa Publisher usando Redis Streams
 type RedisStreamPublisher struct {
-	client *redis.Client
+	client streamClient
 	logger logger.Logger
+
+	// allowedTypes restringe os tipos de evento por stream; streams ausentes aceitam qualquer tipo
+	allowedTypes map[string]map[domainEvents.EventType]bool
+
+	// maxLen é o MAXLEN aproximado (~) aplicado em cada XADD; 0 mantém os streams sem corte
+	maxLen int64
+
+	// maxAttempts é o total de XADD por evento; retryBackoff é a espera antes da segunda tentativa
+	maxAttempts  int
+	retryBackoff time.Duration
+
+	metrics metrics.Collector
+	codec   EventCodec
 }
 
-// NewRedisStreamPublisher cria uma nova instância do publisher
+// NewRedisStreamPublisher cria uma nova instância do publisher com a allowlist padrão
 func NewRedisStreamPublisher(client *redis.Client, logger logger.Logger) *RedisStreamPublisher {
-	return &RedisStreamPublisher{
-		client: client,
-		logger: logger,
+	p := &RedisStreamPublisher{
+		client:       client,
+		logger:       logger,
+		allowedTypes: make(map[string]map[domainEvents.EventType]bool),
+		maxAttempts:  DefaultPublishMaxAttempts,
+		retryBackoff: DefaultPublishRetryBackoff,
+		metrics:      metrics.NewNoopCollector(),
+		codec:        NewJSONEnvelopeCodec(),
+	}
+	for stream, types := range domainEvents.DefaultStreamEventTypes() {
+		p.SetAllowedEventTypes(stream, types...)
+	}
+	return p
+}
+
+// SetAllowedEventTypes substitui os tipos de evento aceitos em um stream
+func (p *RedisStreamPublisher) SetAllowedEventTypes(streamName string, types ...domainEvents.EventType) {
+	allowed := make(map[domainEvents.EventType]bool, len(types))
+	for _, eventType := range types {
+		allowed[eventType] = true
+	}
+	p.allowedTypes[streamName] = allowed
+}
+
+// ConfigureStreamEventTypes aplica a allowlist da configuração (stream -> tipos separados por "|")
+func (p *RedisStreamPublisher) ConfigureStreamEventTypes(mapping map[string]string) {
+	for stream, types := range mapping {
+		p.SetAllowedEventTypes(stream, ParseEventTypes(types)...)
+	}
+}
+
+// SetMaxLen limita o tamanho dos streams (corte aproximado, o XLEN pode passar um pouco do limite)
+func (p *RedisStreamPublisher) SetMaxLen(maxLen int64) {
+	if maxLen < 0 {
+		maxLen = 0
+	}
+	p.maxLen = maxLen
+}
+
+// SetRetry configura as tentativas de XADD por evento (1 desabilita o retry) e a espera inicial
+// entre elas, que dobra a cada falha até maxPublishRetryBackoff
+func (p *RedisStreamPublisher) SetRetry(maxAttempts int, backoff time.Duration) {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	if backoff < 0 {
+		backoff = 0
+	}
+	p.maxAttempts = maxAttempts
+	p.retryBackoff = backoff
+}
+
+// isAllowed verifica se o tipo de evento pode ser publicado no stream
+func (p *RedisStreamPublisher) isAllowed(streamName string, eventType domainEvents.EventType) bool {
+	allowed, ok := p.allowedTypes[streamName]
+	if !ok {
+		return true
 	}
+	return allowed[eventType]
+}
+
+// SetMetrics configura o collector de métricas de publicação (padrão: no-op)
+func (p *RedisStreamPublisher) SetMetrics(collector metrics.Collector) {
+	p.metrics = collector
+}
+
+// SetCodec troca a serialização das entradas do stream (padrão: JSONEnvelopeCodec)
+func (p *RedisStreamPublisher) SetCodec(codec EventCodec) {
+	p.codec = codec
 }
 
 // Publish publica um evento no stream especificado
 func (p *RedisStreamPublisher) Publish(ctx context.Context, streamName string, event *domainEvents.Event) error {
+	err := p.publish(ctx, streamName, event)
+	p.metrics.ObserveEventPublished(streamName, string(event.Type), err)
+	return err
+}
+
+// publish valida e grava o evento com XADD
+func (p *RedisStreamPublisher) publish(ctx context.Context, streamName string, event *domainEvents.Event) error {
+	// Rejeitar eventos roteados para o stream errado
+	if !p.isAllowed(streamName, event.Type) {
+		p.logger.Error("Event type not allowed on stream",
+			"stream", streamName,
+			"event_type", event.Type,
+			"user_id", event.UserID,
+		)
+		return fmt.Errorf("%w: %s on %s", ErrEventTypeNotAllowed, event.Type, streamName)
+	}
+
 	// Gerar ID único se não tiver
 	if event.ID == "" {
 		event.ID = uuid.New().String()
 	}
 
-	// Serializar os dados do evento para JSON
-	eventDataJSON, err := json.Marshal(event.Data)
-	if err != nil {
-		return fmt.Errorf("failed to marshal event data: %w", err)
-	}
-
-	metadataJSON, err := json.Marshal(event.Metadata)
+	// Serializar o evento inteiro (dados e metadata) no formato do codec
+	fields, err := p.codec.Encode(event)
 	if err != nil {
-		return fmt.Errorf("failed to marshal event metadata: %w", err)
-	}
-
-	// Preparar os campos para o Redis Stream
-	// Redis Streams armazena como key-value pairs
-	fields := map[string]interface{}{
-		"event_id":  event.ID,
-		"type":      string(event.Type),
-		"user_id":   event.UserID,
-		"event_ctx": event.EventID, // Renomeado para evitar confusão
-		"timestamp": event.Timestamp.Format(time.RFC3339Nano),
-		"data":      string(eventDataJSON),
-		"metadata":  string(metadataJSON),
+		return err
 	}
 
 	// Publicar no Redis Stream
 	// XADD stream_name * field1 value1 field2 value2 ...
-	result := p.client.XAdd(ctx, &redis.XAddArgs{
+	streamID, attempts, err := p.xaddWithRetry(ctx, &redis.XAddArgs{
 		Stream: streamName,
 		ID:     "*", // Deixar o Redis gerar o ID automaticamente
+		MaxLen: p.maxLen,
+		Approx: true,
 		Values: fields,
 	})
-
-	if result.Err() != nil {
+	if err != nil {
 		p.logger.Error("Failed to publish event to Redis Stream",
 			"stream", streamName,
 			"event_type", event.Type,
 			"event_id", event.ID,
-			"error", result.Err(),
+			"attempts", attempts,
+			"error", err,
 		)
-		return fmt.Errorf("failed to publish to stream %s: %w", streamName, result.Err())
+		return &domainEvents.PublishError{
+			Stream:    streamName,
+			EventType: event.Type,
+			Attempts:  attempts,
+			Err:       err,
+		}
 	}
 
 	// Guardar o ID do stream no evento para referência
-	event.StreamID = result.Val()
+	event.StreamID = streamID
 
 	p.logger.Info("Event published successfully to Redis Stream",
 		"stream", streamName,
@@ -88,6 +209,40 @@ func (p *RedisStreamPublisher) Publish(ctx context.Context, streamName string, e
 	return nil
 }
 
+// xaddWithRetry executa o XADD com backoff exponencial entre as tentativas
+// Retorna o ID gerado pelo Redis e quantas tentativas foram feitas; para antes se o ctx terminar
+func (p *RedisStreamPublisher) xaddWithRetry(ctx context.Context, args *redis.XAddArgs) (string, int, error) {
+	backoff := p.retryBackoff
+
+	for attempt := 1; ; attempt++ {
+		streamID, err := p.client.XAdd(ctx, args).Result()
+		if err == nil {
+			return streamID, attempt, nil
+		}
+		if attempt >= p.maxAttempts || ctx.Err() != nil {
+			return "", attempt, err
+		}
+
+		p.logger.Warn("Retrying event publish after Redis error",
+			"stream", args.Stream,
+			"attempt", attempt,
+			"backoff", backoff,
+			"error", err,
+		)
+
+		select {
+		case <-ctx.Done():
+			return "", attempt, err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxPublishRetryBackoff {
+			backoff = maxPublishRetryBackoff
+		}
+	}
+}
+
 // PublishPositionChanged publica evento de mudança de posição
 func (p *RedisStreamPublisher) PublishPositionChanged(ctx context.Context, event *domainEvents.Event) error {
 	return p.Publish(ctx, domainEvents.StreamPositionEvents, event)
@@ -98,6 +253,16 @@ func (p *RedisStreamPublisher) PublishSectorChanged(ctx context.Context, event *
 	return p.Publish(ctx, domainEvents.StreamSectorEvents, event)
 }
 
+// PublishProximity publica evento de proximidade entre usuários
+func (p *RedisStreamPublisher) PublishProximity(ctx context.Context, event *domainEvents.Event) error {
+	return p.Publish(ctx, domainEvents.StreamProximityEvents, event)
+}
+
+// PublishUserDeleted publica evento de remoção de usuário
+func (p *RedisStreamPublisher) PublishUserDeleted(ctx context.Context, event *domainEvents.Event) error {
+	return p.Publish(ctx, domainEvents.StreamUserEvents, event)
+}
+
 // Close fecha a conexão (não precisamos fazer nada aqui pois o Redis client é compartilhado)
 func (p *RedisStreamPublisher) Close() error {
 	return nil
@@ -152,15 +317,19 @@ func (p *RedisStreamPublisher) ensureStreamExists(ctx context.Context, streamNam
 	return nil
 }
 
-// InitializeStreams inicializa todos os streams necessários
-func (p *RedisStreamPublisher) InitializeStreams(ctx context.Context) error {
-	streams := []string{
+// managedStreams lista os streams criados e monitorados pelo publisher
+func managedStreams() []string {
+	return []string{
 		domainEvents.StreamPositionEvents,
 		domainEvents.StreamSectorEvents,
 		domainEvents.StreamProximityEvents,
+		domainEvents.StreamUserEvents,
 	}
+}
 
-	for _, stream := range streams {
+// InitializeStreams inicializa todos os streams necessários
+func (p *RedisStreamPublisher) InitializeStreams(ctx context.Context) error {
+	for _, stream := range managedStreams() {
 		if err := p.ensureStreamExists(ctx, stream); err != nil {
 			return fmt.Errorf("failed to initialize stream %s: %w", stream, err)
 		}
@@ -169,3 +338,24 @@ func (p *RedisStreamPublisher) InitializeStreams(ctx context.Context) error {
 	p.logger.Info("All Redis Streams initialized successfully")
 	return nil
 }
+
+// StreamStats retorna o tamanho atual e o limite configurado de cada stream
+// Com corte aproximado o tamanho oscila perto do limite; o consumer não depende dele
+func (p *RedisStreamPublisher) StreamStats(ctx context.Context) (map[string]interface{}, error) {
+	stats := make(map[string]interface{})
+
+	for _, stream := range managedStreams() {
+		length, err := p.client.XLen(ctx, stream).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get length of stream %s: %w", stream, err)
+		}
+
+		stats[stream] = map[string]interface{}{
+			"length":     length,
+			"max_length": p.maxLen,
+			"capped":     p.maxLen > 0,
+		}
+	}
+
+	return stats, nil
+}
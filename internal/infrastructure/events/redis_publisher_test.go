@@ -0,0 +1,168 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	domainEvents "github.com/vitao/geolocation-tracker/internal/domain/events"
+)
+
+// xaddRecorder é um hook do Redis que registra os XADD sem acessar a rede
+type xaddRecorder struct {
+	streams []string
+}
+
+func (r *xaddRecorder) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	if cmd.Name() == "xadd" {
+		r.streams = append(r.streams, cmd.Args()[1].(string))
+	}
+	return ctx, errors.New("redis disabled in tests")
+}
+
+func (r *xaddRecorder) AfterProcess(ctx context.Context, cmd redis.Cmder) error { return nil }
+
+func (r *xaddRecorder) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return ctx, errors.New("redis disabled in tests")
+}
+
+func (r *xaddRecorder) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	return nil
+}
+
+// newRecordingPublisher cria um publisher que registra os XADD enviados
+func newRecordingPublisher() (*RedisStreamPublisher, *xaddRecorder) {
+	client := redis.NewClient(&redis.Options{
+		Addr:       "127.0.0.1:1",
+		MaxRetries: -1,
+	})
+	recorder := &xaddRecorder{}
+	client.AddHook(recorder)
+
+	// Sem retry: cada Publish registra no máximo um XADD
+	publisher := NewRedisStreamPublisher(client, nopLogger{})
+	publisher.SetRetry(1, 0)
+	return publisher, recorder
+}
+
+// TestPublish_AllowedTypeReachesStream testa que um tipo permitido segue para o XADD
+func TestPublish_AllowedTypeReachesStream(t *testing.T) {
+	publisher, recorder := newRecordingPublisher()
+	event := &domainEvents.Event{Type: domainEvents.EventTypePositionChanged, UserID: "user123"}
+
+	err := publisher.Publish(context.Background(), domainEvents.StreamPositionEvents, event)
+
+	// O erro vem apenas do Redis desabilitado no teste, não da validação
+	assert.NotErrorIs(t, err, ErrEventTypeNotAllowed)
+	assert.Equal(t, []string{domainEvents.StreamPositionEvents}, recorder.streams)
+}
+
+// TestPublish_MismatchedTypeRejected testa que um evento no stream errado é rejeitado sem XADD
+func TestPublish_MismatchedTypeRejected(t *testing.T) {
+	publisher, recorder := newRecordingPublisher()
+	event := &domainEvents.Event{Type: domainEvents.EventTypeUserEnteredSector, UserID: "user123"}
+
+	err := publisher.Publish(context.Background(), domainEvents.StreamPositionEvents, event)
+
+	assert.ErrorIs(t, err, ErrEventTypeNotAllowed)
+	assert.Empty(t, recorder.streams)
+}
+
+// TestPublish_ConfiguredAllowlistOverridesDefault testa que a configuração substitui a allowlist do stream
+func TestPublish_ConfiguredAllowlistOverridesDefault(t *testing.T) {
+	publisher, recorder := newRecordingPublisher()
+	publisher.ConfigureStreamEventTypes(map[string]string{
+		domainEvents.StreamPositionEvents: "position.changed | proximity.user_nearby",
+	})
+
+	nearby := &domainEvents.Event{Type: domainEvents.EventTypeUserNearby}
+	err := publisher.Publish(context.Background(), domainEvents.StreamPositionEvents, nearby)
+	assert.NotErrorIs(t, err, ErrEventTypeNotAllowed)
+
+	deleted := &domainEvents.Event{Type: domainEvents.EventTypeUserDeleted}
+	err = publisher.Publish(context.Background(), domainEvents.StreamPositionEvents, deleted)
+	assert.ErrorIs(t, err, ErrEventTypeNotAllowed)
+
+	assert.Len(t, recorder.streams, 1)
+}
+
+// flakyStreamClient falha os primeiros XADD com um erro transitório e depois aceita
+type flakyStreamClient struct {
+	streamClient
+	failures int
+	calls    int
+}
+
+func (c *flakyStreamClient) XAdd(ctx context.Context, a *redis.XAddArgs) *redis.StringCmd {
+	c.calls++
+	if c.calls <= c.failures {
+		return redis.NewStringResult("", errors.New("read tcp: connection reset by peer"))
+	}
+	return redis.NewStringResult("1700000000000-0", nil)
+}
+
+// newFlakyPublisher cria um publisher sobre o client instável, com backoff curto para o teste
+func newFlakyPublisher(failures, maxAttempts int) (*RedisStreamPublisher, *flakyStreamClient) {
+	client := &flakyStreamClient{failures: failures}
+	publisher := NewRedisStreamPublisher(nil, nopLogger{})
+	publisher.client = client
+	publisher.SetRetry(maxAttempts, time.Millisecond)
+	return publisher, client
+}
+
+// TestPublish_RetriesTransientErrors testa que o evento é publicado na terceira tentativa
+func TestPublish_RetriesTransientErrors(t *testing.T) {
+	publisher, client := newFlakyPublisher(2, 3)
+	event := &domainEvents.Event{Type: domainEvents.EventTypePositionChanged, UserID: "user123"}
+
+	err := publisher.Publish(context.Background(), domainEvents.StreamPositionEvents, event)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, client.calls)
+	assert.Equal(t, "1700000000000-0", event.StreamID)
+}
+
+// TestPublish_ReturnsPublishErrorAfterExhaustion testa o erro tipado quando as tentativas acabam
+func TestPublish_ReturnsPublishErrorAfterExhaustion(t *testing.T) {
+	publisher, client := newFlakyPublisher(5, 3)
+	event := &domainEvents.Event{Type: domainEvents.EventTypePositionChanged, UserID: "user123"}
+
+	err := publisher.Publish(context.Background(), domainEvents.StreamPositionEvents, event)
+
+	var publishErr *domainEvents.PublishError
+	require.ErrorAs(t, err, &publishErr)
+	assert.Equal(t, 3, publishErr.Attempts)
+	assert.Equal(t, domainEvents.StreamPositionEvents, publishErr.Stream)
+	assert.Equal(t, domainEvents.EventTypePositionChanged, publishErr.EventType)
+	assert.Equal(t, 3, client.calls)
+	assert.Empty(t, event.StreamID)
+}
+
+// TestPublish_StopsRetryingWhenContextEnds testa que o retry não passa do prazo da requisição
+func TestPublish_StopsRetryingWhenContextEnds(t *testing.T) {
+	publisher, client := newFlakyPublisher(5, 5)
+	publisher.SetRetry(5, time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := publisher.Publish(ctx, domainEvents.StreamPositionEvents, &domainEvents.Event{Type: domainEvents.EventTypePositionChanged})
+
+	var publishErr *domainEvents.PublishError
+	require.ErrorAs(t, err, &publishErr)
+	assert.Equal(t, 1, publishErr.Attempts)
+	assert.Equal(t, 1, client.calls)
+}
+
+// TestPublish_NotAllowedIsNotRetried testa que erros de validação não geram novas tentativas
+func TestPublish_NotAllowedIsNotRetried(t *testing.T) {
+	publisher, client := newFlakyPublisher(5, 3)
+
+	err := publisher.Publish(context.Background(), domainEvents.StreamPositionEvents, &domainEvents.Event{Type: domainEvents.EventTypeUserDeleted})
+
+	assert.ErrorIs(t, err, ErrEventTypeNotAllowed)
+	assert.Zero(t, client.calls)
+}
@@ -0,0 +1,187 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// MaxReplayEvents é o máximo de entradas relidas por replay
+const MaxReplayEvents = 10000
+
+// replayPageSize é o COUNT de cada XRANGE do replay
+const replayPageSize = 100
+
+// ErrInvalidReplayRange indica limites de janela que não são IDs do stream nem timestamps RFC3339
+var ErrInvalidReplayRange = errors.New("invalid replay range")
+
+// streamIDPattern reconhece IDs completos (ms-seq) e parciais (ms) de entradas do stream
+var streamIDPattern = regexp.MustCompile(`^\d+(-\d+)?$`)
+
+// ReplayResult resume um replay: quantas entradas foram lidas e quantas reentregues aos handlers
+type ReplayResult struct {
+	Stream    string   `json:"stream"`
+	Start     string   `json:"start"` // Limites usados no XRANGE (inclusivos)
+	End       string   `json:"end"`
+	Groups    []string `json:"groups"`
+	Read      int      `json:"read"`                 // Entradas lidas do stream
+	Replayed  int      `json:"replayed"`             // Eventos entregues a pelo menos um handler
+	Failed    int      `json:"failed"`               // Eventos em que algum handler falhou
+	Skipped   int      `json:"skipped"`              // Entradas ilegíveis ou sem handler nos grupos
+	LastID    string   `json:"last_id"`              // Último ID lido
+	Truncated bool     `json:"truncated"`            // Limite atingido antes do fim da janela
+	NextStart string   `json:"next_start,omitempty"` // start da próxima chamada quando truncated
+}
+
+// ParseReplayBoundary converte o limite da janela em um ID aceito pelo XRANGE
+// Aceita IDs do stream ("1700000000000-0" ou só os ms) e timestamps RFC3339; vazio usa "-" ou "+"
+func ParseReplayBoundary(value string, end bool) (string, error) {
+	switch {
+	case value == "" && end, value == "+":
+		return "+", nil
+	case value == "", value == "-":
+		return "-", nil
+	case streamIDPattern.MatchString(value):
+		return value, nil
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q is neither a stream ID nor an RFC3339 timestamp", ErrInvalidReplayRange, value)
+	}
+	if timestamp.UnixMilli() < 0 {
+		return "", fmt.Errorf("%w: %q is before the Unix epoch", ErrInvalidReplayRange, value)
+	}
+	// Um ID só com ms cobre todas as entradas daquele ms, tanto no início quanto no fim
+	return strconv.FormatInt(timestamp.UnixMilli(), 10), nil
+}
+
+// Replay relê as entradas de streamName entre start e end (inclusivos) com XRANGE e as entrega
+// aos handlers registrados dos grupos informados (todos quando vazio)
+// Os eventos não são publicados de novo, o ACK e os consumer groups não mudam e o registro
+// de eventos processados é ignorado: os handlers executam mesmo para eventos já processados
+func (c *RedisStreamConsumer) Replay(ctx context.Context, streamName, start, end string, groups []string, limit int) (*ReplayResult, error) {
+	if limit <= 0 || limit > MaxReplayEvents {
+		limit = MaxReplayEvents
+	}
+	if len(groups) == 0 {
+		groups = c.registeredGroups()
+	}
+
+	result := &ReplayResult{
+		Stream: streamName,
+		Start:  start,
+		End:    end,
+		Groups: groups,
+	}
+
+	cursor := start
+	for result.Read < limit {
+		count := replayPageSize
+		if remaining := limit - result.Read; remaining < count {
+			count = remaining
+		}
+
+		messages, err := c.client.XRangeN(ctx, streamName, cursor, end, int64(count)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stream %s: %w", streamName, err)
+		}
+
+		for _, message := range messages {
+			result.Read++
+			result.LastID = message.ID
+			c.replayMessage(ctx, message, streamName, groups, result)
+		}
+
+		if len(messages) < count {
+			return result, nil
+		}
+		if cursor, err = nextStreamID(result.LastID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Limite atingido: só há mais entradas se a próxima página não vier vazia
+	more, err := c.client.XRangeN(ctx, streamName, cursor, end, 1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stream %s: %w", streamName, err)
+	}
+	if len(more) > 0 {
+		result.Truncated = true
+		result.NextStart = cursor
+	}
+
+	return result, nil
+}
+
+// replayMessage decodifica uma entrada e a entrega aos handlers de cada grupo
+func (c *RedisStreamConsumer) replayMessage(ctx context.Context, message redis.XMessage, streamName string, groups []string, result *ReplayResult) {
+	event, err := c.codec.Decode(message)
+	if err != nil {
+		c.logger.Warn("Skipping unreadable entry during replay",
+			"stream", streamName,
+			"message_id", message.ID,
+			"error", err,
+		)
+		result.Skipped++
+		return
+	}
+
+	handled, success := false, true
+	for _, group := range groups {
+		handlers := c.handlers[group][event.Type]
+		if len(handlers) == 0 {
+			continue
+		}
+		handled = true
+		if !c.runHandlers(ctx, handlers, event, group) {
+			success = false
+		}
+	}
+
+	switch {
+	case !handled:
+		result.Skipped++
+	case !success:
+		result.Failed++
+	default:
+		result.Replayed++
+	}
+}
+
+// registeredGroups retorna os consumer groups com handlers, em ordem alfabética
+func (c *RedisStreamConsumer) registeredGroups() []string {
+	groups := make([]string, 0, len(c.handlers))
+	for group := range c.handlers {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// HasGroup indica se o consumer group tem handlers registrados
+func (c *RedisStreamConsumer) HasGroup(consumerGroup string) bool {
+	return len(c.handlers[consumerGroup]) > 0
+}
+
+// nextStreamID retorna o menor ID maior que id, para paginar o XRANGE sem repetir a entrada
+// (equivale ao "(" exclusivo do Redis 6.2+, mas funciona em qualquer versão)
+func nextStreamID(id string) (string, error) {
+	ms, seq, found := strings.Cut(id, "-")
+	if !found {
+		return "", fmt.Errorf("invalid stream ID %q", id)
+	}
+
+	sequence, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid stream ID %q: %w", id, err)
+	}
+	return fmt.Sprintf("%s-%d", ms, sequence+1), nil
+}
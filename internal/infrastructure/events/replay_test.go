@@ -0,0 +1,140 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	domainEvents "github.com/vitao/geolocation-tracker/internal/domain/events"
+)
+
+// newReplayFixture publica n eventos em streams em memória e cria o consumer sobre eles
+func newReplayFixture(t *testing.T, n int) (*fakeStreams, *RedisStreamConsumer) {
+	fake := newFakeStreams()
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	client.AddHook(fake)
+
+	publisher := NewRedisStreamPublisher(client, nopLogger{})
+	for i := 0; i < n; i++ {
+		event := &domainEvents.Event{Type: domainEvents.EventTypePositionChanged, UserID: fmt.Sprintf("user-%d", i), Timestamp: time.Now()}
+		require.NoError(t, publisher.Publish(context.Background(), domainEvents.StreamPositionEvents, event))
+	}
+
+	return fake, NewRedisStreamConsumer(client, nopLogger{})
+}
+
+// TestReplay_DispatchesWindowToGroupHandlers testa a janela inclusiva e a entrega aos handlers sem republicar
+func TestReplay_DispatchesWindowToGroupHandlers(t *testing.T) {
+	fake, consumer := newReplayFixture(t, 5)
+
+	// Eventos já processados também são reentregues: o replay ignora a deduplicação
+	store := newMemoryProcessedEvents()
+	for _, entry := range fake.entries[domainEvents.StreamPositionEvents] {
+		event, err := consumer.codec.Decode(entry)
+		require.NoError(t, err)
+		store.processed[domainEvents.ConsumerGroupAnalytics+":"+event.ID] = time.Hour
+	}
+	consumer.SetDeduplication(store, time.Hour)
+
+	analytics, realtime := &countingHandler{}, &countingHandler{}
+	consumer.RegisterHandler(domainEvents.ConsumerGroupAnalytics, domainEvents.EventTypePositionChanged, analytics)
+	consumer.RegisterHandler(domainEvents.ConsumerGroupRealtime, domainEvents.EventTypePositionChanged, realtime)
+
+	result, err := consumer.Replay(context.Background(), domainEvents.StreamPositionEvents, "2-0", "4-0", nil, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Read)
+	assert.Equal(t, 3, result.Replayed)
+	assert.Equal(t, "4-0", result.LastID)
+	assert.False(t, result.Truncated)
+	assert.Equal(t, []string{domainEvents.ConsumerGroupAnalytics, domainEvents.ConsumerGroupRealtime}, result.Groups)
+	assert.Equal(t, 3, analytics.calls)
+	assert.Equal(t, 3, realtime.calls)
+	assert.Len(t, store.processed, 5)
+	assert.Len(t, fake.entries[domainEvents.StreamPositionEvents], 5)
+}
+
+// TestReplay_SelectedGroupsAndFailures testa o filtro de grupos e a contagem de falhas e entradas sem handler
+func TestReplay_SelectedGroupsAndFailures(t *testing.T) {
+	fake, consumer := newReplayFixture(t, 2)
+	fake.entries[domainEvents.StreamPositionEvents] = append(fake.entries[domainEvents.StreamPositionEvents],
+		redis.XMessage{ID: "3-0", Values: map[string]interface{}{payloadField: "not json"}},
+	)
+
+	analytics, notifications := &failingHandler{}, &countingHandler{}
+	consumer.RegisterHandler(domainEvents.ConsumerGroupAnalytics, domainEvents.EventTypePositionChanged, analytics)
+	consumer.RegisterHandler(domainEvents.ConsumerGroupNotifications, domainEvents.EventTypeUserEnteredSector, notifications)
+
+	failed, err := consumer.Replay(context.Background(), domainEvents.StreamPositionEvents, "-", "+", []string{domainEvents.ConsumerGroupAnalytics}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, failed.Read)
+	assert.Equal(t, 2, failed.Failed)
+	assert.Equal(t, 1, failed.Skipped)
+	assert.Equal(t, 2, analytics.calls)
+
+	// notifications não trata position.changed: nada a reentregar
+	skipped, err := consumer.Replay(context.Background(), domainEvents.StreamPositionEvents, "-", "+", []string{domainEvents.ConsumerGroupNotifications}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, skipped.Replayed)
+	assert.Equal(t, 3, skipped.Skipped)
+	assert.Equal(t, 0, notifications.calls)
+}
+
+// TestReplay_PagesUntilLimit testa a paginação do XRANGE e o corte pelo limite
+func TestReplay_PagesUntilLimit(t *testing.T) {
+	_, consumer := newReplayFixture(t, 250)
+	handler := &countingHandler{}
+	consumer.RegisterHandler(domainEvents.ConsumerGroupAnalytics, domainEvents.EventTypePositionChanged, handler)
+
+	all, err := consumer.Replay(context.Background(), domainEvents.StreamPositionEvents, "-", "+", nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 250, all.Replayed)
+	assert.Equal(t, "250-0", all.LastID)
+	assert.False(t, all.Truncated)
+
+	limited, err := consumer.Replay(context.Background(), domainEvents.StreamPositionEvents, "-", "+", nil, 150)
+	require.NoError(t, err)
+	assert.Equal(t, 150, limited.Read)
+	assert.Equal(t, "150-0", limited.LastID)
+	assert.True(t, limited.Truncated)
+	assert.Equal(t, "150-1", limited.NextStart)
+	assert.Equal(t, 400, handler.calls)
+
+	// Limite exatamente no fim da janela não é truncado
+	exact, err := consumer.Replay(context.Background(), domainEvents.StreamPositionEvents, "201", "+", nil, 50)
+	require.NoError(t, err)
+	assert.Equal(t, 50, exact.Read)
+	assert.False(t, exact.Truncated)
+	assert.Empty(t, exact.NextStart)
+}
+
+// TestParseReplayBoundary testa IDs do stream, timestamps e valores inválidos
+func TestParseReplayBoundary(t *testing.T) {
+	cases := []struct {
+		value string
+		end   bool
+		want  string
+	}{
+		{"", false, "-"},
+		{"", true, "+"},
+		{"1700000000000-3", false, "1700000000000-3"},
+		{"1700000000000", true, "1700000000000"},
+		{"2023-11-14T22:13:20.5Z", false, "1700000000500"},
+		{"2023-11-14T19:13:20-03:00", true, "1700000000000"},
+	}
+	for _, tc := range cases {
+		got, err := ParseReplayBoundary(tc.value, tc.end)
+		require.NoError(t, err, tc.value)
+		assert.Equal(t, tc.want, got, tc.value)
+	}
+
+	_, err := ParseReplayBoundary("yesterday", false)
+	assert.ErrorIs(t, err, ErrInvalidReplayRange)
+
+	_, err = ParseReplayBoundary("1969-12-31T23:59:59Z", false)
+	assert.ErrorIs(t, err, ErrInvalidReplayRange)
+}
@@ -0,0 +1,197 @@
+package realtime
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// Parâmetros das conexões WebSocket
+const (
+	writeWait      = 10 * time.Second    // Tempo máximo para escrever uma mensagem
+	pongWait       = 60 * time.Second    // Tempo máximo sem pong do cliente
+	pingPeriod     = (pongWait * 9) / 10 // Deve ser menor que pongWait
+	maxMessageSize = 512                 // Clientes só enviam controle (pong/close)
+
+	// clientSendBuffer é quantas mensagens um cliente pode acumular antes de ser descartado
+	clientSendBuffer = 64
+)
+
+// Hub mantém as conexões WebSocket inscritas e distribui as mensagens entre elas
+// Broadcast nunca bloqueia: clientes lentos (buffer cheio) são desconectados
+type Hub struct {
+	mu      sync.RWMutex
+	clients map[*client]struct{}
+	closed  bool
+	logger  logger.Logger
+}
+
+// client representa uma conexão inscrita, opcionalmente filtrada por setor
+type client struct {
+	hub       *Hub
+	conn      *websocket.Conn
+	addr      string
+	sectorID  string // Vazio recebe todos os setores
+	send      chan []byte
+	closeOnce sync.Once
+}
+
+// NewHub cria um novo hub
+func NewHub(logger logger.Logger) *Hub {
+	return &Hub{
+		clients: make(map[*client]struct{}),
+		logger:  logger,
+	}
+}
+
+// Subscribe registra a conexão e inicia suas rotinas de leitura e escrita
+// A conexão passa a pertencer ao hub, que a fecha ao desconectar
+func (h *Hub) Subscribe(conn *websocket.Conn, sectorID string) {
+	c := &client{
+		hub:      h,
+		conn:     conn,
+		addr:     conn.RemoteAddr().String(),
+		sectorID: sectorID,
+		send:     make(chan []byte, clientSendBuffer),
+	}
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		conn.Close()
+		return
+	}
+	h.clients[c] = struct{}{}
+	total := len(h.clients)
+	h.mu.Unlock()
+
+	h.logger.Info("WebSocket client subscribed",
+		"remote_addr", c.addr,
+		"sector_id", sectorID,
+		"clients", total,
+	)
+
+	go c.writePump()
+	go c.readPump()
+}
+
+// Broadcast envia a mensagem aos clientes sem filtro ou inscritos em um dos setores
+func (h *Hub) Broadcast(message []byte, sectorIDs ...string) {
+	var slow []*client
+
+	h.mu.RLock()
+	for c := range h.clients {
+		if !c.matches(sectorIDs) {
+			continue
+		}
+		select {
+		case c.send <- message:
+		default:
+			slow = append(slow, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, c := range slow {
+		h.logger.Warn("Dropping slow WebSocket client",
+			"remote_addr", c.addr,
+			"sector_id", c.sectorID,
+		)
+		h.unsubscribe(c)
+	}
+}
+
+// Clients retorna o número de conexões ativas
+func (h *Hub) Clients() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
+// Close desconecta todos os clientes e recusa novas inscrições
+func (h *Hub) Close() {
+	h.mu.Lock()
+	h.closed = true
+	clients := make([]*client, 0, len(h.clients))
+	for c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mu.Unlock()
+
+	for _, c := range clients {
+		h.unsubscribe(c)
+	}
+}
+
+// unsubscribe remove o cliente; é idempotente
+func (h *Hub) unsubscribe(c *client) {
+	h.mu.Lock()
+	delete(h.clients, c)
+	h.mu.Unlock()
+
+	// Fechar send encerra o writePump, que fecha a conexão e com ela o readPump
+	c.closeOnce.Do(func() { close(c.send) })
+}
+
+// matches verifica se o cliente quer mensagens de algum dos setores
+func (c *client) matches(sectorIDs []string) bool {
+	if c.sectorID == "" {
+		return true
+	}
+	for _, sectorID := range sectorIDs {
+		if sectorID == c.sectorID {
+			return true
+		}
+	}
+	return false
+}
+
+// readPump consome as mensagens do cliente para processar pong e detectar desconexão
+func (c *client) readPump() {
+	defer c.hub.unsubscribe(c)
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// writePump é o único escritor da conexão: envia mensagens e pings periódicos
+func (c *client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case message, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				// Hub descartou o cliente
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				c.hub.unsubscribe(c)
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.hub.unsubscribe(c)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package realtime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nopLogger descarta todos os logs durante os testes
+type nopLogger struct{}
+
+func (nopLogger) Info(msg string, fields ...interface{})  {}
+func (nopLogger) Error(msg string, fields ...interface{}) {}
+func (nopLogger) Warn(msg string, fields ...interface{})  {}
+func (nopLogger) Fatal(msg string, fields ...interface{}) {}
+func (nopLogger) Debug(msg string, fields ...interface{}) {}
+func (nopLogger) Sync() error                             { return nil }
+
+// newTestServer expõe o hub via WebSocket; o setor vem da query, como no handler HTTP
+func newTestServer(t *testing.T, hub *Hub) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		hub.Subscribe(conn, r.URL.Query().Get("sector"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// dial conecta ao servidor e aguarda o registro no hub
+func dial(t *testing.T, server *httptest.Server, hub *Hub, query string) *websocket.Conn {
+	before := hub.Clients()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + query
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	require.Eventually(t, func() bool { return hub.Clients() == before+1 }, time.Second, 5*time.Millisecond)
+	return conn
+}
+
+// TestHub_BroadcastFiltersBySector testa que clientes filtrados só recebem o próprio setor
+func TestHub_BroadcastFiltersBySector(t *testing.T) {
+	hub := NewHub(nopLogger{})
+	server := newTestServer(t, hub)
+
+	all := dial(t, server, hub, "")
+	filtered := dial(t, server, hub, "?sector=sector_1_1")
+
+	hub.Broadcast([]byte("other"), "sector_9_9")
+	hub.Broadcast([]byte("mine"), "sector_1_2", "sector_1_1")
+
+	for _, expected := range []string{"other", "mine"} {
+		all.SetReadDeadline(time.Now().Add(time.Second))
+		_, message, err := all.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(message))
+	}
+
+	filtered.SetReadDeadline(time.Now().Add(time.Second))
+	_, message, err := filtered.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, "mine", string(message))
+}
+
+// TestHub_DropsSlowClient testa que Broadcast não bloqueia e descarta o cliente com buffer cheio
+func TestHub_DropsSlowClient(t *testing.T) {
+	hub := NewHub(nopLogger{})
+	c := &client{hub: hub, sectorID: "", send: make(chan []byte, 1)}
+	hub.clients[c] = struct{}{}
+
+	hub.Broadcast([]byte("first"))
+	assert.Equal(t, 1, hub.Clients())
+
+	// Buffer cheio: o cliente é removido e send é fechado
+	done := make(chan struct{})
+	go func() {
+		hub.Broadcast([]byte("second"))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Broadcast blocked on slow client")
+	}
+
+	assert.Equal(t, 0, hub.Clients())
+	<-c.send
+	_, open := <-c.send
+	assert.False(t, open)
+}
+
+// TestHub_CloseDisconnectsClients testa que Close encerra as conexões e recusa novas
+func TestHub_CloseDisconnectsClients(t *testing.T) {
+	hub := NewHub(nopLogger{})
+	server := newTestServer(t, hub)
+	conn := dial(t, server, hub, "")
+
+	hub.Close()
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err := conn.ReadMessage()
+	assert.Error(t, err)
+	assert.Equal(t, 0, hub.Clients())
+}
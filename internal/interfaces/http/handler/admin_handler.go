@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// AdminHandler gerencia endpoints administrativos e de diagnóstico
+type AdminHandler struct {
+	inspectUserCacheUC *usecase.InspectUserCacheUseCase
+	recomputeSectorsUC *usecase.RecomputeSectorsUseCase
+	backgroundRunner   usecase.BackgroundRunner
+	logger             logger.Logger
+}
+
+// NewAdminHandler cria uma nova instância do handler
+func NewAdminHandler(
+	inspectUserCacheUC *usecase.InspectUserCacheUseCase,
+	recomputeSectorsUC *usecase.RecomputeSectorsUseCase,
+	backgroundRunner usecase.BackgroundRunner,
+	logger logger.Logger,
+) *AdminHandler {
+	return &AdminHandler{
+		inspectUserCacheUC: inspectUserCacheUC,
+		recomputeSectorsUC: recomputeSectorsUC,
+		backgroundRunner:   backgroundRunner,
+		logger:             logger,
+	}
+}
+
+// GetUserCache retorna as entradas de cache de um usuário
+// @Summary Inspecionar cache do usuário
+// @Description Retorna as entradas de cache conhecidas do usuário (posição atual e histórico) com seus TTLs, indicando hits e misses
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} usecase.InspectUserCacheResponse "Entradas de cache do usuário"
+// @Failure 400 {object} map[string]interface{} "ID do usuário inválido"
+// @Failure 401 {object} map[string]interface{} "Token de admin inválido ou ausente"
+// @Failure 403 {object} map[string]interface{} "Endpoints de admin desabilitados"
+// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Router /admin/cache/user/{id} [get]
+func (h *AdminHandler) GetUserCache(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	userID := c.Param("id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "user ID is required",
+		})
+		return
+	}
+
+	// Executar use case
+	response, err := h.inspectUserCacheUC.Execute(c.Request.Context(), usecase.InspectUserCacheRequest{
+		UserID: userID,
+	})
+	if err != nil {
+		log.Error("Failed to inspect user cache",
+			"user_id", userID,
+			"error", err.Error(),
+		)
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to inspect user cache",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	log.Info("User cache inspected",
+		"user_id", userID,
+		"hits", response.Hits,
+		"misses", response.Misses,
+	)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RecomputeSectors inicia o recálculo dos setores de todas as posições armazenadas
+// @Summary Recalcular setores armazenados
+// @Description Inicia em background o recálculo de sector_x/sector_y de todas as posições (histórico e posição atual) com o esquema de setorização atual, em chunks. Use após mudar a origem ou o tamanho dos setores e acompanhe em GET /admin/sectors/recompute
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Success 202 {object} usecase.RecomputeSectorsStatus "Recálculo iniciado"
+// @Failure 401 {object} map[string]interface{} "Token de admin inválido ou ausente"
+// @Failure 403 {object} map[string]interface{} "Endpoints de admin desabilitados"
+// @Failure 409 {object} map[string]interface{} "Já existe um recálculo em execução"
+// @Router /admin/sectors/recompute [post]
+func (h *AdminHandler) RecomputeSectors(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	status, err := h.recomputeSectorsUC.Start(h.backgroundRunner)
+	if err != nil {
+		log.Warn("Sector recompute not started", "error", err.Error())
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to start sector recompute",
+			"details": err.Error(),
+			"job":     status,
+		})
+		return
+	}
+
+	log.Info("Sector recompute started")
+
+	c.JSON(http.StatusAccepted, status)
+}
+
+// GetRecomputeSectorsStatus retorna o estado do último recálculo de setores
+// @Summary Estado do recálculo de setores
+// @Description Retorna o estado do último recálculo iniciado (idle, running, completed ou failed), com o resumo ao terminar
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Success 200 {object} usecase.RecomputeSectorsStatus "Estado do recálculo"
+// @Failure 401 {object} map[string]interface{} "Token de admin inválido ou ausente"
+// @Failure 403 {object} map[string]interface{} "Endpoints de admin desabilitados"
+// @Router /admin/sectors/recompute [get]
+func (h *AdminHandler) GetRecomputeSectorsStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.recomputeSectorsUC.Status())
+}
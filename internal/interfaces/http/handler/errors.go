@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/service"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+)
+
+// errorStatus mapeia o erro de um use case para o status HTTP
+// Apenas erros sem sentinela (falhas de infraestrutura) resultam em 500
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrUserNotFound),
+		errors.Is(err, usecase.ErrPositionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput),
+		errors.Is(err, usecase.ErrInvalidSectorID),
+		errors.Is(err, usecase.ErrInvalidTimeRange),
+		errors.Is(err, usecase.ErrEmptyBatch),
+		errors.Is(err, usecase.ErrBatchTooLarge),
+		errors.Is(err, usecase.ErrEmptyNearbyBatch),
+		errors.Is(err, usecase.ErrNearbyBatchTooLarge),
+		errors.Is(err, usecase.ErrEmptyCurrentPositionsBatch),
+		errors.Is(err, usecase.ErrCurrentPositionsBatchTooLarge),
+		errors.Is(err, usecase.ErrEmptyCentroidGroup),
+		errors.Is(err, usecase.ErrCentroidGroupTooLarge),
+		errors.Is(err, usecase.ErrUnsupportedExportFormat):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrRecomputeInProgress):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrPositionRejected):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, repository.ErrQueryTimeout):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
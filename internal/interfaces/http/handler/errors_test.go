@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/service"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+)
+
+// nopLogger descarta todos os logs durante os testes
+type nopLogger struct{}
+
+func (nopLogger) Info(msg string, fields ...interface{})  {}
+func (nopLogger) Error(msg string, fields ...interface{}) {}
+func (nopLogger) Warn(msg string, fields ...interface{})  {}
+func (nopLogger) Fatal(msg string, fields ...interface{}) {}
+func (nopLogger) Debug(msg string, fields ...interface{}) {}
+func (nopLogger) Sync() error                             { return nil }
+
+// TestErrorStatus testa o mapeamento dos erros de use case para status HTTP
+func TestErrorStatus(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"user not found", fmt.Errorf("%w: user123", usecase.ErrUserNotFound), http.StatusNotFound},
+		{"position not found", fmt.Errorf("%w: pos123", usecase.ErrPositionNotFound), http.StatusNotFound},
+		{"recompute in progress", usecase.ErrRecomputeInProgress, http.StatusConflict},
+		{"invalid input", fmt.Errorf("%w: invalid user ID", usecase.ErrInvalidInput), http.StatusBadRequest},
+		{"invalid sector", usecase.ErrInvalidSectorID, http.StatusBadRequest},
+		{"invalid time range", usecase.ErrInvalidTimeRange, http.StatusBadRequest},
+		{"batch too large", usecase.ErrBatchTooLarge, http.StatusBadRequest},
+		{"position rejected", fmt.Errorf("%w: too fast", service.ErrPositionRejected), http.StatusUnprocessableEntity},
+		{"query timeout", fmt.Errorf("failed to find nearby positions: %w", repository.ErrQueryTimeout), http.StatusGatewayTimeout},
+		{"infrastructure failure", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, errorStatus(tc.err))
+		})
+	}
+}
+
+// newCurrentPositionRouter expõe GetCurrentPosition sobre os repositórios mockados (sempre cache miss)
+func newCurrentPositionRouter(userRepo *mocks.MockUserRepository, positionRepo *mocks.MockPositionRepository) *gin.Engine {
+	cache := new(mocks.MockCache)
+	cache.On("GetCachedUserPosition", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("cache miss"))
+	cache.On("CacheUserPosition", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	h := &UserHandler{
+		getCurrentPositionUC: usecase.NewGetCurrentPositionUseCase(userRepo, positionRepo, cache, nopLogger{}),
+		logger:               nopLogger{},
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/users/:id/position", h.GetCurrentPosition)
+	return router
+}
+
+// TestGetCurrentPosition_UserNotFoundReturns404 testa que usuário inexistente vira 404
+func TestGetCurrentPosition_UserNotFoundReturns404(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	userRepo.On("FindByID", mock.Anything, mock.Anything).Return(nil, entity.ErrUserIDNotFound)
+	router := newCurrentPositionRouter(userRepo, new(mocks.MockPositionRepository))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/user123/position", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// TestGetCurrentPosition_PositionNotFoundReturns404 testa que usuário sem posição vira 404
+func TestGetCurrentPosition_PositionNotFoundReturns404(t *testing.T) {
+	user, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	assert.NoError(t, err)
+
+	userRepo := new(mocks.MockUserRepository)
+	userRepo.On("FindByID", mock.Anything, mock.Anything).Return(user, nil)
+	positionRepo := new(mocks.MockPositionRepository)
+	positionRepo.On("FindCurrentByUserID", mock.Anything, mock.Anything).Return(nil, entity.ErrPositionNotFound)
+	router := newCurrentPositionRouter(userRepo, positionRepo)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/user123/position", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// TestGetCurrentPosition_InvalidUserIDReturns400 testa que ID em branco vira 400
+func TestGetCurrentPosition_InvalidUserIDReturns400(t *testing.T) {
+	router := newCurrentPositionRouter(new(mocks.MockUserRepository), new(mocks.MockPositionRepository))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/%20/position", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+// TestGetCurrentPosition_RepositoryFailureReturns500 testa que falha de infraestrutura continua 500
+func TestGetCurrentPosition_RepositoryFailureReturns500(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	userRepo.On("FindByID", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+	router := newCurrentPositionRouter(userRepo, new(mocks.MockPositionRepository))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/user123/position", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+// TestGetPositionByID_NotFoundReturns404 testa que posição inexistente vira 404
+func TestGetPositionByID_NotFoundReturns404(t *testing.T) {
+	positionRepo := new(mocks.MockPositionRepository)
+	positionRepo.On("FindByID", mock.Anything, mock.Anything).Return(nil, entity.ErrPositionNotFound)
+
+	h := &PositionHandler{
+		getPositionByIDUC: usecase.NewGetPositionByIDUseCase(new(mocks.MockUserRepository), positionRepo, nopLogger{}),
+		logger:            nopLogger{},
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/positions/:id", h.GetPositionByID)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/positions/pos123", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// TestSavePositionsBatch_OversizedBodyReturns413 testa que o corpo cortado pelo MaxBytesReader vira 413
+func TestSavePositionsBatch_OversizedBodyReturns413(t *testing.T) {
+	h := &PositionHandler{logger: nopLogger{}}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/positions/batch", func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 64)
+		h.SavePositionsBatch(c)
+	})
+
+	// Sem Content-Length: o limite só é percebido durante o binding
+	body := `{"positions": [` + strings.Repeat(`{"user_id": "user123", "latitude": 1, "longitude": 1},`, 10) + `]}`
+	req := httptest.NewRequest(http.MethodPost, "/positions/batch", io.NopCloser(strings.NewReader(body)))
+	req.ContentLength = -1
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// FeedHandler gerencia endpoints do feed de atividade
+type FeedHandler struct {
+	getRecentActivityUC *usecase.GetRecentActivityUseCase
+	logger              logger.Logger
+}
+
+// NewFeedHandler cria uma nova instância do handler
+func NewFeedHandler(
+	getRecentActivityUC *usecase.GetRecentActivityUseCase,
+	logger logger.Logger,
+) *FeedHandler {
+	return &FeedHandler{
+		getRecentActivityUC: getRecentActivityUC,
+		logger:              logger,
+	}
+}
+
+// GetRecentActivityRequest representa os parâmetros do feed de atividade recente
+type GetRecentActivityRequest struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// GetRecentActivity retorna as posições atualizadas mais recentemente
+// @Summary Feed de atividade recente
+// @Description Retorna as posições atuais atualizadas mais recentemente entre todos os usuários, da mais nova para a mais antiga
+// @Tags feed
+// @Accept json
+// @Produce json
+// @Param limit query int false "Número máximo de itens (padrão: 20, máximo: 100)"
+// @Success 200 {object} usecase.GetRecentActivityResponse "Posições mais recentes"
+// @Failure 400 {object} map[string]interface{} "Parâmetros inválidos"
+// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Router /feed/recent [get]
+func (h *FeedHandler) GetRecentActivity(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	var req GetRecentActivityRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error("Invalid query parameters", "error", err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.getRecentActivityUC.Execute(c.Request.Context(), usecase.GetRecentActivityRequest{
+		Limit: req.Limit,
+	})
+	if err != nil {
+		log.Error("Failed to get recent activity", "error", err.Error())
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to get recent activity",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -1,15 +1,70 @@
 package handler
 
 import (
+	"context"
+	"database/sql"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
 )
 
-// HealthHandler gerencia o endpoint de health check
-type HealthHandler struct {
-	// Futuramente adicionaremos dependências para DB e Redis
+// DefaultHealthCheckTimeout limita cada verificação de dependência
+const DefaultHealthCheckTimeout = 2 * time.Second
+
+// DatabaseHealthChecker é a parte do database.DB usada pelo health check
+type DatabaseHealthChecker interface {
+	Health(ctx context.Context) error
+	Stats() sql.DBStats
+}
+
+// RedisHealthChecker é a parte do cache.Redis usada pelo health check
+type RedisHealthChecker interface {
+	Health(ctx context.Context) error
+	PoolStats() *redis.PoolStats
+}
+
+// DependencyHealth representa o estado de uma dependência
+type DependencyHealth struct {
+	Status    string      `json:"status"` // "up" ou "down"
+	LatencyMs int64       `json:"latency_ms"`
+	Error     string      `json:"error,omitempty"`
+	Pool      interface{} `json:"pool"`
+}
+
+// DatabasePoolStats representa a saturação do pool do PostgreSQL
+type DatabasePoolStats struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+}
+
+// RedisPoolStats representa a saturação do pool do Redis
+type RedisPoolStats struct {
+	TotalConns uint32 `json:"total_conns"`
+	IdleConns  uint32 `json:"idle_conns"`
+	StaleConns uint32 `json:"stale_conns"`
+	Hits       uint32 `json:"hits"`
+	Misses     uint32 `json:"misses"`
+	Timeouts   uint32 `json:"timeouts"`
+}
+
+// DeepHealthResponse representa a resposta do health check profundo
+type DeepHealthResponse struct {
+	Status       string                      `json:"status"` // "healthy" ou "unhealthy"
+	Service      string                      `json:"service"`
+	Dependencies map[string]DependencyHealth `json:"dependencies"`
+}
+
+// LivenessResponse representa a resposta do liveness probe
+type LivenessResponse struct {
+	Status  string `json:"status"` // Sempre "alive"
+	Service string `json:"service"`
 }
 
 // HealthResponse representa a resposta do health check
@@ -20,16 +75,30 @@ type HealthResponse struct {
 	Services  map[string]string `json:"services"`
 }
 
+// HealthHandler verifica as dependências externas do serviço
+type HealthHandler struct {
+	db      DatabaseHealthChecker
+	redis   RedisHealthChecker
+	timeout time.Duration
+	logger  logger.Logger
+}
+
 // NewHealthHandler cria uma nova instância do handler de health check
-func NewHealthHandler() *HealthHandler {
-	return &HealthHandler{}
+func NewHealthHandler(db DatabaseHealthChecker, redis RedisHealthChecker, timeout time.Duration, logger logger.Logger) *HealthHandler {
+	if timeout <= 0 {
+		timeout = DefaultHealthCheckTimeout
+	}
+	return &HealthHandler{
+		db:      db,
+		redis:   redis,
+		timeout: timeout,
+		logger:  logger,
+	}
 }
 
-// Check verifica o status da aplicação e suas dependências
+// Check retorna apenas o status da API (sem tocar nas dependências)
+// A verificação de DB e Redis fica em DeepHealth
 func (h *HealthHandler) Check(c *gin.Context) {
-	// Por enquanto, vamos retornar apenas o status da API
-	// Nas próximas etapas adicionaremos verificação de DB e Redis
-
 	response := HealthResponse{
 		Status:    "healthy",
 		Timestamp: time.Now(),
@@ -44,3 +113,113 @@ func (h *HealthHandler) Check(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// DeepHealth verifica PostgreSQL e Redis
+// @Summary Health check profundo
+// @Description Verifica a conexão com PostgreSQL e Redis e retorna as estatísticas dos pools de conexão
+// @Tags health
+// @Produce json
+// @Success 200 {object} DeepHealthResponse "Todas as dependências disponíveis"
+// @Failure 503 {object} DeepHealthResponse "Alguma dependência indisponível"
+// @Router /health/deep [get]
+func (h *HealthHandler) DeepHealth(c *gin.Context) {
+	status, response := h.checkDependencies(c, "Deep health check failed")
+	c.JSON(status, response)
+}
+
+// Liveness responde 200 enquanto o processo atende requisições, sem tocar nas dependências
+// Uso: livenessProbe (uma falha do banco não deve reiniciar o pod)
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, LivenessResponse{
+		Status:  "alive",
+		Service: "geolocation-tracker",
+	})
+}
+
+// Readiness verifica PostgreSQL e Redis como o health check profundo
+// Uso: readinessProbe (o pod sai do balanceamento enquanto uma dependência estiver fora)
+// Fica fora do basePath /api/v1, como /health, por isso não entra no Swagger
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	status, response := h.checkDependencies(c, "Readiness check failed")
+	c.JSON(status, response)
+}
+
+// checkDependencies verifica PostgreSQL e Redis e retorna 503 se algum estiver fora
+func (h *HealthHandler) checkDependencies(c *gin.Context, failureMessage string) (int, DeepHealthResponse) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	ctx := c.Request.Context()
+
+	database := h.check(ctx, h.db.Health)
+	database.Pool = newDatabasePoolStats(h.db.Stats())
+
+	cache := h.check(ctx, h.redis.Health)
+	cache.Pool = newRedisPoolStats(h.redis.PoolStats())
+
+	response := DeepHealthResponse{
+		Status:  "healthy",
+		Service: "geolocation-tracker",
+		Dependencies: map[string]DependencyHealth{
+			"database": database,
+			"redis":    cache,
+		},
+	}
+
+	status := http.StatusOK
+	if database.Status != "up" || cache.Status != "up" {
+		response.Status = "unhealthy"
+		status = http.StatusServiceUnavailable
+
+		log.Warn(failureMessage,
+			"database", database.Status,
+			"redis", cache.Status,
+		)
+	}
+
+	return status, response
+}
+
+// check executa a verificação com timeout e mede a latência
+func (h *HealthHandler) check(ctx context.Context, health func(context.Context) error) DependencyHealth {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := health(ctx)
+	result := DependencyHealth{
+		Status:    "up",
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = "down"
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// newDatabasePoolStats converte as estatísticas do database/sql
+func newDatabasePoolStats(stats sql.DBStats) DatabasePoolStats {
+	return DatabasePoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+	}
+}
+
+// newRedisPoolStats converte as estatísticas do go-redis
+func newRedisPoolStats(stats *redis.PoolStats) RedisPoolStats {
+	if stats == nil {
+		return RedisPoolStats{}
+	}
+	return RedisPoolStats{
+		TotalConns: stats.TotalConns,
+		IdleConns:  stats.IdleConns,
+		StaleConns: stats.StaleConns,
+		Hits:       stats.Hits,
+		Misses:     stats.Misses,
+		Timeouts:   stats.Timeouts,
+	}
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDatabase simula database.DB com estado configurável
+type fakeDatabase struct {
+	err   error
+	stats sql.DBStats
+}
+
+func (f *fakeDatabase) Health(ctx context.Context) error { return f.err }
+func (f *fakeDatabase) Stats() sql.DBStats               { return f.stats }
+
+// fakeRedis simula cache.Redis com estado configurável
+type fakeRedis struct {
+	err   error
+	stats *redis.PoolStats
+}
+
+func (f *fakeRedis) Health(ctx context.Context) error { return f.err }
+func (f *fakeRedis) PoolStats() *redis.PoolStats      { return f.stats }
+
+// serveDeepHealth executa GET /health/deep sobre as dependências informadas
+func serveDeepHealth(t *testing.T, db DatabaseHealthChecker, cache RedisHealthChecker) (int, DeepHealthResponse) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/health/deep", NewHealthHandler(db, cache, 0, nopLogger{}).DeepHealth)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/deep", nil))
+
+	var response DeepHealthResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	return rec.Code, response
+}
+
+// TestDeepHealth_AllUp testa que dependências saudáveis retornam 200 com estatísticas dos pools
+func TestDeepHealth_AllUp(t *testing.T) {
+	db := &fakeDatabase{stats: sql.DBStats{MaxOpenConnections: 25, OpenConnections: 4, InUse: 1, Idle: 3}}
+	cache := &fakeRedis{stats: &redis.PoolStats{TotalConns: 10, IdleConns: 8}}
+
+	code, response := serveDeepHealth(t, db, cache)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "healthy", response.Status)
+	assert.Equal(t, "up", response.Dependencies["database"].Status)
+	assert.Equal(t, "up", response.Dependencies["redis"].Status)
+
+	pool := response.Dependencies["database"].Pool.(map[string]interface{})
+	assert.Equal(t, float64(25), pool["max_open_connections"])
+	assert.Equal(t, float64(1), pool["in_use"])
+}
+
+// TestDeepHealth_RedisDown testa que uma dependência fora retorna 503 com o erro
+func TestDeepHealth_RedisDown(t *testing.T) {
+	code, response := serveDeepHealth(t, &fakeDatabase{}, &fakeRedis{err: errors.New("connection refused")})
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unhealthy", response.Status)
+	assert.Equal(t, "up", response.Dependencies["database"].Status)
+	assert.Equal(t, "down", response.Dependencies["redis"].Status)
+	assert.Equal(t, "connection refused", response.Dependencies["redis"].Error)
+}
+
+// TestLiveness_IgnoresDependencies testa que o liveness responde 200 mesmo com o banco fora
+func TestLiveness_IgnoresDependencies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/healthz", NewHealthHandler(&fakeDatabase{err: errors.New("connection refused")}, &fakeRedis{}, 0, nopLogger{}).Liveness)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	var response LivenessResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "alive", response.Status)
+}
+
+// TestReadiness_RedisDown testa que o readiness retorna 503 quando uma dependência está fora
+func TestReadiness_RedisDown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/readyz", NewHealthHandler(&fakeDatabase{}, &fakeRedis{err: errors.New("connection refused")}, 0, nopLogger{}).Readiness)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var response DeepHealthResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "unhealthy", response.Status)
+	assert.Equal(t, "down", response.Dependencies["redis"].Status)
+}
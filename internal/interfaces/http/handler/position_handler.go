@@ -1,34 +1,57 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/pkg/geojson"
 	"github.com/vitao/geolocation-tracker/pkg/logger"
 )
 
 // PositionHandler gerencia endpoints relacionados a posições
 type PositionHandler struct {
-	savePositionUC     *usecase.SaveUserPositionUseCase
-	findNearbyUC       *usecase.FindNearbyUsersUseCase
-	getUsersInSectorUC *usecase.GetUsersInSectorUseCase
-	logger             logger.Logger
+	savePositionUC       *usecase.SaveUserPositionUseCase
+	savePositionsBatchUC *usecase.SaveUserPositionsBatchUseCase
+	findNearbyUC         *usecase.FindNearbyUsersUseCase
+	findNearbyBatchUC    *usecase.FindNearbyUsersBatchUseCase
+	getUsersInSectorUC   *usecase.GetUsersInSectorUseCase
+	getPositionByIDUC    *usecase.GetPositionByIDUseCase
+	getCurrentBatchUC    *usecase.GetCurrentPositionsBatchUseCase
+	getInBoundingBoxUC   *usecase.GetPositionsInBoundingBoxUseCase
+	getGroupCentroidUC   *usecase.GetGroupCentroidUseCase
+	strictJSON           bool // Recusa chaves desconhecidas nos corpos JSON
+	logger               logger.Logger
 }
 
 // NewPositionHandler cria uma nova instância do handler
 func NewPositionHandler(
 	savePositionUC *usecase.SaveUserPositionUseCase,
+	savePositionsBatchUC *usecase.SaveUserPositionsBatchUseCase,
 	findNearbyUC *usecase.FindNearbyUsersUseCase,
+	findNearbyBatchUC *usecase.FindNearbyUsersBatchUseCase,
 	getUsersInSectorUC *usecase.GetUsersInSectorUseCase,
+	getPositionByIDUC *usecase.GetPositionByIDUseCase,
+	getCurrentBatchUC *usecase.GetCurrentPositionsBatchUseCase,
+	getInBoundingBoxUC *usecase.GetPositionsInBoundingBoxUseCase,
+	getGroupCentroidUC *usecase.GetGroupCentroidUseCase,
+	strictJSON bool,
 	logger logger.Logger,
 ) *PositionHandler {
 	return &PositionHandler{
-		savePositionUC:     savePositionUC,
-		findNearbyUC:       findNearbyUC,
-		getUsersInSectorUC: getUsersInSectorUC,
-		logger:             logger,
+		savePositionUC:       savePositionUC,
+		savePositionsBatchUC: savePositionsBatchUC,
+		findNearbyUC:         findNearbyUC,
+		findNearbyBatchUC:    findNearbyBatchUC,
+		getUsersInSectorUC:   getUsersInSectorUC,
+		getPositionByIDUC:    getPositionByIDUC,
+		getCurrentBatchUC:    getCurrentBatchUC,
+		getInBoundingBoxUC:   getInBoundingBoxUC,
+		getGroupCentroidUC:   getGroupCentroidUC,
+		strictJSON:           strictJSON,
+		logger:               logger,
 	}
 }
 
@@ -37,28 +60,32 @@ type SavePositionRequest struct {
 	UserID    string  `json:"user_id" binding:"required"`
 	Latitude  float64 `json:"latitude" binding:"required,min=-90,max=90"`
 	Longitude float64 `json:"longitude" binding:"required,min=-180,max=180"`
+
+	// Timestamp é o momento da leitura no cliente (RFC3339), para envios offline; ausente = horário do servidor
+	Timestamp *time.Time `json:"timestamp,omitempty"`
 }
 
 // SavePosition salva a posição de um usuário
 // @Summary Salvar posição do usuário
-// @Description Salva uma nova posição geográfica para um usuário específico
+// @Description Salva uma nova posição geográfica para um usuário específico. O timestamp opcional (RFC3339) registra o horário real da leitura em envios offline; sem ele vale o horário do servidor. Timestamps no futuro ou mais antigos que POSITIONS_MAX_AGE_HOURS são recusados com 400
 // @Tags positions
 // @Accept json
 // @Produce json
 // @Param request body SavePositionRequest true "Dados da posição"
 // @Success 201 {object} usecase.SaveUserPositionResponse "Posição salva com sucesso"
-// @Failure 400 {object} map[string]interface{} "Dados de posição inválidos"
+// @Failure 400 {object} ValidationErrorResponse "Dados de posição inválidos"
 // @Failure 404 {object} map[string]interface{} "Usuário não encontrado"
+// @Failure 422 {object} map[string]interface{} "Posição recusada pela validação do evento ou por velocidade implausível"
+// @Failure 413 {object} map[string]interface{} "Corpo da requisição acima de HTTP_MAX_BODY_BYTES"
 // @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
 // @Router /positions [post]
 func (h *PositionHandler) SavePosition(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
 	var req SavePositionRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Error("Invalid request payload", "error", err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request payload",
-			"details": err.Error(),
-		})
+	if err := bindJSON(c, &req, h.strictJSON); err != nil {
+		log.Error("Invalid request payload", "error", err.Error())
+		respondValidationError(c, "Invalid request payload", &req, err)
 		return
 	}
 
@@ -68,25 +95,29 @@ func (h *PositionHandler) SavePosition(c *gin.Context) {
 		Latitude:  req.Latitude,
 		Longitude: req.Longitude,
 		Timestamp: time.Now(),
+		RequestID: usecase.RequestIDFromContext(c.Request.Context()),
+	}
+	if req.Timestamp != nil {
+		ucRequest.Timestamp = *req.Timestamp
 	}
 
 	// Executar use case
 	response, err := h.savePositionUC.Execute(c.Request.Context(), ucRequest)
 	if err != nil {
-		h.logger.Error("Failed to save position",
+		log.Error("Failed to save position",
 			"user_id", req.UserID,
 			"latitude", req.Latitude,
 			"longitude", req.Longitude,
 			"error", err.Error(),
 		)
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err), gin.H{
 			"error":   "Failed to save position",
 			"details": err.Error(),
 		})
 		return
 	}
 
-	h.logger.Info("Position saved successfully",
+	log.Info("Position saved successfully",
 		"user_id", req.UserID,
 		"position_id", response.PositionID,
 		"sector_id", response.SectorID,
@@ -95,12 +126,89 @@ func (h *PositionHandler) SavePosition(c *gin.Context) {
 	c.JSON(http.StatusCreated, response)
 }
 
+// BatchPositionPayload representa uma posição do lote
+type BatchPositionPayload struct {
+	UserID    string    `json:"user_id" binding:"required"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// SavePositionsBatchRequest representa o payload para salvar posições em lote
+type SavePositionsBatchRequest struct {
+	Positions []BatchPositionPayload `json:"positions" binding:"required,min=1,max=500,dive"`
+}
+
+// SavePositionsBatch salva várias posições em uma única transação
+// @Summary Salvar posições em lote
+// @Description Salva até 500 posições em uma única transação. Cada item é validado individualmente: coordenadas inválidas não rejeitam o lote inteiro. Apenas a posição mais recente de cada usuário gera evento de mudança de posição
+// @Tags positions
+// @Accept json
+// @Produce json
+// @Param request body SavePositionsBatchRequest true "Posições do lote"
+// @Success 200 {object} usecase.SaveUserPositionsBatchResponse "Resultado por item do lote"
+// @Failure 400 {object} map[string]interface{} "Payload do lote inválido"
+// @Failure 404 {object} map[string]interface{} "Usuário não encontrado"
+// @Failure 413 {object} map[string]interface{} "Corpo da requisição acima de HTTP_MAX_BODY_BYTES"
+// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Router /positions/batch [post]
+func (h *PositionHandler) SavePositionsBatch(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	var req SavePositionsBatchRequest
+	if err := bindJSON(c, &req, h.strictJSON); err != nil {
+		log.Error("Invalid batch payload", "error", err.Error())
+		c.JSON(bindErrorStatus(err), gin.H{
+			"error":   "Invalid batch payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Converter para use case request (coordenadas são validadas por item no use case)
+	items := make([]usecase.BatchPositionItem, len(req.Positions))
+	for i, p := range req.Positions {
+		items[i] = usecase.BatchPositionItem{
+			UserID:    p.UserID,
+			Latitude:  p.Latitude,
+			Longitude: p.Longitude,
+			Timestamp: p.Timestamp,
+		}
+	}
+
+	response, err := h.savePositionsBatchUC.Execute(c.Request.Context(), usecase.SaveUserPositionsBatchRequest{
+		Positions: items,
+		RequestID: usecase.RequestIDFromContext(c.Request.Context()),
+	})
+	if err != nil {
+		log.Error("Failed to save position batch",
+			"positions", len(items),
+			"error", err.Error(),
+		)
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to save position batch",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	log.Info("Position batch saved",
+		"saved", response.Saved,
+		"failed", response.Failed,
+	)
+
+	c.JSON(http.StatusOK, response)
+}
+
 // FindNearbyRequest representa o payload para buscar usuários próximos
 type FindNearbyRequest struct {
-	Latitude   float64 `form:"latitude" binding:"required,min=-90,max=90"`
-	Longitude  float64 `form:"longitude" binding:"required,min=-180,max=180"`
-	RadiusM    float64 `form:"radius_meters" binding:"required,min=1,max=50000"`
-	MaxResults int     `form:"max_results"`
+	Latitude      float64 `form:"latitude" binding:"required,min=-90,max=90"`
+	Longitude     float64 `form:"longitude" binding:"required,min=-180,max=180"`
+	RadiusM       float64 `form:"radius_meters" binding:"required,min=1,max=50000"`
+	MaxResults    int     `form:"max_results" binding:"omitempty,min=0"`
+	Adaptive      bool    `form:"adaptive"`
+	MinUsers      int     `form:"min_users" binding:"omitempty,min=1,max=100"`
+	MaxAgeSeconds int     `form:"max_age_seconds" binding:"omitempty,min=1,max=86400"`
 }
 
 // FindNearbyUsers busca usuários próximos
@@ -108,73 +216,247 @@ type FindNearbyRequest struct {
 // @Description Busca usuários próximos a uma coordenada específica dentro de um raio determinado
 // @Tags positions
 // @Accept json
-// @Produce json
+// @Produce json,application/geo+json
 // @Param user_id query string true "ID do usuário que está buscando"
 // @Param latitude query number true "Latitude da posição de referência (-90 a 90)"
 // @Param longitude query number true "Longitude da posição de referência (-180 a 180)"
 // @Param radius_meters query number true "Raio de busca em metros (1 a 50000)"
-// @Param max_results query int false "Número máximo de resultados (padrão: 50)"
+// @Param max_results query int false "Número máximo de resultados (padrão: 20, via NEARBY_DEFAULT_MAX_RESULTS; valores acima do teto NEARBY_MAX_RESULTS, padrão 100, são reduzidos a ele; negativos retornam 400)"
+// @Param adaptive query bool false "Começa com raio pequeno e dobra até radius_meters ou até achar min_users"
+// @Param min_users query int false "Usuários desejados na busca adaptativa (padrão: 5)"
+// @Param max_age_seconds query int false "Ignora usuários vistos há mais segundos que isso (1 a 86400; padrão: sem filtro)"
+// @Param format query string false "Formato da resposta: geojson retorna uma FeatureCollection (também via Accept: application/geo+json)"
 // @Success 200 {object} usecase.FindNearbyUsersResponse "Lista de usuários próximos"
-// @Failure 400 {object} map[string]interface{} "Parâmetros de busca inválidos"
+// @Failure 400 {object} ValidationErrorResponse "Parâmetros de busca inválidos"
+// @Failure 404 {object} map[string]interface{} "Usuário não encontrado"
 // @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
 // @Router /positions/nearby [get]
 func (h *PositionHandler) FindNearbyUsers(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
 	userID := c.Query("user_id")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "user_id is required",
-		})
+		respondMissingField(c, "user_id")
 		return
 	}
 
 	var req FindNearbyRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		h.logger.Error("Invalid query parameters", "error", err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid query parameters",
-			"details": err.Error(),
-		})
+		log.Error("Invalid query parameters", "error", err.Error())
+		respondValidationError(c, "Invalid query parameters", &req, err)
 		return
 	}
 
 	// Converter para use case request
 	ucRequest := usecase.FindNearbyUsersRequest{
-		UserID:     userID,
-		Latitude:   req.Latitude,
-		Longitude:  req.Longitude,
-		RadiusM:    req.RadiusM,
-		MaxResults: req.MaxResults,
+		UserID:        userID,
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+		RadiusM:       req.RadiusM,
+		MaxResults:    req.MaxResults,
+		Adaptive:      req.Adaptive,
+		MinUsers:      req.MinUsers,
+		MaxAgeSeconds: req.MaxAgeSeconds,
 	}
 
 	// Executar use case
 	response, err := h.findNearbyUC.Execute(c.Request.Context(), ucRequest)
 	if err != nil {
-		h.logger.Error("Failed to find nearby users",
+		log.Error("Failed to find nearby users",
 			"user_id", userID,
 			"latitude", req.Latitude,
 			"longitude", req.Longitude,
 			"radius", req.RadiusM,
 			"error", err.Error(),
 		)
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err), gin.H{
 			"error":   "Failed to find nearby users",
 			"details": err.Error(),
 		})
 		return
 	}
 
-	h.logger.Info("Nearby users search completed",
+	log.Info("Nearby users search completed",
 		"user_id", userID,
 		"total_found", response.TotalFound,
 	)
 
+	if geojson.Requested(c.Query("format"), c.GetHeader("Accept")) {
+		renderGeoJSON(c, nearbyUsersToGeoJSON(response))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// NearbyBatchCenterPayload representa um centro da busca em lote
+type NearbyBatchCenterPayload struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	RadiusM   float64 `json:"radius_meters"`
+}
+
+// FindNearbyBatchRequest representa o payload da busca de proximidade em lote
+type FindNearbyBatchRequest struct {
+	Centers    []NearbyBatchCenterPayload `json:"centers" binding:"required,min=1,max=50"`
+	MaxResults int                        `json:"max_results" binding:"omitempty,min=0"` // Por centro; padrão 20, reduzido ao teto NEARBY_MAX_RESULTS (padrão 100)
+}
+
+// FindNearbyUsersBatch busca usuários próximos de vários centros
+// @Summary Buscar usuários próximos em lote
+// @Description Executa a busca de proximidade para até 50 centros em paralelo e retorna os resultados na mesma ordem do pedido. Centros inválidos são reportados individualmente sem rejeitar o lote inteiro
+// @Tags positions
+// @Accept json
+// @Produce json
+// @Param request body FindNearbyBatchRequest true "Centros da busca"
+// @Success 200 {object} usecase.FindNearbyUsersBatchResponse "Resultado por centro"
+// @Failure 400 {object} map[string]interface{} "Payload do lote inválido"
+// @Failure 413 {object} map[string]interface{} "Corpo da requisição acima de HTTP_MAX_BODY_BYTES"
+// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Router /positions/nearby/batch [post]
+func (h *PositionHandler) FindNearbyUsersBatch(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	var req FindNearbyBatchRequest
+	if err := bindJSON(c, &req, h.strictJSON); err != nil {
+		log.Error("Invalid nearby batch payload", "error", err.Error())
+		c.JSON(bindErrorStatus(err), gin.H{
+			"error":   "Invalid nearby batch payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Converter para use case request (coordenadas e raio são validados por centro no use case)
+	centers := make([]usecase.NearbyBatchCenter, len(req.Centers))
+	for i, center := range req.Centers {
+		centers[i] = usecase.NearbyBatchCenter{
+			Latitude:  center.Latitude,
+			Longitude: center.Longitude,
+			RadiusM:   center.RadiusM,
+		}
+	}
+
+	response, err := h.findNearbyBatchUC.Execute(c.Request.Context(), usecase.FindNearbyUsersBatchRequest{
+		Centers:    centers,
+		MaxResults: req.MaxResults,
+	})
+	if err != nil {
+		log.Error("Failed to run nearby batch search",
+			"centers", len(centers),
+			"error", err.Error(),
+		)
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to run nearby batch search",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	log.Info("Nearby batch search completed",
+		"centers", len(centers),
+		"failed", response.Failed,
+	)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetCurrentPositionsBatchRequest representa o payload da busca de posições atuais em lote
+type GetCurrentPositionsBatchRequest struct {
+	UserIDs []string `json:"user_ids" binding:"required,min=1,max=100"`
+}
+
+// GetCurrentPositionsBatch retorna a posição atual de vários usuários
+// @Summary Buscar posições atuais em lote
+// @Description Retorna a posição atual de até 100 usuários em uma única consulta, na ordem dos IDs pedidos. IDs sem posição atual aparecem em missing_user_ids
+// @Tags positions
+// @Accept json
+// @Produce json
+// @Param request body GetCurrentPositionsBatchRequest true "IDs dos usuários"
+// @Success 200 {object} usecase.GetCurrentPositionsBatchResponse "Posições encontradas e IDs sem posição"
+// @Failure 400 {object} map[string]interface{} "Payload do lote inválido"
+// @Failure 413 {object} map[string]interface{} "Corpo da requisição acima de HTTP_MAX_BODY_BYTES"
+// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Router /positions/current/batch [post]
+func (h *PositionHandler) GetCurrentPositionsBatch(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	var req GetCurrentPositionsBatchRequest
+	if err := bindJSON(c, &req, h.strictJSON); err != nil {
+		log.Error("Invalid current positions batch payload", "error", err.Error())
+		respondValidationError(c, "Invalid current positions batch payload", &req, err)
+		return
+	}
+
+	response, err := h.getCurrentBatchUC.Execute(c.Request.Context(), usecase.GetCurrentPositionsBatchRequest{
+		UserIDs: req.UserIDs,
+	})
+	if err != nil {
+		log.Error("Failed to get current positions batch",
+			"users", len(req.UserIDs),
+			"error", err.Error(),
+		)
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to get current positions batch",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetGroupCentroidRequest representa o payload do ponto de encontro do grupo
+type GetGroupCentroidRequest struct {
+	UserIDs []string `json:"user_ids" binding:"max=100"`
+}
+
+// GetGroupCentroid calcula o ponto de encontro de um grupo de usuários
+// @Summary Ponto de encontro do grupo
+// @Description Calcula o centro geográfico (média esférica) das posições atuais de até 100 usuários e a maior distância de um membro até ele. Com um único usuário o centro é a própria posição; IDs sem posição atual ficam fora do cálculo e aparecem em missing_user_ids
+// @Tags positions
+// @Accept json
+// @Produce json
+// @Param request body GetGroupCentroidRequest true "IDs dos usuários do grupo"
+// @Success 200 {object} usecase.GetGroupCentroidResponse "Centro do grupo e distância de cada membro"
+// @Failure 400 {object} map[string]interface{} "Lista vazia, acima de 100 IDs ou ID inválido"
+// @Failure 404 {object} map[string]interface{} "Nenhum usuário do grupo tem posição atual"
+// @Failure 413 {object} map[string]interface{} "Corpo da requisição acima de HTTP_MAX_BODY_BYTES"
+// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Router /positions/centroid [post]
+func (h *PositionHandler) GetGroupCentroid(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	var req GetGroupCentroidRequest
+	if err := bindJSON(c, &req, h.strictJSON); err != nil {
+		log.Error("Invalid group centroid payload", "error", err.Error())
+		respondValidationError(c, "Invalid group centroid payload", &req, err)
+		return
+	}
+
+	response, err := h.getGroupCentroidUC.Execute(c.Request.Context(), usecase.GetGroupCentroidRequest{
+		UserIDs: req.UserIDs,
+	})
+	if err != nil {
+		log.Error("Failed to calculate group centroid",
+			"users", len(req.UserIDs),
+			"error", err.Error(),
+		)
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to calculate group centroid",
+			"details": err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
 // GetUsersInSectorRequest representa o payload para buscar usuários no setor
 type GetUsersInSectorRequest struct {
-	Latitude  float64 `form:"latitude" binding:"required,min=-90,max=90"`
-	Longitude float64 `form:"longitude" binding:"required,min=-180,max=180"`
+	Latitude      float64 `form:"latitude" binding:"required,min=-90,max=90"`
+	Longitude     float64 `form:"longitude" binding:"required,min=-180,max=180"`
+	MaxAgeSeconds int     `form:"max_age_seconds" binding:"omitempty,min=1,max=86400"`
 }
 
 // GetUsersInSector busca usuários no mesmo setor
@@ -182,61 +464,258 @@ type GetUsersInSectorRequest struct {
 // @Description Busca todos os usuários que estão no mesmo setor geográfico de uma coordenada específica
 // @Tags positions
 // @Accept json
-// @Produce json
+// @Produce json,application/geo+json
 // @Param user_id query string true "ID do usuário que está buscando"
 // @Param latitude query number true "Latitude da posição de referência (-90 a 90)"
 // @Param longitude query number true "Longitude da posição de referência (-180 a 180)"
+// @Param max_age_seconds query int false "Ignora usuários vistos há mais segundos que isso (1 a 86400; padrão: sem filtro)"
+// @Param format query string false "Formato da resposta: geojson retorna uma FeatureCollection (também via Accept: application/geo+json)"
 // @Success 200 {object} usecase.GetUsersInSectorResponse "Lista de usuários no setor"
-// @Failure 400 {object} map[string]interface{} "Parâmetros de busca inválidos"
+// @Failure 400 {object} ValidationErrorResponse "Parâmetros de busca inválidos"
+// @Failure 404 {object} map[string]interface{} "Usuário não encontrado"
 // @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
 // @Router /positions/sector [get]
 func (h *PositionHandler) GetUsersInSector(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
 	userID := c.Query("user_id")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "user_id is required",
-		})
+		respondMissingField(c, "user_id")
 		return
 	}
 
 	var req GetUsersInSectorRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		h.logger.Error("Invalid query parameters", "error", err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid query parameters",
-			"details": err.Error(),
-		})
+		log.Error("Invalid query parameters", "error", err.Error())
+		respondValidationError(c, "Invalid query parameters", &req, err)
 		return
 	}
 
 	// Converter para use case request
 	ucRequest := usecase.GetUsersInSectorRequest{
-		UserID:    userID,
-		Latitude:  req.Latitude,
-		Longitude: req.Longitude,
+		UserID:        userID,
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+		MaxAgeSeconds: req.MaxAgeSeconds,
 	}
 
 	// Executar use case
 	response, err := h.getUsersInSectorUC.Execute(c.Request.Context(), ucRequest)
 	if err != nil {
-		h.logger.Error("Failed to get users in sector",
+		log.Error("Failed to get users in sector",
 			"user_id", userID,
 			"latitude", req.Latitude,
 			"longitude", req.Longitude,
 			"error", err.Error(),
 		)
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err), gin.H{
 			"error":   "Failed to get users in sector",
 			"details": err.Error(),
 		})
 		return
 	}
 
-	h.logger.Info("Sector users search completed",
+	log.Info("Sector users search completed",
 		"user_id", userID,
 		"sector_id", response.SectorID,
 		"total_found", response.TotalFound,
 	)
 
+	if geojson.Requested(c.Query("format"), c.GetHeader("Accept")) {
+		renderGeoJSON(c, sectorUsersToGeoJSON(response))
+		return
+	}
+
 	c.JSON(http.StatusOK, response)
 }
+
+// GetPositionByID retorna uma posição do histórico pelo ID
+// @Summary Buscar posição por ID
+// @Description Retorna coordenada, setor, horário de registro e dono de uma posição (ex.: position_id de um evento position.changed)
+// @Tags positions
+// @Accept json
+// @Produce json
+// @Param id path string true "ID da posição"
+// @Success 200 {object} usecase.GetPositionByIDResponse "Posição encontrada"
+// @Failure 400 {object} map[string]interface{} "ID inválido"
+// @Failure 404 {object} map[string]interface{} "Posição não encontrada"
+// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Router /positions/{id} [get]
+func (h *PositionHandler) GetPositionByID(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	positionID := c.Param("id")
+	if positionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "position ID is required",
+		})
+		return
+	}
+
+	response, err := h.getPositionByIDUC.Execute(c.Request.Context(), usecase.GetPositionByIDRequest{
+		PositionID: positionID,
+	})
+	if err != nil {
+		if errors.Is(err, usecase.ErrPositionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Position not found",
+				"details": err.Error(),
+			})
+			return
+		}
+		log.Error("Failed to get position",
+			"position_id", positionID,
+			"error", err.Error(),
+		)
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to get position",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetPositionsInBoundingBoxRequest representa o retângulo da busca (viewport do mapa)
+// Ponteiros para aceitar 0 (equador/Greenwich) como valor informado
+type GetPositionsInBoundingBoxRequest struct {
+	MinLatitude  *float64 `form:"min_lat" binding:"required,min=-90,max=90"`
+	MinLongitude *float64 `form:"min_lng" binding:"required,min=-180,max=180"`
+	MaxLatitude  *float64 `form:"max_lat" binding:"required,min=-90,max=90"`
+	MaxLongitude *float64 `form:"max_lng" binding:"required,min=-180,max=180"`
+	Limit        int      `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+// GetPositionsInBoundingBox busca os usuários com posição atual dentro de um retângulo
+// @Summary Buscar usuários em um retângulo
+// @Description Retorna as posições atuais dentro do retângulo (viewport do mapa), atualizadas mais recentemente primeiro. Exige min < max nos dois eixos, não aceita retângulos que cruzam o antimeridiano e rejeita áreas acima de 10.000 km²
+// @Tags positions
+// @Accept json
+// @Produce json,application/geo+json
+// @Param min_lat query number true "Latitude mínima (-90 a 90)"
+// @Param min_lng query number true "Longitude mínima (-180 a 180)"
+// @Param max_lat query number true "Latitude máxima (maior que min_lat)"
+// @Param max_lng query number true "Longitude máxima (maior que min_lng)"
+// @Param limit query int false "Número máximo de posições (1 a 500, padrão: 100)"
+// @Param format query string false "Formato da resposta: geojson retorna uma FeatureCollection (também via Accept: application/geo+json)"
+// @Success 200 {object} usecase.GetPositionsInBoundingBoxResponse "Posições no retângulo"
+// @Failure 400 {object} ValidationErrorResponse "Retângulo inválido ou grande demais"
+// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Router /positions/bbox [get]
+func (h *PositionHandler) GetPositionsInBoundingBox(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	var req GetPositionsInBoundingBoxRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error("Invalid query parameters", "error", err.Error())
+		respondValidationError(c, "Invalid query parameters", &req, err)
+		return
+	}
+
+	response, err := h.getInBoundingBoxUC.Execute(c.Request.Context(), usecase.GetPositionsInBoundingBoxRequest{
+		MinLatitude:  *req.MinLatitude,
+		MinLongitude: *req.MinLongitude,
+		MaxLatitude:  *req.MaxLatitude,
+		MaxLongitude: *req.MaxLongitude,
+		Limit:        req.Limit,
+	})
+	if err != nil {
+		log.Error("Failed to find positions in bounding box", "error", err.Error())
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to find positions in bounding box",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	log.Info("Bounding box search completed",
+		"area_km2", response.AreaKm2,
+		"total_found", response.TotalFound,
+	)
+
+	if geojson.Requested(c.Query("format"), c.GetHeader("Accept")) {
+		renderGeoJSON(c, boundingBoxPositionsToGeoJSON(response))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// renderGeoJSON responde com a FeatureCollection usando o content type GeoJSON
+func renderGeoJSON(c *gin.Context, collection *geojson.FeatureCollection) {
+	c.Header("Content-Type", geojson.MediaType)
+	c.JSON(http.StatusOK, collection)
+}
+
+// nearbyUsersToGeoJSON converte a busca de proximidade; o search center vem marcado nas propriedades
+func nearbyUsersToGeoJSON(response *usecase.FindNearbyUsersResponse) *geojson.FeatureCollection {
+	features := make([]geojson.Feature, 0, len(response.NearbyUsers)+1)
+
+	if response.SearchCenter.UserID != "" {
+		center := response.SearchCenter
+		features = append(features, geojson.NewPointFeature(center.Latitude, center.Longitude, map[string]interface{}{
+			"user_id":       center.UserID,
+			"user_name":     center.UserName,
+			"position_id":   center.PositionID,
+			"sector_id":     center.SectorID,
+			"age":           center.Age,
+			"search_center": true,
+		}))
+	}
+
+	for _, user := range response.NearbyUsers {
+		features = append(features, geojson.NewPointFeature(user.Latitude, user.Longitude, map[string]interface{}{
+			"user_id":         user.UserID,
+			"user_name":       user.UserName,
+			"position_id":     user.PositionID,
+			"sector_id":       user.SectorID,
+			"distance_meters": user.DistanceM,
+			"age":             user.Age,
+			"search_center":   false,
+		}))
+	}
+
+	return geojson.NewFeatureCollection(features)
+}
+
+// sectorUsersToGeoJSON converte a busca por setor; o solicitante vem marcado nas propriedades
+func sectorUsersToGeoJSON(response *usecase.GetUsersInSectorResponse) *geojson.FeatureCollection {
+	features := make([]geojson.Feature, 0, len(response.UsersInSector)+1)
+
+	sectorUser := func(user usecase.SectorUserResponse, requestedBy bool) geojson.Feature {
+		return geojson.NewPointFeature(user.Latitude, user.Longitude, map[string]interface{}{
+			"user_id":      user.UserID,
+			"user_name":    user.UserName,
+			"position_id":  user.PositionID,
+			"sector_id":    response.SectorID,
+			"age":          user.Age,
+			"requested_by": requestedBy,
+		})
+	}
+
+	if response.RequestedBy.UserID != "" {
+		features = append(features, sectorUser(response.RequestedBy, true))
+	}
+	for _, user := range response.UsersInSector {
+		features = append(features, sectorUser(user, false))
+	}
+
+	return geojson.NewFeatureCollection(features)
+}
+
+// boundingBoxPositionsToGeoJSON converte a busca por retângulo
+func boundingBoxPositionsToGeoJSON(response *usecase.GetPositionsInBoundingBoxResponse) *geojson.FeatureCollection {
+	features := make([]geojson.Feature, 0, len(response.Positions))
+
+	for _, position := range response.Positions {
+		features = append(features, geojson.NewPointFeature(position.Latitude, position.Longitude, map[string]interface{}{
+			"user_id":     position.UserID,
+			"position_id": position.PositionID,
+			"sector_id":   position.SectorID,
+			"recorded_at": position.RecordedAt,
+		}))
+	}
+
+	return geojson.NewFeatureCollection(features)
+}
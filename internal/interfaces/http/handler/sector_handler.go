@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// SectorHandler gerencia endpoints relacionados a setores
+type SectorHandler struct {
+	getSectorsAroundUC   *usecase.GetSectorsAroundUseCase
+	getSectorStatsUC     *usecase.GetSectorStatisticsUseCase
+	getSectorDensityUC   *usecase.GetSectorDensityUseCase
+	getSectorAnalysisUC  *usecase.GetSectorAnalysisUseCase
+	getOccupiedSectorsUC *usecase.GetOccupiedSectorsUseCase
+	logger               logger.Logger
+}
+
+// NewSectorHandler cria uma nova instância do handler
+func NewSectorHandler(
+	getSectorsAroundUC *usecase.GetSectorsAroundUseCase,
+	getSectorStatsUC *usecase.GetSectorStatisticsUseCase,
+	getSectorDensityUC *usecase.GetSectorDensityUseCase,
+	getSectorAnalysisUC *usecase.GetSectorAnalysisUseCase,
+	getOccupiedSectorsUC *usecase.GetOccupiedSectorsUseCase,
+	logger logger.Logger,
+) *SectorHandler {
+	return &SectorHandler{
+		getSectorsAroundUC:   getSectorsAroundUC,
+		getSectorStatsUC:     getSectorStatsUC,
+		getSectorDensityUC:   getSectorDensityUC,
+		getSectorAnalysisUC:  getSectorAnalysisUC,
+		getOccupiedSectorsUC: getOccupiedSectorsUC,
+		logger:               logger,
+	}
+}
+
+// GetSectorsAroundRequest representa os parâmetros para buscar setores ao redor
+type GetSectorsAroundRequest struct {
+	Latitude  float64 `form:"latitude" binding:"required,min=-90,max=90"`
+	Longitude float64 `form:"longitude" binding:"required,min=-180,max=180"`
+	Rings     int     `form:"rings" binding:"omitempty,min=1,max=10"`
+}
+
+// GetSectorsAround retorna a contagem de usuários nos setores ao redor de um ponto
+// @Summary Contagem de usuários nos setores ao redor
+// @Description Retorna a contagem de usuários do setor central e de N anéis de setores vizinhos, organizada em grid (norte -> sul, oeste -> leste)
+// @Tags sectors
+// @Accept json
+// @Produce json
+// @Param latitude query number true "Latitude do ponto central (-90 a 90)"
+// @Param longitude query number true "Longitude do ponto central (-180 a 180)"
+// @Param rings query int false "Número de anéis ao redor do setor central (padrão: 1, máximo: 10)"
+// @Success 200 {object} usecase.GetSectorsAroundResponse "Grid de setores com contagem de usuários"
+// @Failure 400 {object} map[string]interface{} "Parâmetros de busca inválidos"
+// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Router /sectors/around [get]
+func (h *SectorHandler) GetSectorsAround(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	var req GetSectorsAroundRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error("Invalid query parameters", "error", err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Converter para use case request
+	ucRequest := usecase.GetSectorsAroundRequest{
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		Rings:     req.Rings,
+	}
+
+	// Executar use case
+	response, err := h.getSectorsAroundUC.Execute(c.Request.Context(), ucRequest)
+	if err != nil {
+		log.Error("Failed to get sectors around",
+			"latitude", req.Latitude,
+			"longitude", req.Longitude,
+			"rings", req.Rings,
+			"error", err.Error(),
+		)
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to get sectors around",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	log.Info("Sectors around search completed",
+		"center_sector_id", response.CenterSectorID,
+		"rings", response.Rings,
+		"total_users", response.TotalUsers,
+	)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetOccupiedSectorsRequest representa o retângulo opcional (viewport do mapa)
+type GetOccupiedSectorsRequest struct {
+	MinLatitude  *float64 `form:"min_latitude" binding:"omitempty,min=-90,max=90"`
+	MaxLatitude  *float64 `form:"max_latitude" binding:"omitempty,min=-90,max=90"`
+	MinLongitude *float64 `form:"min_longitude" binding:"omitempty,min=-180,max=180"`
+	MaxLongitude *float64 `form:"max_longitude" binding:"omitempty,min=-180,max=180"`
+}
+
+// GetOccupiedSectors lista os setores com pelo menos um usuário e a contagem de cada um
+// @Summary Setores ocupados (heatmap)
+// @Description Retorna todos os setores com pelo menos um usuário na posição atual, com o centro e a contagem de usuários, do mais ocupado para o menos ocupado. Com o retângulo (os quatro limites), retorna apenas os setores com algum usuário dentro dele, com a contagem do setor inteiro
+// @Tags sectors
+// @Accept json
+// @Produce json
+// @Param min_latitude query number false "Latitude mínima do retângulo (-90 a 90)"
+// @Param max_latitude query number false "Latitude máxima do retângulo (-90 a 90)"
+// @Param min_longitude query number false "Longitude mínima do retângulo (-180 a 180)"
+// @Param max_longitude query number false "Longitude máxima do retângulo (-180 a 180; não pode ser menor que min_longitude)"
+// @Success 200 {object} usecase.GetOccupiedSectorsResponse "Setores ocupados"
+// @Failure 400 {object} map[string]interface{} "Retângulo inválido ou incompleto"
+// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Router /sectors/occupied [get]
+func (h *SectorHandler) GetOccupiedSectors(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	var req GetOccupiedSectorsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error("Invalid query parameters", "error", err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.getOccupiedSectorsUC.Execute(c.Request.Context(), usecase.GetOccupiedSectorsRequest{
+		MinLatitude:  req.MinLatitude,
+		MaxLatitude:  req.MaxLatitude,
+		MinLongitude: req.MinLongitude,
+		MaxLongitude: req.MaxLongitude,
+	})
+	if err != nil {
+		log.Error("Failed to get occupied sectors", "error", err.Error())
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to get occupied sectors",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	log.Info("Occupied sectors retrieved",
+		"total_sectors", response.TotalSectors,
+		"total_users", response.TotalUsers,
+	)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetSectorStatistics retorna estatísticas de ocupação de um setor
+// @Summary Estatísticas do setor
+// @Description Retorna a quantidade de usuários (posição atual), a quantidade de posições registradas e a última atividade de um setor, para heatmaps
+// @Tags sectors
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do setor no formato sector_x_y (ex: sector_-518_-2616)"
+// @Success 200 {object} usecase.GetSectorStatisticsResponse "Estatísticas do setor"
+// @Failure 400 {object} map[string]interface{} "ID do setor inválido"
+// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Router /sectors/{id}/stats [get]
+func (h *SectorHandler) GetSectorStatistics(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	sectorID := c.Param("id")
+
+	response, err := h.getSectorStatsUC.Execute(c.Request.Context(), usecase.GetSectorStatisticsRequest{
+		SectorID: sectorID,
+	})
+	if err != nil {
+		log.Error("Failed to get sector statistics",
+			"sector_id", sectorID,
+			"error", err.Error(),
+		)
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to get sector statistics",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	log.Info("Sector statistics retrieved",
+		"sector_id", response.SectorID,
+		"user_count", response.UserCount,
+	)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetSectorDensity retorna a densidade atual do setor e o tamanho de setor recomendado
+// @Summary Densidade do setor
+// @Description Calcula a densidade atual do setor (usuários com posição atual por km²) e o tamanho de setor recomendado para essa densidade
+// @Tags sectors
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do setor no formato sector_x_y (ex: sector_-518_-2616)"
+// @Success 200 {object} usecase.GetSectorDensityResponse "Densidade e tamanho recomendado"
+// @Failure 400 {object} map[string]interface{} "ID do setor inválido"
+// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Router /sectors/{id}/density [get]
+func (h *SectorHandler) GetSectorDensity(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	sectorID := c.Param("id")
+
+	response, err := h.getSectorDensityUC.Execute(c.Request.Context(), usecase.GetSectorDensityRequest{
+		SectorID: sectorID,
+	})
+	if err != nil {
+		log.Error("Failed to get sector density",
+			"sector_id", sectorID,
+			"error", err.Error(),
+		)
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to get sector density",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	log.Info("Sector density retrieved",
+		"sector_id", response.SectorID,
+		"density_per_km2", response.DensityPerKm2,
+	)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetSectorAnalysis retorna a análise do setor com a contagem de cada vizinho
+// @Summary Análise do setor e vizinhos
+// @Description Retorna a contagem de usuários e a densidade do setor, e a contagem de usuários de cada um dos 8 setores vizinhos (bloco 3x3 consultado de uma vez)
+// @Tags sectors
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do setor no formato sector_x_y (ex: sector_-518_-2616)"
+// @Success 200 {object} usecase.GetSectorAnalysisResponse "Análise do setor e vizinhos"
+// @Failure 400 {object} map[string]interface{} "ID do setor inválido"
+// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Router /sectors/{id}/analysis [get]
+func (h *SectorHandler) GetSectorAnalysis(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	sectorID := c.Param("id")
+
+	response, err := h.getSectorAnalysisUC.Execute(c.Request.Context(), usecase.GetSectorAnalysisRequest{
+		SectorID: sectorID,
+	})
+	if err != nil {
+		log.Error("Failed to analyze sector",
+			"sector_id", sectorID,
+			"error", err.Error(),
+		)
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to analyze sector",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	log.Info("Sector analysis retrieved",
+		"sector_id", response.SectorID,
+		"user_count", response.UserCount,
+	)
+
+	c.JSON(http.StatusOK, response)
+}
@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"mime"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/vitao/geolocation-tracker/internal/usecase"
@@ -12,22 +16,43 @@ import (
 // UserHandler gerencia endpoints relacionados a usuários
 type UserHandler struct {
 	createUserUC         *usecase.CreateUserUseCase
+	listUsersUC          *usecase.ListUsersUseCase
+	getUserByEmailUC     *usecase.GetUserByEmailUseCase
+	deleteUserUC         *usecase.DeleteUserUseCase
 	getCurrentPositionUC *usecase.GetCurrentPositionUseCase
 	getPositionHistoryUC *usecase.GetPositionHistoryUseCase
+	getMovementStatsUC   *usecase.GetUserMovementStatsUseCase
+	exportHistoryUC      *usecase.ExportPositionHistoryUseCase
+	streamHistoryUC      *usecase.StreamPositionHistoryUseCase
+	strictJSON           bool // Recusa chaves desconhecidas nos corpos JSON
 	logger               logger.Logger
 }
 
 // NewUserHandler cria uma nova instância do handler
 func NewUserHandler(
 	createUserUC *usecase.CreateUserUseCase,
+	listUsersUC *usecase.ListUsersUseCase,
+	getUserByEmailUC *usecase.GetUserByEmailUseCase,
+	deleteUserUC *usecase.DeleteUserUseCase,
 	getCurrentPositionUC *usecase.GetCurrentPositionUseCase,
 	getPositionHistoryUC *usecase.GetPositionHistoryUseCase,
+	getMovementStatsUC *usecase.GetUserMovementStatsUseCase,
+	exportHistoryUC *usecase.ExportPositionHistoryUseCase,
+	streamHistoryUC *usecase.StreamPositionHistoryUseCase,
+	strictJSON bool,
 	logger logger.Logger,
 ) *UserHandler {
 	return &UserHandler{
 		createUserUC:         createUserUC,
+		listUsersUC:          listUsersUC,
+		getUserByEmailUC:     getUserByEmailUC,
+		deleteUserUC:         deleteUserUC,
 		getCurrentPositionUC: getCurrentPositionUC,
 		getPositionHistoryUC: getPositionHistoryUC,
+		getMovementStatsUC:   getMovementStatsUC,
+		exportHistoryUC:      exportHistoryUC,
+		streamHistoryUC:      streamHistoryUC,
+		strictJSON:           strictJSON,
 		logger:               logger,
 	}
 }
@@ -40,38 +65,38 @@ func NewUserHandler(
 // @Produce json
 // @Param request body usecase.CreateUserRequest true "Dados do usuário"
 // @Success 201 {object} usecase.CreateUserResponse "Usuário criado com sucesso"
-// @Failure 400 {object} map[string]interface{} "Erro de validação"
+// @Failure 400 {object} ValidationErrorResponse "Erro de validação"
 // @Failure 409 {object} map[string]interface{} "Usuário já existe"
+// @Failure 413 {object} map[string]interface{} "Corpo da requisição acima de HTTP_MAX_BODY_BYTES"
 // @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
 // @Router /users [post]
 func (h *UserHandler) CreateUser(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
 	var req usecase.CreateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Error("Invalid request payload for create user", map[string]interface{}{
+	if err := bindJSON(c, &req, h.strictJSON); err != nil {
+		log.Error("Invalid request payload for create user", map[string]interface{}{
 			"error": err.Error(),
 		})
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request payload",
-			"details": err.Error(),
-		})
+		respondValidationError(c, "Invalid request payload", &req, err)
 		return
 	}
 
 	// Executar use case
 	response, err := h.createUserUC.Execute(c.Request.Context(), req)
 	if err != nil {
-		h.logger.Error("Failed to create user", map[string]interface{}{
+		log.Error("Failed to create user", map[string]interface{}{
 			"user_id": req.ID,
 			"error":   err.Error(),
 		})
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err), gin.H{
 			"error":   "Failed to create user",
 			"details": err.Error(),
 		})
 		return
 	}
 
-	h.logger.Info("User created successfully", map[string]interface{}{
+	log.Info("User created successfully", map[string]interface{}{
 		"user_id": response.UserID,
 		"name":    response.Name,
 	})
@@ -79,6 +104,62 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	c.JSON(http.StatusCreated, response)
 }
 
+// DeleteUser remove um usuário e todas as suas posições
+// @Summary Remover usuário
+// @Description Remove o usuário junto com sua posição atual e histórico, e publica o evento user.deleted
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} usecase.DeleteUserResponse "Usuário removido com sucesso"
+// @Failure 400 {object} map[string]interface{} "ID do usuário inválido"
+// @Failure 404 {object} map[string]interface{} "Usuário não encontrado"
+// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Router /users/{id} [delete]
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	userID := c.Param("id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "user ID is required",
+		})
+		return
+	}
+
+	ucRequest := usecase.DeleteUserRequest{
+		UserID:    userID,
+		RequestID: usecase.RequestIDFromContext(c.Request.Context()),
+	}
+
+	// Executar use case
+	response, err := h.deleteUserUC.Execute(c.Request.Context(), ucRequest)
+	if err != nil {
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "User not found",
+				"details": err.Error(),
+			})
+			return
+		}
+		log.Error("Failed to delete user",
+			"user_id", userID,
+			"error", err.Error(),
+		)
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to delete user",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	log.Info("User deleted successfully",
+		"user_id", response.UserID,
+	)
+
+	c.JSON(http.StatusOK, response)
+}
+
 // GetCurrentPosition retorna a posição atual do usuário
 // @Summary Obter posição atual do usuário
 // @Description Retorna a posição geográfica atual de um usuário específico
@@ -92,6 +173,8 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 // @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
 // @Router /users/{id}/position [get]
 func (h *UserHandler) GetCurrentPosition(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
 	userID := c.Param("id")
 	if userID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -108,18 +191,18 @@ func (h *UserHandler) GetCurrentPosition(c *gin.Context) {
 	// Executar use case
 	response, err := h.getCurrentPositionUC.Execute(c.Request.Context(), ucRequest)
 	if err != nil {
-		h.logger.Error("Failed to get current position",
+		log.Error("Failed to get current position",
 			"user_id", userID,
 			"error", err.Error(),
 		)
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err), gin.H{
 			"error":   "Failed to get current position",
 			"details": err.Error(),
 		})
 		return
 	}
 
-	h.logger.Info("Current position retrieved successfully",
+	log.Info("Current position retrieved successfully",
 		"user_id", userID,
 		"position_id", response.PositionID,
 	)
@@ -127,20 +210,123 @@ func (h *UserHandler) GetCurrentPosition(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// ListUsers retorna uma página de usuários
+// @Summary Listar usuários
+// @Description Retorna uma página de usuários, com total e indicação de mais páginas. Filtros por trecho do nome e domínio do email são combinados, e o total considera os filtros
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param limit query int false "Número máximo de usuários a retornar (padrão: 20, máximo: 100)"
+// @Param offset query int false "Número de usuários a pular, para paginação (padrão: 0)"
+// @Param name query string false "Trecho do nome, sem diferenciar maiúsculas"
+// @Param email_domain query string false "Domínio do email (ex: example.com)"
+// @Param sort query string false "Ordenação: created_at_desc (padrão), created_at_asc ou name"
+// @Success 200 {object} usecase.ListUsersResponse "Página de usuários"
+// @Failure 400 {object} map[string]interface{} "Ordenação ou domínio inválido"
+// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Router /users [get]
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	// Parâmetros inválidos caem nos padrões (limit e offset são normalizados no use case)
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		limit = 0
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	response, err := h.listUsersUC.Execute(c.Request.Context(), usecase.ListUsersRequest{
+		Limit:       limit,
+		Offset:      offset,
+		Name:        c.Query("name"),
+		EmailDomain: c.Query("email_domain"),
+		Sort:        c.Query("sort"),
+	})
+	if err != nil {
+		log.Error("Failed to list users",
+			"limit", limit,
+			"offset", offset,
+			"sort", c.Query("sort"),
+			"error", err.Error(),
+		)
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to list users",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetUserByEmail busca um usuário pelo email
+// @Summary Buscar usuário por email
+// @Description Retorna o usuário cadastrado com o email informado, para clientes que não conhecem o ID
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param email query string true "Email do usuário"
+// @Success 200 {object} usecase.GetUserByEmailResponse "Usuário encontrado"
+// @Failure 400 {object} map[string]interface{} "Email inválido"
+// @Failure 404 {object} map[string]interface{} "Usuário não encontrado"
+// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Router /users/by-email [get]
+func (h *UserHandler) GetUserByEmail(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	email := c.Query("email")
+
+	response, err := h.getUserByEmailUC.Execute(c.Request.Context(), usecase.GetUserByEmailRequest{
+		Email: email,
+	})
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid email",
+				"details": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "User not found",
+				"details": err.Error(),
+			})
+			return
+		}
+		log.Error("Failed to get user by email",
+			"error", err.Error(),
+		)
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to get user",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // GetPositionHistory retorna o histórico de posições do usuário
 // @Summary Obter histórico de posições do usuário
-// @Description Retorna o histórico de posições geográficas de um usuário com limite configurável
+// @Description Retorna uma página do histórico de posições geográficas de um usuário, com total e indicação de mais páginas
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id path string true "ID do usuário"
 // @Param limit query int false "Número máximo de posições a retornar (padrão: 10, máximo: 100)"
+// @Param offset query int false "Número de posições a pular, para paginação (padrão: 0)"
 // @Success 200 {object} usecase.GetPositionHistoryResponse "Histórico de posições do usuário"
 // @Failure 400 {object} map[string]interface{} "ID do usuário inválido"
 // @Failure 404 {object} map[string]interface{} "Usuário não encontrado"
 // @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
 // @Router /users/{id}/positions/history [get]
 func (h *UserHandler) GetPositionHistory(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
 	userID := c.Param("id")
 	if userID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -159,32 +345,276 @@ func (h *UserHandler) GetPositionHistory(c *gin.Context) {
 		limit = 100 // Máximo permitido
 	}
 
+	// Parse do parâmetro offset
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
 	// Converter para use case request
 	ucRequest := usecase.GetPositionHistoryRequest{
 		UserID: userID,
 		Limit:  limit,
+		Offset: offset,
 	}
 
 	// Executar use case
 	response, err := h.getPositionHistoryUC.Execute(c.Request.Context(), ucRequest)
 	if err != nil {
-		h.logger.Error("Failed to get position history",
+		log.Error("Failed to get position history",
 			"user_id", userID,
 			"limit", limit,
 			"error", err.Error(),
 		)
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err), gin.H{
 			"error":   "Failed to get position history",
 			"details": err.Error(),
 		})
 		return
 	}
 
-	h.logger.Info("Position history retrieved successfully",
+	log.Info("Position history retrieved successfully",
 		"user_id", userID,
 		"total", response.Total,
 		"limit", limit,
+		"offset", offset,
+	)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetMovementStats retorna estatísticas de deslocamento do usuário em um intervalo
+// @Summary Obter estatísticas de deslocamento do usuário
+// @Description Retorna distância percorrida, velocidade média, trocas de setor e setor mais visitado no intervalo
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Param from query string false "Início do intervalo em RFC3339 (padrão: to - 24h)"
+// @Param to query string false "Fim do intervalo em RFC3339 (padrão: agora)"
+// @Success 200 {object} usecase.GetUserMovementStatsResponse "Estatísticas de deslocamento"
+// @Failure 400 {object} map[string]interface{} "Intervalo inválido"
+// @Failure 404 {object} map[string]interface{} "Usuário não encontrado"
+// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Router /users/{id}/movement-stats [get]
+func (h *UserHandler) GetMovementStats(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	userID := c.Param("id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "user ID is required",
+		})
+		return
+	}
+
+	ucRequest := usecase.GetUserMovementStatsRequest{UserID: userID}
+	for param, target := range map[string]*time.Time{"from": &ucRequest.From, "to": &ucRequest.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid " + param + " parameter, expected RFC3339",
+				"details": err.Error(),
+			})
+			return
+		}
+		*target = parsed
+	}
+
+	// Executar use case
+	response, err := h.getMovementStatsUC.Execute(c.Request.Context(), ucRequest)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidTimeRange) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid time range",
+				"details": err.Error(),
+			})
+			return
+		}
+		log.Error("Failed to get movement stats",
+			"user_id", userID,
+			"error", err.Error(),
+		)
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to get movement stats",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	log.Info("Movement stats retrieved successfully",
+		"user_id", userID,
+		"positions", response.PositionCount,
 	)
 
 	c.JSON(http.StatusOK, response)
 }
+
+// ExportPositionHistory exporta o histórico completo de posições do usuário
+// @Summary Exportar histórico de posições do usuário
+// @Description Faz o download de todo o histórico do usuário (sem o limite de 100 da paginação), da posição mais antiga para a mais recente. O histórico é lido do banco em chunks e escrito em streaming. O CSV tem as colunas position_id, latitude, longitude, sector_id, recorded_at
+// @Tags users
+// @Produce json
+// @Produce text/csv
+// @Param id path string true "ID do usuário"
+// @Param format query string false "Formato do arquivo: json (padrão) ou csv"
+// @Success 200 {array} usecase.PositionExportItem "Histórico completo (anexo)"
+// @Failure 400 {object} map[string]interface{} "ID do usuário ou formato inválido"
+// @Failure 404 {object} map[string]interface{} "Usuário não encontrado"
+// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Router /users/{id}/positions/export [get]
+func (h *UserHandler) ExportPositionHistory(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	userID := c.Param("id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "user ID is required",
+		})
+		return
+	}
+
+	format, err := usecase.NormalizeExportFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid format parameter",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	writer := &exportWriter{
+		c:           c,
+		contentType: exportContentTypes[format],
+		filename:    fmt.Sprintf("positions-%s.%s", userID, format),
+	}
+
+	// Executar use case (escreve direto na resposta)
+	response, err := h.exportHistoryUC.Execute(c.Request.Context(), usecase.ExportPositionHistoryRequest{
+		UserID: userID,
+		Format: format,
+	}, writer)
+	if err != nil {
+		if writer.started {
+			// Status e headers já foram enviados: só resta interromper o download
+			log.Error("Position history export interrupted",
+				"user_id", userID,
+				"format", format,
+				"error", err.Error(),
+			)
+			c.Abort()
+			return
+		}
+		log.Error("Failed to export position history",
+			"user_id", userID,
+			"format", format,
+			"error", err.Error(),
+		)
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to export position history",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	log.Info("Position history exported successfully",
+		"user_id", userID,
+		"format", format,
+		"exported", response.Exported,
+	)
+}
+
+// StreamPositionHistory retorna as posições gravadas após um cursor (long-poll)
+// @Summary Acompanhar o histórico de posições do usuário
+// @Description Retorna as posições gravadas após a posição since, da mais antiga para a mais recente. Use next_cursor como since da próxima chamada; com wait_seconds a resposta aguarda posições novas antes de retornar vazia (sem since, começa do início do histórico)
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Param since query string false "position_id da última posição já recebida"
+// @Param limit query int false "Número máximo de posições a retornar (padrão: 50, máximo: 100)"
+// @Param wait_seconds query int false "Segundos para aguardar posições novas quando não há nenhuma (padrão: 0, máximo: 30; limitado ao timeout da requisição)"
+// @Success 200 {object} usecase.StreamPositionHistoryResponse "Posições após o cursor e o próximo cursor"
+// @Failure 400 {object} map[string]interface{} "ID do usuário ou cursor inválido"
+// @Failure 404 {object} map[string]interface{} "Usuário ou posição do cursor não encontrados"
+// @Failure 500 {object} map[string]interface{} "Erro interno do servidor"
+// @Router /users/{id}/positions/stream [get]
+func (h *UserHandler) StreamPositionHistory(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	userID := c.Param("id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "user ID is required",
+		})
+		return
+	}
+
+	// Parâmetros inválidos caem nos padrões (limit e wait_seconds são normalizados no use case)
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		limit = 0
+	}
+	waitSeconds, err := strconv.Atoi(c.DefaultQuery("wait_seconds", "0"))
+	if err != nil {
+		waitSeconds = 0
+	}
+
+	// Executar use case
+	response, err := h.streamHistoryUC.Execute(c.Request.Context(), usecase.StreamPositionHistoryRequest{
+		UserID:      userID,
+		Since:       c.Query("since"),
+		Limit:       limit,
+		WaitSeconds: waitSeconds,
+	})
+	if err != nil {
+		log.Error("Failed to stream position history",
+			"user_id", userID,
+			"since", c.Query("since"),
+			"error", err.Error(),
+		)
+		c.JSON(errorStatus(err), gin.H{
+			"error":   "Failed to stream position history",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// exportContentTypes mapeia o formato de exportação para o Content-Type da resposta
+var exportContentTypes = map[string]string{
+	usecase.ExportFormatJSON: "application/json; charset=utf-8",
+	usecase.ExportFormatCSV:  "text/csv; charset=utf-8",
+}
+
+// exportChunkWriteTimeout é o prazo de escrita concedido a cada chunk da exportação,
+// renovado a cada escrita para que o WriteTimeout do servidor não corte downloads longos
+const exportChunkWriteTimeout = 15 * time.Second
+
+// exportWriter adia status e headers do download até o primeiro byte,
+// para que erros de validação ainda possam ser respondidos em JSON
+type exportWriter struct {
+	c           *gin.Context
+	contentType string
+	filename    string
+	started     bool
+}
+
+// Write envia os headers do anexo na primeira escrita e repassa os bytes para a resposta
+func (w *exportWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.c.Header("Content-Type", w.contentType)
+		w.c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": w.filename}))
+		w.c.Status(http.StatusOK)
+	}
+	// Sem suporte a deadline (ex.: writers de teste) a escrita segue sem prazo próprio
+	_ = http.NewResponseController(w.c.Writer).SetWriteDeadline(time.Now().Add(exportChunkWriteTimeout))
+	return w.c.Writer.Write(p)
+}
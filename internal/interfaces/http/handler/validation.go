@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Códigos de erro de validação
+const (
+	ValidationCodeRequired     = "REQUIRED"
+	ValidationCodeOutOfRange   = "OUT_OF_RANGE"
+	ValidationCodeInvalidEmail = "INVALID_EMAIL"
+	ValidationCodeInvalidType  = "INVALID_TYPE"
+	ValidationCodeInvalidJSON  = "INVALID_JSON"
+	ValidationCodeBodyTooLarge = "BODY_TOO_LARGE"
+	ValidationCodeUnknownField = "UNKNOWN_FIELD"
+	ValidationCodeInvalid      = "INVALID"
+)
+
+// ValidationError descreve a falha de validação de um campo
+type ValidationError struct {
+	Code    string `json:"code" example:"REQUIRED"`
+	Field   string `json:"field,omitempty" example:"latitude"` // Nome do campo no JSON ou na query
+	Message string `json:"message" example:"latitude is required"`
+}
+
+// ValidationErrorResponse é a resposta 400 com os erros por campo
+type ValidationErrorResponse struct {
+	Error  string            `json:"error" example:"Invalid request payload"`
+	Errors []ValidationError `json:"errors"`
+}
+
+// UnknownFieldError indica uma chave do JSON sem campo correspondente na requisição (modo estrito)
+type UnknownFieldError struct {
+	Field string
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("unknown field %q", e.Field)
+}
+
+// bindJSON faz o binding e a validação do corpo JSON em obj
+// Com strict, chaves que não existem em obj são recusadas em vez de ignoradas
+func bindJSON(c *gin.Context, obj interface{}, strict bool) error {
+	if !strict {
+		return c.ShouldBindJSON(obj)
+	}
+	return c.ShouldBindWith(obj, strictJSONBinding{})
+}
+
+// strictJSONBinding é o binding JSON do Gin com DisallowUnknownFields
+type strictJSONBinding struct{}
+
+func (strictJSONBinding) Name() string {
+	return "json"
+}
+
+func (strictJSONBinding) Bind(req *http.Request, obj interface{}) error {
+	if req == nil || req.Body == nil {
+		return errors.New("invalid request")
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		// encoding/json não exporta o erro de campo desconhecido, apenas a mensagem
+		if quoted, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			if field, unquoteErr := strconv.Unquote(quoted); unquoteErr == nil {
+				return &UnknownFieldError{Field: field}
+			}
+		}
+		return err
+	}
+
+	if binding.Validator == nil {
+		return nil
+	}
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// respondValidationError responde 400 com os erros de binding de req convertidos por campo
+// (413 quando o corpo passou do limite do middleware BodyLimit)
+func respondValidationError(c *gin.Context, message string, req interface{}, err error) {
+	c.JSON(bindErrorStatus(err), ValidationErrorResponse{
+		Error:  message,
+		Errors: validationErrors(req, err),
+	})
+}
+
+// respondMissingField responde 400 para um campo obrigatório lido fora do binding (ex: c.Query)
+func respondMissingField(c *gin.Context, field string) {
+	c.JSON(http.StatusBadRequest, ValidationErrorResponse{
+		Error: "Invalid query parameters",
+		Errors: []ValidationError{{
+			Code:    ValidationCodeRequired,
+			Field:   field,
+			Message: fmt.Sprintf("%s is required", field),
+		}},
+	})
+}
+
+// bindErrorStatus retorna o status de um erro de binding: 413 para corpo acima do limite, 400 nos demais
+func bindErrorStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// validationErrors converte erros de ShouldBindJSON/ShouldBindQuery em erros por campo
+// Os nomes dos campos vêm das tags json/form de req
+func validationErrors(req interface{}, err error) []ValidationError {
+	var fieldErrors validator.ValidationErrors
+	if errors.As(err, &fieldErrors) {
+		result := make([]ValidationError, 0, len(fieldErrors))
+		for _, fieldErr := range fieldErrors {
+			result = append(result, fromFieldError(req, fieldErr))
+		}
+		return result
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []ValidationError{{
+			Code:    ValidationCodeInvalidType,
+			Field:   typeErr.Field,
+			Message: fmt.Sprintf("%s must be of type %s", typeErr.Field, typeErr.Type.String()),
+		}}
+	}
+
+	var unknownErr *UnknownFieldError
+	if errors.As(err, &unknownErr) {
+		return []ValidationError{{
+			Code:    ValidationCodeUnknownField,
+			Field:   unknownErr.Field,
+			Message: fmt.Sprintf("%s is not a known field", unknownErr.Field),
+		}}
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return []ValidationError{{
+			Code:    ValidationCodeBodyTooLarge,
+			Message: fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit),
+		}}
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return []ValidationError{{
+			Code:    ValidationCodeInvalidJSON,
+			Message: "request body is not valid JSON",
+		}}
+	}
+
+	if errors.Is(err, io.EOF) {
+		return []ValidationError{{
+			Code:    ValidationCodeInvalidJSON,
+			Message: "request body is empty",
+		}}
+	}
+
+	// Query com número ou booleano mal formado (o binding de form não informa o campo)
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		return []ValidationError{{
+			Code:    ValidationCodeInvalidType,
+			Message: fmt.Sprintf("invalid value %q", numErr.Num),
+		}}
+	}
+
+	return []ValidationError{{
+		Code:    ValidationCodeInvalid,
+		Message: err.Error(),
+	}}
+}
+
+// fromFieldError traduz a regra violada em código e mensagem
+func fromFieldError(req interface{}, fieldErr validator.FieldError) ValidationError {
+	field := fieldName(req, fieldErr)
+
+	switch fieldErr.Tag() {
+	case "required":
+		return ValidationError{Code: ValidationCodeRequired, Field: field, Message: fmt.Sprintf("%s is required", field)}
+	case "min":
+		return ValidationError{Code: ValidationCodeOutOfRange, Field: field, Message: fmt.Sprintf("%s must be at least %s", field, fieldErr.Param())}
+	case "max":
+		return ValidationError{Code: ValidationCodeOutOfRange, Field: field, Message: fmt.Sprintf("%s must be at most %s", field, fieldErr.Param())}
+	case "email":
+		return ValidationError{Code: ValidationCodeInvalidEmail, Field: field, Message: fmt.Sprintf("%s must be a valid email", field)}
+	default:
+		return ValidationError{Code: ValidationCodeInvalid, Field: field, Message: fmt.Sprintf("%s failed %s validation", field, fieldErr.Tag())}
+	}
+}
+
+// fieldName retorna o nome do campo como o cliente o envia (tag json ou form)
+func fieldName(req interface{}, fieldErr validator.FieldError) string {
+	t := reflect.TypeOf(req)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fieldErr.Field()
+	}
+
+	structField, ok := t.FieldByName(fieldErr.StructField())
+	if !ok {
+		return fieldErr.Field()
+	}
+	for _, tag := range []string{"json", "form"} {
+		if name := strings.Split(structField.Tag.Get(tag), ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return fieldErr.Field()
+}
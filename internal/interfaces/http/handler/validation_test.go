@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newJSONContext cria um contexto do Gin com o corpo JSON informado
+func newJSONContext(body string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return c
+}
+
+// TestBindJSON_UnknownFieldOnlyRejectedWhenStrict testa que chaves extras só falham no modo estrito
+func TestBindJSON_UnknownFieldOnlyRejectedWhenStrict(t *testing.T) {
+	body := `{"user_id": "user123", "latitude": -23.5, "longitude": -46.6, "device": {"battery": 80}}`
+
+	var lenient SavePositionRequest
+	require.NoError(t, bindJSON(newJSONContext(body), &lenient, false))
+	assert.Equal(t, "user123", lenient.UserID)
+
+	var strict SavePositionRequest
+	err := bindJSON(newJSONContext(body), &strict, true)
+
+	var unknownErr *UnknownFieldError
+	require.ErrorAs(t, err, &unknownErr)
+	assert.Equal(t, "device", unknownErr.Field)
+	assert.Equal(t, []ValidationError{{
+		Code:    ValidationCodeUnknownField,
+		Field:   "device",
+		Message: "device is not a known field",
+	}}, validationErrors(&strict, err))
+}
+
+// TestBindJSON_StrictKeepsValidation testa que o modo estrito continua aplicando as tags binding
+func TestBindJSON_StrictKeepsValidation(t *testing.T) {
+	var req SavePositionRequest
+	err := bindJSON(newJSONContext(`{"latitude": -23.5, "longitude": -46.6}`), &req, true)
+
+	errs := validationErrors(&req, err)
+	require.Len(t, errs, 1)
+	assert.Equal(t, ValidationCodeRequired, errs[0].Code)
+	assert.Equal(t, "user_id", errs[0].Field)
+
+	err = bindJSON(newJSONContext(`{"user_id": }`), &req, true)
+	assert.Equal(t, ValidationCodeInvalidJSON, validationErrors(&req, err)[0].Code)
+}
+
+// TestCreateUser_StrictJSONRejectsUnknownField testa o 400 com o campo desconhecido na resposta
+func TestCreateUser_StrictJSONRejectsUnknownField(t *testing.T) {
+	h := &UserHandler{strictJSON: true, logger: nopLogger{}}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/users", h.CreateUser)
+
+	body := `{"id": "user123", "name": "João Silva", "email": "joao@example.com", "event_id": "evt-1", "role": "admin"}`
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var response ValidationErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, ValidationCodeUnknownField, response.Errors[0].Code)
+	assert.Equal(t, "role", response.Errors[0].Field)
+}
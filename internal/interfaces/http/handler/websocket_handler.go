@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/internal/infrastructure/realtime"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// WebSocketHandler gerencia as inscrições WebSocket de atualizações em tempo real
+type WebSocketHandler struct {
+	hub      *realtime.Hub
+	upgrader websocket.Upgrader
+	logger   logger.Logger
+}
+
+// NewWebSocketHandler cria uma nova instância do handler de WebSocket
+// allowOrigin é a mesma política de origens do middleware CORS
+func NewWebSocketHandler(hub *realtime.Hub, allowOrigin func(origin string) bool, logger logger.Logger) *WebSocketHandler {
+	return &WebSocketHandler{
+		hub: hub,
+		upgrader: websocket.Upgrader{
+			HandshakeTimeout: 10 * time.Second,
+			CheckOrigin: func(r *http.Request) bool {
+				// Clientes fora do navegador não enviam Origin
+				origin := r.Header.Get("Origin")
+				return origin == "" || allowOrigin(origin)
+			},
+		},
+		logger: logger,
+	}
+}
+
+// SubscribePositions inscreve o cliente nas atualizações de posição
+// @Summary Atualizações de posição em tempo real
+// @Description Abre um WebSocket que recebe eventos position.changed. Com sector, recebe apenas as mudanças que entram ou saem do setor
+// @Tags realtime
+// @Param sector query string false "ID do setor no formato sector_x_y (ex: sector_-518_-2616)"
+// @Success 101 "Conexão WebSocket estabelecida"
+// @Failure 400 {object} map[string]interface{} "Setor inválido"
+// @Router /ws/positions [get]
+func (h *WebSocketHandler) SubscribePositions(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context(), h.logger)
+
+	sectorID := c.Query("sector")
+	if sectorID != "" {
+		if _, err := valueobject.ParseSectorID(sectorID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid sector",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade já respondeu ao cliente com o erro HTTP
+		log.Warn("Failed to upgrade WebSocket connection",
+			"remote_addr", c.Request.RemoteAddr,
+			"error", err,
+		)
+		return
+	}
+
+	h.hub.Subscribe(conn, sectorID)
+}
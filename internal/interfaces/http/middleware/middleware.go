@@ -2,11 +2,20 @@ package middleware
 
 import (
 	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/vitao/geolocation-tracker/pkg/logger"
+	"github.com/vitao/geolocation-tracker/pkg/metrics"
 )
 
 // RequestLogger middleware para logging estruturado de requisições
@@ -19,21 +28,88 @@ func RequestLogger(logger logger.Logger) gin.HandlerFunc {
 			"latency", param.Latency,
 			"client_ip", param.ClientIP,
 			"user_agent", param.Request.UserAgent(),
+			"request_id", param.Keys["request_id"],
 		)
 		return ""
 	})
 }
 
+// Metrics middleware que registra contagem e latência das requisições por rota
+// Usa o template da rota (ex.: /api/v1/users/:id) para não explodir a cardinalidade
+func Metrics(collector metrics.Collector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		collector.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
+
+// CORSPolicy define as origens que podem chamar a API a partir do navegador
+// Com "*" qualquer origem é aceita, mas sem credenciais (o navegador rejeita "*" com credenciais)
+type CORSPolicy struct {
+	anyOrigin bool
+	origins   map[string]struct{}
+}
+
+// NewCORSPolicy cria a política a partir da lista de origens (ex.: "https://app.example.com")
+func NewCORSPolicy(allowedOrigins []string) *CORSPolicy {
+	policy := &CORSPolicy{origins: make(map[string]struct{}, len(allowedOrigins))}
+	for _, origin := range allowedOrigins {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		switch origin {
+		case "":
+		case "*":
+			policy.anyOrigin = true
+		default:
+			policy.origins[strings.ToLower(origin)] = struct{}{}
+		}
+	}
+	return policy
+}
+
+// AllowsOrigin verifica se a origem pode acessar a API
+func (p *CORSPolicy) AllowsOrigin(origin string) bool {
+	if p.anyOrigin {
+		return true
+	}
+	_, ok := p.origins[strings.ToLower(origin)]
+	return ok
+}
+
 // CORS middleware para configurar headers CORS
-func CORS() gin.HandlerFunc {
+// Origens da lista recebem a própria origem de volta e credenciais; com "*" a resposta usa
+// o curinga sem credenciais. Origens fora da lista não recebem headers CORS e têm o preflight negado
+func CORS(policy *CORSPolicy) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, PATCH, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
-		c.Header("Access-Control-Expose-Headers", "Content-Length")
-		c.Header("Access-Control-Allow-Credentials", "true")
+		origin := c.GetHeader("Origin")
+		if !policy.anyOrigin {
+			// A resposta depende da origem: caches não podem reaproveitá-la entre origens
+			c.Writer.Header().Add("Vary", "Origin")
+		}
+
+		allowed := origin != "" && policy.AllowsOrigin(origin)
+		if allowed {
+			if policy.anyOrigin {
+				c.Header("Access-Control-Allow-Origin", "*")
+			} else {
+				c.Header("Access-Control-Allow-Origin", origin)
+				c.Header("Access-Control-Allow-Credentials", "true")
+			}
+			c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, PATCH, OPTIONS")
+			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+			c.Header("Access-Control-Expose-Headers", "Content-Length")
+		}
 
-		if c.Request.Method == "OPTIONS" {
+		if c.Request.Method == http.MethodOptions {
+			if origin != "" && !allowed {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
 			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
@@ -42,11 +118,167 @@ func CORS() gin.HandlerFunc {
 	}
 }
 
-// RateLimiter middleware básico para rate limiting (placeholder)
-func RateLimiter() gin.HandlerFunc {
+// BodyLimit middleware que limita o corpo de POST, PUT e PATCH a maxBytes (0 desabilita)
+// Um Content-Length acima do limite é respondido com 413 antes do handler; corpos sem tamanho
+// declarado são cortados por http.MaxBytesReader e o erro chega ao binding do handler
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// TODO: Implementar rate limiting com Redis
-		// Por enquanto, apenas pass-through
+		if maxBytes <= 0 {
+			c.Next()
+			return
+		}
+
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > maxBytes {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error": fmt.Sprintf("Request body too large, limit is %d bytes", maxBytes),
+				"code":  "BODY_TOO_LARGE",
+			})
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		c.Next()
+	}
+}
+
+// RateLimitStore registra requisições em uma janela deslizante compartilhada (ex.: Redis)
+type RateLimitStore interface {
+	AllowRequest(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error)
+}
+
+// RateLimit define quantas requisições cada cliente pode fazer por janela
+// Requests <= 0 desabilita o limite
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// ParseRateLimit converte "requisições/segundos" (ex.: "100/60") em RateLimit
+func ParseRateLimit(value string) (RateLimit, error) {
+	requests, seconds, found := strings.Cut(strings.TrimSpace(value), "/")
+	if !found {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: expected requests/seconds", value)
+	}
+
+	limit, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil {
+		return RateLimit{}, fmt.Errorf("invalid rate limit requests %q: %w", requests, err)
+	}
+	windowSeconds, err := strconv.Atoi(strings.TrimSpace(seconds))
+	if err != nil || windowSeconds <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit window %q: must be a positive number of seconds", seconds)
+	}
+
+	return RateLimit{Requests: limit, Window: time.Duration(windowSeconds) * time.Second}, nil
+}
+
+// rateLimitClientKey identifica o cliente pelo user_id da query ou, na ausência, pelo IP
+func rateLimitClientKey(c *gin.Context) string {
+	if userID := c.Query("user_id"); userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// RateLimiter middleware de rate limiting por cliente com janela deslizante
+// Cada grupo de rotas tem contadores próprios; falhas do store liberam a requisição
+func RateLimiter(store RateLimitStore, group string, limit RateLimit, logger logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if store == nil || limit.Requests <= 0 || limit.Window <= 0 {
+			c.Next()
+			return
+		}
+
+		client := rateLimitClientKey(c)
+		allowed, retryAfter, err := store.AllowRequest(c.Request.Context(), group+":"+client, limit.Requests, limit.Window)
+		if err != nil {
+			logger.Warn("Rate limiter unavailable, allowing request",
+				"group", group,
+				"client", client,
+				"error", err.Error(),
+			)
+			c.Next()
+			return
+		}
+
+		if !allowed {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+
+			logger.Warn("Rate limit exceeded",
+				"group", group,
+				"client", client,
+				"limit", limit.Requests,
+				"window", limit.Window.String(),
+			)
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":   "Rate limit exceeded",
+				"details": fmt.Sprintf("limit of %d requests per %s exceeded, retry in %ds", limit.Requests, limit.Window, seconds),
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AdminTokenHeader é o header usado para autenticar endpoints administrativos
+const AdminTokenHeader = "X-Admin-Token"
+
+// AdminAuth middleware que exige o token de admin no header X-Admin-Token
+// Sem token configurado, os endpoints de admin ficam desabilitados
+func AdminAuth(token string, logger logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "Admin endpoints are disabled",
+			})
+			return
+		}
+
+		provided := c.GetHeader(AdminTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			logger.Warn("Unauthorized admin request",
+				"path", c.Request.URL.Path,
+				"client_ip", c.ClientIP(),
+			)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid or missing admin token",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequestIDHeader é o header de correlação das requisições
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID middleware que propaga o X-Request-ID (gerando um UUID quando ausente)
+// O ID é devolvido no header da resposta e colocado no context da requisição
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+		// Handlers e use cases derivam o logger com logger.FromContext a partir deste context
+		c.Request = c.Request.WithContext(logger.ContextWithRequestID(c.Request.Context(), requestID))
+
 		c.Next()
 	}
 }
@@ -63,17 +295,103 @@ func SecurityHeaders() gin.HandlerFunc {
 	}
 }
 
+// timeoutWriter descarta o que o handler escrever depois que o timeout já respondeu
+// O handler recebe um mapa de headers próprio, copiado para o writer original só ao enviar a resposta,
+// para que a goroutine do handler e a resposta de timeout nunca escrevam no mesmo http.Header
+type timeoutWriter struct {
+	gin.ResponseWriter
+	mu       sync.Mutex
+	header   http.Header
+	timedOut bool
+}
+
+func newTimeoutWriter(w gin.ResponseWriter) *timeoutWriter {
+	return &timeoutWriter{ResponseWriter: w, header: w.Header().Clone()}
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+// flushHeaderLocked substitui os headers do writer original pelos do handler enquanto nada foi enviado; exige w.mu
+func (w *timeoutWriter) flushHeaderLocked() {
+	if w.ResponseWriter.Written() {
+		return
+	}
+
+	dst := w.ResponseWriter.Header()
+	for key := range dst {
+		delete(dst, key)
+	}
+	for key, values := range w.header {
+		dst[key] = values
+	}
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.timedOut {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.timedOut {
+		w.flushHeaderLocked()
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	w.flushHeaderLocked()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timeoutWriter) WriteString(data string) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	w.flushHeaderLocked()
+	return w.ResponseWriter.WriteString(data)
+}
+
+func (w *timeoutWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.timedOut {
+		w.flushHeaderLocked()
+		w.ResponseWriter.Flush()
+	}
+}
+
 // Timeout middleware para timeout de requisições
+// Ao estourar o prazo responde 408 e aguarda o handler encerrar (o context é cancelado),
+// descartando o que ele escrever depois; o gin.Context não pode ser reciclado antes disso
 func Timeout(timeout time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx := c.Request.Context()
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
 
 		// Criar contexto com timeout
-		ctx, cancel := context.WithTimeout(ctx, timeout)
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
 		defer cancel()
 
-		// Substituir contexto da requisição
+		// Substituir contexto da requisição e o writer
 		c.Request = c.Request.WithContext(ctx)
+		writer := newTimeoutWriter(c.Writer)
+		c.Writer = writer
 
 		// Channel para verificar se a requisição completou
 		done := make(chan struct{})
@@ -85,15 +403,27 @@ func Timeout(timeout time.Duration) gin.HandlerFunc {
 
 		select {
 		case <-done:
-			// Requisição completou normalmente
+			// Requisição completou normalmente; repassar headers de respostas sem corpo (o gin só envia o status depois)
+			writer.mu.Lock()
+			writer.flushHeaderLocked()
+			writer.mu.Unlock()
 			return
 		case <-ctx.Done():
-			// Timeout ocorreu
-			c.JSON(http.StatusRequestTimeout, gin.H{
-				"error": "Request timeout",
-				"code":  "TIMEOUT",
-			})
-			c.Abort()
+			// Timeout ocorreu: responder direto no writer original, sem tocar no gin.Context
+			writer.mu.Lock()
+			if !writer.ResponseWriter.Written() {
+				body, _ := json.Marshal(gin.H{
+					"error": "Request timeout",
+					"code":  "TIMEOUT",
+				})
+				writer.ResponseWriter.Header().Set("Content-Type", "application/json; charset=utf-8")
+				writer.ResponseWriter.WriteHeader(http.StatusRequestTimeout)
+				_, _ = writer.ResponseWriter.Write(body)
+			}
+			writer.timedOut = true
+			writer.mu.Unlock()
+
+			<-done
 		}
 	}
 }
@@ -103,8 +433,8 @@ func ErrorHandler(logger logger.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
 
-		// Verificar se houve erro
-		if len(c.Errors) > 0 {
+		// Verificar se houve erro (sem sobrescrever uma resposta já enviada pelo handler)
+		if len(c.Errors) > 0 && !c.Writer.Written() {
 			err := c.Errors.Last()
 
 			logger.Error("Request error",
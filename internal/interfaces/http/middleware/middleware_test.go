@@ -0,0 +1,334 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+)
+
+// newRequestIDRouter cria um router que devolve o ID de correlação visto pelo handler
+func newRequestIDRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/ping", func(c *gin.Context) {
+		c.String(http.StatusOK, usecase.RequestIDFromContext(c.Request.Context()))
+	})
+	return router
+}
+
+// TestRequestID_PropagatesHeader testa que o X-Request-ID recebido é mantido
+func TestRequestID_PropagatesHeader(t *testing.T) {
+	router := newRequestIDRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(RequestIDHeader, "req-123")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, "req-123", rec.Body.String())
+	assert.Equal(t, "req-123", rec.Header().Get(RequestIDHeader))
+}
+
+// TestRequestID_GeneratesWhenMissing testa a geração de UUID quando o header está ausente
+func TestRequestID_GeneratesWhenMissing(t *testing.T) {
+	router := newRequestIDRouter()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	requestID := rec.Header().Get(RequestIDHeader)
+	_, err := uuid.Parse(requestID)
+	assert.NoError(t, err)
+	assert.Equal(t, requestID, rec.Body.String())
+}
+
+// nopLogger descarta todos os logs durante os testes
+type nopLogger struct{}
+
+func (nopLogger) Info(msg string, fields ...interface{})  {}
+func (nopLogger) Error(msg string, fields ...interface{}) {}
+func (nopLogger) Warn(msg string, fields ...interface{})  {}
+func (nopLogger) Fatal(msg string, fields ...interface{}) {}
+func (nopLogger) Debug(msg string, fields ...interface{}) {}
+func (nopLogger) Sync() error                             { return nil }
+
+// fakeRateLimitStore conta requisições por chave em memória (sem janela real)
+type fakeRateLimitStore struct {
+	counts     map[string]int
+	retryAfter time.Duration
+	err        error
+}
+
+func (s *fakeRateLimitStore) AllowRequest(_ context.Context, key string, limit int, _ time.Duration) (bool, time.Duration, error) {
+	if s.err != nil {
+		return false, 0, s.err
+	}
+	if s.counts[key] >= limit {
+		return false, s.retryAfter, nil
+	}
+	s.counts[key]++
+	return true, 0, nil
+}
+
+// newRateLimitedRouter cria um router com limite de 2 requisições por minuto
+func newRateLimitedRouter(store RateLimitStore) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RateLimiter(store, "test", RateLimit{Requests: 2, Window: time.Minute}, nopLogger{}))
+	router.GET("/ping", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+// TestRateLimiter_RejectsAfterLimit testa o 429 com Retry-After após exceder o limite
+func TestRateLimiter_RejectsAfterLimit(t *testing.T) {
+	store := &fakeRateLimitStore{counts: map[string]int{}, retryAfter: 1500 * time.Millisecond}
+	router := newRateLimitedRouter(store)
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+}
+
+// TestRateLimiter_KeysByUserID testa que clientes com user_id distintos têm contadores próprios
+func TestRateLimiter_KeysByUserID(t *testing.T) {
+	store := &fakeRateLimitStore{counts: map[string]int{}}
+	router := newRateLimitedRouter(store)
+
+	for _, target := range []string{"/ping?user_id=a", "/ping?user_id=a", "/ping?user_id=b"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	assert.Equal(t, 2, store.counts["test:user:a"])
+	assert.Equal(t, 1, store.counts["test:user:b"])
+}
+
+// TestRateLimiter_FailsOpen testa que uma falha no store não bloqueia a requisição
+func TestRateLimiter_FailsOpen(t *testing.T) {
+	router := newRateLimitedRouter(&fakeRateLimitStore{err: errors.New("redis down")})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+// TestParseRateLimit testa o formato "requisições/segundos"
+func TestParseRateLimit(t *testing.T) {
+	limit, err := ParseRateLimit("100/60")
+	assert.NoError(t, err)
+	assert.Equal(t, RateLimit{Requests: 100, Window: time.Minute}, limit)
+
+	for _, invalid := range []string{"100", "abc/60", "100/0", "100/x"} {
+		_, err := ParseRateLimit(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+// newTimeoutRouter cria um router com timeout de 20ms e um handler que ignora o context e espera `delay`
+func newTimeoutRouter(delay time.Duration) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Timeout(20 * time.Millisecond))
+	router.GET("/slow", func(c *gin.Context) {
+		time.Sleep(delay)
+		c.JSON(http.StatusOK, gin.H{"status": "done"})
+	})
+	return router
+}
+
+// TestTimeout_SlowHandlerReturns408 testa o 408 e o descarte da resposta tardia do handler
+func TestTimeout_SlowHandlerReturns408(t *testing.T) {
+	router := newTimeoutRouter(200 * time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	assert.Equal(t, http.StatusRequestTimeout, rec.Code)
+	assert.Contains(t, rec.Body.String(), "TIMEOUT")
+	assert.NotContains(t, rec.Body.String(), "done")
+}
+
+// TestTimeout_FastHandlerUnaffected testa que respostas dentro do prazo passam intactas
+func TestTimeout_FastHandlerUnaffected(t *testing.T) {
+	router := newTimeoutRouter(0)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "done")
+}
+
+// TestTimeout_HandlerSettingHeadersAfterDeadline testa que headers definidos pelo handler após o timeout não disputam o mapa da resposta 408 (rodar com -race)
+func TestTimeout_HandlerSettingHeadersAfterDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Timeout(5 * time.Millisecond))
+	router.GET("/slow", func(c *gin.Context) {
+		deadline := time.Now().Add(50 * time.Millisecond)
+		for i := 0; time.Now().Before(deadline); i++ {
+			c.Header("X-Progress", strconv.Itoa(i))
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "done"})
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	assert.Equal(t, http.StatusRequestTimeout, rec.Code)
+	assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Empty(t, rec.Header().Get("X-Progress"))
+}
+
+// TestTimeout_HandlerHeadersReachResponse testa que headers do handler e de middlewares anteriores chegam à resposta
+func TestTimeout_HandlerHeadersReachResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(SecurityHeaders(), Timeout(time.Second))
+	router.GET("/created", func(c *gin.Context) {
+		c.Header("Location", "/things/1")
+		c.Status(http.StatusCreated)
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/created", nil))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "/things/1", rec.Header().Get("Location"))
+	assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+}
+
+// newCORSRouter cria um router com o middleware CORS para as origens informadas
+func newCORSRouter(allowedOrigins ...string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS(NewCORSPolicy(allowedOrigins)))
+	router.GET("/ping", func(c *gin.Context) {
+		c.String(http.StatusOK, "pong")
+	})
+	return router
+}
+
+// corsRequest envia uma requisição com o header Origin
+func corsRequest(router *gin.Engine, method, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/ping", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestCORS_EchoesAllowedOrigin testa que uma origem da lista recebe a si mesma e credenciais
+func TestCORS_EchoesAllowedOrigin(t *testing.T) {
+	router := newCORSRouter("https://app.example.com", "https://admin.example.com/")
+
+	rec := corsRequest(router, http.MethodGet, "https://admin.example.com")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "https://admin.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Contains(t, rec.Header().Values("Vary"), "Origin")
+}
+
+// TestCORS_RejectsUnknownOrigin testa que origens fora da lista não recebem headers CORS
+func TestCORS_RejectsUnknownOrigin(t *testing.T) {
+	router := newCORSRouter("https://app.example.com")
+
+	rec := corsRequest(router, http.MethodGet, "https://evil.example.com")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Credentials"))
+
+	preflight := corsRequest(router, http.MethodOptions, "https://evil.example.com")
+	assert.Equal(t, http.StatusForbidden, preflight.Code)
+}
+
+// TestCORS_WildcardWithoutCredentials testa que "*" nunca é combinado com credenciais
+func TestCORS_WildcardWithoutCredentials(t *testing.T) {
+	router := newCORSRouter("*")
+
+	rec := corsRequest(router, http.MethodOptions, "https://any.example.com")
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+// newBodyLimitRouter cria um router que lê o corpo inteiro com o limite informado
+func newBodyLimitRouter(maxBytes int64) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(BodyLimit(maxBytes))
+	echo := func(c *gin.Context) {
+		body, err := c.GetRawData()
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.String(http.StatusOK, string(body))
+	}
+	router.POST("/echo", echo)
+	router.DELETE("/echo", echo)
+	return router
+}
+
+// TestBodyLimit_RejectsDeclaredLength testa o 413 antes do handler quando o Content-Length passa do limite
+func TestBodyLimit_RejectsDeclaredLength(t *testing.T) {
+	router := newBodyLimitRouter(8)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("0123456789")))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Contains(t, rec.Body.String(), "BODY_TOO_LARGE")
+}
+
+// TestBodyLimit_CutsUndeclaredLength testa que um corpo sem Content-Length é cortado na leitura
+func TestBodyLimit_CutsUndeclaredLength(t *testing.T) {
+	router := newBodyLimitRouter(8)
+
+	req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("0123456789"))
+	req.ContentLength = -1
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "too large")
+}
+
+// TestBodyLimit_IgnoresOtherMethodsAndSmallBodies testa que apenas POST/PUT/PATCH acima do limite são afetados
+func TestBodyLimit_IgnoresOtherMethodsAndSmallBodies(t *testing.T) {
+	router := newBodyLimitRouter(8)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("small")))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "small", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/echo", strings.NewReader("0123456789")))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -2,23 +2,56 @@ package routes
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/vitao/geolocation-tracker/internal/interfaces/http/handler"
+	"github.com/vitao/geolocation-tracker/internal/interfaces/http/middleware"
 	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/pkg/config"
 	"github.com/vitao/geolocation-tracker/pkg/logger"
+	"github.com/vitao/geolocation-tracker/pkg/metrics"
 )
 
 // SetupRoutes configura todas as rotas da aplicação
 func SetupRoutes(
 	createUserUC *usecase.CreateUserUseCase,
+	listUsersUC *usecase.ListUsersUseCase,
+	getUserByEmailUC *usecase.GetUserByEmailUseCase,
+	deleteUserUC *usecase.DeleteUserUseCase,
 	savePositionUC *usecase.SaveUserPositionUseCase,
+	savePositionsBatchUC *usecase.SaveUserPositionsBatchUseCase,
 	findNearbyUC *usecase.FindNearbyUsersUseCase,
+	findNearbyBatchUC *usecase.FindNearbyUsersBatchUseCase,
 	getUsersInSectorUC *usecase.GetUsersInSectorUseCase,
 	getCurrentPositionUC *usecase.GetCurrentPositionUseCase,
+	getCurrentBatchUC *usecase.GetCurrentPositionsBatchUseCase,
+	getInBoundingBoxUC *usecase.GetPositionsInBoundingBoxUseCase,
+	getGroupCentroidUC *usecase.GetGroupCentroidUseCase,
 	getPositionHistoryUC *usecase.GetPositionHistoryUseCase,
+	exportHistoryUC *usecase.ExportPositionHistoryUseCase,
+	streamHistoryUC *usecase.StreamPositionHistoryUseCase,
+	getMovementStatsUC *usecase.GetUserMovementStatsUseCase,
+	getSectorsAroundUC *usecase.GetSectorsAroundUseCase,
+	getSectorStatsUC *usecase.GetSectorStatisticsUseCase,
+	getSectorDensityUC *usecase.GetSectorDensityUseCase,
+	getSectorAnalysisUC *usecase.GetSectorAnalysisUseCase,
+	getOccupiedSectorsUC *usecase.GetOccupiedSectorsUseCase,
+	inspectUserCacheUC *usecase.InspectUserCacheUseCase,
+	recomputeSectorsUC *usecase.RecomputeSectorsUseCase,
+	getRecentActivityUC *usecase.GetRecentActivityUseCase,
+	getPositionByIDUC *usecase.GetPositionByIDUseCase,
+	backgroundRunner usecase.BackgroundRunner,
+	adminToken string,
+	rateLimitStore middleware.RateLimitStore,
+	rateLimitCfg config.RateLimitConfig,
+	requestTimeout time.Duration,
+	maxBodyBytes int64,
+	strictJSON bool,
+	corsPolicy *middleware.CORSPolicy,
+	collector metrics.Collector,
 	logger logger.Logger,
 ) *gin.Engine {
 
@@ -26,21 +59,18 @@ func SetupRoutes(
 	router := gin.New()
 
 	// Middlewares básicos
-	router.Use(gin.Logger())
+	router.Use(middleware.RequestLogger(logger))
+	router.Use(middleware.Metrics(collector))
 	router.Use(gin.Recovery())
+	router.Use(middleware.RequestID())
+	router.Use(middleware.CORS(corsPolicy))
 
-	// CORS middleware
-	router.Use(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusOK)
-			return
-		}
-		c.Next()
-	})
+	// Swagger documentation
+	// Registrado antes de SecurityHeaders: a UI usa scripts inline, bloqueados pelo CSP
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	router.Use(middleware.SecurityHeaders())
+	router.Use(middleware.ErrorHandler(logger))
 
 	// Health check
 	// @Summary Health Check
@@ -57,37 +87,134 @@ func SetupRoutes(
 		})
 	})
 
-	// Swagger documentation
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-
 	// Criar handlers
 	userHandler := handler.NewUserHandler(
 		createUserUC,
+		listUsersUC,
+		getUserByEmailUC,
+		deleteUserUC,
 		getCurrentPositionUC,
 		getPositionHistoryUC,
+		getMovementStatsUC,
+		exportHistoryUC,
+		streamHistoryUC,
+		strictJSON,
 		logger,
 	)
 
 	positionHandler := handler.NewPositionHandler(
 		savePositionUC,
+		savePositionsBatchUC,
 		findNearbyUC,
+		findNearbyBatchUC,
 		getUsersInSectorUC,
+		getPositionByIDUC,
+		getCurrentBatchUC,
+		getInBoundingBoxUC,
+		getGroupCentroidUC,
+		strictJSON,
 		logger,
 	)
 
+	sectorHandler := handler.NewSectorHandler(
+		getSectorsAroundUC,
+		getSectorStatsUC,
+		getSectorDensityUC,
+		getSectorAnalysisUC,
+		getOccupiedSectorsUC,
+		logger,
+	)
+
+	adminHandler := handler.NewAdminHandler(
+		inspectUserCacheUC,
+		recomputeSectorsUC,
+		backgroundRunner,
+		logger,
+	)
+
+	feedHandler := handler.NewFeedHandler(
+		getRecentActivityUC,
+		logger,
+	)
+
+	// Rate limiting por grupo de rotas (contadores independentes por grupo)
+	rateLimit := func(group string) gin.HandlerFunc {
+		return middleware.RateLimiter(rateLimitStore, group, groupRateLimit(rateLimitCfg, group, logger), logger)
+	}
+
 	// API v1 routes
-	api := router.Group("/api/v1")
+	api := router.Group("/api/v1", middleware.Timeout(requestTimeout), middleware.BodyLimit(maxBodyBytes))
 	{
 		// Rotas de usuários
-		api.POST("/users", userHandler.CreateUser)
-		api.GET("/users/:id/position", userHandler.GetCurrentPosition)
-		api.GET("/users/:id/positions/history", userHandler.GetPositionHistory)
+		users := api.Group("/users", rateLimit("users"))
+		users.POST("", userHandler.CreateUser)
+		users.GET("", userHandler.ListUsers)
+		users.GET("/by-email", userHandler.GetUserByEmail)
+		users.DELETE("/:id", userHandler.DeleteUser)
+		users.GET("/:id/position", userHandler.GetCurrentPosition)
+		users.GET("/:id/positions/history", userHandler.GetPositionHistory)
+		users.GET("/:id/positions/stream", userHandler.StreamPositionHistory)
+		users.GET("/:id/movement-stats", userHandler.GetMovementStats)
 
 		// Rotas de posições
-		api.POST("/positions", positionHandler.SavePosition)
-		api.GET("/positions/nearby", positionHandler.FindNearbyUsers)
-		api.GET("/positions/sector", positionHandler.GetUsersInSector)
+		positions := api.Group("/positions", rateLimit("positions"))
+		positions.POST("", positionHandler.SavePosition)
+		positions.POST("/batch", positionHandler.SavePositionsBatch)
+		positions.GET("/nearby", positionHandler.FindNearbyUsers)
+		positions.POST("/nearby/batch", positionHandler.FindNearbyUsersBatch)
+		positions.POST("/current/batch", positionHandler.GetCurrentPositionsBatch)
+		positions.POST("/centroid", positionHandler.GetGroupCentroid)
+		positions.GET("/sector", positionHandler.GetUsersInSector)
+		positions.GET("/bbox", positionHandler.GetPositionsInBoundingBox)
+		positions.GET("/:id", positionHandler.GetPositionByID)
+
+		// Rotas de setores
+		sectors := api.Group("/sectors", rateLimit("sectors"))
+		sectors.GET("/around", sectorHandler.GetSectorsAround)
+		sectors.GET("/occupied", sectorHandler.GetOccupiedSectors)
+		sectors.GET("/:id/stats", sectorHandler.GetSectorStatistics)
+		sectors.GET("/:id/density", sectorHandler.GetSectorDensity)
+		sectors.GET("/:id/analysis", sectorHandler.GetSectorAnalysis)
+
+		// Feed de atividade
+		feed := api.Group("/feed", rateLimit("feed"))
+		feed.GET("/recent", feedHandler.GetRecentActivity)
+
+		// Rotas administrativas (autenticadas via X-Admin-Token)
+		admin := api.Group("/admin", rateLimit("admin"), middleware.AdminAuth(adminToken, logger))
+		admin.GET("/cache/user/:id", adminHandler.GetUserCache)
+		admin.POST("/sectors/recompute", adminHandler.RecomputeSectors)
+		admin.GET("/sectors/recompute", adminHandler.GetRecomputeSectorsStatus)
 	}
 
+	// Exportação fora do grupo com timeout: o download do histórico completo pode passar do prazo de requisição
+	// (o prazo de escrita do servidor é estendido a cada chunk pelo handler)
+	export := router.Group("/api/v1/users", middleware.BodyLimit(maxBodyBytes), rateLimit("users"))
+	export.GET("/:id/positions/export", userHandler.ExportPositionHistory)
+
 	return router
 }
+
+// groupRateLimit resolve o limite do grupo: override em RATE_LIMIT_GROUPS ou o padrão
+func groupRateLimit(cfg config.RateLimitConfig, group string, logger logger.Logger) middleware.RateLimit {
+	limit := middleware.RateLimit{
+		Requests: cfg.Requests,
+		Window:   time.Duration(cfg.WindowSeconds) * time.Second,
+	}
+
+	override, ok := cfg.Groups[group]
+	if !ok {
+		return limit
+	}
+
+	parsed, err := middleware.ParseRateLimit(override)
+	if err != nil {
+		logger.Warn("Invalid rate limit override, using default",
+			"group", group,
+			"value", override,
+			"error", err.Error(),
+		)
+		return limit
+	}
+	return parsed
+}
@@ -0,0 +1,141 @@
+package routes_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/interfaces/http/middleware"
+	"github.com/vitao/geolocation-tracker/internal/interfaces/http/routes"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+	"github.com/vitao/geolocation-tracker/pkg/metrics"
+)
+
+// newTestRouter monta as rotas com apenas o use case de exportação, o rate limit e o timeout de requisição informados
+func newTestRouter(
+	t *testing.T,
+	exportUC *usecase.ExportPositionHistoryUseCase,
+	rateLimitStore middleware.RateLimitStore,
+	rateLimitCfg config.RateLimitConfig,
+	requestTimeout time.Duration,
+) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	log, err := logger.New(logger.Options{Level: "error"})
+	require.NoError(t, err)
+
+	return routes.SetupRoutes(
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		exportUC,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil,
+		"",
+		rateLimitStore,
+		rateLimitCfg,
+		requestTimeout,
+		1<<20,
+		false,
+		middleware.NewCORSPolicy(nil),
+		metrics.NewNoopCollector(),
+		log,
+	)
+}
+
+// TestExportPositionHistory_OutlivesRequestTimeout testa que uma exportação mais longa que o timeout de requisição chega completa
+func TestExportPositionHistory_OutlivesRequestTimeout(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	positionRepo := new(mocks.MockPositionRepository)
+
+	log, err := logger.New(logger.Options{Level: "error"})
+	require.NoError(t, err)
+	cfg := &config.Config{Database: config.DatabaseConfig{PositionExportChunkSize: 1}}
+	exportUC := usecase.NewExportPositionHistoryUseCase(userRepo, positionRepo, log, cfg)
+
+	userID, err := entity.NewUserID("user123")
+	require.NoError(t, err)
+	user, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	require.NoError(t, err)
+	userRepo.On("FindByID", mock.Anything, *userID).Return(user, nil)
+
+	// Três chunks de 1 posição, cada leitura levando 20ms: ~60ms contra um timeout de 20ms
+	start := time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+	var cursor *repository.HistoryCursor
+	for i, id := range []string{"pos-1", "pos-2", "pos-3"} {
+		recordedAt := start.Add(time.Duration(i) * time.Minute)
+		position, err := entity.NewPosition(id, *userID, -23.550520, -46.633309, recordedAt)
+		require.NoError(t, err)
+
+		positionRepo.On("FindHistoryByUserIDAfter", mock.Anything, *userID, cursor, 1).
+			Return([]*entity.Position{position}, nil).After(20 * time.Millisecond).Once()
+		cursor = &repository.HistoryCursor{RecordedAt: recordedAt, PositionID: id}
+	}
+	positionRepo.On("FindHistoryByUserIDAfter", mock.Anything, *userID, cursor, 1).
+		Return([]*entity.Position{}, nil).Once()
+
+	// Servidor real com WriteTimeout também curto: o handler precisa estender o prazo a cada chunk
+	server := httptest.NewUnstartedServer(newTestRouter(t, exportUC, nil, config.RateLimitConfig{}, 20*time.Millisecond))
+	server.Config.WriteTimeout = 20 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/users/user123/positions/export")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	var items []usecase.PositionExportItem
+	require.NoError(t, json.Unmarshal(body, &items))
+	require.Len(t, items, 3)
+	assert.Equal(t, "pos-1", items[0].PositionID)
+	assert.Equal(t, "pos-3", items[2].PositionID)
+
+	userRepo.AssertExpectations(t)
+	positionRepo.AssertExpectations(t)
+}
+
+// recordingRateLimitStore recusa toda requisição e registra o limite pedido por chave
+type recordingRateLimitStore struct {
+	limits map[string]middleware.RateLimit
+}
+
+func (s *recordingRateLimitStore) AllowRequest(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	s.limits[key] = middleware.RateLimit{Requests: limit, Window: window}
+	return false, window, nil
+}
+
+// TestSetupRoutes_RateLimitFromConfig testa que os limites de RATE_LIMIT_* carregados pelo config chegam ao limiter de cada grupo
+func TestSetupRoutes_RateLimitFromConfig(t *testing.T) {
+	t.Setenv("RATE_LIMIT_REQUESTS", "30")
+	t.Setenv("RATE_LIMIT_WINDOW_SECONDS", "10")
+	t.Setenv("RATE_LIMIT_GROUPS", "positions=600/60")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+
+	store := &recordingRateLimitStore{limits: make(map[string]middleware.RateLimit)}
+	router := newTestRouter(t, nil, store, cfg.RateLimit, time.Second)
+
+	for _, path := range []string{"/api/v1/positions/nearby", "/api/v1/sectors/around"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code, path)
+	}
+
+	assert.Equal(t, middleware.RateLimit{Requests: 600, Window: time.Minute}, store.limits["positions:ip:192.0.2.1"])
+	assert.Equal(t, middleware.RateLimit{Requests: 30, Window: 10 * time.Second}, store.limits["sectors:ip:192.0.2.1"])
+}
@@ -20,3 +20,28 @@ type CacheInterface interface {
 	GetCachedUserHistory(ctx context.Context, userID string, limit int, dest interface{}) error
 	InvalidateUserCaches(ctx context.Context, userID string) error
 }
+
+// CacheEntry representa o conteúdo bruto de uma chave do cache
+type CacheEntry struct {
+	Key   string
+	Found bool
+	TTL   time.Duration // -1 indica chave sem expiração
+	Value string        // JSON armazenado
+}
+
+// CacheInspector define a leitura bruta do cache usada para diagnóstico
+type CacheInspector interface {
+	Inspect(ctx context.Context, key string) (*CacheEntry, error)
+}
+
+// Chaves de cache conhecidas por usuário
+const (
+	userPositionCacheKey = "user:position:%s"
+	userHistoryCacheKey  = "history:%s:%d"
+
+	// proximityStateCacheKey guarda os IDs dos usuários que já estavam próximos (alertas de proximidade)
+	proximityStateCacheKey = "proximity:%s"
+)
+
+// historyCacheLimits são os limits de histórico mais comuns, usados na invalidação e no diagnóstico
+var historyCacheLimits = []int{10, 20, 50, 100}
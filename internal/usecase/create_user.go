@@ -45,22 +45,24 @@ func NewCreateUserUseCase(
 
 // Execute executa o use case de criação de usuário
 func (uc *CreateUserUseCase) Execute(ctx context.Context, req CreateUserRequest) (*CreateUserResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
 	// 1. Criar usuário
 	user, err := entity.NewUser(req.ID, req.Name, req.Email)
 	if err != nil {
-		uc.logger.Error("Failed to create user entity", map[string]interface{}{
+		log.Error("Failed to create user entity", map[string]interface{}{
 			"user_id": req.ID,
 			"name":    req.Name,
 			"email":   req.Email,
 			"error":   err.Error(),
 		})
-		return nil, fmt.Errorf("invalid user data: %w", err)
+		return nil, fmt.Errorf("%w: invalid user data: %w", ErrInvalidInput, err)
 	}
 
 	// 2. Verificar se o usuário já existe
 	existingUser, err := uc.userRepo.FindByID(ctx, user.ID())
 	if err == nil && existingUser != nil {
-		uc.logger.Info("User already exists", map[string]interface{}{
+		log.Info("User already exists", map[string]interface{}{
 			"user_id": req.ID,
 		})
 		existingUserID := existingUser.ID()
@@ -76,14 +78,14 @@ func (uc *CreateUserUseCase) Execute(ctx context.Context, req CreateUserRequest)
 
 	// 3. Salvar usuário no repository
 	if err := uc.userRepo.Save(ctx, user); err != nil {
-		uc.logger.Error("Failed to save user", map[string]interface{}{
+		log.Error("Failed to save user", map[string]interface{}{
 			"user_id": req.ID,
 			"error":   err.Error(),
 		})
 		return nil, fmt.Errorf("failed to save user: %w", err)
 	}
 
-	uc.logger.Info("User created successfully", map[string]interface{}{
+	log.Info("User created successfully", map[string]interface{}{
 		"user_id": req.ID,
 		"name":    req.Name,
 		"email":   req.Email,
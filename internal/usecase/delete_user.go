@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/events"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// DeleteUserRequest representa os dados de entrada
+type DeleteUserRequest struct {
+	UserID    string `json:"user_id" validate:"required"`
+	RequestID string `json:"-"` // ID de correlação da requisição
+}
+
+// DeleteUserResponse representa a resposta
+type DeleteUserResponse struct {
+	UserID  string `json:"user_id"`
+	Message string `json:"message"`
+}
+
+// DeleteUserUseCase remove um usuário e todas as suas posições
+type DeleteUserUseCase struct {
+	userRepo       repository.UserRepository
+	eventPublisher events.Publisher
+	cache          CacheInterface
+	logger         logger.Logger
+}
+
+// NewDeleteUserUseCase cria uma nova instância do use case
+func NewDeleteUserUseCase(
+	userRepo repository.UserRepository,
+	eventPublisher events.Publisher,
+	cache CacheInterface,
+	logger logger.Logger,
+) *DeleteUserUseCase {
+	return &DeleteUserUseCase{
+		userRepo:       userRepo,
+		eventPublisher: eventPublisher,
+		cache:          cache,
+		logger:         logger,
+	}
+}
+
+// Execute executa o use case de remoção de usuário
+func (uc *DeleteUserUseCase) Execute(ctx context.Context, req DeleteUserRequest) (*DeleteUserResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	// 1. Validar ID
+	userID, err := entity.NewUserID(req.UserID)
+	if err != nil {
+		log.Error("Invalid user ID", map[string]interface{}{
+			"user_id": req.UserID,
+			"error":   err.Error(),
+		})
+		return nil, fmt.Errorf("%w: invalid user ID: %w", ErrInvalidInput, err)
+	}
+
+	// 2. Remover usuário, posição atual e histórico (transação no repository)
+	if err := uc.userRepo.Delete(ctx, *userID); err != nil {
+		if errors.Is(err, entity.ErrUserIDNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrUserNotFound, req.UserID)
+		}
+		log.Error("Failed to delete user", map[string]interface{}{
+			"user_id": req.UserID,
+			"error":   err.Error(),
+		})
+		return nil, fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	// 3. Invalidar caches locais do usuário
+	uc.invalidateUserCaches(ctx, userID.String())
+
+	// 4. Publicar evento para que consumidores invalidem seus caches
+	requestID := req.RequestID
+	if requestID == "" {
+		requestID = RequestIDFromContext(ctx)
+	}
+	event := events.NewUserDeletedEvent(userID.String(), eventContextID(requestID))
+	event.Metadata.RequestID = requestID
+	if err := uc.eventPublisher.PublishUserDeleted(ctx, event); err != nil {
+		// Log error mas não falha a operação (a remoção já foi comitada)
+		log.Error("Failed to publish user deleted event", map[string]interface{}{
+			"user_id": req.UserID,
+			"error":   err.Error(),
+		})
+	}
+
+	log.Info("User deleted successfully", map[string]interface{}{
+		"user_id": req.UserID,
+	})
+
+	return &DeleteUserResponse{
+		UserID:  userID.String(),
+		Message: "User and positions deleted successfully",
+	}, nil
+}
+
+// invalidateUserCaches remove posição atual e históricos cacheados do usuário
+func (uc *DeleteUserUseCase) invalidateUserCaches(ctx context.Context, userID string) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	keys := []string{fmt.Sprintf(userPositionCacheKey, userID)}
+	for _, limit := range historyCacheLimits {
+		keys = append(keys, fmt.Sprintf(userHistoryCacheKey, userID, limit))
+	}
+
+	for _, key := range keys {
+		if err := uc.cache.Delete(ctx, key); err != nil {
+			log.Debug("Failed to invalidate user cache", map[string]interface{}{
+				"user_id": userID,
+				"key":     key,
+				"error":   err.Error(),
+			})
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/events"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+)
+
+// DeleteUserUseCaseTestSuite define a suite de testes para DeleteUserUseCase
+type DeleteUserUseCaseTestSuite struct {
+	suite.Suite
+	userRepo       *mocks.MockUserRepository
+	eventPublisher *mocks.MockEventPublisher
+	cache          *mocks.MockCache
+	logger         *mocks.MockLogger
+	useCase        *usecase.DeleteUserUseCase
+	ctx            context.Context
+}
+
+// SetupTest configura cada teste
+func (suite *DeleteUserUseCaseTestSuite) SetupTest() {
+	suite.userRepo = new(mocks.MockUserRepository)
+	suite.eventPublisher = new(mocks.MockEventPublisher)
+	suite.cache = new(mocks.MockCache)
+	suite.logger = new(mocks.MockLogger)
+	suite.useCase = usecase.NewDeleteUserUseCase(suite.userRepo, suite.eventPublisher, suite.cache, suite.logger)
+	suite.ctx = context.Background()
+}
+
+// TearDownTest limpa após cada teste
+func (suite *DeleteUserUseCaseTestSuite) TearDownTest() {
+	suite.userRepo.AssertExpectations(suite.T())
+	suite.eventPublisher.AssertExpectations(suite.T())
+	suite.cache.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// TestDeleteUser_Success testa remoção com invalidação de cache e evento user.deleted
+func (suite *DeleteUserUseCaseTestSuite) TestDeleteUser_Success() {
+	// Arrange
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+
+	suite.userRepo.On("Delete", mock.Anything, *userID).Return(nil)
+	suite.cache.On("Delete", mock.Anything, "user:position:user123").Return(nil)
+	for _, limit := range []int{10, 20, 50, 100} {
+		suite.cache.On("Delete", mock.Anything, fmt.Sprintf("history:user123:%d", limit)).Return(nil)
+	}
+	suite.eventPublisher.On("PublishUserDeleted", mock.Anything,
+		mock.MatchedBy(func(event *events.Event) bool {
+			return event.Type == events.EventTypeUserDeleted &&
+				event.UserID == "user123" &&
+				event.Metadata.RequestID == "req-1"
+		})).Return(nil)
+	suite.logger.On("Info", "User deleted successfully", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.DeleteUserRequest{UserID: "user123", RequestID: "req-1"})
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "user123", response.UserID)
+}
+
+// TestDeleteUser_NotFound testa que um usuário inexistente retorna ErrUserNotFound sem publicar evento
+func (suite *DeleteUserUseCaseTestSuite) TestDeleteUser_NotFound() {
+	// Arrange
+	userID, err := entity.NewUserID("missing")
+	suite.Require().NoError(err)
+
+	suite.userRepo.On("Delete", mock.Anything, *userID).
+		Return(fmt.Errorf("%w: missing", entity.ErrUserIDNotFound))
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.DeleteUserRequest{UserID: "missing"})
+
+	// Assert
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, usecase.ErrUserNotFound)
+	suite.eventPublisher.AssertNotCalled(suite.T(), "PublishUserDeleted", mock.Anything, mock.Anything)
+}
+
+// TestDeleteUser_PublishErrorDoesNotFail testa que falha ao publicar o evento não desfaz a remoção
+func (suite *DeleteUserUseCaseTestSuite) TestDeleteUser_PublishErrorDoesNotFail() {
+	// Arrange
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+
+	suite.userRepo.On("Delete", mock.Anything, *userID).Return(nil)
+	suite.cache.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	suite.eventPublisher.On("PublishUserDeleted", mock.Anything, mock.Anything).
+		Return(errors.New("redis unavailable"))
+	suite.logger.On("Error", "Failed to publish user deleted event", mock.Anything).Return()
+	suite.logger.On("Info", "User deleted successfully", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.DeleteUserRequest{UserID: "user123"})
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.NotNil(suite.T(), response)
+}
+
+// TestDeleteUser_RequestIDFromContext testa que o ID de correlação do context chega ao evento e aos logs
+func (suite *DeleteUserUseCaseTestSuite) TestDeleteUser_RequestIDFromContext() {
+	// Arrange
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	ctx := usecase.ContextWithRequestID(suite.ctx, "req-ctx")
+
+	suite.userRepo.On("Delete", mock.Anything, *userID).Return(nil)
+	suite.cache.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	suite.eventPublisher.On("PublishUserDeleted", mock.Anything,
+		mock.MatchedBy(func(event *events.Event) bool {
+			return event.EventID == "req-ctx" && event.Metadata.RequestID == "req-ctx"
+		})).Return(nil)
+	suite.logger.On("Info", "User deleted successfully",
+		mock.MatchedBy(func(fields map[string]interface{}) bool {
+			return fields["request_id"] == "req-ctx" && fields["user_id"] == "user123"
+		})).Return()
+
+	// Act
+	_, err = suite.useCase.Execute(ctx, usecase.DeleteUserRequest{UserID: "user123"})
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+// TestDeleteUserUseCase executa toda a suite de testes
+func TestDeleteUserUseCase(t *testing.T) {
+	suite.Run(t, new(DeleteUserUseCaseTestSuite))
+}
@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+)
+
+// Erros sentinela dos use cases; os handlers os mapeiam para status HTTP com errors.Is
+// Erros fora desta lista (e dos sentinelas específicos de cada use case) são falhas de infraestrutura
+var (
+	// ErrInvalidInput indica dados de entrada inválidos (HTTP 400)
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrUserNotFound indica que o usuário não existe (HTTP 404)
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrPositionNotFound indica que a posição consultada não existe (HTTP 404)
+	ErrPositionNotFound = errors.New("position not found")
+)
+
+// userLookupError converte o erro de UserRepository.FindByID:
+// usuário inexistente vira ErrUserNotFound, o resto continua falha de infraestrutura
+func userLookupError(userID string, err error) error {
+	if errors.Is(err, entity.ErrUserIDNotFound) {
+		return fmt.Errorf("%w: %s", ErrUserNotFound, userID)
+	}
+	return fmt.Errorf("failed to find user %s: %w", userID, err)
+}
@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+const (
+	// ExportFormatJSON exporta o histórico como um array JSON
+	ExportFormatJSON = "json"
+	// ExportFormatCSV exporta o histórico como CSV com linha de cabeçalho
+	ExportFormatCSV = "csv"
+
+	// DefaultPositionExportChunkSize é o tamanho de chunk usado quando não configurado
+	DefaultPositionExportChunkSize = 500
+)
+
+// ErrUnsupportedExportFormat indica um formato de exportação diferente de csv e json
+var ErrUnsupportedExportFormat = errors.New("unsupported export format, expected csv or json")
+
+// positionExportCSVHeader são as colunas do CSV exportado
+var positionExportCSVHeader = []string{"position_id", "latitude", "longitude", "sector_id", "recorded_at"}
+
+// ExportPositionHistoryRequest representa os dados de entrada (Format vazio usa json)
+type ExportPositionHistoryRequest struct {
+	UserID string `json:"user_id"`
+	Format string `json:"format"`
+}
+
+// ExportPositionHistoryResponse resume a exportação já escrita
+type ExportPositionHistoryResponse struct {
+	UserID   string `json:"user_id"`
+	Format   string `json:"format"`
+	Exported int    `json:"exported"`
+	Chunks   int    `json:"chunks"`
+}
+
+// PositionExportItem representa uma posição exportada
+type PositionExportItem struct {
+	PositionID string    `json:"position_id"`
+	Latitude   float64   `json:"latitude"`
+	Longitude  float64   `json:"longitude"`
+	SectorID   string    `json:"sector_id"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// ExportPositionHistoryUseCase exporta o histórico completo de um usuário em streaming
+// O histórico é lido em chunks por paginação por chave, sem carregar tudo em memória
+type ExportPositionHistoryUseCase struct {
+	userRepo     repository.UserRepository
+	positionRepo repository.PositionRepository
+	logger       logger.Logger
+	chunkSize    int
+}
+
+// NewExportPositionHistoryUseCase cria uma nova instância do use case
+func NewExportPositionHistoryUseCase(
+	userRepo repository.UserRepository,
+	positionRepo repository.PositionRepository,
+	logger logger.Logger,
+	cfg *config.Config,
+) *ExportPositionHistoryUseCase {
+	chunkSize := cfg.Database.PositionExportChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultPositionExportChunkSize
+	}
+
+	return &ExportPositionHistoryUseCase{
+		userRepo:     userRepo,
+		positionRepo: positionRepo,
+		logger:       logger,
+		chunkSize:    chunkSize,
+	}
+}
+
+// NormalizeExportFormat valida o formato pedido; vazio usa json
+func NormalizeExportFormat(format string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", ExportFormatJSON:
+		return ExportFormatJSON, nil
+	case ExportFormatCSV:
+		return ExportFormatCSV, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedExportFormat, format)
+	}
+}
+
+// Execute escreve o histórico em w, da posição mais antiga para a mais recente
+// Nada é escrito em w antes de o formato e o usuário serem validados; um erro depois
+// disso significa que a saída ficou incompleta
+func (uc *ExportPositionHistoryUseCase) Execute(ctx context.Context, req ExportPositionHistoryRequest, w io.Writer) (*ExportPositionHistoryResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	// 1. Validar formato e usuário
+	format, err := NormalizeExportFormat(req.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDPtr, err := entity.NewUserID(req.UserID)
+	if err != nil {
+		log.Error("Invalid user ID", map[string]interface{}{
+			"user_id": req.UserID,
+			"error":   err.Error(),
+		})
+		return nil, fmt.Errorf("%w: invalid user ID: %w", ErrInvalidInput, err)
+	}
+
+	userID := *userIDPtr
+	if _, err := uc.userRepo.FindByID(ctx, userID); err != nil {
+		log.Error("User not found", map[string]interface{}{
+			"user_id": req.UserID,
+			"error":   err.Error(),
+		})
+		return nil, userLookupError(req.UserID, err)
+	}
+
+	// 2. Percorrer o histórico em chunks, escrevendo cada um assim que lido
+	encoder := newPositionExportEncoder(format, w)
+	if err := encoder.begin(); err != nil {
+		return nil, fmt.Errorf("failed to write export: %w", err)
+	}
+
+	response := &ExportPositionHistoryResponse{UserID: req.UserID, Format: format}
+	var cursor *repository.HistoryCursor
+
+	for {
+		positions, err := uc.positionRepo.FindHistoryByUserIDAfter(ctx, userID, cursor, uc.chunkSize)
+		if err != nil {
+			log.Error("Failed to read position history for export", map[string]interface{}{
+				"user_id":  req.UserID,
+				"exported": response.Exported,
+				"error":    err.Error(),
+			})
+			return nil, fmt.Errorf("failed to read position history: %w", err)
+		}
+		if len(positions) == 0 {
+			break
+		}
+
+		for _, position := range positions {
+			if err := encoder.write(toPositionExportItem(position)); err != nil {
+				return nil, fmt.Errorf("failed to write export: %w", err)
+			}
+		}
+		if err := encoder.flush(); err != nil {
+			return nil, fmt.Errorf("failed to write export: %w", err)
+		}
+
+		cursor = repository.NewHistoryCursor(positions[len(positions)-1])
+		response.Exported += len(positions)
+		response.Chunks++
+
+		if len(positions) < uc.chunkSize {
+			break
+		}
+	}
+
+	if err := encoder.end(); err != nil {
+		return nil, fmt.Errorf("failed to write export: %w", err)
+	}
+
+	log.Info("Position history exported", map[string]interface{}{
+		"user_id":  req.UserID,
+		"format":   format,
+		"exported": response.Exported,
+		"chunks":   response.Chunks,
+	})
+
+	return response, nil
+}
+
+// toPositionExportItem monta o item exportado a partir da posição
+func toPositionExportItem(position *entity.Position) PositionExportItem {
+	positionID := position.ID()
+	coordinate := position.Coordinate()
+
+	return PositionExportItem{
+		PositionID: positionID.Value(),
+		Latitude:   coordinate.Latitude(),
+		Longitude:  coordinate.Longitude(),
+		SectorID:   position.Sector().ID(),
+		RecordedAt: position.RecordedAt().Time(),
+	}
+}
+
+// positionExportEncoder escreve os itens exportados em um formato
+type positionExportEncoder interface {
+	begin() error
+	write(item PositionExportItem) error
+	flush() error
+	end() error
+}
+
+// newPositionExportEncoder cria o encoder do formato (já normalizado)
+func newPositionExportEncoder(format string, w io.Writer) positionExportEncoder {
+	if format == ExportFormatCSV {
+		return &csvExportEncoder{w: csv.NewWriter(w)}
+	}
+	return &jsonExportEncoder{w: w}
+}
+
+// jsonExportEncoder escreve um array JSON, um item por vez
+type jsonExportEncoder struct {
+	w       io.Writer
+	written bool
+}
+
+func (e *jsonExportEncoder) begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonExportEncoder) write(item PositionExportItem) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	if e.written {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.written = true
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonExportEncoder) flush() error { return nil }
+
+func (e *jsonExportEncoder) end() error {
+	_, err := io.WriteString(e.w, "]\n")
+	return err
+}
+
+// csvExportEncoder escreve o CSV com cabeçalho, com flush a cada chunk
+type csvExportEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvExportEncoder) begin() error {
+	return e.w.Write(positionExportCSVHeader)
+}
+
+func (e *csvExportEncoder) write(item PositionExportItem) error {
+	return e.w.Write([]string{
+		item.PositionID,
+		strconv.FormatFloat(item.Latitude, 'f', -1, 64),
+		strconv.FormatFloat(item.Longitude, 'f', -1, 64),
+		item.SectorID,
+		item.RecordedAt.Format(time.RFC3339Nano),
+	})
+}
+
+func (e *csvExportEncoder) flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExportEncoder) end() error {
+	return e.flush()
+}
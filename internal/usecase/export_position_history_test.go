@@ -0,0 +1,186 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+)
+
+// ExportPositionHistoryUseCaseTestSuite define a suite de testes para ExportPositionHistoryUseCase
+type ExportPositionHistoryUseCaseTestSuite struct {
+	suite.Suite
+	userRepo     *mocks.MockUserRepository
+	positionRepo *mocks.MockPositionRepository
+	logger       *mocks.MockLogger
+	useCase      *usecase.ExportPositionHistoryUseCase
+	ctx          context.Context
+	userID       *entity.UserID
+	start        time.Time
+}
+
+// SetupTest configura cada teste com chunks de 2 posições
+func (suite *ExportPositionHistoryUseCaseTestSuite) SetupTest() {
+	suite.userRepo = new(mocks.MockUserRepository)
+	suite.positionRepo = new(mocks.MockPositionRepository)
+	suite.logger = new(mocks.MockLogger)
+	cfg := &config.Config{Database: config.DatabaseConfig{PositionExportChunkSize: 2}}
+	suite.useCase = usecase.NewExportPositionHistoryUseCase(suite.userRepo, suite.positionRepo, suite.logger, cfg)
+	suite.ctx = context.Background()
+	suite.start = time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	suite.userID = userID
+}
+
+// TearDownTest limpa após cada teste
+func (suite *ExportPositionHistoryUseCaseTestSuite) TearDownTest() {
+	suite.userRepo.AssertExpectations(suite.T())
+	suite.positionRepo.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// position cria uma posição do usuário da suite, minutes após o início
+func (suite *ExportPositionHistoryUseCaseTestSuite) position(id string, minutes int) *entity.Position {
+	position, err := entity.NewPosition(id, *suite.userID, -23.550520, -46.633309, suite.start.Add(time.Duration(minutes)*time.Minute))
+	suite.Require().NoError(err)
+	return position
+}
+
+// mockThreePositions simula um histórico de 3 posições lido em 2 chunks
+func (suite *ExportPositionHistoryUseCaseTestSuite) mockThreePositions() {
+	user, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+	suite.userRepo.On("FindByID", mock.Anything, *suite.userID).Return(user, nil)
+
+	chunk1 := []*entity.Position{suite.position("pos-1", 0), suite.position("pos-2", 1)}
+	chunk2 := []*entity.Position{suite.position("pos-3", 2)}
+
+	suite.positionRepo.On("FindHistoryByUserIDAfter", mock.Anything, *suite.userID, (*repository.HistoryCursor)(nil), 2).
+		Return(chunk1, nil).Once()
+	suite.positionRepo.On("FindHistoryByUserIDAfter", mock.Anything, *suite.userID,
+		&repository.HistoryCursor{RecordedAt: suite.start.Add(time.Minute), PositionID: "pos-2"}, 2).
+		Return(chunk2, nil).Once()
+	suite.logger.On("Info", "Position history exported", mock.Anything).Return()
+}
+
+// TestExport_CSVPagesThroughHistory testa o cabeçalho, a ordem e a continuação pelo cursor
+func (suite *ExportPositionHistoryUseCaseTestSuite) TestExport_CSVPagesThroughHistory() {
+	// Arrange
+	suite.mockThreePositions()
+	var out bytes.Buffer
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.ExportPositionHistoryRequest{
+		UserID: "user123",
+		Format: "CSV",
+	}, &out)
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), usecase.ExportFormatCSV, response.Format)
+	assert.Equal(suite.T(), 3, response.Exported)
+	assert.Equal(suite.T(), 2, response.Chunks)
+
+	records, err := csv.NewReader(&out).ReadAll()
+	suite.Require().NoError(err)
+	suite.Require().Len(records, 4)
+	assert.Equal(suite.T(), []string{"position_id", "latitude", "longitude", "sector_id", "recorded_at"}, records[0])
+	assert.Equal(suite.T(), "pos-1", records[1][0])
+	assert.Equal(suite.T(), "-23.55052", records[1][1])
+	assert.Equal(suite.T(), suite.start.Format(time.RFC3339Nano), records[1][4])
+	assert.Equal(suite.T(), "pos-3", records[3][0])
+}
+
+// TestExport_JSONArray testa que a saída JSON é um array válido
+func (suite *ExportPositionHistoryUseCaseTestSuite) TestExport_JSONArray() {
+	suite.mockThreePositions()
+	var out bytes.Buffer
+
+	_, err := suite.useCase.Execute(suite.ctx, usecase.ExportPositionHistoryRequest{UserID: "user123"}, &out)
+	suite.Require().NoError(err)
+
+	var items []usecase.PositionExportItem
+	suite.Require().NoError(json.Unmarshal(out.Bytes(), &items))
+	suite.Require().Len(items, 3)
+	assert.Equal(suite.T(), "pos-2", items[1].PositionID)
+	assert.True(suite.T(), items[1].RecordedAt.Equal(suite.start.Add(time.Minute)))
+}
+
+// TestExport_EmptyHistory testa que um histórico vazio ainda gera um arquivo válido
+func (suite *ExportPositionHistoryUseCaseTestSuite) TestExport_EmptyHistory() {
+	user, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+	suite.userRepo.On("FindByID", mock.Anything, *suite.userID).Return(user, nil)
+	suite.positionRepo.On("FindHistoryByUserIDAfter", mock.Anything, *suite.userID, (*repository.HistoryCursor)(nil), 2).
+		Return([]*entity.Position{}, nil)
+	suite.logger.On("Info", "Position history exported", mock.Anything).Return()
+
+	var out bytes.Buffer
+	response, err := suite.useCase.Execute(suite.ctx, usecase.ExportPositionHistoryRequest{UserID: "user123"}, &out)
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, response.Exported)
+	assert.Equal(suite.T(), "[]\n", out.String())
+}
+
+// TestExport_UnsupportedFormat testa que o formato é rejeitado antes de qualquer escrita
+func (suite *ExportPositionHistoryUseCaseTestSuite) TestExport_UnsupportedFormat() {
+	var out bytes.Buffer
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.ExportPositionHistoryRequest{
+		UserID: "user123",
+		Format: "xml",
+	}, &out)
+
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, usecase.ErrUnsupportedExportFormat)
+	assert.Zero(suite.T(), out.Len())
+}
+
+// TestExport_UserNotFound testa que usuário inexistente não escreve nada
+func (suite *ExportPositionHistoryUseCaseTestSuite) TestExport_UserNotFound() {
+	suite.userRepo.On("FindByID", mock.Anything, *suite.userID).Return(nil, entity.ErrUserIDNotFound)
+	suite.logger.On("Error", "User not found", mock.Anything).Return()
+
+	var out bytes.Buffer
+	response, err := suite.useCase.Execute(suite.ctx, usecase.ExportPositionHistoryRequest{UserID: "user123"}, &out)
+
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, usecase.ErrUserNotFound)
+	assert.Zero(suite.T(), out.Len())
+}
+
+// TestExport_RepositoryError testa a falha na leitura de um chunk
+func (suite *ExportPositionHistoryUseCaseTestSuite) TestExport_RepositoryError() {
+	user, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+	suite.userRepo.On("FindByID", mock.Anything, *suite.userID).Return(user, nil)
+	suite.positionRepo.On("FindHistoryByUserIDAfter", mock.Anything, *suite.userID, (*repository.HistoryCursor)(nil), 2).
+		Return(nil, errors.New("database error"))
+	suite.logger.On("Error", "Failed to read position history for export", mock.Anything).Return()
+
+	var out bytes.Buffer
+	response, err := suite.useCase.Execute(suite.ctx, usecase.ExportPositionHistoryRequest{UserID: "user123"}, &out)
+
+	assert.Nil(suite.T(), response)
+	assert.Error(suite.T(), err)
+}
+
+// TestExportPositionHistoryUseCase executa toda a suite de testes
+func TestExportPositionHistoryUseCase(t *testing.T) {
+	suite.Run(t, new(ExportPositionHistoryUseCaseTestSuite))
+}
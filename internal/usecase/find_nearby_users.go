@@ -3,11 +3,16 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/vitao/geolocation-tracker/internal/domain/entity"
 	"github.com/vitao/geolocation-tracker/internal/domain/repository"
 	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/pkg/config"
 	"github.com/vitao/geolocation-tracker/pkg/logger"
+	"github.com/vitao/geolocation-tracker/pkg/metrics"
+	"golang.org/x/sync/singleflight"
 )
 
 // FindNearbyUsersRequest representa os dados de entrada
@@ -16,19 +21,51 @@ type FindNearbyUsersRequest struct {
 	Latitude   float64 `json:"latitude" validate:"required,min=-90,max=90"`
 	Longitude  float64 `json:"longitude" validate:"required,min=-180,max=180"`
 	RadiusM    float64 `json:"radius_meters" validate:"required,min=1,max=50000"` // Máximo 50km
-	MaxResults int     `json:"max_results" validate:"min=1,max=100"`              // Máximo 100 resultados
+	MaxResults int     `json:"max_results" validate:"omitempty,min=0"`            // 0 usa o padrão; acima do teto é reduzido
+
+	// Adaptive começa em um raio pequeno e dobra até RadiusM ou até achar MinUsers usuários
+	Adaptive bool `json:"adaptive"`
+	MinUsers int  `json:"min_users" validate:"omitempty,min=1,max=100"`
+
+	// MaxAgeSeconds descarta usuários vistos há mais tempo que isso (0 não filtra)
+	// Limitado a 24h, a idade máxima padrão de novas posições (entity.DefaultMaxPositionAgeHours)
+	MaxAgeSeconds int `json:"max_age_seconds" validate:"omitempty,min=1,max=86400"`
 }
 
+const (
+	// AdaptiveNearbyStartRadiusM é o raio inicial da busca adaptativa
+	AdaptiveNearbyStartRadiusM = 100.0
+	// DefaultAdaptiveNearbyMinUsers é o K padrão da busca adaptativa
+	DefaultAdaptiveNearbyMinUsers = 5
+
+	// DefaultNearbyMaxResults é o max_results usado quando nem o cliente nem a config informam um
+	DefaultNearbyMaxResults = 20
+	// MaxNearbyMaxResults é o teto de max_results quando NEARBY_MAX_RESULTS não está configurado
+	MaxNearbyMaxResults = 100
+)
+
 // NearbyUserResponse representa um usuário próximo
 type NearbyUserResponse struct {
-	UserID     string  `json:"user_id"`
-	UserName   string  `json:"user_name"`
-	PositionID string  `json:"position_id"`
-	Latitude   float64 `json:"latitude"`
-	Longitude  float64 `json:"longitude"`
-	SectorID   string  `json:"sector_id"`
-	DistanceM  float64 `json:"distance_meters"`
-	Age        string  `json:"age"` // Ex: "5m30s"
+	UserID     string    `json:"user_id"`
+	UserName   string    `json:"user_name"`
+	PositionID string    `json:"position_id"`
+	Latitude   float64   `json:"latitude"`
+	Longitude  float64   `json:"longitude"`
+	SectorID   string    `json:"sector_id"`
+	DistanceM  float64   `json:"distance_meters"`
+	RecordedAt time.Time `json:"recorded_at"`
+	AgeSeconds int64     `json:"age_seconds"` // Para filtros como "vistos nos últimos 60s"
+	Age        string    `json:"age"`         // Ex: "5m30s"
+}
+
+// refreshAge recalcula a idade a partir de RecordedAt (respostas em cache envelhecem)
+func (r *NearbyUserResponse) refreshAge() {
+	if r.RecordedAt.IsZero() {
+		return
+	}
+	age := time.Since(r.RecordedAt)
+	r.AgeSeconds = int64(age / time.Second)
+	r.Age = age.String()
 }
 
 // FindNearbyUsersResponse representa a resposta
@@ -36,6 +73,8 @@ type FindNearbyUsersResponse struct {
 	SearchCenter NearbyUserResponse   `json:"search_center"`
 	NearbyUsers  []NearbyUserResponse `json:"nearby_users"`
 	TotalFound   int                  `json:"total_found"`
+	RadiusUsedM  float64              `json:"radius_used_meters"`
+	MaxResults   int                  `json:"max_results"` // Limite efetivo aplicado (padrão ou teto do servidor)
 	Message      string               `json:"message"`
 }
 
@@ -44,40 +83,122 @@ type FindNearbyUsersUseCase struct {
 	userRepo     repository.UserRepository
 	positionRepo repository.PositionRepository
 	cache        CacheInterface
+	metrics      metrics.Collector
 	logger       logger.Logger
+
+	// minResultsToCache evita cachear resultados vazios/quase vazios
+	minResultsToCache int
+
+	// nearbyGridMeters aproxima o centro da chave de cache para uma grade (0 usa a coordenada exata)
+	nearbyGridMeters float64
+
+	// defaultMaxResults/maxResultsCap são o padrão e o teto de max_results
+	defaultMaxResults int
+	maxResultsCap     int
+
+	// flights agrupa buscas simultâneas iguais no banco (proteção contra cache stampede)
+	flights singleflight.Group
 }
 
+// nearbyFlightTimeout limita a busca compartilhada, que não herda o cancelamento da requisição
+const nearbyFlightTimeout = 10 * time.Second
+
 // NewFindNearbyUsersUseCase cria uma nova instância do use case
 func NewFindNearbyUsersUseCase(
 	userRepo repository.UserRepository,
 	positionRepo repository.PositionRepository,
 	cache CacheInterface,
+	collector metrics.Collector,
 	logger logger.Logger,
+	cfg *config.Config,
 ) *FindNearbyUsersUseCase {
+	maxResultsCap := cfg.Nearby.MaxResults
+	if maxResultsCap <= 0 {
+		maxResultsCap = MaxNearbyMaxResults
+	}
+	defaultMaxResults := cfg.Nearby.DefaultMaxResults
+	if defaultMaxResults <= 0 {
+		defaultMaxResults = DefaultNearbyMaxResults
+	}
+	if defaultMaxResults > maxResultsCap {
+		defaultMaxResults = maxResultsCap
+	}
+
 	return &FindNearbyUsersUseCase{
-		userRepo:     userRepo,
-		positionRepo: positionRepo,
-		cache:        cache,
-		logger:       logger,
+		userRepo:          userRepo,
+		positionRepo:      positionRepo,
+		cache:             cache,
+		metrics:           collector,
+		logger:            logger,
+		minResultsToCache: cfg.Cache.NearbyMinResultsToCache,
+		nearbyGridMeters:  cfg.Cache.NearbyGridMeters,
+		defaultMaxResults: defaultMaxResults,
+		maxResultsCap:     maxResultsCap,
+	}
+}
+
+// resolveMaxResults aplica o padrão quando max_results não é informado (0) e reduz valores
+// acima do teto configurado; negativos são rejeitados
+func (uc *FindNearbyUsersUseCase) resolveMaxResults(requested int) (int, error) {
+	switch {
+	case requested < 0:
+		return 0, fmt.Errorf("%w: max_results must not be negative", ErrInvalidInput)
+	case requested == 0:
+		return uc.defaultMaxResults, nil
+	case requested > uc.maxResultsCap:
+		return uc.maxResultsCap, nil
+	default:
+		return requested, nil
 	}
 }
 
 // Execute executa o use case de buscar usuários próximos
 func (uc *FindNearbyUsersUseCase) Execute(ctx context.Context, req FindNearbyUsersRequest) (*FindNearbyUsersResponse, error) {
+	start := time.Now()
+	response, err := uc.execute(ctx, req)
+	uc.metrics.ObserveUseCase("find_nearby_users", time.Since(start), err)
+	return response, err
+}
+
+// execute contém o fluxo do use case; Execute apenas o instrumenta
+func (uc *FindNearbyUsersUseCase) execute(ctx context.Context, req FindNearbyUsersRequest) (*FindNearbyUsersResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	// 0. Resolver o limite efetivo antes de qualquer leitura (vale também para o cache)
+	maxResults, err := uc.resolveMaxResults(req.MaxResults)
+	if err != nil {
+		log.Error("Invalid max_results", map[string]interface{}{
+			"max_results": req.MaxResults,
+			"error":       err.Error(),
+		})
+		return nil, err
+	}
+
 	// 1. Tentar buscar no cache primeiro (apenas para coordenadas fixas, sem considerar user_id)
+	// A busca adaptativa não usa cache: o raio efetivo depende de K e da densidade no momento
 	var cachedResponse FindNearbyUsersResponse
-	if err := uc.cache.GetCachedNearbyUsers(ctx, req.Latitude, req.Longitude, req.RadiusM, &cachedResponse); err == nil {
+	if !req.Adaptive && uc.lookupCache(ctx, req, &cachedResponse) {
 		// Ajustar o search center para o usuário atual se ele estiver nos resultados
 		searchCenter, nearbyUsers := uc.adjustSearchCenterFromCache(cachedResponse, req.UserID)
+		if uc.nearbyGridMeters > 0 {
+			// A entrada pode ter sido gravada por outro centro da mesma célula da grade
+			searchCenter, nearbyUsers = recenterCachedUsers(req, searchCenter, nearbyUsers)
+		}
+		nearbyUsers = filterByMaxAge(nearbyUsers, req.MaxAgeSeconds)
+		if len(nearbyUsers) > maxResults {
+			nearbyUsers = nearbyUsers[:maxResults]
+		}
 
 		response := &FindNearbyUsersResponse{
 			SearchCenter: searchCenter,
 			NearbyUsers:  nearbyUsers,
 			TotalFound:   len(nearbyUsers),
+			RadiusUsedM:  req.RadiusM,
+			MaxResults:   maxResults,
 			Message:      fmt.Sprintf("Found %d users within %.0fm radius", len(nearbyUsers), req.RadiusM),
 		}
 
-		uc.logger.Info("Cache hit for nearby users search", map[string]interface{}{
+		log.Info("Cache hit for nearby users search", map[string]interface{}{
 			"user_id":     req.UserID,
 			"latitude":    req.Latitude,
 			"longitude":   req.Longitude,
@@ -92,135 +213,109 @@ func (uc *FindNearbyUsersUseCase) Execute(ctx context.Context, req FindNearbyUse
 	// 2. Cache miss - executar busca completa
 	userIDPtr, err := entity.NewUserID(req.UserID)
 	if err != nil {
-		uc.logger.Error("Invalid user ID", map[string]interface{}{
+		log.Error("Invalid user ID", map[string]interface{}{
 			"user_id": req.UserID,
 			"error":   err.Error(),
 		})
-		return nil, fmt.Errorf("invalid user ID: %w", err)
+		return nil, fmt.Errorf("%w: invalid user ID: %w", ErrInvalidInput, err)
 	}
 
 	userID := *userIDPtr
 	_, err = uc.userRepo.FindByID(ctx, userID) // Apenas validar que existe
 	if err != nil {
-		uc.logger.Error("User not found", map[string]interface{}{
+		log.Error("User not found", map[string]interface{}{
 			"user_id": req.UserID,
 			"error":   err.Error(),
 		})
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, userLookupError(req.UserID, err)
 	}
 
 	// 3. Validar coordenadas de busca
 	searchCoordinate, err := valueobject.NewCoordinate(req.Latitude, req.Longitude)
 	if err != nil {
-		uc.logger.Error("Invalid search coordinates", map[string]interface{}{
+		log.Error("Invalid search coordinates", map[string]interface{}{
 			"latitude":  req.Latitude,
 			"longitude": req.Longitude,
 			"error":     err.Error(),
 		})
-		return nil, fmt.Errorf("invalid search coordinates: %w", err)
+		return nil, fmt.Errorf("%w: invalid search coordinates: %w", ErrInvalidInput, err)
 	}
 
-	// 4. Definir valores padrão
-	maxResults := req.MaxResults
-	if maxResults <= 0 {
-		maxResults = 20 // Padrão: 20 resultados
+	// 4. Buscar usuários próximos
+	// A busca fixa é compartilhada entre requisições simultâneas iguais (evita stampede no cache miss)
+	radius := req.RadiusM
+	var candidates []NearbyUserResponse
+	if req.Adaptive {
+		var nearbyPositions []*repository.NearbyPosition
+		nearbyPositions, radius, err = uc.findNearbyAdaptive(ctx, searchCoordinate, userID, req, maxResults)
+		if err == nil {
+			candidates = uc.toNearbyUsers(ctx, searchCoordinate, nearbyPositions)
+		}
+	} else {
+		candidates, err = uc.findNearbyShared(ctx, searchCoordinate, radius, maxResults+1)
 	}
-
-	// 5. Buscar posições próximas
-	nearbyPositions, err := uc.positionRepo.FindNearby(ctx, searchCoordinate, req.RadiusM, maxResults+1)
 	if err != nil {
-		uc.logger.Error("Failed to find nearby positions", map[string]interface{}{
+		log.Error("Failed to find nearby positions", map[string]interface{}{
 			"latitude":    req.Latitude,
 			"longitude":   req.Longitude,
-			"radius":      req.RadiusM,
+			"radius":      radius,
 			"max_results": maxResults,
 			"error":       err.Error(),
 		})
 		return nil, fmt.Errorf("failed to find nearby positions: %w", err)
 	}
 
-	// 6. Processar resultados
-	var nearbyUsers []NearbyUserResponse
+	// 5. Separar o usuário da busca (centro) dos demais
+	// candidates pode ser compartilhado com outras requisições: apenas lido aqui
+	nearbyUsers := make([]NearbyUserResponse, 0, len(candidates)) // Sempre [] no JSON, nunca null
 	searchCenterSet := false
 	var searchCenter NearbyUserResponse
 
-	for _, position := range nearbyPositions {
-		// Buscar dados do usuário
-		positionUser, err := uc.userRepo.FindByID(ctx, position.UserID())
-		if err != nil {
-			positionID := position.ID()
-			userIDValue := position.UserID()
-			uc.logger.Error("User not found for position", map[string]interface{}{
-				"position_id": positionID.String(),
-				"user_id":     userIDValue.String(),
-			})
-			continue
-		}
-
-		// Calcular distância
-		positionCoordinate := position.Coordinate()
-		distance := searchCoordinate.DistanceTo(positionCoordinate)
-
-		// Criar resposta
-		userIDValue := positionUser.ID()
-		positionIDValue := position.ID()
-		nearbyUser := NearbyUserResponse{
-			UserID:     userIDValue.String(),
-			UserName:   positionUser.Name(),
-			PositionID: positionIDValue.String(),
-			Latitude:   positionCoordinate.Latitude(),
-			Longitude:  positionCoordinate.Longitude(),
-			SectorID:   position.Sector().ID(),
-			DistanceM:  distance,
-			Age:        position.Age().String(),
-		}
-
-		// Se é o usuário da busca, definir como centro
-		positionUserID := position.UserID()
-		if positionUserID.Equals(&userID) && !searchCenterSet {
-			searchCenter = nearbyUser
+	for _, candidate := range candidates {
+		if candidate.UserID == userID.String() && !searchCenterSet {
+			searchCenter = candidate
 			searchCenterSet = true
 		} else {
-			nearbyUsers = append(nearbyUsers, nearbyUser)
+			nearbyUsers = append(nearbyUsers, candidate)
 		}
 	}
 
-	// 7. Limitar resultados
+	// 6. Limitar resultados
 	if len(nearbyUsers) > maxResults {
 		nearbyUsers = nearbyUsers[:maxResults]
 	}
 
-	// 8. Preparar resposta para cache
+	// 7. Preparar resposta para cache
 	response := &FindNearbyUsersResponse{
 		SearchCenter: searchCenter,
 		NearbyUsers:  nearbyUsers,
 		TotalFound:   len(nearbyUsers),
-		Message:      fmt.Sprintf("Found %d users within %.0fm radius", len(nearbyUsers), req.RadiusM),
+		RadiusUsedM:  radius,
+		MaxResults:   maxResults,
+		Message:      fmt.Sprintf("Found %d users within %.0fm radius", len(nearbyUsers), radius),
 	}
 
-	// 9. Salvar no cache (sem o search center específico, para reutilização)
-	cacheableResponse := FindNearbyUsersResponse{
-		NearbyUsers: append(nearbyUsers, searchCenter), // Incluir todos os usuários
-		TotalFound:  len(nearbyUsers) + 1,
-		Message:     response.Message,
+	// 8. Salvar no cache (sem o search center específico, para reutilização)
+	// O search center só entra na lista se foi encontrado (evita um usuário zerado no cache)
+	if !req.Adaptive {
+		uc.cacheNearbyResult(ctx, req, response, searchCenter, searchCenterSet)
 	}
-	if cacheErr := uc.cache.CacheNearbyUsers(ctx, req.Latitude, req.Longitude, req.RadiusM, cacheableResponse); cacheErr != nil {
-		uc.logger.Error("Failed to cache nearby users", map[string]interface{}{
-			"latitude":  req.Latitude,
-			"longitude": req.Longitude,
-			"radius":    req.RadiusM,
-			"error":     cacheErr.Error(),
-		})
-		// Não falhar a operação por erro de cache
+
+	// 9. Filtrar por idade depois do cache, para a entrada servir a qualquer max_age_seconds
+	if req.MaxAgeSeconds > 0 {
+		response.NearbyUsers = filterByMaxAge(response.NearbyUsers, req.MaxAgeSeconds)
+		response.TotalFound = len(response.NearbyUsers)
+		response.Message = fmt.Sprintf("Found %d users within %.0fm radius", response.TotalFound, radius)
 	}
 
 	// 10. Log de sucesso
-	uc.logger.Info("Nearby users search completed from database", map[string]interface{}{
+	log.Info("Nearby users search completed from database", map[string]interface{}{
 		"user_id":     req.UserID,
 		"latitude":    req.Latitude,
 		"longitude":   req.Longitude,
-		"radius":      req.RadiusM,
-		"total_found": len(nearbyUsers),
+		"radius":      radius,
+		"adaptive":    req.Adaptive,
+		"total_found": response.TotalFound,
 		"has_center":  searchCenterSet,
 		"source":      "database",
 	})
@@ -228,13 +323,245 @@ func (uc *FindNearbyUsersUseCase) Execute(ctx context.Context, req FindNearbyUse
 	return response, nil
 }
 
+// lookupCache consulta o cache da busca e registra hit/miss
+func (uc *FindNearbyUsersUseCase) lookupCache(ctx context.Context, req FindNearbyUsersRequest, dest *FindNearbyUsersResponse) bool {
+	lat, lng := uc.cacheKeyCoordinate(req)
+	hit := uc.cache.GetCachedNearbyUsers(ctx, lat, lng, req.RadiusM, dest) == nil
+	uc.metrics.ObserveCacheLookup("nearby_users", hit)
+	return hit
+}
+
+// findNearbyShared busca e monta os usuários próximos de uma coordenada com single-flight:
+// requisições simultâneas com a mesma chave aguardam a primeira e reutilizam o resultado
+// A busca roda desacoplada do cancelamento de quem a iniciou (com timeout próprio), para que
+// um cliente que desistiu não derrube as demais requisições que aguardam a mesma chave
+func (uc *FindNearbyUsersUseCase) findNearbyShared(ctx context.Context, center *valueobject.Coordinate, radius float64, limit int) ([]NearbyUserResponse, error) {
+	key := fmt.Sprintf("%.6f:%.6f:%.0f:%d", center.Latitude(), center.Longitude(), radius, limit)
+
+	results := uc.flights.DoChan(key, func() (interface{}, error) {
+		flightCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), nearbyFlightTimeout)
+		defer cancel()
+
+		nearbyPositions, err := uc.positionRepo.FindNearby(flightCtx, center, radius, limit)
+		if err != nil {
+			return nil, err
+		}
+		return uc.toNearbyUsers(flightCtx, center, nearbyPositions), nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-results:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return result.Val.([]NearbyUserResponse), nil
+	}
+}
+
+// toNearbyUsers monta as respostas das posições encontradas, descartando usuários que não existem mais
+func (uc *FindNearbyUsersUseCase) toNearbyUsers(ctx context.Context, center *valueobject.Coordinate, nearbyPositions []*repository.NearbyPosition) []NearbyUserResponse {
+	users := make([]NearbyUserResponse, 0, len(nearbyPositions))
+	for _, nearby := range nearbyPositions {
+		if nearbyUser, ok := uc.toNearbyUser(ctx, center, nearby); ok {
+			users = append(users, nearbyUser)
+		}
+	}
+	return users
+}
+
+// cacheKeyCoordinate retorna a coordenada usada na chave de cache: a exata ou a aproximada
+// para a grade configurada. Coordenadas inválidas seguem exatas (a busca no banco as rejeita)
+func (uc *FindNearbyUsersUseCase) cacheKeyCoordinate(req FindNearbyUsersRequest) (float64, float64) {
+	if uc.nearbyGridMeters <= 0 {
+		return req.Latitude, req.Longitude
+	}
+
+	coordinate, err := valueobject.NewCoordinate(req.Latitude, req.Longitude)
+	if err != nil {
+		return req.Latitude, req.Longitude
+	}
+
+	snapped := coordinate.Snap(uc.nearbyGridMeters)
+	return snapped.Latitude(), snapped.Longitude()
+}
+
+// recenterCachedUsers recalcula as distâncias de uma resposta em cache para o centro exato da busca,
+// descarta quem ficou fora do raio e reordena por distância
+func recenterCachedUsers(req FindNearbyUsersRequest, searchCenter NearbyUserResponse, users []NearbyUserResponse) (NearbyUserResponse, []NearbyUserResponse) {
+	if searchCenter.UserID != "" {
+		searchCenter.DistanceM = valueobject.CalculateDistance(req.Latitude, req.Longitude, searchCenter.Latitude, searchCenter.Longitude)
+	}
+
+	inRadius := make([]NearbyUserResponse, 0, len(users))
+	for _, user := range users {
+		user.DistanceM = valueobject.CalculateDistance(req.Latitude, req.Longitude, user.Latitude, user.Longitude)
+		if user.DistanceM <= req.RadiusM {
+			inRadius = append(inRadius, user)
+		}
+	}
+
+	sort.SliceStable(inRadius, func(i, j int) bool {
+		return inRadius[i].DistanceM < inRadius[j].DistanceM
+	})
+
+	return searchCenter, inRadius
+}
+
+// toNearbyUser monta a resposta de uma posição com os dados do usuário e a distância ao centro
+// (a do PostGIS quando disponível). Retorna false (e registra o erro) quando o usuário não existe mais
+func (uc *FindNearbyUsersUseCase) toNearbyUser(ctx context.Context, center *valueobject.Coordinate, nearby *repository.NearbyPosition) (NearbyUserResponse, bool) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	position := nearby.Position
+	positionUser, err := uc.userRepo.FindByID(ctx, position.UserID())
+	if err != nil {
+		positionID := position.ID()
+		userIDValue := position.UserID()
+		log.Error("User not found for position", map[string]interface{}{
+			"position_id": positionID.String(),
+			"user_id":     userIDValue.String(),
+		})
+		return NearbyUserResponse{}, false
+	}
+
+	positionCoordinate := position.Coordinate()
+	userIDValue := positionUser.ID()
+	positionIDValue := position.ID()
+	age := position.Age()
+	return NearbyUserResponse{
+		UserID:     userIDValue.String(),
+		UserName:   positionUser.Name(),
+		PositionID: positionIDValue.String(),
+		Latitude:   positionCoordinate.Latitude(),
+		Longitude:  positionCoordinate.Longitude(),
+		SectorID:   position.Sector().ID(),
+		DistanceM:  nearby.Distance(center),
+		RecordedAt: position.RecordedAt().Time(),
+		AgeSeconds: int64(age / time.Second),
+		Age:        age.String(),
+	}, true
+}
+
+// cacheNearbyResult salva o resultado incluindo o search center, para reaproveitar com outros user_id
+func (uc *FindNearbyUsersUseCase) cacheNearbyResult(ctx context.Context, req FindNearbyUsersRequest, response *FindNearbyUsersResponse, searchCenter NearbyUserResponse, searchCenterSet bool) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	nearbyUsers := response.NearbyUsers
+	cachedUsers := make([]NearbyUserResponse, 0, len(nearbyUsers)+1)
+	cachedUsers = append(cachedUsers, nearbyUsers...)
+	if searchCenterSet {
+		cachedUsers = append(cachedUsers, searchCenter)
+	}
+
+	cacheableResponse := FindNearbyUsersResponse{
+		NearbyUsers: cachedUsers, // Incluir todos os usuários
+		TotalFound:  len(cachedUsers),
+		Message:     response.Message,
+	}
+	if len(cachedUsers) < uc.minResultsToCache {
+		// Resultado vazio em cache esconderia usuários que chegarem em seguida
+		log.Debug("Skipping cache for nearby users search", map[string]interface{}{
+			"latitude":    req.Latitude,
+			"longitude":   req.Longitude,
+			"radius":      req.RadiusM,
+			"total_found": len(cachedUsers),
+			"min_results": uc.minResultsToCache,
+		})
+		return
+	}
+
+	lat, lng := uc.cacheKeyCoordinate(req)
+	if cacheErr := uc.cache.CacheNearbyUsers(ctx, lat, lng, req.RadiusM, cacheableResponse); cacheErr != nil {
+		log.Error("Failed to cache nearby users", map[string]interface{}{
+			"latitude":  req.Latitude,
+			"longitude": req.Longitude,
+			"radius":    req.RadiusM,
+			"error":     cacheErr.Error(),
+		})
+		// Não falhar a operação por erro de cache
+	}
+}
+
+// findNearbyAdaptive dobra o raio a partir de AdaptiveNearbyStartRadiusM até req.RadiusM,
+// parando assim que encontrar MinUsers usuários além do solicitante
+func (uc *FindNearbyUsersUseCase) findNearbyAdaptive(
+	ctx context.Context,
+	center *valueobject.Coordinate,
+	userID entity.UserID,
+	req FindNearbyUsersRequest,
+	maxResults int,
+) ([]*repository.NearbyPosition, float64, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	minUsers := req.MinUsers
+	if minUsers <= 0 {
+		minUsers = DefaultAdaptiveNearbyMinUsers
+	}
+
+	radius := AdaptiveNearbyStartRadiusM
+	if radius > req.RadiusM {
+		radius = req.RadiusM
+	}
+
+	for {
+		positions, err := uc.positionRepo.FindNearby(ctx, center, radius, maxResults+1)
+		if err != nil {
+			return nil, radius, err
+		}
+
+		found := 0
+		for _, nearby := range positions {
+			positionUserID := nearby.Position.UserID()
+			if !positionUserID.Equals(&userID) {
+				found++
+			}
+		}
+
+		if found >= minUsers || radius >= req.RadiusM {
+			log.Debug("Adaptive nearby search finished", map[string]interface{}{
+				"radius":    radius,
+				"found":     found,
+				"min_users": minUsers,
+			})
+			return positions, radius, nil
+		}
+
+		radius *= 2
+		if radius > req.RadiusM {
+			radius = req.RadiusM
+		}
+	}
+}
+
+// exceedsMaxAge verifica se a idade passa do limite em segundos (0 não filtra)
+func exceedsMaxAge(ageSeconds int64, maxAgeSeconds int) bool {
+	return maxAgeSeconds > 0 && ageSeconds > int64(maxAgeSeconds)
+}
+
+// filterByMaxAge remove os usuários vistos há mais de maxAgeSeconds
+func filterByMaxAge(users []NearbyUserResponse, maxAgeSeconds int) []NearbyUserResponse {
+	if maxAgeSeconds <= 0 {
+		return users
+	}
+	fresh := make([]NearbyUserResponse, 0, len(users))
+	for _, user := range users {
+		if !exceedsMaxAge(user.AgeSeconds, maxAgeSeconds) {
+			fresh = append(fresh, user)
+		}
+	}
+	return fresh
+}
+
 // adjustSearchCenterFromCache ajusta o search center baseado no usuário atual
 func (uc *FindNearbyUsersUseCase) adjustSearchCenterFromCache(cachedResponse FindNearbyUsersResponse, userID string) (NearbyUserResponse, []NearbyUserResponse) {
 	var searchCenter NearbyUserResponse
-	var nearbyUsers []NearbyUserResponse
+	nearbyUsers := make([]NearbyUserResponse, 0, len(cachedResponse.NearbyUsers))
 
 	// Procurar o usuário nos resultados cached
 	for _, user := range cachedResponse.NearbyUsers {
+		user.refreshAge()
 		if user.UserID == userID {
 			searchCenter = user
 		} else {
@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+	"github.com/vitao/geolocation-tracker/pkg/metrics"
+)
+
+// Limites da busca de proximidade em lote
+const (
+	MaxNearbyBatchCenters = 50 // Centros aceitos por requisição
+	MaxNearbyBatchRadiusM = 50000.0
+
+	// nearbyBatchWorkers limita as queries PostGIS simultâneas (o pool do banco tem 25 conexões)
+	nearbyBatchWorkers = 5
+)
+
+// ErrEmptyNearbyBatch indica um lote sem centros
+var ErrEmptyNearbyBatch = errors.New("batch must contain at least one center")
+
+// ErrNearbyBatchTooLarge indica um lote acima de MaxNearbyBatchCenters
+var ErrNearbyBatchTooLarge = fmt.Errorf("batch must contain at most %d centers", MaxNearbyBatchCenters)
+
+// NearbyBatchCenter representa um ponto de referência do lote
+type NearbyBatchCenter struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	RadiusM   float64 `json:"radius_meters"`
+}
+
+// FindNearbyUsersBatchRequest representa os dados de entrada
+type FindNearbyUsersBatchRequest struct {
+	Centers    []NearbyBatchCenter `json:"centers"`
+	MaxResults int                 `json:"max_results"` // Por centro; mesmo padrão e teto da busca unitária
+}
+
+// NearbyBatchResult representa o resultado de um centro, na mesma posição do pedido
+type NearbyBatchResult struct {
+	Index       int                  `json:"index"`
+	Latitude    float64              `json:"latitude"`
+	Longitude   float64              `json:"longitude"`
+	RadiusM     float64              `json:"radius_meters"`
+	NearbyUsers []NearbyUserResponse `json:"nearby_users"`
+	TotalFound  int                  `json:"total_found"`
+	Error       string               `json:"error,omitempty"`
+}
+
+// FindNearbyUsersBatchResponse representa a resposta do lote
+type FindNearbyUsersBatchResponse struct {
+	Results []NearbyBatchResult `json:"results"`
+	Failed  int                 `json:"failed"`
+	Message string              `json:"message"`
+}
+
+// FindNearbyUsersBatchUseCase executa buscas de proximidade para vários centros em paralelo
+// Centros inválidos ou com falha são reportados individualmente sem rejeitar o lote inteiro
+type FindNearbyUsersBatchUseCase struct {
+	positionRepo repository.PositionRepository
+	metrics      metrics.Collector
+	logger       logger.Logger
+
+	// single reaproveita a montagem das respostas do fluxo unitário
+	single *FindNearbyUsersUseCase
+}
+
+// NewFindNearbyUsersBatchUseCase cria uma nova instância do use case
+func NewFindNearbyUsersBatchUseCase(
+	userRepo repository.UserRepository,
+	positionRepo repository.PositionRepository,
+	cache CacheInterface,
+	collector metrics.Collector,
+	logger logger.Logger,
+	cfg *config.Config,
+) *FindNearbyUsersBatchUseCase {
+	return &FindNearbyUsersBatchUseCase{
+		positionRepo: positionRepo,
+		metrics:      collector,
+		logger:       logger,
+		single:       NewFindNearbyUsersUseCase(userRepo, positionRepo, cache, collector, logger, cfg),
+	}
+}
+
+// Execute executa o use case de busca de proximidade em lote
+func (uc *FindNearbyUsersBatchUseCase) Execute(ctx context.Context, req FindNearbyUsersBatchRequest) (*FindNearbyUsersBatchResponse, error) {
+	start := time.Now()
+	response, err := uc.execute(ctx, req)
+	uc.metrics.ObserveUseCase("find_nearby_users_batch", time.Since(start), err)
+	return response, err
+}
+
+// execute contém o fluxo do use case; Execute apenas o instrumenta
+func (uc *FindNearbyUsersBatchUseCase) execute(ctx context.Context, req FindNearbyUsersBatchRequest) (*FindNearbyUsersBatchResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	if len(req.Centers) == 0 {
+		return nil, ErrEmptyNearbyBatch
+	}
+	if len(req.Centers) > MaxNearbyBatchCenters {
+		return nil, ErrNearbyBatchTooLarge
+	}
+
+	maxResults, err := uc.single.resolveMaxResults(req.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Distribuir os centros entre um número fixo de workers
+	results := make([]NearbyBatchResult, len(req.Centers))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+
+	workers := nearbyBatchWorkers
+	if workers > len(req.Centers) {
+		workers = len(req.Centers)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = uc.searchCenter(ctx, i, req.Centers[i], maxResults)
+			}
+		}()
+	}
+
+	for i := range req.Centers {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	// 2. Consolidar
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+
+	log.Info("Nearby batch search completed", map[string]interface{}{
+		"centers":     len(req.Centers),
+		"failed":      failed,
+		"max_results": maxResults,
+	})
+
+	return &FindNearbyUsersBatchResponse{
+		Results: results,
+		Failed:  failed,
+		Message: fmt.Sprintf("Searched %d centers (%d failed)", len(req.Centers), failed),
+	}, nil
+}
+
+// searchCenter executa a busca de um centro; erros ficam no próprio resultado
+func (uc *FindNearbyUsersBatchUseCase) searchCenter(ctx context.Context, index int, center NearbyBatchCenter, maxResults int) NearbyBatchResult {
+	log := logger.FromContext(ctx, uc.logger)
+
+	result := NearbyBatchResult{
+		Index:       index,
+		Latitude:    center.Latitude,
+		Longitude:   center.Longitude,
+		RadiusM:     center.RadiusM,
+		NearbyUsers: []NearbyUserResponse{}, // Sempre [] no JSON, nunca null
+	}
+
+	if center.RadiusM < 1 || center.RadiusM > MaxNearbyBatchRadiusM {
+		result.Error = fmt.Sprintf("radius_meters must be between 1 and %.0f", MaxNearbyBatchRadiusM)
+		return result
+	}
+
+	coordinate, err := valueobject.NewCoordinate(center.Latitude, center.Longitude)
+	if err != nil {
+		result.Error = fmt.Sprintf("invalid coordinates: %s", err.Error())
+		return result
+	}
+
+	positions, err := uc.positionRepo.FindNearby(ctx, coordinate, center.RadiusM, maxResults)
+	if err != nil {
+		log.Error("Failed to find nearby positions", map[string]interface{}{
+			"index":     index,
+			"latitude":  center.Latitude,
+			"longitude": center.Longitude,
+			"radius":    center.RadiusM,
+			"error":     err.Error(),
+		})
+		result.Error = fmt.Sprintf("failed to find nearby positions: %s", err.Error())
+		return result
+	}
+
+	for _, nearby := range positions {
+		if nearbyUser, ok := uc.single.toNearbyUser(ctx, coordinate, nearby); ok {
+			result.NearbyUsers = append(result.NearbyUsers, nearbyUser)
+		}
+	}
+	result.TotalFound = len(result.NearbyUsers)
+
+	return result
+}
@@ -0,0 +1,151 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+	"github.com/vitao/geolocation-tracker/pkg/metrics"
+)
+
+// FindNearbyUsersBatchUseCaseTestSuite define a suite de testes para FindNearbyUsersBatchUseCase
+type FindNearbyUsersBatchUseCaseTestSuite struct {
+	suite.Suite
+	userRepo     *mocks.MockUserRepository
+	positionRepo *mocks.MockPositionRepository
+	cache        *mocks.MockCache
+	logger       *mocks.MockLogger
+	useCase      *usecase.FindNearbyUsersBatchUseCase
+	ctx          context.Context
+}
+
+// SetupTest configura cada teste
+func (suite *FindNearbyUsersBatchUseCaseTestSuite) SetupTest() {
+	suite.userRepo = new(mocks.MockUserRepository)
+	suite.positionRepo = new(mocks.MockPositionRepository)
+	suite.cache = new(mocks.MockCache)
+	suite.logger = new(mocks.MockLogger)
+	suite.useCase = usecase.NewFindNearbyUsersBatchUseCase(suite.userRepo, suite.positionRepo, suite.cache, metrics.NewNoopCollector(), suite.logger, &config.Config{})
+	suite.ctx = context.Background()
+}
+
+// TearDownTest limpa após cada teste
+func (suite *FindNearbyUsersBatchUseCaseTestSuite) TearDownTest() {
+	suite.userRepo.AssertExpectations(suite.T())
+	suite.positionRepo.AssertExpectations(suite.T())
+	suite.cache.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// atCoordinate casa o argumento *valueobject.Coordinate de FindNearby pela latitude
+func atCoordinate(latitude float64) interface{} {
+	return mock.MatchedBy(func(coord *valueobject.Coordinate) bool {
+		return coord.Latitude() == latitude
+	})
+}
+
+// TestFindNearbyBatch_ResultsFollowRequestOrder testa que cada centro tem seu resultado na mesma posição
+func (suite *FindNearbyUsersBatchUseCaseTestSuite) TestFindNearbyBatch_ResultsFollowRequestOrder() {
+	// Arrange
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	user, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+	position, err := entity.NewPosition("pos-1", *userID, -23.550520, -46.633309, time.Now())
+	suite.Require().NoError(err)
+
+	suite.positionRepo.On("FindNearby", mock.Anything, atCoordinate(-23.550520), 500.0, 20).
+		Return(withoutDistance(position), nil)
+	suite.positionRepo.On("FindNearby", mock.Anything, atCoordinate(-22.906847), 1000.0, 20).
+		Return(withoutDistance(), nil)
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Return(user, nil)
+	suite.logger.On("Info", "Nearby batch search completed", mock.Anything).
+		Return()
+
+	request := usecase.FindNearbyUsersBatchRequest{
+		Centers: []usecase.NearbyBatchCenter{
+			{Latitude: -23.550520, Longitude: -46.633309, RadiusM: 500},
+			{Latitude: -22.906847, Longitude: -43.172897, RadiusM: 1000},
+			{Latitude: 120, Longitude: -46.633309, RadiusM: 500},
+			{Latitude: -23.550520, Longitude: -46.633309, RadiusM: 0},
+		},
+	}
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(response.Results, 4)
+	assert.Equal(suite.T(), 2, response.Failed)
+
+	for i, result := range response.Results {
+		assert.Equal(suite.T(), i, result.Index)
+		assert.Equal(suite.T(), request.Centers[i].Latitude, result.Latitude)
+	}
+
+	assert.Empty(suite.T(), response.Results[0].Error)
+	suite.Require().Len(response.Results[0].NearbyUsers, 1)
+	assert.Equal(suite.T(), "João Silva", response.Results[0].NearbyUsers[0].UserName)
+	assert.InDelta(suite.T(), 0, response.Results[0].NearbyUsers[0].DistanceM, 0.001)
+
+	assert.Empty(suite.T(), response.Results[1].Error)
+	assert.NotNil(suite.T(), response.Results[1].NearbyUsers)
+	assert.Equal(suite.T(), 0, response.Results[1].TotalFound)
+
+	assert.Contains(suite.T(), response.Results[2].Error, "invalid coordinates")
+	assert.Contains(suite.T(), response.Results[3].Error, "radius_meters")
+}
+
+// TestFindNearbyBatch_RepositoryErrorIsPerCenter testa que a falha de um centro não derruba o lote
+func (suite *FindNearbyUsersBatchUseCaseTestSuite) TestFindNearbyBatch_RepositoryErrorIsPerCenter() {
+	// Arrange
+	suite.positionRepo.On("FindNearby", mock.Anything, atCoordinate(-23.550520), 500.0, 5).
+		Return(nil, errors.New("connection refused"))
+	suite.positionRepo.On("FindNearby", mock.Anything, atCoordinate(-22.906847), 500.0, 5).
+		Return(withoutDistance(), nil)
+	suite.logger.On("Error", "Failed to find nearby positions", mock.Anything).
+		Return()
+	suite.logger.On("Info", "Nearby batch search completed", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.FindNearbyUsersBatchRequest{
+		Centers: []usecase.NearbyBatchCenter{
+			{Latitude: -23.550520, Longitude: -46.633309, RadiusM: 500},
+			{Latitude: -22.906847, Longitude: -43.172897, RadiusM: 500},
+		},
+		MaxResults: 5,
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, response.Failed)
+	assert.Contains(suite.T(), response.Results[0].Error, "connection refused")
+	assert.Empty(suite.T(), response.Results[1].Error)
+}
+
+// TestFindNearbyBatch_SizeLimits testa lote vazio e acima do limite
+func (suite *FindNearbyUsersBatchUseCaseTestSuite) TestFindNearbyBatch_SizeLimits() {
+	_, err := suite.useCase.Execute(suite.ctx, usecase.FindNearbyUsersBatchRequest{})
+	assert.ErrorIs(suite.T(), err, usecase.ErrEmptyNearbyBatch)
+
+	centers := make([]usecase.NearbyBatchCenter, usecase.MaxNearbyBatchCenters+1)
+	_, err = suite.useCase.Execute(suite.ctx, usecase.FindNearbyUsersBatchRequest{Centers: centers})
+	assert.ErrorIs(suite.T(), err, usecase.ErrNearbyBatchTooLarge)
+}
+
+// TestFindNearbyUsersBatchUseCase executa a suite de testes
+func TestFindNearbyUsersBatchUseCase(t *testing.T) {
+	suite.Run(t, new(FindNearbyUsersBatchUseCaseTestSuite))
+}
@@ -3,14 +3,21 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
 	"github.com/vitao/geolocation-tracker/internal/usecase"
 	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+	"github.com/vitao/geolocation-tracker/pkg/metrics"
 )
 
 // FindNearbyUsersUseCaseTestSuite define a suite de testes para FindNearbyUsersUseCase
@@ -30,7 +37,7 @@ func (suite *FindNearbyUsersUseCaseTestSuite) SetupTest() {
 	suite.positionRepo = new(mocks.MockPositionRepository)
 	suite.cache = new(mocks.MockCache)
 	suite.logger = new(mocks.MockLogger)
-	suite.useCase = usecase.NewFindNearbyUsersUseCase(suite.userRepo, suite.positionRepo, suite.cache, suite.logger)
+	suite.useCase = usecase.NewFindNearbyUsersUseCase(suite.userRepo, suite.positionRepo, suite.cache, metrics.NewNoopCollector(), suite.logger, &config.Config{})
 	suite.ctx = context.Background()
 }
 
@@ -70,7 +77,7 @@ func (suite *FindNearbyUsersUseCaseTestSuite) TestFindNearbyUsers_Success() {
 	// Mock: encontrar posições próximas - O use case chama com maxResults+1 = 11
 	positions := []*entity.Position{} // Lista vazia para simplificar
 	suite.positionRepo.On("FindNearby", mock.Anything, mock.Anything, 1000.0, 11).
-		Return(positions, nil)
+		Return(withoutDistance(positions...), nil)
 
 	// Mock: cachear resultado
 	suite.cache.On("CacheNearbyUsers", mock.Anything, request.Latitude, request.Longitude, request.RadiusM, mock.Anything).
@@ -88,6 +95,116 @@ func (suite *FindNearbyUsersUseCaseTestSuite) TestFindNearbyUsers_Success() {
 	assert.NotNil(suite.T(), response)
 	assert.Equal(suite.T(), 0, response.TotalFound)
 	assert.Empty(suite.T(), response.NearbyUsers)
+	assertJSONEmptyArray(suite.T(), response, "nearby_users")
+}
+
+// TestFindNearbyUsers_CacheHitEmpty testa que um cache hit sem outros usuários retorna []
+func (suite *FindNearbyUsersUseCaseTestSuite) TestFindNearbyUsers_CacheHitEmpty() {
+	// Arrange
+	request := usecase.FindNearbyUsersRequest{
+		UserID:     "user123",
+		Latitude:   -23.550520,
+		Longitude:  -46.633309,
+		RadiusM:    1000.0,
+		MaxResults: 10,
+	}
+
+	// Mock: cache contém apenas o próprio usuário
+	suite.cache.On("GetCachedNearbyUsers", mock.Anything, request.Latitude, request.Longitude, request.RadiusM, mock.Anything).
+		Run(func(args mock.Arguments) {
+			cached := args.Get(4).(*usecase.FindNearbyUsersResponse)
+			cached.NearbyUsers = []usecase.NearbyUserResponse{{UserID: "user123"}}
+		}).
+		Return(nil)
+
+	suite.logger.On("Info", "Cache hit for nearby users search", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "user123", response.SearchCenter.UserID)
+	assertJSONEmptyArray(suite.T(), response, "nearby_users")
+}
+
+// TestFindNearbyUsers_CacheHitRefreshesAge testa que a idade é recalculada a partir de recorded_at
+func (suite *FindNearbyUsersUseCaseTestSuite) TestFindNearbyUsers_CacheHitRefreshesAge() {
+	// Arrange
+	request := usecase.FindNearbyUsersRequest{
+		UserID:     "user123",
+		Latitude:   -23.550520,
+		Longitude:  -46.633309,
+		RadiusM:    1000.0,
+		MaxResults: 10,
+	}
+	recordedAt := time.Now().Add(-90 * time.Second)
+
+	// Mock: entrada em cache gravada quando a posição tinha 5s
+	suite.cache.On("GetCachedNearbyUsers", mock.Anything, request.Latitude, request.Longitude, request.RadiusM, mock.Anything).
+		Run(func(args mock.Arguments) {
+			cached := args.Get(4).(*usecase.FindNearbyUsersResponse)
+			cached.NearbyUsers = []usecase.NearbyUserResponse{
+				{UserID: "user456", RecordedAt: recordedAt, AgeSeconds: 5, Age: "5s"},
+			}
+		}).
+		Return(nil)
+	suite.logger.On("Info", "Cache hit for nearby users search", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(response.NearbyUsers, 1)
+	assert.InDelta(suite.T(), 90, response.NearbyUsers[0].AgeSeconds, 2)
+	assert.True(suite.T(), response.NearbyUsers[0].RecordedAt.Equal(recordedAt))
+}
+
+// TestFindNearbyUsers_GridCacheKeyRecentersHit testa que a chave usa a grade e o hit é recalculado
+// para o centro exato: distâncias atualizadas, quem saiu do raio é descartado e a ordem é refeita
+func (suite *FindNearbyUsersUseCaseTestSuite) TestFindNearbyUsers_GridCacheKeyRecentersHit() {
+	// Arrange
+	cfg := &config.Config{Cache: config.CacheConfig{NearbyGridMeters: 10}}
+	suite.useCase = usecase.NewFindNearbyUsersUseCase(suite.userRepo, suite.positionRepo, suite.cache, metrics.NewNoopCollector(), suite.logger, cfg)
+
+	request := usecase.FindNearbyUsersRequest{
+		UserID:     "user123",
+		Latitude:   -23.550520,
+		Longitude:  -46.633309,
+		RadiusM:    100.0,
+		MaxResults: 10,
+	}
+	center, err := valueobject.NewCoordinate(request.Latitude, request.Longitude)
+	suite.Require().NoError(err)
+	snapped := center.Snap(10)
+
+	// Mock: entrada gravada por outro centro da mesma célula (distâncias desatualizadas)
+	suite.cache.On("GetCachedNearbyUsers", mock.Anything, snapped.Latitude(), snapped.Longitude(), request.RadiusM, mock.Anything).
+		Run(func(args mock.Arguments) {
+			cached := args.Get(4).(*usecase.FindNearbyUsersResponse)
+			cached.NearbyUsers = []usecase.NearbyUserResponse{
+				{UserID: "user456", Latitude: -23.550900, Longitude: -46.633309, DistanceM: 1},  // ~42m
+				{UserID: "user789", Latitude: -23.550600, Longitude: -46.633309, DistanceM: 50}, // ~9m
+				{UserID: "user999", Latitude: -23.552000, Longitude: -46.633309, DistanceM: 99}, // ~165m
+			}
+		}).
+		Return(nil)
+	suite.logger.On("Info", "Cache hit for nearby users search", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(response.NearbyUsers, 2)
+	assert.Equal(suite.T(), "user789", response.NearbyUsers[0].UserID)
+	assert.InDelta(suite.T(), 8.9, response.NearbyUsers[0].DistanceM, 0.5)
+	assert.Equal(suite.T(), "user456", response.NearbyUsers[1].UserID)
+	assert.InDelta(suite.T(), 42.3, response.NearbyUsers[1].DistanceM, 0.5)
 }
 
 // TestFindNearbyUsers_InvalidCoordinates testa com coordenadas inválidas
@@ -172,10 +289,468 @@ func (suite *FindNearbyUsersUseCaseTestSuite) TestFindNearbyUsers_RepositoryErro
 	assert.Contains(suite.T(), err.Error(), "database error")
 }
 
+// TestFindNearbyUsers_SkipsCachingEmptyResult testa que resultado vazio não é cacheado
+func (suite *FindNearbyUsersUseCaseTestSuite) TestFindNearbyUsers_SkipsCachingEmptyResult() {
+	// Arrange
+	cfg := &config.Config{Cache: config.CacheConfig{NearbyMinResultsToCache: 1}}
+	suite.useCase = usecase.NewFindNearbyUsersUseCase(suite.userRepo, suite.positionRepo, suite.cache, metrics.NewNoopCollector(), suite.logger, cfg)
+
+	request := usecase.FindNearbyUsersRequest{
+		UserID:     "user123",
+		Latitude:   -23.550520,
+		Longitude:  -46.633309,
+		RadiusM:    1000.0,
+		MaxResults: 10,
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+
+	validUser, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+
+	suite.cache.On("GetCachedNearbyUsers", mock.Anything, request.Latitude, request.Longitude, request.RadiusM, mock.Anything).
+		Return(errors.New("cache miss"))
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Return(validUser, nil)
+	suite.positionRepo.On("FindNearby", mock.Anything, mock.Anything, 1000.0, 11).
+		Return(withoutDistance(), nil)
+
+	suite.logger.On("Debug", "Skipping cache for nearby users search", mock.Anything).
+		Return()
+	suite.logger.On("Info", "Nearby users search completed from database", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, response.TotalFound)
+	suite.cache.AssertNotCalled(suite.T(), "CacheNearbyUsers", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestFindNearbyUsers_CacheWithoutSearchCenter testa que um search center ausente não vira usuário zerado no cache
+func (suite *FindNearbyUsersUseCaseTestSuite) TestFindNearbyUsers_CacheWithoutSearchCenter() {
+	// Arrange
+	request := usecase.FindNearbyUsersRequest{
+		UserID:     "user123",
+		Latitude:   -23.550520,
+		Longitude:  -46.633309,
+		RadiusM:    1000.0,
+		MaxResults: 10,
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+
+	validUser, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+
+	// Apenas outro usuário por perto: o solicitante não tem posição no raio
+	otherUserID, err := entity.NewUserID("user456")
+	suite.Require().NoError(err)
+
+	otherUser, err := entity.NewUser("user456", "Maria Santos", "maria@example.com")
+	suite.Require().NoError(err)
+
+	otherPosition, err := entity.NewPosition("pos-1", *otherUserID, -23.550600, -46.633400, time.Now().Add(-time.Minute))
+	suite.Require().NoError(err)
+
+	suite.cache.On("GetCachedNearbyUsers", mock.Anything, request.Latitude, request.Longitude, request.RadiusM, mock.Anything).
+		Return(errors.New("cache miss"))
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Return(validUser, nil)
+	suite.userRepo.On("FindByID", mock.Anything, *otherUserID).
+		Return(otherUser, nil)
+	suite.positionRepo.On("FindNearby", mock.Anything, mock.Anything, 1000.0, 11).
+		Return(withoutDistance(otherPosition), nil)
+
+	// Mock: cache recebe apenas o usuário encontrado
+	suite.cache.On("CacheNearbyUsers", mock.Anything, request.Latitude, request.Longitude, request.RadiusM,
+		mock.MatchedBy(func(cached usecase.FindNearbyUsersResponse) bool {
+			return len(cached.NearbyUsers) == 1 &&
+				cached.NearbyUsers[0].UserID == "user456" &&
+				cached.TotalFound == 1
+		})).Return(nil)
+
+	suite.logger.On("Info", "Nearby users search completed from database", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, response.TotalFound)
+	assert.Empty(suite.T(), response.SearchCenter.UserID)
+}
+
+// nearbyPositions cria n posições de outros usuários perto do centro de busca
+func (suite *FindNearbyUsersUseCaseTestSuite) nearbyPositions(n int) []*entity.Position {
+	positions := make([]*entity.Position, 0, n)
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("user%d", 500+i)
+		otherUserID, err := entity.NewUserID(id)
+		suite.Require().NoError(err)
+		otherUser, err := entity.NewUser(id, "Outro Usuário", id+"@example.com")
+		suite.Require().NoError(err)
+		position, err := entity.NewPosition(fmt.Sprintf("pos-%d", i), *otherUserID, -23.550600, -46.633400, time.Now().Add(-time.Minute))
+		suite.Require().NoError(err)
+
+		suite.userRepo.On("FindByID", mock.Anything, *otherUserID).Return(otherUser, nil).Maybe()
+		positions = append(positions, position)
+	}
+	return positions
+}
+
+// TestFindNearbyUsers_PrefersDatabaseDistance testa que a distância do PostGIS é usada quando presente
+func (suite *FindNearbyUsersUseCaseTestSuite) TestFindNearbyUsers_PrefersDatabaseDistance() {
+	// Arrange
+	request := usecase.FindNearbyUsersRequest{
+		UserID:     "user123",
+		Latitude:   -23.550520,
+		Longitude:  -46.633309,
+		RadiusM:    1000,
+		MaxResults: 10,
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	validUser, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+
+	positions := suite.nearbyPositions(2)
+	dbDistance := 12.34
+	nearby := withoutDistance(positions...)
+	nearby[0].DistanceM = &dbDistance
+
+	suite.cache.On("GetCachedNearbyUsers", mock.Anything, request.Latitude, request.Longitude, request.RadiusM, mock.Anything).
+		Return(errors.New("cache miss"))
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Return(validUser, nil)
+	suite.positionRepo.On("FindNearby", mock.Anything, mock.Anything, 1000.0, 11).
+		Return(nearby, nil)
+	suite.cache.On("CacheNearbyUsers", mock.Anything, request.Latitude, request.Longitude, request.RadiusM, mock.Anything).
+		Return(nil)
+	suite.logger.On("Info", "Nearby users search completed from database", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(response.NearbyUsers, 2)
+	assert.Equal(suite.T(), dbDistance, response.NearbyUsers[0].DistanceM)
+
+	// Sem distância do banco: Haversine como fallback
+	center, err := valueobject.NewCoordinate(request.Latitude, request.Longitude)
+	suite.Require().NoError(err)
+	assert.InDelta(suite.T(), center.DistanceTo(positions[1].Coordinate()), response.NearbyUsers[1].DistanceM, 0.001)
+}
+
+// TestFindNearbyUsers_MaxAgeFiltersAfterCaching testa que o cache guarda o resultado completo e a resposta vem filtrada
+func (suite *FindNearbyUsersUseCaseTestSuite) TestFindNearbyUsers_MaxAgeFiltersAfterCaching() {
+	// Arrange
+	request := usecase.FindNearbyUsersRequest{
+		UserID:        "user123",
+		Latitude:      -23.550520,
+		Longitude:     -46.633309,
+		RadiusM:       1000,
+		MaxResults:    10,
+		MaxAgeSeconds: 120,
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	validUser, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+
+	// nearbyPositions cria posições com 1 minuto; a segunda é envelhecida para 2 horas
+	positions := suite.nearbyPositions(2)
+	staleUserID := positions[1].UserID()
+	stale, err := entity.NewPosition("pos-stale", staleUserID, -23.550600, -46.633400, time.Now().Add(-2*time.Hour))
+	suite.Require().NoError(err)
+	positions[1] = stale
+
+	suite.cache.On("GetCachedNearbyUsers", mock.Anything, request.Latitude, request.Longitude, request.RadiusM, mock.Anything).
+		Return(errors.New("cache miss"))
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Return(validUser, nil)
+	suite.positionRepo.On("FindNearby", mock.Anything, mock.Anything, 1000.0, 11).
+		Return(withoutDistance(positions...), nil)
+	suite.cache.On("CacheNearbyUsers", mock.Anything, request.Latitude, request.Longitude, request.RadiusM,
+		mock.MatchedBy(func(cached usecase.FindNearbyUsersResponse) bool {
+			return len(cached.NearbyUsers) == 2
+		})).
+		Return(nil)
+	suite.logger.On("Info", "Nearby users search completed from database", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(response.NearbyUsers, 1)
+	assert.Equal(suite.T(), "user500", response.NearbyUsers[0].UserID)
+	assert.Equal(suite.T(), 1, response.TotalFound)
+}
+
+// withoutDistance embrulha posições como resultado de FindNearby sem a distância do banco
+func withoutDistance(positions ...*entity.Position) []*repository.NearbyPosition {
+	nearby := make([]*repository.NearbyPosition, 0, len(positions))
+	for _, position := range positions {
+		nearby = append(nearby, &repository.NearbyPosition{Position: position})
+	}
+	return nearby
+}
+
+// TestFindNearbyUsers_AdaptiveDenseStopsEarly testa que em área densa a busca para no raio inicial
+func (suite *FindNearbyUsersUseCaseTestSuite) TestFindNearbyUsers_AdaptiveDenseStopsEarly() {
+	// Arrange
+	request := usecase.FindNearbyUsersRequest{
+		UserID:     "user123",
+		Latitude:   -23.550520,
+		Longitude:  -46.633309,
+		RadiusM:    1600.0,
+		MaxResults: 10,
+		Adaptive:   true,
+		MinUsers:   3,
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	validUser, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+
+	suite.userRepo.On("FindByID", mock.Anything, *userID).Return(validUser, nil)
+	suite.positionRepo.On("FindNearby", mock.Anything, mock.Anything, usecase.AdaptiveNearbyStartRadiusM, 11).
+		Return(withoutDistance(suite.nearbyPositions(3)...), nil).Once()
+
+	suite.logger.On("Debug", "Adaptive nearby search finished", mock.Anything).Return()
+	suite.logger.On("Info", "Nearby users search completed from database", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 3, response.TotalFound)
+	assert.Equal(suite.T(), usecase.AdaptiveNearbyStartRadiusM, response.RadiusUsedM)
+	suite.positionRepo.AssertNumberOfCalls(suite.T(), "FindNearby", 1)
+	suite.cache.AssertNotCalled(suite.T(), "GetCachedNearbyUsers", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.cache.AssertNotCalled(suite.T(), "CacheNearbyUsers", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestFindNearbyUsers_AdaptiveSparseExpandsToMax testa que em área esparsa o raio dobra até o máximo
+func (suite *FindNearbyUsersUseCaseTestSuite) TestFindNearbyUsers_AdaptiveSparseExpandsToMax() {
+	// Arrange
+	request := usecase.FindNearbyUsersRequest{
+		UserID:     "user123",
+		Latitude:   -23.550520,
+		Longitude:  -46.633309,
+		RadiusM:    500.0,
+		MaxResults: 10,
+		Adaptive:   true,
+		MinUsers:   3,
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	validUser, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+
+	// O próprio solicitante não conta para K
+	ownPosition, err := entity.NewPosition("pos-own", *userID, -23.550520, -46.633309, time.Now())
+	suite.Require().NoError(err)
+	others := suite.nearbyPositions(1)
+
+	suite.userRepo.On("FindByID", mock.Anything, *userID).Return(validUser, nil)
+	suite.positionRepo.On("FindNearby", mock.Anything, mock.Anything, 100.0, 11).
+		Return(withoutDistance(ownPosition), nil).Once()
+	suite.positionRepo.On("FindNearby", mock.Anything, mock.Anything, 200.0, 11).
+		Return(withoutDistance(ownPosition), nil).Once()
+	suite.positionRepo.On("FindNearby", mock.Anything, mock.Anything, 400.0, 11).
+		Return(withoutDistance(append([]*entity.Position{ownPosition}, others...)...), nil).Once()
+	suite.positionRepo.On("FindNearby", mock.Anything, mock.Anything, 500.0, 11).
+		Return(withoutDistance(append([]*entity.Position{ownPosition}, others...)...), nil).Once()
+
+	suite.logger.On("Debug", "Adaptive nearby search finished", mock.Anything).Return()
+	suite.logger.On("Info", "Nearby users search completed from database", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, response.TotalFound)
+	assert.Equal(suite.T(), "user123", response.SearchCenter.UserID)
+	assert.Equal(suite.T(), 500.0, response.RadiusUsedM)
+	suite.positionRepo.AssertNumberOfCalls(suite.T(), "FindNearby", 4)
+}
+
+// TestFindNearbyUsers_ConcurrentMissesShareQuery testa que buscas simultâneas iguais consultam o banco uma vez
+func (suite *FindNearbyUsersUseCaseTestSuite) TestFindNearbyUsers_ConcurrentMissesShareQuery() {
+	// Arrange
+	const concurrent = 10
+	request := usecase.FindNearbyUsersRequest{
+		UserID:     "user123",
+		Latitude:   -23.550520,
+		Longitude:  -46.633309,
+		RadiusM:    1000.0,
+		MaxResults: 10,
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	validUser, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+
+	// Todas as requisições chegam antes do cache ser preenchido
+	suite.cache.On("GetCachedNearbyUsers", mock.Anything, request.Latitude, request.Longitude, request.RadiusM, mock.Anything).
+		Return(errors.New("cache miss"))
+
+	var arrived sync.WaitGroup
+	arrived.Add(concurrent)
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Run(func(mock.Arguments) { arrived.Done() }).
+		Return(validUser, nil)
+
+	// A primeira consulta fica presa até todas as requisições estarem aguardando
+	release := make(chan struct{})
+	positions := suite.nearbyPositions(3)
+	suite.positionRepo.On("FindNearby", mock.Anything, mock.Anything, 1000.0, 11).
+		Run(func(mock.Arguments) { <-release }).
+		Return(withoutDistance(positions...), nil)
+
+	suite.cache.On("CacheNearbyUsers", mock.Anything, request.Latitude, request.Longitude, request.RadiusM, mock.Anything).
+		Return(nil)
+	suite.logger.On("Info", "Nearby users search completed from database", mock.Anything).
+		Return()
+
+	// Act
+	responses := make([]*usecase.FindNearbyUsersResponse, concurrent)
+	errs := make([]error, concurrent)
+	var done sync.WaitGroup
+	for i := 0; i < concurrent; i++ {
+		done.Add(1)
+		go func(i int) {
+			defer done.Done()
+			responses[i], errs[i] = suite.useCase.Execute(suite.ctx, request)
+		}(i)
+	}
+
+	arrived.Wait()
+	time.Sleep(50 * time.Millisecond) // Tempo para as requisições entrarem no single-flight
+	close(release)
+	done.Wait()
+
+	// Assert
+	suite.positionRepo.AssertNumberOfCalls(suite.T(), "FindNearby", 1)
+	for i := 0; i < concurrent; i++ {
+		suite.Require().NoError(errs[i])
+		assert.Equal(suite.T(), 3, responses[i].TotalFound)
+	}
+}
+
+// TestFindNearbyUsers_MaxResultsLimits testa o padrão e o teto configurados para max_results
+func (suite *FindNearbyUsersUseCaseTestSuite) TestFindNearbyUsers_MaxResultsLimits() {
+	testCases := []struct {
+		name      string
+		requested int
+		want      int
+	}{
+		{name: "omitido usa o padrão", requested: 0, want: 3},
+		{name: "dentro do teto", requested: 4, want: 4},
+		{name: "acima do teto é reduzido", requested: 500, want: 5},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			// Mocks próprios por caso: cada um espera uma query com o limite resolvido
+			userRepo := new(mocks.MockUserRepository)
+			positionRepo := new(mocks.MockPositionRepository)
+			cache := new(mocks.MockCache)
+			logger := new(mocks.MockLogger)
+			cfg := &config.Config{Nearby: config.NearbyConfig{DefaultMaxResults: 3, MaxResults: 5}}
+			useCase := usecase.NewFindNearbyUsersUseCase(userRepo, positionRepo, cache, metrics.NewNoopCollector(), logger, cfg)
+
+			userID, err := entity.NewUserID("user123")
+			suite.Require().NoError(err)
+			validUser, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+			suite.Require().NoError(err)
+
+			cache.On("GetCachedNearbyUsers", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return(errors.New("cache miss"))
+			userRepo.On("FindByID", mock.Anything, *userID).Return(validUser, nil)
+			positionRepo.On("FindNearby", mock.Anything, mock.Anything, 1000.0, tc.want+1).
+				Return(withoutDistance(), nil).Once()
+			cache.On("CacheNearbyUsers", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+			logger.On("Debug", mock.Anything, mock.Anything).Return().Maybe()
+			logger.On("Info", "Nearby users search completed from database", mock.Anything).Return()
+
+			response, err := useCase.Execute(suite.ctx, usecase.FindNearbyUsersRequest{
+				UserID:     "user123",
+				Latitude:   -23.550520,
+				Longitude:  -46.633309,
+				RadiusM:    1000,
+				MaxResults: tc.requested,
+			})
+
+			suite.Require().NoError(err)
+			assert.Equal(suite.T(), tc.want, response.MaxResults)
+			positionRepo.AssertExpectations(suite.T())
+		})
+	}
+}
+
+// TestFindNearbyUsers_NegativeMaxResults testa a rejeição de max_results negativo antes de qualquer leitura
+func (suite *FindNearbyUsersUseCaseTestSuite) TestFindNearbyUsers_NegativeMaxResults() {
+	suite.logger.On("Error", "Invalid max_results", mock.Anything).Return()
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.FindNearbyUsersRequest{
+		UserID:     "user123",
+		Latitude:   -23.550520,
+		Longitude:  -46.633309,
+		RadiusM:    1000,
+		MaxResults: -1,
+	})
+
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, usecase.ErrInvalidInput)
+}
+
+// TestFindNearbyUsers_CacheHitRespectsMaxResults testa que um hit gravado com limite maior é cortado
+func (suite *FindNearbyUsersUseCaseTestSuite) TestFindNearbyUsers_CacheHitRespectsMaxResults() {
+	request := usecase.FindNearbyUsersRequest{
+		UserID:     "user123",
+		Latitude:   -23.550520,
+		Longitude:  -46.633309,
+		RadiusM:    1000,
+		MaxResults: 2,
+	}
+
+	suite.cache.On("GetCachedNearbyUsers", mock.Anything, request.Latitude, request.Longitude, request.RadiusM, mock.Anything).
+		Run(func(args mock.Arguments) {
+			cached := args.Get(4).(*usecase.FindNearbyUsersResponse)
+			cached.NearbyUsers = []usecase.NearbyUserResponse{{UserID: "user1"}, {UserID: "user2"}, {UserID: "user3"}}
+		}).
+		Return(nil)
+	suite.logger.On("Info", "Cache hit for nearby users search", mock.Anything).Return()
+
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 2, response.TotalFound)
+	assert.Equal(suite.T(), 2, response.MaxResults)
+}
+
 // TestNewFindNearbyUsersUseCase testa o construtor
 func (suite *FindNearbyUsersUseCaseTestSuite) TestNewFindNearbyUsersUseCase() {
 	// Act
-	uc := usecase.NewFindNearbyUsersUseCase(suite.userRepo, suite.positionRepo, suite.cache, suite.logger)
+	uc := usecase.NewFindNearbyUsersUseCase(suite.userRepo, suite.positionRepo, suite.cache, metrics.NewNoopCollector(), suite.logger, &config.Config{})
 
 	// Assert
 	assert.NotNil(suite.T(), uc)
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/vitao/geolocation-tracker/internal/domain/entity"
@@ -51,10 +52,12 @@ func NewGetCurrentPositionUseCase(
 
 // Execute executa o use case de buscar posição atual do usuário
 func (uc *GetCurrentPositionUseCase) Execute(ctx context.Context, req GetCurrentPositionRequest) (*GetCurrentPositionResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
 	// 1. Tentar buscar no cache primeiro
 	var cachedResponse GetCurrentPositionResponse
 	if err := uc.cache.GetCachedUserPosition(ctx, req.UserID, &cachedResponse); err == nil {
-		uc.logger.Info("Cache hit for current position", map[string]interface{}{
+		log.Info("Cache hit for current position", map[string]interface{}{
 			"user_id":     req.UserID,
 			"position_id": cachedResponse.PositionID,
 			"source":      "cache",
@@ -65,31 +68,34 @@ func (uc *GetCurrentPositionUseCase) Execute(ctx context.Context, req GetCurrent
 	// 2. Cache miss - buscar dados completos
 	userIDPtr, err := entity.NewUserID(req.UserID)
 	if err != nil {
-		uc.logger.Error("Invalid user ID", map[string]interface{}{
+		log.Error("Invalid user ID", map[string]interface{}{
 			"user_id": req.UserID,
 			"error":   err.Error(),
 		})
-		return nil, fmt.Errorf("invalid user ID: %w", err)
+		return nil, fmt.Errorf("%w: invalid user ID: %w", ErrInvalidInput, err)
 	}
 
 	userID := *userIDPtr
 	user, err := uc.userRepo.FindByID(ctx, userID)
 	if err != nil {
-		uc.logger.Error("User not found", map[string]interface{}{
+		log.Error("User not found", map[string]interface{}{
 			"user_id": req.UserID,
 			"error":   err.Error(),
 		})
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, userLookupError(req.UserID, err)
 	}
 
 	// 3. Buscar posição atual do usuário
 	currentPosition, err := uc.positionRepo.FindCurrentByUserID(ctx, userID)
 	if err != nil {
-		uc.logger.Error("Current position not found", map[string]interface{}{
+		log.Error("Current position not found", map[string]interface{}{
 			"user_id": req.UserID,
 			"error":   err.Error(),
 		})
-		return nil, fmt.Errorf("current position not found: %w", err)
+		if errors.Is(err, entity.ErrPositionNotFound) {
+			return nil, fmt.Errorf("%w: no current position for user %s", ErrPositionNotFound, req.UserID)
+		}
+		return nil, fmt.Errorf("failed to find current position: %w", err)
 	}
 
 	// 4. Preparar resposta
@@ -110,7 +116,7 @@ func (uc *GetCurrentPositionUseCase) Execute(ctx context.Context, req GetCurrent
 
 	// 5. Salvar no cache para próximas consultas
 	if cacheErr := uc.cache.CacheUserPosition(ctx, req.UserID, response); cacheErr != nil {
-		uc.logger.Error("Failed to cache user position", map[string]interface{}{
+		log.Error("Failed to cache user position", map[string]interface{}{
 			"user_id": req.UserID,
 			"error":   cacheErr.Error(),
 		})
@@ -118,7 +124,7 @@ func (uc *GetCurrentPositionUseCase) Execute(ctx context.Context, req GetCurrent
 	}
 
 	// 6. Log de sucesso
-	uc.logger.Info("Current position retrieved from database", map[string]interface{}{
+	log.Info("Current position retrieved from database", map[string]interface{}{
 		"user_id":     req.UserID,
 		"position_id": response.PositionID,
 		"sector_id":   response.SectorID,
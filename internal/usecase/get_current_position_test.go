@@ -118,7 +118,7 @@ func (suite *GetCurrentPositionUseCaseTestSuite) TestGetCurrentPosition_UserNotF
 
 	// Mock: usuário não existe
 	suite.userRepo.On("FindByID", mock.Anything, *userID).
-		Return(nil, errors.New("user not found"))
+		Return(nil, entity.ErrUserIDNotFound)
 
 	// Mock: log de erro
 	suite.logger.On("Error", "User not found", mock.Anything).
@@ -128,9 +128,8 @@ func (suite *GetCurrentPositionUseCaseTestSuite) TestGetCurrentPosition_UserNotF
 	response, err := suite.useCase.Execute(suite.ctx, request)
 
 	// Assert
-	assert.Error(suite.T(), err)
+	assert.ErrorIs(suite.T(), err, usecase.ErrUserNotFound)
 	assert.Nil(suite.T(), response)
-	assert.Contains(suite.T(), err.Error(), "user not found")
 }
 
 // TestGetCurrentPosition_PositionNotFound testa posição não encontrada
@@ -156,7 +155,7 @@ func (suite *GetCurrentPositionUseCaseTestSuite) TestGetCurrentPosition_Position
 
 	// Mock: posição não encontrada
 	suite.positionRepo.On("FindCurrentByUserID", mock.Anything, *userID).
-		Return(nil, errors.New("position not found"))
+		Return(nil, entity.ErrPositionNotFound)
 
 	// Mock: log de erro
 	suite.logger.On("Error", "Current position not found", mock.Anything).
@@ -166,9 +165,8 @@ func (suite *GetCurrentPositionUseCaseTestSuite) TestGetCurrentPosition_Position
 	response, err := suite.useCase.Execute(suite.ctx, request)
 
 	// Assert
-	assert.Error(suite.T(), err)
+	assert.ErrorIs(suite.T(), err, usecase.ErrPositionNotFound)
 	assert.Nil(suite.T(), response)
-	assert.Contains(suite.T(), err.Error(), "position not found")
 }
 
 // TestGetCurrentPosition_InvalidUserID testa ID de usuário inválido
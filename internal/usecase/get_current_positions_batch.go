@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// MaxCurrentPositionsBatchUsers é o limite de usuários por requisição da busca em lote
+const MaxCurrentPositionsBatchUsers = 100
+
+// ErrEmptyCurrentPositionsBatch indica um lote sem usuários
+var ErrEmptyCurrentPositionsBatch = errors.New("batch must contain at least one user ID")
+
+// ErrCurrentPositionsBatchTooLarge indica um lote acima de MaxCurrentPositionsBatchUsers
+var ErrCurrentPositionsBatchTooLarge = fmt.Errorf("batch must contain at most %d user IDs", MaxCurrentPositionsBatchUsers)
+
+// GetCurrentPositionsBatchRequest representa os dados de entrada
+type GetCurrentPositionsBatchRequest struct {
+	UserIDs []string `json:"user_ids"`
+}
+
+// CurrentPositionItem representa a posição atual de um usuário no lote
+type CurrentPositionItem struct {
+	UserID     string    `json:"user_id"`
+	PositionID string    `json:"position_id"`
+	Latitude   float64   `json:"latitude"`
+	Longitude  float64   `json:"longitude"`
+	SectorID   string    `json:"sector_id"`
+	RecordedAt time.Time `json:"recorded_at"`
+	AgeSeconds int64     `json:"age_seconds"`
+	Age        string    `json:"age"` // Ex: "5m30s"
+}
+
+// GetCurrentPositionsBatchResponse representa a resposta do lote
+type GetCurrentPositionsBatchResponse struct {
+	Positions []CurrentPositionItem `json:"positions"`        // Na ordem dos IDs pedidos
+	Missing   []string              `json:"missing_user_ids"` // IDs sem posição atual
+	Message   string                `json:"message"`
+}
+
+// GetCurrentPositionsBatchUseCase busca as posições atuais de vários usuários em uma query
+type GetCurrentPositionsBatchUseCase struct {
+	positionRepo repository.PositionRepository
+	logger       logger.Logger
+}
+
+// NewGetCurrentPositionsBatchUseCase cria uma nova instância do use case
+func NewGetCurrentPositionsBatchUseCase(
+	positionRepo repository.PositionRepository,
+	logger logger.Logger,
+) *GetCurrentPositionsBatchUseCase {
+	return &GetCurrentPositionsBatchUseCase{
+		positionRepo: positionRepo,
+		logger:       logger,
+	}
+}
+
+// Execute executa o use case de busca de posições atuais em lote
+func (uc *GetCurrentPositionsBatchUseCase) Execute(ctx context.Context, req GetCurrentPositionsBatchRequest) (*GetCurrentPositionsBatchResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	// 1. Validar e deduplicar os IDs (mantendo a ordem do pedido)
+	if len(req.UserIDs) == 0 {
+		return nil, ErrEmptyCurrentPositionsBatch
+	}
+
+	requested := make([]string, 0, len(req.UserIDs))
+	userIDs := make([]entity.UserID, 0, len(req.UserIDs))
+	seen := make(map[string]bool, len(req.UserIDs))
+	for _, id := range req.UserIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		userID, err := entity.NewUserID(id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid user ID %q: %w", ErrInvalidInput, id, err)
+		}
+		requested = append(requested, id)
+		userIDs = append(userIDs, *userID)
+	}
+
+	if len(userIDs) > MaxCurrentPositionsBatchUsers {
+		return nil, ErrCurrentPositionsBatchTooLarge
+	}
+
+	// 2. Buscar todas as posições atuais de uma vez
+	positions, err := uc.positionRepo.FindCurrentByUserIDs(ctx, userIDs)
+	if err != nil {
+		log.Error("Failed to find current positions", map[string]interface{}{
+			"users": len(userIDs),
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to find current positions: %w", err)
+	}
+
+	byUser := make(map[string]*entity.Position, len(positions))
+	for _, position := range positions {
+		positionUserID := position.UserID()
+		byUser[positionUserID.String()] = position
+	}
+
+	// 3. Montar a resposta na ordem do pedido, separando quem não tem posição atual
+	response := &GetCurrentPositionsBatchResponse{
+		Positions: make([]CurrentPositionItem, 0, len(positions)), // Sempre [] no JSON, nunca null
+		Missing:   make([]string, 0),
+	}
+	for i, id := range requested {
+		position, ok := byUser[userIDs[i].String()]
+		if !ok {
+			response.Missing = append(response.Missing, id)
+			continue
+		}
+		response.Positions = append(response.Positions, toCurrentPositionItem(position))
+	}
+	response.Message = fmt.Sprintf("Found current positions for %d of %d users", len(response.Positions), len(requested))
+
+	log.Info("Current positions batch retrieved", map[string]interface{}{
+		"users":   len(requested),
+		"found":   len(response.Positions),
+		"missing": len(response.Missing),
+	})
+
+	return response, nil
+}
+
+// toCurrentPositionItem monta o item da resposta a partir da posição
+func toCurrentPositionItem(position *entity.Position) CurrentPositionItem {
+	coordinate := position.Coordinate()
+	userID := position.UserID()
+	positionID := position.ID()
+	age := position.Age()
+
+	return CurrentPositionItem{
+		UserID:     userID.String(),
+		PositionID: positionID.String(),
+		Latitude:   coordinate.Latitude(),
+		Longitude:  coordinate.Longitude(),
+		SectorID:   position.Sector().ID(),
+		RecordedAt: position.RecordedAt().Time(),
+		AgeSeconds: int64(age / time.Second),
+		Age:        age.String(),
+	}
+}
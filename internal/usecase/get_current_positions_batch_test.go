@@ -0,0 +1,128 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+)
+
+// GetCurrentPositionsBatchUseCaseTestSuite define a suite de testes para GetCurrentPositionsBatchUseCase
+type GetCurrentPositionsBatchUseCaseTestSuite struct {
+	suite.Suite
+	positionRepo *mocks.MockPositionRepository
+	logger       *mocks.MockLogger
+	useCase      *usecase.GetCurrentPositionsBatchUseCase
+	ctx          context.Context
+}
+
+// SetupTest configura cada teste
+func (suite *GetCurrentPositionsBatchUseCaseTestSuite) SetupTest() {
+	suite.positionRepo = new(mocks.MockPositionRepository)
+	suite.logger = new(mocks.MockLogger)
+	suite.useCase = usecase.NewGetCurrentPositionsBatchUseCase(suite.positionRepo, suite.logger)
+	suite.ctx = context.Background()
+}
+
+// TearDownTest limpa após cada teste
+func (suite *GetCurrentPositionsBatchUseCaseTestSuite) TearDownTest() {
+	suite.positionRepo.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// userIDs converte strings em UserIDs válidos
+func (suite *GetCurrentPositionsBatchUseCaseTestSuite) userIDs(ids ...string) []entity.UserID {
+	userIDs := make([]entity.UserID, 0, len(ids))
+	for _, id := range ids {
+		userID, err := entity.NewUserID(id)
+		suite.Require().NoError(err)
+		userIDs = append(userIDs, *userID)
+	}
+	return userIDs
+}
+
+// TestGetCurrentPositionsBatch_FoundAndMissing testa a ordem do pedido, a deduplicação e os IDs sem posição
+func (suite *GetCurrentPositionsBatchUseCaseTestSuite) TestGetCurrentPositionsBatch_FoundAndMissing() {
+	// Arrange
+	ids := suite.userIDs("user-1", "user-2", "user-3")
+	position1, err := entity.NewPosition("pos-1", ids[0], -23.550520, -46.633309, time.Now().Add(-time.Minute))
+	suite.Require().NoError(err)
+	position3, err := entity.NewPosition("pos-3", ids[2], -23.550600, -46.633400, time.Now())
+	suite.Require().NoError(err)
+
+	// Mock: uma única query com os IDs sem duplicados; o banco devolve fora de ordem
+	suite.positionRepo.On("FindCurrentByUserIDs", mock.Anything, ids).
+		Return([]*entity.Position{position3, position1}, nil).Once()
+	suite.logger.On("Info", "Current positions batch retrieved", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetCurrentPositionsBatchRequest{
+		UserIDs: []string{"user-1", "user-2", "user-1", "user-3"},
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(response.Positions, 2)
+	assert.Equal(suite.T(), "user-1", response.Positions[0].UserID)
+	assert.Equal(suite.T(), "pos-1", response.Positions[0].PositionID)
+	assert.Equal(suite.T(), "user-3", response.Positions[1].UserID)
+	assert.Equal(suite.T(), []string{"user-2"}, response.Missing)
+}
+
+// TestGetCurrentPositionsBatch_TooLarge testa o limite de IDs por requisição
+func (suite *GetCurrentPositionsBatchUseCaseTestSuite) TestGetCurrentPositionsBatch_TooLarge() {
+	ids := make([]string, 0, usecase.MaxCurrentPositionsBatchUsers+1)
+	for i := 0; i <= usecase.MaxCurrentPositionsBatchUsers; i++ {
+		ids = append(ids, fmt.Sprintf("user-%d", i))
+	}
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetCurrentPositionsBatchRequest{UserIDs: ids})
+
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, usecase.ErrCurrentPositionsBatchTooLarge)
+}
+
+// TestGetCurrentPositionsBatch_Empty testa o lote vazio
+func (suite *GetCurrentPositionsBatchUseCaseTestSuite) TestGetCurrentPositionsBatch_Empty() {
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetCurrentPositionsBatchRequest{})
+
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, usecase.ErrEmptyCurrentPositionsBatch)
+}
+
+// TestGetCurrentPositionsBatch_InvalidUserID testa que um ID inválido rejeita o lote
+func (suite *GetCurrentPositionsBatchUseCaseTestSuite) TestGetCurrentPositionsBatch_InvalidUserID() {
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetCurrentPositionsBatchRequest{
+		UserIDs: []string{"user-1", ""},
+	})
+
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, usecase.ErrInvalidInput)
+}
+
+// TestGetCurrentPositionsBatch_RepositoryError testa a falha da query
+func (suite *GetCurrentPositionsBatchUseCaseTestSuite) TestGetCurrentPositionsBatch_RepositoryError() {
+	suite.positionRepo.On("FindCurrentByUserIDs", mock.Anything, suite.userIDs("user-1")).
+		Return(nil, errors.New("database error"))
+	suite.logger.On("Error", "Failed to find current positions", mock.Anything).Return()
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetCurrentPositionsBatchRequest{
+		UserIDs: []string{"user-1"},
+	})
+
+	assert.Nil(suite.T(), response)
+	assert.Error(suite.T(), err)
+}
+
+// TestGetCurrentPositionsBatchUseCase executa toda a suite de testes
+func TestGetCurrentPositionsBatchUseCase(t *testing.T) {
+	suite.Run(t, new(GetCurrentPositionsBatchUseCaseTestSuite))
+}
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// MaxCentroidGroupUsers é o limite de usuários por cálculo de ponto de encontro
+const MaxCentroidGroupUsers = 100
+
+// ErrEmptyCentroidGroup indica um grupo sem usuários
+var ErrEmptyCentroidGroup = errors.New("group must contain at least one user ID")
+
+// ErrCentroidGroupTooLarge indica um grupo acima de MaxCentroidGroupUsers
+var ErrCentroidGroupTooLarge = fmt.Errorf("group must contain at most %d user IDs", MaxCentroidGroupUsers)
+
+// GetGroupCentroidRequest representa os dados de entrada
+type GetGroupCentroidRequest struct {
+	UserIDs []string `json:"user_ids"`
+}
+
+// GroupMemberDistance representa a posição atual de um membro e sua distância até o centro
+type GroupMemberDistance struct {
+	UserID         string  `json:"user_id"`
+	Latitude       float64 `json:"latitude"`
+	Longitude      float64 `json:"longitude"`
+	DistanceMeters float64 `json:"distance_meters"`
+}
+
+// GetGroupCentroidResponse representa o ponto de encontro do grupo
+type GetGroupCentroidResponse struct {
+	Latitude          float64               `json:"latitude"`            // Centro geográfico das posições atuais
+	Longitude         float64               `json:"longitude"`           // Centro geográfico das posições atuais
+	MaxDistanceMeters float64               `json:"max_distance_meters"` // Maior distância de um membro até o centro
+	FarthestUserID    string                `json:"farthest_user_id"`
+	Members           []GroupMemberDistance `json:"members"`          // Na ordem dos IDs pedidos
+	Missing           []string              `json:"missing_user_ids"` // IDs sem posição atual (fora do cálculo)
+	Message           string                `json:"message"`
+}
+
+// GetGroupCentroidUseCase calcula o ponto de encontro de um grupo pelas posições atuais
+type GetGroupCentroidUseCase struct {
+	positionRepo repository.PositionRepository
+	logger       logger.Logger
+}
+
+// NewGetGroupCentroidUseCase cria uma nova instância do use case
+func NewGetGroupCentroidUseCase(
+	positionRepo repository.PositionRepository,
+	logger logger.Logger,
+) *GetGroupCentroidUseCase {
+	return &GetGroupCentroidUseCase{
+		positionRepo: positionRepo,
+		logger:       logger,
+	}
+}
+
+// Execute executa o use case de ponto de encontro do grupo
+func (uc *GetGroupCentroidUseCase) Execute(ctx context.Context, req GetGroupCentroidRequest) (*GetGroupCentroidResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	// 1. Validar e deduplicar os IDs (mantendo a ordem do pedido)
+	if len(req.UserIDs) == 0 {
+		return nil, ErrEmptyCentroidGroup
+	}
+
+	requested := make([]string, 0, len(req.UserIDs))
+	userIDs := make([]entity.UserID, 0, len(req.UserIDs))
+	seen := make(map[string]bool, len(req.UserIDs))
+	for _, id := range req.UserIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		userID, err := entity.NewUserID(id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid user ID %q: %w", ErrInvalidInput, id, err)
+		}
+		requested = append(requested, id)
+		userIDs = append(userIDs, *userID)
+	}
+
+	if len(userIDs) > MaxCentroidGroupUsers {
+		return nil, ErrCentroidGroupTooLarge
+	}
+
+	// 2. Buscar as posições atuais de todos de uma vez
+	positions, err := uc.positionRepo.FindCurrentByUserIDs(ctx, userIDs)
+	if err != nil {
+		log.Error("Failed to find current positions", map[string]interface{}{
+			"users": len(userIDs),
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to find current positions: %w", err)
+	}
+
+	byUser := make(map[string]*entity.Position, len(positions))
+	for _, position := range positions {
+		positionUserID := position.UserID()
+		byUser[positionUserID.String()] = position
+	}
+
+	// 3. Separar quem tem posição atual, na ordem do pedido
+	response := &GetGroupCentroidResponse{
+		Members: make([]GroupMemberDistance, 0, len(positions)), // Sempre [] no JSON, nunca null
+		Missing: make([]string, 0),
+	}
+	coordinates := make([]*valueobject.Coordinate, 0, len(positions))
+	for i, id := range requested {
+		position, ok := byUser[userIDs[i].String()]
+		if !ok {
+			response.Missing = append(response.Missing, id)
+			continue
+		}
+		coordinates = append(coordinates, position.Coordinate())
+		response.Members = append(response.Members, GroupMemberDistance{UserID: id})
+	}
+
+	if len(coordinates) == 0 {
+		return nil, fmt.Errorf("%w: none of the %d users has a current position", ErrPositionNotFound, len(requested))
+	}
+
+	// 4. Calcular o centro; com um único membro o centro é a própria posição
+	centroid, err := valueobject.CalculateCentroid(coordinates)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+	}
+
+	response.Latitude = centroid.Latitude()
+	response.Longitude = centroid.Longitude()
+
+	for i, coordinate := range coordinates {
+		member := &response.Members[i]
+		member.Latitude = coordinate.Latitude()
+		member.Longitude = coordinate.Longitude()
+		member.DistanceMeters = coordinate.DistanceTo(centroid)
+
+		if member.DistanceMeters > response.MaxDistanceMeters || response.FarthestUserID == "" {
+			response.MaxDistanceMeters = member.DistanceMeters
+			response.FarthestUserID = member.UserID
+		}
+	}
+
+	if len(coordinates) == 1 {
+		response.Message = "Only one user has a current position; the meeting point is their position"
+	} else {
+		response.Message = fmt.Sprintf("Meeting point for %d of %d users", len(coordinates), len(requested))
+	}
+
+	log.Info("Group centroid calculated", map[string]interface{}{
+		"users":               len(requested),
+		"found":               len(coordinates),
+		"missing":             len(response.Missing),
+		"max_distance_meters": response.MaxDistanceMeters,
+	})
+
+	return response, nil
+}
@@ -0,0 +1,148 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+)
+
+// GetGroupCentroidUseCaseTestSuite define a suite de testes para GetGroupCentroidUseCase
+type GetGroupCentroidUseCaseTestSuite struct {
+	suite.Suite
+	positionRepo *mocks.MockPositionRepository
+	logger       *mocks.MockLogger
+	useCase      *usecase.GetGroupCentroidUseCase
+	ctx          context.Context
+}
+
+// SetupTest configura cada teste
+func (suite *GetGroupCentroidUseCaseTestSuite) SetupTest() {
+	suite.positionRepo = new(mocks.MockPositionRepository)
+	suite.logger = new(mocks.MockLogger)
+	suite.useCase = usecase.NewGetGroupCentroidUseCase(suite.positionRepo, suite.logger)
+	suite.ctx = context.Background()
+}
+
+// TearDownTest limpa após cada teste
+func (suite *GetGroupCentroidUseCaseTestSuite) TearDownTest() {
+	suite.positionRepo.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// userIDs converte strings em UserIDs válidos
+func (suite *GetGroupCentroidUseCaseTestSuite) userIDs(ids ...string) []entity.UserID {
+	userIDs := make([]entity.UserID, 0, len(ids))
+	for _, id := range ids {
+		userID, err := entity.NewUserID(id)
+		suite.Require().NoError(err)
+		userIDs = append(userIDs, *userID)
+	}
+	return userIDs
+}
+
+// position cria a posição atual de um usuário
+func (suite *GetGroupCentroidUseCaseTestSuite) position(userID entity.UserID, lat, lng float64) *entity.Position {
+	position, err := entity.NewPosition("pos-"+userID.String(), userID, lat, lng, time.Now())
+	suite.Require().NoError(err)
+	return position
+}
+
+// TestGetGroupCentroid_MeetingPoint testa o centro, a maior distância e os IDs sem posição
+func (suite *GetGroupCentroidUseCaseTestSuite) TestGetGroupCentroid_MeetingPoint() {
+	// Arrange: dois usuários a leste e oeste do centro, no mesmo paralelo
+	ids := suite.userIDs("user-1", "user-2", "user-3")
+	suite.positionRepo.On("FindCurrentByUserIDs", mock.Anything, ids).
+		Return([]*entity.Position{suite.position(ids[2], 0, 0.02), suite.position(ids[0], 0, 0)}, nil).Once()
+	suite.logger.On("Info", "Group centroid calculated", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetGroupCentroidRequest{
+		UserIDs: []string{"user-1", "user-2", "user-1", "user-3"},
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.InDelta(suite.T(), 0, response.Latitude, 1e-9)
+	assert.InDelta(suite.T(), 0.01, response.Longitude, 1e-9)
+	suite.Require().Len(response.Members, 2)
+	assert.Equal(suite.T(), "user-1", response.Members[0].UserID)
+	assert.Equal(suite.T(), "user-3", response.Members[1].UserID)
+	assert.InDelta(suite.T(), response.Members[0].DistanceMeters, response.Members[1].DistanceMeters, 1e-6)
+	assert.InDelta(suite.T(), 1112, response.MaxDistanceMeters, 1)
+	assert.Equal(suite.T(), []string{"user-2"}, response.Missing)
+}
+
+// TestGetGroupCentroid_SingleUser testa que o centro de um único usuário é a própria posição
+func (suite *GetGroupCentroidUseCaseTestSuite) TestGetGroupCentroid_SingleUser() {
+	ids := suite.userIDs("user-1")
+	suite.positionRepo.On("FindCurrentByUserIDs", mock.Anything, ids).
+		Return([]*entity.Position{suite.position(ids[0], -23.550520, -46.633309)}, nil).Once()
+	suite.logger.On("Info", "Group centroid calculated", mock.Anything).Return()
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetGroupCentroidRequest{UserIDs: []string{"user-1"}})
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), -23.550520, response.Latitude)
+	assert.Equal(suite.T(), -46.633309, response.Longitude)
+	assert.Equal(suite.T(), 0.0, response.MaxDistanceMeters)
+	assert.Equal(suite.T(), "user-1", response.FarthestUserID)
+	assert.Contains(suite.T(), response.Message, "Only one user")
+}
+
+// TestGetGroupCentroid_NoCurrentPositions testa o grupo sem nenhuma posição atual
+func (suite *GetGroupCentroidUseCaseTestSuite) TestGetGroupCentroid_NoCurrentPositions() {
+	suite.positionRepo.On("FindCurrentByUserIDs", mock.Anything, suite.userIDs("user-1", "user-2")).
+		Return([]*entity.Position{}, nil).Once()
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetGroupCentroidRequest{UserIDs: []string{"user-1", "user-2"}})
+
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, usecase.ErrPositionNotFound)
+}
+
+// TestGetGroupCentroid_Empty testa a lista vazia
+func (suite *GetGroupCentroidUseCaseTestSuite) TestGetGroupCentroid_Empty() {
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetGroupCentroidRequest{UserIDs: []string{}})
+
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, usecase.ErrEmptyCentroidGroup)
+}
+
+// TestGetGroupCentroid_TooLarge testa o limite de IDs por requisição
+func (suite *GetGroupCentroidUseCaseTestSuite) TestGetGroupCentroid_TooLarge() {
+	ids := make([]string, 0, usecase.MaxCentroidGroupUsers+1)
+	for i := 0; i <= usecase.MaxCentroidGroupUsers; i++ {
+		ids = append(ids, fmt.Sprintf("user-%d", i))
+	}
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetGroupCentroidRequest{UserIDs: ids})
+
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, usecase.ErrCentroidGroupTooLarge)
+}
+
+// TestGetGroupCentroid_RepositoryError testa a falha da query
+func (suite *GetGroupCentroidUseCaseTestSuite) TestGetGroupCentroid_RepositoryError() {
+	suite.positionRepo.On("FindCurrentByUserIDs", mock.Anything, suite.userIDs("user-1")).
+		Return(nil, errors.New("database error"))
+	suite.logger.On("Error", "Failed to find current positions", mock.Anything).Return()
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetGroupCentroidRequest{UserIDs: []string{"user-1"}})
+
+	assert.Nil(suite.T(), response)
+	assert.Error(suite.T(), err)
+}
+
+// TestGetGroupCentroidUseCase executa toda a suite de testes
+func TestGetGroupCentroidUseCase(t *testing.T) {
+	suite.Run(t, new(GetGroupCentroidUseCaseTestSuite))
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// GetOccupiedSectorsRequest representa os dados de entrada
+// O retângulo (viewport do mapa) é opcional, mas se informado exige os quatro limites
+type GetOccupiedSectorsRequest struct {
+	MinLatitude  *float64 `json:"min_latitude,omitempty" validate:"omitempty,min=-90,max=90"`
+	MaxLatitude  *float64 `json:"max_latitude,omitempty" validate:"omitempty,min=-90,max=90"`
+	MinLongitude *float64 `json:"min_longitude,omitempty" validate:"omitempty,min=-180,max=180"`
+	MaxLongitude *float64 `json:"max_longitude,omitempty" validate:"omitempty,min=-180,max=180"`
+}
+
+// OccupiedSectorResponse representa um setor ocupado, com o centro para desenhar no mapa
+type OccupiedSectorResponse struct {
+	SectorID        string  `json:"sector_id"`
+	X               int     `json:"x"`
+	Y               int     `json:"y"`
+	CenterLatitude  float64 `json:"center_latitude"`
+	CenterLongitude float64 `json:"center_longitude"`
+	UserCount       int     `json:"user_count"`
+}
+
+// GetOccupiedSectorsResponse representa a resposta (setores mais ocupados primeiro)
+type GetOccupiedSectorsResponse struct {
+	Sectors      []OccupiedSectorResponse `json:"sectors"`
+	BoundingBox  *SectorBounds            `json:"bounding_box,omitempty"` // Retângulo aplicado, se houver
+	TotalSectors int                      `json:"total_sectors"`
+	TotalUsers   int                      `json:"total_users"`
+	Message      string                   `json:"message"`
+}
+
+// GetOccupiedSectorsUseCase lista os setores com pelo menos um usuário (heatmap)
+type GetOccupiedSectorsUseCase struct {
+	positionRepo repository.PositionRepository
+	logger       logger.Logger
+}
+
+// NewGetOccupiedSectorsUseCase cria uma nova instância do use case
+func NewGetOccupiedSectorsUseCase(
+	positionRepo repository.PositionRepository,
+	logger logger.Logger,
+) *GetOccupiedSectorsUseCase {
+	return &GetOccupiedSectorsUseCase{
+		positionRepo: positionRepo,
+		logger:       logger,
+	}
+}
+
+// Execute executa o use case de setores ocupados
+func (uc *GetOccupiedSectorsUseCase) Execute(ctx context.Context, req GetOccupiedSectorsRequest) (*GetOccupiedSectorsResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	// 1. Validar o retângulo opcional
+	bbox, err := buildBoundingBox(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Agregar usuários por setor
+	occupied, err := uc.positionRepo.FindOccupiedSectors(ctx, bbox)
+	if err != nil {
+		log.Error("Failed to find occupied sectors", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to find occupied sectors: %w", err)
+	}
+
+	// 3. Montar resposta com o centro de cada setor
+	response := &GetOccupiedSectorsResponse{
+		Sectors: make([]OccupiedSectorResponse, 0, len(occupied)),
+	}
+	for _, item := range occupied {
+		center, err := item.Sector.ToCoordinate()
+		if err != nil {
+			log.Warn("Skipping sector without a valid center", map[string]interface{}{
+				"sector_id": item.Sector.ID(),
+				"error":     err.Error(),
+			})
+			continue
+		}
+
+		response.Sectors = append(response.Sectors, OccupiedSectorResponse{
+			SectorID:        item.Sector.ID(),
+			X:               item.Sector.X(),
+			Y:               item.Sector.Y(),
+			CenterLatitude:  center.Latitude(),
+			CenterLongitude: center.Longitude(),
+			UserCount:       item.UserCount,
+		})
+		response.TotalUsers += item.UserCount
+	}
+	response.TotalSectors = len(response.Sectors)
+
+	if bbox != nil {
+		response.BoundingBox = &SectorBounds{
+			MinLatitude:  bbox.MinLatitude,
+			MaxLatitude:  bbox.MaxLatitude,
+			MinLongitude: bbox.MinLongitude,
+			MaxLongitude: bbox.MaxLongitude,
+		}
+	}
+	response.Message = fmt.Sprintf("Found %d occupied sectors with %d users", response.TotalSectors, response.TotalUsers)
+
+	log.Info("Occupied sectors retrieved", map[string]interface{}{
+		"total_sectors": response.TotalSectors,
+		"total_users":   response.TotalUsers,
+		"bounding_box":  bbox != nil,
+	})
+
+	return response, nil
+}
+
+// buildBoundingBox valida o retângulo da requisição (nil quando nenhum limite foi informado)
+func buildBoundingBox(req GetOccupiedSectorsRequest) (*repository.BoundingBox, error) {
+	limits := []*float64{req.MinLatitude, req.MaxLatitude, req.MinLongitude, req.MaxLongitude}
+
+	provided := 0
+	for _, limit := range limits {
+		if limit != nil {
+			provided++
+		}
+	}
+	if provided == 0 {
+		return nil, nil
+	}
+	if provided != len(limits) {
+		return nil, fmt.Errorf("%w: bounding box requires min_latitude, max_latitude, min_longitude and max_longitude", ErrInvalidInput)
+	}
+
+	bbox := &repository.BoundingBox{
+		MinLatitude:  *req.MinLatitude,
+		MaxLatitude:  *req.MaxLatitude,
+		MinLongitude: *req.MinLongitude,
+		MaxLongitude: *req.MaxLongitude,
+	}
+
+	// Os cantos precisam ser coordenadas válidas
+	if _, err := valueobject.NewCoordinate(bbox.MinLatitude, bbox.MinLongitude); err != nil {
+		return nil, fmt.Errorf("%w: invalid bounding box: %w", ErrInvalidInput, err)
+	}
+	if _, err := valueobject.NewCoordinate(bbox.MaxLatitude, bbox.MaxLongitude); err != nil {
+		return nil, fmt.Errorf("%w: invalid bounding box: %w", ErrInvalidInput, err)
+	}
+	if bbox.MinLatitude > bbox.MaxLatitude {
+		return nil, fmt.Errorf("%w: min_latitude must not be greater than max_latitude", ErrInvalidInput)
+	}
+	if bbox.MinLongitude > bbox.MaxLongitude {
+		return nil, fmt.Errorf("%w: min_longitude must not be greater than max_longitude (bounding boxes crossing the antimeridian are not supported)", ErrInvalidInput)
+	}
+
+	return bbox, nil
+}
@@ -0,0 +1,150 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+)
+
+// GetOccupiedSectorsUseCaseTestSuite define a suite de testes para GetOccupiedSectorsUseCase
+type GetOccupiedSectorsUseCaseTestSuite struct {
+	suite.Suite
+	positionRepo *mocks.MockPositionRepository
+	logger       *mocks.MockLogger
+	useCase      *usecase.GetOccupiedSectorsUseCase
+	ctx          context.Context
+}
+
+// SetupTest configura cada teste
+func (suite *GetOccupiedSectorsUseCaseTestSuite) SetupTest() {
+	suite.positionRepo = new(mocks.MockPositionRepository)
+	suite.logger = new(mocks.MockLogger)
+	suite.useCase = usecase.NewGetOccupiedSectorsUseCase(suite.positionRepo, suite.logger)
+	suite.ctx = context.Background()
+}
+
+// TearDownTest limpa após cada teste
+func (suite *GetOccupiedSectorsUseCaseTestSuite) TearDownTest() {
+	suite.positionRepo.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// occupancy cria um setor ocupado para os mocks
+func (suite *GetOccupiedSectorsUseCaseTestSuite) occupancy(x, y, users int) *repository.SectorOccupancy {
+	sector, err := valueobject.NewSector(x, y)
+	suite.Require().NoError(err)
+	return &repository.SectorOccupancy{Sector: sector, UserCount: users}
+}
+
+// TestGetOccupiedSectors_All testa a lista completa com centro e totais
+func (suite *GetOccupiedSectorsUseCaseTestSuite) TestGetOccupiedSectors_All() {
+	// Arrange
+	busy := suite.occupancy(-518, -2616, 3)
+	quiet := suite.occupancy(10, 20, 1)
+
+	suite.positionRepo.On("FindOccupiedSectors", mock.Anything, (*repository.BoundingBox)(nil)).
+		Return([]*repository.SectorOccupancy{busy, quiet}, nil)
+	suite.logger.On("Info", "Occupied sectors retrieved", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetOccupiedSectorsRequest{})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(response.Sectors, 2)
+	assert.Equal(suite.T(), 2, response.TotalSectors)
+	assert.Equal(suite.T(), 4, response.TotalUsers)
+	assert.Nil(suite.T(), response.BoundingBox)
+
+	first := response.Sectors[0]
+	center, err := busy.Sector.ToCoordinate()
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "sector_-518_-2616", first.SectorID)
+	assert.Equal(suite.T(), 3, first.UserCount)
+	assert.Equal(suite.T(), center.Latitude(), first.CenterLatitude)
+	assert.Equal(suite.T(), center.Longitude(), first.CenterLongitude)
+}
+
+// TestGetOccupiedSectors_BoundingBox testa o repasse do retângulo ao repository
+func (suite *GetOccupiedSectorsUseCaseTestSuite) TestGetOccupiedSectors_BoundingBox() {
+	// Arrange
+	minLat, maxLat, minLng, maxLng := -23.6, -23.5, -46.7, -46.6
+	expected := &repository.BoundingBox{MinLatitude: minLat, MaxLatitude: maxLat, MinLongitude: minLng, MaxLongitude: maxLng}
+
+	suite.positionRepo.On("FindOccupiedSectors", mock.Anything, expected).
+		Return([]*repository.SectorOccupancy{}, nil)
+	suite.logger.On("Info", "Occupied sectors retrieved", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetOccupiedSectorsRequest{
+		MinLatitude: &minLat, MaxLatitude: &maxLat, MinLongitude: &minLng, MaxLongitude: &maxLng,
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), response.Sectors)
+	suite.Require().NotNil(response.BoundingBox)
+	assert.Equal(suite.T(), minLng, response.BoundingBox.MinLongitude)
+}
+
+// TestGetOccupiedSectors_InvalidBoundingBox testa retângulos incompletos ou invertidos
+func (suite *GetOccupiedSectorsUseCaseTestSuite) TestGetOccupiedSectors_InvalidBoundingBox() {
+	lat, lng := -23.5, -46.6
+	lower, higher := -10.0, 10.0
+	outside := 91.0
+
+	testCases := []struct {
+		name    string
+		request usecase.GetOccupiedSectorsRequest
+	}{
+		{name: "incomplete", request: usecase.GetOccupiedSectorsRequest{MinLatitude: &lat, MinLongitude: &lng}},
+		{name: "latitude inverted", request: usecase.GetOccupiedSectorsRequest{
+			MinLatitude: &higher, MaxLatitude: &lower, MinLongitude: &lower, MaxLongitude: &higher,
+		}},
+		{name: "crosses antimeridian", request: usecase.GetOccupiedSectorsRequest{
+			MinLatitude: &lower, MaxLatitude: &higher, MinLongitude: &higher, MaxLongitude: &lower,
+		}},
+		{name: "out of range", request: usecase.GetOccupiedSectorsRequest{
+			MinLatitude: &lower, MaxLatitude: &outside, MinLongitude: &lower, MaxLongitude: &higher,
+		}},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			response, err := suite.useCase.Execute(suite.ctx, tc.request)
+
+			assert.Nil(suite.T(), response)
+			assert.ErrorIs(suite.T(), err, usecase.ErrInvalidInput)
+		})
+	}
+	suite.positionRepo.AssertNotCalled(suite.T(), "FindOccupiedSectors", mock.Anything, mock.Anything)
+}
+
+// TestGetOccupiedSectors_RepositoryError testa a falha na agregação
+func (suite *GetOccupiedSectorsUseCaseTestSuite) TestGetOccupiedSectors_RepositoryError() {
+	// Arrange
+	suite.positionRepo.On("FindOccupiedSectors", mock.Anything, mock.Anything).
+		Return(nil, errors.New("database error"))
+	suite.logger.On("Error", "Failed to find occupied sectors", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetOccupiedSectorsRequest{})
+
+	// Assert
+	assert.Nil(suite.T(), response)
+	assert.Error(suite.T(), err)
+	assert.NotErrorIs(suite.T(), err, usecase.ErrInvalidInput)
+}
+
+// TestGetOccupiedSectorsUseCase executa toda a suite de testes
+func TestGetOccupiedSectorsUseCase(t *testing.T) {
+	suite.Run(t, new(GetOccupiedSectorsUseCaseTestSuite))
+}
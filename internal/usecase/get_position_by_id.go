@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// GetPositionByIDRequest representa os dados de entrada
+type GetPositionByIDRequest struct {
+	PositionID string `json:"position_id" validate:"required"`
+}
+
+// GetPositionByIDResponse representa a resposta
+type GetPositionByIDResponse struct {
+	PositionID string    `json:"position_id"`
+	UserID     string    `json:"user_id"`
+	UserName   string    `json:"user_name"`
+	Latitude   float64   `json:"latitude"`
+	Longitude  float64   `json:"longitude"`
+	SectorID   string    `json:"sector_id"`
+	RecordedAt time.Time `json:"recorded_at"`
+	Message    string    `json:"message"`
+}
+
+// GetPositionByIDUseCase implementa a busca de uma posição do histórico pelo ID
+// Usado para resolver o position_id referenciado em eventos position.changed
+type GetPositionByIDUseCase struct {
+	userRepo     repository.UserRepository
+	positionRepo repository.PositionRepository
+	logger       logger.Logger
+}
+
+// NewGetPositionByIDUseCase cria uma nova instância do use case
+func NewGetPositionByIDUseCase(
+	userRepo repository.UserRepository,
+	positionRepo repository.PositionRepository,
+	logger logger.Logger,
+) *GetPositionByIDUseCase {
+	return &GetPositionByIDUseCase{
+		userRepo:     userRepo,
+		positionRepo: positionRepo,
+		logger:       logger,
+	}
+}
+
+// Execute executa o use case de buscar posição por ID
+func (uc *GetPositionByIDUseCase) Execute(ctx context.Context, req GetPositionByIDRequest) (*GetPositionByIDResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	// 1. Validar ID
+	positionID, err := entity.NewPositionID(req.PositionID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid position ID: %w", ErrInvalidInput, err)
+	}
+
+	// 2. Buscar posição
+	position, err := uc.positionRepo.FindByID(ctx, *positionID)
+	if err != nil {
+		if errors.Is(err, entity.ErrPositionNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrPositionNotFound, req.PositionID)
+		}
+		log.Error("Failed to find position", map[string]interface{}{
+			"position_id": req.PositionID,
+			"error":       err.Error(),
+		})
+		return nil, fmt.Errorf("failed to find position: %w", err)
+	}
+
+	// 3. Buscar dono da posição
+	userID := position.UserID()
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		log.Error("User not found for position", map[string]interface{}{
+			"position_id": req.PositionID,
+			"user_id":     userID.String(),
+			"error":       err.Error(),
+		})
+		return nil, fmt.Errorf("failed to find position owner: %w", err)
+	}
+
+	// 4. Montar resposta
+	return &GetPositionByIDResponse{
+		PositionID: positionID.Value(),
+		UserID:     userID.String(),
+		UserName:   user.Name(),
+		Latitude:   position.Latitude(),
+		Longitude:  position.Longitude(),
+		SectorID:   position.Sector().ID(),
+		RecordedAt: position.RecordedAt().Time(),
+		Message:    "Position retrieved successfully",
+	}, nil
+}
@@ -0,0 +1,125 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+)
+
+// GetPositionByIDUseCaseTestSuite define a suite de testes para GetPositionByIDUseCase
+type GetPositionByIDUseCaseTestSuite struct {
+	suite.Suite
+	userRepo     *mocks.MockUserRepository
+	positionRepo *mocks.MockPositionRepository
+	logger       *mocks.MockLogger
+	useCase      *usecase.GetPositionByIDUseCase
+	ctx          context.Context
+}
+
+// SetupTest configura cada teste
+func (suite *GetPositionByIDUseCaseTestSuite) SetupTest() {
+	suite.userRepo = new(mocks.MockUserRepository)
+	suite.positionRepo = new(mocks.MockPositionRepository)
+	suite.logger = new(mocks.MockLogger)
+	suite.useCase = usecase.NewGetPositionByIDUseCase(suite.userRepo, suite.positionRepo, suite.logger)
+	suite.ctx = context.Background()
+}
+
+// TearDownTest limpa após cada teste
+func (suite *GetPositionByIDUseCaseTestSuite) TearDownTest() {
+	suite.userRepo.AssertExpectations(suite.T())
+	suite.positionRepo.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// TestGetPositionByID_Success testa busca bem-sucedida com o nome do dono
+func (suite *GetPositionByIDUseCaseTestSuite) TestGetPositionByID_Success() {
+	// Arrange
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	user, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+	positionID, err := entity.NewPositionID("pos-1")
+	suite.Require().NoError(err)
+
+	recordedAt := time.Now().Add(-time.Hour)
+	position, err := entity.NewPosition("pos-1", *userID, -23.550520, -46.633309, recordedAt)
+	suite.Require().NoError(err)
+
+	suite.positionRepo.On("FindByID", mock.Anything, *positionID).
+		Return(position, nil)
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Return(user, nil)
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetPositionByIDRequest{PositionID: "pos-1"})
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "pos-1", response.PositionID)
+	assert.Equal(suite.T(), "user123", response.UserID)
+	assert.Equal(suite.T(), "João Silva", response.UserName)
+	assert.InDelta(suite.T(), -23.550520, response.Latitude, 1e-9)
+	assert.InDelta(suite.T(), -46.633309, response.Longitude, 1e-9)
+	assert.Equal(suite.T(), position.Sector().ID(), response.SectorID)
+	assert.True(suite.T(), recordedAt.Equal(response.RecordedAt))
+}
+
+// TestGetPositionByID_NotFound testa posição inexistente
+func (suite *GetPositionByIDUseCaseTestSuite) TestGetPositionByID_NotFound() {
+	// Arrange
+	positionID, err := entity.NewPositionID("pos-missing")
+	suite.Require().NoError(err)
+
+	suite.positionRepo.On("FindByID", mock.Anything, *positionID).
+		Return(nil, fmt.Errorf("%w: %s", entity.ErrPositionNotFound, "pos-missing"))
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetPositionByIDRequest{PositionID: "pos-missing"})
+
+	// Assert
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, usecase.ErrPositionNotFound)
+}
+
+// TestGetPositionByID_RepositoryError testa falha do banco
+func (suite *GetPositionByIDUseCaseTestSuite) TestGetPositionByID_RepositoryError() {
+	// Arrange
+	positionID, err := entity.NewPositionID("pos-1")
+	suite.Require().NoError(err)
+
+	suite.positionRepo.On("FindByID", mock.Anything, *positionID).
+		Return(nil, errors.New("connection refused"))
+	suite.logger.On("Error", "Failed to find position", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetPositionByIDRequest{PositionID: "pos-1"})
+
+	// Assert
+	assert.Nil(suite.T(), response)
+	assert.Error(suite.T(), err)
+	assert.NotErrorIs(suite.T(), err, usecase.ErrPositionNotFound)
+}
+
+// TestGetPositionByID_EmptyID testa ID vazio
+func (suite *GetPositionByIDUseCaseTestSuite) TestGetPositionByID_EmptyID() {
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetPositionByIDRequest{})
+
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, entity.ErrEmptyPositionID)
+}
+
+// TestGetPositionByIDUseCase executa a suite de testes
+func TestGetPositionByIDUseCase(t *testing.T) {
+	suite.Run(t, new(GetPositionByIDUseCaseTestSuite))
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/vitao/geolocation-tracker/internal/domain/entity"
 	"github.com/vitao/geolocation-tracker/internal/domain/repository"
@@ -13,6 +14,7 @@ import (
 type GetPositionHistoryRequest struct {
 	UserID string `json:"user_id" validate:"required,uuid"`
 	Limit  int    `json:"limit" validate:"min=1,max=100"`
+	Offset int    `json:"offset" validate:"min=0"`
 }
 
 // PositionHistoryItem representa um item do histórico
@@ -23,6 +25,10 @@ type PositionHistoryItem struct {
 	SectorID   string  `json:"sector_id"`
 	Age        string  `json:"age"`
 	RecordedAt string  `json:"recorded_at"`
+
+	// Bearing é o rumo em graus (0 = norte) vindo da posição anterior no tempo
+	// Ausente na posição mais antiga da página e quando o usuário não se moveu
+	Bearing *float64 `json:"bearing,omitempty"`
 }
 
 // GetPositionHistoryResponse representa a resposta
@@ -30,8 +36,14 @@ type GetPositionHistoryResponse struct {
 	UserID   string                `json:"user_id"`
 	UserName string                `json:"user_name"`
 	History  []PositionHistoryItem `json:"history"`
-	Total    int                   `json:"total"`
+	Total    int                   `json:"total"` // Total de posições no histórico (todas as páginas)
+	Limit    int                   `json:"limit"`
+	Offset   int                   `json:"offset"`
+	HasMore  bool                  `json:"has_more"`
 	Message  string                `json:"message"`
+
+	// TotalDistanceM é o caminho percorrido entre as posições desta página, em ordem cronológica
+	TotalDistanceM float64 `json:"total_distance_meters"`
 }
 
 // GetPositionHistoryUseCase implementa a busca do histórico de posições
@@ -59,6 +71,8 @@ func NewGetPositionHistoryUseCase(
 
 // Execute executa o use case de buscar histórico de posições
 func (uc *GetPositionHistoryUseCase) Execute(ctx context.Context, req GetPositionHistoryRequest) (*GetPositionHistoryResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
 	// 1. Validar parâmetros
 	if req.Limit <= 0 {
 		req.Limit = 10 // Padrão: 10 posições
@@ -66,12 +80,18 @@ func (uc *GetPositionHistoryUseCase) Execute(ctx context.Context, req GetPositio
 	if req.Limit > 100 {
 		req.Limit = 100 // Máximo: 100 posições
 	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
 
-	// 2. Tentar buscar no cache primeiro
+	// 2. Tentar buscar no cache primeiro (apenas a primeira página é cacheada)
 	var cachedResponse GetPositionHistoryResponse
 
-	if err := uc.cache.GetCachedUserHistory(ctx, req.UserID, req.Limit, &cachedResponse); err == nil {
-		uc.logger.Info("Cache hit for position history", map[string]interface{}{
+	if req.Offset == 0 && uc.cache.GetCachedUserHistory(ctx, req.UserID, req.Limit, &cachedResponse) == nil {
+		if cachedResponse.History == nil {
+			cachedResponse.History = []PositionHistoryItem{} // Entradas antigas podem ter null
+		}
+		log.Info("Cache hit for position history", map[string]interface{}{
 			"user_id": req.UserID,
 			"limit":   req.Limit,
 			"total":   cachedResponse.Total,
@@ -83,37 +103,47 @@ func (uc *GetPositionHistoryUseCase) Execute(ctx context.Context, req GetPositio
 	// 3. Cache miss - buscar dados completos
 	userIDPtr, err := entity.NewUserID(req.UserID)
 	if err != nil {
-		uc.logger.Error("Invalid user ID", map[string]interface{}{
+		log.Error("Invalid user ID", map[string]interface{}{
 			"user_id": req.UserID,
 			"error":   err.Error(),
 		})
-		return nil, fmt.Errorf("invalid user ID: %w", err)
+		return nil, fmt.Errorf("%w: invalid user ID: %w", ErrInvalidInput, err)
 	}
 
 	userID := *userIDPtr
 	user, err := uc.userRepo.FindByID(ctx, userID)
 	if err != nil {
-		uc.logger.Error("User not found", map[string]interface{}{
+		log.Error("User not found", map[string]interface{}{
 			"user_id": req.UserID,
 			"error":   err.Error(),
 		})
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, userLookupError(req.UserID, err)
 	}
 
 	// 4. Buscar histórico de posições
-	positions, err := uc.positionRepo.FindHistoryByUserID(ctx, userID, req.Limit)
+	positions, err := uc.positionRepo.FindHistoryByUserID(ctx, userID, req.Limit, req.Offset)
 	if err != nil {
-		uc.logger.Error("Failed to get position history", map[string]interface{}{
+		log.Error("Failed to get position history", map[string]interface{}{
 			"user_id": req.UserID,
 			"limit":   req.Limit,
+			"offset":  req.Offset,
 			"error":   err.Error(),
 		})
 		return nil, fmt.Errorf("failed to get position history: %w", err)
 	}
 
+	total, err := uc.positionRepo.CountHistoryByUserID(ctx, userID)
+	if err != nil {
+		log.Error("Failed to count position history", map[string]interface{}{
+			"user_id": req.UserID,
+			"error":   err.Error(),
+		})
+		return nil, fmt.Errorf("failed to count position history: %w", err)
+	}
+
 	// 5. Converter para resposta
-	var history []PositionHistoryItem
-	for _, position := range positions {
+	history := make([]PositionHistoryItem, 0, len(positions)) // Sempre [] no JSON, nunca null
+	for i, position := range positions {
 		coordinate := position.Coordinate()
 		positionIDValue := position.ID()
 		recordedAt := position.RecordedAt()
@@ -126,6 +156,11 @@ func (uc *GetPositionHistoryUseCase) Execute(ctx context.Context, req GetPositio
 			Age:        position.Age().String(),
 			RecordedAt: recordedAt.String(),
 		}
+
+		// A página vem das mais recentes para as mais antigas: a anterior no tempo é a seguinte
+		if i+1 < len(positions) {
+			item.Bearing = movementBearing(positions[i+1], position)
+		}
 		history = append(history, item)
 	}
 
@@ -135,27 +170,64 @@ func (uc *GetPositionHistoryUseCase) Execute(ctx context.Context, req GetPositio
 		UserID:   userIDValue.String(),
 		UserName: user.Name(),
 		History:  history,
-		Total:    len(history),
-		Message:  fmt.Sprintf("Retrieved %d position records", len(history)),
+		Total:    total,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+		HasMore:  req.Offset+len(history) < total,
+		Message:  fmt.Sprintf("Retrieved %d of %d position records", len(history), total),
+
+		TotalDistanceM: pathDistance(positions),
 	}
 
-	// 7. Cachear resultado com TTL baixo (1 minuto)
-	if cacheErr := uc.cache.CacheUserHistory(ctx, req.UserID, req.Limit, response); cacheErr != nil {
-		uc.logger.Error("Failed to cache position history", map[string]interface{}{
-			"user_id": req.UserID,
-			"limit":   req.Limit,
-			"error":   cacheErr.Error(),
-		})
-		// Não falhar a operação por erro de cache
+	// 7. Cachear a primeira página com TTL baixo (1 minuto)
+	// As chaves de cache são por limit; outras páginas sempre vão ao banco
+	if req.Offset == 0 {
+		if cacheErr := uc.cache.CacheUserHistory(ctx, req.UserID, req.Limit, response); cacheErr != nil {
+			log.Error("Failed to cache position history", map[string]interface{}{
+				"user_id": req.UserID,
+				"limit":   req.Limit,
+				"error":   cacheErr.Error(),
+			})
+			// Não falhar a operação por erro de cache
+		}
 	}
 
 	// 8. Log de sucesso
-	uc.logger.Info("Position history retrieved from database", map[string]interface{}{
+	log.Info("Position history retrieved from database", map[string]interface{}{
 		"user_id": req.UserID,
-		"total":   len(history),
+		"total":   total,
 		"limit":   req.Limit,
+		"offset":  req.Offset,
 		"source":  "database",
 	})
 
 	return response, nil
 }
+
+// movementBearing retorna o rumo do deslocamento entre duas posições, ou nil se não houve movimento
+func movementBearing(from, to *entity.Position) *float64 {
+	fromCoordinate := from.Coordinate()
+	toCoordinate := to.Coordinate()
+	if fromCoordinate.Equals(toCoordinate) {
+		return nil
+	}
+
+	bearing := fromCoordinate.BearingTo(toCoordinate)
+	return &bearing
+}
+
+// pathDistance soma as distâncias entre posições consecutivas no tempo
+// O histórico vem das mais recentes para as mais antigas: ordena uma cópia das mais antigas primeiro
+func pathDistance(positions []*entity.Position) float64 {
+	ordered := make([]*entity.Position, len(positions))
+	copy(ordered, positions)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].RecordedAt().Before(ordered[j].RecordedAt())
+	})
+
+	total := 0.0
+	for i := 1; i < len(ordered); i++ {
+		total += ordered[i-1].Coordinate().DistanceTo(ordered[i].Coordinate())
+	}
+	return total
+}
@@ -83,8 +83,10 @@ func (suite *GetPositionHistoryUseCaseTestSuite) TestGetPositionHistory_Success(
 		Return(validUser, nil)
 
 	// Mock: histórico encontrado
-	suite.positionRepo.On("FindHistoryByUserID", mock.Anything, *userID, 10).
+	suite.positionRepo.On("FindHistoryByUserID", mock.Anything, *userID, 10, 0).
 		Return(positions, nil)
+	suite.positionRepo.On("CountHistoryByUserID", mock.Anything, *userID).
+		Return(2, nil)
 
 	// Mock: cachear o resultado
 	suite.cache.On("CacheUserHistory", mock.Anything, request.UserID, 10, mock.Anything).
@@ -106,6 +108,86 @@ func (suite *GetPositionHistoryUseCaseTestSuite) TestGetPositionHistory_Success(
 	assert.Len(suite.T(), response.History, 2)
 	assert.Equal(suite.T(), "pos-1", response.History[0].PositionID)
 	assert.Equal(suite.T(), "pos-2", response.History[1].PositionID)
+	assert.Equal(suite.T(), 10, response.Limit)
+	assert.Equal(suite.T(), 0, response.Offset)
+	assert.False(suite.T(), response.HasMore)
+}
+
+// TestGetPositionHistory_Pagination testa página intermediária com total real e has_more
+func (suite *GetPositionHistoryUseCaseTestSuite) TestGetPositionHistory_Pagination() {
+	// Arrange
+	request := usecase.GetPositionHistoryRequest{
+		UserID: "user123",
+		Limit:  2,
+		Offset: 2,
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+
+	validUser, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+
+	position3, err := entity.NewPosition("pos-3", *userID, -23.550520, -46.633309, time.Now().Add(-3*time.Hour))
+	suite.Require().NoError(err)
+	position4, err := entity.NewPosition("pos-4", *userID, -23.551000, -46.634000, time.Now().Add(-4*time.Hour))
+	suite.Require().NoError(err)
+
+	// Páginas além da primeira não consultam nem gravam cache
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Return(validUser, nil)
+	suite.positionRepo.On("FindHistoryByUserID", mock.Anything, *userID, 2, 2).
+		Return([]*entity.Position{position3, position4}, nil)
+	suite.positionRepo.On("CountHistoryByUserID", mock.Anything, *userID).
+		Return(5, nil)
+	suite.logger.On("Info", "Position history retrieved from database", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Len(suite.T(), response.History, 2)
+	assert.Equal(suite.T(), 5, response.Total)
+	assert.Equal(suite.T(), 2, response.Limit)
+	assert.Equal(suite.T(), 2, response.Offset)
+	assert.True(suite.T(), response.HasMore)
+	suite.cache.AssertNotCalled(suite.T(), "GetCachedUserHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.cache.AssertNotCalled(suite.T(), "CacheUserHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestGetPositionHistory_CountError testa erro ao contar o histórico
+func (suite *GetPositionHistoryUseCaseTestSuite) TestGetPositionHistory_CountError() {
+	// Arrange
+	request := usecase.GetPositionHistoryRequest{
+		UserID: "user123",
+		Limit:  10,
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+
+	validUser, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+
+	suite.addCacheMissMocks(request.UserID, request.Limit)
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Return(validUser, nil)
+	suite.positionRepo.On("FindHistoryByUserID", mock.Anything, *userID, 10, 0).
+		Return([]*entity.Position{}, nil)
+	suite.positionRepo.On("CountHistoryByUserID", mock.Anything, *userID).
+		Return(0, errors.New("database error"))
+	suite.logger.On("Error", "Failed to count position history", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), response)
+	assert.Contains(suite.T(), err.Error(), "failed to count position history")
 }
 
 // TestGetPositionHistory_UserNotFound testa usuário não encontrado
@@ -164,7 +246,7 @@ func (suite *GetPositionHistoryUseCaseTestSuite) TestGetPositionHistory_Reposito
 		Return(validUser, nil)
 
 	// Mock: erro no repositório
-	suite.positionRepo.On("FindHistoryByUserID", mock.Anything, *userID, 10).
+	suite.positionRepo.On("FindHistoryByUserID", mock.Anything, *userID, 10, 0).
 		Return(nil, repoError)
 
 	// Mock: log de erro
@@ -202,8 +284,10 @@ func (suite *GetPositionHistoryUseCaseTestSuite) TestGetPositionHistory_EmptyHis
 		Return(validUser, nil)
 
 	// Mock: histórico vazio
-	suite.positionRepo.On("FindHistoryByUserID", mock.Anything, *userID, 10).
+	suite.positionRepo.On("FindHistoryByUserID", mock.Anything, *userID, 10, 0).
 		Return([]*entity.Position{}, nil)
+	suite.positionRepo.On("CountHistoryByUserID", mock.Anything, *userID).
+		Return(0, nil)
 
 	// Mock: log de sucesso do banco de dados
 	suite.logger.On("Info", "Position history retrieved from database", mock.Anything).
@@ -218,6 +302,35 @@ func (suite *GetPositionHistoryUseCaseTestSuite) TestGetPositionHistory_EmptyHis
 	assert.Equal(suite.T(), "user123", response.UserID)
 	assert.Equal(suite.T(), 0, response.Total)
 	assert.Empty(suite.T(), response.History)
+	assertJSONEmptyArray(suite.T(), response, "history")
+}
+
+// TestGetPositionHistory_CachedNullHistory testa que uma entrada de cache com null vira []
+func (suite *GetPositionHistoryUseCaseTestSuite) TestGetPositionHistory_CachedNullHistory() {
+	// Arrange
+	request := usecase.GetPositionHistoryRequest{
+		UserID: "user123",
+		Limit:  10,
+	}
+
+	// Mock: cache hit com History nulo (formato antigo)
+	suite.cache.On("GetCachedUserHistory", mock.Anything, request.UserID, 10, mock.Anything).
+		Run(func(args mock.Arguments) {
+			cached := args.Get(3).(*usecase.GetPositionHistoryResponse)
+			cached.UserID = "user123"
+			cached.History = nil
+		}).
+		Return(nil)
+
+	suite.logger.On("Info", "Cache hit for position history", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assertJSONEmptyArray(suite.T(), response, "history")
 }
 
 // TestGetPositionHistory_InvalidUserID testa ID de usuário inválido
@@ -266,8 +379,10 @@ func (suite *GetPositionHistoryUseCaseTestSuite) TestGetPositionHistory_DefaultL
 		Return(validUser, nil)
 
 	// Mock: histórico com limite padrão (10)
-	suite.positionRepo.On("FindHistoryByUserID", mock.Anything, *userID, 10).
+	suite.positionRepo.On("FindHistoryByUserID", mock.Anything, *userID, 10, 0).
 		Return([]*entity.Position{}, nil)
+	suite.positionRepo.On("CountHistoryByUserID", mock.Anything, *userID).
+		Return(0, nil)
 
 	// Mock: log de sucesso do banco de dados
 	suite.logger.On("Info", "Position history retrieved from database", mock.Anything).
@@ -281,6 +396,85 @@ func (suite *GetPositionHistoryUseCaseTestSuite) TestGetPositionHistory_DefaultL
 	assert.NotNil(suite.T(), response)
 }
 
+// TestGetPositionHistory_Bearing testa o rumo entre posições consecutivas (mais recentes primeiro)
+func (suite *GetPositionHistoryUseCaseTestSuite) TestGetPositionHistory_Bearing() {
+	// Arrange
+	request := usecase.GetPositionHistoryRequest{UserID: "user123", Limit: 10}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	validUser, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+
+	now := time.Now()
+	oldest, err := entity.NewPosition("pos-1", *userID, 0, 0, now.Add(-3*time.Minute))
+	suite.Require().NoError(err)
+	north, err := entity.NewPosition("pos-2", *userID, 0.001, 0, now.Add(-2*time.Minute))
+	suite.Require().NoError(err)
+	east, err := entity.NewPosition("pos-3", *userID, 0.001, 0.001, now.Add(-time.Minute))
+	suite.Require().NoError(err)
+	stopped, err := entity.NewPosition("pos-4", *userID, 0.001, 0.001, now)
+	suite.Require().NoError(err)
+
+	suite.addCacheMissMocks(request.UserID, 10)
+	suite.userRepo.On("FindByID", mock.Anything, *userID).Return(validUser, nil)
+	suite.positionRepo.On("FindHistoryByUserID", mock.Anything, *userID, 10, 0).
+		Return([]*entity.Position{stopped, east, north, oldest}, nil)
+	suite.positionRepo.On("CountHistoryByUserID", mock.Anything, *userID).Return(4, nil)
+	suite.logger.On("Info", "Position history retrieved from database", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(response.History, 4)
+	assert.Nil(suite.T(), response.History[0].Bearing) // Parado
+	suite.Require().NotNil(response.History[1].Bearing)
+	assert.InDelta(suite.T(), 90, *response.History[1].Bearing, 0.01)
+	suite.Require().NotNil(response.History[2].Bearing)
+	assert.InDelta(suite.T(), 0, *response.History[2].Bearing, 0.01)
+	assert.Nil(suite.T(), response.History[3].Bearing) // Sem anterior na página
+}
+
+// TestGetPositionHistory_TotalDistance testa a soma do caminho em ordem cronológica
+// O repositório entrega as mais recentes primeiro; a ida e volta deve contar os três trechos
+func (suite *GetPositionHistoryUseCaseTestSuite) TestGetPositionHistory_TotalDistance() {
+	// Arrange
+	request := usecase.GetPositionHistoryRequest{UserID: "user123", Limit: 10}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	validUser, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+
+	// Caminho: origem -> ~111m ao norte -> ~111m a leste -> de volta ao ponto norte
+	now := time.Now()
+	start, err := entity.NewPosition("pos-1", *userID, 0, 0, now.Add(-3*time.Minute))
+	suite.Require().NoError(err)
+	north, err := entity.NewPosition("pos-2", *userID, 0.001, 0, now.Add(-2*time.Minute))
+	suite.Require().NoError(err)
+	east, err := entity.NewPosition("pos-3", *userID, 0.001, 0.001, now.Add(-time.Minute))
+	suite.Require().NoError(err)
+	back, err := entity.NewPosition("pos-4", *userID, 0.001, 0, now)
+	suite.Require().NoError(err)
+
+	suite.addCacheMissMocks(request.UserID, 10)
+	suite.userRepo.On("FindByID", mock.Anything, *userID).Return(validUser, nil)
+	suite.positionRepo.On("FindHistoryByUserID", mock.Anything, *userID, 10, 0).
+		Return([]*entity.Position{back, east, north, start}, nil)
+	suite.positionRepo.On("CountHistoryByUserID", mock.Anything, *userID).Return(4, nil)
+	suite.logger.On("Info", "Position history retrieved from database", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.InDelta(suite.T(), 3*111.19, response.TotalDistanceM, 0.5)
+	assert.Equal(suite.T(), "pos-4", response.History[0].PositionID) // Ordem da resposta preservada
+}
+
 // TestNewGetPositionHistoryUseCase testa o construtor
 func (suite *GetPositionHistoryUseCaseTestSuite) TestNewGetPositionHistoryUseCase() {
 	// Act
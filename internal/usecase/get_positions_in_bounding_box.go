@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// Limites da busca por retângulo
+const (
+	DefaultBoundingBoxLimit = 100
+	MaxBoundingBoxLimit     = 500
+
+	// MaxBoundingBoxAreaKm2 rejeita retângulos grandes demais (~100 km x 100 km) para proteger o banco
+	MaxBoundingBoxAreaKm2 = 10000.0
+)
+
+// GetPositionsInBoundingBoxRequest representa os dados de entrada
+type GetPositionsInBoundingBoxRequest struct {
+	MinLatitude  float64 `json:"min_latitude" validate:"min=-90,max=90"`
+	MinLongitude float64 `json:"min_longitude" validate:"min=-180,max=180"`
+	MaxLatitude  float64 `json:"max_latitude" validate:"min=-90,max=90"`
+	MaxLongitude float64 `json:"max_longitude" validate:"min=-180,max=180"`
+	Limit        int     `json:"limit" validate:"min=0,max=500"`
+}
+
+// BoundingBoxPositionResponse representa uma posição atual dentro do retângulo
+type BoundingBoxPositionResponse struct {
+	UserID     string    `json:"user_id"`
+	PositionID string    `json:"position_id"`
+	Latitude   float64   `json:"latitude"`
+	Longitude  float64   `json:"longitude"`
+	SectorID   string    `json:"sector_id"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// GetPositionsInBoundingBoxResponse representa a resposta (atualizadas mais recentemente primeiro)
+type GetPositionsInBoundingBoxResponse struct {
+	Positions   []BoundingBoxPositionResponse `json:"positions"`
+	BoundingBox SectorBounds                  `json:"bounding_box"`
+	AreaKm2     float64                       `json:"area_km2"`
+	TotalFound  int                           `json:"total_found"`
+	Limit       int                           `json:"limit"`
+	Message     string                        `json:"message"`
+}
+
+// GetPositionsInBoundingBoxUseCase busca as posições atuais dentro de um retângulo (viewport do mapa)
+type GetPositionsInBoundingBoxUseCase struct {
+	positionRepo repository.PositionRepository
+	logger       logger.Logger
+}
+
+// NewGetPositionsInBoundingBoxUseCase cria uma nova instância do use case
+func NewGetPositionsInBoundingBoxUseCase(
+	positionRepo repository.PositionRepository,
+	logger logger.Logger,
+) *GetPositionsInBoundingBoxUseCase {
+	return &GetPositionsInBoundingBoxUseCase{
+		positionRepo: positionRepo,
+		logger:       logger,
+	}
+}
+
+// Execute executa o use case de busca por retângulo
+func (uc *GetPositionsInBoundingBoxUseCase) Execute(ctx context.Context, req GetPositionsInBoundingBoxRequest) (*GetPositionsInBoundingBoxResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	// 1. Validar o retângulo e a área
+	bbox := &repository.BoundingBox{
+		MinLatitude:  req.MinLatitude,
+		MaxLatitude:  req.MaxLatitude,
+		MinLongitude: req.MinLongitude,
+		MaxLongitude: req.MaxLongitude,
+	}
+	if err := validatePositionsBoundingBox(bbox); err != nil {
+		return nil, err
+	}
+
+	area := boundingBoxAreaKm2(bbox)
+	if area > MaxBoundingBoxAreaKm2 {
+		return nil, fmt.Errorf("%w: bounding box area %.0f km² exceeds the maximum of %.0f km²", ErrInvalidInput, area, MaxBoundingBoxAreaKm2)
+	}
+
+	// 2. Normalizar limite
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultBoundingBoxLimit
+	}
+	if limit > MaxBoundingBoxLimit {
+		limit = MaxBoundingBoxLimit
+	}
+
+	// 3. Buscar posições atuais no retângulo
+	positions, err := uc.positionRepo.FindInBoundingBox(ctx, bbox, limit)
+	if err != nil {
+		log.Error("Failed to find positions in bounding box", map[string]interface{}{
+			"min_latitude":  bbox.MinLatitude,
+			"min_longitude": bbox.MinLongitude,
+			"max_latitude":  bbox.MaxLatitude,
+			"max_longitude": bbox.MaxLongitude,
+			"error":         err.Error(),
+		})
+		return nil, fmt.Errorf("failed to find positions in bounding box: %w", err)
+	}
+
+	// 4. Montar resposta
+	items := make([]BoundingBoxPositionResponse, 0, len(positions))
+	for _, position := range positions {
+		userID := position.UserID()
+		positionID := position.ID()
+
+		items = append(items, BoundingBoxPositionResponse{
+			UserID:     userID.Value(),
+			PositionID: positionID.Value(),
+			Latitude:   position.Latitude(),
+			Longitude:  position.Longitude(),
+			SectorID:   position.Sector().ID(),
+			RecordedAt: position.RecordedAt().Time(),
+		})
+	}
+
+	log.Info("Positions in bounding box retrieved", map[string]interface{}{
+		"area_km2": area,
+		"limit":    limit,
+		"found":    len(items),
+	})
+
+	return &GetPositionsInBoundingBoxResponse{
+		Positions: items,
+		BoundingBox: SectorBounds{
+			MinLatitude:  bbox.MinLatitude,
+			MaxLatitude:  bbox.MaxLatitude,
+			MinLongitude: bbox.MinLongitude,
+			MaxLongitude: bbox.MaxLongitude,
+		},
+		AreaKm2:    area,
+		TotalFound: len(items),
+		Limit:      limit,
+		Message:    fmt.Sprintf("Found %d users in bounding box", len(items)),
+	}, nil
+}
+
+// validatePositionsBoundingBox exige cantos válidos e min < max nos dois eixos
+func validatePositionsBoundingBox(bbox *repository.BoundingBox) error {
+	if _, err := valueobject.NewCoordinate(bbox.MinLatitude, bbox.MinLongitude); err != nil {
+		return fmt.Errorf("%w: invalid bounding box: %w", ErrInvalidInput, err)
+	}
+	if _, err := valueobject.NewCoordinate(bbox.MaxLatitude, bbox.MaxLongitude); err != nil {
+		return fmt.Errorf("%w: invalid bounding box: %w", ErrInvalidInput, err)
+	}
+	if bbox.MinLatitude >= bbox.MaxLatitude {
+		return fmt.Errorf("%w: min_lat must be less than max_lat", ErrInvalidInput)
+	}
+	if bbox.MinLongitude >= bbox.MaxLongitude {
+		return fmt.Errorf("%w: min_lng must be less than max_lng (bounding boxes crossing the antimeridian are not supported)", ErrInvalidInput)
+	}
+	return nil
+}
+
+// boundingBoxAreaKm2 aproxima a área do retângulo, com a largura medida na latitude central
+func boundingBoxAreaKm2(bbox *repository.BoundingBox) float64 {
+	midLatitude := (bbox.MinLatitude + bbox.MaxLatitude) / 2
+	heightKm := (bbox.MaxLatitude - bbox.MinLatitude) * valueobject.MetersPerDegreeLat / 1000
+	widthKm := (bbox.MaxLongitude - bbox.MinLongitude) * valueobject.MetersPerDegreeLngAtEquator *
+		math.Cos(midLatitude*math.Pi/180) / 1000
+	return heightKm * widthKm
+}
@@ -0,0 +1,151 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+)
+
+// GetPositionsInBoundingBoxUseCaseTestSuite define a suite de testes para GetPositionsInBoundingBoxUseCase
+type GetPositionsInBoundingBoxUseCaseTestSuite struct {
+	suite.Suite
+	positionRepo *mocks.MockPositionRepository
+	logger       *mocks.MockLogger
+	useCase      *usecase.GetPositionsInBoundingBoxUseCase
+	ctx          context.Context
+}
+
+// SetupTest configura cada teste
+func (suite *GetPositionsInBoundingBoxUseCaseTestSuite) SetupTest() {
+	suite.positionRepo = new(mocks.MockPositionRepository)
+	suite.logger = new(mocks.MockLogger)
+	suite.useCase = usecase.NewGetPositionsInBoundingBoxUseCase(suite.positionRepo, suite.logger)
+	suite.ctx = context.Background()
+}
+
+// TearDownTest limpa após cada teste
+func (suite *GetPositionsInBoundingBoxUseCaseTestSuite) TearDownTest() {
+	suite.positionRepo.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// centralSaoPaulo é um retângulo de ~11 km x ~10 km no centro de São Paulo
+func centralSaoPaulo() usecase.GetPositionsInBoundingBoxRequest {
+	return usecase.GetPositionsInBoundingBoxRequest{
+		MinLatitude:  -23.60,
+		MinLongitude: -46.70,
+		MaxLatitude:  -23.50,
+		MaxLongitude: -46.60,
+	}
+}
+
+// TestGetPositionsInBoundingBox_Success testa a busca com o limite padrão
+func (suite *GetPositionsInBoundingBoxUseCaseTestSuite) TestGetPositionsInBoundingBox_Success() {
+	// Arrange
+	userID, err := entity.NewUserID("user-a")
+	suite.Require().NoError(err)
+	position, err := entity.NewPosition("pos-a", *userID, -23.550520, -46.633309, time.Now().Add(-time.Minute))
+	suite.Require().NoError(err)
+
+	expected := &repository.BoundingBox{MinLatitude: -23.60, MaxLatitude: -23.50, MinLongitude: -46.70, MaxLongitude: -46.60}
+	suite.positionRepo.On("FindInBoundingBox", mock.Anything, expected, usecase.DefaultBoundingBoxLimit).
+		Return([]*entity.Position{position}, nil)
+	suite.logger.On("Info", "Positions in bounding box retrieved", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, centralSaoPaulo())
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(response.Positions, 1)
+	assert.Equal(suite.T(), "user-a", response.Positions[0].UserID)
+	assert.Equal(suite.T(), "pos-a", response.Positions[0].PositionID)
+	assert.Equal(suite.T(), position.Sector().ID(), response.Positions[0].SectorID)
+	assert.Equal(suite.T(), 1, response.TotalFound)
+	assert.Equal(suite.T(), usecase.DefaultBoundingBoxLimit, response.Limit)
+	assert.InDelta(suite.T(), 113.6, response.AreaKm2, 1)
+	assert.Equal(suite.T(), -23.60, response.BoundingBox.MinLatitude)
+}
+
+// TestGetPositionsInBoundingBox_LimitCapped testa que limites acima do teto são reduzidos
+func (suite *GetPositionsInBoundingBoxUseCaseTestSuite) TestGetPositionsInBoundingBox_LimitCapped() {
+	// Arrange
+	req := centralSaoPaulo()
+	req.Limit = usecase.MaxBoundingBoxLimit + 1
+
+	suite.positionRepo.On("FindInBoundingBox", mock.Anything, mock.Anything, usecase.MaxBoundingBoxLimit).
+		Return([]*entity.Position{}, nil)
+	suite.logger.On("Info", "Positions in bounding box retrieved", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, req)
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), response.Positions)
+	assert.Equal(suite.T(), usecase.MaxBoundingBoxLimit, response.Limit)
+}
+
+// TestGetPositionsInBoundingBox_InvalidBox testa retângulos rejeitados antes de consultar o banco
+func (suite *GetPositionsInBoundingBoxUseCaseTestSuite) TestGetPositionsInBoundingBox_InvalidBox() {
+	testCases := []struct {
+		name    string
+		request usecase.GetPositionsInBoundingBoxRequest
+	}{
+		{name: "latitude inverted", request: usecase.GetPositionsInBoundingBoxRequest{
+			MinLatitude: -23.50, MaxLatitude: -23.60, MinLongitude: -46.70, MaxLongitude: -46.60,
+		}},
+		{name: "latitude equal", request: usecase.GetPositionsInBoundingBoxRequest{
+			MinLatitude: -23.50, MaxLatitude: -23.50, MinLongitude: -46.70, MaxLongitude: -46.60,
+		}},
+		{name: "longitude inverted", request: usecase.GetPositionsInBoundingBoxRequest{
+			MinLatitude: -23.60, MaxLatitude: -23.50, MinLongitude: -46.60, MaxLongitude: -46.70,
+		}},
+		{name: "out of range", request: usecase.GetPositionsInBoundingBoxRequest{
+			MinLatitude: -23.60, MaxLatitude: 91, MinLongitude: -46.70, MaxLongitude: -46.60,
+		}},
+		{name: "area too large", request: usecase.GetPositionsInBoundingBoxRequest{
+			MinLatitude: -24, MaxLatitude: -23, MinLongitude: -47, MaxLongitude: -46,
+		}},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			response, err := suite.useCase.Execute(suite.ctx, tc.request)
+
+			assert.Nil(suite.T(), response)
+			assert.ErrorIs(suite.T(), err, usecase.ErrInvalidInput)
+		})
+	}
+	suite.positionRepo.AssertNotCalled(suite.T(), "FindInBoundingBox", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestGetPositionsInBoundingBox_RepositoryError testa a falha na busca
+func (suite *GetPositionsInBoundingBoxUseCaseTestSuite) TestGetPositionsInBoundingBox_RepositoryError() {
+	// Arrange
+	suite.positionRepo.On("FindInBoundingBox", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, errors.New("database error"))
+	suite.logger.On("Error", "Failed to find positions in bounding box", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, centralSaoPaulo())
+
+	// Assert
+	assert.Nil(suite.T(), response)
+	assert.Error(suite.T(), err)
+	assert.NotErrorIs(suite.T(), err, usecase.ErrInvalidInput)
+}
+
+// TestGetPositionsInBoundingBoxUseCase executa toda a suite de testes
+func TestGetPositionsInBoundingBoxUseCase(t *testing.T) {
+	suite.Run(t, new(GetPositionsInBoundingBoxUseCaseTestSuite))
+}
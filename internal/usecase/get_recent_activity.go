@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// Limites do feed de atividade recente
+const (
+	DefaultRecentActivityLimit = 20
+	MaxRecentActivityLimit     = 100
+)
+
+// GetRecentActivityRequest representa os dados de entrada
+type GetRecentActivityRequest struct {
+	Limit int `json:"limit" validate:"min=0,max=100"`
+}
+
+// RecentActivityResponse representa um item do feed
+type RecentActivityResponse struct {
+	UserID     string    `json:"user_id"`
+	UserName   string    `json:"user_name"`
+	PositionID string    `json:"position_id"`
+	Latitude   float64   `json:"latitude"`
+	Longitude  float64   `json:"longitude"`
+	SectorID   string    `json:"sector_id"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// GetRecentActivityResponse representa a resposta
+type GetRecentActivityResponse struct {
+	Items      []RecentActivityResponse `json:"items"`
+	TotalFound int                      `json:"total_found"`
+	Limit      int                      `json:"limit"`
+	Message    string                   `json:"message"`
+}
+
+// GetRecentActivityUseCase implementa o feed das posições atualizadas mais recentemente
+type GetRecentActivityUseCase struct {
+	positionRepo repository.PositionRepository
+	logger       logger.Logger
+	staleAfter   time.Duration
+}
+
+// NewGetRecentActivityUseCase cria uma nova instância do use case
+func NewGetRecentActivityUseCase(
+	positionRepo repository.PositionRepository,
+	logger logger.Logger,
+	cfg *config.Config,
+) *GetRecentActivityUseCase {
+	return &GetRecentActivityUseCase{
+		positionRepo: positionRepo,
+		logger:       logger,
+		staleAfter:   time.Duration(cfg.Positions.FeedStaleAfterSeconds) * time.Second,
+	}
+}
+
+// Execute executa o use case de feed de atividade recente
+func (uc *GetRecentActivityUseCase) Execute(ctx context.Context, req GetRecentActivityRequest) (*GetRecentActivityResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	// 1. Normalizar limite
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultRecentActivityLimit
+	}
+	if limit > MaxRecentActivityLimit {
+		limit = MaxRecentActivityLimit
+	}
+
+	// 2. Descartar posições paradas há mais que o configurado
+	var since *valueobject.Timestamp
+	if uc.staleAfter > 0 {
+		since = valueobject.NewTimestamp(time.Now().Add(-uc.staleAfter))
+	}
+
+	// 3. Buscar atividade (já ordenada da mais recente para a mais antiga)
+	activity, err := uc.positionRepo.FindRecentActivity(ctx, since, limit)
+	if err != nil {
+		log.Error("Failed to find recent activity", map[string]interface{}{
+			"limit": limit,
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to find recent activity: %w", err)
+	}
+
+	// 4. Montar resposta
+	items := make([]RecentActivityResponse, 0, len(activity))
+	for _, entry := range activity {
+		userID := entry.Position.UserID()
+		positionID := entry.Position.ID()
+
+		items = append(items, RecentActivityResponse{
+			UserID:     userID.Value(),
+			UserName:   entry.UserName,
+			PositionID: positionID.Value(),
+			Latitude:   entry.Position.Latitude(),
+			Longitude:  entry.Position.Longitude(),
+			SectorID:   entry.Position.Sector().ID(),
+			UpdatedAt:  entry.UpdatedAt.Time(),
+		})
+	}
+
+	log.Info("Recent activity feed retrieved", map[string]interface{}{
+		"limit": limit,
+		"found": len(items),
+	})
+
+	return &GetRecentActivityResponse{
+		Items:      items,
+		TotalFound: len(items),
+		Limit:      limit,
+		Message:    fmt.Sprintf("Found %d recently active users", len(items)),
+	}, nil
+}
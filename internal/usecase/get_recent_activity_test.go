@@ -0,0 +1,130 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+)
+
+// GetRecentActivityUseCaseTestSuite define a suite de testes para GetRecentActivityUseCase
+type GetRecentActivityUseCaseTestSuite struct {
+	suite.Suite
+	positionRepo *mocks.MockPositionRepository
+	logger       *mocks.MockLogger
+	useCase      *usecase.GetRecentActivityUseCase
+	ctx          context.Context
+}
+
+// SetupTest configura cada teste
+func (suite *GetRecentActivityUseCaseTestSuite) SetupTest() {
+	suite.positionRepo = new(mocks.MockPositionRepository)
+	suite.logger = new(mocks.MockLogger)
+	suite.useCase = usecase.NewGetRecentActivityUseCase(suite.positionRepo, suite.logger, &config.Config{})
+	suite.ctx = context.Background()
+}
+
+// TearDownTest limpa após cada teste
+func (suite *GetRecentActivityUseCaseTestSuite) TearDownTest() {
+	suite.positionRepo.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// recentActivity cria uma entrada do feed atualizada há `ago`
+func (suite *GetRecentActivityUseCaseTestSuite) recentActivity(userID, name string, ago time.Duration) *repository.RecentActivity {
+	id, err := entity.NewUserID(userID)
+	suite.Require().NoError(err)
+	position, err := entity.NewPosition("pos-"+userID, *id, -23.550520, -46.633309, time.Now().Add(-ago))
+	suite.Require().NoError(err)
+
+	return &repository.RecentActivity{
+		UserName:  name,
+		Position:  position,
+		UpdatedAt: valueobject.NewTimestamp(time.Now().Add(-ago)),
+	}
+}
+
+// TestGetRecentActivity_KeepsRecencyOrder testa que a ordem do repositório (mais recente primeiro) é mantida
+func (suite *GetRecentActivityUseCaseTestSuite) TestGetRecentActivity_KeepsRecencyOrder() {
+	activity := []*repository.RecentActivity{
+		suite.recentActivity("user-a", "Ana", time.Second),
+		suite.recentActivity("user-b", "Bruno", time.Minute),
+		suite.recentActivity("user-c", "Carla", time.Hour),
+	}
+
+	suite.positionRepo.On("FindRecentActivity", mock.Anything, (*valueobject.Timestamp)(nil), 10).
+		Return(activity, nil)
+	suite.logger.On("Info", "Recent activity feed retrieved", mock.Anything).Return()
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetRecentActivityRequest{Limit: 10})
+
+	suite.NoError(err)
+	suite.Equal(3, response.TotalFound)
+	suite.Equal([]string{"user-a", "user-b", "user-c"}, []string{
+		response.Items[0].UserID, response.Items[1].UserID, response.Items[2].UserID,
+	})
+	suite.Equal("Ana", response.Items[0].UserName)
+	suite.True(response.Items[0].UpdatedAt.After(response.Items[1].UpdatedAt))
+	suite.True(response.Items[1].UpdatedAt.After(response.Items[2].UpdatedAt))
+}
+
+// TestGetRecentActivity_LimitEnforcement testa o limite padrão e o teto de itens
+func (suite *GetRecentActivityUseCaseTestSuite) TestGetRecentActivity_LimitEnforcement() {
+	cases := map[int]int{
+		0:    usecase.DefaultRecentActivityLimit,
+		5:    5,
+		1000: usecase.MaxRecentActivityLimit,
+	}
+
+	suite.logger.On("Info", "Recent activity feed retrieved", mock.Anything).Return()
+	for requested, expected := range cases {
+		suite.positionRepo.On("FindRecentActivity", mock.Anything, mock.Anything, expected).
+			Return([]*repository.RecentActivity{}, nil).Once()
+
+		response, err := suite.useCase.Execute(suite.ctx, usecase.GetRecentActivityRequest{Limit: requested})
+
+		suite.NoError(err)
+		suite.Equal(expected, response.Limit)
+		suite.Empty(response.Items)
+	}
+}
+
+// TestGetRecentActivity_ExcludesStale testa o corte de posições paradas quando configurado
+func (suite *GetRecentActivityUseCaseTestSuite) TestGetRecentActivity_ExcludesStale() {
+	cfg := &config.Config{Positions: config.PositionsConfig{FeedStaleAfterSeconds: 600}}
+	useCase := usecase.NewGetRecentActivityUseCase(suite.positionRepo, suite.logger, cfg)
+
+	suite.positionRepo.On("FindRecentActivity", mock.Anything, mock.MatchedBy(func(since *valueobject.Timestamp) bool {
+		return since != nil && time.Since(since.Time()) >= 10*time.Minute && time.Since(since.Time()) < 11*time.Minute
+	}), usecase.DefaultRecentActivityLimit).Return([]*repository.RecentActivity{}, nil)
+	suite.logger.On("Info", "Recent activity feed retrieved", mock.Anything).Return()
+
+	_, err := useCase.Execute(suite.ctx, usecase.GetRecentActivityRequest{})
+	suite.NoError(err)
+}
+
+// TestGetRecentActivity_RepositoryError testa a propagação de erro do repositório
+func (suite *GetRecentActivityUseCaseTestSuite) TestGetRecentActivity_RepositoryError() {
+	suite.positionRepo.On("FindRecentActivity", mock.Anything, mock.Anything, usecase.DefaultRecentActivityLimit).
+		Return(nil, errors.New("db down"))
+	suite.logger.On("Error", "Failed to find recent activity", mock.Anything).Return()
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetRecentActivityRequest{})
+
+	suite.Error(err)
+	suite.Nil(response)
+}
+
+// TestGetRecentActivityUseCase executa toda a suite de testes
+func TestGetRecentActivityUseCase(t *testing.T) {
+	suite.Run(t, new(GetRecentActivityUseCaseTestSuite))
+}
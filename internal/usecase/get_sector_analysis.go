@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/service"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// GetSectorAnalysisRequest representa os dados de entrada
+type GetSectorAnalysisRequest struct {
+	SectorID string `json:"sector_id" validate:"required"`
+}
+
+// GetSectorAnalysisResponse representa a resposta
+type GetSectorAnalysisResponse struct {
+	SectorID       string                `json:"sector_id"`
+	X              int                   `json:"x"`
+	Y              int                   `json:"y"`
+	UserCount      int                   `json:"user_count"`
+	DensityPerKm2  float64               `json:"density_per_km2"`
+	Neighbors      []SectorCountResponse `json:"neighbors"` // Os 8 setores ao redor, com a contagem de cada um
+	NeighborsUsers int                   `json:"neighbors_users"`
+	Message        string                `json:"message"`
+}
+
+// GetSectorAnalysisUseCase expõe a análise do setor e dos vizinhos feita pelo domain service
+type GetSectorAnalysisUseCase struct {
+	geoService *service.GeoLocationService
+	logger     logger.Logger
+}
+
+// NewGetSectorAnalysisUseCase cria uma nova instância do use case
+func NewGetSectorAnalysisUseCase(
+	geoService *service.GeoLocationService,
+	logger logger.Logger,
+) *GetSectorAnalysisUseCase {
+	return &GetSectorAnalysisUseCase{
+		geoService: geoService,
+		logger:     logger,
+	}
+}
+
+// Execute executa o use case de análise do setor
+func (uc *GetSectorAnalysisUseCase) Execute(ctx context.Context, req GetSectorAnalysisRequest) (*GetSectorAnalysisResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	// 1. Converter ID para setor
+	sector, err := valueobject.ParseSectorID(req.SectorID)
+	if err != nil {
+		log.Error("Invalid sector ID", map[string]interface{}{
+			"sector_id": req.SectorID,
+			"error":     err.Error(),
+		})
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSectorID, err.Error())
+	}
+
+	// 2. Analisar o bloco 3x3 (uma única query)
+	analysis, err := uc.geoService.AnalyzeSector(ctx, sector)
+	if err != nil {
+		log.Error("Failed to analyze sector", map[string]interface{}{
+			"sector_id": req.SectorID,
+			"error":     err.Error(),
+		})
+		return nil, fmt.Errorf("failed to analyze sector: %w", err)
+	}
+
+	// 3. Preparar resposta
+	response := &GetSectorAnalysisResponse{
+		SectorID:      sector.ID(),
+		X:             sector.X(),
+		Y:             sector.Y(),
+		UserCount:     analysis.UserCount,
+		DensityPerKm2: analysis.Density,
+		Neighbors:     make([]SectorCountResponse, 0, len(analysis.Neighbors)),
+	}
+	for _, neighbor := range analysis.Neighbors {
+		response.Neighbors = append(response.Neighbors, SectorCountResponse{
+			SectorID:  neighbor.Sector.ID(),
+			X:         neighbor.Sector.X(),
+			Y:         neighbor.Sector.Y(),
+			UserCount: neighbor.UserCount,
+		})
+		response.NeighborsUsers += neighbor.UserCount
+	}
+	response.Message = fmt.Sprintf("Sector %s has %d users and %d users in %d neighbor sectors",
+		sector.ID(), response.UserCount, response.NeighborsUsers, len(response.Neighbors))
+
+	log.Info("Sector analysis completed", map[string]interface{}{
+		"sector_id":       sector.ID(),
+		"user_count":      response.UserCount,
+		"neighbors_users": response.NeighborsUsers,
+	})
+
+	return response, nil
+}
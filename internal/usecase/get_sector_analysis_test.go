@@ -0,0 +1,112 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/service"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+)
+
+// GetSectorAnalysisUseCaseTestSuite define a suite de testes para GetSectorAnalysisUseCase
+type GetSectorAnalysisUseCaseTestSuite struct {
+	suite.Suite
+	positionRepo *mocks.MockPositionRepository
+	logger       *mocks.MockLogger
+	useCase      *usecase.GetSectorAnalysisUseCase
+	ctx          context.Context
+}
+
+// SetupTest configura cada teste com o domain service real sobre o repository mockado
+func (suite *GetSectorAnalysisUseCaseTestSuite) SetupTest() {
+	suite.positionRepo = new(mocks.MockPositionRepository)
+	suite.logger = new(mocks.MockLogger)
+	suite.useCase = usecase.NewGetSectorAnalysisUseCase(service.NewGeoLocationService(suite.positionRepo), suite.logger)
+	suite.ctx = context.Background()
+}
+
+// TearDownTest limpa após cada teste
+func (suite *GetSectorAnalysisUseCaseTestSuite) TearDownTest() {
+	suite.positionRepo.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// positionInSector cria a posição atual de um usuário gravada no setor (x, y)
+func (suite *GetSectorAnalysisUseCaseTestSuite) positionInSector(id string, x, y int) *entity.Position {
+	userID, err := entity.NewUserID("user-" + id)
+	suite.Require().NoError(err)
+	now := time.Now()
+	position, err := entity.ReconstructPosition("pos-"+id, *userID, -23.550520, -46.633309, x, y, now, now)
+	suite.Require().NoError(err)
+	return position
+}
+
+// TestGetSectorAnalysis_CountsPerNeighbor testa a contagem do setor e de cada vizinho com uma única query
+func (suite *GetSectorAnalysisUseCaseTestSuite) TestGetSectorAnalysis_CountsPerNeighbor() {
+	// Arrange: 2 usuários no centro, 1 ao norte e 2 a sudoeste
+	positions := []*entity.Position{
+		suite.positionInSector("a", 10, 20),
+		suite.positionInSector("b", 10, 20),
+		suite.positionInSector("c", 10, 21),
+		suite.positionInSector("d", 9, 19),
+		suite.positionInSector("e", 9, 19),
+	}
+	suite.positionRepo.On("FindInSectors", mock.Anything, mock.MatchedBy(func(sectors []*valueobject.Sector) bool {
+		return len(sectors) == 9
+	})).Return(positions, nil).Once()
+	suite.logger.On("Info", "Sector analysis completed", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetSectorAnalysisRequest{SectorID: "sector_10_20"})
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "sector_10_20", response.SectorID)
+	assert.Equal(suite.T(), 2, response.UserCount)
+	assert.InDelta(suite.T(), 200, response.DensityPerKm2, 1e-9)
+	assert.Equal(suite.T(), 3, response.NeighborsUsers)
+	suite.Require().Len(response.Neighbors, 8)
+
+	counts := make(map[string]int, len(response.Neighbors))
+	for _, neighbor := range response.Neighbors {
+		counts[neighbor.SectorID] = neighbor.UserCount
+	}
+	assert.NotContains(suite.T(), counts, "sector_10_20")
+	assert.Equal(suite.T(), 1, counts["sector_10_21"])
+	assert.Equal(suite.T(), 2, counts["sector_9_19"])
+	assert.Equal(suite.T(), 0, counts["sector_11_20"])
+}
+
+// TestGetSectorAnalysis_InvalidSectorID testa a rejeição de um ID fora do formato
+func (suite *GetSectorAnalysisUseCaseTestSuite) TestGetSectorAnalysis_InvalidSectorID() {
+	suite.logger.On("Error", "Invalid sector ID", mock.Anything).Return()
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetSectorAnalysisRequest{SectorID: "sector_x"})
+
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, usecase.ErrInvalidSectorID)
+}
+
+// TestGetSectorAnalysis_RepositoryError testa a falha da query do bloco
+func (suite *GetSectorAnalysisUseCaseTestSuite) TestGetSectorAnalysis_RepositoryError() {
+	suite.positionRepo.On("FindInSectors", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+	suite.logger.On("Error", "Failed to analyze sector", mock.Anything).Return()
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetSectorAnalysisRequest{SectorID: "sector_1_2"})
+
+	assert.Nil(suite.T(), response)
+	assert.Error(suite.T(), err)
+}
+
+// TestGetSectorAnalysisUseCase executa toda a suite de testes
+func TestGetSectorAnalysisUseCase(t *testing.T) {
+	suite.Run(t, new(GetSectorAnalysisUseCaseTestSuite))
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/service"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// GetSectorDensityRequest representa os dados de entrada
+type GetSectorDensityRequest struct {
+	SectorID string `json:"sector_id" validate:"required"`
+}
+
+// GetSectorDensityResponse representa a resposta
+type GetSectorDensityResponse struct {
+	SectorID                    string  `json:"sector_id"`
+	X                           int     `json:"x"`
+	Y                           int     `json:"y"`
+	AreaKm2                     float64 `json:"area_km2"`
+	DensityPerKm2               float64 `json:"density_per_km2"` // Usuários com posição atual no setor por km²
+	CurrentSectorSizeMeters     float64 `json:"current_sector_size_meters"`
+	RecommendedSectorSizeMeters float64 `json:"recommended_sector_size_meters"` // Heurística de CalculateOptimalSectorSize
+	Message                     string  `json:"message"`
+}
+
+// GetSectorDensityUseCase calcula a densidade atual de um setor e o tamanho de setor recomendado
+type GetSectorDensityUseCase struct {
+	geoService *service.GeoLocationService
+	logger     logger.Logger
+}
+
+// NewGetSectorDensityUseCase cria uma nova instância do use case
+func NewGetSectorDensityUseCase(
+	geoService *service.GeoLocationService,
+	logger logger.Logger,
+) *GetSectorDensityUseCase {
+	return &GetSectorDensityUseCase{
+		geoService: geoService,
+		logger:     logger,
+	}
+}
+
+// Execute executa o use case de densidade do setor
+func (uc *GetSectorDensityUseCase) Execute(ctx context.Context, req GetSectorDensityRequest) (*GetSectorDensityResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	// 1. Converter ID para setor
+	sector, err := valueobject.ParseSectorID(req.SectorID)
+	if err != nil {
+		log.Error("Invalid sector ID", map[string]interface{}{
+			"sector_id": req.SectorID,
+			"error":     err.Error(),
+		})
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSectorID, err.Error())
+	}
+
+	// 2. Calcular densidade a partir das posições atuais
+	density, err := uc.geoService.ComputeSectorDensity(ctx, sector)
+	if err != nil {
+		log.Error("Failed to compute sector density", map[string]interface{}{
+			"sector_id": req.SectorID,
+			"error":     err.Error(),
+		})
+		return nil, fmt.Errorf("failed to compute sector density: %w", err)
+	}
+
+	// 3. Aplicar a heurística de tamanho ótimo
+	recommended := uc.geoService.CalculateOptimalSectorSize(density)
+
+	response := &GetSectorDensityResponse{
+		SectorID:                    sector.ID(),
+		X:                           sector.X(),
+		Y:                           sector.Y(),
+		AreaKm2:                     service.SectorAreaKm2,
+		DensityPerKm2:               density,
+		CurrentSectorSizeMeters:     valueobject.SectorSizeMeters,
+		RecommendedSectorSizeMeters: recommended,
+		Message: fmt.Sprintf("Sector %s has %.0f users/km², recommended sector size is %.0fm",
+			sector.ID(), density, recommended),
+	}
+
+	log.Info("Sector density computed", map[string]interface{}{
+		"sector_id":        sector.ID(),
+		"density_per_km2":  density,
+		"recommended_size": recommended,
+	})
+
+	return response, nil
+}
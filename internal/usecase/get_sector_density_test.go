@@ -0,0 +1,118 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/service"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+)
+
+// GetSectorDensityUseCaseTestSuite define a suite de testes para GetSectorDensityUseCase
+type GetSectorDensityUseCaseTestSuite struct {
+	suite.Suite
+	positionRepo *mocks.MockPositionRepository
+	logger       *mocks.MockLogger
+	useCase      *usecase.GetSectorDensityUseCase
+	ctx          context.Context
+}
+
+// SetupTest configura cada teste com o domain service real sobre o repository mockado
+func (suite *GetSectorDensityUseCaseTestSuite) SetupTest() {
+	suite.positionRepo = new(mocks.MockPositionRepository)
+	suite.logger = new(mocks.MockLogger)
+	suite.useCase = usecase.NewGetSectorDensityUseCase(service.NewGeoLocationService(suite.positionRepo), suite.logger)
+	suite.ctx = context.Background()
+}
+
+// TearDownTest limpa após cada teste
+func (suite *GetSectorDensityUseCaseTestSuite) TearDownTest() {
+	suite.positionRepo.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// positions cria n posições atuais de usuários distintos
+func (suite *GetSectorDensityUseCaseTestSuite) positions(n int) []*entity.Position {
+	positions := make([]*entity.Position, 0, n)
+	for i := 0; i < n; i++ {
+		userID, err := entity.NewUserID(fmt.Sprintf("user-%d", i))
+		suite.Require().NoError(err)
+		position, err := entity.NewPosition(fmt.Sprintf("pos-%d", i), *userID, -23.550520, -46.633309, time.Now())
+		suite.Require().NoError(err)
+		positions = append(positions, position)
+	}
+	return positions
+}
+
+// TestGetSectorDensity_RecommendedSize testa a densidade sobre 0.01 km² e cada faixa da heurística
+func (suite *GetSectorDensityUseCaseTestSuite) TestGetSectorDensity_RecommendedSize() {
+	testCases := []struct {
+		users           int
+		wantDensity     float64
+		wantRecommended float64
+	}{
+		{users: 0, wantDensity: 0, wantRecommended: 500},
+		{users: 1, wantDensity: 100, wantRecommended: 100},
+		{users: 10, wantDensity: 1000, wantRecommended: 50},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(fmt.Sprintf("%d usuários", tc.users), func() {
+			// Mocks próprios por caso: cada um espera exatamente uma contagem
+			positionRepo := new(mocks.MockPositionRepository)
+			logger := new(mocks.MockLogger)
+			useCase := usecase.NewGetSectorDensityUseCase(service.NewGeoLocationService(positionRepo), logger)
+
+			positionRepo.On("FindInSector", mock.Anything, mock.MatchedBy(func(sector *valueobject.Sector) bool {
+				return sector.X() == -518 && sector.Y() == -2616
+			})).Return(suite.positions(tc.users), nil).Once()
+			logger.On("Info", "Sector density computed", mock.Anything).Return()
+
+			response, err := useCase.Execute(suite.ctx, usecase.GetSectorDensityRequest{SectorID: "sector_-518_-2616"})
+
+			suite.Require().NoError(err)
+			assert.Equal(suite.T(), "sector_-518_-2616", response.SectorID)
+			assert.InDelta(suite.T(), 0.01, response.AreaKm2, 1e-12)
+			assert.InDelta(suite.T(), tc.wantDensity, response.DensityPerKm2, 1e-9)
+			assert.Equal(suite.T(), float64(valueobject.SectorSizeMeters), response.CurrentSectorSizeMeters)
+			assert.Equal(suite.T(), tc.wantRecommended, response.RecommendedSectorSizeMeters)
+			positionRepo.AssertExpectations(suite.T())
+			logger.AssertExpectations(suite.T())
+		})
+	}
+}
+
+// TestGetSectorDensity_InvalidSectorID testa a rejeição de um ID fora do formato
+func (suite *GetSectorDensityUseCaseTestSuite) TestGetSectorDensity_InvalidSectorID() {
+	suite.logger.On("Error", "Invalid sector ID", mock.Anything).Return()
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetSectorDensityRequest{SectorID: "invalid"})
+
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, usecase.ErrInvalidSectorID)
+}
+
+// TestGetSectorDensity_RepositoryError testa a falha na contagem do setor
+func (suite *GetSectorDensityUseCaseTestSuite) TestGetSectorDensity_RepositoryError() {
+	suite.positionRepo.On("FindInSector", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+	suite.logger.On("Error", "Failed to compute sector density", mock.Anything).Return()
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetSectorDensityRequest{SectorID: "sector_1_2"})
+
+	assert.Nil(suite.T(), response)
+	assert.Error(suite.T(), err)
+}
+
+// TestGetSectorDensityUseCase executa toda a suite de testes
+func TestGetSectorDensityUseCase(t *testing.T) {
+	suite.Run(t, new(GetSectorDensityUseCaseTestSuite))
+}
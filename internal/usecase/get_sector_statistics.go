@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// ErrInvalidSectorID indica um ID de setor fora do formato sector_x_y
+var ErrInvalidSectorID = errors.New("invalid sector ID")
+
+// GetSectorStatisticsRequest representa os dados de entrada
+type GetSectorStatisticsRequest struct {
+	SectorID string `json:"sector_id" validate:"required"`
+}
+
+// GetSectorStatisticsResponse representa a resposta
+type GetSectorStatisticsResponse struct {
+	SectorID      string     `json:"sector_id"`
+	X             int        `json:"x"`
+	Y             int        `json:"y"`
+	UserCount     int        `json:"user_count"`
+	PositionCount int        `json:"position_count"`
+	LastActivity  *time.Time `json:"last_activity,omitempty"`
+	Message       string     `json:"message"`
+}
+
+// GetSectorStatisticsUseCase implementa a consulta de estatísticas de um setor (heatmaps)
+type GetSectorStatisticsUseCase struct {
+	positionRepo repository.PositionRepository
+	logger       logger.Logger
+}
+
+// NewGetSectorStatisticsUseCase cria uma nova instância do use case
+func NewGetSectorStatisticsUseCase(
+	positionRepo repository.PositionRepository,
+	logger logger.Logger,
+) *GetSectorStatisticsUseCase {
+	return &GetSectorStatisticsUseCase{
+		positionRepo: positionRepo,
+		logger:       logger,
+	}
+}
+
+// Execute executa o use case de estatísticas do setor
+func (uc *GetSectorStatisticsUseCase) Execute(ctx context.Context, req GetSectorStatisticsRequest) (*GetSectorStatisticsResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	// 1. Converter ID para setor
+	sector, err := valueobject.ParseSectorID(req.SectorID)
+	if err != nil {
+		log.Error("Invalid sector ID", map[string]interface{}{
+			"sector_id": req.SectorID,
+			"error":     err.Error(),
+		})
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSectorID, err.Error())
+	}
+
+	// 2. Buscar estatísticas
+	stats, err := uc.positionRepo.GetSectorStatistics(ctx, sector)
+	if err != nil {
+		log.Error("Failed to get sector statistics", map[string]interface{}{
+			"sector_id": req.SectorID,
+			"error":     err.Error(),
+		})
+		return nil, fmt.Errorf("failed to get sector statistics: %w", err)
+	}
+
+	// 3. Preparar resposta
+	response := &GetSectorStatisticsResponse{
+		SectorID:      sector.ID(),
+		X:             sector.X(),
+		Y:             sector.Y(),
+		UserCount:     stats.UserCount,
+		PositionCount: stats.PositionCount,
+		Message:       fmt.Sprintf("Sector %s has %d users", sector.ID(), stats.UserCount),
+	}
+	if stats.LastActivity != nil {
+		lastActivity := stats.LastActivity.Time()
+		response.LastActivity = &lastActivity
+	}
+
+	log.Info("Sector statistics retrieved", map[string]interface{}{
+		"sector_id":      sector.ID(),
+		"user_count":     stats.UserCount,
+		"position_count": stats.PositionCount,
+	})
+
+	return response, nil
+}
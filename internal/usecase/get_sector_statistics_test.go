@@ -0,0 +1,117 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+)
+
+// GetSectorStatisticsUseCaseTestSuite define a suite de testes para GetSectorStatisticsUseCase
+type GetSectorStatisticsUseCaseTestSuite struct {
+	suite.Suite
+	positionRepo *mocks.MockPositionRepository
+	logger       *mocks.MockLogger
+	useCase      *usecase.GetSectorStatisticsUseCase
+	ctx          context.Context
+}
+
+// SetupTest configura cada teste
+func (suite *GetSectorStatisticsUseCaseTestSuite) SetupTest() {
+	suite.positionRepo = new(mocks.MockPositionRepository)
+	suite.logger = new(mocks.MockLogger)
+	suite.useCase = usecase.NewGetSectorStatisticsUseCase(suite.positionRepo, suite.logger)
+	suite.ctx = context.Background()
+}
+
+// TearDownTest limpa após cada teste
+func (suite *GetSectorStatisticsUseCaseTestSuite) TearDownTest() {
+	suite.positionRepo.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// TestGetSectorStatistics_Success testa estatísticas de um setor com atividade
+func (suite *GetSectorStatisticsUseCaseTestSuite) TestGetSectorStatistics_Success() {
+	// Arrange
+	lastActivity := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	suite.positionRepo.On("GetSectorStatistics", mock.Anything, mock.MatchedBy(func(sector *valueobject.Sector) bool {
+		return sector.X() == -518 && sector.Y() == -2616
+	})).Return(&repository.SectorStats{
+		UserCount:     3,
+		PositionCount: 42,
+		LastActivity:  valueobject.NewTimestamp(lastActivity),
+	}, nil)
+
+	suite.logger.On("Info", "Sector statistics retrieved", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetSectorStatisticsRequest{SectorID: "sector_-518_-2616"})
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "sector_-518_-2616", response.SectorID)
+	assert.Equal(suite.T(), -518, response.X)
+	assert.Equal(suite.T(), -2616, response.Y)
+	assert.Equal(suite.T(), 3, response.UserCount)
+	assert.Equal(suite.T(), 42, response.PositionCount)
+	suite.Require().NotNil(response.LastActivity)
+	assert.True(suite.T(), lastActivity.Equal(*response.LastActivity))
+}
+
+// TestGetSectorStatistics_EmptySector testa setor sem atividade
+func (suite *GetSectorStatisticsUseCaseTestSuite) TestGetSectorStatistics_EmptySector() {
+	// Arrange
+	suite.positionRepo.On("GetSectorStatistics", mock.Anything, mock.Anything).
+		Return(&repository.SectorStats{}, nil)
+	suite.logger.On("Info", "Sector statistics retrieved", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetSectorStatisticsRequest{SectorID: "sector_0_0"})
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, response.UserCount)
+	assert.Nil(suite.T(), response.LastActivity)
+}
+
+// TestGetSectorStatistics_InvalidSectorID testa ID fora do formato sector_x_y
+func (suite *GetSectorStatisticsUseCaseTestSuite) TestGetSectorStatistics_InvalidSectorID() {
+	// Arrange
+	suite.logger.On("Error", "Invalid sector ID", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetSectorStatisticsRequest{SectorID: "abc"})
+
+	// Assert
+	assert.ErrorIs(suite.T(), err, usecase.ErrInvalidSectorID)
+	assert.Nil(suite.T(), response)
+}
+
+// TestGetSectorStatistics_RepositoryError testa erro do repositório
+func (suite *GetSectorStatisticsUseCaseTestSuite) TestGetSectorStatistics_RepositoryError() {
+	// Arrange
+	suite.positionRepo.On("GetSectorStatistics", mock.Anything, mock.Anything).
+		Return(nil, errors.New("database connection failed"))
+	suite.logger.On("Error", "Failed to get sector statistics", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetSectorStatisticsRequest{SectorID: "sector_1_2"})
+
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), response)
+	assert.Contains(suite.T(), err.Error(), "failed to get sector statistics")
+}
+
+// TestGetSectorStatisticsUseCase executa toda a suite de testes
+func TestGetSectorStatisticsUseCase(t *testing.T) {
+	suite.Run(t, new(GetSectorStatisticsUseCaseTestSuite))
+}
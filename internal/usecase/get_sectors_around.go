@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// Limites de anéis para a busca de setores ao redor
+const (
+	DefaultSectorRings = 1
+	MaxSectorRings     = 10 // 21x21 = 441 setores
+)
+
+// GetSectorsAroundRequest representa os dados de entrada
+type GetSectorsAroundRequest struct {
+	Latitude  float64 `json:"latitude" validate:"required,min=-90,max=90"`
+	Longitude float64 `json:"longitude" validate:"required,min=-180,max=180"`
+	Rings     int     `json:"rings" validate:"min=0,max=10"`
+}
+
+// SectorCountResponse representa a contagem de usuários de um setor
+type SectorCountResponse struct {
+	SectorID  string `json:"sector_id"`
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+	UserCount int    `json:"user_count"`
+}
+
+// GetSectorsAroundResponse representa a resposta
+// Grid é organizado em linhas do norte para o sul e colunas do oeste para o leste
+type GetSectorsAroundResponse struct {
+	CenterSectorID string                  `json:"center_sector_id"`
+	Rings          int                     `json:"rings"`
+	Grid           [][]SectorCountResponse `json:"grid"`
+	TotalSectors   int                     `json:"total_sectors"`
+	TotalUsers     int                     `json:"total_users"`
+	Message        string                  `json:"message"`
+}
+
+// GetSectorsAroundUseCase implementa a contagem de usuários nos setores ao redor de um ponto
+type GetSectorsAroundUseCase struct {
+	positionRepo repository.PositionRepository
+	logger       logger.Logger
+}
+
+// NewGetSectorsAroundUseCase cria uma nova instância do use case
+func NewGetSectorsAroundUseCase(
+	positionRepo repository.PositionRepository,
+	logger logger.Logger,
+) *GetSectorsAroundUseCase {
+	return &GetSectorsAroundUseCase{
+		positionRepo: positionRepo,
+		logger:       logger,
+	}
+}
+
+// Execute executa o use case de contagem de usuários nos setores ao redor
+func (uc *GetSectorsAroundUseCase) Execute(ctx context.Context, req GetSectorsAroundRequest) (*GetSectorsAroundResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	// 1. Validar parâmetros
+	rings := req.Rings
+	if rings <= 0 {
+		rings = DefaultSectorRings
+	}
+	if rings > MaxSectorRings {
+		rings = MaxSectorRings
+	}
+
+	// 2. Validar coordenadas e calcular setor central
+	coordinate, err := valueobject.NewCoordinate(req.Latitude, req.Longitude)
+	if err != nil {
+		log.Error("Invalid coordinates", map[string]interface{}{
+			"latitude":  req.Latitude,
+			"longitude": req.Longitude,
+			"error":     err.Error(),
+		})
+		return nil, fmt.Errorf("%w: invalid coordinates: %w", ErrInvalidInput, err)
+	}
+
+	center, err := valueobject.NewSectorFromCoordinate(coordinate)
+	if err != nil {
+		log.Error("Failed to create sector", map[string]interface{}{
+			"latitude":  req.Latitude,
+			"longitude": req.Longitude,
+			"error":     err.Error(),
+		})
+		return nil, fmt.Errorf("failed to create sector: %w", err)
+	}
+
+	// 3. Buscar posições atuais em todos os setores de uma vez
+	sectors := center.GetSectorsInRings(rings)
+	positions, err := uc.positionRepo.FindInSectors(ctx, sectors)
+	if err != nil {
+		log.Error("Failed to find positions in sectors", map[string]interface{}{
+			"sector_id": center.ID(),
+			"rings":     rings,
+			"error":     err.Error(),
+		})
+		return nil, fmt.Errorf("failed to find positions in sectors: %w", err)
+	}
+
+	// 4. Contar usuários por setor
+	counts := make(map[string]int, len(sectors))
+	for _, position := range positions {
+		counts[position.Sector().ID()]++
+	}
+
+	// 5. Montar grid (norte -> sul, oeste -> leste)
+	grid := make([][]SectorCountResponse, 0, 2*rings+1)
+	for y := center.Y() + rings; y >= center.Y()-rings; y-- {
+		row := make([]SectorCountResponse, 0, 2*rings+1)
+		for x := center.X() - rings; x <= center.X()+rings; x++ {
+			sector, err := valueobject.NewSector(x, y)
+			if err != nil {
+				continue // Fora dos limites do grid
+			}
+			row = append(row, SectorCountResponse{
+				SectorID:  sector.ID(),
+				X:         x,
+				Y:         y,
+				UserCount: counts[sector.ID()],
+			})
+		}
+		if len(row) > 0 {
+			grid = append(grid, row)
+		}
+	}
+
+	// 6. Log de sucesso
+	log.Info("Sectors around search completed", map[string]interface{}{
+		"sector_id":     center.ID(),
+		"rings":         rings,
+		"total_sectors": len(sectors),
+		"total_users":   len(positions),
+	})
+
+	return &GetSectorsAroundResponse{
+		CenterSectorID: center.ID(),
+		Rings:          rings,
+		Grid:           grid,
+		TotalSectors:   len(sectors),
+		TotalUsers:     len(positions),
+		Message:        fmt.Sprintf("Found %d users in %d sectors around %s", len(positions), len(sectors), center.ID()),
+	}, nil
+}
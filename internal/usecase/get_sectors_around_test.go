@@ -0,0 +1,176 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+)
+
+// GetSectorsAroundUseCaseTestSuite define a suite de testes para GetSectorsAroundUseCase
+type GetSectorsAroundUseCaseTestSuite struct {
+	suite.Suite
+	positionRepo *mocks.MockPositionRepository
+	logger       *mocks.MockLogger
+	useCase      *usecase.GetSectorsAroundUseCase
+	ctx          context.Context
+}
+
+// SetupTest configura cada teste
+func (suite *GetSectorsAroundUseCaseTestSuite) SetupTest() {
+	suite.positionRepo = new(mocks.MockPositionRepository)
+	suite.logger = new(mocks.MockLogger)
+	suite.useCase = usecase.NewGetSectorsAroundUseCase(suite.positionRepo, suite.logger)
+	suite.ctx = context.Background()
+}
+
+// TearDownTest limpa após cada teste
+func (suite *GetSectorsAroundUseCaseTestSuite) TearDownTest() {
+	suite.positionRepo.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// TestGetSectorsAround_OneRing testa grid 3x3 (9 setores)
+func (suite *GetSectorsAroundUseCaseTestSuite) TestGetSectorsAround_OneRing() {
+	// Arrange
+	request := usecase.GetSectorsAroundRequest{
+		Latitude:  -23.550520,
+		Longitude: -46.633309,
+		Rings:     1,
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+
+	// Dois usuários no setor central
+	centerPosition1, err := entity.NewPosition("pos-1", *userID, -23.550520, -46.633309, time.Now())
+	suite.Require().NoError(err)
+	centerPosition2, err := entity.NewPosition("pos-2", *userID, -23.550520, -46.633309, time.Now())
+	suite.Require().NoError(err)
+
+	// Mock: busca com 9 setores
+	suite.positionRepo.On("FindInSectors", mock.Anything, mock.MatchedBy(func(sectors []*valueobject.Sector) bool {
+		return len(sectors) == 9
+	})).Return([]*entity.Position{centerPosition1, centerPosition2}, nil)
+
+	// Mock: log de sucesso
+	suite.logger.On("Info", "Sectors around search completed", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), response)
+	assert.Equal(suite.T(), 1, response.Rings)
+	assert.Equal(suite.T(), 9, response.TotalSectors)
+	assert.Equal(suite.T(), 2, response.TotalUsers)
+	assert.Len(suite.T(), response.Grid, 3)
+	for _, row := range response.Grid {
+		assert.Len(suite.T(), row, 3)
+	}
+
+	// Setor central fica no meio do grid
+	center := response.Grid[1][1]
+	assert.Equal(suite.T(), response.CenterSectorID, center.SectorID)
+	assert.Equal(suite.T(), 2, center.UserCount)
+	assert.Equal(suite.T(), 0, response.Grid[0][0].UserCount)
+
+	// Primeira linha é a mais ao norte, primeira coluna a mais a oeste
+	assert.Equal(suite.T(), center.Y+1, response.Grid[0][1].Y)
+	assert.Equal(suite.T(), center.X-1, response.Grid[1][0].X)
+}
+
+// TestGetSectorsAround_TwoRings testa grid 5x5 (25 setores)
+func (suite *GetSectorsAroundUseCaseTestSuite) TestGetSectorsAround_TwoRings() {
+	// Arrange
+	request := usecase.GetSectorsAroundRequest{
+		Latitude:  -23.550520,
+		Longitude: -46.633309,
+		Rings:     2,
+	}
+
+	// Mock: busca com 25 setores
+	suite.positionRepo.On("FindInSectors", mock.Anything, mock.MatchedBy(func(sectors []*valueobject.Sector) bool {
+		return len(sectors) == 25
+	})).Return([]*entity.Position{}, nil)
+
+	// Mock: log de sucesso
+	suite.logger.On("Info", "Sectors around search completed", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), response)
+	assert.Equal(suite.T(), 25, response.TotalSectors)
+	assert.Equal(suite.T(), 0, response.TotalUsers)
+	assert.Len(suite.T(), response.Grid, 5)
+	for _, row := range response.Grid {
+		assert.Len(suite.T(), row, 5)
+	}
+	assert.Equal(suite.T(), response.CenterSectorID, response.Grid[2][2].SectorID)
+}
+
+// TestGetSectorsAround_DefaultRings testa valor padrão de anéis
+func (suite *GetSectorsAroundUseCaseTestSuite) TestGetSectorsAround_DefaultRings() {
+	// Arrange
+	request := usecase.GetSectorsAroundRequest{
+		Latitude:  -23.550520,
+		Longitude: -46.633309,
+	}
+
+	suite.positionRepo.On("FindInSectors", mock.Anything, mock.MatchedBy(func(sectors []*valueobject.Sector) bool {
+		return len(sectors) == 9
+	})).Return([]*entity.Position{}, nil)
+
+	suite.logger.On("Info", "Sectors around search completed", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), usecase.DefaultSectorRings, response.Rings)
+}
+
+// TestGetSectorsAround_RepositoryError testa erro ao buscar posições
+func (suite *GetSectorsAroundUseCaseTestSuite) TestGetSectorsAround_RepositoryError() {
+	// Arrange
+	request := usecase.GetSectorsAroundRequest{
+		Latitude:  -23.550520,
+		Longitude: -46.633309,
+		Rings:     1,
+	}
+
+	suite.positionRepo.On("FindInSectors", mock.Anything, mock.Anything).
+		Return(nil, errors.New("database connection failed"))
+
+	suite.logger.On("Error", "Failed to find positions in sectors", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), response)
+	assert.Contains(suite.T(), err.Error(), "failed to find positions in sectors")
+}
+
+// TestGetSectorsAroundUseCase executa toda a suite de testes
+func TestGetSectorsAroundUseCase(t *testing.T) {
+	suite.Run(t, new(GetSectorsAroundUseCaseTestSuite))
+}
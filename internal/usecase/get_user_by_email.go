@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// GetUserByEmailRequest representa os dados de entrada
+type GetUserByEmailRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// GetUserByEmailResponse representa a resposta
+type GetUserByEmailResponse struct {
+	UserID    string    `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	Message   string    `json:"message"`
+}
+
+// GetUserByEmailUseCase implementa a busca de usuário pelo email
+// Útil para clientes que conhecem o email do participante, mas não o ID gerado
+type GetUserByEmailUseCase struct {
+	userRepo repository.UserRepository
+	logger   logger.Logger
+}
+
+// NewGetUserByEmailUseCase cria uma nova instância do use case
+func NewGetUserByEmailUseCase(
+	userRepo repository.UserRepository,
+	logger logger.Logger,
+) *GetUserByEmailUseCase {
+	return &GetUserByEmailUseCase{
+		userRepo: userRepo,
+		logger:   logger,
+	}
+}
+
+// Execute executa o use case de buscar usuário por email
+func (uc *GetUserByEmailUseCase) Execute(ctx context.Context, req GetUserByEmailRequest) (*GetUserByEmailResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	// 1. Validar email
+	email, err := entity.NewEmail(req.Email)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid email: %w", ErrInvalidInput, err)
+	}
+
+	// 2. Buscar usuário
+	user, err := uc.userRepo.FindByEmail(ctx, *email)
+	if err != nil {
+		if errors.Is(err, entity.ErrEmailNotFound) {
+			return nil, fmt.Errorf("%w: no user with email %s", ErrUserNotFound, email.Value())
+		}
+		log.Error("Failed to find user by email", map[string]interface{}{
+			"email": email.Value(),
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	// 3. Montar resposta
+	userID := user.ID()
+	return &GetUserByEmailResponse{
+		UserID:    userID.String(),
+		Name:      user.Name(),
+		Email:     email.Value(),
+		CreatedAt: user.CreatedAt().Time(),
+		Message:   "User retrieved successfully",
+	}, nil
+}
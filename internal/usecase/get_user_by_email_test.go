@@ -0,0 +1,100 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+)
+
+// GetUserByEmailUseCaseTestSuite define a suite de testes para GetUserByEmailUseCase
+type GetUserByEmailUseCaseTestSuite struct {
+	suite.Suite
+	useCase  *usecase.GetUserByEmailUseCase
+	userRepo *mocks.MockUserRepository
+	logger   *mocks.MockLogger
+	ctx      context.Context
+}
+
+// SetupTest configura cada teste
+func (suite *GetUserByEmailUseCaseTestSuite) SetupTest() {
+	suite.userRepo = new(mocks.MockUserRepository)
+	suite.logger = new(mocks.MockLogger)
+	suite.ctx = context.Background()
+	suite.useCase = usecase.NewGetUserByEmailUseCase(suite.userRepo, suite.logger)
+}
+
+// TearDownTest limpa após cada teste
+func (suite *GetUserByEmailUseCaseTestSuite) TearDownTest() {
+	suite.userRepo.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// emailMatcher compara o email normalizado passado ao repositório
+func emailMatcher(expected string) interface{} {
+	return mock.MatchedBy(func(email entity.Email) bool {
+		return email.Value() == expected
+	})
+}
+
+// TestGetUserByEmail_Success testa a busca com email normalizado
+func (suite *GetUserByEmailUseCaseTestSuite) TestGetUserByEmail_Success() {
+	// Arrange
+	user, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+
+	suite.userRepo.On("FindByEmail", mock.Anything, emailMatcher("joao@example.com")).Return(user, nil)
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetUserByEmailRequest{Email: "  Joao@Example.com "})
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "user123", response.UserID)
+	assert.Equal(suite.T(), "João Silva", response.Name)
+	assert.Equal(suite.T(), "joao@example.com", response.Email)
+}
+
+// TestGetUserByEmail_InvalidEmail testa que email mal formado não consulta o banco
+func (suite *GetUserByEmailUseCaseTestSuite) TestGetUserByEmail_InvalidEmail() {
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetUserByEmailRequest{Email: "not-an-email"})
+
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, usecase.ErrInvalidInput)
+	suite.userRepo.AssertNotCalled(suite.T(), "FindByEmail", mock.Anything, mock.Anything)
+}
+
+// TestGetUserByEmail_NotFound testa o mapeamento do erro sentinela do repositório
+func (suite *GetUserByEmailUseCaseTestSuite) TestGetUserByEmail_NotFound() {
+	suite.userRepo.On("FindByEmail", mock.Anything, emailMatcher("ghost@example.com")).
+		Return(nil, fmt.Errorf("%w: ghost@example.com", entity.ErrEmailNotFound))
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetUserByEmailRequest{Email: "ghost@example.com"})
+
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, usecase.ErrUserNotFound)
+}
+
+// TestGetUserByEmail_RepositoryError testa a propagação de falhas do banco
+func (suite *GetUserByEmailUseCaseTestSuite) TestGetUserByEmail_RepositoryError() {
+	suite.userRepo.On("FindByEmail", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+	suite.logger.On("Error", "Failed to find user by email", mock.Anything).Return()
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetUserByEmailRequest{Email: "joao@example.com"})
+
+	assert.Nil(suite.T(), response)
+	assert.Error(suite.T(), err)
+	assert.NotErrorIs(suite.T(), err, usecase.ErrUserNotFound)
+}
+
+// TestGetUserByEmailUseCase executa a suite de testes
+func TestGetUserByEmailUseCase(t *testing.T) {
+	suite.Run(t, new(GetUserByEmailUseCaseTestSuite))
+}
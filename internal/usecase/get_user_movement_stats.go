@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+const (
+	// DefaultMovementStatsWindow é o intervalo usado quando from não é informado
+	DefaultMovementStatsWindow = 24 * time.Hour
+	// MaxMovementStatsPositions limita quantas posições entram no cálculo
+	MaxMovementStatsPositions = 10000
+)
+
+// ErrInvalidTimeRange indica um intervalo com from posterior a to
+var ErrInvalidTimeRange = errors.New("invalid time range")
+
+// GetUserMovementStatsRequest representa os dados de entrada (From/To zero usam os padrões)
+type GetUserMovementStatsRequest struct {
+	UserID string    `json:"user_id" validate:"required"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+}
+
+// GetUserMovementStatsResponse representa a resposta
+type GetUserMovementStatsResponse struct {
+	UserID            string    `json:"user_id"`
+	From              time.Time `json:"from"`
+	To                time.Time `json:"to"`
+	PositionCount     int       `json:"position_count"`
+	TotalDistanceM    float64   `json:"total_distance_meters"`
+	AverageSpeedMPS   float64   `json:"average_speed_mps"`
+	SectorChanges     int       `json:"sector_changes"`
+	MostVisitedSector string    `json:"most_visited_sector,omitempty"`
+	MostVisitedCount  int       `json:"most_visited_count"`
+	Truncated         bool      `json:"truncated"` // true se o intervalo tinha mais que MaxMovementStatsPositions
+	Message           string    `json:"message"`
+}
+
+// GetUserMovementStatsUseCase calcula estatísticas de deslocamento de um usuário a partir do histórico
+type GetUserMovementStatsUseCase struct {
+	userRepo     repository.UserRepository
+	positionRepo repository.PositionRepository
+	logger       logger.Logger
+}
+
+// NewGetUserMovementStatsUseCase cria uma nova instância do use case
+func NewGetUserMovementStatsUseCase(
+	userRepo repository.UserRepository,
+	positionRepo repository.PositionRepository,
+	logger logger.Logger,
+) *GetUserMovementStatsUseCase {
+	return &GetUserMovementStatsUseCase{
+		userRepo:     userRepo,
+		positionRepo: positionRepo,
+		logger:       logger,
+	}
+}
+
+// Execute executa o use case de estatísticas de deslocamento
+func (uc *GetUserMovementStatsUseCase) Execute(ctx context.Context, req GetUserMovementStatsRequest) (*GetUserMovementStatsResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	// 1. Definir intervalo (padrão: últimas 24h)
+	to := req.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+	from := req.From
+	if from.IsZero() {
+		from = to.Add(-DefaultMovementStatsWindow)
+	}
+	if from.After(to) {
+		return nil, fmt.Errorf("%w: from %s is after to %s", ErrInvalidTimeRange, from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	// 2. Validar usuário
+	userIDPtr, err := entity.NewUserID(req.UserID)
+	if err != nil {
+		log.Error("Invalid user ID", map[string]interface{}{
+			"user_id": req.UserID,
+			"error":   err.Error(),
+		})
+		return nil, fmt.Errorf("%w: invalid user ID: %w", ErrInvalidInput, err)
+	}
+
+	userID := *userIDPtr
+	if _, err := uc.userRepo.FindByID(ctx, userID); err != nil {
+		log.Error("User not found", map[string]interface{}{
+			"user_id": req.UserID,
+			"error":   err.Error(),
+		})
+		return nil, userLookupError(req.UserID, err)
+	}
+
+	// 3. Buscar histórico do intervalo em ordem cronológica (+1 para detectar truncamento)
+	positions, err := uc.positionRepo.FindHistoryByUserIDInRange(ctx, userID,
+		valueobject.NewTimestamp(from), valueobject.NewTimestamp(to), MaxMovementStatsPositions+1)
+	if err != nil {
+		log.Error("Failed to get position history in range", map[string]interface{}{
+			"user_id": req.UserID,
+			"from":    from,
+			"to":      to,
+			"error":   err.Error(),
+		})
+		return nil, fmt.Errorf("failed to get position history: %w", err)
+	}
+
+	truncated := len(positions) > MaxMovementStatsPositions
+	if truncated {
+		positions = positions[:MaxMovementStatsPositions]
+	}
+
+	// 4. Calcular estatísticas
+	response := computeMovementStats(positions)
+	response.UserID = userID.String()
+	response.From = from
+	response.To = to
+	response.Truncated = truncated
+	response.Message = fmt.Sprintf("%.0fm traveled across %d positions", response.TotalDistanceM, response.PositionCount)
+
+	log.Info("User movement stats computed", map[string]interface{}{
+		"user_id":        req.UserID,
+		"positions":      response.PositionCount,
+		"distance":       response.TotalDistanceM,
+		"sector_changes": response.SectorChanges,
+		"truncated":      truncated,
+	})
+
+	return response, nil
+}
+
+// computeMovementStats percorre as posições em ordem cronológica acumulando distância,
+// trocas de setor e visitas por setor
+func computeMovementStats(positions []*entity.Position) *GetUserMovementStatsResponse {
+	response := &GetUserMovementStatsResponse{PositionCount: len(positions)}
+	if len(positions) == 0 {
+		return response
+	}
+
+	visits := make(map[string]int)
+	for i, position := range positions {
+		sectorID := position.Sector().ID()
+		visits[sectorID]++
+
+		// Em empate, vale o setor que atingiu a contagem primeiro
+		if visits[sectorID] > response.MostVisitedCount {
+			response.MostVisitedSector = sectorID
+			response.MostVisitedCount = visits[sectorID]
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		previous := positions[i-1]
+		response.TotalDistanceM += previous.Coordinate().DistanceTo(position.Coordinate())
+		if previous.Sector().ID() != sectorID {
+			response.SectorChanges++
+		}
+	}
+
+	elapsed := positions[len(positions)-1].RecordedAt().Time().Sub(positions[0].RecordedAt().Time())
+	if elapsed > 0 {
+		response.AverageSpeedMPS = response.TotalDistanceM / elapsed.Seconds()
+	}
+
+	return response
+}
@@ -0,0 +1,158 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+)
+
+// GetUserMovementStatsUseCaseTestSuite define a suite de testes para GetUserMovementStatsUseCase
+type GetUserMovementStatsUseCaseTestSuite struct {
+	suite.Suite
+	userRepo     *mocks.MockUserRepository
+	positionRepo *mocks.MockPositionRepository
+	logger       *mocks.MockLogger
+	useCase      *usecase.GetUserMovementStatsUseCase
+	ctx          context.Context
+	userID       *entity.UserID
+}
+
+// SetupTest configura cada teste
+func (suite *GetUserMovementStatsUseCaseTestSuite) SetupTest() {
+	suite.userRepo = new(mocks.MockUserRepository)
+	suite.positionRepo = new(mocks.MockPositionRepository)
+	suite.logger = new(mocks.MockLogger)
+	suite.useCase = usecase.NewGetUserMovementStatsUseCase(suite.userRepo, suite.positionRepo, suite.logger)
+	suite.ctx = context.Background()
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	suite.userID = userID
+}
+
+// TearDownTest limpa após cada teste
+func (suite *GetUserMovementStatsUseCaseTestSuite) TearDownTest() {
+	suite.userRepo.AssertExpectations(suite.T())
+	suite.positionRepo.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// position cria uma posição do usuário da suite
+func (suite *GetUserMovementStatsUseCaseTestSuite) position(id string, lat, lng float64, recordedAt time.Time) *entity.Position {
+	position, err := entity.NewPosition(id, *suite.userID, lat, lng, recordedAt)
+	suite.Require().NoError(err)
+	return position
+}
+
+// TestMovementStats_KnownPath testa cada estatística em um trajeto ida e volta conhecido
+func (suite *GetUserMovementStatsUseCaseTestSuite) TestMovementStats_KnownPath() {
+	// Arrange: parado em A, vai até B (~1,1km ao norte, outro setor) e volta para A, 100s entre pontos
+	from := time.Now().Add(-time.Hour).Truncate(time.Second)
+	to := from.Add(time.Hour)
+	latA, lngA := -23.550520, -46.633309
+	latB, lngB := -23.540520, -46.633309
+
+	path := []*entity.Position{
+		suite.position("pos-1", latA, lngA, from.Add(1*time.Minute)),
+		suite.position("pos-2", latA, lngA, from.Add(1*time.Minute+100*time.Second)),
+		suite.position("pos-3", latB, lngB, from.Add(1*time.Minute+200*time.Second)),
+		suite.position("pos-4", latA, lngA, from.Add(1*time.Minute+300*time.Second)),
+	}
+	legAB := valueobject.CalculateDistance(latA, lngA, latB, lngB)
+
+	validUser, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+	suite.userRepo.On("FindByID", mock.Anything, *suite.userID).Return(validUser, nil)
+	suite.positionRepo.On("FindHistoryByUserIDInRange", mock.Anything, *suite.userID,
+		valueobject.NewTimestamp(from), valueobject.NewTimestamp(to), usecase.MaxMovementStatsPositions+1).
+		Return(path, nil)
+	suite.logger.On("Info", "User movement stats computed", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetUserMovementStatsRequest{
+		UserID: "user123",
+		From:   from,
+		To:     to,
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 4, response.PositionCount)
+	assert.InDelta(suite.T(), 2*legAB, response.TotalDistanceM, 0.001)
+	assert.InDelta(suite.T(), 2*legAB/300, response.AverageSpeedMPS, 0.001)
+	assert.Equal(suite.T(), 2, response.SectorChanges)
+	assert.Equal(suite.T(), path[0].Sector().ID(), response.MostVisitedSector)
+	assert.Equal(suite.T(), 3, response.MostVisitedCount)
+	assert.False(suite.T(), response.Truncated)
+}
+
+// TestMovementStats_EmptyRange testa um intervalo sem posições
+func (suite *GetUserMovementStatsUseCaseTestSuite) TestMovementStats_EmptyRange() {
+	// Arrange
+	validUser, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+	suite.userRepo.On("FindByID", mock.Anything, *suite.userID).Return(validUser, nil)
+	suite.positionRepo.On("FindHistoryByUserIDInRange", mock.Anything, *suite.userID, mock.Anything, mock.Anything, mock.Anything).
+		Return([]*entity.Position{}, nil)
+	suite.logger.On("Info", "User movement stats computed", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetUserMovementStatsRequest{UserID: "user123"})
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, response.PositionCount)
+	assert.Zero(suite.T(), response.TotalDistanceM)
+	assert.Zero(suite.T(), response.AverageSpeedMPS)
+	assert.Empty(suite.T(), response.MostVisitedSector)
+	assert.Equal(suite.T(), usecase.DefaultMovementStatsWindow, response.To.Sub(response.From))
+}
+
+// TestMovementStats_InvalidRange testa from posterior a to
+func (suite *GetUserMovementStatsUseCaseTestSuite) TestMovementStats_InvalidRange() {
+	// Arrange
+	to := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetUserMovementStatsRequest{
+		UserID: "user123",
+		From:   to.Add(time.Hour),
+		To:     to,
+	})
+
+	// Assert
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, usecase.ErrInvalidTimeRange)
+}
+
+// TestMovementStats_RepositoryError testa falha ao buscar o histórico
+func (suite *GetUserMovementStatsUseCaseTestSuite) TestMovementStats_RepositoryError() {
+	// Arrange
+	validUser, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+	suite.userRepo.On("FindByID", mock.Anything, *suite.userID).Return(validUser, nil)
+	suite.positionRepo.On("FindHistoryByUserIDInRange", mock.Anything, *suite.userID, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, errors.New("database connection failed"))
+	suite.logger.On("Error", "Failed to get position history in range", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.GetUserMovementStatsRequest{UserID: "user123"})
+
+	// Assert
+	assert.Nil(suite.T(), response)
+	assert.Contains(suite.T(), err.Error(), "database connection failed")
+}
+
+// TestGetUserMovementStatsUseCase executa toda a suite de testes
+func TestGetUserMovementStatsUseCase(t *testing.T) {
+	suite.Run(t, new(GetUserMovementStatsUseCaseTestSuite))
+}
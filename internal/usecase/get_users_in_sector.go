@@ -3,6 +3,8 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/vitao/geolocation-tracker/internal/domain/entity"
 	"github.com/vitao/geolocation-tracker/internal/domain/repository"
@@ -15,16 +17,22 @@ type GetUsersInSectorRequest struct {
 	UserID    string  `json:"user_id" validate:"required,uuid"`
 	Latitude  float64 `json:"latitude" validate:"required,min=-90,max=90"`
 	Longitude float64 `json:"longitude" validate:"required,min=-180,max=180"`
+
+	// MaxAgeSeconds descarta usuários vistos há mais tempo que isso (0 não filtra)
+	MaxAgeSeconds int `json:"max_age_seconds" validate:"omitempty,min=1,max=86400"`
 }
 
 // SectorUserResponse representa um usuário no setor
 type SectorUserResponse struct {
-	UserID     string  `json:"user_id"`
-	UserName   string  `json:"user_name"`
-	PositionID string  `json:"position_id"`
-	Latitude   float64 `json:"latitude"`
-	Longitude  float64 `json:"longitude"`
-	Age        string  `json:"age"` // Ex: "5m30s"
+	UserID     string    `json:"user_id"`
+	UserName   string    `json:"user_name"`
+	PositionID string    `json:"position_id"`
+	Latitude   float64   `json:"latitude"`
+	Longitude  float64   `json:"longitude"`
+	DistanceM  float64   `json:"distance_meters"` // Distância até o solicitante
+	RecordedAt time.Time `json:"recorded_at"`
+	AgeSeconds int64     `json:"age_seconds"` // Para filtros como "vistos nos últimos 60s"
+	Age        string    `json:"age"`         // Ex: "5m30s"
 }
 
 // GetUsersInSectorResponse representa a resposta
@@ -45,6 +53,13 @@ type SectorBounds struct {
 	MaxLongitude float64 `json:"max_longitude"`
 }
 
+// sectorUsersCacheKey guarda os usuários com posição atual em um setor
+// SaveUserPositionUseCase remove as chaves dos setores de origem e destino a cada posição salva
+const (
+	sectorUsersCacheKey = "sector:%s"
+	sectorUsersCacheTTL = 30 * time.Second
+)
+
 // GetUsersInSectorUseCase implementa a busca de usuários no mesmo setor
 type GetUsersInSectorUseCase struct {
 	userRepo     repository.UserRepository
@@ -70,41 +85,43 @@ func NewGetUsersInSectorUseCase(
 
 // Execute executa o use case de buscar usuários no mesmo setor
 func (uc *GetUsersInSectorUseCase) Execute(ctx context.Context, req GetUsersInSectorRequest) (*GetUsersInSectorResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
 	// 1. Validar se o usuário existe
 	userIDPtr, err := entity.NewUserID(req.UserID)
 	if err != nil {
-		uc.logger.Error("Invalid user ID", map[string]interface{}{
+		log.Error("Invalid user ID", map[string]interface{}{
 			"user_id": req.UserID,
 			"error":   err.Error(),
 		})
-		return nil, fmt.Errorf("invalid user ID: %w", err)
+		return nil, fmt.Errorf("%w: invalid user ID: %w", ErrInvalidInput, err)
 	}
 
 	userID := *userIDPtr
 	_, err = uc.userRepo.FindByID(ctx, userID) // Apenas validar que existe
 	if err != nil {
-		uc.logger.Error("User not found", map[string]interface{}{
+		log.Error("User not found", map[string]interface{}{
 			"user_id": req.UserID,
 			"error":   err.Error(),
 		})
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, userLookupError(req.UserID, err)
 	}
 
 	// 2. Validar coordenadas e calcular setor
 	coordinate, err := valueobject.NewCoordinate(req.Latitude, req.Longitude)
 	if err != nil {
-		uc.logger.Error("Invalid coordinates", map[string]interface{}{
+		log.Error("Invalid coordinates", map[string]interface{}{
 			"latitude":  req.Latitude,
 			"longitude": req.Longitude,
 			"error":     err.Error(),
 		})
-		return nil, fmt.Errorf("invalid coordinates: %w", err)
+		return nil, fmt.Errorf("%w: invalid coordinates: %w", ErrInvalidInput, err)
 	}
 
 	// 3. Calcular setor a partir das coordenadas
 	sector, err := valueobject.NewSectorFromCoordinate(coordinate)
 	if err != nil {
-		uc.logger.Error("Failed to create sector", map[string]interface{}{
+		log.Error("Failed to create sector", map[string]interface{}{
 			"latitude":  req.Latitude,
 			"longitude": req.Longitude,
 			"error":     err.Error(),
@@ -112,67 +129,73 @@ func (uc *GetUsersInSectorUseCase) Execute(ctx context.Context, req GetUsersInSe
 		return nil, fmt.Errorf("failed to create sector: %w", err)
 	}
 
-	// 4. Buscar todas as posições no setor
-	sectorPositions, err := uc.positionRepo.FindInSector(ctx, sector)
+	// 4. Buscar os usuários do setor (cache curto, invalidado quando uma posição é salva)
+	members, err := uc.findSectorMembers(ctx, sector)
 	if err != nil {
-		uc.logger.Error("Failed to find positions in sector", map[string]interface{}{
-			"sector_id": sector.ID(),
-			"latitude":  req.Latitude,
-			"longitude": req.Longitude,
-			"error":     err.Error(),
-		})
-		return nil, fmt.Errorf("failed to find positions in sector: %w", err)
+		return nil, err
 	}
 
 	// 5. Processar resultados
-	var usersInSector []SectorUserResponse
+	usersInSector := make([]SectorUserResponse, 0, len(members)) // Sempre [] no JSON, nunca null
+	memberCoordinates := make([]*valueobject.Coordinate, 0, len(members))
 	var requestedBy SectorUserResponse
 	requestedBySet := false
 
-	for _, position := range sectorPositions {
-		// Buscar dados do usuário
-		positionUser, err := uc.userRepo.FindByID(ctx, position.UserID())
-		if err != nil {
-			positionID := position.ID()
-			userIDValue := position.UserID()
-			uc.logger.Error("User not found for position", map[string]interface{}{
-				"position_id": positionID.String(),
-				"user_id":     userIDValue.String(),
-			})
+	// Referência de distância: posição do solicitante no setor, ou a coordenada consultada
+	reference := coordinate
+
+	filtered := 0
+	for _, member := range members {
+		// Entradas em cache envelhecem: a idade é sempre recalculada a partir de RecordedAt
+		member.refreshAge()
+
+		// Posições antigas demais ficam de fora; o solicitante é sempre mantido
+		isRequester := member.UserID == userID.String()
+		if !isRequester && exceedsMaxAge(member.AgeSeconds, req.MaxAgeSeconds) {
+			filtered++
 			continue
 		}
 
-		// Criar resposta do usuário
-		positionCoordinate := position.Coordinate()
-		userIDValue := positionUser.ID()
-		positionIDValue := position.ID()
-		sectorUser := SectorUserResponse{
-			UserID:     userIDValue.String(),
-			UserName:   positionUser.Name(),
-			PositionID: positionIDValue.String(),
-			Latitude:   positionCoordinate.Latitude(),
-			Longitude:  positionCoordinate.Longitude(),
-			Age:        position.Age().String(),
+		memberCoordinate, err := valueobject.NewCoordinate(member.Latitude, member.Longitude)
+		if err != nil {
+			continue
 		}
 
 		// Se é o usuário que fez a requisição
-		positionUserID := position.UserID()
-		if positionUserID.Equals(&userID) && !requestedBySet {
-			requestedBy = sectorUser
+		if isRequester && !requestedBySet {
+			requestedBy = member
 			requestedBySet = true
+			reference = memberCoordinate
 		} else {
-			usersInSector = append(usersInSector, sectorUser)
+			usersInSector = append(usersInSector, member)
+			memberCoordinates = append(memberCoordinates, memberCoordinate)
 		}
 	}
 
+	// 5.1. Ordenar do mais próximo ao mais distante do solicitante
+	for i := range usersInSector {
+		usersInSector[i].DistanceM = reference.DistanceTo(memberCoordinates[i])
+	}
+	sort.SliceStable(usersInSector, func(i, j int) bool {
+		return usersInSector[i].DistanceM < usersInSector[j].DistanceM
+	})
+
 	// 6. Calcular bounds do setor
-	bounds := uc.calculateSectorBounds(sector)
+	bounds, err := uc.calculateSectorBounds(sector)
+	if err != nil {
+		log.Error("Failed to calculate sector bounds", map[string]interface{}{
+			"sector_id": sector.ID(),
+			"error":     err.Error(),
+		})
+		return nil, fmt.Errorf("failed to calculate sector bounds: %w", err)
+	}
 
 	// 7. Log de sucesso
-	uc.logger.Info("Sector users search completed", map[string]interface{}{
+	log.Info("Sector users search completed", map[string]interface{}{
 		"user_id":          req.UserID,
 		"sector_id":        sector.ID(),
 		"total_found":      len(usersInSector),
+		"filtered_by_age":  filtered,
 		"requested_by_set": requestedBySet,
 	})
 
@@ -187,26 +210,91 @@ func (uc *GetUsersInSectorUseCase) Execute(ctx context.Context, req GetUsersInSe
 	}, nil
 }
 
-// calculateSectorBounds calcula os limites geográficos do setor
-func (uc *GetUsersInSectorUseCase) calculateSectorBounds(sector *valueobject.Sector) SectorBounds {
-	// Cada setor representa um quadrado de 100x100 metros
-	// Aqui calculamos os bounds aproximados baseados no centro do setor
+// findSectorMembers retorna os usuários com posição atual no setor, consultando o cache antes do banco
+// A entrada não depende do solicitante: distância, idade e filtro por idade são aplicados em Execute
+func (uc *GetUsersInSectorUseCase) findSectorMembers(ctx context.Context, sector *valueobject.Sector) ([]SectorUserResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	key := fmt.Sprintf(sectorUsersCacheKey, sector.ID())
+
+	var cached []SectorUserResponse
+	if err := uc.cache.Get(ctx, key, &cached); err == nil {
+		log.Debug("Cache hit for sector users", map[string]interface{}{
+			"sector_id": sector.ID(),
+			"members":   len(cached),
+			"source":    "cache",
+		})
+		return cached, nil
+	}
 
-	// Para simplificar, vamos usar uma aproximação
-	// 1 grau de latitude ≈ 111.000 metros
-	// 1 grau de longitude ≈ 111.000 * cos(latitude) metros
+	sectorPositions, err := uc.positionRepo.FindInSector(ctx, sector)
+	if err != nil {
+		log.Error("Failed to find positions in sector", map[string]interface{}{
+			"sector_id": sector.ID(),
+			"error":     err.Error(),
+		})
+		return nil, fmt.Errorf("failed to find positions in sector: %w", err)
+	}
 
-	deltaLat := 50.0 / 111000.0 // 50 metros em graus (raio do setor)
-	deltaLng := 50.0 / 111000.0 // Aproximação simples
+	members := make([]SectorUserResponse, 0, len(sectorPositions))
+	for _, position := range sectorPositions {
+		// Buscar dados do usuário
+		positionUser, err := uc.userRepo.FindByID(ctx, position.UserID())
+		if err != nil {
+			positionID := position.ID()
+			userIDValue := position.UserID()
+			log.Error("User not found for position", map[string]interface{}{
+				"position_id": positionID.String(),
+				"user_id":     userIDValue.String(),
+			})
+			continue
+		}
 
-	// Coordenadas do centro do setor (aproximadas)
-	centerLat := float64(sector.Y()) * 0.001 // Conversão simplificada
-	centerLng := float64(sector.X()) * 0.001
+		positionCoordinate := position.Coordinate()
+		userIDValue := positionUser.ID()
+		positionIDValue := position.ID()
+		members = append(members, SectorUserResponse{
+			UserID:     userIDValue.String(),
+			UserName:   positionUser.Name(),
+			PositionID: positionIDValue.String(),
+			Latitude:   positionCoordinate.Latitude(),
+			Longitude:  positionCoordinate.Longitude(),
+			RecordedAt: position.RecordedAt().Time(),
+		})
+	}
 
-	return SectorBounds{
-		MinLatitude:  centerLat - deltaLat,
-		MaxLatitude:  centerLat + deltaLat,
-		MinLongitude: centerLng - deltaLng,
-		MaxLongitude: centerLng + deltaLng,
+	if err := uc.cache.Set(ctx, key, members, sectorUsersCacheTTL); err != nil {
+		log.Error("Failed to cache sector users", map[string]interface{}{
+			"sector_id": sector.ID(),
+			"error":     err.Error(),
+		})
+	}
+
+	return members, nil
+}
+
+// refreshAge recalcula a idade a partir de RecordedAt
+func (r *SectorUserResponse) refreshAge() {
+	age := time.Since(r.RecordedAt)
+	r.AgeSeconds = int64(age / time.Second)
+	r.Age = age.String()
+}
+
+// calculateSectorBounds calcula os limites geográficos do setor
+// Usa a geometria real do setor (cantos de 100x100 metros ao redor do centro)
+func (uc *GetUsersInSectorUseCase) calculateSectorBounds(sector *valueobject.Sector) (SectorBounds, error) {
+	topLeft, _, _, bottomRight, err := sector.GetBounds()
+	if err != nil {
+		return SectorBounds{}, err
+	}
+	if topLeft == nil || bottomRight == nil {
+		return SectorBounds{}, fmt.Errorf("sector %s bounds are outside valid coordinates", sector.ID())
 	}
+
+	return SectorBounds{
+		MinLatitude:  bottomRight.Latitude(),
+		MaxLatitude:  topLeft.Latitude(),
+		MinLongitude: topLeft.Longitude(),
+		MaxLongitude: bottomRight.Longitude(),
+	}, nil
 }
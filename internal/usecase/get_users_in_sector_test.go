@@ -3,6 +3,7 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
 	"github.com/vitao/geolocation-tracker/internal/usecase"
 	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
 )
@@ -43,6 +45,19 @@ func (suite *GetUsersInSectorUseCaseTestSuite) TearDownTest() {
 	suite.logger.AssertExpectations(suite.T())
 }
 
+// isSectorUsersCacheKey identifica as chaves de cache de usuários por setor
+func isSectorUsersCacheKey(key string) bool {
+	return strings.HasPrefix(key, "sector:")
+}
+
+// expectSectorCacheMiss simula o cache do setor vazio e aceita a gravação do resultado
+func (suite *GetUsersInSectorUseCaseTestSuite) expectSectorCacheMiss() {
+	suite.cache.On("Get", mock.Anything, mock.MatchedBy(isSectorUsersCacheKey), mock.Anything).
+		Return(errors.New("cache miss")).Once()
+	suite.cache.On("Set", mock.Anything, mock.MatchedBy(isSectorUsersCacheKey), mock.Anything, 30*time.Second).
+		Return(nil).Maybe()
+}
+
 // TestGetUsersInSector_Success testa busca bem-sucedida
 func (suite *GetUsersInSectorUseCaseTestSuite) TestGetUsersInSector_Success() {
 	// Arrange
@@ -83,6 +98,7 @@ func (suite *GetUsersInSectorUseCaseTestSuite) TestGetUsersInSector_Success() {
 		Return(otherUser, nil)
 
 	// Mock: posições no setor encontradas
+	suite.expectSectorCacheMiss()
 	suite.positionRepo.On("FindInSector", mock.Anything, mock.Anything).
 		Return(positions, nil)
 
@@ -102,6 +118,17 @@ func (suite *GetUsersInSectorUseCaseTestSuite) TestGetUsersInSector_Success() {
 	assert.Len(suite.T(), response.UsersInSector, 1)
 	assert.Equal(suite.T(), "user456", response.UsersInSector[0].UserID)
 	assert.Equal(suite.T(), "Maria Santos", response.UsersInSector[0].UserName)
+	assert.True(suite.T(), response.UsersInSector[0].RecordedAt.Equal(position1.RecordedAt().Time()))
+	assert.InDelta(suite.T(), 30*60, response.UsersInSector[0].AgeSeconds, 2)
+
+	// Membros do setor gravados no cache para as próximas consultas
+	suite.cache.AssertCalled(suite.T(), "Set", mock.Anything, "sector:"+response.SectorID, mock.Anything, 30*time.Second)
+
+	// Bounds do setor contêm o ponto consultado e têm ~100m de lado
+	bounds := response.SectorBounds
+	assert.True(suite.T(), bounds.MinLatitude <= request.Latitude && request.Latitude <= bounds.MaxLatitude)
+	assert.True(suite.T(), bounds.MinLongitude <= request.Longitude && request.Longitude <= bounds.MaxLongitude)
+	assert.InDelta(suite.T(), 100.0/111320.0, bounds.MaxLatitude-bounds.MinLatitude, 1e-9)
 }
 
 // TestGetUsersInSector_UserNotFound testa usuário solicitante não encontrado
@@ -155,6 +182,7 @@ func (suite *GetUsersInSectorUseCaseTestSuite) TestGetUsersInSector_RepositoryEr
 		Return(validUser, nil)
 
 	// Mock: erro no repositório
+	suite.expectSectorCacheMiss()
 	suite.positionRepo.On("FindInSector", mock.Anything, mock.Anything).
 		Return(nil, repoError)
 
@@ -191,6 +219,7 @@ func (suite *GetUsersInSectorUseCaseTestSuite) TestGetUsersInSector_EmptySector(
 		Return(validUser, nil)
 
 	// Mock: setor vazio
+	suite.expectSectorCacheMiss()
 	suite.positionRepo.On("FindInSector", mock.Anything, mock.Anything).
 		Return([]*entity.Position{}, nil)
 
@@ -208,6 +237,7 @@ func (suite *GetUsersInSectorUseCaseTestSuite) TestGetUsersInSector_EmptySector(
 	assert.Equal(suite.T(), "", response.RequestedBy.UserID)
 	assert.Equal(suite.T(), 0, response.TotalFound)
 	assert.Empty(suite.T(), response.UsersInSector)
+	assertJSONEmptyArray(suite.T(), response, "users_in_sector")
 }
 
 // TestGetUsersInSector_InvalidCoordinates testa coordenadas inválidas
@@ -290,6 +320,7 @@ func (suite *GetUsersInSectorUseCaseTestSuite) TestGetUsersInSector_ExcludeSelf(
 		Return(validUser, nil)
 
 	// Mock: posições incluem a do próprio usuário (que deve ser filtrada)
+	suite.expectSectorCacheMiss()
 	suite.positionRepo.On("FindInSector", mock.Anything, mock.Anything).
 		Return(positions, nil)
 
@@ -307,6 +338,178 @@ func (suite *GetUsersInSectorUseCaseTestSuite) TestGetUsersInSector_ExcludeSelf(
 	assert.Empty(suite.T(), response.UsersInSector)
 }
 
+// TestGetUsersInSector_MaxAgeFiltersStalePositions testa que posições antigas são ignoradas, exceto a do solicitante
+func (suite *GetUsersInSectorUseCaseTestSuite) TestGetUsersInSector_MaxAgeFiltersStalePositions() {
+	request := usecase.GetUsersInSectorRequest{
+		UserID:        "user123",
+		Latitude:      -23.550520,
+		Longitude:     -46.633309,
+		MaxAgeSeconds: 60,
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	validUser, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+	freshID, err := entity.NewUserID("user456")
+	suite.Require().NoError(err)
+	freshUser, err := entity.NewUser("user456", "Maria Santos", "maria@example.com")
+	suite.Require().NoError(err)
+	staleID, err := entity.NewUserID("user789")
+	suite.Require().NoError(err)
+	staleUser, err := entity.NewUser("user789", "Pedro Lima", "pedro@example.com")
+	suite.Require().NoError(err)
+
+	now := time.Now()
+	selfPosition, err := entity.NewPosition("pos-self", *userID, -23.550520, -46.633309, now.Add(-time.Hour))
+	suite.Require().NoError(err)
+	freshPosition, err := entity.NewPosition("pos-fresh", *freshID, -23.550520, -46.633309, now.Add(-10*time.Second))
+	suite.Require().NoError(err)
+	stalePosition, err := entity.NewPosition("pos-stale", *staleID, -23.550520, -46.633309, now.Add(-10*time.Minute))
+	suite.Require().NoError(err)
+
+	suite.userRepo.On("FindByID", mock.Anything, *userID).Return(validUser, nil)
+	suite.userRepo.On("FindByID", mock.Anything, *freshID).Return(freshUser, nil)
+	suite.userRepo.On("FindByID", mock.Anything, *staleID).Return(staleUser, nil) // Entra no cache do setor
+	suite.expectSectorCacheMiss()
+	suite.positionRepo.On("FindInSector", mock.Anything, mock.Anything).
+		Return([]*entity.Position{selfPosition, freshPosition, stalePosition}, nil)
+	suite.logger.On("Info", "Sector users search completed", mock.Anything).Return()
+
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "user123", response.RequestedBy.UserID)
+	suite.Require().Len(response.UsersInSector, 1)
+	assert.Equal(suite.T(), "user456", response.UsersInSector[0].UserID)
+}
+
+// TestGetUsersInSector_CacheHit testa que o cache evita o banco e que idade e filtros são recalculados
+func (suite *GetUsersInSectorUseCaseTestSuite) TestGetUsersInSector_CacheHit() {
+	request := usecase.GetUsersInSectorRequest{
+		UserID:        "user123",
+		Latitude:      -23.550520,
+		Longitude:     -46.633309,
+		MaxAgeSeconds: 60,
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	validUser, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+	suite.userRepo.On("FindByID", mock.Anything, *userID).Return(validUser, nil).Once()
+
+	// Entrada gravada há algum tempo: AgeSeconds armazenado está desatualizado
+	now := time.Now()
+	cached := []usecase.SectorUserResponse{
+		{UserID: "user123", UserName: "João Silva", PositionID: "pos-self", Latitude: -23.550250, Longitude: -46.633309, RecordedAt: now.Add(-time.Hour)},
+		{UserID: "user456", UserName: "Maria Santos", PositionID: "pos-fresh", Latitude: -23.550520, Longitude: -46.633309, RecordedAt: now.Add(-10 * time.Second)},
+		{UserID: "user789", UserName: "Pedro Lima", PositionID: "pos-stale", Latitude: -23.550520, Longitude: -46.633309, RecordedAt: now.Add(-10 * time.Minute)},
+	}
+	suite.cache.On("Get", mock.Anything, mock.MatchedBy(isSectorUsersCacheKey), mock.Anything).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*[]usecase.SectorUserResponse) = cached
+		}).
+		Return(nil).Once()
+	suite.logger.On("Debug", "Cache hit for sector users", mock.Anything).Return()
+	suite.logger.On("Info", "Sector users search completed", mock.Anything).Return()
+
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "user123", response.RequestedBy.UserID)
+	suite.Require().Len(response.UsersInSector, 1)
+	assert.Equal(suite.T(), "user456", response.UsersInSector[0].UserID)
+	assert.InDelta(suite.T(), 10, response.UsersInSector[0].AgeSeconds, 2)
+	assert.InDelta(suite.T(), 30, response.UsersInSector[0].DistanceM, 1) // Até a posição do solicitante
+	suite.positionRepo.AssertNotCalled(suite.T(), "FindInSector", mock.Anything, mock.Anything)
+	suite.cache.AssertNotCalled(suite.T(), "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestGetUsersInSector_SortedByDistance testa a ordenação pela distância até o solicitante
+func (suite *GetUsersInSectorUseCaseTestSuite) TestGetUsersInSector_SortedByDistance() {
+	request := usecase.GetUsersInSectorRequest{
+		UserID:    "user123",
+		Latitude:  -23.550520,
+		Longitude: -46.633309,
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	validUser, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+
+	nearQueryID, err := entity.NewUserID("user456")
+	suite.Require().NoError(err)
+	nearQueryUser, err := entity.NewUser("user456", "Maria Santos", "maria@example.com")
+	suite.Require().NoError(err)
+	nearSelfID, err := entity.NewUserID("user789")
+	suite.Require().NoError(err)
+	nearSelfUser, err := entity.NewUser("user789", "Pedro Lima", "pedro@example.com")
+	suite.Require().NoError(err)
+
+	now := time.Now()
+	// Solicitante ~30m ao norte do ponto consultado; user789 ao lado dele, user456 sobre o ponto consultado
+	selfPosition, err := entity.NewPosition("pos-self", *userID, -23.550250, -46.633309, now)
+	suite.Require().NoError(err)
+	nearQuery, err := entity.NewPosition("pos-near-query", *nearQueryID, -23.550520, -46.633309, now)
+	suite.Require().NoError(err)
+	nearSelf, err := entity.NewPosition("pos-near-self", *nearSelfID, -23.550260, -46.633309, now)
+	suite.Require().NoError(err)
+
+	testCases := []struct {
+		name          string
+		positions     []*entity.Position
+		expectedOrder []string
+		reference     *entity.Position
+	}{
+		{
+			name:          "requester position as reference",
+			positions:     []*entity.Position{nearQuery, selfPosition, nearSelf},
+			expectedOrder: []string{"user789", "user456"},
+			reference:     selfPosition,
+		},
+		{
+			name:          "query coordinate when requester is not in sector",
+			positions:     []*entity.Position{nearSelf, nearQuery},
+			expectedOrder: []string{"user456", "user789"},
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.SetupTest()
+
+			suite.userRepo.On("FindByID", mock.Anything, *userID).Return(validUser, nil)
+			suite.userRepo.On("FindByID", mock.Anything, *nearQueryID).Return(nearQueryUser, nil)
+			suite.userRepo.On("FindByID", mock.Anything, *nearSelfID).Return(nearSelfUser, nil)
+			suite.expectSectorCacheMiss()
+			suite.positionRepo.On("FindInSector", mock.Anything, mock.Anything).Return(tc.positions, nil)
+			suite.logger.On("Info", "Sector users search completed", mock.Anything).Return()
+
+			response, err := suite.useCase.Execute(suite.ctx, request)
+
+			suite.Require().NoError(err)
+			suite.Require().Len(response.UsersInSector, len(tc.expectedOrder))
+			for i, expectedUserID := range tc.expectedOrder {
+				assert.Equal(suite.T(), expectedUserID, response.UsersInSector[i].UserID)
+			}
+
+			reference, err := valueobject.NewCoordinate(request.Latitude, request.Longitude)
+			suite.Require().NoError(err)
+			if tc.reference != nil {
+				reference = tc.reference.Coordinate()
+			}
+			for _, member := range response.UsersInSector {
+				memberCoordinate, err := valueobject.NewCoordinate(member.Latitude, member.Longitude)
+				suite.Require().NoError(err)
+				assert.InDelta(suite.T(), reference.DistanceTo(memberCoordinate), member.DistanceM, 0.001)
+			}
+			assert.LessOrEqual(suite.T(), response.UsersInSector[0].DistanceM, response.UsersInSector[1].DistanceM)
+		})
+	}
+}
+
 // TestNewGetUsersInSectorUseCase testa o construtor
 func (suite *GetUsersInSectorUseCaseTestSuite) TestNewGetUsersInSectorUseCase() {
 	// Act
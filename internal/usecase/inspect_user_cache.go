@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// Status de uma entrada de cache inspecionada
+const (
+	CacheStatusHit  = "hit"
+	CacheStatusMiss = "miss"
+)
+
+// InspectUserCacheRequest representa os dados de entrada
+type InspectUserCacheRequest struct {
+	UserID string `json:"user_id" validate:"required"`
+}
+
+// CacheEntryResponse representa uma chave de cache do usuário
+type CacheEntryResponse struct {
+	Key        string          `json:"key"`
+	Type       string          `json:"type"`   // position ou history
+	Status     string          `json:"status"` // hit ou miss
+	TTLSeconds int64           `json:"ttl_seconds,omitempty"`
+	Value      json.RawMessage `json:"value,omitempty" swaggertype:"object"`
+}
+
+// InspectUserCacheResponse representa a resposta
+type InspectUserCacheResponse struct {
+	UserID  string               `json:"user_id"`
+	Entries []CacheEntryResponse `json:"entries"`
+	Hits    int                  `json:"hits"`
+	Misses  int                  `json:"misses"`
+	Message string               `json:"message"`
+}
+
+// userCacheKey associa uma chave de cache ao seu tipo
+type userCacheKey struct {
+	key       string
+	entryType string
+}
+
+// userCacheKeys retorna as chaves de cache conhecidas de um usuário
+func userCacheKeys(userID string) []userCacheKey {
+	keys := []userCacheKey{{fmt.Sprintf(userPositionCacheKey, userID), "position"}}
+	for _, limit := range historyCacheLimits {
+		keys = append(keys, userCacheKey{fmt.Sprintf(userHistoryCacheKey, userID, limit), "history"})
+	}
+	return keys
+}
+
+// InspectUserCacheUseCase implementa a inspeção das chaves de cache de um usuário
+type InspectUserCacheUseCase struct {
+	inspector CacheInspector
+	logger    logger.Logger
+}
+
+// NewInspectUserCacheUseCase cria uma nova instância do use case
+func NewInspectUserCacheUseCase(
+	inspector CacheInspector,
+	logger logger.Logger,
+) *InspectUserCacheUseCase {
+	return &InspectUserCacheUseCase{
+		inspector: inspector,
+		logger:    logger,
+	}
+}
+
+// Execute executa o use case de inspeção de cache do usuário
+func (uc *InspectUserCacheUseCase) Execute(ctx context.Context, req InspectUserCacheRequest) (*InspectUserCacheResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	// 1. Validar UserID
+	if _, err := entity.NewUserID(req.UserID); err != nil {
+		log.Error("Invalid user ID", map[string]interface{}{
+			"user_id": req.UserID,
+			"error":   err.Error(),
+		})
+		return nil, fmt.Errorf("%w: invalid user ID: %w", ErrInvalidInput, err)
+	}
+
+	// 2. Montar chaves conhecidas (posição atual + histórico)
+	keys := userCacheKeys(req.UserID)
+
+	// 3. Inspecionar cada chave
+	response := &InspectUserCacheResponse{
+		UserID:  req.UserID,
+		Entries: make([]CacheEntryResponse, 0, len(keys)),
+	}
+
+	for _, k := range keys {
+		entry, err := uc.inspector.Inspect(ctx, k.key)
+		if err != nil {
+			log.Error("Failed to inspect cache key", map[string]interface{}{
+				"user_id": req.UserID,
+				"key":     k.key,
+				"error":   err.Error(),
+			})
+			return nil, fmt.Errorf("failed to inspect cache key %s: %w", k.key, err)
+		}
+
+		entryResponse := CacheEntryResponse{
+			Key:    k.key,
+			Type:   k.entryType,
+			Status: CacheStatusMiss,
+		}
+		if entry.Found {
+			entryResponse.Status = CacheStatusHit
+			entryResponse.TTLSeconds = int64(entry.TTL.Seconds())
+			entryResponse.Value = json.RawMessage(entry.Value)
+			response.Hits++
+		} else {
+			response.Misses++
+		}
+
+		response.Entries = append(response.Entries, entryResponse)
+	}
+
+	// 4. Log de sucesso
+	log.Info("User cache inspected", map[string]interface{}{
+		"user_id": req.UserID,
+		"hits":    response.Hits,
+		"misses":  response.Misses,
+	})
+
+	response.Message = fmt.Sprintf("Found %d cached entries (%d misses) for user %s", response.Hits, response.Misses, req.UserID)
+	return response, nil
+}
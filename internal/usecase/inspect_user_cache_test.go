@@ -0,0 +1,127 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+)
+
+// InspectUserCacheUseCaseTestSuite define a suite de testes para InspectUserCacheUseCase
+type InspectUserCacheUseCaseTestSuite struct {
+	suite.Suite
+	inspector *mocks.MockCacheInspector
+	logger    *mocks.MockLogger
+	useCase   *usecase.InspectUserCacheUseCase
+	ctx       context.Context
+}
+
+// SetupTest configura cada teste
+func (suite *InspectUserCacheUseCaseTestSuite) SetupTest() {
+	suite.inspector = new(mocks.MockCacheInspector)
+	suite.logger = new(mocks.MockLogger)
+	suite.useCase = usecase.NewInspectUserCacheUseCase(suite.inspector, suite.logger)
+	suite.ctx = context.Background()
+}
+
+// TearDownTest limpa após cada teste
+func (suite *InspectUserCacheUseCaseTestSuite) TearDownTest() {
+	suite.inspector.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// TestInspectUserCache_HitsAndMisses testa entradas em cache e ausentes
+func (suite *InspectUserCacheUseCaseTestSuite) TestInspectUserCache_HitsAndMisses() {
+	// Arrange
+	positionKey := "user:position:user123"
+	historyKey := "history:user123:10"
+
+	// Mock: posição atual e histórico com limit 10 estão em cache
+	suite.inspector.On("Inspect", mock.Anything, positionKey).
+		Return(&usecase.CacheEntry{Key: positionKey, Found: true, TTL: 4 * time.Minute, Value: `{"position_id":"pos-1"}`}, nil)
+	suite.inspector.On("Inspect", mock.Anything, historyKey).
+		Return(&usecase.CacheEntry{Key: historyKey, Found: true, TTL: 30 * time.Second, Value: `{"total":1}`}, nil)
+
+	// Mock: demais chaves de histórico ausentes
+	for _, key := range []string{"history:user123:20", "history:user123:50", "history:user123:100"} {
+		suite.inspector.On("Inspect", mock.Anything, key).
+			Return(&usecase.CacheEntry{Key: key}, nil)
+	}
+
+	suite.logger.On("Info", "User cache inspected", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.InspectUserCacheRequest{UserID: "user123"})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), response)
+	assert.Equal(suite.T(), "user123", response.UserID)
+	assert.Equal(suite.T(), 2, response.Hits)
+	assert.Equal(suite.T(), 3, response.Misses)
+	assert.Len(suite.T(), response.Entries, 5)
+
+	position := response.Entries[0]
+	assert.Equal(suite.T(), positionKey, position.Key)
+	assert.Equal(suite.T(), "position", position.Type)
+	assert.Equal(suite.T(), usecase.CacheStatusHit, position.Status)
+	assert.Equal(suite.T(), int64(240), position.TTLSeconds)
+	assert.JSONEq(suite.T(), `{"position_id":"pos-1"}`, string(position.Value))
+
+	history := response.Entries[1]
+	assert.Equal(suite.T(), historyKey, history.Key)
+	assert.Equal(suite.T(), usecase.CacheStatusHit, history.Status)
+	assert.Equal(suite.T(), int64(30), history.TTLSeconds)
+
+	for _, miss := range response.Entries[2:] {
+		assert.Equal(suite.T(), "history", miss.Type)
+		assert.Equal(suite.T(), usecase.CacheStatusMiss, miss.Status)
+		assert.Zero(suite.T(), miss.TTLSeconds)
+		assert.Nil(suite.T(), miss.Value)
+	}
+}
+
+// TestInspectUserCache_InspectorError testa erro ao acessar o cache
+func (suite *InspectUserCacheUseCaseTestSuite) TestInspectUserCache_InspectorError() {
+	// Arrange
+	suite.inspector.On("Inspect", mock.Anything, "user:position:user123").
+		Return(nil, errors.New("redis unavailable"))
+
+	suite.logger.On("Error", "Failed to inspect cache key", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.InspectUserCacheRequest{UserID: "user123"})
+
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), response)
+	assert.Contains(suite.T(), err.Error(), "redis unavailable")
+}
+
+// TestInspectUserCache_InvalidUserID testa com ID de usuário inválido
+func (suite *InspectUserCacheUseCaseTestSuite) TestInspectUserCache_InvalidUserID() {
+	// Arrange
+	suite.logger.On("Error", "Invalid user ID", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.InspectUserCacheRequest{UserID: ""})
+
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), response)
+	suite.inspector.AssertNotCalled(suite.T(), "Inspect", mock.Anything, mock.Anything)
+}
+
+// TestInspectUserCacheUseCase executa toda a suite de testes
+func TestInspectUserCacheUseCase(t *testing.T) {
+	suite.Run(t, new(InspectUserCacheUseCaseTestSuite))
+}
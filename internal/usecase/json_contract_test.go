@@ -0,0 +1,24 @@
+package usecase_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertJSONEmptyArray verifica que o campo de lista serializa como [] (nunca null)
+func assertJSONEmptyArray(t *testing.T, response interface{}, field string) {
+	t.Helper()
+
+	data, err := json.Marshal(response)
+	require.NoError(t, err)
+
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &fields))
+
+	raw, ok := fields[field]
+	require.True(t, ok, "field %s not present in JSON", field)
+	assert.JSONEq(t, `[]`, string(raw), "field %s must serialize as []", field)
+}
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// Limites da listagem de usuários
+const (
+	DefaultListUsersLimit = 20
+	MaxListUsersLimit     = 100
+)
+
+// ListUsersRequest representa os dados de entrada
+type ListUsersRequest struct {
+	Limit  int `json:"limit" validate:"min=0,max=100"`
+	Offset int `json:"offset" validate:"min=0"`
+
+	// Filtros opcionais: trecho do nome e domínio do email (ex: "example.com")
+	Name        string `json:"name" validate:"omitempty,max=100"`
+	EmailDomain string `json:"email_domain" validate:"omitempty,max=255"`
+
+	// Sort é created_at_desc (padrão), created_at_asc ou name
+	Sort string `json:"sort" validate:"omitempty,oneof=created_at_desc created_at_asc name"`
+}
+
+// UserSummary representa um usuário na listagem
+type UserSummary struct {
+	UserID    string    `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListUsersResponse representa a resposta
+type ListUsersResponse struct {
+	Users   []UserSummary `json:"users"`
+	Total   int           `json:"total"` // Total de usuários (todas as páginas)
+	Limit   int           `json:"limit"`
+	Offset  int           `json:"offset"`
+	Sort    string        `json:"sort"` // Ordenação aplicada
+	HasMore bool          `json:"has_more"`
+	Message string        `json:"message"`
+}
+
+// ListUsersUseCase implementa a listagem paginada de usuários
+type ListUsersUseCase struct {
+	userRepo repository.UserRepository
+	logger   logger.Logger
+}
+
+// NewListUsersUseCase cria uma nova instância do use case
+func NewListUsersUseCase(
+	userRepo repository.UserRepository,
+	logger logger.Logger,
+) *ListUsersUseCase {
+	return &ListUsersUseCase{
+		userRepo: userRepo,
+		logger:   logger,
+	}
+}
+
+// Execute executa o use case de listar usuários
+func (uc *ListUsersUseCase) Execute(ctx context.Context, req ListUsersRequest) (*ListUsersResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	// 1. Normalizar paginação
+	if req.Limit <= 0 {
+		req.Limit = DefaultListUsersLimit
+	}
+	if req.Limit > MaxListUsersLimit {
+		req.Limit = MaxListUsersLimit
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	// 1.1. Validar filtros e ordenação
+	query, err := buildUserQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Buscar página e total (o total considera os mesmos filtros)
+	users, err := uc.userRepo.FindAll(ctx, query)
+	if err != nil {
+		log.Error("Failed to list users", map[string]interface{}{
+			"limit":  req.Limit,
+			"offset": req.Offset,
+			"sort":   query.Sort,
+			"error":  err.Error(),
+		})
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	total, err := uc.userRepo.CountUsers(ctx, query.Filter)
+	if err != nil {
+		log.Error("Failed to count users", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	// 3. Converter para resposta
+	summaries := make([]UserSummary, 0, len(users)) // Sempre [] no JSON, nunca null
+	for _, user := range users {
+		userID := user.ID()
+		email := user.Email()
+		summaries = append(summaries, UserSummary{
+			UserID:    userID.String(),
+			Name:      user.Name(),
+			Email:     email.String(),
+			CreatedAt: user.CreatedAt().Time(),
+		})
+	}
+
+	log.Info("Users listed", map[string]interface{}{
+		"limit":        req.Limit,
+		"offset":       req.Offset,
+		"name":         query.Filter.NameContains,
+		"email_domain": query.Filter.EmailDomain,
+		"sort":         query.Sort,
+		"found":        len(summaries),
+		"total":        total,
+	})
+
+	return &ListUsersResponse{
+		Users:   summaries,
+		Total:   total,
+		Limit:   req.Limit,
+		Offset:  req.Offset,
+		Sort:    string(query.Sort),
+		HasMore: req.Offset+len(summaries) < total,
+		Message: fmt.Sprintf("Retrieved %d of %d users", len(summaries), total),
+	}, nil
+}
+
+// buildUserQuery normaliza os filtros da listagem e valida a ordenação
+func buildUserQuery(req ListUsersRequest) (repository.UserQuery, error) {
+	query := repository.UserQuery{
+		Filter: repository.UserFilter{
+			NameContains: strings.TrimSpace(req.Name),
+			EmailDomain:  strings.ToLower(strings.TrimPrefix(strings.TrimSpace(req.EmailDomain), "@")),
+		},
+		Sort:   repository.UserSort(req.Sort),
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	}
+
+	if query.Sort == "" {
+		query.Sort = repository.UserSortCreatedAtDesc
+	}
+	if !query.Sort.IsValid() {
+		return query, fmt.Errorf("%w: unsupported sort %q (use created_at_desc, created_at_asc or name)", ErrInvalidInput, req.Sort)
+	}
+	if strings.ContainsAny(query.Filter.EmailDomain, "@ ") {
+		return query, fmt.Errorf("%w: invalid email domain %q", ErrInvalidInput, req.EmailDomain)
+	}
+
+	return query, nil
+}
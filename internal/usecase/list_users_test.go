@@ -0,0 +1,148 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+)
+
+// ListUsersUseCaseTestSuite define a suite de testes para ListUsersUseCase
+type ListUsersUseCaseTestSuite struct {
+	suite.Suite
+	useCase  *usecase.ListUsersUseCase
+	userRepo *mocks.MockUserRepository
+	logger   *mocks.MockLogger
+	ctx      context.Context
+}
+
+// SetupTest configura cada teste
+func (suite *ListUsersUseCaseTestSuite) SetupTest() {
+	suite.userRepo = new(mocks.MockUserRepository)
+	suite.logger = new(mocks.MockLogger)
+	suite.ctx = context.Background()
+	suite.useCase = usecase.NewListUsersUseCase(suite.userRepo, suite.logger)
+}
+
+// TearDownTest limpa após cada teste
+func (suite *ListUsersUseCaseTestSuite) TearDownTest() {
+	suite.userRepo.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// listQuery monta a consulta esperada sem filtros, na ordenação padrão
+func listQuery(limit, offset int) repository.UserQuery {
+	return repository.UserQuery{Sort: repository.UserSortCreatedAtDesc, Limit: limit, Offset: offset}
+}
+
+// TestListUsers_ReturnsPageWithTotal testa a página e os metadados de paginação
+func (suite *ListUsersUseCaseTestSuite) TestListUsers_ReturnsPageWithTotal() {
+	// Arrange
+	first, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+	second, err := entity.NewUser("user456", "Maria Souza", "maria@example.com")
+	suite.Require().NoError(err)
+
+	suite.userRepo.On("FindAll", mock.Anything, listQuery(2, 0)).Return([]*entity.User{first, second}, nil)
+	suite.userRepo.On("CountUsers", mock.Anything, repository.UserFilter{}).Return(5, nil)
+	suite.logger.On("Info", "Users listed", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.ListUsersRequest{Limit: 2})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(response.Users, 2)
+	assert.Equal(suite.T(), "user123", response.Users[0].UserID)
+	assert.Equal(suite.T(), "maria@example.com", response.Users[1].Email)
+	assert.Equal(suite.T(), 5, response.Total)
+	assert.True(suite.T(), response.HasMore)
+}
+
+// TestListUsers_NormalizesPagination testa o limite máximo e o offset negativo
+func (suite *ListUsersUseCaseTestSuite) TestListUsers_NormalizesPagination() {
+	suite.userRepo.On("FindAll", mock.Anything, listQuery(usecase.MaxListUsersLimit, 0)).Return([]*entity.User{}, nil)
+	suite.userRepo.On("CountUsers", mock.Anything, repository.UserFilter{}).Return(0, nil)
+	suite.logger.On("Info", "Users listed", mock.Anything).Return()
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.ListUsersRequest{Limit: 1000, Offset: -3})
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), usecase.MaxListUsersLimit, response.Limit)
+	assert.Equal(suite.T(), 0, response.Offset)
+	assert.NotNil(suite.T(), response.Users)
+	assert.False(suite.T(), response.HasMore)
+}
+
+// TestListUsers_DefaultLimit testa o limite padrão quando não informado
+func (suite *ListUsersUseCaseTestSuite) TestListUsers_DefaultLimit() {
+	suite.userRepo.On("FindAll", mock.Anything, listQuery(usecase.DefaultListUsersLimit, 40)).Return([]*entity.User{}, nil)
+	suite.userRepo.On("CountUsers", mock.Anything, repository.UserFilter{}).Return(10, nil)
+	suite.logger.On("Info", "Users listed", mock.Anything).Return()
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.ListUsersRequest{Offset: 40})
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), usecase.DefaultListUsersLimit, response.Limit)
+	assert.False(suite.T(), response.HasMore)
+}
+
+// TestListUsers_CountError testa a propagação do erro de contagem
+func (suite *ListUsersUseCaseTestSuite) TestListUsers_CountError() {
+	suite.userRepo.On("FindAll", mock.Anything, listQuery(usecase.DefaultListUsersLimit, 0)).Return([]*entity.User{}, nil)
+	suite.userRepo.On("CountUsers", mock.Anything, repository.UserFilter{}).Return(0, errors.New("connection refused"))
+	suite.logger.On("Error", "Failed to count users", mock.Anything).Return()
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.ListUsersRequest{})
+
+	assert.Nil(suite.T(), response)
+	assert.Error(suite.T(), err)
+}
+
+// TestListUsers_FiltersAndSort testa a normalização dos filtros e o total filtrado
+func (suite *ListUsersUseCaseTestSuite) TestListUsers_FiltersAndSort() {
+	filter := repository.UserFilter{NameContains: "silva", EmailDomain: "example.com"}
+	suite.userRepo.On("FindAll", mock.Anything, repository.UserQuery{
+		Filter: filter,
+		Sort:   repository.UserSortName,
+		Limit:  usecase.DefaultListUsersLimit,
+	}).Return([]*entity.User{}, nil)
+	suite.userRepo.On("CountUsers", mock.Anything, filter).Return(3, nil)
+	suite.logger.On("Info", "Users listed", mock.Anything).Return()
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.ListUsersRequest{
+		Name:        "  silva ",
+		EmailDomain: "@Example.com",
+		Sort:        "name",
+	})
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 3, response.Total)
+	assert.Equal(suite.T(), "name", response.Sort)
+}
+
+// TestListUsers_InvalidSortOrDomain testa a rejeição de ordenação e domínio inválidos
+func (suite *ListUsersUseCaseTestSuite) TestListUsers_InvalidSortOrDomain() {
+	for _, req := range []usecase.ListUsersRequest{
+		{Sort: "email; DROP TABLE users"},
+		{EmailDomain: "joao@example.com"},
+	} {
+		response, err := suite.useCase.Execute(suite.ctx, req)
+
+		assert.Nil(suite.T(), response)
+		assert.ErrorIs(suite.T(), err, usecase.ErrInvalidInput)
+	}
+	suite.userRepo.AssertNotCalled(suite.T(), "FindAll", mock.Anything, mock.Anything)
+}
+
+// TestListUsersUseCase executa a suite de testes
+func TestListUsersUseCase(t *testing.T) {
+	suite.Run(t, new(ListUsersUseCaseTestSuite))
+}
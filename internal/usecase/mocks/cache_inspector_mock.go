@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+)
+
+// MockCacheInspector é um mock para a inspeção de cache
+type MockCacheInspector struct {
+	mock.Mock
+}
+
+// Verifica se implementa a interface
+var _ usecase.CacheInspector = (*MockCacheInspector)(nil)
+
+// Inspect implementa o método Inspect
+func (m *MockCacheInspector) Inspect(ctx context.Context, key string) (*usecase.CacheEntry, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.CacheEntry), args.Error(1)
+}
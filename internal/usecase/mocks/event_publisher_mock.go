@@ -30,6 +30,18 @@ func (m *MockEventPublisher) PublishSectorChanged(ctx context.Context, event *ev
 	return args.Error(0)
 }
 
+// PublishProximity mock
+func (m *MockEventPublisher) PublishProximity(ctx context.Context, event *events.Event) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+// PublishUserDeleted mock
+func (m *MockEventPublisher) PublishUserDeleted(ctx context.Context, event *events.Event) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
 // Close mock
 func (m *MockEventPublisher) Close() error {
 	args := m.Called()
@@ -25,6 +25,14 @@ func (m *MockLogger) Error(msg string, fields ...interface{}) {
 	m.Called(args...)
 }
 
+// Warn mock
+func (m *MockLogger) Warn(msg string, fields ...interface{}) {
+	args := make([]interface{}, 0, len(fields)+1)
+	args = append(args, msg)
+	args = append(args, fields...)
+	m.Called(args...)
+}
+
 // Fatal mock
 func (m *MockLogger) Fatal(msg string, fields ...interface{}) {
 	args := make([]interface{}, 0, len(fields)+1)
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/mock"
 	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
 	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
 )
 
@@ -19,6 +20,48 @@ func (m *MockPositionRepository) Save(ctx context.Context, position *entity.Posi
 	return args.Error(0)
 }
 
+// SaveWithEvents mock
+func (m *MockPositionRepository) SaveWithEvents(ctx context.Context, position *entity.Position, outbox []*repository.OutboxEntry) error {
+	args := m.Called(ctx, position, outbox)
+	return args.Error(0)
+}
+
+// SaveHistory mock
+func (m *MockPositionRepository) SaveHistory(ctx context.Context, position *entity.Position) error {
+	args := m.Called(ctx, position)
+	return args.Error(0)
+}
+
+// SaveBatch mock
+func (m *MockPositionRepository) SaveBatch(ctx context.Context, history []*entity.Position, current []*entity.Position, outbox []*repository.OutboxEntry) error {
+	args := m.Called(ctx, history, current, outbox)
+	return args.Error(0)
+}
+
+// FindPositionsAfterID mock
+func (m *MockPositionRepository) FindPositionsAfterID(ctx context.Context, afterID string, limit int) ([]*entity.Position, error) {
+	args := m.Called(ctx, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Position), args.Error(1)
+}
+
+// UpdateSectors mock
+func (m *MockPositionRepository) UpdateSectors(ctx context.Context, positions []*entity.Position) error {
+	args := m.Called(ctx, positions)
+	return args.Error(0)
+}
+
+// GetSectorStatistics mock
+func (m *MockPositionRepository) GetSectorStatistics(ctx context.Context, sector *valueobject.Sector) (*repository.SectorStats, error) {
+	args := m.Called(ctx, sector)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.SectorStats), args.Error(1)
+}
+
 // FindByID mock
 func (m *MockPositionRepository) FindByID(ctx context.Context, id entity.PositionID) (*entity.Position, error) {
 	args := m.Called(ctx, id)
@@ -37,22 +80,55 @@ func (m *MockPositionRepository) FindCurrentByUserID(ctx context.Context, userID
 	return args.Get(0).(*entity.Position), args.Error(1)
 }
 
+// FindCurrentByUserIDs mock
+func (m *MockPositionRepository) FindCurrentByUserIDs(ctx context.Context, userIDs []entity.UserID) ([]*entity.Position, error) {
+	args := m.Called(ctx, userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Position), args.Error(1)
+}
+
 // FindHistoryByUserID mock
-func (m *MockPositionRepository) FindHistoryByUserID(ctx context.Context, userID entity.UserID, limit int) ([]*entity.Position, error) {
-	args := m.Called(ctx, userID, limit)
+func (m *MockPositionRepository) FindHistoryByUserID(ctx context.Context, userID entity.UserID, limit, offset int) ([]*entity.Position, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Position), args.Error(1)
+}
+
+// FindHistoryByUserIDInRange mock
+func (m *MockPositionRepository) FindHistoryByUserIDInRange(ctx context.Context, userID entity.UserID, from, to *valueobject.Timestamp, limit int) ([]*entity.Position, error) {
+	args := m.Called(ctx, userID, from, to, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Position), args.Error(1)
+}
+
+// FindHistoryByUserIDAfter mock
+func (m *MockPositionRepository) FindHistoryByUserIDAfter(ctx context.Context, userID entity.UserID, after *repository.HistoryCursor, limit int) ([]*entity.Position, error) {
+	args := m.Called(ctx, userID, after, limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*entity.Position), args.Error(1)
 }
 
+// CountHistoryByUserID mock
+func (m *MockPositionRepository) CountHistoryByUserID(ctx context.Context, userID entity.UserID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
 // FindNearby mock
-func (m *MockPositionRepository) FindNearby(ctx context.Context, coord *valueobject.Coordinate, radiusMeters float64, limit int) ([]*entity.Position, error) {
+func (m *MockPositionRepository) FindNearby(ctx context.Context, coord *valueobject.Coordinate, radiusMeters float64, limit int) ([]*repository.NearbyPosition, error) {
 	args := m.Called(ctx, coord, radiusMeters, limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*entity.Position), args.Error(1)
+	return args.Get(0).([]*repository.NearbyPosition), args.Error(1)
 }
 
 // FindInSector mock
@@ -84,3 +160,30 @@ func (m *MockPositionRepository) DeleteOldPositions(ctx context.Context, olderTh
 	args := m.Called(ctx, olderThan)
 	return args.Int(0), args.Error(1)
 }
+
+// FindRecentActivity mock
+func (m *MockPositionRepository) FindRecentActivity(ctx context.Context, since *valueobject.Timestamp, limit int) ([]*repository.RecentActivity, error) {
+	args := m.Called(ctx, since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*repository.RecentActivity), args.Error(1)
+}
+
+// FindOccupiedSectors mock
+func (m *MockPositionRepository) FindOccupiedSectors(ctx context.Context, bbox *repository.BoundingBox) ([]*repository.SectorOccupancy, error) {
+	args := m.Called(ctx, bbox)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*repository.SectorOccupancy), args.Error(1)
+}
+
+// FindInBoundingBox mock
+func (m *MockPositionRepository) FindInBoundingBox(ctx context.Context, bbox *repository.BoundingBox, limit int) ([]*entity.Position, error) {
+	args := m.Called(ctx, bbox, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Position), args.Error(1)
+}
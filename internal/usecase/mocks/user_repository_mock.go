@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/mock"
 	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
 )
 
 // MockUserRepository é um mock do UserRepository para testes
@@ -49,10 +50,16 @@ func (m *MockUserRepository) Delete(ctx context.Context, id entity.UserID) error
 }
 
 // FindAll mock
-func (m *MockUserRepository) FindAll(ctx context.Context, limit, offset int) ([]*entity.User, error) {
-	args := m.Called(ctx, limit, offset)
+func (m *MockUserRepository) FindAll(ctx context.Context, query repository.UserQuery) ([]*entity.User, error) {
+	args := m.Called(ctx, query)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*entity.User), args.Error(1)
 }
+
+// CountUsers mock
+func (m *MockUserRepository) CountUsers(ctx context.Context, filter repository.UserFilter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// PurgeOldPositionsResponse representa o resultado de uma limpeza
+type PurgeOldPositionsResponse struct {
+	Deleted int       `json:"deleted"`
+	Cutoff  time.Time `json:"cutoff"`
+}
+
+// PurgeOldPositionsUseCase remove posições fora da janela de retenção
+// Executado periodicamente pela aplicação (ver POSITIONS_RETENTION_HOURS)
+type PurgeOldPositionsUseCase struct {
+	positionRepo repository.PositionRepository
+	logger       logger.Logger
+	retention    time.Duration
+}
+
+// NewPurgeOldPositionsUseCase cria uma nova instância do use case
+func NewPurgeOldPositionsUseCase(
+	positionRepo repository.PositionRepository,
+	logger logger.Logger,
+	cfg *config.Config,
+) *PurgeOldPositionsUseCase {
+	return &PurgeOldPositionsUseCase{
+		positionRepo: positionRepo,
+		logger:       logger,
+		retention:    time.Duration(cfg.Positions.RetentionHours) * time.Hour,
+	}
+}
+
+// Enabled indica se há janela de retenção configurada
+func (uc *PurgeOldPositionsUseCase) Enabled() bool {
+	return uc.retention > 0
+}
+
+// Execute remove as posições registradas antes de agora menos a retenção
+func (uc *PurgeOldPositionsUseCase) Execute(ctx context.Context) (*PurgeOldPositionsResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	if !uc.Enabled() {
+		return &PurgeOldPositionsResponse{}, nil
+	}
+
+	cutoff := time.Now().Add(-uc.retention)
+	deleted, err := uc.positionRepo.DeleteOldPositions(ctx, valueobject.NewTimestamp(cutoff))
+	if err != nil {
+		log.Error("Failed to purge old positions", map[string]interface{}{
+			"cutoff": cutoff,
+			"error":  err.Error(),
+		})
+		return nil, fmt.Errorf("failed to purge old positions: %w", err)
+	}
+
+	log.Info("Old positions purged", map[string]interface{}{
+		"deleted":         deleted,
+		"cutoff":          cutoff,
+		"retention_hours": uc.retention.Hours(),
+	})
+
+	return &PurgeOldPositionsResponse{
+		Deleted: deleted,
+		Cutoff:  cutoff,
+	}, nil
+}
@@ -0,0 +1,94 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+)
+
+// PurgeOldPositionsUseCaseTestSuite define a suite de testes para PurgeOldPositionsUseCase
+type PurgeOldPositionsUseCaseTestSuite struct {
+	suite.Suite
+	positionRepo *mocks.MockPositionRepository
+	logger       *mocks.MockLogger
+	ctx          context.Context
+}
+
+// SetupTest configura cada teste
+func (suite *PurgeOldPositionsUseCaseTestSuite) SetupTest() {
+	suite.positionRepo = new(mocks.MockPositionRepository)
+	suite.logger = new(mocks.MockLogger)
+	suite.ctx = context.Background()
+}
+
+// TearDownTest limpa após cada teste
+func (suite *PurgeOldPositionsUseCaseTestSuite) TearDownTest() {
+	suite.positionRepo.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// newUseCase cria o use case com a retenção informada
+func (suite *PurgeOldPositionsUseCaseTestSuite) newUseCase(retentionHours int) *usecase.PurgeOldPositionsUseCase {
+	cfg := &config.Config{Positions: config.PositionsConfig{RetentionHours: retentionHours}}
+	return usecase.NewPurgeOldPositionsUseCase(suite.positionRepo, suite.logger, cfg)
+}
+
+// TestPurgeOldPositions_DeletesBeforeCutoff testa o corte em agora menos a retenção
+func (suite *PurgeOldPositionsUseCaseTestSuite) TestPurgeOldPositions_DeletesBeforeCutoff() {
+	// Arrange
+	useCase := suite.newUseCase(24)
+	expectedCutoff := time.Now().Add(-24 * time.Hour)
+
+	suite.positionRepo.On("DeleteOldPositions", mock.Anything, mock.MatchedBy(func(cutoff *valueobject.Timestamp) bool {
+		return cutoff.Time().Sub(expectedCutoff).Abs() < time.Minute
+	})).Return(42, nil)
+	suite.logger.On("Info", "Old positions purged", mock.Anything).Return()
+
+	// Act
+	response, err := useCase.Execute(suite.ctx)
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 42, response.Deleted)
+	assert.WithinDuration(suite.T(), expectedCutoff, response.Cutoff, time.Minute)
+}
+
+// TestPurgeOldPositions_Disabled testa que retenção 0 não toca no banco
+func (suite *PurgeOldPositionsUseCaseTestSuite) TestPurgeOldPositions_Disabled() {
+	useCase := suite.newUseCase(0)
+
+	response, err := useCase.Execute(suite.ctx)
+
+	suite.Require().NoError(err)
+	assert.False(suite.T(), useCase.Enabled())
+	assert.Equal(suite.T(), 0, response.Deleted)
+	suite.positionRepo.AssertNotCalled(suite.T(), "DeleteOldPositions", mock.Anything, mock.Anything)
+}
+
+// TestPurgeOldPositions_RepositoryError testa a propagação do erro do banco
+func (suite *PurgeOldPositionsUseCaseTestSuite) TestPurgeOldPositions_RepositoryError() {
+	useCase := suite.newUseCase(24)
+
+	suite.positionRepo.On("DeleteOldPositions", mock.Anything, mock.Anything).
+		Return(0, errors.New("connection refused"))
+	suite.logger.On("Error", "Failed to purge old positions", mock.Anything).Return()
+
+	response, err := useCase.Execute(suite.ctx)
+
+	assert.Nil(suite.T(), response)
+	assert.Error(suite.T(), err)
+}
+
+// TestPurgeOldPositionsUseCase executa a suite de testes
+func TestPurgeOldPositionsUseCase(t *testing.T) {
+	suite.Run(t, new(PurgeOldPositionsUseCaseTestSuite))
+}
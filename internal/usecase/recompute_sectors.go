@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// DefaultSectorRecomputeChunkSize é o tamanho de chunk usado quando não configurado
+const DefaultSectorRecomputeChunkSize = 1000
+
+// Estados do recálculo em background
+const (
+	RecomputeStatusIdle      = "idle"
+	RecomputeStatusRunning   = "running"
+	RecomputeStatusCompleted = "completed"
+	RecomputeStatusFailed    = "failed"
+)
+
+// sectorRecomputeWorker é o nome do worker que executa o recálculo
+const sectorRecomputeWorker = "sector-recompute"
+
+// ErrRecomputeInProgress indica que já existe um recálculo de setores em execução
+var ErrRecomputeInProgress = errors.New("sector recompute already in progress")
+
+// BackgroundRunner executa rotinas longas fora da requisição, encerradas no shutdown (ex.: worker.Manager)
+type BackgroundRunner interface {
+	Go(name string, fn func(ctx context.Context))
+}
+
+// RecomputeSectorsResponse representa o resultado do recálculo
+type RecomputeSectorsResponse struct {
+	Scanned int    `json:"scanned"`
+	Updated int    `json:"updated"`
+	Chunks  int    `json:"chunks"`
+	Message string `json:"message"`
+}
+
+// RecomputeSectorsStatus representa o estado do último recálculo em background
+type RecomputeSectorsStatus struct {
+	Status     string                    `json:"status"`
+	StartedAt  *time.Time                `json:"started_at,omitempty"`
+	FinishedAt *time.Time                `json:"finished_at,omitempty"`
+	Result     *RecomputeSectorsResponse `json:"result,omitempty"`
+	Error      string                    `json:"error,omitempty"`
+}
+
+// RecomputeSectorsUseCase recalcula sector_x/sector_y das posições armazenadas
+// Necessário quando o esquema de setorização (origem ou tamanho) muda
+type RecomputeSectorsUseCase struct {
+	positionRepo repository.PositionRepository
+	logger       logger.Logger
+	chunkSize    int
+
+	mu  sync.Mutex
+	job RecomputeSectorsStatus // Último recálculo iniciado por Start
+}
+
+// NewRecomputeSectorsUseCase cria uma nova instância do use case
+func NewRecomputeSectorsUseCase(
+	positionRepo repository.PositionRepository,
+	logger logger.Logger,
+	cfg *config.Config,
+) *RecomputeSectorsUseCase {
+	chunkSize := cfg.Database.SectorRecomputeChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultSectorRecomputeChunkSize
+	}
+
+	return &RecomputeSectorsUseCase{
+		positionRepo: positionRepo,
+		logger:       logger,
+		chunkSize:    chunkSize,
+		job:          RecomputeSectorsStatus{Status: RecomputeStatusIdle},
+	}
+}
+
+// Start inicia o recálculo em background e retorna o estado inicial do job
+// O recálculo roda no context do runner (não no da requisição); só um pode rodar por vez
+func (uc *RecomputeSectorsUseCase) Start(runner BackgroundRunner) (*RecomputeSectorsStatus, error) {
+	uc.mu.Lock()
+	if uc.job.Status == RecomputeStatusRunning {
+		status := uc.job
+		uc.mu.Unlock()
+		return &status, ErrRecomputeInProgress
+	}
+
+	startedAt := time.Now().UTC()
+	uc.job = RecomputeSectorsStatus{Status: RecomputeStatusRunning, StartedAt: &startedAt}
+	status := uc.job
+	uc.mu.Unlock()
+
+	runner.Go(sectorRecomputeWorker, func(ctx context.Context) {
+		response, err := uc.Execute(ctx)
+		uc.finish(response, err)
+	})
+
+	return &status, nil
+}
+
+// Status retorna o estado do último recálculo iniciado por Start
+func (uc *RecomputeSectorsUseCase) Status() *RecomputeSectorsStatus {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	status := uc.job
+	return &status
+}
+
+// finish registra o resultado do recálculo em background
+func (uc *RecomputeSectorsUseCase) finish(response *RecomputeSectorsResponse, err error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	finishedAt := time.Now().UTC()
+	uc.job.FinishedAt = &finishedAt
+	if err != nil {
+		uc.job.Status = RecomputeStatusFailed
+		uc.job.Error = err.Error()
+		return
+	}
+	uc.job.Status = RecomputeStatusCompleted
+	uc.job.Result = response
+}
+
+// Execute percorre todas as posições em chunks e regrava apenas os setores divergentes
+func (uc *RecomputeSectorsUseCase) Execute(ctx context.Context) (*RecomputeSectorsResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	response := &RecomputeSectorsResponse{}
+	lastID := ""
+
+	for {
+		positions, err := uc.positionRepo.FindPositionsAfterID(ctx, lastID, uc.chunkSize)
+		if err != nil {
+			log.Error("Failed to list positions for sector recompute", map[string]interface{}{
+				"after_id": lastID,
+				"error":    err.Error(),
+			})
+			return nil, fmt.Errorf("failed to list positions: %w", err)
+		}
+		if len(positions) == 0 {
+			break
+		}
+
+		changed, err := recomputePositionSectors(positions)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(changed) > 0 {
+			if err := uc.positionRepo.UpdateSectors(ctx, changed); err != nil {
+				log.Error("Failed to update position sectors", map[string]interface{}{
+					"after_id": lastID,
+					"count":    len(changed),
+					"error":    err.Error(),
+				})
+				return nil, fmt.Errorf("failed to update sectors: %w", err)
+			}
+		}
+
+		lastPositionID := positions[len(positions)-1].ID()
+		lastID = lastPositionID.Value()
+		response.Scanned += len(positions)
+		response.Updated += len(changed)
+		response.Chunks++
+
+		log.Debug("Sector recompute chunk processed", map[string]interface{}{
+			"chunk":   response.Chunks,
+			"scanned": len(positions),
+			"updated": len(changed),
+		})
+
+		if len(positions) < uc.chunkSize {
+			break
+		}
+	}
+
+	response.Message = fmt.Sprintf("%d of %d positions updated", response.Updated, response.Scanned)
+
+	log.Info("Sector recompute completed", map[string]interface{}{
+		"scanned": response.Scanned,
+		"updated": response.Updated,
+		"chunks":  response.Chunks,
+	})
+
+	return response, nil
+}
+
+// recomputePositionSectors retorna as posições cujo setor armazenado difere do esquema atual,
+// já com o setor recalculado
+func recomputePositionSectors(positions []*entity.Position) ([]*entity.Position, error) {
+	changed := make([]*entity.Position, 0)
+
+	for _, position := range positions {
+		positionID := position.ID()
+		sector, err := valueobject.NewSectorFromCoordinate(position.Coordinate())
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute sector for position %s: %w", positionID.Value(), err)
+		}
+
+		if sector.X() == position.SectorX() && sector.Y() == position.SectorY() {
+			continue
+		}
+
+		updated, err := entity.ReconstructPosition(
+			positionID.Value(),
+			position.UserID(),
+			position.Latitude(),
+			position.Longitude(),
+			sector.X(),
+			sector.Y(),
+			position.RecordedAt().Time(),
+			position.CreatedAt().Time(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rebuild position %s: %w", positionID.Value(), err)
+		}
+
+		changed = append(changed, updated)
+	}
+
+	return changed, nil
+}
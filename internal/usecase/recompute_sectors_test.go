@@ -0,0 +1,222 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+)
+
+// RecomputeSectorsUseCaseTestSuite define a suite de testes para RecomputeSectorsUseCase
+type RecomputeSectorsUseCaseTestSuite struct {
+	suite.Suite
+	positionRepo *mocks.MockPositionRepository
+	logger       *mocks.MockLogger
+	useCase      *usecase.RecomputeSectorsUseCase
+	ctx          context.Context
+}
+
+// SetupTest configura cada teste com chunks de 2 posições
+func (suite *RecomputeSectorsUseCaseTestSuite) SetupTest() {
+	suite.positionRepo = new(mocks.MockPositionRepository)
+	suite.logger = new(mocks.MockLogger)
+	cfg := &config.Config{Database: config.DatabaseConfig{SectorRecomputeChunkSize: 2}}
+	suite.useCase = usecase.NewRecomputeSectorsUseCase(suite.positionRepo, suite.logger, cfg)
+	suite.ctx = context.Background()
+
+	suite.logger.On("Debug", "Sector recompute chunk processed", mock.Anything).Return().Maybe()
+}
+
+// TearDownTest limpa após cada teste
+func (suite *RecomputeSectorsUseCaseTestSuite) TearDownTest() {
+	suite.positionRepo.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// storedPosition simula uma posição gravada com setor do esquema antigo
+func (suite *RecomputeSectorsUseCaseTestSuite) storedPosition(id string, lat, lng float64, sectorX, sectorY int) *entity.Position {
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	now := time.Now()
+	position, err := entity.ReconstructPosition(id, *userID, lat, lng, sectorX, sectorY, now, now)
+	suite.Require().NoError(err)
+	return position
+}
+
+// currentSector calcula o setor esperado pelo esquema atual
+func (suite *RecomputeSectorsUseCaseTestSuite) currentSector(lat, lng float64) *valueobject.Sector {
+	coord, err := valueobject.NewCoordinate(lat, lng)
+	suite.Require().NoError(err)
+	sector, err := valueobject.NewSectorFromCoordinate(coord)
+	suite.Require().NoError(err)
+	return sector
+}
+
+// TestRecomputeSectors_RewritesStaleSectors testa que os setores regravados batem com NewSectorFromCoordinate
+func (suite *RecomputeSectorsUseCaseTestSuite) TestRecomputeSectors_RewritesStaleSectors() {
+	// Arrange
+	upToDate := suite.currentSector(-22.906847, -43.172896)
+	chunk1 := []*entity.Position{
+		suite.storedPosition("pos-1", -23.550520, -46.633309, 1, 1),
+		suite.storedPosition("pos-2", -22.906847, -43.172896, upToDate.X(), upToDate.Y()),
+	}
+	chunk2 := []*entity.Position{
+		suite.storedPosition("pos-3", 40.712776, -74.005974, 0, 0),
+	}
+
+	suite.positionRepo.On("FindPositionsAfterID", mock.Anything, "", 2).Return(chunk1, nil)
+	suite.positionRepo.On("FindPositionsAfterID", mock.Anything, "pos-2", 2).Return(chunk2, nil)
+
+	var updated []*entity.Position
+	suite.positionRepo.On("UpdateSectors", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			updated = append(updated, args.Get(1).([]*entity.Position)...)
+		}).Return(nil).Twice()
+
+	suite.logger.On("Info", "Sector recompute completed", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx)
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 3, response.Scanned)
+	assert.Equal(suite.T(), 2, response.Updated)
+	assert.Equal(suite.T(), 2, response.Chunks)
+
+	// Apenas as posições com setor divergente são regravadas, já no esquema atual
+	suite.Require().Len(updated, 2)
+	for _, position := range updated {
+		expected := suite.currentSector(position.Latitude(), position.Longitude())
+		assert.Equal(suite.T(), expected.X(), position.SectorX())
+		assert.Equal(suite.T(), expected.Y(), position.SectorY())
+	}
+	firstID, lastID := updated[0].ID(), updated[1].ID()
+	assert.Equal(suite.T(), "pos-1", firstID.Value())
+	assert.Equal(suite.T(), "pos-3", lastID.Value())
+}
+
+// TestRecomputeSectors_FullLastChunk testa que um chunk cheio busca o próximo até vir vazio
+func (suite *RecomputeSectorsUseCaseTestSuite) TestRecomputeSectors_FullLastChunk() {
+	// Arrange
+	sector := suite.currentSector(-23.550520, -46.633309)
+	chunk := []*entity.Position{
+		suite.storedPosition("pos-1", -23.550520, -46.633309, sector.X(), sector.Y()),
+		suite.storedPosition("pos-2", -23.550520, -46.633309, sector.X(), sector.Y()),
+	}
+
+	suite.positionRepo.On("FindPositionsAfterID", mock.Anything, "", 2).Return(chunk, nil)
+	suite.positionRepo.On("FindPositionsAfterID", mock.Anything, "pos-2", 2).Return([]*entity.Position{}, nil)
+	suite.logger.On("Info", "Sector recompute completed", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx)
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 2, response.Scanned)
+	assert.Equal(suite.T(), 0, response.Updated)
+	suite.positionRepo.AssertNotCalled(suite.T(), "UpdateSectors", mock.Anything, mock.Anything)
+}
+
+// TestRecomputeSectors_UpdateError testa falha ao regravar um chunk
+func (suite *RecomputeSectorsUseCaseTestSuite) TestRecomputeSectors_UpdateError() {
+	// Arrange
+	chunk := []*entity.Position{suite.storedPosition("pos-1", -23.550520, -46.633309, 1, 1)}
+
+	suite.positionRepo.On("FindPositionsAfterID", mock.Anything, "", 2).Return(chunk, nil)
+	suite.positionRepo.On("UpdateSectors", mock.Anything, mock.Anything).Return(errors.New("database connection failed"))
+	suite.logger.On("Error", "Failed to update position sectors", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx)
+
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), response)
+	assert.Contains(suite.T(), err.Error(), "failed to update sectors")
+}
+
+// deferredRunner guarda as rotinas recebidas para o teste executá-las quando quiser
+type deferredRunner struct {
+	names []string
+	fns   []func(ctx context.Context)
+}
+
+func (r *deferredRunner) Go(name string, fn func(ctx context.Context)) {
+	r.names = append(r.names, name)
+	r.fns = append(r.fns, fn)
+}
+
+// TestRecomputeSectors_StartRunsInBackground testa o ciclo do job: running, recusa de um segundo início e completed
+func (suite *RecomputeSectorsUseCaseTestSuite) TestRecomputeSectors_StartRunsInBackground() {
+	// Arrange
+	chunk := []*entity.Position{suite.storedPosition("pos-1", -23.550520, -46.633309, 1, 1)}
+	suite.positionRepo.On("FindPositionsAfterID", mock.Anything, "", 2).Return(chunk, nil)
+	suite.positionRepo.On("UpdateSectors", mock.Anything, mock.Anything).Return(nil)
+	suite.logger.On("Info", "Sector recompute completed", mock.Anything).Return()
+	runner := &deferredRunner{}
+
+	assert.Equal(suite.T(), usecase.RecomputeStatusIdle, suite.useCase.Status().Status)
+
+	// Act: iniciar sem executar a rotina ainda
+	status, err := suite.useCase.Start(runner)
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), usecase.RecomputeStatusRunning, status.Status)
+	assert.NotNil(suite.T(), status.StartedAt)
+	suite.Require().Len(runner.fns, 1)
+	assert.Equal(suite.T(), "sector-recompute", runner.names[0])
+	suite.positionRepo.AssertNotCalled(suite.T(), "FindPositionsAfterID", mock.Anything, mock.Anything, mock.Anything)
+
+	// Um segundo início enquanto o primeiro roda é recusado
+	_, err = suite.useCase.Start(runner)
+	assert.ErrorIs(suite.T(), err, usecase.ErrRecomputeInProgress)
+	assert.Len(suite.T(), runner.fns, 1)
+
+	// O worker termina e o resultado fica disponível no status
+	runner.fns[0](suite.ctx)
+	final := suite.useCase.Status()
+	assert.Equal(suite.T(), usecase.RecomputeStatusCompleted, final.Status)
+	assert.NotNil(suite.T(), final.FinishedAt)
+	suite.Require().NotNil(final.Result)
+	assert.Equal(suite.T(), 1, final.Result.Updated)
+}
+
+// TestRecomputeSectors_StartRecordsFailure testa que uma falha do worker fica registrada no status
+func (suite *RecomputeSectorsUseCaseTestSuite) TestRecomputeSectors_StartRecordsFailure() {
+	// Arrange
+	suite.positionRepo.On("FindPositionsAfterID", mock.Anything, "", 2).Return(nil, errors.New("database connection failed"))
+	suite.logger.On("Error", "Failed to list positions for sector recompute", mock.Anything).Return()
+	runner := &deferredRunner{}
+
+	// Act
+	_, err := suite.useCase.Start(runner)
+	suite.Require().NoError(err)
+	runner.fns[0](suite.ctx)
+
+	// Assert
+	status := suite.useCase.Status()
+	assert.Equal(suite.T(), usecase.RecomputeStatusFailed, status.Status)
+	assert.Contains(suite.T(), status.Error, "database connection failed")
+	assert.Nil(suite.T(), status.Result)
+
+	// Depois de terminar, um novo recálculo pode ser iniciado
+	_, err = suite.useCase.Start(runner)
+	assert.NoError(suite.T(), err)
+}
+
+// TestRecomputeSectorsUseCase executa toda a suite de testes
+func TestRecomputeSectorsUseCase(t *testing.T) {
+	suite.Run(t, new(RecomputeSectorsUseCaseTestSuite))
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// ContextWithRequestID retorna um context com o ID de correlação da requisição
+// O ID também entra nos logs derivados com logger.FromContext
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return logger.ContextWithRequestID(ctx, requestID)
+}
+
+// RequestIDFromContext retorna o ID de correlação da requisição ("" se ausente)
+func RequestIDFromContext(ctx context.Context) string {
+	return logger.RequestIDFromContext(ctx)
+}
@@ -2,15 +2,18 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/vitao/geolocation-tracker/internal/domain/entity"
 	"github.com/vitao/geolocation-tracker/internal/domain/events"
 	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/service"
 	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
+	"github.com/vitao/geolocation-tracker/pkg/config"
 	"github.com/vitao/geolocation-tracker/pkg/logger"
+	"github.com/vitao/geolocation-tracker/pkg/metrics"
 )
 
 // SaveUserPositionRequest representa os dados de entrada para salvar posição
@@ -19,12 +22,15 @@ type SaveUserPositionRequest struct {
 	Latitude  float64   `json:"latitude" validate:"required,min=-90,max=90"`
 	Longitude float64   `json:"longitude" validate:"required,min=-180,max=180"`
 	Timestamp time.Time `json:"timestamp"`
+	RequestID string    `json:"-"` // ID de correlação (X-Request-ID), propagado aos eventos
 }
 
 // SaveUserPositionResponse representa a resposta
 type SaveUserPositionResponse struct {
 	PositionID string `json:"position_id"`
 	SectorID   string `json:"sector_id"`
+	Flagged    bool   `json:"flagged,omitempty"`
+	FlagReason string `json:"flag_reason,omitempty"`
 	Message    string `json:"message"`
 }
 
@@ -34,57 +40,104 @@ type SaveUserPositionUseCase struct {
 	positionRepo   repository.PositionRepository
 	eventPublisher events.Publisher
 	cache          CacheInterface
+	validator      service.PositionValidator
+	metrics        metrics.Collector
 	logger         logger.Logger
+
+	// updateCurrentOnOutOfOrder permite que posições fora de ordem substituam a atual
+	updateCurrentOnOutOfOrder bool
+
+	// intraSectorMovementEvents publica movimento dentro do mesmo setor
+	intraSectorMovementEvents bool
+
+	// proximityAlertRadiusM é o raio dos alertas de proximidade (0 desabilita)
+	proximityAlertRadiusM float64
+
+	// maxSpeedKmh é a velocidade máxima plausível desde a posição atual (0 desabilita)
+	maxSpeedKmh float64
+
+	// rejectImplausibleSpeed recusa posições acima de maxSpeedKmh em vez de apenas marcá-las
+	rejectImplausibleSpeed bool
 }
 
+// implausibleSpeedReject é o valor de POSITIONS_IMPLAUSIBLE_SPEED_ACTION que recusa a posição
+// Qualquer outro valor apenas marca a posição como suspeita
+const implausibleSpeedReject = "reject"
+
+// Limites dos alertas de proximidade
+const (
+	maxProximityCandidates = 50        // Usuários avaliados por posição salva
+	proximityStateTTL      = time.Hour // Estado "já próximos" expira sem novas posições
+)
+
 // NewSaveUserPositionUseCase cria uma nova instância do use case
 func NewSaveUserPositionUseCase(
 	userRepo repository.UserRepository,
 	positionRepo repository.PositionRepository,
 	eventPublisher events.Publisher,
 	cache CacheInterface,
+	validator service.PositionValidator,
+	collector metrics.Collector,
 	logger logger.Logger,
+	cfg *config.Config,
 ) *SaveUserPositionUseCase {
 	return &SaveUserPositionUseCase{
-		userRepo:       userRepo,
-		positionRepo:   positionRepo,
-		eventPublisher: eventPublisher,
-		cache:          cache,
-		logger:         logger,
+		userRepo:                  userRepo,
+		positionRepo:              positionRepo,
+		eventPublisher:            eventPublisher,
+		cache:                     cache,
+		validator:                 validator,
+		metrics:                   collector,
+		logger:                    logger,
+		updateCurrentOnOutOfOrder: cfg.Positions.UpdateCurrentOnOutOfOrder,
+		intraSectorMovementEvents: cfg.Positions.IntraSectorMovementEvents,
+		proximityAlertRadiusM:     cfg.Positions.ProximityAlertRadiusM,
+		maxSpeedKmh:               cfg.Positions.MaxSpeedKmh,
+		rejectImplausibleSpeed:    cfg.Positions.ImplausibleSpeedAction == implausibleSpeedReject,
 	}
 }
 
 // Execute executa o use case de salvar posição do usuário
 func (uc *SaveUserPositionUseCase) Execute(ctx context.Context, req SaveUserPositionRequest) (*SaveUserPositionResponse, error) {
+	start := time.Now()
+	response, err := uc.execute(ctx, req)
+	uc.metrics.ObserveUseCase("save_user_position", time.Since(start), err)
+	return response, err
+}
+
+// execute contém o fluxo do use case; Execute apenas o instrumenta
+func (uc *SaveUserPositionUseCase) execute(ctx context.Context, req SaveUserPositionRequest) (*SaveUserPositionResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
 	// 1. Criar UserID e validar se o usuário existe
 	userIDPtr, err := entity.NewUserID(req.UserID)
 	if err != nil {
-		uc.logger.Error("Invalid user ID", map[string]interface{}{
+		log.Error("Invalid user ID", map[string]interface{}{
 			"user_id": req.UserID,
 			"error":   err.Error(),
 		})
-		return nil, fmt.Errorf("invalid user ID: %w", err)
+		return nil, fmt.Errorf("%w: invalid user ID: %w", ErrInvalidInput, err)
 	}
 
 	userID := *userIDPtr // Desreferencia o ponteiro
 	user, err := uc.userRepo.FindByID(ctx, userID)
 	if err != nil {
-		uc.logger.Error("User not found", map[string]interface{}{
+		log.Error("User not found", map[string]interface{}{
 			"user_id": req.UserID,
 			"error":   err.Error(),
 		})
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, userLookupError(req.UserID, err)
 	}
 
 	// 2. Criar coordenada e validar
 	coordinate, err := valueobject.NewCoordinate(req.Latitude, req.Longitude)
 	if err != nil {
-		uc.logger.Error("Invalid coordinates", map[string]interface{}{
+		log.Error("Invalid coordinates", map[string]interface{}{
 			"latitude":  req.Latitude,
 			"longitude": req.Longitude,
 			"error":     err.Error(),
 		})
-		return nil, fmt.Errorf("invalid coordinates: %w", err)
+		return nil, fmt.Errorf("%w: invalid coordinates: %w", ErrInvalidInput, err)
 	}
 
 	// 3. Usar timestamp atual se não fornecido
@@ -94,30 +147,102 @@ func (uc *SaveUserPositionUseCase) Execute(ctx context.Context, req SaveUserPosi
 	}
 
 	// 4. Criar nova posição
-	positionID := uuid.New().String()
+	positionID := entity.NewRandomPositionID()
 	position, err := entity.NewPosition(
-		positionID,
+		positionID.Value(),
 		user.ID(),
 		coordinate.Latitude(),
 		coordinate.Longitude(),
 		timestamp,
 	)
 	if err != nil {
-		uc.logger.Error("Failed to create position", map[string]interface{}{
+		log.Error("Failed to create position", map[string]interface{}{
 			"user_id": user.ID(),
 			"error":   err.Error(),
 		})
+		// Timestamp no futuro ou além da idade máxima é erro do cliente
+		if errors.Is(err, entity.ErrFuturePosition) || errors.Is(err, entity.ErrPositionTooOld) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+		}
 		return nil, fmt.Errorf("failed to create position: %w", err)
 	}
 
+	// 4.1. Aplicar validação plugável (ex: posição dentro do evento)
+	validation, err := uc.validator.Validate(ctx, position)
+	if err != nil {
+		log.Error("Failed to validate position", map[string]interface{}{
+			"user_id": req.UserID,
+			"error":   err.Error(),
+		})
+		return nil, fmt.Errorf("failed to validate position: %w", err)
+	}
+	if validation.Rejected {
+		log.Warn("Position rejected by validator", map[string]interface{}{
+			"user_id":   req.UserID,
+			"latitude":  coordinate.Latitude(),
+			"longitude": coordinate.Longitude(),
+			"reason":    validation.Reason,
+		})
+		return nil, fmt.Errorf("%w: %s", service.ErrPositionRejected, validation.Reason)
+	}
+	if validation.Flagged {
+		log.Warn("Position flagged by validator", map[string]interface{}{
+			"user_id":   req.UserID,
+			"latitude":  coordinate.Latitude(),
+			"longitude": coordinate.Longitude(),
+			"reason":    validation.Reason,
+		})
+	}
+
 	// 5. Buscar posição anterior para comparação (para eventos)
 	var previousPosition *entity.Position
 	previousPosition, _ = uc.positionRepo.FindCurrentByUserID(ctx, userID)
 	// Não retornamos erro se não encontrar posição anterior (usuário novo)
 
-	// 6. Salvar posição no repositório
-	if err := uc.positionRepo.Save(ctx, position); err != nil {
-		uc.logger.Error("Failed to save position", map[string]interface{}{
+	// 5.1. Posição fora de ordem (mais antiga que a atual) vai apenas para o histórico
+	if uc.isOutOfOrder(position, previousPosition) {
+		response, err := uc.saveOutOfOrderPosition(ctx, req.UserID, position, previousPosition)
+		if err != nil {
+			return nil, err
+		}
+		response.Flagged = validation.Flagged
+		response.FlagReason = validation.Reason
+		return response, nil
+	}
+
+	// 5.2. Saltos acima da velocidade plausível são recusados ou marcados como suspeitos
+	if speedKmh := position.SpeedKmhFrom(previousPosition); uc.isImplausibleSpeed(speedKmh) {
+		reason := uc.implausibleSpeedReason(speedKmh)
+		if uc.rejectImplausibleSpeed {
+			log.Warn("Position rejected by speed guard", map[string]interface{}{
+				"user_id":   req.UserID,
+				"speed_kmh": speedKmh,
+				"max_kmh":   uc.maxSpeedKmh,
+			})
+			return nil, fmt.Errorf("%w: %s", service.ErrPositionRejected, reason)
+		}
+
+		log.Warn("Position flagged by speed guard", map[string]interface{}{
+			"user_id":   req.UserID,
+			"speed_kmh": speedKmh,
+			"max_kmh":   uc.maxSpeedKmh,
+		})
+		if !validation.Flagged {
+			validation.Flagged = true
+			validation.Reason = reason
+		}
+	}
+
+	// 6. Salvar posição e o evento de mudança de posição na mesma transação (outbox)
+	// O relay de outbox publica o evento no Redis; ele não se perde se o Redis estiver fora
+	requestID := req.RequestID
+	if requestID == "" {
+		requestID = RequestIDFromContext(ctx)
+	}
+	positionChanged := uc.newPositionChangedEvent(requestID, user, position, previousPosition)
+	outbox := []*repository.OutboxEntry{{Stream: events.StreamPositionEvents, Event: positionChanged}}
+	if err := uc.positionRepo.SaveWithEvents(ctx, position, outbox); err != nil {
+		log.Error("Failed to save position", map[string]interface{}{
 			"position_id": position.ID(),
 			"user_id":     user.ID(),
 			"error":       err.Error(),
@@ -125,21 +250,28 @@ func (uc *SaveUserPositionUseCase) Execute(ctx context.Context, req SaveUserPosi
 		return nil, fmt.Errorf("failed to save position: %w", err)
 	}
 
-	// 7. Publicar evento de mudança de posição
-	if err := uc.publishPositionChangedEvent(ctx, user, position, previousPosition); err != nil {
-		// Log error mas não falha a operação (evento é secundário)
-		uc.logger.Error("Failed to publish position changed event",
+	// 7. Publicar eventos de saída/entrada quando o setor muda
+	if err := uc.publishSectorChangedEvents(ctx, requestID, user, position, previousPosition); err != nil {
+		log.Error("Failed to publish sector changed events", publishFailureFields(err,
 			"position_id", position.ID(),
 			"user_id", user.ID(),
-			"error", err.Error(),
-		)
+		)...)
+	}
+
+	// 7.1. Alertar proximidade com usuários que acabaram de ficar perto
+	if err := uc.publishProximityEvents(ctx, requestID, user, position); err != nil {
+		log.Error("Failed to publish proximity events", publishFailureFields(err,
+			"position_id", position.ID(),
+			"user_id", user.ID(),
+		)...)
 	}
 
 	// 8. Invalidar caches relacionados (importante!)
 	uc.invalidateRelatedCaches(ctx, req.UserID)
+	uc.invalidateSectorCaches(ctx, position, previousPosition)
 
 	// 9. Log de sucesso
-	uc.logger.Info("Position saved successfully", map[string]interface{}{
+	log.Info("Position saved successfully", map[string]interface{}{
 		"position_id": position.ID(),
 		"user_id":     user.ID(),
 		"sector":      position.Sector().ID(),
@@ -152,50 +284,122 @@ func (uc *SaveUserPositionUseCase) Execute(ctx context.Context, req SaveUserPosi
 	return &SaveUserPositionResponse{
 		PositionID: positionIDEntity.String(),
 		SectorID:   position.Sector().ID(),
+		Flagged:    validation.Flagged,
+		FlagReason: validation.Reason,
 		Message:    "Position saved successfully",
 	}, nil
 }
 
-// invalidateRelatedCaches invalida caches relacionados ao usuário
+// publishFailureFields monta os campos do log de falha de publicação
+// Um events.PublishError indica que o publisher esgotou as tentativas e o evento foi perdido
+func publishFailureFields(err error, fields ...interface{}) []interface{} {
+	var publishErr *events.PublishError
+	if errors.As(err, &publishErr) {
+		fields = append(fields, "attempts", publishErr.Attempts, "event_lost", true)
+	}
+	return append(fields, "error", err.Error())
+}
+
+// isOutOfOrder verifica se a nova posição foi registrada antes da posição atual
+func (uc *SaveUserPositionUseCase) isOutOfOrder(position, previousPosition *entity.Position) bool {
+	if uc.updateCurrentOnOutOfOrder || previousPosition == nil {
+		return false
+	}
+	return position.RecordedAt().Time().Before(previousPosition.RecordedAt().Time())
+}
+
+// isImplausibleSpeed verifica se a velocidade excede o limite configurado
+func (uc *SaveUserPositionUseCase) isImplausibleSpeed(speedKmh float64) bool {
+	return uc.maxSpeedKmh > 0 && speedKmh > uc.maxSpeedKmh
+}
+
+// implausibleSpeedReason descreve o motivo da recusa ou marcação pela velocidade
+func (uc *SaveUserPositionUseCase) implausibleSpeedReason(speedKmh float64) string {
+	return fmt.Sprintf("implausible speed %.0f km/h (max %.0f km/h)", speedKmh, uc.maxSpeedKmh)
+}
+
+// saveOutOfOrderPosition salva a posição apenas no histórico, mantendo a posição atual
+// Nenhum evento é publicado: o usuário não se moveu em relação à posição atual
+func (uc *SaveUserPositionUseCase) saveOutOfOrderPosition(
+	ctx context.Context,
+	userID string,
+	position *entity.Position,
+	currentPosition *entity.Position,
+) (*SaveUserPositionResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	if err := uc.positionRepo.SaveHistory(ctx, position); err != nil {
+		log.Error("Failed to save position to history", map[string]interface{}{
+			"position_id": position.ID(),
+			"user_id":     userID,
+			"error":       err.Error(),
+		})
+		return nil, fmt.Errorf("failed to save position: %w", err)
+	}
+
+	// Apenas o histórico mudou, mas a invalidação completa é barata e segura
+	uc.invalidateRelatedCaches(ctx, userID)
+
+	log.Info("Out-of-order position saved to history", map[string]interface{}{
+		"position_id":         position.ID(),
+		"user_id":             userID,
+		"recorded_at":         position.RecordedAt().Time(),
+		"current_recorded_at": currentPosition.RecordedAt().Time(),
+	})
+
+	positionID := position.ID()
+	return &SaveUserPositionResponse{
+		PositionID: positionID.String(),
+		SectorID:   position.Sector().ID(),
+		Message:    "Position saved to history (older than current position)",
+	}, nil
+}
+
+// invalidateRelatedCaches invalida a posição atual e todos os históricos cacheados do usuário
 func (uc *SaveUserPositionUseCase) invalidateRelatedCaches(ctx context.Context, userID string) {
-	// 1. Invalidar cache de posição atual do usuário
-	currentPosKey := fmt.Sprintf("user:position:%s", userID)
-	if err := uc.cache.Delete(ctx, currentPosKey); err != nil {
-		uc.logger.Error("Failed to invalidate current position cache", map[string]interface{}{
+	log := logger.FromContext(ctx, uc.logger)
+
+	if err := uc.cache.InvalidateUserCaches(ctx, userID); err != nil {
+		log.Error("Failed to invalidate user caches", map[string]interface{}{
 			"user_id": userID,
-			"key":     currentPosKey,
 			"error":   err.Error(),
 		})
+		return
 	}
 
-	// 2. Invalidar cache de histórico do usuário (múltiplos limits possíveis)
-	// Nota: Redis pattern matching seria ideal aqui, mas para simplicidade vamos invalidar os mais comuns
-	commonLimits := []int{10, 20, 50, 100}
-	for _, limit := range commonLimits {
-		historyKey := fmt.Sprintf("history:%s:%d", userID, limit)
-		if err := uc.cache.Delete(ctx, historyKey); err != nil {
-			uc.logger.Debug("Failed to invalidate history cache", map[string]interface{}{
-				"user_id": userID,
-				"key":     historyKey,
-				"error":   err.Error(),
-			})
-		}
-	}
-
-	// 3. Log de invalidação
-	uc.logger.Debug("Cache invalidation completed", map[string]interface{}{
+	log.Debug("Cache invalidation completed", map[string]interface{}{
 		"user_id": userID,
 		"caches":  []string{"current_position", "history"},
 	})
 }
 
-// publishPositionChangedEvent publica evento quando posição do usuário muda
-func (uc *SaveUserPositionUseCase) publishPositionChangedEvent(
-	ctx context.Context,
+// invalidateSectorCaches remove a lista de usuários em cache dos setores de destino e de origem
+// Posições salvas apenas no histórico não mudam a posição atual e não passam por aqui
+func (uc *SaveUserPositionUseCase) invalidateSectorCaches(ctx context.Context, position, previousPosition *entity.Position) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	sectorIDs := []string{position.Sector().ID()}
+	if previousPosition != nil && previousPosition.Sector().ID() != sectorIDs[0] {
+		sectorIDs = append(sectorIDs, previousPosition.Sector().ID())
+	}
+
+	for _, sectorID := range sectorIDs {
+		if err := uc.cache.Delete(ctx, fmt.Sprintf(sectorUsersCacheKey, sectorID)); err != nil {
+			log.Error("Failed to invalidate sector users cache", map[string]interface{}{
+				"sector_id": sectorID,
+				"error":     err.Error(),
+			})
+		}
+	}
+}
+
+// newPositionChangedEvent monta o evento de mudança de posição em relação à posição anterior
+func (uc *SaveUserPositionUseCase) newPositionChangedEvent(
+	requestID string,
 	user *entity.User,
 	newPosition *entity.Position,
 	previousPosition *entity.Position,
-) error {
+) *events.Event {
 	// Preparar dados do evento
 	var previousLat, previousLng float64
 	var previousSector string
@@ -213,6 +417,9 @@ func (uc *SaveUserPositionUseCase) publishPositionChangedEvent(
 		)
 	}
 
+	// Velocidade desde a posição anterior, para analytics e detecção de saltos
+	speedKmh := newPosition.SpeedKmhFrom(previousPosition)
+
 	// Criar dados do evento
 	positionID := newPosition.ID()
 	userID := user.ID()
@@ -226,15 +433,250 @@ func (uc *SaveUserPositionUseCase) publishPositionChangedEvent(
 		PreviousSector: previousSector,
 		NewSector:      newPosition.Sector().ID(),
 		DistanceMoved:  distanceMoved,
+		SpeedKmh:       speedKmh,
+		Suspicious:     uc.isImplausibleSpeed(speedKmh),
 	}
 
 	// Criar evento
 	event := events.NewPositionChangedEvent(
 		userID.String(),
-		"default-event", // TODO: pegar do contexto do evento
+		eventContextID(requestID),
 		eventData,
 	)
+	event.Metadata.RequestID = requestID
+
+	return event
+}
 
-	// Publicar evento
-	return uc.eventPublisher.PublishPositionChanged(ctx, event)
+// publishSectorChangedEvents publica UserLeftSector (setor anterior) e UserEnteredSector (novo setor)
+// quando a nova posição está em um setor diferente da posição anterior; no mesmo setor,
+// publica UserMovedInSector se habilitado
+func (uc *SaveUserPositionUseCase) publishSectorChangedEvents(
+	ctx context.Context,
+	requestID string,
+	user *entity.User,
+	newPosition *entity.Position,
+	previousPosition *entity.Position,
+) error {
+	if previousPosition == nil {
+		return nil
+	}
+	if newPosition.IsInSameSector(previousPosition) {
+		return uc.publishIntraSectorMovementEvent(ctx, requestID, user, newPosition, previousPosition)
+	}
+
+	userID := user.ID()
+
+	// 1. Saída do setor anterior
+	previousSector := previousPosition.Sector()
+	leftEvent := events.NewSectorChangedEvent(
+		userID.String(),
+		eventContextID(requestID),
+		events.EventTypeUserLeftSector,
+		events.SectorChangedData{
+			SectorX:   previousSector.X(),
+			SectorY:   previousSector.Y(),
+			SectorID:  previousSector.ID(),
+			Latitude:  previousPosition.Latitude(),
+			Longitude: previousPosition.Longitude(),
+		},
+	)
+	leftEvent.Metadata.RequestID = requestID
+	if err := uc.eventPublisher.PublishSectorChanged(ctx, leftEvent); err != nil {
+		return fmt.Errorf("failed to publish user left sector event: %w", err)
+	}
+
+	// 2. Entrada no novo setor, com a contagem atual de usuários (já inclui esta posição)
+	newSector := newPosition.Sector()
+	sectorPositions, err := uc.positionRepo.FindInSector(ctx, newSector)
+	if err != nil {
+		return fmt.Errorf("failed to count users in sector: %w", err)
+	}
+
+	enteredEvent := events.NewSectorChangedEvent(
+		userID.String(),
+		eventContextID(requestID),
+		events.EventTypeUserEnteredSector,
+		events.SectorChangedData{
+			SectorX:       newSector.X(),
+			SectorY:       newSector.Y(),
+			SectorID:      newSector.ID(),
+			Latitude:      newPosition.Latitude(),
+			Longitude:     newPosition.Longitude(),
+			UsersInSector: len(sectorPositions),
+		},
+	)
+	enteredEvent.Metadata.RequestID = requestID
+	if err := uc.eventPublisher.PublishSectorChanged(ctx, enteredEvent); err != nil {
+		return fmt.Errorf("failed to publish user entered sector event: %w", err)
+	}
+
+	return nil
+}
+
+// publishIntraSectorMovementEvent publica o movimento dentro do setor, quando habilitado e houve deslocamento
+func (uc *SaveUserPositionUseCase) publishIntraSectorMovementEvent(
+	ctx context.Context,
+	requestID string,
+	user *entity.User,
+	newPosition *entity.Position,
+	previousPosition *entity.Position,
+) error {
+	if !uc.intraSectorMovementEvents {
+		return nil
+	}
+
+	distance := previousPosition.Coordinate().DistanceTo(newPosition.Coordinate())
+	if distance == 0 {
+		return nil
+	}
+
+	userID := user.ID()
+	sector := newPosition.Sector()
+	event := events.NewSectorMovementEvent(
+		userID.String(),
+		eventContextID(requestID),
+		events.SectorMovementData{
+			SectorX:       sector.X(),
+			SectorY:       sector.Y(),
+			SectorID:      sector.ID(),
+			Latitude:      newPosition.Latitude(),
+			Longitude:     newPosition.Longitude(),
+			DistanceMoved: distance,
+		},
+	)
+	event.Metadata.RequestID = requestID
+	if err := uc.eventPublisher.PublishSectorChanged(ctx, event); err != nil {
+		return fmt.Errorf("failed to publish user moved in sector event: %w", err)
+	}
+
+	return nil
+}
+
+// eventContextID retorna o ID de contexto do evento (ID de correlação da requisição)
+// Sem requisição de origem (ex: chamadas internas), usa o contexto padrão
+func eventContextID(requestID string) string {
+	if requestID == "" {
+		return "default-event"
+	}
+	return requestID
+}
+
+// publishProximityEvents publica proximity.user_nearby para cada usuário que entrou no raio
+// configurado desde a última avaliação; o par fica registrado no cache para não repetir o alerta
+func (uc *SaveUserPositionUseCase) publishProximityEvents(
+	ctx context.Context,
+	requestID string,
+	user *entity.User,
+	position *entity.Position,
+) error {
+	if uc.proximityAlertRadiusM <= 0 {
+		return nil
+	}
+
+	userID := user.ID()
+	nearby, err := uc.positionRepo.FindNearby(ctx, position.Coordinate(), uc.proximityAlertRadiusM, maxProximityCandidates+1)
+	if err != nil {
+		return fmt.Errorf("failed to find nearby users: %w", err)
+	}
+
+	// Cache miss = ninguém estava próximo
+	wasNear := uc.proximityState(ctx, userID.String())
+
+	current := make([]string, 0, len(nearby))
+	for _, other := range nearby {
+		otherID := other.Position.UserID()
+		if otherID.Equals(&userID) {
+			continue
+		}
+		current = append(current, otherID.String())
+		if wasNear[otherID.String()] {
+			continue
+		}
+
+		if err := uc.publishUserNearbyEvent(ctx, requestID, userID.String(), position, other); err != nil {
+			return err
+		}
+
+		// Registrar o par também do lado do outro usuário, para não alertar de novo quando ele se mover
+		uc.markNear(ctx, otherID.String(), userID.String())
+	}
+
+	uc.saveProximityState(ctx, userID.String(), current)
+	return nil
+}
+
+// publishUserNearbyEvent publica o evento de um par que acabou de ficar próximo
+func (uc *SaveUserPositionUseCase) publishUserNearbyEvent(
+	ctx context.Context,
+	requestID string,
+	userID string,
+	position *entity.Position,
+	other *repository.NearbyPosition,
+) error {
+	otherID := other.Position.UserID()
+
+	// Nome é informativo; falha na busca não impede o evento
+	var nearUserName string
+	if nearUser, err := uc.userRepo.FindByID(ctx, otherID); err == nil {
+		nearUserName = nearUser.Name()
+	}
+
+	event := events.NewProximityEvent(
+		userID,
+		eventContextID(requestID),
+		events.ProximityData{
+			NearUserID:   otherID.String(),
+			NearUserName: nearUserName,
+			Distance:     other.Distance(position.Coordinate()),
+			MaxDistance:  uc.proximityAlertRadiusM,
+			IsEntering:   true,
+		},
+	)
+	event.Metadata.RequestID = requestID
+
+	if err := uc.eventPublisher.PublishProximity(ctx, event); err != nil {
+		return fmt.Errorf("failed to publish user nearby event: %w", err)
+	}
+	return nil
+}
+
+// proximityState retorna os usuários que estavam próximos de userID na última avaliação
+func (uc *SaveUserPositionUseCase) proximityState(ctx context.Context, userID string) map[string]bool {
+	var previous []string
+	_ = uc.cache.Get(ctx, fmt.Sprintf(proximityStateCacheKey, userID), &previous)
+
+	state := make(map[string]bool, len(previous))
+	for _, id := range previous {
+		state[id] = true
+	}
+	return state
+}
+
+// saveProximityState grava os usuários atualmente próximos de userID
+func (uc *SaveUserPositionUseCase) saveProximityState(ctx context.Context, userID string, nearUserIDs []string) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	key := fmt.Sprintf(proximityStateCacheKey, userID)
+	if err := uc.cache.Set(ctx, key, nearUserIDs, proximityStateTTL); err != nil {
+		log.Debug("Failed to save proximity state", map[string]interface{}{
+			"user_id": userID,
+			"key":     key,
+			"error":   err.Error(),
+		})
+	}
+}
+
+// markNear adiciona nearUserID ao estado de proximidade de userID
+func (uc *SaveUserPositionUseCase) markNear(ctx context.Context, userID, nearUserID string) {
+	state := uc.proximityState(ctx, userID)
+	if state[nearUserID] {
+		return
+	}
+
+	nearUserIDs := make([]string, 0, len(state)+1)
+	for id := range state {
+		nearUserIDs = append(nearUserIDs, id)
+	}
+	uc.saveProximityState(ctx, userID, append(nearUserIDs, nearUserID))
 }
@@ -11,8 +11,14 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/events"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/service"
+	"github.com/vitao/geolocation-tracker/internal/domain/valueobject"
 	"github.com/vitao/geolocation-tracker/internal/usecase"
 	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+	"github.com/vitao/geolocation-tracker/pkg/metrics"
 )
 
 // SaveUserPositionUseCaseTestSuite define a suite de testes para SaveUserPositionUseCase
@@ -40,7 +46,10 @@ func (suite *SaveUserPositionUseCaseTestSuite) SetupTest() {
 		suite.positionRepo,
 		suite.eventPublisher,
 		suite.cache,
+		service.NewNoopPositionValidator(),
+		metrics.NewNoopCollector(),
 		suite.logger,
+		&config.Config{},
 	)
 	suite.ctx = context.Background()
 
@@ -62,9 +71,8 @@ func (suite *SaveUserPositionUseCaseTestSuite) TearDownTest() {
 // addCacheInvalidationMocks adiciona mocks de invalidação de cache para testes de escrita
 func (suite *SaveUserPositionUseCaseTestSuite) addCacheInvalidationMocks(userID string) {
 	// Mocks para invalidação de cache (podem falhar sem quebrar o teste)
-	suite.cache.On("Delete", mock.Anything, mock.MatchedBy(func(key string) bool {
-		return strings.Contains(key, userID)
-	})).Return(nil).Maybe()
+	suite.cache.On("InvalidateUserCaches", mock.Anything, userID).Return(nil).Maybe()
+	suite.cache.On("Delete", mock.Anything, mock.MatchedBy(isSectorUsersCacheKey)).Return(nil).Maybe()
 
 	// Mock para log de debug da invalidação do cache
 	suite.logger.On("Debug", "Cache invalidation completed", mock.Anything).Return().Maybe()
@@ -96,11 +104,7 @@ func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_Success() {
 		Return(nil, errors.New("no previous position")).Maybe()
 
 	// Mock: salvar posição com sucesso
-	suite.positionRepo.On("Save", mock.Anything, mock.AnythingOfType("*entity.Position")).
-		Return(nil)
-
-	// Mock: publicar evento com sucesso
-	suite.eventPublisher.On("PublishPositionChanged", mock.Anything, mock.AnythingOfType("*events.Event")).
+	suite.positionRepo.On("SaveWithEvents", mock.Anything, mock.AnythingOfType("*entity.Position"), mock.Anything).
 		Return(nil)
 
 	// Mock: logs de sucesso
@@ -116,6 +120,7 @@ func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_Success() {
 	assert.NotEmpty(suite.T(), response.PositionID)
 	assert.NotEmpty(suite.T(), response.SectorID)
 	assert.Equal(suite.T(), "Position saved successfully", response.Message)
+	suite.cache.AssertCalled(suite.T(), "InvalidateUserCaches", mock.Anything, "user123")
 }
 
 // TestSaveUserPosition_UserNotFound testa quando usuário não existe
@@ -133,7 +138,7 @@ func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_UserNotFound
 
 	// Mock: usuário não existe
 	suite.userRepo.On("FindByID", mock.Anything, *userID).
-		Return(nil, errors.New("user not found"))
+		Return(nil, entity.ErrUserIDNotFound)
 
 	// Mock: log de erro
 	suite.logger.On("Error", "User not found", mock.Anything).
@@ -143,9 +148,8 @@ func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_UserNotFound
 	response, err := suite.useCase.Execute(suite.ctx, request)
 
 	// Assert
-	assert.Error(suite.T(), err)
+	assert.ErrorIs(suite.T(), err, usecase.ErrUserNotFound)
 	assert.Nil(suite.T(), response)
-	assert.Contains(suite.T(), err.Error(), "user not found")
 }
 
 // TestSaveUserPosition_InvalidCoordinates testa com coordenadas inválidas
@@ -214,6 +218,41 @@ func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_InvalidCoord
 	}
 }
 
+// TestSaveUserPosition_InvalidClientTimestamp testa que timestamps do cliente no futuro ou antigos demais são erro de entrada
+func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_InvalidClientTimestamp() {
+	testCases := []struct {
+		name      string
+		timestamp time.Time
+		domainErr error
+	}{
+		{name: "futuro", timestamp: time.Now().Add(time.Hour), domainErr: entity.ErrFuturePosition},
+		{name: "antigo demais", timestamp: time.Now().Add(-(entity.DefaultMaxPositionAgeHours + 1) * time.Hour), domainErr: entity.ErrPositionTooOld},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.SetupTest()
+
+			userID, err := entity.NewUserID("user123")
+			suite.Require().NoError(err)
+			suite.userRepo.On("FindByID", mock.Anything, *userID).Return(suite.validUser, nil)
+			suite.logger.On("Error", "Failed to create position", mock.Anything).Return()
+
+			response, err := suite.useCase.Execute(suite.ctx, usecase.SaveUserPositionRequest{
+				UserID:    "user123",
+				Latitude:  -23.550520,
+				Longitude: -46.633309,
+				Timestamp: tc.timestamp,
+			})
+
+			assert.Nil(suite.T(), response)
+			assert.ErrorIs(suite.T(), err, usecase.ErrInvalidInput)
+			assert.ErrorIs(suite.T(), err, tc.domainErr)
+			suite.positionRepo.AssertNotCalled(suite.T(), "SaveWithEvents", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
 // TestSaveUserPosition_RepositoryError testa erro ao salvar no repositório
 func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_RepositoryError() {
 	// Arrange
@@ -238,7 +277,7 @@ func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_RepositoryEr
 		Return(nil, errors.New("no previous position"))
 
 	// Mock: erro ao salvar posição
-	suite.positionRepo.On("Save", mock.Anything, mock.AnythingOfType("*entity.Position")).
+	suite.positionRepo.On("SaveWithEvents", mock.Anything, mock.AnythingOfType("*entity.Position"), mock.Anything).
 		Return(repositoryError)
 
 	// Mock: log de erro
@@ -255,8 +294,9 @@ func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_RepositoryEr
 	assert.Contains(suite.T(), err.Error(), "database connection failed")
 }
 
-// TestSaveUserPosition_EventPublishError testa erro ao publicar evento
-func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_EventPublishError() {
+// TestSaveUserPosition_PositionEventWrittenToOutbox testa que o evento de posição vai para a outbox
+// na mesma gravação da posição, sem publicação direta no Redis
+func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_PositionEventWrittenToOutbox() {
 	// Arrange
 	request := usecase.SaveUserPositionRequest{
 		UserID:    "user123",
@@ -268,42 +308,756 @@ func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_EventPublish
 	userID, err := entity.NewUserID("user123")
 	suite.Require().NoError(err)
 
-	eventError := errors.New("event publisher failed")
+	suite.addCacheInvalidationMocks(request.UserID)
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Return(suite.validUser, nil)
+	suite.positionRepo.On("FindCurrentByUserID", mock.Anything, *userID).
+		Return(nil, errors.New("no previous position"))
+
+	var saved *entity.Position
+	var outbox []*repository.OutboxEntry
+	suite.positionRepo.On("SaveWithEvents", mock.Anything, mock.AnythingOfType("*entity.Position"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			saved = args.Get(1).(*entity.Position)
+			outbox = args.Get(2).([]*repository.OutboxEntry)
+		}).
+		Return(nil)
+	suite.logger.On("Info", "Position saved successfully", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(outbox, 1)
+	assert.Equal(suite.T(), events.StreamPositionEvents, outbox[0].Stream)
+	assert.Equal(suite.T(), events.EventTypePositionChanged, outbox[0].Event.Type)
+	assert.Equal(suite.T(), response.PositionID, outbox[0].Event.Data["position_id"])
+	assert.Equal(suite.T(), saved.Sector().ID(), outbox[0].Event.Data["new_sector"])
+	suite.eventPublisher.AssertNotCalled(suite.T(), "PublishPositionChanged", mock.Anything, mock.Anything)
+}
+
+// TestSaveUserPosition_EventLostAfterRetries testa o log quando o publisher esgota as tentativas
+// Eventos de setor continuam publicados diretamente e não passam pela outbox
+func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_EventLostAfterRetries() {
+	// Arrange
+	request := usecase.SaveUserPositionRequest{
+		UserID:    "user123",
+		Latitude:  -23.550520,
+		Longitude: -46.633309,
+		Timestamp: time.Now(),
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+
+	// Posição anterior em outro setor: a nova posição gera eventos de saída e entrada
+	previousPosition, err := entity.NewPosition("pos-previous", *userID, -22.906847, -43.172897, time.Now().Add(-time.Hour))
+	suite.Require().NoError(err)
+
+	publishErr := &events.PublishError{
+		Stream:    events.StreamSectorEvents,
+		EventType: events.EventTypeUserLeftSector,
+		Attempts:  3,
+		Err:       errors.New("connection reset by peer"),
+	}
+
+	suite.addCacheInvalidationMocks(request.UserID)
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Return(suite.validUser, nil)
+	suite.positionRepo.On("FindCurrentByUserID", mock.Anything, *userID).
+		Return(previousPosition, nil)
+	suite.positionRepo.On("SaveWithEvents", mock.Anything, mock.AnythingOfType("*entity.Position"), mock.Anything).
+		Return(nil)
+	suite.eventPublisher.On("PublishSectorChanged", mock.Anything, mock.AnythingOfType("*events.Event")).
+		Return(publishErr)
+
+	// O log identifica o evento perdido e quantas tentativas foram feitas
+	suite.logger.On("Info", "Position saved successfully", mock.Anything).
+		Return()
+	suite.logger.On("Error", "Failed to publish sector changed events",
+		"position_id", mock.Anything, "user_id", mock.Anything,
+		"attempts", 3, "event_lost", true,
+		"error", mock.MatchedBy(func(msg string) bool {
+			return strings.Contains(msg, publishErr.Error())
+		})).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert: a posição continua salva
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), response)
+}
+
+// TestSaveUserPosition_BackdatedPositionKeepsCurrent testa posição fora de ordem
+func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_BackdatedPositionKeepsCurrent() {
+	// Arrange
+	now := time.Now()
+	request := usecase.SaveUserPositionRequest{
+		UserID:    "user123",
+		Latitude:  -23.551000,
+		Longitude: -46.634000,
+		Timestamp: now.Add(-2 * time.Hour), // Anterior à posição atual
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+
+	currentPosition, err := entity.NewPosition("pos-current", *userID, -23.550520, -46.633309, now.Add(-time.Hour))
+	suite.Require().NoError(err)
 
-	// Adicionar mocks de invalidação de cache
 	suite.addCacheInvalidationMocks(request.UserID)
 
-	// Mock: usuário existe
 	suite.userRepo.On("FindByID", mock.Anything, *userID).
 		Return(suite.validUser, nil)
+	suite.positionRepo.On("FindCurrentByUserID", mock.Anything, *userID).
+		Return(currentPosition, nil)
 
-	// Mock: buscar posição anterior (pode não existir)
+	// Mock: posição vai apenas para o histórico
+	suite.positionRepo.On("SaveHistory", mock.Anything, mock.MatchedBy(func(p *entity.Position) bool {
+		return p.RecordedAt().Time().Equal(request.Timestamp)
+	})).Return(nil)
+
+	suite.logger.On("Info", "Out-of-order position saved to history", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), response)
+	assert.NotEmpty(suite.T(), response.PositionID)
+	assert.Contains(suite.T(), response.Message, "history")
+	suite.positionRepo.AssertNotCalled(suite.T(), "SaveWithEvents", mock.Anything, mock.Anything, mock.Anything)
+	suite.eventPublisher.AssertNotCalled(suite.T(), "PublishPositionChanged", mock.Anything, mock.Anything)
+}
+
+// TestSaveUserPosition_BackdatedPositionUpdatesCurrentWhenEnabled testa a configuração que permite sobrescrever
+func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_BackdatedPositionUpdatesCurrentWhenEnabled() {
+	// Arrange
+	cfg := &config.Config{Positions: config.PositionsConfig{UpdateCurrentOnOutOfOrder: true}}
+	suite.useCase = usecase.NewSaveUserPositionUseCase(
+		suite.userRepo,
+		suite.positionRepo,
+		suite.eventPublisher,
+		suite.cache,
+		service.NewNoopPositionValidator(),
+		metrics.NewNoopCollector(),
+		suite.logger,
+		cfg,
+	)
+
+	now := time.Now()
+	request := usecase.SaveUserPositionRequest{
+		UserID:    "user123",
+		Latitude:  -23.550520, // Mesmo setor da posição atual
+		Longitude: -46.633309,
+		Timestamp: now.Add(-2 * time.Hour),
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+
+	currentPosition, err := entity.NewPosition("pos-current", *userID, -23.550520, -46.633309, now.Add(-time.Hour))
+	suite.Require().NoError(err)
+
+	suite.addCacheInvalidationMocks(request.UserID)
+
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Return(suite.validUser, nil)
+	suite.positionRepo.On("FindCurrentByUserID", mock.Anything, *userID).
+		Return(currentPosition, nil)
+	suite.positionRepo.On("SaveWithEvents", mock.Anything, mock.AnythingOfType("*entity.Position"), mock.Anything).
+		Return(nil)
+	suite.logger.On("Info", "Position saved successfully", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Position saved successfully", response.Message)
+	suite.positionRepo.AssertNotCalled(suite.T(), "SaveHistory", mock.Anything, mock.Anything)
+}
+
+// TestSaveUserPosition_SectorChangePublishesEvents testa eventos de saída/entrada de setor
+func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_SectorChangePublishesEvents() {
+	// Arrange
+	now := time.Now()
+	request := usecase.SaveUserPositionRequest{
+		UserID:    "user123",
+		Latitude:  -23.550520,
+		Longitude: -46.633309,
+		Timestamp: now,
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+
+	// Posição anterior ~1km ao norte (outro setor)
+	previousPosition, err := entity.NewPosition("pos-previous", *userID, -23.541520, -46.633309, now.Add(-time.Minute))
+	suite.Require().NoError(err)
+
+	otherUserID, err := entity.NewUserID("user456")
+	suite.Require().NoError(err)
+	otherPosition, err := entity.NewPosition("pos-other", *otherUserID, -23.550520, -46.633309, now.Add(-time.Minute))
+	suite.Require().NoError(err)
+
+	suite.addCacheInvalidationMocks(request.UserID)
+
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Return(suite.validUser, nil)
+	suite.positionRepo.On("FindCurrentByUserID", mock.Anything, *userID).
+		Return(previousPosition, nil)
+	suite.positionRepo.On("SaveWithEvents", mock.Anything, mock.AnythingOfType("*entity.Position"), mock.Anything).
+		Return(nil)
+
+	// Mock: contagem de usuários no novo setor (inclui a posição recém salva)
+	suite.positionRepo.On("FindInSector", mock.Anything, mock.Anything).
+		Return([]*entity.Position{otherPosition, previousPosition}, nil)
+
+	var published []*events.Event
+	suite.eventPublisher.On("PublishSectorChanged", mock.Anything, mock.AnythingOfType("*events.Event")).
+		Run(func(args mock.Arguments) {
+			published = append(published, args.Get(1).(*events.Event))
+		}).
+		Return(nil).Twice()
+
+	suite.logger.On("Info", "Position saved successfully", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), response)
+	suite.Require().Len(published, 2)
+
+	// Caches de usuários por setor invalidados na origem e no destino
+	suite.cache.AssertCalled(suite.T(), "Delete", mock.Anything, "sector:"+previousPosition.Sector().ID())
+	suite.cache.AssertCalled(suite.T(), "Delete", mock.Anything, "sector:"+response.SectorID)
+
+	left := published[0]
+	assert.Equal(suite.T(), events.EventTypeUserLeftSector, left.Type)
+	assert.Equal(suite.T(), previousPosition.Sector().ID(), left.Data["sector_id"])
+
+	entered := published[1]
+	assert.Equal(suite.T(), events.EventTypeUserEnteredSector, entered.Type)
+	assert.Equal(suite.T(), response.SectorID, entered.Data["sector_id"])
+	assert.Equal(suite.T(), 2, entered.Data["users_in_sector"])
+}
+
+// TestSaveUserPosition_SameSectorSkipsSectorEvents testa que não há eventos de setor sem mudança de setor
+func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_SameSectorSkipsSectorEvents() {
+	// Arrange
+	now := time.Now()
+	request := usecase.SaveUserPositionRequest{
+		UserID:    "user123",
+		Latitude:  -23.550520,
+		Longitude: -46.633309,
+		Timestamp: now,
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+
+	previousPosition, err := entity.NewPosition("pos-previous", *userID, -23.550530, -46.633319, now.Add(-time.Minute))
+	suite.Require().NoError(err)
+
+	suite.addCacheInvalidationMocks(request.UserID)
+
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Return(suite.validUser, nil)
+	suite.positionRepo.On("FindCurrentByUserID", mock.Anything, *userID).
+		Return(previousPosition, nil)
+	suite.positionRepo.On("SaveWithEvents", mock.Anything, mock.AnythingOfType("*entity.Position"), mock.Anything).
+		Return(nil)
+	suite.logger.On("Info", "Position saved successfully", mock.Anything).
+		Return()
+
+	// Act
+	_, err = suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	suite.eventPublisher.AssertNotCalled(suite.T(), "PublishSectorChanged", mock.Anything, mock.Anything)
+	suite.positionRepo.AssertNotCalled(suite.T(), "FindInSector", mock.Anything, mock.Anything)
+	suite.cache.AssertNumberOfCalls(suite.T(), "Delete", 1) // Apenas o setor atual
+}
+
+// useConfig recria o use case com a configuração de posições informada
+func (suite *SaveUserPositionUseCaseTestSuite) useConfig(positions config.PositionsConfig) {
+	suite.useCase = usecase.NewSaveUserPositionUseCase(
+		suite.userRepo,
+		suite.positionRepo,
+		suite.eventPublisher,
+		suite.cache,
+		service.NewNoopPositionValidator(),
+		metrics.NewNoopCollector(),
+		suite.logger,
+		&config.Config{Positions: positions},
+	)
+}
+
+// enableIntraSectorMovementEvents recria o use case com o evento de movimento no setor habilitado
+func (suite *SaveUserPositionUseCaseTestSuite) enableIntraSectorMovementEvents() {
+	suite.useConfig(config.PositionsConfig{IntraSectorMovementEvents: true})
+}
+
+// TestSaveUserPosition_IntraSectorMovementWhenEnabled testa o evento de movimento dentro do setor
+func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_IntraSectorMovementWhenEnabled() {
+	// Arrange
+	suite.enableIntraSectorMovementEvents()
+
+	now := time.Now()
+	request := usecase.SaveUserPositionRequest{
+		UserID:    "user123",
+		Latitude:  -23.550520,
+		Longitude: -46.633309,
+		Timestamp: now,
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+
+	// Alguns metros de distância, mesmo setor
+	previousPosition, err := entity.NewPosition("pos-previous", *userID, -23.550530, -46.633319, now.Add(-time.Minute))
+	suite.Require().NoError(err)
+
+	suite.addCacheInvalidationMocks(request.UserID)
+
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Return(suite.validUser, nil)
+	suite.positionRepo.On("FindCurrentByUserID", mock.Anything, *userID).
+		Return(previousPosition, nil)
+	suite.positionRepo.On("SaveWithEvents", mock.Anything, mock.AnythingOfType("*entity.Position"), mock.Anything).
+		Return(nil)
+
+	var published []*events.Event
+	suite.eventPublisher.On("PublishSectorChanged", mock.Anything, mock.AnythingOfType("*events.Event")).
+		Run(func(args mock.Arguments) {
+			published = append(published, args.Get(1).(*events.Event))
+		}).
+		Return(nil).Once()
+
+	suite.logger.On("Info", "Position saved successfully", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(published, 1)
+
+	moved := published[0]
+	assert.Equal(suite.T(), events.EventTypeUserMovedInSector, moved.Type)
+	assert.Equal(suite.T(), response.SectorID, moved.Data["sector_id"])
+	expected := valueobject.CalculateDistance(previousPosition.Latitude(), previousPosition.Longitude(), request.Latitude, request.Longitude)
+	assert.InDelta(suite.T(), expected, moved.Data["distance_moved"], 0.001)
+	suite.positionRepo.AssertNotCalled(suite.T(), "FindInSector", mock.Anything, mock.Anything)
+}
+
+// TestSaveUserPosition_SectorChangeWithIntraSectorEnabled testa que a troca de setor continua publicando saída/entrada
+func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_SectorChangeWithIntraSectorEnabled() {
+	// Arrange
+	suite.enableIntraSectorMovementEvents()
+
+	now := time.Now()
+	request := usecase.SaveUserPositionRequest{
+		UserID:    "user123",
+		Latitude:  -23.550520,
+		Longitude: -46.633309,
+		Timestamp: now,
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+
+	// Posição anterior ~1km ao norte (outro setor)
+	previousPosition, err := entity.NewPosition("pos-previous", *userID, -23.541520, -46.633309, now.Add(-time.Minute))
+	suite.Require().NoError(err)
+
+	suite.addCacheInvalidationMocks(request.UserID)
+
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Return(suite.validUser, nil)
+	suite.positionRepo.On("FindCurrentByUserID", mock.Anything, *userID).
+		Return(previousPosition, nil)
+	suite.positionRepo.On("SaveWithEvents", mock.Anything, mock.AnythingOfType("*entity.Position"), mock.Anything).
+		Return(nil)
+	suite.positionRepo.On("FindInSector", mock.Anything, mock.Anything).
+		Return([]*entity.Position{}, nil)
+
+	var types []events.EventType
+	suite.eventPublisher.On("PublishSectorChanged", mock.Anything, mock.AnythingOfType("*events.Event")).
+		Run(func(args mock.Arguments) {
+			types = append(types, args.Get(1).(*events.Event).Type)
+		}).
+		Return(nil).Twice()
+
+	suite.logger.On("Info", "Position saved successfully", mock.Anything).
+		Return()
+
+	// Act
+	_, err = suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), []events.EventType{events.EventTypeUserLeftSector, events.EventTypeUserEnteredSector}, types)
+}
+
+// TestSaveUserPosition_ProximityAlertsOnlyNewPairs testa que apenas usuários que acabaram de ficar próximos geram alerta
+func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_ProximityAlertsOnlyNewPairs() {
+	// Arrange
+	suite.useConfig(config.PositionsConfig{ProximityAlertRadiusM: 100})
+
+	now := time.Now()
+	request := usecase.SaveUserPositionRequest{
+		UserID:    "user123",
+		Latitude:  -23.550520,
+		Longitude: -46.633309,
+		Timestamp: now,
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	newcomerID, err := entity.NewUserID("user456")
+	suite.Require().NoError(err)
+	alreadyNearID, err := entity.NewUserID("user789")
+	suite.Require().NoError(err)
+
+	// A própria posição aparece na busca e deve ser ignorada
+	self, err := entity.NewPosition("pos-self", *userID, request.Latitude, request.Longitude, now)
+	suite.Require().NoError(err)
+	newcomer, err := entity.NewPosition("pos-newcomer", *newcomerID, -23.550600, -46.633400, now)
+	suite.Require().NoError(err)
+	alreadyNear, err := entity.NewPosition("pos-already-near", *alreadyNearID, -23.550450, -46.633250, now)
+	suite.Require().NoError(err)
+	newcomerUser, err := entity.NewUser("user456", "Maria Souza", "maria@example.com")
+	suite.Require().NoError(err)
+
+	suite.addCacheInvalidationMocks(request.UserID)
+
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Return(suite.validUser, nil)
+	suite.userRepo.On("FindByID", mock.Anything, *newcomerID).
+		Return(newcomerUser, nil)
 	suite.positionRepo.On("FindCurrentByUserID", mock.Anything, *userID).
 		Return(nil, errors.New("no previous position"))
+	suite.positionRepo.On("SaveWithEvents", mock.Anything, mock.AnythingOfType("*entity.Position"), mock.Anything).
+		Return(nil)
+	suite.positionRepo.On("FindNearby", mock.Anything, mock.Anything, 100.0, mock.Anything).
+		Return(withoutDistance(self, newcomer, alreadyNear), nil)
 
-	// Mock: salvar posição com sucesso
-	suite.positionRepo.On("Save", mock.Anything, mock.AnythingOfType("*entity.Position")).
+	// user789 já estava próximo; user456 ainda não tem estado
+	suite.cache.On("Get", mock.Anything, "proximity:user123", mock.Anything).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*[]string) = []string{"user789"}
+		}).
+		Return(nil)
+	suite.cache.On("Get", mock.Anything, "proximity:user456", mock.Anything).
+		Return(errors.New("cache miss"))
+	suite.cache.On("Set", mock.Anything, "proximity:user456", []string{"user123"}, time.Hour).
+		Return(nil)
+	suite.cache.On("Set", mock.Anything, "proximity:user123", []string{"user456", "user789"}, time.Hour).
 		Return(nil)
 
-	// Mock: erro ao publicar evento
-	suite.eventPublisher.On("PublishPositionChanged", mock.Anything, mock.AnythingOfType("*events.Event")).
-		Return(eventError)
+	var published []*events.Event
+	suite.eventPublisher.On("PublishProximity", mock.Anything, mock.AnythingOfType("*events.Event")).
+		Run(func(args mock.Arguments) {
+			published = append(published, args.Get(1).(*events.Event))
+		}).
+		Return(nil).Once()
 
-	// Mock: logs - sucesso ao salvar e erro no evento
 	suite.logger.On("Info", "Position saved successfully", mock.Anything).
 		Return()
-	suite.logger.On("Error", "Failed to publish position changed event",
-		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+
+	// Act
+	_, err = suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(published, 1)
+
+	nearby := published[0]
+	assert.Equal(suite.T(), events.EventTypeUserNearby, nearby.Type)
+	assert.Equal(suite.T(), "user123", nearby.UserID)
+	assert.Equal(suite.T(), "user456", nearby.Data["near_user_id"])
+	assert.Equal(suite.T(), "Maria Souza", nearby.Data["near_user_name"])
+	assert.Equal(suite.T(), true, nearby.Data["is_entering"])
+	assert.Equal(suite.T(), 100.0, nearby.Data["max_distance"])
+	assert.InDelta(suite.T(), newcomer.DistanceTo(self), nearby.Data["distance"], 0.001)
+}
+
+// TestSaveUserPosition_PropagatesRequestID testa que o ID de correlação chega ao evento publicado
+func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_PropagatesRequestID() {
+	testCases := []struct {
+		name      string
+		requestID string
+		ctx       context.Context
+	}{
+		{
+			name:      "via request",
+			requestID: "req-123",
+			ctx:       context.Background(),
+		},
+		{
+			name: "via context",
+			ctx:  usecase.ContextWithRequestID(context.Background(), "req-123"),
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.SetupTest()
+
+			// Arrange
+			request := usecase.SaveUserPositionRequest{
+				UserID:    "user123",
+				Latitude:  -23.550520,
+				Longitude: -46.633309,
+				Timestamp: time.Now(),
+				RequestID: tc.requestID,
+			}
+
+			userID, err := entity.NewUserID("user123")
+			suite.Require().NoError(err)
+
+			suite.addCacheInvalidationMocks(request.UserID)
+
+			suite.userRepo.On("FindByID", mock.Anything, *userID).
+				Return(suite.validUser, nil)
+			suite.positionRepo.On("FindCurrentByUserID", mock.Anything, *userID).
+				Return(nil, errors.New("no previous position"))
+			// Mock: evento gravado na outbox carrega o ID de correlação
+			suite.positionRepo.On("SaveWithEvents", mock.Anything, mock.AnythingOfType("*entity.Position"),
+				mock.MatchedBy(func(outbox []*repository.OutboxEntry) bool {
+					event := outbox[0].Event
+					return event.EventID == "req-123" && event.Metadata.RequestID == "req-123"
+				})).Return(nil)
+
+			suite.logger.On("Info", "Position saved successfully", mock.Anything).
+				Return()
+
+			// Act
+			_, err = suite.useCase.Execute(tc.ctx, request)
+
+			// Assert
+			assert.NoError(suite.T(), err)
+			suite.positionRepo.AssertExpectations(suite.T())
+		})
+	}
+}
+
+// insideVenue é um predicado fake que aceita apenas coordenadas dentro de um retângulo do evento
+func insideVenue(coord *valueobject.Coordinate) bool {
+	return coord.Latitude() >= -23.56 && coord.Latitude() <= -23.54 &&
+		coord.Longitude() >= -46.64 && coord.Longitude() <= -46.62
+}
+
+// TestSaveUserPosition_RejectedOutsideVenue testa posição recusada pelo validador
+func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_RejectedOutsideVenue() {
+	// Arrange
+	validator := service.NewPredicatePositionValidator(insideVenue, service.ValidationReject, "outside venue")
+	suite.useCase = usecase.NewSaveUserPositionUseCase(
+		suite.userRepo,
+		suite.positionRepo,
+		suite.eventPublisher,
+		suite.cache,
+		validator,
+		metrics.NewNoopCollector(),
+		suite.logger,
+		&config.Config{},
+	)
+
+	request := usecase.SaveUserPositionRequest{
+		UserID:    "user123",
+		Latitude:  -22.906847, // Rio de Janeiro, fora do evento
+		Longitude: -43.172897,
+		Timestamp: time.Now(),
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Return(suite.validUser, nil)
+	suite.logger.On("Warn", "Position rejected by validator", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, service.ErrPositionRejected)
+	assert.Contains(suite.T(), err.Error(), "outside venue")
+	suite.positionRepo.AssertNotCalled(suite.T(), "SaveWithEvents", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestSaveUserPosition_FlaggedOutsideVenue testa posição salva, mas marcada pelo validador
+func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_FlaggedOutsideVenue() {
+	// Arrange
+	validator := service.NewPredicatePositionValidator(insideVenue, service.ValidationFlag, "outside venue")
+	suite.useCase = usecase.NewSaveUserPositionUseCase(
+		suite.userRepo,
+		suite.positionRepo,
+		suite.eventPublisher,
+		suite.cache,
+		validator,
+		metrics.NewNoopCollector(),
+		suite.logger,
+		&config.Config{},
+	)
+
+	request := usecase.SaveUserPositionRequest{
+		UserID:    "user123",
+		Latitude:  -22.906847,
+		Longitude: -43.172897,
+		Timestamp: time.Now(),
+	}
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+
+	suite.addCacheInvalidationMocks(request.UserID)
+
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Return(suite.validUser, nil)
+	suite.positionRepo.On("FindCurrentByUserID", mock.Anything, *userID).
+		Return(nil, errors.New("no previous position"))
+	suite.positionRepo.On("SaveWithEvents", mock.Anything, mock.AnythingOfType("*entity.Position"), mock.Anything).
+		Return(nil)
+	suite.logger.On("Warn", "Position flagged by validator", mock.Anything).
+		Return()
+	suite.logger.On("Info", "Position saved successfully", mock.Anything).
 		Return()
 
 	// Act
 	response, err := suite.useCase.Execute(suite.ctx, request)
 
 	// Assert
-	// NOTE: Dependendo da implementação, erro no evento pode ou não falhar todo o processo
-	// Assumindo que position é salva mesmo com erro no evento
 	assert.NoError(suite.T(), err)
-	assert.NotNil(suite.T(), response)
+	assert.True(suite.T(), response.Flagged)
+	assert.Equal(suite.T(), "outside venue", response.FlagReason)
+}
+
+// expectTeleport prepara um salto de São Paulo (1 minuto atrás) para o Rio de Janeiro (~360km)
+func (suite *SaveUserPositionUseCaseTestSuite) expectTeleport() usecase.SaveUserPositionRequest {
+	now := time.Now()
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	previousPosition, err := entity.NewPosition("pos-previous", *userID, -23.550520, -46.633309, now.Add(-time.Minute))
+	suite.Require().NoError(err)
+
+	suite.userRepo.On("FindByID", mock.Anything, *userID).
+		Return(suite.validUser, nil)
+	suite.positionRepo.On("FindCurrentByUserID", mock.Anything, *userID).
+		Return(previousPosition, nil)
+
+	return usecase.SaveUserPositionRequest{
+		UserID:    "user123",
+		Latitude:  -22.906847,
+		Longitude: -43.172897,
+		Timestamp: now,
+	}
+}
+
+// outboxEvent retorna o evento gravado na outbox pela chamada a SaveWithEvents
+func outboxEvent(args mock.Arguments) *events.Event {
+	return args.Get(2).([]*repository.OutboxEntry)[0].Event
+}
+
+// TestSaveUserPosition_ImplausibleJumpFlagged testa que o salto é salvo, marcado e sinalizado no evento
+func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_ImplausibleJumpFlagged() {
+	// Arrange
+	suite.useConfig(config.PositionsConfig{MaxSpeedKmh: 300, ImplausibleSpeedAction: "flag"})
+	request := suite.expectTeleport()
+	suite.addCacheInvalidationMocks(request.UserID)
+
+	var changed *events.Event
+	suite.positionRepo.On("SaveWithEvents", mock.Anything, mock.AnythingOfType("*entity.Position"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			changed = outboxEvent(args)
+		}).
+		Return(nil)
+	suite.positionRepo.On("FindInSector", mock.Anything, mock.Anything).
+		Return([]*entity.Position{}, nil).Maybe()
+	suite.eventPublisher.On("PublishSectorChanged", mock.Anything, mock.AnythingOfType("*events.Event")).
+		Return(nil).Maybe()
+	suite.logger.On("Warn", "Position flagged by speed guard", mock.Anything).
+		Return()
+	suite.logger.On("Info", "Position saved successfully", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.True(suite.T(), response.Flagged)
+	assert.Contains(suite.T(), response.FlagReason, "implausible speed")
+	suite.Require().NotNil(changed)
+	assert.Equal(suite.T(), true, changed.Data["suspicious"])
+	assert.Greater(suite.T(), changed.Data["speed_kmh"], 20000.0) // ~360km em 1 minuto
+}
+
+// TestSaveUserPosition_ImplausibleJumpRejected testa a recusa do salto quando configurada
+func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_ImplausibleJumpRejected() {
+	// Arrange
+	suite.useConfig(config.PositionsConfig{MaxSpeedKmh: 300, ImplausibleSpeedAction: "reject"})
+	request := suite.expectTeleport()
+	suite.logger.On("Warn", "Position rejected by speed guard", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, service.ErrPositionRejected)
+	assert.Contains(suite.T(), err.Error(), "implausible speed")
+	suite.positionRepo.AssertNotCalled(suite.T(), "SaveWithEvents", mock.Anything, mock.Anything, mock.Anything)
+	suite.eventPublisher.AssertNotCalled(suite.T(), "PublishPositionChanged", mock.Anything, mock.Anything)
+}
+
+// TestSaveUserPosition_SpeedInEventWithoutGuard testa a velocidade no evento com a verificação desabilitada
+func (suite *SaveUserPositionUseCaseTestSuite) TestSaveUserPosition_SpeedInEventWithoutGuard() {
+	// Arrange
+	request := suite.expectTeleport()
+	suite.addCacheInvalidationMocks(request.UserID)
+
+	var changed *events.Event
+	suite.positionRepo.On("SaveWithEvents", mock.Anything, mock.AnythingOfType("*entity.Position"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			changed = outboxEvent(args)
+		}).
+		Return(nil)
+	suite.positionRepo.On("FindInSector", mock.Anything, mock.Anything).
+		Return([]*entity.Position{}, nil).Maybe()
+	suite.eventPublisher.On("PublishSectorChanged", mock.Anything, mock.AnythingOfType("*events.Event")).
+		Return(nil).Maybe()
+	suite.logger.On("Info", "Position saved successfully", mock.Anything).
+		Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.False(suite.T(), response.Flagged)
+	suite.Require().NotNil(changed)
+	assert.Equal(suite.T(), false, changed.Data["suspicious"])
+	distance := changed.Data["distance_moved"].(float64)
+	assert.InDelta(suite.T(), distance/60*3.6, changed.Data["speed_kmh"], 1)
 }
 
 // TestSaveUserPosition_InvalidUserID testa com ID de usuário inválido
@@ -337,7 +1091,10 @@ func (suite *SaveUserPositionUseCaseTestSuite) TestNewSaveUserPositionUseCase()
 		suite.positionRepo,
 		suite.eventPublisher,
 		suite.cache,
+		service.NewNoopPositionValidator(),
+		metrics.NewNoopCollector(),
 		suite.logger,
+		&config.Config{},
 	)
 
 	// Assert
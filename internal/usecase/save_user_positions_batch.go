@@ -0,0 +1,355 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/events"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/service"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+	"github.com/vitao/geolocation-tracker/pkg/metrics"
+)
+
+// MaxBatchPositions é o número máximo de posições aceitas em um lote
+const MaxBatchPositions = 500
+
+// ErrEmptyBatch indica um lote sem posições
+var ErrEmptyBatch = errors.New("batch must contain at least one position")
+
+// ErrBatchTooLarge indica um lote acima de MaxBatchPositions
+var ErrBatchTooLarge = fmt.Errorf("batch must contain at most %d positions", MaxBatchPositions)
+
+// BatchPositionItem representa uma posição do lote
+type BatchPositionItem struct {
+	UserID    string    `json:"user_id"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// SaveUserPositionsBatchRequest representa os dados de entrada para salvar posições em lote
+type SaveUserPositionsBatchRequest struct {
+	Positions []BatchPositionItem `json:"positions"`
+	RequestID string              `json:"-"` // ID de correlação (X-Request-ID), propagado aos eventos
+}
+
+// BatchPositionResult representa o resultado de um item do lote
+type BatchPositionResult struct {
+	Index      int    `json:"index"`
+	UserID     string `json:"user_id"`
+	Success    bool   `json:"success"`
+	PositionID string `json:"position_id,omitempty"`
+	SectorID   string `json:"sector_id,omitempty"`
+	Flagged    bool   `json:"flagged,omitempty"`
+	FlagReason string `json:"flag_reason,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// SaveUserPositionsBatchResponse representa a resposta do lote
+type SaveUserPositionsBatchResponse struct {
+	Results []BatchPositionResult `json:"results"`
+	Saved   int                   `json:"saved"`
+	Failed  int                   `json:"failed"`
+	Message string                `json:"message"`
+}
+
+// SaveUserPositionsBatchUseCase salva várias posições em uma única transação
+// Itens inválidos são reportados individualmente sem rejeitar o lote inteiro
+type SaveUserPositionsBatchUseCase struct {
+	userRepo     repository.UserRepository
+	positionRepo repository.PositionRepository
+	validator    service.PositionValidator
+	metrics      metrics.Collector
+	logger       logger.Logger
+
+	// single reaproveita as regras de eventos, cache e ordenação do fluxo unitário
+	single *SaveUserPositionUseCase
+}
+
+// NewSaveUserPositionsBatchUseCase cria uma nova instância do use case
+func NewSaveUserPositionsBatchUseCase(
+	userRepo repository.UserRepository,
+	positionRepo repository.PositionRepository,
+	eventPublisher events.Publisher,
+	cache CacheInterface,
+	validator service.PositionValidator,
+	collector metrics.Collector,
+	logger logger.Logger,
+	cfg *config.Config,
+) *SaveUserPositionsBatchUseCase {
+	return &SaveUserPositionsBatchUseCase{
+		userRepo:     userRepo,
+		positionRepo: positionRepo,
+		validator:    validator,
+		metrics:      collector,
+		logger:       logger,
+		single:       NewSaveUserPositionUseCase(userRepo, positionRepo, eventPublisher, cache, validator, collector, logger, cfg),
+	}
+}
+
+// userBatch agrupa as posições válidas de um usuário no lote
+type userBatch struct {
+	user      *entity.User
+	positions []*entity.Position
+	indexes   []int // Índice de cada posição no lote, para reportar o resultado por item
+	latest    *entity.Position
+}
+
+// Execute executa o use case de salvar posições em lote
+func (uc *SaveUserPositionsBatchUseCase) Execute(ctx context.Context, req SaveUserPositionsBatchRequest) (*SaveUserPositionsBatchResponse, error) {
+	start := time.Now()
+	response, err := uc.execute(ctx, req)
+	uc.metrics.ObserveUseCase("save_user_positions_batch", time.Since(start), err)
+	return response, err
+}
+
+// execute contém o fluxo do use case; Execute apenas o instrumenta
+func (uc *SaveUserPositionsBatchUseCase) execute(ctx context.Context, req SaveUserPositionsBatchRequest) (*SaveUserPositionsBatchResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	if len(req.Positions) == 0 {
+		return nil, ErrEmptyBatch
+	}
+	if len(req.Positions) > MaxBatchPositions {
+		return nil, ErrBatchTooLarge
+	}
+
+	results := make([]BatchPositionResult, len(req.Positions))
+	accepted := make([]*entity.Position, len(req.Positions))
+	batches := make(map[string]*userBatch)
+	userOrder := make([]string, 0)
+	now := time.Now()
+
+	// 1. Validar cada item individualmente
+	for i, item := range req.Positions {
+		results[i] = BatchPositionResult{Index: i, UserID: item.UserID}
+
+		position, validation, err := uc.buildPosition(ctx, item, batches, now)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		positionID := position.ID()
+		results[i].Success = true
+		results[i].PositionID = positionID.String()
+		results[i].SectorID = position.Sector().ID()
+		results[i].Flagged = validation.Flagged
+		results[i].FlagReason = validation.Reason
+
+		batch := batches[item.UserID]
+		if len(batch.positions) == 0 {
+			userOrder = append(userOrder, item.UserID)
+		}
+		batch.positions = append(batch.positions, position)
+		batch.indexes = append(batch.indexes, i)
+		accepted[i] = position
+	}
+
+	// 2. Aplicar a verificação de velocidade e definir a posição atual de cada usuário
+	// (apenas a mais recente do lote)
+	current := make([]*entity.Position, 0, len(userOrder))
+	previous := make(map[string]*entity.Position, len(userOrder))
+	users := make([]string, 0, len(userOrder))
+	for _, userID := range userOrder {
+		batch := batches[userID]
+		previousPosition, _ := uc.positionRepo.FindCurrentByUserID(ctx, batch.user.ID())
+
+		uc.applySpeedGuard(log, batch, previousPosition, results, accepted)
+		if batch.latest == nil {
+			continue // Todas as posições do usuário foram recusadas
+		}
+		users = append(users, userID)
+
+		// Lote mais antigo que a posição atual vai apenas para o histórico
+		if uc.single.isOutOfOrder(batch.latest, previousPosition) {
+			continue
+		}
+		previous[userID] = previousPosition
+		current = append(current, batch.latest)
+	}
+	userOrder = users
+
+	history := make([]*entity.Position, 0, len(req.Positions))
+	for _, position := range accepted {
+		if position != nil {
+			history = append(history, position)
+		}
+	}
+
+	// 3. Salvar tudo e os eventos de mudança de posição em uma única transação (outbox)
+	// Apenas a posição mais recente de cada usuário gera evento
+	requestID := req.RequestID
+	if requestID == "" {
+		requestID = RequestIDFromContext(ctx)
+	}
+	outbox := make([]*repository.OutboxEntry, 0, len(current))
+	for _, userID := range userOrder {
+		batch := batches[userID]
+		if previousPosition, ok := previous[userID]; ok {
+			positionChanged := uc.single.newPositionChangedEvent(requestID, batch.user, batch.latest, previousPosition)
+			outbox = append(outbox, &repository.OutboxEntry{Stream: events.StreamPositionEvents, Event: positionChanged})
+		}
+	}
+
+	if len(history) > 0 {
+		if err := uc.positionRepo.SaveBatch(ctx, history, current, outbox); err != nil {
+			log.Error("Failed to save position batch", map[string]interface{}{
+				"positions": len(history),
+				"error":     err.Error(),
+			})
+			return nil, fmt.Errorf("failed to save position batch: %w", err)
+		}
+	}
+
+	// 4. Publicar os demais eventos apenas para a posição mais recente de cada usuário
+	for _, userID := range userOrder {
+		batch := batches[userID]
+		if previousPosition, ok := previous[userID]; ok {
+			if err := uc.single.publishSectorChangedEvents(ctx, requestID, batch.user, batch.latest, previousPosition); err != nil {
+				log.Error("Failed to publish sector changed events", publishFailureFields(err,
+					"position_id", batch.latest.ID(),
+					"user_id", userID,
+				)...)
+			}
+			uc.single.invalidateSectorCaches(ctx, batch.latest, previousPosition)
+		}
+		if err := uc.single.publishProximityEvents(ctx, requestID, batch.user, batch.latest); err != nil {
+			log.Error("Failed to publish proximity events", publishFailureFields(err,
+				"position_id", batch.latest.ID(),
+				"user_id", userID,
+			)...)
+		}
+
+		uc.single.invalidateRelatedCaches(ctx, userID)
+	}
+
+	saved := len(history)
+	failed := len(req.Positions) - saved
+
+	log.Info("Position batch saved", map[string]interface{}{
+		"total":  len(req.Positions),
+		"saved":  saved,
+		"failed": failed,
+		"users":  len(userOrder),
+	})
+
+	return &SaveUserPositionsBatchResponse{
+		Results: results,
+		Saved:   saved,
+		Failed:  failed,
+		Message: fmt.Sprintf("%d of %d positions saved", saved, len(req.Positions)),
+	}, nil
+}
+
+// applySpeedGuard aplica a verificação de velocidade do fluxo unitário a cada posição do usuário,
+// em ordem cronológica, a partir da posição atual. Posições recusadas saem de accepted e do
+// resultado de sucesso; batch.latest fica com a mais recente das posições restantes
+func (uc *SaveUserPositionsBatchUseCase) applySpeedGuard(
+	log logger.Logger,
+	batch *userBatch,
+	previousPosition *entity.Position,
+	results []BatchPositionResult,
+	accepted []*entity.Position,
+) {
+	order := make([]int, len(batch.positions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return batch.positions[order[a]].RecordedAt().Time().Before(batch.positions[order[b]].RecordedAt().Time())
+	})
+
+	// reference é a última posição aceita; posições fora de ordem não passam pela verificação
+	reference := previousPosition
+	for _, i := range order {
+		position := batch.positions[i]
+		index := batch.indexes[i]
+
+		if !uc.single.isOutOfOrder(position, previousPosition) {
+			if speedKmh := position.SpeedKmhFrom(reference); uc.single.isImplausibleSpeed(speedKmh) {
+				reason := uc.single.implausibleSpeedReason(speedKmh)
+				if uc.single.rejectImplausibleSpeed {
+					log.Warn("Position rejected by speed guard", map[string]interface{}{
+						"user_id":   results[index].UserID,
+						"index":     index,
+						"speed_kmh": speedKmh,
+						"max_kmh":   uc.single.maxSpeedKmh,
+					})
+					accepted[index] = nil
+					results[index] = BatchPositionResult{
+						Index:  index,
+						UserID: results[index].UserID,
+						Error:  fmt.Errorf("%w: %s", service.ErrPositionRejected, reason).Error(),
+					}
+					continue
+				}
+
+				log.Warn("Position flagged by speed guard", map[string]interface{}{
+					"user_id":   results[index].UserID,
+					"index":     index,
+					"speed_kmh": speedKmh,
+					"max_kmh":   uc.single.maxSpeedKmh,
+				})
+				if !results[index].Flagged {
+					results[index].Flagged = true
+					results[index].FlagReason = reason
+				}
+			}
+			reference = position
+		}
+
+		batch.latest = position
+	}
+}
+
+// buildPosition valida um item do lote e cria a posição correspondente
+// Usuários já consultados no lote são reaproveitados para evitar buscas repetidas
+func (uc *SaveUserPositionsBatchUseCase) buildPosition(
+	ctx context.Context,
+	item BatchPositionItem,
+	batches map[string]*userBatch,
+	now time.Time,
+) (*entity.Position, *service.PositionValidation, error) {
+	batch, ok := batches[item.UserID]
+	if !ok {
+		userID, err := entity.NewUserID(item.UserID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: invalid user ID: %w", ErrInvalidInput, err)
+		}
+
+		user, err := uc.userRepo.FindByID(ctx, *userID)
+		if err != nil {
+			return nil, nil, userLookupError(item.UserID, err)
+		}
+
+		batch = &userBatch{user: user}
+		batches[item.UserID] = batch
+	}
+
+	timestamp := item.Timestamp
+	if timestamp.IsZero() {
+		timestamp = now
+	}
+
+	position, err := entity.NewPosition(entity.NewRandomPositionID().Value(), batch.user.ID(), item.Latitude, item.Longitude, timestamp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create position: %w", err)
+	}
+
+	validation, err := uc.validator.Validate(ctx, position)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to validate position: %w", err)
+	}
+	if validation.Rejected {
+		return nil, nil, fmt.Errorf("%w: %s", service.ErrPositionRejected, validation.Reason)
+	}
+
+	return position, validation, nil
+}
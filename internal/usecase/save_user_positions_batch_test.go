@@ -0,0 +1,284 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/events"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/domain/service"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+	"github.com/vitao/geolocation-tracker/pkg/config"
+	"github.com/vitao/geolocation-tracker/pkg/metrics"
+)
+
+// SaveUserPositionsBatchUseCaseTestSuite define a suite de testes para SaveUserPositionsBatchUseCase
+type SaveUserPositionsBatchUseCaseTestSuite struct {
+	suite.Suite
+	userRepo       *mocks.MockUserRepository
+	positionRepo   *mocks.MockPositionRepository
+	eventPublisher *mocks.MockEventPublisher
+	cache          *mocks.MockCache
+	logger         *mocks.MockLogger
+	useCase        *usecase.SaveUserPositionsBatchUseCase
+	ctx            context.Context
+}
+
+// SetupTest configura cada teste
+func (suite *SaveUserPositionsBatchUseCaseTestSuite) SetupTest() {
+	suite.userRepo = new(mocks.MockUserRepository)
+	suite.positionRepo = new(mocks.MockPositionRepository)
+	suite.eventPublisher = new(mocks.MockEventPublisher)
+	suite.cache = new(mocks.MockCache)
+	suite.logger = new(mocks.MockLogger)
+	suite.useConfig(config.PositionsConfig{})
+	suite.ctx = context.Background()
+
+	// Invalidação de cache pode ocorrer para qualquer usuário do lote
+	suite.cache.On("InvalidateUserCaches", mock.Anything, mock.Anything).Return(nil).Maybe()
+	suite.cache.On("Delete", mock.Anything, mock.MatchedBy(isSectorUsersCacheKey)).Return(nil).Maybe()
+	suite.logger.On("Debug", "Cache invalidation completed", mock.Anything).Return().Maybe()
+}
+
+// useConfig recria o use case com a configuração de posições informada
+func (suite *SaveUserPositionsBatchUseCaseTestSuite) useConfig(positions config.PositionsConfig) {
+	suite.useCase = usecase.NewSaveUserPositionsBatchUseCase(
+		suite.userRepo,
+		suite.positionRepo,
+		suite.eventPublisher,
+		suite.cache,
+		service.NewNoopPositionValidator(),
+		metrics.NewNoopCollector(),
+		suite.logger,
+		&config.Config{Positions: positions},
+	)
+}
+
+// TearDownTest limpa após cada teste
+func (suite *SaveUserPositionsBatchUseCaseTestSuite) TearDownTest() {
+	suite.userRepo.AssertExpectations(suite.T())
+	suite.positionRepo.AssertExpectations(suite.T())
+	suite.eventPublisher.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// mockUser registra um usuário existente sem posição atual
+func (suite *SaveUserPositionsBatchUseCaseTestSuite) mockUser(id string) {
+	user, err := entity.NewUser(id, "User "+id, id+"@example.com")
+	suite.Require().NoError(err)
+	userID, err := entity.NewUserID(id)
+	suite.Require().NoError(err)
+
+	suite.userRepo.On("FindByID", mock.Anything, *userID).Return(user, nil).Once()
+	suite.positionRepo.On("FindCurrentByUserID", mock.Anything, *userID).
+		Return(nil, errors.New("no previous position"))
+}
+
+// TestSaveBatch_PartialFailure testa que item inválido não rejeita o lote inteiro
+func (suite *SaveUserPositionsBatchUseCaseTestSuite) TestSaveBatch_PartialFailure() {
+	// Arrange
+	now := time.Now()
+	request := usecase.SaveUserPositionsBatchRequest{
+		Positions: []usecase.BatchPositionItem{
+			{UserID: "user123", Latitude: -23.550520, Longitude: -46.633309, Timestamp: now},
+			{UserID: "user123", Latitude: 123.0, Longitude: -46.633309, Timestamp: now},
+			{UserID: "unknown", Latitude: -23.550520, Longitude: -46.633309, Timestamp: now},
+		},
+	}
+
+	suite.mockUser("user123")
+	unknownID, err := entity.NewUserID("unknown")
+	suite.Require().NoError(err)
+	suite.userRepo.On("FindByID", mock.Anything, *unknownID).Return(nil, errors.New("user not found")).Once()
+
+	suite.positionRepo.On("SaveBatch", mock.Anything,
+		mock.MatchedBy(func(history []*entity.Position) bool { return len(history) == 1 }),
+		mock.MatchedBy(func(current []*entity.Position) bool { return len(current) == 1 }),
+		mock.MatchedBy(func(outbox []*repository.OutboxEntry) bool { return len(outbox) == 1 }),
+	).Return(nil)
+
+	suite.logger.On("Info", "Position batch saved", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, response.Saved)
+	assert.Equal(suite.T(), 2, response.Failed)
+	suite.Require().Len(response.Results, 3)
+
+	assert.True(suite.T(), response.Results[0].Success)
+	assert.NotEmpty(suite.T(), response.Results[0].PositionID)
+
+	assert.False(suite.T(), response.Results[1].Success)
+	assert.Contains(suite.T(), response.Results[1].Error, "failed to create position")
+
+	assert.False(suite.T(), response.Results[2].Success)
+	assert.Contains(suite.T(), response.Results[2].Error, "user not found")
+}
+
+// TestSaveBatch_PublishesOnlyLatestPerUser testa um único evento por usuário na outbox, com a posição mais recente
+func (suite *SaveUserPositionsBatchUseCaseTestSuite) TestSaveBatch_PublishesOnlyLatestPerUser() {
+	// Arrange
+	base := time.Now().Add(-time.Hour)
+	request := usecase.SaveUserPositionsBatchRequest{
+		Positions: []usecase.BatchPositionItem{
+			{UserID: "user123", Latitude: -23.551, Longitude: -46.634, Timestamp: base.Add(2 * time.Minute)},
+			{UserID: "user123", Latitude: -23.552, Longitude: -46.635, Timestamp: base},
+			{UserID: "user456", Latitude: -22.906, Longitude: -43.172, Timestamp: base.Add(time.Minute)},
+			{UserID: "user123", Latitude: -23.553, Longitude: -46.636, Timestamp: base.Add(time.Minute)},
+		},
+		RequestID: "req-batch",
+	}
+
+	suite.mockUser("user123")
+	suite.mockUser("user456")
+
+	var current []*entity.Position
+	published := make(map[string]*events.Event)
+	suite.positionRepo.On("SaveBatch", mock.Anything,
+		mock.MatchedBy(func(history []*entity.Position) bool { return len(history) == 4 }),
+		mock.Anything,
+		mock.Anything,
+	).Run(func(args mock.Arguments) {
+		current = args.Get(2).([]*entity.Position)
+		for _, entry := range args.Get(3).([]*repository.OutboxEntry) {
+			assert.Equal(suite.T(), events.StreamPositionEvents, entry.Stream)
+			published[entry.Event.UserID] = entry.Event
+		}
+	}).Return(nil)
+
+	suite.logger.On("Info", "Position batch saved", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 4, response.Saved)
+	assert.Equal(suite.T(), 0, response.Failed)
+
+	// Posição atual de user123 é a mais recente (primeiro item), não a última enviada
+	suite.Require().Len(current, 2)
+	assert.Equal(suite.T(), -23.551, current[0].Latitude())
+
+	// Nada é publicado diretamente: o relay da outbox publica os eventos
+	suite.eventPublisher.AssertNotCalled(suite.T(), "PublishPositionChanged", mock.Anything, mock.Anything)
+	suite.Require().Len(published, 2)
+	assert.Equal(suite.T(), response.Results[0].PositionID, published["user123"].Data["position_id"])
+	assert.Equal(suite.T(), response.Results[2].PositionID, published["user456"].Data["position_id"])
+	assert.Equal(suite.T(), "req-batch", published["user123"].Metadata.RequestID)
+}
+
+// TestSaveBatch_ImplausibleJumpRejected testa que o salto do lote é recusado no resultado do item
+// sem derrubar as demais posições
+func (suite *SaveUserPositionsBatchUseCaseTestSuite) TestSaveBatch_ImplausibleJumpRejected() {
+	// Arrange
+	suite.useConfig(config.PositionsConfig{MaxSpeedKmh: 300, ImplausibleSpeedAction: "reject"})
+	now := time.Now()
+	request := usecase.SaveUserPositionsBatchRequest{
+		Positions: []usecase.BatchPositionItem{
+			{UserID: "user123", Latitude: -23.550600, Longitude: -46.633400, Timestamp: now.Add(-30 * time.Second)},
+			{UserID: "user123", Latitude: -22.906847, Longitude: -43.172897, Timestamp: now},
+			{UserID: "user456", Latitude: -22.906847, Longitude: -43.172897, Timestamp: now},
+		},
+	}
+
+	// user123 estava em São Paulo há 1 minuto; o segundo item salta ~360km em 30 segundos
+	user, err := entity.NewUser("user123", "User user123", "user123@example.com")
+	suite.Require().NoError(err)
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	previousPosition, err := entity.NewPosition("pos-previous", *userID, -23.550520, -46.633309, now.Add(-time.Minute))
+	suite.Require().NoError(err)
+	suite.userRepo.On("FindByID", mock.Anything, *userID).Return(user, nil).Once()
+	suite.positionRepo.On("FindCurrentByUserID", mock.Anything, *userID).Return(previousPosition, nil)
+	suite.positionRepo.On("FindInSector", mock.Anything, mock.Anything).Return([]*entity.Position{}, nil).Maybe()
+	suite.eventPublisher.On("PublishSectorChanged", mock.Anything, mock.AnythingOfType("*events.Event")).
+		Return(nil).Maybe()
+	suite.mockUser("user456")
+
+	var history, current []*entity.Position
+	suite.positionRepo.On("SaveBatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			history = args.Get(1).([]*entity.Position)
+			current = args.Get(2).([]*entity.Position)
+		}).Return(nil)
+
+	suite.logger.On("Warn", "Position rejected by speed guard", mock.Anything).Return().Once()
+	suite.logger.On("Info", "Position batch saved", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 2, response.Saved)
+	assert.Equal(suite.T(), 1, response.Failed)
+	suite.Require().Len(response.Results, 3)
+
+	assert.True(suite.T(), response.Results[0].Success)
+	assert.False(suite.T(), response.Results[1].Success)
+	assert.Empty(suite.T(), response.Results[1].PositionID)
+	assert.Contains(suite.T(), response.Results[1].Error, service.ErrPositionRejected.Error())
+	assert.Contains(suite.T(), response.Results[1].Error, "implausible speed")
+	assert.True(suite.T(), response.Results[2].Success, "the first position of a user is never a jump")
+
+	// O salto não entra no histórico nem vira a posição atual
+	suite.Require().Len(history, 2)
+	suite.Require().Len(current, 2)
+	assert.Equal(suite.T(), -23.550600, current[0].Latitude())
+	kept := make([]string, 0, len(history))
+	for _, position := range history {
+		positionID := position.ID()
+		kept = append(kept, positionID.String())
+	}
+	assert.Equal(suite.T(), []string{response.Results[0].PositionID, response.Results[2].PositionID}, kept)
+}
+
+// TestSaveBatch_RepositoryError testa falha da transação do lote
+func (suite *SaveUserPositionsBatchUseCaseTestSuite) TestSaveBatch_RepositoryError() {
+	// Arrange
+	request := usecase.SaveUserPositionsBatchRequest{
+		Positions: []usecase.BatchPositionItem{
+			{UserID: "user123", Latitude: -23.550520, Longitude: -46.633309, Timestamp: time.Now()},
+		},
+	}
+
+	suite.mockUser("user123")
+	suite.positionRepo.On("SaveBatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(errors.New("database connection failed"))
+	suite.logger.On("Error", "Failed to save position batch", mock.Anything).Return()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, request)
+
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), response)
+	assert.True(suite.T(), strings.Contains(err.Error(), "failed to save position batch"))
+}
+
+// TestSaveBatch_Empty testa lote vazio
+func (suite *SaveUserPositionsBatchUseCaseTestSuite) TestSaveBatch_Empty() {
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.SaveUserPositionsBatchRequest{})
+
+	// Assert
+	assert.ErrorIs(suite.T(), err, usecase.ErrEmptyBatch)
+	assert.Nil(suite.T(), response)
+}
+
+// TestSaveUserPositionsBatchUseCase executa toda a suite de testes
+func TestSaveUserPositionsBatchUseCase(t *testing.T) {
+	suite.Run(t, new(SaveUserPositionsBatchUseCaseTestSuite))
+}
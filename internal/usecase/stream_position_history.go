@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// Limites do long-poll do histórico
+const (
+	DefaultPositionStreamLimit = 50
+	MaxPositionStreamLimit     = 100
+
+	// MaxPositionStreamWaitSeconds limita a espera por posições novas; a espera também
+	// termina antes do prazo da requisição (HTTP_REQUEST_TIMEOUT_SECONDS)
+	MaxPositionStreamWaitSeconds = 30
+
+	// positionStreamPollInterval é o intervalo entre consultas enquanto não há posições novas
+	positionStreamPollInterval = time.Second
+
+	// positionStreamDeadlineMargin deixa tempo para responder antes do prazo da requisição
+	positionStreamDeadlineMargin = 500 * time.Millisecond
+)
+
+// StreamPositionHistoryRequest representa os dados de entrada
+// Since vazio começa pela posição mais antiga do histórico
+type StreamPositionHistoryRequest struct {
+	UserID      string `json:"user_id"`
+	Since       string `json:"since"`
+	Limit       int    `json:"limit"`
+	WaitSeconds int    `json:"wait_seconds"`
+}
+
+// StreamPositionHistoryResponse representa a resposta
+type StreamPositionHistoryResponse struct {
+	UserID     string               `json:"user_id"`
+	Positions  []PositionExportItem `json:"positions"`
+	Count      int                  `json:"count"`
+	NextCursor string               `json:"next_cursor"` // since da próxima chamada (o próprio since quando não há nada novo)
+	HasMore    bool                 `json:"has_more"`    // Há mais posições já gravadas após next_cursor
+	Message    string               `json:"message"`
+}
+
+// StreamPositionHistoryUseCase retorna as posições gravadas após um cursor, para acompanhamento
+// incremental por polling. Com WaitSeconds a chamada aguarda posições novas (long-poll)
+type StreamPositionHistoryUseCase struct {
+	userRepo     repository.UserRepository
+	positionRepo repository.PositionRepository
+	logger       logger.Logger
+}
+
+// NewStreamPositionHistoryUseCase cria uma nova instância do use case
+func NewStreamPositionHistoryUseCase(
+	userRepo repository.UserRepository,
+	positionRepo repository.PositionRepository,
+	logger logger.Logger,
+) *StreamPositionHistoryUseCase {
+	return &StreamPositionHistoryUseCase{
+		userRepo:     userRepo,
+		positionRepo: positionRepo,
+		logger:       logger,
+	}
+}
+
+// Execute executa o use case de acompanhamento do histórico
+func (uc *StreamPositionHistoryUseCase) Execute(ctx context.Context, req StreamPositionHistoryRequest) (*StreamPositionHistoryResponse, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	// 1. Normalizar parâmetros
+	if req.Limit <= 0 {
+		req.Limit = DefaultPositionStreamLimit
+	}
+	if req.Limit > MaxPositionStreamLimit {
+		req.Limit = MaxPositionStreamLimit
+	}
+	if req.WaitSeconds < 0 {
+		req.WaitSeconds = 0
+	}
+	if req.WaitSeconds > MaxPositionStreamWaitSeconds {
+		req.WaitSeconds = MaxPositionStreamWaitSeconds
+	}
+
+	// 2. Validar usuário
+	userIDPtr, err := entity.NewUserID(req.UserID)
+	if err != nil {
+		log.Error("Invalid user ID", map[string]interface{}{
+			"user_id": req.UserID,
+			"error":   err.Error(),
+		})
+		return nil, fmt.Errorf("%w: invalid user ID: %w", ErrInvalidInput, err)
+	}
+
+	userID := *userIDPtr
+	if _, err := uc.userRepo.FindByID(ctx, userID); err != nil {
+		log.Error("User not found", map[string]interface{}{
+			"user_id": req.UserID,
+			"error":   err.Error(),
+		})
+		return nil, userLookupError(req.UserID, err)
+	}
+
+	// 3. Resolver o cursor
+	cursor, err := uc.resolveCursor(ctx, userID, req.Since)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Buscar após o cursor, aguardando posições novas até o prazo da espera
+	// Uma posição a mais indica se há outra página já disponível
+	deadline := uc.waitDeadline(ctx, time.Duration(req.WaitSeconds)*time.Second)
+	var positions []*entity.Position
+	for {
+		positions, err = uc.positionRepo.FindHistoryByUserIDAfter(ctx, userID, cursor, req.Limit+1)
+		if err != nil {
+			log.Error("Failed to stream position history", map[string]interface{}{
+				"user_id": req.UserID,
+				"since":   req.Since,
+				"error":   err.Error(),
+			})
+			return nil, fmt.Errorf("failed to stream position history: %w", err)
+		}
+
+		wait := time.Until(deadline)
+		if len(positions) > 0 || wait <= 0 {
+			break
+		}
+		if wait > positionStreamPollInterval {
+			wait = positionStreamPollInterval
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	// 5. Preparar resposta
+	response := &StreamPositionHistoryResponse{
+		UserID:     req.UserID,
+		Positions:  make([]PositionExportItem, 0, len(positions)), // Sempre [] no JSON, nunca null
+		NextCursor: req.Since,
+	}
+	if len(positions) > req.Limit {
+		positions = positions[:req.Limit]
+		response.HasMore = true
+	}
+	for _, position := range positions {
+		response.Positions = append(response.Positions, toPositionExportItem(position))
+	}
+	if len(response.Positions) > 0 {
+		response.NextCursor = response.Positions[len(response.Positions)-1].PositionID
+	}
+	response.Count = len(response.Positions)
+	response.Message = fmt.Sprintf("Found %d new positions", response.Count)
+
+	log.Debug("Position history streamed", map[string]interface{}{
+		"user_id":     req.UserID,
+		"since":       req.Since,
+		"count":       response.Count,
+		"next_cursor": response.NextCursor,
+		"has_more":    response.HasMore,
+	})
+
+	return response, nil
+}
+
+// resolveCursor converte o position_id de since no cursor da paginação por chave
+// A posição precisa pertencer ao usuário; since vazio retorna nil (início do histórico)
+func (uc *StreamPositionHistoryUseCase) resolveCursor(ctx context.Context, userID entity.UserID, since string) (*repository.HistoryCursor, error) {
+	log := logger.FromContext(ctx, uc.logger)
+
+	if since == "" {
+		return nil, nil
+	}
+
+	positionID, err := entity.NewPositionID(since)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid since cursor: %w", ErrInvalidInput, err)
+	}
+
+	position, err := uc.positionRepo.FindByID(ctx, *positionID)
+	if err != nil {
+		if errors.Is(err, entity.ErrPositionNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrPositionNotFound, since)
+		}
+		log.Error("Failed to resolve stream cursor", map[string]interface{}{
+			"user_id": userID.String(),
+			"since":   since,
+			"error":   err.Error(),
+		})
+		return nil, fmt.Errorf("failed to resolve stream cursor: %w", err)
+	}
+
+	owner := position.UserID()
+	if !owner.Equals(&userID) {
+		return nil, fmt.Errorf("%w: since cursor %s does not belong to user %s", ErrInvalidInput, since, userID.String())
+	}
+
+	return repository.NewHistoryCursor(position), nil
+}
+
+// waitDeadline calcula até quando aguardar posições novas, respeitando o prazo do context
+func (uc *StreamPositionHistoryUseCase) waitDeadline(ctx context.Context, wait time.Duration) time.Time {
+	deadline := time.Now().Add(wait)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Add(-positionStreamDeadlineMargin).Before(deadline) {
+		deadline = ctxDeadline.Add(-positionStreamDeadlineMargin)
+	}
+	return deadline
+}
@@ -0,0 +1,197 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vitao/geolocation-tracker/internal/domain/entity"
+	"github.com/vitao/geolocation-tracker/internal/domain/repository"
+	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/internal/usecase/mocks"
+)
+
+// StreamPositionHistoryUseCaseTestSuite define a suite de testes para StreamPositionHistoryUseCase
+type StreamPositionHistoryUseCaseTestSuite struct {
+	suite.Suite
+	userRepo     *mocks.MockUserRepository
+	positionRepo *mocks.MockPositionRepository
+	logger       *mocks.MockLogger
+	useCase      *usecase.StreamPositionHistoryUseCase
+	ctx          context.Context
+	userID       *entity.UserID
+	start        time.Time
+}
+
+// SetupTest configura cada teste com um usuário existente
+func (suite *StreamPositionHistoryUseCaseTestSuite) SetupTest() {
+	suite.userRepo = new(mocks.MockUserRepository)
+	suite.positionRepo = new(mocks.MockPositionRepository)
+	suite.logger = new(mocks.MockLogger)
+	suite.useCase = usecase.NewStreamPositionHistoryUseCase(suite.userRepo, suite.positionRepo, suite.logger)
+	suite.ctx = context.Background()
+	suite.start = time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+
+	userID, err := entity.NewUserID("user123")
+	suite.Require().NoError(err)
+	suite.userID = userID
+
+	user, err := entity.NewUser("user123", "João Silva", "joao@example.com")
+	suite.Require().NoError(err)
+	suite.userRepo.On("FindByID", mock.Anything, *userID).Return(user, nil).Maybe()
+	suite.logger.On("Debug", "Position history streamed", mock.Anything).Return().Maybe()
+}
+
+// TearDownTest limpa após cada teste
+func (suite *StreamPositionHistoryUseCaseTestSuite) TearDownTest() {
+	suite.userRepo.AssertExpectations(suite.T())
+	suite.positionRepo.AssertExpectations(suite.T())
+	suite.logger.AssertExpectations(suite.T())
+}
+
+// position cria uma posição do usuário, minutes após o início
+func (suite *StreamPositionHistoryUseCaseTestSuite) position(userID entity.UserID, n int) *entity.Position {
+	position, err := entity.NewPosition(fmt.Sprintf("pos-%d", n), userID, -23.550520, -46.633309, suite.start.Add(time.Duration(n)*time.Minute))
+	suite.Require().NoError(err)
+	return position
+}
+
+// TestStream_WithoutSinceStartsAtBeginning testa a primeira chamada sem cursor
+func (suite *StreamPositionHistoryUseCaseTestSuite) TestStream_WithoutSinceStartsAtBeginning() {
+	// Arrange
+	positions := []*entity.Position{suite.position(*suite.userID, 1), suite.position(*suite.userID, 2)}
+	suite.positionRepo.On("FindHistoryByUserIDAfter", mock.Anything, *suite.userID, (*repository.HistoryCursor)(nil), usecase.DefaultPositionStreamLimit+1).
+		Return(positions, nil).Once()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.StreamPositionHistoryRequest{UserID: "user123"})
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 2, response.Count)
+	assert.Equal(suite.T(), "pos-1", response.Positions[0].PositionID)
+	assert.Equal(suite.T(), "pos-2", response.NextCursor)
+	assert.False(suite.T(), response.HasMore)
+}
+
+// TestStream_SinceCursorReturnsNextPage testa a continuação pelo cursor e o has_more
+func (suite *StreamPositionHistoryUseCaseTestSuite) TestStream_SinceCursorReturnsNextPage() {
+	// Arrange
+	since := suite.position(*suite.userID, 1)
+	positionID, err := entity.NewPositionID("pos-1")
+	suite.Require().NoError(err)
+	suite.positionRepo.On("FindByID", mock.Anything, *positionID).Return(since, nil)
+
+	newer := []*entity.Position{suite.position(*suite.userID, 2), suite.position(*suite.userID, 3), suite.position(*suite.userID, 4)}
+	suite.positionRepo.On("FindHistoryByUserIDAfter", mock.Anything, *suite.userID,
+		&repository.HistoryCursor{RecordedAt: suite.start.Add(time.Minute), PositionID: "pos-1"}, 3).
+		Return(newer, nil).Once()
+
+	// Act
+	response, err := suite.useCase.Execute(suite.ctx, usecase.StreamPositionHistoryRequest{
+		UserID: "user123",
+		Since:  "pos-1",
+		Limit:  2,
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(response.Positions, 2)
+	assert.Equal(suite.T(), "pos-3", response.NextCursor)
+	assert.True(suite.T(), response.HasMore)
+}
+
+// TestStream_NothingNewKeepsCursor testa que sem posições novas o cursor é devolvido sem mudança
+func (suite *StreamPositionHistoryUseCaseTestSuite) TestStream_NothingNewKeepsCursor() {
+	positionID, err := entity.NewPositionID("pos-1")
+	suite.Require().NoError(err)
+	suite.positionRepo.On("FindByID", mock.Anything, *positionID).Return(suite.position(*suite.userID, 1), nil)
+	suite.positionRepo.On("FindHistoryByUserIDAfter", mock.Anything, *suite.userID, mock.Anything, mock.Anything).
+		Return([]*entity.Position{}, nil).Once()
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.StreamPositionHistoryRequest{UserID: "user123", Since: "pos-1"})
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, response.Count)
+	assert.NotNil(suite.T(), response.Positions)
+	assert.Equal(suite.T(), "pos-1", response.NextCursor)
+}
+
+// TestStream_WaitsForNewPositions testa o long-poll: consulta de novo até aparecer uma posição
+func (suite *StreamPositionHistoryUseCaseTestSuite) TestStream_WaitsForNewPositions() {
+	suite.positionRepo.On("FindHistoryByUserIDAfter", mock.Anything, *suite.userID, (*repository.HistoryCursor)(nil), mock.Anything).
+		Return([]*entity.Position{}, nil).Once()
+	suite.positionRepo.On("FindHistoryByUserIDAfter", mock.Anything, *suite.userID, (*repository.HistoryCursor)(nil), mock.Anything).
+		Return([]*entity.Position{suite.position(*suite.userID, 1)}, nil).Once()
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.StreamPositionHistoryRequest{UserID: "user123", WaitSeconds: 5})
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, response.Count)
+	assert.Equal(suite.T(), "pos-1", response.NextCursor)
+}
+
+// TestStream_WaitStopsBeforeRequestDeadline testa que a espera respeita o prazo do context
+func (suite *StreamPositionHistoryUseCaseTestSuite) TestStream_WaitStopsBeforeRequestDeadline() {
+	suite.positionRepo.On("FindHistoryByUserIDAfter", mock.Anything, *suite.userID, mock.Anything, mock.Anything).
+		Return([]*entity.Position{}, nil)
+
+	ctx, cancel := context.WithTimeout(suite.ctx, 700*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	response, err := suite.useCase.Execute(ctx, usecase.StreamPositionHistoryRequest{UserID: "user123", WaitSeconds: 30})
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, response.Count)
+	assert.Less(suite.T(), time.Since(start), 700*time.Millisecond)
+}
+
+// TestStream_CursorFromAnotherUser testa a rejeição de um cursor de outro usuário
+func (suite *StreamPositionHistoryUseCaseTestSuite) TestStream_CursorFromAnotherUser() {
+	otherUserID, err := entity.NewUserID("user456")
+	suite.Require().NoError(err)
+	positionID, err := entity.NewPositionID("pos-1")
+	suite.Require().NoError(err)
+	suite.positionRepo.On("FindByID", mock.Anything, *positionID).Return(suite.position(*otherUserID, 1), nil)
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.StreamPositionHistoryRequest{UserID: "user123", Since: "pos-1"})
+
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, usecase.ErrInvalidInput)
+}
+
+// TestStream_CursorNotFound testa um cursor inexistente
+func (suite *StreamPositionHistoryUseCaseTestSuite) TestStream_CursorNotFound() {
+	positionID, err := entity.NewPositionID("pos-missing")
+	suite.Require().NoError(err)
+	suite.positionRepo.On("FindByID", mock.Anything, *positionID).
+		Return(nil, fmt.Errorf("%w: %s", entity.ErrPositionNotFound, "pos-missing"))
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.StreamPositionHistoryRequest{UserID: "user123", Since: "pos-missing"})
+
+	assert.Nil(suite.T(), response)
+	assert.ErrorIs(suite.T(), err, usecase.ErrPositionNotFound)
+}
+
+// TestStream_RepositoryError testa a falha na leitura do histórico
+func (suite *StreamPositionHistoryUseCaseTestSuite) TestStream_RepositoryError() {
+	suite.positionRepo.On("FindHistoryByUserIDAfter", mock.Anything, *suite.userID, mock.Anything, mock.Anything).
+		Return(nil, errors.New("database error"))
+	suite.logger.On("Error", "Failed to stream position history", mock.Anything).Return()
+
+	response, err := suite.useCase.Execute(suite.ctx, usecase.StreamPositionHistoryRequest{UserID: "user123"})
+
+	assert.Nil(suite.T(), response)
+	assert.Error(suite.T(), err)
+}
+
+// TestStreamPositionHistoryUseCase executa toda a suite de testes
+func TestStreamPositionHistoryUseCase(t *testing.T) {
+	suite.Run(t, new(StreamPositionHistoryUseCaseTestSuite))
+}
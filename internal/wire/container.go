@@ -1,34 +1,115 @@
 package wire
 
 import (
+	"github.com/vitao/geolocation-tracker/internal/infrastructure/database"
+	"github.com/vitao/geolocation-tracker/internal/infrastructure/events"
 	"github.com/vitao/geolocation-tracker/internal/usecase"
+	"github.com/vitao/geolocation-tracker/pkg/metrics"
 )
 
 // Container agrupa todos os use cases da aplicação
 type Container struct {
 	CreateUser         *usecase.CreateUserUseCase
+	DeleteUser         *usecase.DeleteUserUseCase
 	SaveUserPosition   *usecase.SaveUserPositionUseCase
+	SavePositionsBatch *usecase.SaveUserPositionsBatchUseCase
 	FindNearbyUsers    *usecase.FindNearbyUsersUseCase
+	FindNearbyBatch    *usecase.FindNearbyUsersBatchUseCase
 	GetUsersInSector   *usecase.GetUsersInSectorUseCase
 	GetCurrentPosition *usecase.GetCurrentPositionUseCase
+	GetCurrentBatch    *usecase.GetCurrentPositionsBatchUseCase
+	GetInBoundingBox   *usecase.GetPositionsInBoundingBoxUseCase
+	GetGroupCentroid   *usecase.GetGroupCentroidUseCase
 	GetPositionHistory *usecase.GetPositionHistoryUseCase
+	ExportHistory      *usecase.ExportPositionHistoryUseCase
+	StreamHistory      *usecase.StreamPositionHistoryUseCase
+	GetMovementStats   *usecase.GetUserMovementStatsUseCase
+	GetSectorsAround   *usecase.GetSectorsAroundUseCase
+	GetSectorStats     *usecase.GetSectorStatisticsUseCase
+	GetSectorDensity   *usecase.GetSectorDensityUseCase
+	GetSectorAnalysis  *usecase.GetSectorAnalysisUseCase
+	GetOccupiedSectors *usecase.GetOccupiedSectorsUseCase
+	InspectUserCache   *usecase.InspectUserCacheUseCase
+	RecomputeSectors   *usecase.RecomputeSectorsUseCase
+	GetRecentActivity  *usecase.GetRecentActivityUseCase
+	GetPositionByID    *usecase.GetPositionByIDUseCase
+	PurgeOldPositions  *usecase.PurgeOldPositionsUseCase
+	ListUsers          *usecase.ListUsersUseCase
+	GetUserByEmail     *usecase.GetUserByEmailUseCase
+
+	// DB é o mesmo pool usado pelos repositories (health check e estatísticas)
+	DB *database.DB
+
+	// OutboxRelay publica no Redis os eventos gravados na outbox (worker em background)
+	OutboxRelay *events.OutboxRelay
+
+	// Metrics é o collector compartilhado pelos use cases, repositories e eventos (exposto em /metrics)
+	Metrics *metrics.PrometheusCollector
 }
 
 // NewContainer cria um novo container com todos os use cases
 func NewContainer(
 	createUser *usecase.CreateUserUseCase,
+	deleteUser *usecase.DeleteUserUseCase,
 	saveUserPosition *usecase.SaveUserPositionUseCase,
+	savePositionsBatch *usecase.SaveUserPositionsBatchUseCase,
 	findNearbyUsers *usecase.FindNearbyUsersUseCase,
+	findNearbyBatch *usecase.FindNearbyUsersBatchUseCase,
 	getUsersInSector *usecase.GetUsersInSectorUseCase,
 	getCurrentPosition *usecase.GetCurrentPositionUseCase,
+	getCurrentBatch *usecase.GetCurrentPositionsBatchUseCase,
+	getInBoundingBox *usecase.GetPositionsInBoundingBoxUseCase,
+	getGroupCentroid *usecase.GetGroupCentroidUseCase,
 	getPositionHistory *usecase.GetPositionHistoryUseCase,
+	exportHistory *usecase.ExportPositionHistoryUseCase,
+	streamHistory *usecase.StreamPositionHistoryUseCase,
+	getMovementStats *usecase.GetUserMovementStatsUseCase,
+	getSectorsAround *usecase.GetSectorsAroundUseCase,
+	getSectorStats *usecase.GetSectorStatisticsUseCase,
+	getSectorDensity *usecase.GetSectorDensityUseCase,
+	getSectorAnalysis *usecase.GetSectorAnalysisUseCase,
+	getOccupiedSectors *usecase.GetOccupiedSectorsUseCase,
+	inspectUserCache *usecase.InspectUserCacheUseCase,
+	recomputeSectors *usecase.RecomputeSectorsUseCase,
+	getRecentActivity *usecase.GetRecentActivityUseCase,
+	getPositionByID *usecase.GetPositionByIDUseCase,
+	purgeOldPositions *usecase.PurgeOldPositionsUseCase,
+	listUsers *usecase.ListUsersUseCase,
+	getUserByEmail *usecase.GetUserByEmailUseCase,
+	db *database.DB,
+	outboxRelay *events.OutboxRelay,
+	collector *metrics.PrometheusCollector,
 ) *Container {
 	return &Container{
 		CreateUser:         createUser,
+		DeleteUser:         deleteUser,
 		SaveUserPosition:   saveUserPosition,
+		SavePositionsBatch: savePositionsBatch,
 		FindNearbyUsers:    findNearbyUsers,
+		FindNearbyBatch:    findNearbyBatch,
 		GetUsersInSector:   getUsersInSector,
 		GetCurrentPosition: getCurrentPosition,
+		GetCurrentBatch:    getCurrentBatch,
+		GetInBoundingBox:   getInBoundingBox,
+		GetGroupCentroid:   getGroupCentroid,
 		GetPositionHistory: getPositionHistory,
+		ExportHistory:      exportHistory,
+		StreamHistory:      streamHistory,
+		GetMovementStats:   getMovementStats,
+		GetSectorsAround:   getSectorsAround,
+		GetSectorStats:     getSectorStats,
+		GetSectorDensity:   getSectorDensity,
+		GetSectorAnalysis:  getSectorAnalysis,
+		GetOccupiedSectors: getOccupiedSectors,
+		InspectUserCache:   inspectUserCache,
+		RecomputeSectors:   recomputeSectors,
+		GetRecentActivity:  getRecentActivity,
+		GetPositionByID:    getPositionByID,
+		PurgeOldPositions:  purgeOldPositions,
+		ListUsers:          listUsers,
+		GetUserByEmail:     getUserByEmail,
+		DB:                 db,
+		OutboxRelay:        outboxRelay,
+		Metrics:            collector,
 	}
 }
@@ -1,41 +1,77 @@
 package wire
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/google/wire"
 	"github.com/vitao/geolocation-tracker/internal/domain/events"
+	"github.com/vitao/geolocation-tracker/internal/domain/service"
 	"github.com/vitao/geolocation-tracker/internal/infrastructure/cache"
 	"github.com/vitao/geolocation-tracker/internal/infrastructure/database"
 	infraEvents "github.com/vitao/geolocation-tracker/internal/infrastructure/events"
 	"github.com/vitao/geolocation-tracker/internal/usecase"
 	"github.com/vitao/geolocation-tracker/pkg/config"
 	"github.com/vitao/geolocation-tracker/pkg/logger"
+	"github.com/vitao/geolocation-tracker/pkg/metrics"
 )
 
 // Infrastructure Providers
 var InfrastructureSet = wire.NewSet(
-	// Config and Logger
+	// Config, Logger and Metrics
 	config.Load,
-	logger.NewLogger,
+	NewLogger,
+	metrics.NewPrometheusCollector,
+	NewMetricsCollector,
 
 	// Database
 	database.New,
 	database.NewUserRepository,
 	database.NewPositionRepository,
+	database.NewOutboxRepository,
 
 	// Redis and Events
 	cache.NewRedis,
+	NewCacheBackend,
 	NewCacheInterface,
+	NewCacheInspector,
+
+	// Domain services
+	service.NewNoopPositionValidator,
+	service.NewGeoLocationService,
 	NewRedisEventPublisher,
+	infraEvents.NewOutboxRelay,
 )
 
 // UseCase Providers
 var UseCaseSet = wire.NewSet(
 	usecase.NewCreateUserUseCase,
+	usecase.NewDeleteUserUseCase,
 	usecase.NewSaveUserPositionUseCase,
+	usecase.NewSaveUserPositionsBatchUseCase,
 	usecase.NewFindNearbyUsersUseCase,
+	usecase.NewFindNearbyUsersBatchUseCase,
 	usecase.NewGetUsersInSectorUseCase,
 	usecase.NewGetCurrentPositionUseCase,
+	usecase.NewGetCurrentPositionsBatchUseCase,
+	usecase.NewGetPositionsInBoundingBoxUseCase,
+	usecase.NewGetGroupCentroidUseCase,
 	usecase.NewGetPositionHistoryUseCase,
+	usecase.NewExportPositionHistoryUseCase,
+	usecase.NewStreamPositionHistoryUseCase,
+	usecase.NewGetUserMovementStatsUseCase,
+	usecase.NewGetSectorsAroundUseCase,
+	usecase.NewGetSectorStatisticsUseCase,
+	usecase.NewGetSectorDensityUseCase,
+	usecase.NewGetSectorAnalysisUseCase,
+	usecase.NewGetOccupiedSectorsUseCase,
+	usecase.NewInspectUserCacheUseCase,
+	usecase.NewRecomputeSectorsUseCase,
+	usecase.NewGetRecentActivityUseCase,
+	usecase.NewGetPositionByIDUseCase,
+	usecase.NewPurgeOldPositionsUseCase,
+	usecase.NewListUsersUseCase,
+	usecase.NewGetUserByEmailUseCase,
 )
 
 // Complete Application Set
@@ -45,11 +81,49 @@ var ApplicationSet = wire.NewSet(
 )
 
 // NewRedisEventPublisher cria um novo publisher usando Redis client
-func NewRedisEventPublisher(redis *cache.Redis, logger logger.Logger) events.Publisher {
-	return infraEvents.NewRedisStreamPublisher(redis.Client(), logger)
+func NewRedisEventPublisher(redis *cache.Redis, collector metrics.Collector, cfg *config.Config, logger logger.Logger) events.Publisher {
+	publisher := infraEvents.NewRedisStreamPublisher(redis.Client(), logger)
+	publisher.ConfigureStreamEventTypes(cfg.Events.StreamEventTypes)
+	publisher.SetMaxLen(int64(cfg.Events.StreamMaxLen))
+	publisher.SetRetry(cfg.Events.PublishMaxAttempts, time.Duration(cfg.Events.PublishRetryBackoffMs)*time.Millisecond)
+	publisher.SetMetrics(collector)
+	return publisher
+}
+
+// NewLogger cria o logger com o nível e o formato de LOG_LEVEL/LOG_FORMAT
+func NewLogger(cfg *config.Config) (logger.Logger, error) {
+	return logger.New(logger.Options{Level: cfg.Log.Level, Format: cfg.Log.Format})
+}
+
+// NewMetricsCollector converte *metrics.PrometheusCollector para metrics.Collector
+func NewMetricsCollector(collector *metrics.PrometheusCollector) metrics.Collector {
+	return collector
+}
+
+// CacheBackend reúne as interfaces de cache atendidas pelos backends (Redis ou memória)
+type CacheBackend interface {
+	usecase.CacheInterface
+	usecase.CacheInspector
+}
+
+// NewCacheBackend escolhe o backend de cache dos use cases conforme CACHE_BACKEND
+func NewCacheBackend(cfg *config.Config, redis *cache.Redis, logger logger.Logger) (CacheBackend, error) {
+	switch cfg.Cache.Backend {
+	case "", "redis":
+		return redis, nil
+	case "memory":
+		return cache.NewMemory(cfg, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q (expected redis or memory)", cfg.Cache.Backend)
+	}
+}
+
+// NewCacheInterface converte o backend de cache para usecase.CacheInterface
+func NewCacheInterface(backend CacheBackend) usecase.CacheInterface {
+	return backend
 }
 
-// NewCacheInterface converte *cache.Redis para usecase.CacheInterface
-func NewCacheInterface(redis *cache.Redis) usecase.CacheInterface {
-	return redis
+// NewCacheInspector converte o backend de cache para usecase.CacheInspector
+func NewCacheInspector(backend CacheBackend) usecase.CacheInspector {
+	return backend
 }
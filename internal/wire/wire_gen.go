@@ -7,11 +7,13 @@
 package wire
 
 import (
+	"github.com/vitao/geolocation-tracker/internal/domain/service"
 	"github.com/vitao/geolocation-tracker/internal/infrastructure/cache"
 	"github.com/vitao/geolocation-tracker/internal/infrastructure/database"
+	"github.com/vitao/geolocation-tracker/internal/infrastructure/events"
 	"github.com/vitao/geolocation-tracker/internal/usecase"
 	"github.com/vitao/geolocation-tracker/pkg/config"
-	"github.com/vitao/geolocation-tracker/pkg/logger"
+	"github.com/vitao/geolocation-tracker/pkg/metrics"
 )
 
 // Injectors from wire.go:
@@ -22,26 +24,61 @@ func InitializeContainer() (*Container, error) {
 	if err != nil {
 		return nil, err
 	}
-	loggerLogger := logger.NewLogger()
-	db, err := database.New(configConfig, loggerLogger)
+	prometheusCollector := metrics.NewPrometheusCollector()
+	collector := NewMetricsCollector(prometheusCollector)
+	logger, err := NewLogger(configConfig)
 	if err != nil {
 		return nil, err
 	}
-	userRepository := database.NewUserRepository(db, loggerLogger)
-	createUserUseCase := usecase.NewCreateUserUseCase(userRepository, loggerLogger)
-	positionRepository := database.NewPositionRepository(db, loggerLogger)
-	redis, err := cache.NewRedis(configConfig, loggerLogger)
+	db, err := database.New(configConfig, collector, logger)
 	if err != nil {
 		return nil, err
 	}
-	publisher := NewRedisEventPublisher(redis, loggerLogger)
-	cacheInterface := NewCacheInterface(redis)
-	saveUserPositionUseCase := usecase.NewSaveUserPositionUseCase(userRepository, positionRepository, publisher, cacheInterface, loggerLogger)
-	findNearbyUsersUseCase := usecase.NewFindNearbyUsersUseCase(userRepository, positionRepository, cacheInterface, loggerLogger)
-	getUsersInSectorUseCase := usecase.NewGetUsersInSectorUseCase(userRepository, positionRepository, cacheInterface, loggerLogger)
-	getCurrentPositionUseCase := usecase.NewGetCurrentPositionUseCase(userRepository, positionRepository, cacheInterface, loggerLogger)
-	getPositionHistoryUseCase := usecase.NewGetPositionHistoryUseCase(userRepository, positionRepository, cacheInterface, loggerLogger)
-	container := NewContainer(createUserUseCase, saveUserPositionUseCase, findNearbyUsersUseCase, getUsersInSectorUseCase, getCurrentPositionUseCase, getPositionHistoryUseCase)
+	userRepository := database.NewUserRepository(db, logger)
+	createUserUseCase := usecase.NewCreateUserUseCase(userRepository, logger)
+	redis, err := cache.NewRedis(configConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+	publisher := NewRedisEventPublisher(redis, collector, configConfig, logger)
+	cacheBackend, err := NewCacheBackend(configConfig, redis, logger)
+	if err != nil {
+		return nil, err
+	}
+	cacheInterface := NewCacheInterface(cacheBackend)
+	deleteUserUseCase := usecase.NewDeleteUserUseCase(userRepository, publisher, cacheInterface, logger)
+	positionRepository := database.NewPositionRepository(db, configConfig, logger)
+	positionValidator := service.NewNoopPositionValidator()
+	saveUserPositionUseCase := usecase.NewSaveUserPositionUseCase(userRepository, positionRepository, publisher, cacheInterface, positionValidator, collector, logger, configConfig)
+	saveUserPositionsBatchUseCase := usecase.NewSaveUserPositionsBatchUseCase(userRepository, positionRepository, publisher, cacheInterface, positionValidator, collector, logger, configConfig)
+	findNearbyUsersUseCase := usecase.NewFindNearbyUsersUseCase(userRepository, positionRepository, cacheInterface, collector, logger, configConfig)
+	findNearbyUsersBatchUseCase := usecase.NewFindNearbyUsersBatchUseCase(userRepository, positionRepository, cacheInterface, collector, logger, configConfig)
+	getUsersInSectorUseCase := usecase.NewGetUsersInSectorUseCase(userRepository, positionRepository, cacheInterface, logger)
+	getCurrentPositionUseCase := usecase.NewGetCurrentPositionUseCase(userRepository, positionRepository, cacheInterface, logger)
+	getCurrentPositionsBatchUseCase := usecase.NewGetCurrentPositionsBatchUseCase(positionRepository, logger)
+	getPositionsInBoundingBoxUseCase := usecase.NewGetPositionsInBoundingBoxUseCase(positionRepository, logger)
+	getGroupCentroidUseCase := usecase.NewGetGroupCentroidUseCase(positionRepository, logger)
+	getPositionHistoryUseCase := usecase.NewGetPositionHistoryUseCase(userRepository, positionRepository, cacheInterface, logger)
+	exportPositionHistoryUseCase := usecase.NewExportPositionHistoryUseCase(userRepository, positionRepository, logger, configConfig)
+	streamPositionHistoryUseCase := usecase.NewStreamPositionHistoryUseCase(userRepository, positionRepository, logger)
+	getUserMovementStatsUseCase := usecase.NewGetUserMovementStatsUseCase(userRepository, positionRepository, logger)
+	getSectorsAroundUseCase := usecase.NewGetSectorsAroundUseCase(positionRepository, logger)
+	getSectorStatisticsUseCase := usecase.NewGetSectorStatisticsUseCase(positionRepository, logger)
+	geoLocationService := service.NewGeoLocationService(positionRepository)
+	getSectorDensityUseCase := usecase.NewGetSectorDensityUseCase(geoLocationService, logger)
+	getSectorAnalysisUseCase := usecase.NewGetSectorAnalysisUseCase(geoLocationService, logger)
+	getOccupiedSectorsUseCase := usecase.NewGetOccupiedSectorsUseCase(positionRepository, logger)
+	cacheInspector := NewCacheInspector(cacheBackend)
+	inspectUserCacheUseCase := usecase.NewInspectUserCacheUseCase(cacheInspector, logger)
+	recomputeSectorsUseCase := usecase.NewRecomputeSectorsUseCase(positionRepository, logger, configConfig)
+	getRecentActivityUseCase := usecase.NewGetRecentActivityUseCase(positionRepository, logger, configConfig)
+	getPositionByIDUseCase := usecase.NewGetPositionByIDUseCase(userRepository, positionRepository, logger)
+	purgeOldPositionsUseCase := usecase.NewPurgeOldPositionsUseCase(positionRepository, logger, configConfig)
+	listUsersUseCase := usecase.NewListUsersUseCase(userRepository, logger)
+	getUserByEmailUseCase := usecase.NewGetUserByEmailUseCase(userRepository, logger)
+	outboxRepository := database.NewOutboxRepository(db, logger)
+	outboxRelay := events.NewOutboxRelay(outboxRepository, publisher, configConfig, logger)
+	container := NewContainer(createUserUseCase, deleteUserUseCase, saveUserPositionUseCase, saveUserPositionsBatchUseCase, findNearbyUsersUseCase, findNearbyUsersBatchUseCase, getUsersInSectorUseCase, getCurrentPositionUseCase, getCurrentPositionsBatchUseCase, getPositionsInBoundingBoxUseCase, getGroupCentroidUseCase, getPositionHistoryUseCase, exportPositionHistoryUseCase, streamPositionHistoryUseCase, getUserMovementStatsUseCase, getSectorsAroundUseCase, getSectorStatisticsUseCase, getSectorDensityUseCase, getSectorAnalysisUseCase, getOccupiedSectorsUseCase, inspectUserCacheUseCase, recomputeSectorsUseCase, getRecentActivityUseCase, getPositionByIDUseCase, purgeOldPositionsUseCase, listUsersUseCase, getUserByEmailUseCase, db, outboxRelay, prometheusCollector)
 	return container, nil
 }
 
@@ -51,8 +88,13 @@ func InitializeDatabase() (*database.DB, error) {
 	if err != nil {
 		return nil, err
 	}
-	loggerLogger := logger.NewLogger()
-	db, err := database.New(configConfig, loggerLogger)
+	prometheusCollector := metrics.NewPrometheusCollector()
+	collector := NewMetricsCollector(prometheusCollector)
+	logger, err := NewLogger(configConfig)
+	if err != nil {
+		return nil, err
+	}
+	db, err := database.New(configConfig, collector, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -65,8 +107,11 @@ func InitializeRedis() (*cache.Redis, error) {
 	if err != nil {
 		return nil, err
 	}
-	loggerLogger := logger.NewLogger()
-	redis, err := cache.NewRedis(configConfig, loggerLogger)
+	logger, err := NewLogger(configConfig)
+	if err != nil {
+		return nil, err
+	}
+	redis, err := cache.NewRedis(configConfig, logger)
 	if err != nil {
 		return nil, err
 	}
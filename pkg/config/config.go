@@ -1,15 +1,77 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
 	Environment string
 	Port        string
+	Log         LogConfig
+	HTTP        HTTPConfig
 	Database    DatabaseConfig
 	Redis       RedisConfig
+	Events      EventsConfig
+	Positions   PositionsConfig
+	Admin       AdminConfig
+	Cache       CacheConfig
+	Sectors     SectorsConfig
+	Nearby      NearbyConfig
+	RateLimit   RateLimitConfig
+}
+
+type LogConfig struct {
+	// Level é o nível mínimo registrado: "debug", "info", "warn" ou "error"
+	// Padrão: info em produção e debug nos demais ambientes
+	Level string
+
+	// Format é "json" (uma linha JSON por entrada) ou "console" (texto legível)
+	// Padrão: json em produção e console nos demais ambientes
+	Format string
+}
+
+// validate verifica o nível e o formato dos logs
+func (c LogConfig) validate() error {
+	switch c.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("invalid LOG_LEVEL %q: use debug, info, warn or error", c.Level)
+	}
+
+	switch c.Format {
+	case "json", "console":
+	default:
+		return fmt.Errorf("invalid LOG_FORMAT %q: use json or console", c.Format)
+	}
+	return nil
+}
+
+// defaultLogConfig retorna o nível e o formato padrão de cada ambiente
+func defaultLogConfig(environment string) LogConfig {
+	if environment == "production" {
+		return LogConfig{Level: "info", Format: "json"}
+	}
+	return LogConfig{Level: "debug", Format: "console"}
+}
+
+type HTTPConfig struct {
+	// RequestTimeoutSeconds é o prazo das requisições da API v1 antes de responder 408
+	// (0 desabilita o timeout por requisição)
+	RequestTimeoutSeconds int
+
+	// CORSAllowedOrigins são as origens aceitas pelo CORS e pelo WebSocket
+	// "*" aceita qualquer origem, mas sem credenciais (Access-Control-Allow-Credentials)
+	CORSAllowedOrigins []string
+
+	// MaxBodyBytes limita o corpo de POST, PUT e PATCH; acima disso a API responde 413 (0 desabilita)
+	MaxBodyBytes int64
+
+	// StrictJSON recusa com 400 corpos JSON com chaves desconhecidas (desabilitado por padrão:
+	// alguns clientes enviam metadados extras, que são ignorados)
+	StrictJSON bool
 }
 
 type DatabaseConfig struct {
@@ -18,30 +80,342 @@ type DatabaseConfig struct {
 	User     string
 	Password string
 	DBName   string
+
+	// SectorQueryChunkSize limita quantos setores vão em cada query de FindInSectors
+	// (cada setor usa 2 parâmetros; o PostgreSQL aceita no máximo 65535)
+	SectorQueryChunkSize int
+
+	// SectorRecomputeChunkSize é o número de posições regravadas por transação
+	// ao recalcular setores após mudança no esquema de setorização
+	SectorRecomputeChunkSize int
+
+	// PositionExportChunkSize é o número de posições lidas por query na exportação do histórico
+	PositionExportChunkSize int
+
+	// SSLMode é o sslmode da conexão: "disable", "require" (TLS sem validar o certificado)
+	// ou "verify-full" (valida a cadeia com SSLRootCert e o hostname)
+	SSLMode string
+
+	// SSLRootCert é o caminho do certificado da CA (obrigatório com verify-full)
+	SSLRootCert string
+
+	// MaxOpenConns/MaxIdleConns dimensionam o pool do database/sql (MaxIdleConns <= MaxOpenConns)
+	MaxOpenConns int
+	MaxIdleConns int
+
+	// ConnMaxLifetimeMinutes é o tempo de vida de cada conexão antes de ser reaberta (0 não expira)
+	ConnMaxLifetimeMinutes int
+
+	// QueryTimeoutMs é o prazo das consultas de proximidade, setor e histórico (0 desabilita)
+	// O prazo da requisição continua valendo quando for menor
+	QueryTimeoutMs int
+}
+
+// Valores aceitos em DatabaseConfig.SSLMode
+const (
+	SSLModeDisable    = "disable"
+	SSLModeRequire    = "require"
+	SSLModeVerifyFull = "verify-full"
+)
+
+// validatePool verifica os limites do pool de conexões
+func (c DatabaseConfig) validatePool() error {
+	if c.MaxOpenConns <= 0 {
+		return fmt.Errorf("DB_MAX_OPEN_CONNS must be positive, got %d", c.MaxOpenConns)
+	}
+	if c.MaxIdleConns < 0 || c.MaxIdleConns > c.MaxOpenConns {
+		return fmt.Errorf("DB_MAX_IDLE_CONNS must be between 0 and DB_MAX_OPEN_CONNS (%d), got %d", c.MaxOpenConns, c.MaxIdleConns)
+	}
+	if c.ConnMaxLifetimeMinutes < 0 {
+		return fmt.Errorf("DB_CONN_MAX_LIFETIME_MINUTES must not be negative, got %d", c.ConnMaxLifetimeMinutes)
+	}
+	if c.QueryTimeoutMs < 0 {
+		return fmt.Errorf("DB_QUERY_TIMEOUT_MS must not be negative, got %d", c.QueryTimeoutMs)
+	}
+	return nil
+}
+
+// validateTLS verifica a combinação de sslmode e certificado da CA
+func (c DatabaseConfig) validateTLS() error {
+	switch c.SSLMode {
+	case SSLModeDisable, SSLModeRequire:
+	case SSLModeVerifyFull:
+		if c.SSLRootCert == "" {
+			return fmt.Errorf("DB_SSLMODE=verify-full requires DB_SSLROOTCERT with the path to the server CA certificate")
+		}
+	default:
+		return fmt.Errorf("invalid DB_SSLMODE %q: use disable, require or verify-full", c.SSLMode)
+	}
+
+	if c.SSLRootCert != "" {
+		if _, err := os.Stat(c.SSLRootCert); err != nil {
+			return fmt.Errorf("DB_SSLROOTCERT %q is not readable: %w", c.SSLRootCert, err)
+		}
+	}
+	return nil
 }
 
 type RedisConfig struct {
 	Host string
 	Port string
+
+	// Password é enviado no AUTH (vazio conecta sem autenticação); DB é o índice do banco lógico
+	Password string
+	DB       int
+
+	// PoolSize/MinIdleConns dimensionam o pool de conexões (0 usa 10 e 2)
+	PoolSize     int
+	MinIdleConns int
+
+	// Timeouts de conexão, leitura e escrita em segundos (0 usa 5, 3 e 3)
+	DialTimeoutSeconds  int
+	ReadTimeoutSeconds  int
+	WriteTimeoutSeconds int
+}
+
+type EventsConfig struct {
+	// DuplicateHandlerPolicy define o que fazer quando o mesmo handler é
+	// registrado duas vezes para um tipo de evento: "ignore", "replace" ou "allow"
+	DuplicateHandlerPolicy string
+
+	// SectorEntryThrottleSeconds é a janela em que entradas repetidas do mesmo
+	// usuário no mesmo setor geram no máximo uma notificação (0 desabilita)
+	SectorEntryThrottleSeconds int
+
+	// AckModes define o modo de ACK por consumer group ("at-least-once" ou "at-most-once")
+	// Formato da env: "realtime=at-most-once,analytics=at-least-once"
+	AckModes map[string]string
+
+	// StreamEventTypes sobrescreve os tipos de evento aceitos por stream (separados por "|")
+	// Formato da env: "geolocation:position-events=position.changed|proximity.user_nearby"
+	StreamEventTypes map[string]string
+
+	// StreamMaxLen limita cada stream via XADD MAXLEN ~ (0 desabilita o corte)
+	StreamMaxLen int
+
+	// PublishMaxAttempts é o total de tentativas de XADD por evento (1 desabilita o retry)
+	PublishMaxAttempts int
+
+	// PublishRetryBackoffMs é a espera antes da segunda tentativa; dobra a cada nova falha
+	PublishRetryBackoffMs int
+
+	// OutboxPollIntervalMs é o intervalo entre as leituras da outbox pelo relay
+	OutboxPollIntervalMs int
+
+	// OutboxBatchSize é o máximo de eventos publicados por leitura da outbox
+	OutboxBatchSize int
+
+	// OutboxRetentionHours é por quanto tempo eventos já publicados ficam na outbox
+	OutboxRetentionHours int
+
+	// OutboxMaxAttempts é o total de leituras com falha antes de o evento ser descartado da outbox
+	OutboxMaxAttempts int
+
+	// DedupTTLSeconds é por quanto tempo cada consumer group lembra os eventos já processados
+	// e descarta reentregas com o mesmo ID (0 desabilita)
+	DedupTTLSeconds int
+
+	// ReplayEnabled expõe POST /api/v1/admin/events/replay (desabilitado por padrão:
+	// o replay reexecuta os handlers, inclusive notificações e broadcast em tempo real)
+	ReplayEnabled bool
+}
+
+type PositionsConfig struct {
+	// UpdateCurrentOnOutOfOrder permite que uma posição com recorded_at anterior
+	// à posição atual do usuário a substitua (por padrão vai apenas para o histórico)
+	UpdateCurrentOnOutOfOrder bool
+
+	// IntraSectorMovementEvents publica sector.user_moved quando o usuário se move
+	// sem trocar de setor (rastreamento fino; desabilitado por padrão)
+	IntraSectorMovementEvents bool
+
+	// FeedStaleAfterSeconds exclui do feed de atividade recente as posições atuais
+	// sem atualização há mais que esse tempo (0 inclui todas)
+	FeedStaleAfterSeconds int
+
+	// ProximityAlertRadiusM publica proximity.user_nearby quando outro usuário entra nesse
+	// raio após salvar uma posição (0 desabilita; custa uma busca PostGIS por posição)
+	ProximityAlertRadiusM float64
+
+	// RetentionHours remove periodicamente o histórico mais antigo que isso (0 desabilita)
+	// As posições atuais sem atualização nesse período também saem de current_positions
+	RetentionHours int
+
+	// RetentionSweepIntervalMinutes é o intervalo entre as limpezas de retenção
+	RetentionSweepIntervalMinutes int
+
+	// MaxAgeHours é a idade máxima de uma nova posição (recorded_at); acima disso ela é recusada
+	// Uploads em lote/offline podem precisar de mais que o padrão de 24h
+	MaxAgeHours int
+
+	// MaxSpeedKmh é a velocidade máxima plausível desde a posição atual (0 desabilita a verificação)
+	MaxSpeedKmh float64
+
+	// ImplausibleSpeedAction define o que fazer acima de MaxSpeedKmh: "flag" salva a posição
+	// marcada (suspicious no evento position.changed) e "reject" recusa com 422
+	ImplausibleSpeedAction string
+}
+
+type AdminConfig struct {
+	// Token exigido no header X-Admin-Token; vazio desabilita os endpoints de admin
+	Token string
+}
+
+type CacheConfig struct {
+	// Backend seleciona a implementação do cache dos use cases: "redis" (padrão) ou "memory"
+	// O backend "memory" é local ao processo (testes e desenvolvimento); eventos e rate limit
+	// continuam usando o Redis
+	Backend string
+
+	// MemoryMaxEntries é o limite de chaves do backend "memory"; acima dele as menos usadas saem
+	MemoryMaxEntries int
+
+	// NearbyMinResultsToCache é o mínimo de usuários para cachear uma busca por proximidade
+	// (0 cacheia sempre, inclusive resultados vazios)
+	NearbyMinResultsToCache int
+
+	// NearbyGridMeters aproxima o centro da busca para uma grade deste tamanho ao montar a chave
+	// de cache (0 desabilita). O PostGIS continua recebendo a coordenada exata; em um hit as
+	// distâncias são recalculadas para o centro exato, mas usuários a até ~NearbyGridMeters da
+	// borda do raio podem faltar. Grades maiores aumentam o hit rate e esse erro na borda
+	NearbyGridMeters float64
+}
+
+type SectorsConfig struct {
+	// OriginLatitude/OriginLongitude definem a coordenada do setor (0, 0)
+	// O padrão (0, 0) é o cruzamento do equador com Greenwich; para eventos locais
+	// use o centro do evento. Ao mudar, recalcule os setores gravados (admin/sectors/recompute)
+	OriginLatitude  float64
+	OriginLongitude float64
+}
+
+type NearbyConfig struct {
+	// DefaultMaxResults é o max_results aplicado quando o cliente não informa um
+	DefaultMaxResults int
+
+	// MaxResults é o teto de max_results por busca; valores maiores são reduzidos a ele
+	MaxResults int
+}
+
+type RateLimitConfig struct {
+	// Requests/WindowSeconds é o limite padrão por cliente (IP ou user_id) em cada grupo
+	// de rotas da API; Requests = 0 desabilita o rate limiting
+	Requests      int
+	WindowSeconds int
+
+	// Groups sobrescreve o limite por grupo de rotas no formato "requisições/segundos"
+	// Formato da env: "positions=600/60,users=60/60"
+	Groups map[string]string
 }
 
 func Load() (*Config, error) {
+	environment := getEnv("ENVIRONMENT", "development")
+	logDefaults := defaultLogConfig(environment)
+
 	cfg := &Config{
-		Environment: getEnv("ENVIRONMENT", "development"),
+		Environment: environment,
 		Port:        getEnv("PORT", "8080"),
+		Log: LogConfig{
+			Level:  strings.ToLower(getEnv("LOG_LEVEL", logDefaults.Level)),
+			Format: strings.ToLower(getEnv("LOG_FORMAT", logDefaults.Format)),
+		},
+		HTTP: HTTPConfig{
+			RequestTimeoutSeconds: getEnvAsInt("HTTP_REQUEST_TIMEOUT_SECONDS", 10),
+			CORSAllowedOrigins:    getEnvAsList("CORS_ALLOWED_ORIGINS", "*"),
+			MaxBodyBytes:          int64(getEnvAsInt("HTTP_MAX_BODY_BYTES", 1<<20)),
+			StrictJSON:            getEnvAsBool("HTTP_STRICT_JSON", false),
+		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnv("DB_PORT", "5432"),
 			User:     getEnv("DB_USER", "postgres"),
 			Password: getEnv("DB_PASSWORD", "postgres"),
 			DBName:   getEnv("DB_NAME", "geolocation_db"),
+
+			SectorQueryChunkSize:     getEnvAsInt("DB_SECTOR_QUERY_CHUNK_SIZE", 5000),
+			SectorRecomputeChunkSize: getEnvAsInt("DB_SECTOR_RECOMPUTE_CHUNK_SIZE", 1000),
+			PositionExportChunkSize:  getEnvAsInt("DB_POSITION_EXPORT_CHUNK_SIZE", 500),
+
+			SSLMode:     getEnv("DB_SSLMODE", SSLModeDisable),
+			SSLRootCert: getEnv("DB_SSLROOTCERT", ""),
+
+			MaxOpenConns:           getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:           getEnvAsInt("DB_MAX_IDLE_CONNS", 5),
+			ConnMaxLifetimeMinutes: getEnvAsInt("DB_CONN_MAX_LIFETIME_MINUTES", 5),
+			QueryTimeoutMs:         getEnvAsInt("DB_QUERY_TIMEOUT_MS", 5000),
 		},
 		Redis: RedisConfig{
 			Host: getEnv("REDIS_HOST", "localhost"),
 			Port: getEnv("REDIS_PORT", "6379"),
+
+			Password:            getEnv("REDIS_PASSWORD", ""),
+			DB:                  getEnvAsInt("REDIS_DB", 0),
+			PoolSize:            getEnvAsInt("REDIS_POOL_SIZE", 10),
+			MinIdleConns:        getEnvAsInt("REDIS_MIN_IDLE_CONNS", 2),
+			DialTimeoutSeconds:  getEnvAsInt("REDIS_DIAL_TIMEOUT_SECONDS", 5),
+			ReadTimeoutSeconds:  getEnvAsInt("REDIS_READ_TIMEOUT_SECONDS", 3),
+			WriteTimeoutSeconds: getEnvAsInt("REDIS_WRITE_TIMEOUT_SECONDS", 3),
+		},
+		Events: EventsConfig{
+			DuplicateHandlerPolicy:     getEnv("EVENTS_DUPLICATE_HANDLER_POLICY", "ignore"),
+			SectorEntryThrottleSeconds: getEnvAsInt("EVENTS_SECTOR_ENTRY_THROTTLE_SECONDS", 300),
+			AckModes:                   getEnvAsMap("EVENTS_ACK_MODES"),
+			StreamEventTypes:           getEnvAsMap("EVENTS_STREAM_EVENT_TYPES"),
+			StreamMaxLen:               getEnvAsInt("EVENTS_STREAM_MAXLEN", 100000),
+			PublishMaxAttempts:         getEnvAsInt("EVENTS_PUBLISH_MAX_ATTEMPTS", 3),
+			PublishRetryBackoffMs:      getEnvAsInt("EVENTS_PUBLISH_RETRY_BACKOFF_MS", 100),
+			OutboxPollIntervalMs:       getEnvAsInt("EVENTS_OUTBOX_POLL_INTERVAL_MS", 500),
+			OutboxBatchSize:            getEnvAsInt("EVENTS_OUTBOX_BATCH_SIZE", 100),
+			OutboxRetentionHours:       getEnvAsInt("EVENTS_OUTBOX_RETENTION_HOURS", 24),
+			OutboxMaxAttempts:          getEnvAsInt("EVENTS_OUTBOX_MAX_ATTEMPTS", 20),
+			DedupTTLSeconds:            getEnvAsInt("EVENTS_DEDUP_TTL_SECONDS", 86400),
+			ReplayEnabled:              getEnvAsBool("EVENTS_REPLAY_ENABLED", false),
+		},
+		Positions: PositionsConfig{
+			UpdateCurrentOnOutOfOrder:     getEnvAsBool("POSITIONS_UPDATE_CURRENT_ON_OUT_OF_ORDER", false),
+			IntraSectorMovementEvents:     getEnvAsBool("POSITIONS_INTRA_SECTOR_MOVEMENT_EVENTS", false),
+			FeedStaleAfterSeconds:         getEnvAsInt("POSITIONS_FEED_STALE_AFTER_SECONDS", 0),
+			ProximityAlertRadiusM:         getEnvAsFloat("POSITIONS_PROXIMITY_ALERT_RADIUS_M", 0),
+			RetentionHours:                getEnvAsInt("POSITIONS_RETENTION_HOURS", 720),
+			RetentionSweepIntervalMinutes: getEnvAsInt("POSITIONS_RETENTION_SWEEP_INTERVAL_MINUTES", 60),
+			MaxAgeHours:                   getEnvAsInt("POSITIONS_MAX_AGE_HOURS", 24),
+			MaxSpeedKmh:                   getEnvAsFloat("POSITIONS_MAX_SPEED_KMH", 0),
+			ImplausibleSpeedAction:        getEnv("POSITIONS_IMPLAUSIBLE_SPEED_ACTION", "flag"),
+		},
+		Admin: AdminConfig{
+			Token: getEnv("ADMIN_TOKEN", ""),
+		},
+		RateLimit: RateLimitConfig{
+			Requests:      getEnvAsInt("RATE_LIMIT_REQUESTS", 100),
+			WindowSeconds: getEnvAsInt("RATE_LIMIT_WINDOW_SECONDS", 60),
+			Groups:        getEnvAsMap("RATE_LIMIT_GROUPS"),
+		},
+		Cache: CacheConfig{
+			Backend:                 getEnv("CACHE_BACKEND", "redis"),
+			MemoryMaxEntries:        getEnvAsInt("CACHE_MEMORY_MAX_ENTRIES", 10000),
+			NearbyMinResultsToCache: getEnvAsInt("CACHE_NEARBY_MIN_RESULTS", 1),
+			NearbyGridMeters:        getEnvAsFloat("CACHE_NEARBY_GRID_METERS", 0),
+		},
+		Sectors: SectorsConfig{
+			OriginLatitude:  getEnvAsFloat("SECTOR_ORIGIN_LAT", 0),
+			OriginLongitude: getEnvAsFloat("SECTOR_ORIGIN_LNG", 0),
+		},
+		Nearby: NearbyConfig{
+			DefaultMaxResults: getEnvAsInt("NEARBY_DEFAULT_MAX_RESULTS", 20),
+			MaxResults:        getEnvAsInt("NEARBY_MAX_RESULTS", 100),
 		},
 	}
 
+	if err := cfg.Log.validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Database.validateTLS(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Database.validatePool(); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
@@ -60,3 +434,48 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsList lê valores separados por vírgula, ignorando os vazios
+func getEnvAsList(key, defaultValue string) []string {
+	var result []string
+	for _, value := range strings.Split(getEnv(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// getEnvAsMap lê pares "chave=valor" separados por vírgula
+func getEnvAsMap(key string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		name, value, found := strings.Cut(pair, "=")
+		if !found {
+			continue
+		}
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if name != "" && value != "" {
+			result[name] = value
+		}
+	}
+	return result
+}
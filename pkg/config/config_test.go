@@ -0,0 +1,138 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidateTLS testa as combinações de sslmode e certificado da CA
+func TestValidateTLS(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte("certificate"), 0o600))
+
+	tests := []struct {
+		name    string
+		cfg     DatabaseConfig
+		wantErr string
+	}{
+		{name: "disable", cfg: DatabaseConfig{SSLMode: SSLModeDisable}},
+		{name: "require without CA", cfg: DatabaseConfig{SSLMode: SSLModeRequire}},
+		{name: "verify-full with CA", cfg: DatabaseConfig{SSLMode: SSLModeVerifyFull, SSLRootCert: caFile}},
+		{name: "verify-full without CA", cfg: DatabaseConfig{SSLMode: SSLModeVerifyFull}, wantErr: "requires DB_SSLROOTCERT"},
+		{name: "missing CA file", cfg: DatabaseConfig{SSLMode: SSLModeRequire, SSLRootCert: caFile + ".missing"}, wantErr: "is not readable"},
+		{name: "unknown mode", cfg: DatabaseConfig{SSLMode: "prefer"}, wantErr: "invalid DB_SSLMODE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.validateTLS()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// TestLoad_RejectsVerifyFullWithoutCA testa que a inicialização falha com verify-full sem CA
+func TestLoad_RejectsVerifyFullWithoutCA(t *testing.T) {
+	t.Setenv("DB_SSLMODE", "verify-full")
+	t.Setenv("DB_SSLROOTCERT", "")
+
+	cfg, err := Load()
+
+	assert.Nil(t, cfg)
+	assert.ErrorContains(t, err, "DB_SSLROOTCERT")
+}
+
+// TestValidatePool testa os limites do pool de conexões
+func TestValidatePool(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     DatabaseConfig
+		wantErr string
+	}{
+		{name: "defaults", cfg: DatabaseConfig{MaxOpenConns: 25, MaxIdleConns: 5, ConnMaxLifetimeMinutes: 5}},
+		{name: "idle equals open", cfg: DatabaseConfig{MaxOpenConns: 10, MaxIdleConns: 10}},
+		{name: "idle above open", cfg: DatabaseConfig{MaxOpenConns: 10, MaxIdleConns: 20}, wantErr: "DB_MAX_IDLE_CONNS"},
+		{name: "zero open", cfg: DatabaseConfig{MaxOpenConns: 0}, wantErr: "DB_MAX_OPEN_CONNS"},
+		{name: "negative lifetime", cfg: DatabaseConfig{MaxOpenConns: 10, ConnMaxLifetimeMinutes: -1}, wantErr: "DB_CONN_MAX_LIFETIME_MINUTES"},
+		{name: "negative query timeout", cfg: DatabaseConfig{MaxOpenConns: 10, QueryTimeoutMs: -1}, wantErr: "DB_QUERY_TIMEOUT_MS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.validatePool()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// TestLoad_LogDefaultsByEnvironment testa o nível e o formato padrão de cada ambiente
+func TestLoad_LogDefaultsByEnvironment(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("LOG_FORMAT", "")
+
+	t.Setenv("ENVIRONMENT", "production")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, LogConfig{Level: "info", Format: "json"}, cfg.Log)
+
+	t.Setenv("ENVIRONMENT", "development")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, LogConfig{Level: "debug", Format: "console"}, cfg.Log)
+
+	t.Setenv("LOG_LEVEL", "WARN")
+	t.Setenv("LOG_FORMAT", "json")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, LogConfig{Level: "warn", Format: "json"}, cfg.Log)
+}
+
+// TestLoad_RateLimit testa o limite padrão e os overrides por grupo de RATE_LIMIT_*
+func TestLoad_RateLimit(t *testing.T) {
+	t.Setenv("RATE_LIMIT_REQUESTS", "")
+	t.Setenv("RATE_LIMIT_WINDOW_SECONDS", "")
+	t.Setenv("RATE_LIMIT_GROUPS", "")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 100, cfg.RateLimit.Requests)
+	assert.Equal(t, 60, cfg.RateLimit.WindowSeconds)
+	assert.Empty(t, cfg.RateLimit.Groups)
+
+	t.Setenv("RATE_LIMIT_REQUESTS", "30")
+	t.Setenv("RATE_LIMIT_WINDOW_SECONDS", "10")
+	t.Setenv("RATE_LIMIT_GROUPS", "positions=600/60, admin=5/60")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, RateLimitConfig{
+		Requests:      30,
+		WindowSeconds: 10,
+		Groups:        map[string]string{"positions": "600/60", "admin": "5/60"},
+	}, cfg.RateLimit)
+}
+
+// TestLoad_RejectsInvalidLogConfig testa LOG_LEVEL e LOG_FORMAT desconhecidos
+func TestLoad_RejectsInvalidLogConfig(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "verbose")
+	_, err := Load()
+	assert.ErrorContains(t, err, "LOG_LEVEL")
+
+	t.Setenv("LOG_LEVEL", "info")
+	t.Setenv("LOG_FORMAT", "xml")
+	_, err = Load()
+	assert.ErrorContains(t, err, "LOG_FORMAT")
+}
@@ -0,0 +1,79 @@
+package geojson
+
+import (
+	"strings"
+)
+
+// MediaType é o content type registrado para GeoJSON (RFC 7946)
+const MediaType = "application/geo+json"
+
+// FormatParam é o valor de ?format= que solicita GeoJSON
+const FormatParam = "geojson"
+
+// Geometry representa uma geometria GeoJSON (apenas Point é usado hoje)
+type Geometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// Feature representa uma feature GeoJSON com propriedades livres
+type Feature struct {
+	Type       string                 `json:"type"`
+	Geometry   Geometry               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// FeatureCollection representa uma coleção de features
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// NewPoint cria uma geometria Point; GeoJSON usa a ordem [longitude, latitude]
+func NewPoint(latitude, longitude float64) Geometry {
+	return Geometry{
+		Type:        "Point",
+		Coordinates: []float64{longitude, latitude},
+	}
+}
+
+// NewPointFeature cria uma feature Point com as propriedades informadas
+func NewPointFeature(latitude, longitude float64, properties map[string]interface{}) Feature {
+	if properties == nil {
+		properties = map[string]interface{}{}
+	}
+
+	return Feature{
+		Type:       "Feature",
+		Geometry:   NewPoint(latitude, longitude),
+		Properties: properties,
+	}
+}
+
+// NewFeatureCollection cria uma coleção; features nunca serializa como null
+func NewFeatureCollection(features []Feature) *FeatureCollection {
+	if features == nil {
+		features = []Feature{}
+	}
+
+	return &FeatureCollection{
+		Type:     "FeatureCollection",
+		Features: features,
+	}
+}
+
+// Requested indica se o cliente pediu GeoJSON via ?format=geojson ou header Accept
+func Requested(format, accept string) bool {
+	if strings.EqualFold(format, FormatParam) {
+		return true
+	}
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType := strings.TrimSpace(strings.SplitN(mediaRange, ";", 2)[0])
+		if strings.EqualFold(mediaType, MediaType) {
+			return true
+		}
+	}
+
+	return false
+}
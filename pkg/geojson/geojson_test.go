@@ -0,0 +1,41 @@
+package geojson
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewPointFeature_CoordinateOrder testa que Point usa [longitude, latitude]
+func TestNewPointFeature_CoordinateOrder(t *testing.T) {
+	feature := NewPointFeature(-23.550520, -46.633309, map[string]interface{}{"user_id": "user123"})
+
+	data, err := json.Marshal(feature)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"type": "Feature",
+		"geometry": {"type": "Point", "coordinates": [-46.633309, -23.55052]},
+		"properties": {"user_id": "user123"}
+	}`, string(data))
+}
+
+// TestNewFeatureCollection_Empty testa que uma coleção vazia serializa features como []
+func TestNewFeatureCollection_Empty(t *testing.T) {
+	data, err := json.Marshal(NewFeatureCollection(nil))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"type": "FeatureCollection", "features": []}`, string(data))
+}
+
+// TestRequested testa a negociação por query param e header Accept
+func TestRequested(t *testing.T) {
+	assert.True(t, Requested("geojson", ""))
+	assert.True(t, Requested("GeoJSON", ""))
+	assert.True(t, Requested("", "application/geo+json"))
+	assert.True(t, Requested("", "application/json;q=0.5, application/geo+json;q=1"))
+	assert.False(t, Requested("", "application/json"))
+	assert.False(t, Requested("json", "*/*"))
+}
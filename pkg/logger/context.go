@@ -0,0 +1,88 @@
+package logger
+
+import "context"
+
+// requestIDKey é a chave do ID de correlação no context
+type requestIDKey struct{}
+
+// RequestIDField é o campo com o ID de correlação em todos os logs da requisição
+const RequestIDField = "request_id"
+
+// ContextWithRequestID retorna um context com o ID de correlação da requisição
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext retorna o ID de correlação da requisição ("" se ausente)
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// FromContext retorna um logger que inclui o request_id do context em cada entrada
+// Sem ID no context, retorna o próprio base
+func FromContext(ctx context.Context, base Logger) Logger {
+	requestID := RequestIDFromContext(ctx)
+	if requestID == "" {
+		return base
+	}
+
+	// Com o zap, o campo fica fixo no logger e o caller continua sendo quem chamou
+	if z, ok := base.(*zapLogger); ok {
+		return &zapLogger{logger: z.logger.With(RequestIDField, requestID)}
+	}
+	return &requestLogger{base: base, requestID: requestID}
+}
+
+// requestLogger acrescenta o request_id aos campos de cada entrada (implementações que não são zap)
+type requestLogger struct {
+	base      Logger
+	requestID string
+}
+
+// withRequestID adiciona o request_id aos campos
+// Campos em um único map[string]interface{} (padrão dos use cases) recebem o ID no próprio map
+func (l *requestLogger) withRequestID(fields []interface{}) []interface{} {
+	if len(fields) == 1 {
+		if data, ok := fields[0].(map[string]interface{}); ok {
+			merged := make(map[string]interface{}, len(data)+1)
+			merged[RequestIDField] = l.requestID
+			for key, value := range data {
+				merged[key] = value
+			}
+			return []interface{}{merged}
+		}
+	}
+
+	return append([]interface{}{RequestIDField, l.requestID}, fields...)
+}
+
+// Info registra uma mensagem informativa
+func (l *requestLogger) Info(msg string, fields ...interface{}) {
+	l.base.Info(msg, l.withRequestID(fields)...)
+}
+
+// Error registra uma mensagem de erro
+func (l *requestLogger) Error(msg string, fields ...interface{}) {
+	l.base.Error(msg, l.withRequestID(fields)...)
+}
+
+// Warn registra uma mensagem de alerta
+func (l *requestLogger) Warn(msg string, fields ...interface{}) {
+	l.base.Warn(msg, l.withRequestID(fields)...)
+}
+
+// Fatal registra uma mensagem fatal e encerra o programa
+func (l *requestLogger) Fatal(msg string, fields ...interface{}) {
+	l.base.Fatal(msg, l.withRequestID(fields)...)
+}
+
+// Debug registra uma mensagem de debug
+func (l *requestLogger) Debug(msg string, fields ...interface{}) {
+	l.base.Debug(msg, l.withRequestID(fields)...)
+}
+
+// Sync força a escrita de logs pendentes
+func (l *requestLogger) Sync() error {
+	return l.base.Sync()
+}
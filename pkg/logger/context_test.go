@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingLogger guarda os campos da última entrada (implementação que não é zap)
+type recordingLogger struct {
+	fields []interface{}
+}
+
+func (l *recordingLogger) Info(msg string, fields ...interface{})  { l.fields = fields }
+func (l *recordingLogger) Error(msg string, fields ...interface{}) { l.fields = fields }
+func (l *recordingLogger) Warn(msg string, fields ...interface{})  { l.fields = fields }
+func (l *recordingLogger) Fatal(msg string, fields ...interface{}) { l.fields = fields }
+func (l *recordingLogger) Debug(msg string, fields ...interface{}) { l.fields = fields }
+func (l *recordingLogger) Sync() error                             { return nil }
+
+// TestFromContext_WithoutRequestID testa que sem ID o próprio logger base é usado
+func TestFromContext_WithoutRequestID(t *testing.T) {
+	base := &recordingLogger{}
+
+	assert.Same(t, base, FromContext(context.Background(), base))
+}
+
+// TestFromContext_ZapIncludesRequestID testa o request_id em campos chave/valor e em map
+func TestFromContext_ZapIncludesRequestID(t *testing.T) {
+	base, buf := newBufferLogger(t, Options{Level: LevelDebug, Format: FormatJSON})
+	ctx := ContextWithRequestID(context.Background(), "req-123")
+
+	log := FromContext(ctx, base)
+	log.Info("handler", "status", 200)
+	log.Error("use case", map[string]interface{}{"user_id": "user123"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var handlerEntry, useCaseEntry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &handlerEntry))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &useCaseEntry))
+
+	assert.Equal(t, "req-123", handlerEntry["request_id"])
+	assert.Equal(t, float64(200), handlerEntry["status"])
+	assert.Equal(t, "req-123", useCaseEntry["request_id"])
+	assert.Equal(t, "user123", useCaseEntry["user_id"])
+	assert.Contains(t, useCaseEntry["caller"], "context_test.go")
+}
+
+// TestFromContext_OtherLoggers testa que o ID entra no map dos use cases ou antes dos pares chave/valor
+func TestFromContext_OtherLoggers(t *testing.T) {
+	base := &recordingLogger{}
+	ctx := ContextWithRequestID(context.Background(), "req-123")
+	log := FromContext(ctx, base)
+
+	fields := map[string]interface{}{"user_id": "user123"}
+	log.Info("use case", fields)
+	require.Len(t, base.fields, 1)
+	assert.Equal(t, map[string]interface{}{"request_id": "req-123", "user_id": "user123"}, base.fields[0])
+	assert.NotContains(t, fields, "request_id", "caller map must not be modified")
+
+	log.Warn("handler", "status", 500)
+	assert.Equal(t, []interface{}{"request_id", "req-123", "status", 500}, base.fields)
+}
@@ -1,57 +1,159 @@
 package logger
 
 import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // Logger interface para logging estruturado
 type Logger interface {
 	Info(msg string, fields ...interface{})
 	Error(msg string, fields ...interface{})
+	Warn(msg string, fields ...interface{})
 	Fatal(msg string, fields ...interface{})
 	Debug(msg string, fields ...interface{})
 	Sync() error
 }
 
+// Níveis aceitos em Options.Level
+const (
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+)
+
+// Formatos aceitos em Options.Format
+const (
+	FormatJSON    = "json"    // Uma linha JSON por entrada (produção, agregadores de log)
+	FormatConsole = "console" // Texto legível com cores (desenvolvimento)
+)
+
+// Options configura o nível mínimo e o formato dos logs (vazio usa info e console)
+type Options struct {
+	Level  string
+	Format string
+}
+
 // zapLogger implementação com Zap
 type zapLogger struct {
 	logger *zap.SugaredLogger
 }
 
-// NewLogger cria uma nova instância do logger
-func NewLogger() Logger {
-	// Configuração para desenvolvimento (mais legível)
-	config := zap.NewDevelopmentConfig()
-	config.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
+// New cria um logger com o nível e o formato informados, escrevendo em stderr
+func New(opts Options) (Logger, error) {
+	return newLogger(opts, zapcore.Lock(os.Stderr))
+}
 
-	logger, _ := config.Build()
+// newLogger monta o logger sobre um destino qualquer (usado nos testes)
+func newLogger(opts Options, out zapcore.WriteSyncer) (Logger, error) {
+	level, err := parseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var encoder zapcore.Encoder
+	switch strings.ToLower(opts.Format) {
+	case FormatJSON:
+		encoderConfig := zap.NewProductionEncoderConfig()
+		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	case "", FormatConsole:
+		encoderConfig := zap.NewDevelopmentEncoderConfig()
+		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	default:
+		return nil, fmt.Errorf("invalid log format %q: use json or console", opts.Format)
+	}
+
+	core := zapcore.NewCore(encoder, out, level)
+
+	// AddCallerSkip(1): o caller registrado é quem chamou o Logger, não este wrapper
+	logger := zap.New(core,
+		zap.AddCaller(),
+		zap.AddCallerSkip(1),
+		zap.AddStacktrace(zapcore.ErrorLevel),
+	)
 
 	return &zapLogger{
 		logger: logger.Sugar(),
+	}, nil
+}
+
+// parseLevel converte "debug", "info", "warn" ou "error" (vazio usa info)
+func parseLevel(level string) (zapcore.Level, error) {
+	switch strings.ToLower(level) {
+	case LevelDebug:
+		return zapcore.DebugLevel, nil
+	case "", LevelInfo:
+		return zapcore.InfoLevel, nil
+	case LevelWarn:
+		return zapcore.WarnLevel, nil
+	case LevelError:
+		return zapcore.ErrorLevel, nil
+	default:
+		return zapcore.InfoLevel, fmt.Errorf("invalid log level %q: use debug, info, warn or error", level)
 	}
 }
 
 // Info registra uma mensagem informativa
 func (l *zapLogger) Info(msg string, fields ...interface{}) {
-	l.logger.Infow(msg, fields...)
+	l.logger.Infow(msg, expandFields(fields)...)
 }
 
 // Error registra uma mensagem de erro
 func (l *zapLogger) Error(msg string, fields ...interface{}) {
-	l.logger.Errorw(msg, fields...)
+	l.logger.Errorw(msg, expandFields(fields)...)
+}
+
+// Warn registra uma mensagem de alerta
+func (l *zapLogger) Warn(msg string, fields ...interface{}) {
+	l.logger.Warnw(msg, expandFields(fields)...)
 }
 
 // Fatal registra uma mensagem fatal e encerra o programa
 func (l *zapLogger) Fatal(msg string, fields ...interface{}) {
-	l.logger.Fatalw(msg, fields...)
+	l.logger.Fatalw(msg, expandFields(fields)...)
 }
 
 // Debug registra uma mensagem de debug
 func (l *zapLogger) Debug(msg string, fields ...interface{}) {
-	l.logger.Debugw(msg, fields...)
+	l.logger.Debugw(msg, expandFields(fields)...)
 }
 
 // Sync força a escrita de logs pendentes
 func (l *zapLogger) Sync() error {
 	return l.logger.Sync()
 }
+
+// expandFields converte campos em map[string]interface{} (padrão dos use cases) em pares
+// chave/valor, em ordem alfabética; os demais campos seguem como pares chave/valor
+func expandFields(fields []interface{}) []interface{} {
+	expanded := make([]interface{}, 0, len(fields))
+	for i := 0; i < len(fields); i++ {
+		data, ok := fields[i].(map[string]interface{})
+		if !ok {
+			expanded = append(expanded, fields[i])
+			if i+1 < len(fields) {
+				i++
+				expanded = append(expanded, fields[i])
+			}
+			continue
+		}
+
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			expanded = append(expanded, key, data[key])
+		}
+	}
+	return expanded
+}
@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// newBufferLogger cria um logger que escreve em memória
+func newBufferLogger(t *testing.T, opts Options) (Logger, *bytes.Buffer) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger, err := newLogger(opts, zapcore.AddSync(&buf))
+	require.NoError(t, err)
+	return logger, &buf
+}
+
+// TestLogger_InfoLevelSuppressesDebug testa que debug não é registrado no nível info
+func TestLogger_InfoLevelSuppressesDebug(t *testing.T) {
+	logger, buf := newBufferLogger(t, Options{Level: LevelInfo, Format: FormatConsole})
+
+	logger.Debug("cache miss", "key", "sector:sector_1_1")
+	logger.Info("server started", "port", "8080")
+
+	output := buf.String()
+	assert.NotContains(t, output, "cache miss")
+	assert.Contains(t, output, "server started")
+}
+
+// TestLogger_DebugLevelKeepsDebug testa que o nível debug registra tudo
+func TestLogger_DebugLevelKeepsDebug(t *testing.T) {
+	logger, buf := newBufferLogger(t, Options{Level: LevelDebug, Format: FormatConsole})
+
+	logger.Debug("cache miss")
+
+	assert.Contains(t, buf.String(), "cache miss")
+}
+
+// TestLogger_JSONFormat testa que cada entrada é uma linha JSON com os campos estruturados
+func TestLogger_JSONFormat(t *testing.T) {
+	logger, buf := newBufferLogger(t, Options{Level: LevelWarn, Format: FormatJSON})
+
+	logger.Info("ignored")
+	logger.Warn("slow query", "operation", "position.find_nearby", "duration_ms", 250)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 1)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "warn", entry["level"])
+	assert.Equal(t, "slow query", entry["msg"])
+	assert.Equal(t, "position.find_nearby", entry["operation"])
+	assert.Equal(t, float64(250), entry["duration_ms"])
+	assert.Contains(t, entry["caller"], "logger_test.go")
+}
+
+// TestNew_InvalidOptions testa nível e formato desconhecidos
+func TestNew_InvalidOptions(t *testing.T) {
+	_, err := New(Options{Level: "verbose"})
+	assert.ErrorContains(t, err, "invalid log level")
+
+	_, err = New(Options{Format: "xml"})
+	assert.ErrorContains(t, err, "invalid log format")
+}
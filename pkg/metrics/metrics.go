@@ -0,0 +1,161 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Collector interface para coleta de métricas da aplicação
+type Collector interface {
+	ObserveHTTPRequest(method, route string, status int, duration time.Duration)
+	ObserveUseCase(name string, duration time.Duration, err error)
+	ObserveCacheLookup(cache string, hit bool)
+	ObserveDBQuery(operation string, duration time.Duration)
+	ObserveEventPublished(stream, eventType string, err error)
+	ObserveEventConsumed(consumerGroup, eventType string, success bool)
+}
+
+// PrometheusCollector implementação com Prometheus
+// Usa um registry próprio: várias instâncias não colidem no registry global
+type PrometheusCollector struct {
+	registry *prometheus.Registry
+
+	httpRequests   *prometheus.CounterVec
+	httpDuration   *prometheus.HistogramVec
+	useCaseTotal   *prometheus.CounterVec
+	useCaseLatency *prometheus.HistogramVec
+	cacheLookups   *prometheus.CounterVec
+	dbQueryLatency *prometheus.HistogramVec
+	eventsPublish  *prometheus.CounterVec
+	eventsConsume  *prometheus.CounterVec
+}
+
+// NewPrometheusCollector cria um collector com as métricas registradas
+func NewPrometheusCollector() *PrometheusCollector {
+	c := &PrometheusCollector{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Requisições HTTP por rota, método e status",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Latência das requisições HTTP por rota",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		useCaseTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "usecase_executions_total",
+			Help: "Execuções de use cases por resultado",
+		}, []string{"usecase", "result"}),
+		useCaseLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "usecase_duration_seconds",
+			Help:    "Latência dos use cases",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"usecase"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cache_lookups_total",
+			Help: "Consultas ao cache por resultado (hit/miss)",
+		}, []string{"cache", "result"}),
+		dbQueryLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "db_query_duration_seconds",
+			Help:    "Latência das queries no PostgreSQL por operação",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation"}),
+		eventsPublish: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "events_published_total",
+			Help: "Eventos publicados nos Redis Streams por resultado",
+		}, []string{"stream", "event_type", "result"}),
+		eventsConsume: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "events_consumed_total",
+			Help: "Eventos processados pelos consumer groups por resultado",
+		}, []string{"group", "event_type", "result"}),
+	}
+
+	c.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		c.httpRequests,
+		c.httpDuration,
+		c.useCaseTotal,
+		c.useCaseLatency,
+		c.cacheLookups,
+		c.dbQueryLatency,
+		c.eventsPublish,
+		c.eventsConsume,
+	)
+
+	return c
+}
+
+// Handler retorna o handler HTTP no formato de exposição do Prometheus
+func (c *PrometheusCollector) Handler() http.Handler {
+	return promhttp.HandlerFor(c.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest registra uma requisição HTTP
+func (c *PrometheusCollector) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	c.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	c.httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// ObserveUseCase registra uma execução de use case
+func (c *PrometheusCollector) ObserveUseCase(name string, duration time.Duration, err error) {
+	c.useCaseTotal.WithLabelValues(name, result(err)).Inc()
+	c.useCaseLatency.WithLabelValues(name).Observe(duration.Seconds())
+}
+
+// ObserveCacheLookup registra um hit ou miss de cache
+func (c *PrometheusCollector) ObserveCacheLookup(cache string, hit bool) {
+	outcome := "miss"
+	if hit {
+		outcome = "hit"
+	}
+	c.cacheLookups.WithLabelValues(cache, outcome).Inc()
+}
+
+// ObserveDBQuery registra a duração de uma query
+func (c *PrometheusCollector) ObserveDBQuery(operation string, duration time.Duration) {
+	c.dbQueryLatency.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// ObserveEventPublished registra a publicação de um evento
+func (c *PrometheusCollector) ObserveEventPublished(stream, eventType string, err error) {
+	c.eventsPublish.WithLabelValues(stream, eventType, result(err)).Inc()
+}
+
+// ObserveEventConsumed registra o processamento de um evento
+func (c *PrometheusCollector) ObserveEventConsumed(consumerGroup, eventType string, success bool) {
+	outcome := "success"
+	if !success {
+		outcome = "error"
+	}
+	c.eventsConsume.WithLabelValues(consumerGroup, eventType, outcome).Inc()
+}
+
+// result converte o erro no label de resultado
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+// noopCollector descarta todas as métricas (testes e ferramentas auxiliares)
+type noopCollector struct{}
+
+// NewNoopCollector cria um collector que não registra nada
+func NewNoopCollector() Collector {
+	return noopCollector{}
+}
+
+func (noopCollector) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {}
+func (noopCollector) ObserveUseCase(name string, duration time.Duration, err error)               {}
+func (noopCollector) ObserveCacheLookup(cache string, hit bool)                                   {}
+func (noopCollector) ObserveDBQuery(operation string, duration time.Duration)                     {}
+func (noopCollector) ObserveEventPublished(stream, eventType string, err error)                   {}
+func (noopCollector) ObserveEventConsumed(consumerGroup, eventType string, success bool)          {}
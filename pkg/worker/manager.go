@@ -0,0 +1,112 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vitao/geolocation-tracker/pkg/logger"
+)
+
+// Manager registra as goroutines de longa duração da aplicação (consumers, sweepers, detectores)
+// Todas recebem o mesmo context, cancelado no Shutdown, que aguarda o encerramento com prazo
+type Manager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	logger logger.Logger
+
+	mu      sync.Mutex
+	running map[string]int // Workers ativos por nome (o mesmo nome pode rodar mais de uma vez)
+	wg      sync.WaitGroup
+}
+
+// NewManager cria um manager cujos workers encerram quando parent é cancelado ou no Shutdown
+func NewManager(parent context.Context, logger logger.Logger) *Manager {
+	ctx, cancel := context.WithCancel(parent)
+	return &Manager{
+		ctx:     ctx,
+		cancel:  cancel,
+		logger:  logger,
+		running: make(map[string]int),
+	}
+}
+
+// Context retorna o context compartilhado pelos workers (para inicializações fora de Go)
+func (m *Manager) Context() context.Context {
+	return m.ctx
+}
+
+// Go inicia fn em uma goroutine registrada com o nome informado
+// fn deve retornar assim que ctx for cancelado
+func (m *Manager) Go(name string, fn func(ctx context.Context)) {
+	m.mu.Lock()
+	m.running[name]++
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer m.finished(name)
+		fn(m.ctx)
+	}()
+}
+
+// Shutdown cancela o context dos workers e aguarda até timeout pelo encerramento de todos
+// Os workers que não encerrarem a tempo são registrados no log e retornados no erro
+func (m *Manager) Shutdown(timeout time.Duration) error {
+	m.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		m.logger.Info("Background workers stopped")
+		return nil
+	case <-timer.C:
+	}
+
+	pending := m.Running()
+	for _, name := range pending {
+		m.logger.Warn("Background worker did not stop in time",
+			"worker", name,
+			"timeout", timeout.String(),
+		)
+	}
+	return fmt.Errorf("%d background workers did not stop within %s: %s", len(pending), timeout, strings.Join(pending, ", "))
+}
+
+// Running retorna os nomes dos workers ainda ativos, em ordem alfabética
+func (m *Manager) Running() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.running))
+	for name, count := range m.running {
+		for i := 0; i < count; i++ {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// finished remove o worker do registro de ativos
+func (m *Manager) finished(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.running[name]--
+	if m.running[name] <= 0 {
+		delete(m.running, name)
+	}
+}
@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nopLogger descarta todos os logs durante os testes
+type nopLogger struct{}
+
+func (nopLogger) Info(msg string, fields ...interface{})  {}
+func (nopLogger) Error(msg string, fields ...interface{}) {}
+func (nopLogger) Warn(msg string, fields ...interface{})  {}
+func (nopLogger) Fatal(msg string, fields ...interface{}) {}
+func (nopLogger) Debug(msg string, fields ...interface{}) {}
+func (nopLogger) Sync() error                             { return nil }
+
+// TestShutdown_WaitsForWorkers testa que o shutdown cancela o context e aguarda os workers
+func TestShutdown_WaitsForWorkers(t *testing.T) {
+	manager := NewManager(context.Background(), nopLogger{})
+
+	stopped := make(chan string, 2)
+	for _, name := range []string{"consumer", "sweeper"} {
+		name := name
+		manager.Go(name, func(ctx context.Context) {
+			<-ctx.Done()
+			time.Sleep(10 * time.Millisecond)
+			stopped <- name
+		})
+	}
+	assert.Equal(t, []string{"consumer", "sweeper"}, manager.Running())
+
+	require.NoError(t, manager.Shutdown(time.Second))
+	assert.Len(t, stopped, 2)
+	assert.Empty(t, manager.Running())
+}
+
+// TestShutdown_ReportsStuckWorkers testa que workers presos são reportados após o prazo
+func TestShutdown_ReportsStuckWorkers(t *testing.T) {
+	manager := NewManager(context.Background(), nopLogger{})
+
+	release := make(chan struct{})
+	defer close(release)
+
+	manager.Go("cooperative", func(ctx context.Context) { <-ctx.Done() })
+	manager.Go("stuck", func(ctx context.Context) { <-release })
+
+	start := time.Now()
+	err := manager.Shutdown(50 * time.Millisecond)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stuck")
+	assert.NotContains(t, err.Error(), "cooperative")
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+// TestContext_CanceledWithParent testa que os workers também encerram com o context pai
+func TestContext_CanceledWithParent(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	manager := NewManager(parent, nopLogger{})
+
+	cancel()
+
+	select {
+	case <-manager.Context().Done():
+	case <-time.After(time.Second):
+		t.Fatal("worker context was not canceled with its parent")
+	}
+}